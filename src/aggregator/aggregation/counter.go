@@ -35,6 +35,8 @@ type Counter struct {
 	count int64
 	max   int64
 	min   int64
+
+	distinct HyperLogLog
 }
 
 // NewCounter creates a new counter.
@@ -61,6 +63,27 @@ func (c *Counter) Update(value int64) {
 	if c.HasExpensiveAggregations {
 		c.sumSq += value * value
 	}
+
+	if c.HasCountDistinct {
+		c.distinct.Add(float64(value))
+	}
+}
+
+// Merge merges the values aggregated by another counter into the counter.
+func (c *Counter) Merge(other *Counter) {
+	c.distinct.Merge(&other.distinct)
+	if other.count == 0 {
+		return
+	}
+	c.sum += other.sum
+	c.sumSq += other.sumSq
+	c.count += other.count
+	if c.max < other.max {
+		c.max = other.max
+	}
+	if c.min > other.min {
+		c.min = other.min
+	}
 }
 
 // Count returns the number of values received.
@@ -91,6 +114,20 @@ func (c *Counter) Min() int64 { return c.min }
 // Max returns the maximum counter value.
 func (c *Counter) Max() int64 { return c.max }
 
+// CountDistinct returns the estimated number of distinct counter values.
+func (c *Counter) CountDistinct() float64 { return c.distinct.Estimate() }
+
+// CountDistinctSketch returns the sketch of distinct counter values.
+func (c *Counter) CountDistinctSketch() *HyperLogLog { return &c.distinct }
+
+// MergeCountDistinctSketch merges the registers of a count distinct sketch
+// forwarded from upstream into the counter's sketch.
+func (c *Counter) MergeCountDistinctSketch(registers []uint8) {
+	if c.HasCountDistinct {
+		c.distinct.MergeRegisters(registers)
+	}
+}
+
 // ValueOf returns the value for the aggregation type.
 func (c *Counter) ValueOf(aggType aggregation.Type) float64 {
 	switch aggType {
@@ -108,10 +145,47 @@ func (c *Counter) ValueOf(aggType aggregation.Type) float64 {
 		return float64(c.SumSq())
 	case aggregation.Stdev:
 		return c.Stdev()
+	case aggregation.CountDistinct:
+		return c.CountDistinct()
 	default:
 		return 0
 	}
 }
 
+// CounterSnapshot is a point-in-time copy of the counter state.
+type CounterSnapshot struct {
+	Sum   int64
+	SumSq int64
+	Count int64
+	Max   int64
+	Min   int64
+
+	// CountDistinctRegisters are the count distinct sketch registers, if any.
+	CountDistinctRegisters []uint8
+}
+
+// Snapshot returns a snapshot of the counter state.
+func (c *Counter) Snapshot() CounterSnapshot {
+	return CounterSnapshot{
+		Sum:   c.sum,
+		SumSq: c.sumSq,
+		Count: c.count,
+		Max:   c.max,
+		Min:   c.min,
+
+		CountDistinctRegisters: c.distinct.Registers(),
+	}
+}
+
+// Restore replaces the counter state with that in the snapshot.
+func (c *Counter) Restore(snapshot CounterSnapshot) {
+	c.sum = snapshot.Sum
+	c.sumSq = snapshot.SumSq
+	c.count = snapshot.Count
+	c.max = snapshot.Max
+	c.min = snapshot.Min
+	c.distinct.SetRegisters(snapshot.CountDistinctRegisters)
+}
+
 // Close closes the counter.
 func (c *Counter) Close() {}
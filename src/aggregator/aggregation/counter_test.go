@@ -43,6 +43,7 @@ func TestCounterDefaultAggregationType(t *testing.T) {
 func TestCounterCustomAggregationType(t *testing.T) {
 	opts := NewOptions()
 	opts.HasExpensiveAggregations = true
+	opts.HasCountDistinct = true
 
 	c := NewCounter(opts)
 	require.True(t, c.HasExpensiveAggregations)
@@ -68,9 +69,75 @@ func TestCounterCustomAggregationType(t *testing.T) {
 			require.Equal(t, float64(338350), v)
 		case aggregation.Stdev:
 			require.InDelta(t, 29.01149, v, 0.001)
+		case aggregation.CountDistinct:
+			require.InDelta(t, 100.0, v, 2.0)
 		default:
 			require.Equal(t, float64(0), v)
 			require.False(t, aggType.IsValidForCounter())
 		}
 	}
 }
+
+func TestCounterSnapshotRestore(t *testing.T) {
+	opts := NewOptions()
+	opts.HasExpensiveAggregations = true
+	opts.HasCountDistinct = true
+
+	c := NewCounter(opts)
+	for i := 1; i <= 100; i++ {
+		c.Update(int64(i))
+	}
+
+	restored := NewCounter(opts)
+	restored.Restore(c.Snapshot())
+	for aggType := range aggregation.ValidTypes {
+		require.Equal(t, c.ValueOf(aggType), restored.ValueOf(aggType))
+	}
+}
+
+func TestCounterMergeCountDistinctSketch(t *testing.T) {
+	opts := NewOptions()
+	opts.HasCountDistinct = true
+
+	upstream := NewCounter(opts)
+	for i := 1; i <= 100; i++ {
+		upstream.Update(int64(i % 10))
+	}
+
+	c := NewCounter(opts)
+	c.MergeCountDistinctSketch(upstream.CountDistinctSketch().Registers())
+	require.Equal(t, upstream.CountDistinct(), c.CountDistinct())
+	require.InDelta(t, 10.0, c.ValueOf(aggregation.CountDistinct), 0.5)
+
+	// Merged sketches do not affect other aggregations.
+	require.Equal(t, int64(0), c.Count())
+}
+
+func TestCounterMerge(t *testing.T) {
+	opts := NewOptions()
+	opts.HasExpensiveAggregations = true
+	opts.HasCountDistinct = true
+
+	var (
+		c      = NewCounter(opts)
+		first  = NewCounter(opts)
+		second = NewCounter(opts)
+		empty  = NewCounter(opts)
+	)
+	for i := 1; i <= 100; i++ {
+		c.Update(int64(i))
+		if i <= 50 {
+			first.Update(int64(i))
+		} else {
+			second.Update(int64(i))
+		}
+	}
+
+	merged := NewCounter(opts)
+	merged.Merge(&first)
+	merged.Merge(&empty)
+	merged.Merge(&second)
+	for aggType := range aggregation.ValidTypes {
+		require.Equal(t, c.ValueOf(aggType), merged.ValueOf(aggType))
+	}
+}
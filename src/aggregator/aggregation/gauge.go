@@ -40,6 +40,8 @@ type Gauge struct {
 	count int64
 	max   float64
 	min   float64
+
+	distinct HyperLogLog
 }
 
 // NewGauge creates a new gauge.
@@ -67,6 +69,30 @@ func (g *Gauge) Update(value float64) {
 	if g.HasExpensiveAggregations {
 		g.sumSq += value * value
 	}
+
+	if g.HasCountDistinct {
+		g.distinct.Add(value)
+	}
+}
+
+// Merge merges the values aggregated by another gauge into the gauge. The other
+// gauge is expected to have received its values after those of the gauge so its
+// last value takes precedence.
+func (g *Gauge) Merge(other *Gauge) {
+	g.distinct.Merge(&other.distinct)
+	if other.count == 0 {
+		return
+	}
+	g.last = other.last
+	g.sum += other.sum
+	g.sumSq += other.sumSq
+	g.count += other.count
+	if g.max < other.max {
+		g.max = other.max
+	}
+	if g.min > other.min {
+		g.min = other.min
+	}
 }
 
 // Last returns the last value received.
@@ -100,6 +126,20 @@ func (g *Gauge) Min() float64 { return g.min }
 // Max returns the maximum gauge value.
 func (g *Gauge) Max() float64 { return g.max }
 
+// CountDistinct returns the estimated number of distinct gauge values.
+func (g *Gauge) CountDistinct() float64 { return g.distinct.Estimate() }
+
+// CountDistinctSketch returns the sketch of distinct gauge values.
+func (g *Gauge) CountDistinctSketch() *HyperLogLog { return &g.distinct }
+
+// MergeCountDistinctSketch merges the registers of a count distinct sketch
+// forwarded from upstream into the gauge's sketch.
+func (g *Gauge) MergeCountDistinctSketch(registers []uint8) {
+	if g.HasCountDistinct {
+		g.distinct.MergeRegisters(registers)
+	}
+}
+
 // ValueOf returns the value for the aggregation type.
 func (g *Gauge) ValueOf(aggType aggregation.Type) float64 {
 	switch aggType {
@@ -119,10 +159,50 @@ func (g *Gauge) ValueOf(aggType aggregation.Type) float64 {
 		return g.SumSq()
 	case aggregation.Stdev:
 		return g.Stdev()
+	case aggregation.CountDistinct:
+		return g.CountDistinct()
 	default:
 		return 0
 	}
 }
 
+// GaugeSnapshot is a point-in-time copy of the gauge state.
+type GaugeSnapshot struct {
+	Last  float64
+	Sum   float64
+	SumSq float64
+	Count int64
+	Max   float64
+	Min   float64
+
+	// CountDistinctRegisters are the count distinct sketch registers, if any.
+	CountDistinctRegisters []uint8
+}
+
+// Snapshot returns a snapshot of the gauge state.
+func (g *Gauge) Snapshot() GaugeSnapshot {
+	return GaugeSnapshot{
+		Last:  g.last,
+		Sum:   g.sum,
+		SumSq: g.sumSq,
+		Count: g.count,
+		Max:   g.max,
+		Min:   g.min,
+
+		CountDistinctRegisters: g.distinct.Registers(),
+	}
+}
+
+// Restore replaces the gauge state with that in the snapshot.
+func (g *Gauge) Restore(snapshot GaugeSnapshot) {
+	g.last = snapshot.Last
+	g.sum = snapshot.Sum
+	g.sumSq = snapshot.SumSq
+	g.count = snapshot.Count
+	g.max = snapshot.Max
+	g.min = snapshot.Min
+	g.distinct.SetRegisters(snapshot.CountDistinctRegisters)
+}
+
 // Close closes the gauge.
 func (g *Gauge) Close() {}
@@ -44,6 +44,7 @@ func TestGaugeDefaultAggregationType(t *testing.T) {
 func TestGaugeCustomAggregationType(t *testing.T) {
 	opts := NewOptions()
 	opts.HasExpensiveAggregations = true
+	opts.HasCountDistinct = true
 
 	g := NewGauge(opts)
 	require.True(t, g.HasExpensiveAggregations)
@@ -72,9 +73,41 @@ func TestGaugeCustomAggregationType(t *testing.T) {
 			require.Equal(t, float64(338350), v)
 		case aggregation.Stdev:
 			require.InDelta(t, 29.01149, v, 0.001)
+		case aggregation.CountDistinct:
+			require.InDelta(t, 100.0, v, 2.0)
 		default:
 			require.Equal(t, float64(0), v)
 			require.False(t, aggType.IsValidForGauge())
 		}
 	}
 }
+
+func TestGaugeMerge(t *testing.T) {
+	opts := NewOptions()
+	opts.HasExpensiveAggregations = true
+	opts.HasCountDistinct = true
+
+	var (
+		g      = NewGauge(opts)
+		first  = NewGauge(opts)
+		second = NewGauge(opts)
+		empty  = NewGauge(opts)
+	)
+	for i := 1.0; i <= 100.0; i++ {
+		g.Update(i)
+		if i <= 50 {
+			first.Update(i)
+		} else {
+			second.Update(i)
+		}
+	}
+
+	merged := NewGauge(opts)
+	merged.Merge(&first)
+	merged.Merge(&second)
+	merged.Merge(&empty)
+	for aggType := range aggregation.ValidTypes {
+		require.Equal(t, g.ValueOf(aggType), merged.ValueOf(aggType))
+	}
+	require.Equal(t, 100.0, merged.Last())
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	// hyperLogLogPrecision is the number of hash bits used to pick a register,
+	// giving a standard error of roughly 1.04/sqrt(2^12) ~= 1.6%.
+	hyperLogLogPrecision    = 12
+	hyperLogLogNumRegisters = 1 << hyperLogLogPrecision
+)
+
+var (
+	hyperLogLogAlpha = 0.7213 / (1 + 1.079/float64(hyperLogLogNumRegisters))
+)
+
+// HyperLogLog is a HyperLogLog sketch estimating the number of distinct values
+// added to it. Sketches are mergeable, which allows distinct counts to be
+// computed across forwarded pipelines. HyperLogLog APIs are not thread-safe.
+type HyperLogLog struct {
+	// Registers are allocated lazily so empty sketches are cheap.
+	registers []uint8
+}
+
+// NewHyperLogLog creates a new HyperLogLog sketch.
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{}
+}
+
+// Add adds a value to the sketch.
+func (h *HyperLogLog) Add(value float64) {
+	if value == 0 {
+		// Normalize negative zero so it is counted as the same value as zero.
+		value = 0
+	}
+	hash := mix64(math.Float64bits(value))
+	idx := uint32(hash >> (64 - hyperLogLogPrecision))
+	// Setting the bit right after the remaining hash bits caps the leading
+	// zero count so the register value always fits in the encoding.
+	w := hash<<hyperLogLogPrecision | 1<<(hyperLogLogPrecision-1)
+	h.MergeRegister(idx, uint8(bits.LeadingZeros64(w)+1))
+}
+
+// MergeRegister merges a single register into the sketch.
+func (h *HyperLogLog) MergeRegister(idx uint32, value uint8) {
+	if idx >= hyperLogLogNumRegisters {
+		return
+	}
+	if h.registers == nil {
+		h.registers = make([]uint8, hyperLogLogNumRegisters)
+	}
+	if h.registers[idx] < value {
+		h.registers[idx] = value
+	}
+}
+
+// Merge merges another sketch into the sketch.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	for idx, value := range other.registers {
+		if value != 0 {
+			h.MergeRegister(uint32(idx), value)
+		}
+	}
+}
+
+// MergeRegisters merges the registers of another sketch, as returned by
+// Registers, into the sketch. Registers of an unexpected length are ignored.
+func (h *HyperLogLog) MergeRegisters(registers []uint8) {
+	if len(registers) != hyperLogLogNumRegisters {
+		return
+	}
+	for idx, value := range registers {
+		if value != 0 {
+			h.MergeRegister(uint32(idx), value)
+		}
+	}
+}
+
+// ForEachRegister calls the given function for each non-empty register.
+func (h *HyperLogLog) ForEachRegister(fn func(idx uint32, value uint8)) {
+	for idx, value := range h.registers {
+		if value != 0 {
+			fn(uint32(idx), value)
+		}
+	}
+}
+
+// Estimate returns the estimated number of distinct values added to the sketch.
+func (h *HyperLogLog) Estimate() float64 {
+	if h.registers == nil {
+		return 0
+	}
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, value := range h.registers {
+		sum += 1.0 / float64(uint64(1)<<value)
+		if value == 0 {
+			zeros++
+		}
+	}
+	m := float64(hyperLogLogNumRegisters)
+	estimate := hyperLogLogAlpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Use linear counting for small cardinalities where the raw
+		// estimate is heavily biased.
+		return m * math.Log(m/float64(zeros))
+	}
+	return estimate
+}
+
+// Registers returns a copy of the sketch registers, or nil if the sketch is empty.
+func (h *HyperLogLog) Registers() []uint8 {
+	if h.registers == nil {
+		return nil
+	}
+	registers := make([]uint8, len(h.registers))
+	copy(registers, h.registers)
+	return registers
+}
+
+// SetRegisters replaces the sketch registers with a copy of the given registers.
+func (h *HyperLogLog) SetRegisters(registers []uint8) {
+	if len(registers) != hyperLogLogNumRegisters {
+		h.registers = nil
+		return
+	}
+	h.registers = make([]uint8, hyperLogLogNumRegisters)
+	copy(h.registers, registers)
+}
+
+// Reset resets the sketch.
+func (h *HyperLogLog) Reset() { h.registers = nil }
+
+// mix64 is the splitmix64 mixing function, which spreads the bits of the
+// input evenly across the output.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLogEmpty(t *testing.T) {
+	h := NewHyperLogLog()
+	require.Equal(t, 0.0, h.Estimate())
+	require.Nil(t, h.Registers())
+}
+
+func TestHyperLogLogEstimate(t *testing.T) {
+	for _, n := range []int{1, 10, 1000, 100000} {
+		h := NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			// Adding each value twice should not change the estimate.
+			h.Add(float64(i))
+			h.Add(float64(i))
+		}
+		require.InEpsilon(t, float64(n), h.Estimate(), 0.05)
+	}
+}
+
+func TestHyperLogLogNegativeZero(t *testing.T) {
+	h := NewHyperLogLog()
+	h.Add(0)
+	h.Add(math.Copysign(0, -1))
+	require.InDelta(t, 1.0, h.Estimate(), 0.01)
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	var (
+		h1       = NewHyperLogLog()
+		h2       = NewHyperLogLog()
+		expected = NewHyperLogLog()
+	)
+	for i := 0; i < 5000; i++ {
+		h1.Add(float64(i))
+		expected.Add(float64(i))
+	}
+	for i := 2500; i < 7500; i++ {
+		h2.Add(float64(i))
+		expected.Add(float64(i))
+	}
+	h1.Merge(h2)
+	require.Equal(t, expected.Registers(), h1.Registers())
+	require.InEpsilon(t, 7500.0, h1.Estimate(), 0.05)
+}
+
+func TestHyperLogLogMergeRegisters(t *testing.T) {
+	h1 := NewHyperLogLog()
+	h2 := NewHyperLogLog()
+	for i := 0; i < 1000; i++ {
+		h1.Add(float64(i))
+		h2.Add(float64(i + 500))
+	}
+	expected := NewHyperLogLog()
+	expected.Merge(h1)
+	expected.Merge(h2)
+
+	h1.MergeRegisters(h2.Registers())
+	require.Equal(t, expected.Registers(), h1.Registers())
+
+	// Registers of an unexpected length are ignored.
+	h1.MergeRegisters([]uint8{1, 2, 3})
+	require.Equal(t, expected.Registers(), h1.Registers())
+
+	empty := NewHyperLogLog()
+	empty.MergeRegisters(nil)
+	require.Nil(t, empty.Registers())
+}
+
+func TestHyperLogLogSetRegisters(t *testing.T) {
+	h := NewHyperLogLog()
+	for i := 0; i < 100; i++ {
+		h.Add(float64(i))
+	}
+
+	restored := NewHyperLogLog()
+	restored.SetRegisters(h.Registers())
+	require.Equal(t, h.Estimate(), restored.Estimate())
+
+	restored.SetRegisters(nil)
+	require.Equal(t, 0.0, restored.Estimate())
+}
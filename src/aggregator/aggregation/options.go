@@ -31,6 +31,10 @@ type Options struct {
 	// HasExpensiveAggregations means expensive (multiplication／division)
 	// aggregation types are enabled.
 	HasExpensiveAggregations bool
+
+	// HasCountDistinct means the count distinct aggregation type is enabled
+	// and a sketch of the distinct values received is maintained.
+	HasCountDistinct bool
 }
 
 // NewOptions creates a new aggregation options.
@@ -41,4 +45,5 @@ func NewOptions() Options {
 // ResetSetData resets the aggregation options.
 func (o *Options) ResetSetData(aggTypes aggregation.Types) {
 	o.HasExpensiveAggregations = isExpensive(aggTypes)
+	o.HasCountDistinct = aggTypes.Contains(aggregation.CountDistinct)
 }
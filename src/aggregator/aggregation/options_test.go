@@ -40,4 +40,9 @@ func TestOptions(t *testing.T) {
 
 	o.ResetSetData(aggregation.Types{aggregation.Sum, aggregation.SumSq})
 	require.True(t, o.HasExpensiveAggregations)
+	require.False(t, o.HasCountDistinct)
+
+	o.ResetSetData(aggregation.Types{aggregation.Sum, aggregation.CountDistinct})
+	require.False(t, o.HasExpensiveAggregations)
+	require.True(t, o.HasCountDistinct)
 }
@@ -187,6 +187,46 @@ func (s *stream) ResetSetData(quantiles []float64) {
 	s.compressMinRank = 0
 }
 
+func (s *stream) Snapshot() StreamSnapshot {
+	s.Flush()
+	snapshot := StreamSnapshot{
+		NumValues: s.numValues,
+		Samples:   make([]SampleSnapshot, 0, s.samples.Len()),
+	}
+	for sample := s.samples.Front(); sample != nil; sample = sample.next {
+		snapshot.Samples = append(snapshot.Samples, SampleSnapshot{
+			Value:    sample.value,
+			NumRanks: sample.numRanks,
+			Delta:    sample.delta,
+		})
+	}
+	return snapshot
+}
+
+func (s *stream) Restore(snapshot StreamSnapshot) {
+	sample := s.samples.Front()
+	for sample != nil {
+		next := sample.next
+		s.releaseSampleFn(sample)
+		sample = next
+	}
+	s.insertAndCompressCounter = 0
+	s.flushCounter = 0
+	s.bufLess = s.bufLess[:0]
+	s.bufMore = s.bufMore[:0]
+	s.samples.Reset()
+	s.insertCursor = nil
+	s.compressCursor = nil
+	s.compressMinRank = 0
+
+	for _, ss := range snapshot.Samples {
+		sample := s.acquireSampleFn()
+		sample.setData(ss.Value, ss.NumRanks, ss.Delta)
+		s.samples.PushBack(sample)
+	}
+	s.numValues = snapshot.NumValues
+}
+
 func (s *stream) Close() {
 	if s.closed {
 		return
@@ -212,6 +252,34 @@ func (s *stream) Close() {
 	s.streamPool.Put(s)
 }
 
+// MergeSnapshots merges the samples of two stream snapshots. The rank uncertainty
+// of each sample is widened by that of the closest larger sample from the other
+// snapshot so the error bounds of both streams still hold for the merged samples.
+func MergeSnapshots(a, b StreamSnapshot) StreamSnapshot {
+	merged := StreamSnapshot{
+		NumValues: a.NumValues + b.NumValues,
+		Samples:   make([]SampleSnapshot, 0, len(a.Samples)+len(b.Samples)),
+	}
+	i, j := 0, 0
+	for i < len(a.Samples) || j < len(b.Samples) {
+		if j == len(b.Samples) || (i < len(a.Samples) && a.Samples[i].Value <= b.Samples[j].Value) {
+			merged.Samples = append(merged.Samples, mergedSample(a.Samples[i], b.Samples, j))
+			i++
+		} else {
+			merged.Samples = append(merged.Samples, mergedSample(b.Samples[j], a.Samples, i))
+			j++
+		}
+	}
+	return merged
+}
+
+func mergedSample(sample SampleSnapshot, other []SampleSnapshot, next int) SampleSnapshot {
+	if next < len(other) {
+		sample.Delta += other[next].NumRanks + other[next].Delta - 1
+	}
+	return sample
+}
+
 // addToBuffer adds a new sample to the buffer.
 func (s *stream) addToBuffer(value float64) {
 	if s.numValues > 0 && value < s.insertPointValue() {
@@ -199,6 +199,56 @@ func TestStreamClose(t *testing.T) {
 	require.True(t, s.closed)
 }
 
+func TestStreamSnapshotRestore(t *testing.T) {
+	opts := testStreamOptions().SetInsertAndCompressEvery(testInsertAndCompressEvery)
+	s := NewStream(testQuantiles, opts)
+	for i := 0; i < 10000; i++ {
+		s.Add(float64(i))
+	}
+	snapshot := s.Snapshot()
+	require.Equal(t, int64(10000), snapshot.NumValues)
+
+	restored := NewStream(testQuantiles, opts)
+	restored.Add(-1.0)
+	restored.Restore(snapshot)
+	require.Equal(t, snapshot, restored.Snapshot())
+	require.Equal(t, s.Min(), restored.Min())
+	require.Equal(t, s.Max(), restored.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, s.Quantile(q), restored.Quantile(q))
+	}
+
+	// Values added after a restore are merged with the restored samples.
+	restored.Add(20000.0)
+	restored.Flush()
+	require.Equal(t, 20000.0, restored.Max())
+}
+
+func TestMergeSnapshots(t *testing.T) {
+	opts := testStreamOptions().SetInsertAndCompressEvery(testInsertAndCompressEvery)
+	s1 := NewStream(testQuantiles, opts)
+	s2 := NewStream(testQuantiles, opts)
+	for i := 0; i < 10000; i++ {
+		s1.Add(float64(2 * i))
+		s2.Add(float64(2*i + 1))
+	}
+
+	merged := NewStream(testQuantiles, opts)
+	merged.Restore(MergeSnapshots(s1.Snapshot(), s2.Snapshot()))
+	require.Equal(t, 0.0, merged.Min())
+	require.Equal(t, 19999.0, merged.Max())
+	margin := 20000 * 2 * opts.Eps()
+	for _, q := range testQuantiles {
+		val := merged.Quantile(q)
+		require.True(t, val >= 20000*q-margin && val <= 20000*q+margin)
+	}
+
+	// Merging with an empty snapshot keeps the samples untouched.
+	snapshot := s1.Snapshot()
+	require.Equal(t, snapshot, MergeSnapshots(snapshot, StreamSnapshot{}))
+	require.Equal(t, snapshot, MergeSnapshots(StreamSnapshot{}, snapshot))
+}
+
 func TestStreamAddToMinHeap(t *testing.T) {
 	floatsPool := pool.NewFloatsPool(
 		[]pool.Bucket{
@@ -65,6 +65,25 @@ type Stream interface {
 
 	// ResetSetData resets the stream and sets data.
 	ResetSetData(quantiles []float64)
+
+	// Snapshot flushes the internal buffer and returns a snapshot of the samples.
+	Snapshot() StreamSnapshot
+
+	// Restore replaces the samples in the stream with those in the snapshot.
+	Restore(snapshot StreamSnapshot)
+}
+
+// SampleSnapshot is a point-in-time copy of a sample.
+type SampleSnapshot struct {
+	Value    float64
+	NumRanks int64
+	Delta    int64
+}
+
+// StreamSnapshot is a point-in-time copy of the samples in a stream.
+type StreamSnapshot struct {
+	NumValues int64
+	Samples   []SampleSnapshot
 }
 
 // StreamAlloc allocates a stream.
@@ -33,6 +33,8 @@ type Timer struct {
 	sum    float64   // Sum of the values.
 	sumSq  float64   // Sum of squared values.
 	stream cm.Stream // Stream of values received.
+
+	distinct HyperLogLog // Sketch of distinct values received.
 }
 
 // NewTimer creates a new timer
@@ -54,6 +56,10 @@ func (t *Timer) Add(value float64) {
 	if t.HasExpensiveAggregations {
 		t.sumSq += value * value
 	}
+
+	if t.HasCountDistinct {
+		t.distinct.Add(value)
+	}
 }
 
 // AddBatch adds a batch of timer values.
@@ -63,6 +69,18 @@ func (t *Timer) AddBatch(values []float64) {
 	}
 }
 
+// Merge merges the values aggregated by another timer into the timer.
+func (t *Timer) Merge(other *Timer) {
+	t.distinct.Merge(&other.distinct)
+	if other.count == 0 {
+		return
+	}
+	t.count += other.count
+	t.sum += other.sum
+	t.sumSq += other.sumSq
+	t.stream.Restore(cm.MergeSnapshots(t.stream.Snapshot(), other.stream.Snapshot()))
+}
+
 // Quantile returns the value at a given quantile.
 func (t *Timer) Quantile(q float64) float64 {
 	t.stream.Flush()
@@ -103,6 +121,20 @@ func (t *Timer) Stdev() float64 {
 	return stdev(t.count, t.sumSq, t.sum)
 }
 
+// CountDistinct returns the estimated number of distinct timer values.
+func (t *Timer) CountDistinct() float64 { return t.distinct.Estimate() }
+
+// CountDistinctSketch returns the sketch of distinct timer values.
+func (t *Timer) CountDistinctSketch() *HyperLogLog { return &t.distinct }
+
+// MergeCountDistinctSketch merges the registers of a count distinct sketch
+// forwarded from upstream into the timer's sketch.
+func (t *Timer) MergeCountDistinctSketch(registers []uint8) {
+	if t.HasCountDistinct {
+		t.distinct.MergeRegisters(registers)
+	}
+}
+
 // ValueOf returns the value for the aggregation type.
 func (t *Timer) ValueOf(aggType aggregation.Type) float64 {
 	if q, ok := aggType.Quantile(); ok {
@@ -124,9 +156,43 @@ func (t *Timer) ValueOf(aggType aggregation.Type) float64 {
 		return t.SumSq()
 	case aggregation.Stdev:
 		return t.Stdev()
+	case aggregation.CountDistinct:
+		return t.CountDistinct()
 	}
 	return 0
 }
 
+// TimerSnapshot is a point-in-time copy of the timer state.
+type TimerSnapshot struct {
+	Count  int64
+	Sum    float64
+	SumSq  float64
+	Stream cm.StreamSnapshot
+
+	// CountDistinctRegisters are the count distinct sketch registers, if any.
+	CountDistinctRegisters []uint8
+}
+
+// Snapshot returns a snapshot of the timer state.
+func (t *Timer) Snapshot() TimerSnapshot {
+	return TimerSnapshot{
+		Count:  t.count,
+		Sum:    t.sum,
+		SumSq:  t.sumSq,
+		Stream: t.stream.Snapshot(),
+
+		CountDistinctRegisters: t.distinct.Registers(),
+	}
+}
+
+// Restore replaces the timer state with that in the snapshot.
+func (t *Timer) Restore(snapshot TimerSnapshot) {
+	t.count = snapshot.Count
+	t.sum = snapshot.Sum
+	t.sumSq = snapshot.SumSq
+	t.stream.Restore(snapshot.Stream)
+	t.distinct.SetRegisters(snapshot.CountDistinctRegisters)
+}
+
 // Close closes the timer.
 func (t *Timer) Close() { t.stream.Close() }
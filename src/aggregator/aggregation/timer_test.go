@@ -45,6 +45,7 @@ var (
 		aggregation.P50,
 		aggregation.P95,
 		aggregation.P99,
+		aggregation.CountDistinct,
 	}
 )
 
@@ -126,6 +127,8 @@ func TestTimerAggregations(t *testing.T) {
 			require.Equal(t, 338350.0, v)
 		case aggregation.Stdev:
 			require.InDelta(t, 29.01149, v, 0.001)
+		case aggregation.CountDistinct:
+			require.InDelta(t, 100.0, v, 2.0)
 		case aggregation.P50:
 			require.Equal(t, 50.0, v)
 		case aggregation.P95:
@@ -169,3 +172,57 @@ func TestTimerAggregationsNotExpensive(t *testing.T) {
 	// Closing the timer a second time should be a no op.
 	timer.Close()
 }
+
+func TestTimerSnapshotRestore(t *testing.T) {
+	opts := NewOptions()
+	opts.ResetSetData(testAggTypes)
+
+	timer := NewTimer(testQuantiles, cm.NewOptions(), opts)
+	for i := 1; i <= 100; i++ {
+		timer.Add(float64(i))
+	}
+
+	restored := NewTimer(testQuantiles, cm.NewOptions(), opts)
+	restored.Add(1000.0)
+	restored.Restore(timer.Snapshot())
+	for _, aggType := range testAggTypes {
+		require.Equal(t, timer.ValueOf(aggType), restored.ValueOf(aggType))
+	}
+
+	// Values added after a restore are aggregated with the restored state.
+	restored.Add(101.0)
+	require.Equal(t, int64(101), restored.Count())
+	require.Equal(t, 101.0, restored.Max())
+}
+
+func TestTimerMerge(t *testing.T) {
+	opts := NewOptions()
+	opts.ResetSetData(testAggTypes)
+
+	var (
+		timer  = NewTimer(testQuantiles, cm.NewOptions(), opts)
+		first  = NewTimer(testQuantiles, cm.NewOptions(), opts)
+		second = NewTimer(testQuantiles, cm.NewOptions(), opts)
+		empty  = NewTimer(testQuantiles, cm.NewOptions(), opts)
+	)
+	for i := 1; i <= 100; i++ {
+		timer.Add(float64(i))
+		if i%2 == 0 {
+			first.Add(float64(i))
+		} else {
+			second.Add(float64(i))
+		}
+	}
+
+	merged := NewTimer(testQuantiles, cm.NewOptions(), opts)
+	merged.Merge(&first)
+	merged.Merge(&empty)
+	merged.Merge(&second)
+	for _, aggType := range testAggTypes {
+		if _, ok := aggType.Quantile(); ok {
+			require.InDelta(t, timer.ValueOf(aggType), merged.ValueOf(aggType), 2.0)
+			continue
+		}
+		require.Equal(t, timer.ValueOf(aggType), merged.ValueOf(aggType))
+	}
+}
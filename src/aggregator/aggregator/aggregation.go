@@ -25,6 +25,14 @@ import (
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 )
 
+// aggregationSnapshot is a point-in-time copy of an aggregation. Only the
+// field matching the type of the aggregation is set.
+type aggregationSnapshot struct {
+	counter aggregation.CounterSnapshot
+	timer   aggregation.TimerSnapshot
+	gauge   aggregation.GaugeSnapshot
+}
+
 // counterAggregation is a counter aggregation.
 type counterAggregation struct {
 	aggregation.Counter
@@ -37,6 +45,16 @@ func newCounterAggregation(c aggregation.Counter) counterAggregation {
 func (c *counterAggregation) Add(value float64)                    { c.Counter.Update(int64(value)) }
 func (c *counterAggregation) AddUnion(mu unaggregated.MetricUnion) { c.Counter.Update(mu.CounterVal) }
 
+func (c *counterAggregation) Snapshot() aggregationSnapshot {
+	return aggregationSnapshot{counter: c.Counter.Snapshot()}
+}
+
+func (c *counterAggregation) Restore(snapshot aggregationSnapshot) {
+	c.Counter.Restore(snapshot.counter)
+}
+
+func (c *counterAggregation) Merge(other *counterAggregation) { c.Counter.Merge(&other.Counter) }
+
 // timerAggregation is a timer aggregation.
 type timerAggregation struct {
 	aggregation.Timer
@@ -46,6 +64,16 @@ func newTimerAggregation(t aggregation.Timer) timerAggregation   { return timerA
 func (t *timerAggregation) Add(value float64)                    { t.Timer.Add(value) }
 func (t *timerAggregation) AddUnion(mu unaggregated.MetricUnion) { t.Timer.AddBatch(mu.BatchTimerVal) }
 
+func (t *timerAggregation) Snapshot() aggregationSnapshot {
+	return aggregationSnapshot{timer: t.Timer.Snapshot()}
+}
+
+func (t *timerAggregation) Restore(snapshot aggregationSnapshot) {
+	t.Timer.Restore(snapshot.timer)
+}
+
+func (t *timerAggregation) Merge(other *timerAggregation) { t.Timer.Merge(&other.Timer) }
+
 // gaugeAggregation is a gauge aggregation.
 type gaugeAggregation struct {
 	aggregation.Gauge
@@ -54,3 +82,13 @@ type gaugeAggregation struct {
 func newGaugeAggregation(g aggregation.Gauge) gaugeAggregation   { return gaugeAggregation{Gauge: g} }
 func (g *gaugeAggregation) Add(value float64)                    { g.Gauge.Update(value) }
 func (g *gaugeAggregation) AddUnion(mu unaggregated.MetricUnion) { g.Gauge.Update(mu.GaugeVal) }
+
+func (g *gaugeAggregation) Snapshot() aggregationSnapshot {
+	return aggregationSnapshot{gauge: g.Gauge.Snapshot()}
+}
+
+func (g *gaugeAggregation) Restore(snapshot aggregationSnapshot) {
+	g.Gauge.Restore(snapshot.gauge)
+}
+
+func (g *gaugeAggregation) Merge(other *gaugeAggregation) { g.Gauge.Merge(&other.Gauge) }
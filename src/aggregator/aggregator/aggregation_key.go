@@ -21,6 +21,8 @@
 package aggregator
 
 import (
+	"bytes"
+
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/pipeline/applied"
 	"github.com/m3db/m3/src/metrics/policy"
@@ -32,6 +34,7 @@ type aggregationKey struct {
 	pipeline           applied.Pipeline
 	numForwardedTimes  int
 	idPrefixSuffixType IDPrefixSuffixType
+	rollupRuleID       []byte // only set for the keys of forwarded aggregations
 }
 
 func (k aggregationKey) Equal(other aggregationKey) bool {
@@ -39,5 +42,6 @@ func (k aggregationKey) Equal(other aggregationKey) bool {
 		k.storagePolicy == other.storagePolicy &&
 		k.pipeline.Equal(other.pipeline) &&
 		k.numForwardedTimes == other.numForwardedTimes &&
-		k.idPrefixSuffixType == other.idPrefixSuffixType
+		k.idPrefixSuffixType == other.idPrefixSuffixType &&
+		bytes.Equal(k.rollupRuleID, other.rollupRuleID)
 }
@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 
 	"github.com/uber-go/tally"
+	"go.uber.org/zap"
 )
 
 const (
@@ -57,6 +58,7 @@ var (
 	errInvalidMetricType             = errors.New("invalid metric type")
 	errActivePlacementChanged        = errors.New("active placement has changed")
 	errShardNotOwned                 = errors.New("aggregator shard is not owned")
+	errEmptyEntriesQuery             = errors.New("entries query must have an id or a filter")
 )
 
 // Aggregator aggregates different types of metrics.
@@ -80,6 +82,13 @@ type Aggregator interface {
 	// Status returns the run-time status of the aggregator.
 	Status() RuntimeStatus
 
+	// Entries returns descriptions of the live entries matching the query.
+	Entries(query EntriesQuery) ([]EntryInfo, error)
+
+	// LargestEntries returns descriptions of the live entries with the largest
+	// estimated memory use in each owned shard.
+	LargestEntries(limitPerShard int) ([]ShardEntries, error)
+
 	// Close closes the aggregator.
 	Close() error
 }
@@ -101,6 +110,9 @@ type aggregator struct {
 	flushHandler      handler.Handler
 	adminClient       client.AdminClient
 	resignTimeout     time.Duration
+	checkpointer      *checkpointer
+	checkpointEvery   time.Duration
+	logger            *zap.Logger
 
 	shardSetID          uint32
 	shardSetOpen        bool
@@ -121,7 +133,7 @@ func NewAggregator(opts Options) Aggregator {
 	iOpts := opts.InstrumentOptions()
 	scope := iOpts.MetricsScope()
 	samplingRate := iOpts.MetricsSamplingRate()
-	return &aggregator{
+	agg := &aggregator{
 		opts:              opts,
 		nowFn:             opts.ClockOptions().NowFn(),
 		shardFn:           opts.ShardFn(),
@@ -137,7 +149,13 @@ func NewAggregator(opts Options) Aggregator {
 		metrics:           newAggregatorMetrics(scope, samplingRate, opts.MaxAllowedForwardingDelayFn()),
 		doneCh:            make(chan struct{}),
 		sleepFn:           time.Sleep,
+		logger:            iOpts.Logger(),
+	}
+	if checkpointOpts := opts.CheckpointOptions(); checkpointOpts.Enabled() {
+		agg.checkpointer = newCheckpointer(checkpointOpts)
+		agg.checkpointEvery = checkpointOpts.Interval()
 	}
+	return agg
 }
 
 func (agg *aggregator) Open() error {
@@ -161,6 +179,10 @@ func (agg *aggregator) Open() error {
 		agg.wg.Add(1)
 		go agg.tick()
 	}
+	if agg.checkpointer != nil && agg.checkpointEvery > 0 {
+		agg.wg.Add(1)
+		go agg.checkpoint()
+	}
 	agg.state = aggregatorOpen
 	return nil
 }
@@ -244,6 +266,64 @@ func (agg *aggregator) Status() RuntimeStatus {
 	}
 }
 
+func (agg *aggregator) Entries(query EntriesQuery) ([]EntryInfo, error) {
+	if len(query.ID) > 0 {
+		shard, err := agg.shardFor(query.ID)
+		if err != nil {
+			return nil, err
+		}
+		return shard.Entries(query.ID)
+	}
+	if query.Filter == nil {
+		return nil, errEmptyEntriesQuery
+	}
+	shards, err := agg.openOwnedShards()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		matchFn = func(id id.RawID) bool { return query.Filter.Matches(id) }
+		res     []EntryInfo
+	)
+	for _, shard := range shards {
+		limit := 0
+		if query.Limit > 0 {
+			if len(res) >= query.Limit {
+				break
+			}
+			limit = query.Limit - len(res)
+		}
+		entries, err := shard.MatchingEntries(matchFn, limit)
+		if err == errAggregatorShardClosed {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, entries...)
+	}
+	return res, nil
+}
+
+func (agg *aggregator) LargestEntries(limitPerShard int) ([]ShardEntries, error) {
+	shards, err := agg.openOwnedShards()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]ShardEntries, 0, len(shards))
+	for _, shard := range shards {
+		entries, err := shard.LargestEntries(limitPerShard)
+		if err == errAggregatorShardClosed {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ShardEntries{Shard: shard.ID(), Entries: entries})
+	}
+	return res, nil
+}
+
 func (agg *aggregator) Close() error {
 	agg.Lock()
 	defer agg.Unlock()
@@ -252,6 +332,9 @@ func (agg *aggregator) Close() error {
 		return errAggregatorNotOpenOrClosed
 	}
 	close(agg.doneCh)
+	if agg.checkpointer != nil {
+		agg.writeCheckpoints(agg.ownedShardsWithLock())
+	}
 	for _, shardID := range agg.shardIDs {
 		agg.shards[shardID].Close()
 	}
@@ -407,6 +490,12 @@ func (agg *aggregator) openShardSetWithLock(shardSetID uint32) error {
 	if err := agg.flushTimesManager.Open(shardSetID); err != nil {
 		return err
 	}
+	// NB: checkpoints are only restored when the aggregator starts up, and must be
+	// restored before the flush manager opens so the restored aggregations that have
+	// not been flushed yet are flushed, and those that have been flushed are discarded.
+	if agg.checkpointer != nil && agg.state == aggregatorNotOpen {
+		agg.restoreCheckpointsWithLock()
+	}
 	if err := agg.electionManager.Open(shardSetID); err != nil {
 		return err
 	}
@@ -600,6 +689,77 @@ func (agg *aggregator) tickInternal() {
 	}
 }
 
+func (agg *aggregator) checkpoint() {
+	defer agg.wg.Done()
+
+	ticker := time.NewTicker(agg.checkpointEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-agg.doneCh:
+			return
+		case <-ticker.C:
+			agg.RLock()
+			if agg.state != aggregatorOpen {
+				agg.RUnlock()
+				continue
+			}
+			shards := agg.ownedShardsWithLock()
+			agg.RUnlock()
+			agg.writeCheckpoints(shards)
+		}
+	}
+}
+
+func (agg *aggregator) openOwnedShards() ([]*aggregatorShard, error) {
+	agg.RLock()
+	defer agg.RUnlock()
+
+	if agg.state != aggregatorOpen {
+		return nil, errAggregatorNotOpenOrClosed
+	}
+	return agg.ownedShardsWithLock(), nil
+}
+
+func (agg *aggregator) ownedShardsWithLock() []*aggregatorShard {
+	shards := make([]*aggregatorShard, 0, len(agg.shardIDs))
+	for _, shardID := range agg.shardIDs {
+		shards = append(shards, agg.shards[shardID])
+	}
+	return shards
+}
+
+func (agg *aggregator) writeCheckpoints(shards []*aggregatorShard) {
+	for _, shard := range shards {
+		err := shard.WriteCheckpoint(agg.checkpointer)
+		if err == nil || err == errAggregatorShardClosed {
+			continue
+		}
+		agg.logger.Error("error writing checkpoint",
+			zap.Uint32("shard", shard.ID()),
+			zap.Error(err),
+		)
+	}
+}
+
+func (agg *aggregator) restoreCheckpointsWithLock() {
+	flushTimes, err := agg.flushTimesManager.Get()
+	if err != nil {
+		agg.metrics.tick.flushTimesErrors.Inc(1)
+		flushTimes = nil
+	}
+	for _, shardID := range agg.shardIDs {
+		shard := agg.shards[shardID]
+		if err := shard.RestoreCheckpoint(agg.checkpointer, flushTimes); err != nil {
+			agg.logger.Error("error restoring checkpoint",
+				zap.Uint32("shard", shardID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 type aggregatorAddMetricMetrics struct {
 	success                    tally.Counter
 	successLatency             tally.Timer
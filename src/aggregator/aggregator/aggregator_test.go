@@ -22,7 +22,9 @@ package aggregator
 
 import (
 	"errors"
+	"io/ioutil"
 	"math"
+	"os"
 	"sort"
 	"testing"
 	"time"
@@ -693,6 +695,40 @@ func TestAggregatorCloseSuccess(t *testing.T) {
 	require.Equal(t, aggregatorClosed, agg.state)
 }
 
+func TestAggregatorCheckpointRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	flushTimesManager := NewMockFlushTimesManager(ctrl)
+	flushTimesManager.EXPECT().Reset().Return(nil).AnyTimes()
+	flushTimesManager.EXPECT().Open(gomock.Any()).Return(nil).AnyTimes()
+	flushTimesManager.EXPECT().Get().Return(nil, nil).AnyTimes()
+	flushTimesManager.EXPECT().Close().Return(nil).AnyTimes()
+	checkpointOpts := NewCheckpointOptions().SetEnabled(true).SetDirectory(dir)
+
+	// Closing the aggregator writes a checkpoint for each owned shard.
+	agg, _ := testAggregator(t, ctrl)
+	agg.flushTimesManager = flushTimesManager
+	agg.checkpointer = newCheckpointer(checkpointOpts)
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+	require.NoError(t, agg.AddUntimed(testUntimedMetric, testStagedMetadatas))
+	require.NoError(t, agg.Close())
+
+	// Opening a new aggregator restores the checkpoints.
+	agg, _ = testAggregator(t, ctrl)
+	agg.flushTimesManager = flushTimesManager
+	agg.checkpointer = newCheckpointer(checkpointOpts)
+	require.NoError(t, agg.Open())
+	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
+	require.Equal(t, 0, len(agg.shards[0].metricMap.entries))
+	require.NoError(t, agg.Close())
+}
+
 func TestAggregatorTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -135,6 +135,9 @@ func (agg *aggregator) Resign() error              { return nil }
 func (agg *aggregator) Status() aggr.RuntimeStatus { return aggr.RuntimeStatus{} }
 func (agg *aggregator) Close() error               { return nil }
 
+func (agg *aggregator) Entries(aggr.EntriesQuery) ([]aggr.EntryInfo, error) { return nil, nil }
+func (agg *aggregator) LargestEntries(int) ([]aggr.ShardEntries, error)     { return nil, nil }
+
 func (agg *aggregator) NumMetricsAdded() int {
 	agg.RLock()
 	numMetricsAdded := agg.numMetricsAdded
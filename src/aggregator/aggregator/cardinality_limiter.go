@@ -0,0 +1,226 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	rollupCardinalityLimit = "rollup"
+	tagCardinalityLimit    = "tag"
+)
+
+// CardinalityLimiter limits the number of distinct rollup series tracked by the
+// aggregator. Series exceeding the limits are collapsed into overflow series so
+// their values are still accounted for.
+type CardinalityLimiter interface {
+	// Acquire reserves capacity for a new series with the given id produced by
+	// the given rollup rule. Series are limited per rollup rule, or per rollup
+	// name if the rule id is not known. If the series would exceed a limit, the
+	// id of the overflow series its values should be collapsed into is returned
+	// instead and nothing is reserved.
+	Acquire(rollupRuleID, seriesID []byte) (CardinalityReservation, []byte)
+
+	// Release releases the capacity reserved for a series.
+	Release(reservation CardinalityReservation)
+}
+
+// CardinalityReservation is the capacity reserved for a series.
+type CardinalityReservation struct {
+	reserved    bool
+	rollup      string
+	hasTagValue bool
+	tagValue    string
+}
+
+type overflowedMetricKey struct {
+	rollup string
+	limit  string
+}
+
+type cardinalityLimiterMetrics struct {
+	sync.Mutex
+
+	scope        tally.Scope
+	acquired     tally.Counter
+	released     tally.Counter
+	parseErrors  tally.Counter
+	overflowedBy map[overflowedMetricKey]tally.Counter
+}
+
+func newCardinalityLimiterMetrics(scope tally.Scope) *cardinalityLimiterMetrics {
+	return &cardinalityLimiterMetrics{
+		scope:        scope,
+		acquired:     scope.Counter("acquired"),
+		released:     scope.Counter("released"),
+		parseErrors:  scope.Counter("parse-errors"),
+		overflowedBy: make(map[overflowedMetricKey]tally.Counter),
+	}
+}
+
+// overflowed returns the counter of series collapsed into the overflow series
+// of the given rollup because of the given limit.
+func (m *cardinalityLimiterMetrics) overflowed(rollup string, limit string) tally.Counter {
+	key := overflowedMetricKey{rollup: rollup, limit: limit}
+	m.Lock()
+	counter, exists := m.overflowedBy[key]
+	if !exists {
+		counter = m.scope.Tagged(map[string]string{
+			"rollup": rollup,
+			"limit":  limit,
+		}).Counter("overflowed")
+		m.overflowedBy[key] = counter
+	}
+	m.Unlock()
+	return counter
+}
+
+type cardinalityLimiter struct {
+	sync.Mutex
+
+	nameAndTagsFn        id.NameAndTagsFn
+	sortedTagIteratorFn  id.SortedTagIteratorFn
+	newOverflowIDFn      id.NewIDFn
+	overflowTagPair      id.TagPair
+	maxSeriesPerRollup   int
+	limitTagName         []byte
+	maxSeriesPerTagValue int
+
+	seriesByRollup   map[string]int
+	seriesByTagValue map[string]int
+	metrics          *cardinalityLimiterMetrics
+}
+
+// NewCardinalityLimiter creates a new cardinality limiter.
+func NewCardinalityLimiter(opts CardinalityLimitOptions) CardinalityLimiter {
+	return &cardinalityLimiter{
+		nameAndTagsFn:        opts.NameAndTagsFn(),
+		sortedTagIteratorFn:  opts.SortedTagIteratorFn(),
+		newOverflowIDFn:      opts.NewOverflowIDFn(),
+		overflowTagPair:      opts.OverflowTagPair(),
+		maxSeriesPerRollup:   opts.MaxSeriesPerRollup(),
+		limitTagName:         opts.LimitTagName(),
+		maxSeriesPerTagValue: opts.MaxSeriesPerTagValue(),
+		seriesByRollup:       make(map[string]int),
+		seriesByTagValue:     make(map[string]int),
+		metrics:              newCardinalityLimiterMetrics(opts.InstrumentOptions().MetricsScope()),
+	}
+}
+
+func (l *cardinalityLimiter) Acquire(rollupRuleID, seriesID []byte) (CardinalityReservation, []byte) {
+	if !l.rollupLimitEnabled() && !l.tagLimitEnabled() {
+		return CardinalityReservation{}, nil
+	}
+	name, tags, err := l.nameAndTagsFn(seriesID)
+	if err != nil {
+		// Series whose ids can not be parsed are not limited.
+		l.metrics.parseErrors.Inc(1)
+		return CardinalityReservation{}, nil
+	}
+	tagValue, hasTagValue := l.limitTagValue(tags)
+
+	// Rollup rules producing the same output name are limited separately.
+	rollup := string(rollupRuleID)
+	if len(rollup) == 0 {
+		rollup = string(name)
+	}
+
+	l.Lock()
+	if l.rollupLimitEnabled() && l.seriesByRollup[rollup] >= l.maxSeriesPerRollup {
+		l.Unlock()
+		l.metrics.overflowed(rollup, rollupCardinalityLimit).Inc(1)
+		return CardinalityReservation{}, l.newOverflowIDFn(name, []id.TagPair{l.overflowTagPair})
+	}
+	if hasTagValue && l.seriesByTagValue[string(tagValue)] >= l.maxSeriesPerTagValue {
+		l.Unlock()
+		l.metrics.overflowed(rollup, tagCardinalityLimit).Inc(1)
+		return CardinalityReservation{}, l.newOverflowIDFn(name, []id.TagPair{
+			{Name: l.limitTagName, Value: tagValue},
+			l.overflowTagPair,
+		})
+	}
+	reservation := CardinalityReservation{
+		reserved:    true,
+		rollup:      rollup,
+		hasTagValue: hasTagValue,
+		tagValue:    string(tagValue),
+	}
+	l.seriesByRollup[reservation.rollup]++
+	if hasTagValue {
+		l.seriesByTagValue[reservation.tagValue]++
+	}
+	l.Unlock()
+	l.metrics.acquired.Inc(1)
+	return reservation, nil
+}
+
+func (l *cardinalityLimiter) Release(reservation CardinalityReservation) {
+	if !reservation.reserved {
+		return
+	}
+	l.Lock()
+	decrementOrDelete(l.seriesByRollup, reservation.rollup)
+	if reservation.hasTagValue {
+		decrementOrDelete(l.seriesByTagValue, reservation.tagValue)
+	}
+	l.Unlock()
+	l.metrics.released.Inc(1)
+}
+
+func (l *cardinalityLimiter) rollupLimitEnabled() bool {
+	return l.maxSeriesPerRollup > 0
+}
+
+func (l *cardinalityLimiter) tagLimitEnabled() bool {
+	return len(l.limitTagName) > 0 && l.maxSeriesPerTagValue > 0
+}
+
+// limitTagValue returns the value of the limit tag if the tag limit is enabled
+// and the tags contain the limit tag.
+func (l *cardinalityLimiter) limitTagValue(tags []byte) ([]byte, bool) {
+	if !l.tagLimitEnabled() {
+		return nil, false
+	}
+	it := l.sortedTagIteratorFn(tags)
+	defer it.Close()
+
+	for it.Next() {
+		name, value := it.Current()
+		if bytes.Equal(name, l.limitTagName) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func decrementOrDelete(counts map[string]int, key string) {
+	if counts[key] <= 1 {
+		delete(counts, key)
+		return
+	}
+	counts[key]--
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	defaultCardinalityOverflowTagPair = id.TagPair{
+		Name:  []byte("m3_overflow"),
+		Value: []byte("true"),
+	}
+)
+
+// CardinalityLimitOptions provide a set of options for limiting the number of
+// distinct rollup series tracked by the aggregator.
+type CardinalityLimitOptions interface {
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) CardinalityLimitOptions
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetNameAndTagsFn sets the function to extract the name and tags of a rollup id.
+	SetNameAndTagsFn(value id.NameAndTagsFn) CardinalityLimitOptions
+
+	// NameAndTagsFn returns the function to extract the name and tags of a rollup id.
+	NameAndTagsFn() id.NameAndTagsFn
+
+	// SetSortedTagIteratorFn sets the function to iterate over the tags of a rollup id.
+	SetSortedTagIteratorFn(value id.SortedTagIteratorFn) CardinalityLimitOptions
+
+	// SortedTagIteratorFn returns the function to iterate over the tags of a rollup id.
+	SortedTagIteratorFn() id.SortedTagIteratorFn
+
+	// SetNewOverflowIDFn sets the function to create the id of an overflow series.
+	SetNewOverflowIDFn(value id.NewIDFn) CardinalityLimitOptions
+
+	// NewOverflowIDFn returns the function to create the id of an overflow series.
+	NewOverflowIDFn() id.NewIDFn
+
+	// SetOverflowTagPair sets the tag pair identifying overflow series.
+	SetOverflowTagPair(value id.TagPair) CardinalityLimitOptions
+
+	// OverflowTagPair returns the tag pair identifying overflow series.
+	OverflowTagPair() id.TagPair
+
+	// SetMaxSeriesPerRollup sets the maximum number of distinct series for each
+	// rollup metric name, with zero meaning no limit.
+	SetMaxSeriesPerRollup(value int) CardinalityLimitOptions
+
+	// MaxSeriesPerRollup returns the maximum number of distinct series for each
+	// rollup metric name, with zero meaning no limit.
+	MaxSeriesPerRollup() int
+
+	// SetLimitTagName sets the name of the tag whose values have their number of
+	// distinct series limited.
+	SetLimitTagName(value []byte) CardinalityLimitOptions
+
+	// LimitTagName returns the name of the tag whose values have their number of
+	// distinct series limited.
+	LimitTagName() []byte
+
+	// SetMaxSeriesPerTagValue sets the maximum number of distinct series for each
+	// value of the limit tag, with zero meaning no limit.
+	SetMaxSeriesPerTagValue(value int) CardinalityLimitOptions
+
+	// MaxSeriesPerTagValue returns the maximum number of distinct series for each
+	// value of the limit tag, with zero meaning no limit.
+	MaxSeriesPerTagValue() int
+}
+
+type cardinalityLimitOptions struct {
+	instrumentOpts       instrument.Options
+	nameAndTagsFn        id.NameAndTagsFn
+	sortedTagIteratorFn  id.SortedTagIteratorFn
+	newOverflowIDFn      id.NewIDFn
+	overflowTagPair      id.TagPair
+	maxSeriesPerRollup   int
+	limitTagName         []byte
+	maxSeriesPerTagValue int
+}
+
+// NewCardinalityLimitOptions create a new set of cardinality limit options.
+func NewCardinalityLimitOptions() CardinalityLimitOptions {
+	return &cardinalityLimitOptions{
+		instrumentOpts:      instrument.NewOptions(),
+		nameAndTagsFn:       m3.NameAndTags,
+		sortedTagIteratorFn: m3.NewSortedTagIterator,
+		newOverflowIDFn:     m3.NewRollupID,
+		overflowTagPair:     defaultCardinalityOverflowTagPair,
+	}
+}
+
+func (o *cardinalityLimitOptions) SetInstrumentOptions(value instrument.Options) CardinalityLimitOptions {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *cardinalityLimitOptions) SetNameAndTagsFn(value id.NameAndTagsFn) CardinalityLimitOptions {
+	opts := *o
+	opts.nameAndTagsFn = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) NameAndTagsFn() id.NameAndTagsFn {
+	return o.nameAndTagsFn
+}
+
+func (o *cardinalityLimitOptions) SetSortedTagIteratorFn(value id.SortedTagIteratorFn) CardinalityLimitOptions {
+	opts := *o
+	opts.sortedTagIteratorFn = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) SortedTagIteratorFn() id.SortedTagIteratorFn {
+	return o.sortedTagIteratorFn
+}
+
+func (o *cardinalityLimitOptions) SetNewOverflowIDFn(value id.NewIDFn) CardinalityLimitOptions {
+	opts := *o
+	opts.newOverflowIDFn = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) NewOverflowIDFn() id.NewIDFn {
+	return o.newOverflowIDFn
+}
+
+func (o *cardinalityLimitOptions) SetOverflowTagPair(value id.TagPair) CardinalityLimitOptions {
+	opts := *o
+	opts.overflowTagPair = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) OverflowTagPair() id.TagPair {
+	return o.overflowTagPair
+}
+
+func (o *cardinalityLimitOptions) SetMaxSeriesPerRollup(value int) CardinalityLimitOptions {
+	opts := *o
+	opts.maxSeriesPerRollup = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) MaxSeriesPerRollup() int {
+	return o.maxSeriesPerRollup
+}
+
+func (o *cardinalityLimitOptions) SetLimitTagName(value []byte) CardinalityLimitOptions {
+	opts := *o
+	opts.limitTagName = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) LimitTagName() []byte {
+	return o.limitTagName
+}
+
+func (o *cardinalityLimitOptions) SetMaxSeriesPerTagValue(value int) CardinalityLimitOptions {
+	opts := *o
+	opts.maxSeriesPerTagValue = value
+	return &opts
+}
+
+func (o *cardinalityLimitOptions) MaxSeriesPerTagValue() int {
+	return o.maxSeriesPerTagValue
+}
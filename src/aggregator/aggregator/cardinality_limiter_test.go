@@ -0,0 +1,130 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestCardinalityLimiterDisabled(t *testing.T) {
+	l := NewCardinalityLimiter(NewCardinalityLimitOptions())
+	for i := 0; i < 10; i++ {
+		reservation, overflowID := l.Acquire(nil, testRollupID("foo", "host", string(rune('a'+i))))
+		require.Nil(t, overflowID)
+		require.False(t, reservation.reserved)
+	}
+}
+
+func TestCardinalityLimiterMaxSeriesPerRollup(t *testing.T) {
+	l := NewCardinalityLimiter(NewCardinalityLimitOptions().SetMaxSeriesPerRollup(2))
+
+	r1, overflowID := l.Acquire(nil, testRollupID("foo", "host", "a"))
+	require.Nil(t, overflowID)
+	_, overflowID = l.Acquire(nil, testRollupID("foo", "host", "b"))
+	require.Nil(t, overflowID)
+
+	// The third series of the same rollup is collapsed into the overflow series.
+	_, overflowID = l.Acquire(nil, testRollupID("foo", "host", "c"))
+	expected := m3.NewRollupID([]byte("foo"), []id.TagPair{defaultCardinalityOverflowTagPair})
+	require.Equal(t, expected, overflowID)
+
+	// Other rollups are not affected.
+	_, overflowID = l.Acquire(nil, testRollupID("bar", "host", "c"))
+	require.Nil(t, overflowID)
+
+	// Releasing a series makes room for another one.
+	l.Release(r1)
+	_, overflowID = l.Acquire(nil, testRollupID("foo", "host", "c"))
+	require.Nil(t, overflowID)
+}
+
+func TestCardinalityLimiterMaxSeriesPerRollupRule(t *testing.T) {
+	s := tally.NewTestScope("", nil)
+	l := NewCardinalityLimiter(NewCardinalityLimitOptions().
+		SetMaxSeriesPerRollup(1).
+		SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(s)))
+
+	// Rollup rules producing the same rollup name are limited separately.
+	_, overflowID := l.Acquire([]byte("rule1"), testRollupID("foo", "host", "a"))
+	require.Nil(t, overflowID)
+	_, overflowID = l.Acquire([]byte("rule2"), testRollupID("foo", "host", "b"))
+	require.Nil(t, overflowID)
+
+	expected := m3.NewRollupID([]byte("foo"), []id.TagPair{defaultCardinalityOverflowTagPair})
+	for i := 0; i < 2; i++ {
+		_, overflowID = l.Acquire([]byte("rule1"), testRollupID("foo", "host", "c"))
+		require.Equal(t, expected, overflowID)
+	}
+
+	// The overflows are reported against the rollup rule.
+	counters := s.Snapshot().Counters()
+	c, exists := counters["overflowed+limit=rollup,rollup=rule1"]
+	require.True(t, exists)
+	require.Equal(t, int64(2), c.Value())
+	_, exists = counters["overflowed+limit=rollup,rollup=rule2"]
+	require.False(t, exists)
+}
+
+func TestCardinalityLimiterMaxSeriesPerTagValue(t *testing.T) {
+	l := NewCardinalityLimiter(NewCardinalityLimitOptions().
+		SetLimitTagName([]byte("service")).
+		SetMaxSeriesPerTagValue(1))
+
+	_, overflowID := l.Acquire(nil, testRollupID("foo", "service", "a"))
+	require.Nil(t, overflowID)
+
+	// The second series with the same tag value is collapsed into the overflow
+	// series for the tag value, across rollups.
+	_, overflowID = l.Acquire(nil, testRollupID("bar", "service", "a"))
+	expected := m3.NewRollupID([]byte("bar"), []id.TagPair{
+		{Name: []byte("service"), Value: []byte("a")},
+		defaultCardinalityOverflowTagPair,
+	})
+	require.Equal(t, expected, overflowID)
+
+	// Series with other tag values or without the tag are not affected.
+	_, overflowID = l.Acquire(nil, testRollupID("foo", "service", "b"))
+	require.Nil(t, overflowID)
+	_, overflowID = l.Acquire(nil, testRollupID("foo", "host", "a"))
+	require.Nil(t, overflowID)
+}
+
+func TestCardinalityLimiterInvalidID(t *testing.T) {
+	l := NewCardinalityLimiter(NewCardinalityLimitOptions().SetMaxSeriesPerRollup(1))
+	for i := 0; i < 2; i++ {
+		reservation, overflowID := l.Acquire(nil, []byte("invalid"))
+		require.Nil(t, overflowID)
+		require.False(t, reservation.reserved)
+	}
+}
+
+func testRollupID(name, tagName, tagValue string) []byte {
+	return m3.NewRollupID([]byte(name), []id.TagPair{
+		{Name: []byte(tagName), Value: []byte(tagValue)},
+	})
+}
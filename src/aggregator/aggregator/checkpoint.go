@@ -0,0 +1,809 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/metrics/generated/proto/pipelinepb"
+	"github.com/m3db/m3/src/metrics/generated/proto/policypb"
+	"github.com/m3db/m3/src/metrics/metric"
+	metricid "github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	checkpointFileFmt     = "shard-%d.checkpoint"
+	checkpointVersion     = 5
+	checkpointChecksumLen = 4
+)
+
+var (
+	checkpointMagic = []byte("M3AC")
+
+	errCheckpointTooShort         = errors.New("checkpoint is too short")
+	errCheckpointChecksumMismatch = errors.New("checkpoint checksum mismatch")
+	errCheckpointInvalidMagic     = errors.New("checkpoint has invalid magic number")
+	errCheckpointInvalidLength    = errors.New("checkpoint has invalid length")
+)
+
+// elemSnapshot is a point-in-time copy of the aggregations in an element
+// that have not been consumed yet and of its sliding window.
+type elemSnapshot struct {
+	values              []timedAggregationSnapshot
+	windowed            []timedAggregationSnapshot
+	nextStepAtNanos     int64
+	lastConsumedAtNanos int64
+	lastConsumedValues  []float64
+}
+
+type timedAggregationSnapshot struct {
+	startAtNanos           int64
+	sourcesSeen            []uint64
+	overflowSourcesSeen    []uint64
+	numOverflowSourcesSeen int
+	aggregation            aggregationSnapshot
+}
+
+// entrySnapshot is a point-in-time copy of the aggregations in an entry.
+type entrySnapshot struct {
+	hasDefaultMetadatas bool
+	cutoverNanos        int64
+	aggregations        []aggregationSnapshotWithKey
+}
+
+type aggregationSnapshotWithKey struct {
+	key  aggregationKey
+	elem elemSnapshot
+}
+
+type flushingMetricListFn func(list flushingMetricList)
+
+type entrySnapshotFn func(
+	category metricCategory,
+	metricType metric.Type,
+	id metricid.RawID,
+	snapshot entrySnapshot,
+)
+
+// listIDFor returns the id of the list storing the aggregations for the given
+// metric category and aggregation key.
+func listIDFor(category metricCategory, key aggregationKey) (metricListID, error) {
+	resolution := key.storagePolicy.Resolution().Window
+	switch category {
+	case untimedMetric:
+		return standardMetricListID{resolution: resolution}.toMetricListID(), nil
+	case forwardedMetric:
+		return forwardedMetricListID{
+			resolution:        resolution,
+			numForwardedTimes: key.numForwardedTimes,
+		}.toMetricListID(), nil
+	case timedMetric:
+		return timedMetricListID{resolution: resolution}.toMetricListID(), nil
+	default:
+		return metricListID{}, fmt.Errorf("unknown metric category: %v", category)
+	}
+}
+
+// lastFlushedNanosFor returns the last flushed time persisted in kv for the
+// given list, and false if there is none.
+func lastFlushedNanosFor(
+	shardFlushTimes *schema.ShardFlushTimes,
+	listID metricListID,
+) (int64, bool) {
+	if shardFlushTimes == nil {
+		return 0, false
+	}
+	var (
+		lastFlushedNanos int64
+		exists           bool
+	)
+	switch listID.listType {
+	case standardMetricListType:
+		lastFlushedNanos, exists = shardFlushTimes.StandardByResolution[int64(listID.standard.resolution)]
+	case timedMetricListType:
+		lastFlushedNanos, exists = shardFlushTimes.TimedByResolution[int64(listID.timed.resolution)]
+	case forwardedMetricListType:
+		flushTimesForResolution := shardFlushTimes.ForwardedByResolution[int64(listID.forwarded.resolution)]
+		if flushTimesForResolution == nil {
+			return 0, false
+		}
+		lastFlushedNanos, exists = flushTimesForResolution.ByNumForwardedTimes[int32(listID.forwarded.numForwardedTimes)]
+	}
+	return lastFlushedNanos, exists
+}
+
+type checkpointerMetrics struct {
+	write            tally.Counter
+	writeErrors      tally.Counter
+	writeLatency     tally.Timer
+	entriesWritten   tally.Counter
+	restore          tally.Counter
+	restoreErrors    tally.Counter
+	restoreNotFound  tally.Counter
+	restoreTooOld    tally.Counter
+	entriesRestored  tally.Counter
+	entryErrors      tally.Counter
+	listsDiscarded   tally.Counter
+	listsNotFlushed  tally.Counter
+	restoreLatency   tally.Timer
+	checkpointLength tally.Gauge
+}
+
+func newCheckpointerMetrics(scope tally.Scope) checkpointerMetrics {
+	writeScope := scope.SubScope("write")
+	restoreScope := scope.SubScope("restore")
+	return checkpointerMetrics{
+		write:            writeScope.Counter("success"),
+		writeErrors:      writeScope.Counter("errors"),
+		writeLatency:     writeScope.Timer("latency"),
+		entriesWritten:   writeScope.Counter("entries"),
+		restore:          restoreScope.Counter("success"),
+		restoreErrors:    restoreScope.Counter("errors"),
+		restoreNotFound:  restoreScope.Counter("not-found"),
+		restoreTooOld:    restoreScope.Counter("too-old"),
+		entriesRestored:  restoreScope.Counter("entries"),
+		entryErrors:      restoreScope.Counter("entry-errors"),
+		listsDiscarded:   restoreScope.Counter("lists-discarded"),
+		listsNotFlushed:  restoreScope.Counter("lists-not-flushed"),
+		restoreLatency:   restoreScope.Timer("latency"),
+		checkpointLength: writeScope.Gauge("bytes"),
+	}
+}
+
+// checkpointer writes the in-flight aggregation state of a shard to a file
+// on local disk, and restores it from the file when the aggregator starts.
+type checkpointer struct {
+	sync.Mutex
+
+	directory string
+	maxAge    time.Duration
+	nowFn     clock.NowFn
+	logger    *zap.Logger
+	metrics   checkpointerMetrics
+}
+
+func newCheckpointer(opts CheckpointOptions) *checkpointer {
+	iOpts := opts.InstrumentOptions()
+	return &checkpointer{
+		directory: opts.Directory(),
+		maxAge:    opts.MaxAge(),
+		nowFn:     opts.ClockOptions().NowFn(),
+		logger:    iOpts.Logger(),
+		metrics:   newCheckpointerMetrics(iOpts.MetricsScope()),
+	}
+}
+
+// Write atomically replaces the checkpoint of the given shard with a snapshot
+// of the entries in the metric map.
+func (c *checkpointer) Write(shard uint32, m *metricMap) error {
+	c.Lock()
+	defer c.Unlock()
+
+	start := c.nowFn()
+	n, err := c.write(shard, m, start)
+	if err != nil {
+		c.metrics.writeErrors.Inc(1)
+		return err
+	}
+	c.metrics.write.Inc(1)
+	c.metrics.checkpointLength.Update(float64(n))
+	c.metrics.writeLatency.Record(c.nowFn().Sub(start))
+	return nil
+}
+
+func (c *checkpointer) write(shard uint32, m *metricMap, now time.Time) (int64, error) {
+	if err := os.MkdirAll(c.directory, 0755); err != nil {
+		return 0, err
+	}
+	fileName := fmt.Sprintf(checkpointFileFmt, shard)
+	f, err := ioutil.TempFile(c.directory, fileName+".tmp")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := f.Name()
+	n, err := c.writeTo(f, shard, m, now)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, filepath.Join(c.directory, fileName))
+	}
+	if err != nil {
+		os.Remove(tmpPath) // nolint: errcheck
+		return 0, err
+	}
+	return n, nil
+}
+
+func (c *checkpointer) writeTo(w io.Writer, shard uint32, m *metricMap, now time.Time) (int64, error) {
+	var (
+		checksum   = crc32.NewIEEE()
+		counter    = &countingWriter{w: io.MultiWriter(w, checksum)}
+		buffered   = bufio.NewWriter(counter)
+		enc        = newCheckpointEncoder(buffered)
+		numEntries int64
+	)
+	enc.writeRaw(checkpointMagic)
+	enc.writeUvarint(checkpointVersion)
+	enc.writeUvarint(uint64(shard))
+	enc.writeVarint(now.UnixNano())
+	m.ForEachSnapshot(func(
+		category metricCategory,
+		metricType metric.Type,
+		id metricid.RawID,
+		snapshot entrySnapshot,
+	) {
+		enc.writeBool(true)
+		enc.writeEntry(category, metricType, id, snapshot)
+		numEntries++
+	})
+	enc.writeBool(false)
+	if enc.err != nil {
+		return 0, enc.err
+	}
+	if err := buffered.Flush(); err != nil {
+		return 0, err
+	}
+	var checksumBytes [checkpointChecksumLen]byte
+	binary.BigEndian.PutUint32(checksumBytes[:], checksum.Sum32())
+	if _, err := w.Write(checksumBytes[:]); err != nil {
+		return 0, err
+	}
+	c.metrics.entriesWritten.Inc(numEntries)
+	return counter.n + checkpointChecksumLen, nil
+}
+
+// Restore restores the entries in the checkpoint of the given shard into the
+// metric map, and discards the aggregations that have already been flushed
+// based on the flush times persisted in kv so they are not flushed again.
+func (c *checkpointer) Restore(
+	shard uint32,
+	m *metricMap,
+	flushTimes *schema.ShardSetFlushTimes,
+) error {
+	start := c.nowFn()
+	data, err := ioutil.ReadFile(filepath.Join(c.directory, fmt.Sprintf(checkpointFileFmt, shard)))
+	if os.IsNotExist(err) {
+		c.metrics.restoreNotFound.Inc(1)
+		return nil
+	}
+	if err != nil {
+		c.metrics.restoreErrors.Inc(1)
+		return err
+	}
+	if err := c.restoreFrom(data, shard, m, start); err != nil {
+		c.metrics.restoreErrors.Inc(1)
+		return err
+	}
+
+	var shardFlushTimes *schema.ShardFlushTimes
+	if flushTimes != nil {
+		shardFlushTimes = flushTimes.ByShard[shard]
+	}
+	m.ForEachFlushingList(func(list flushingMetricList) {
+		lastFlushedNanos, exists := lastFlushedNanosFor(shardFlushTimes, list.ID())
+		if !exists {
+			c.metrics.listsNotFlushed.Inc(1)
+			return
+		}
+		list.DiscardBefore(lastFlushedNanos)
+		c.metrics.listsDiscarded.Inc(1)
+	})
+	c.metrics.restore.Inc(1)
+	c.metrics.restoreLatency.Record(c.nowFn().Sub(start))
+	return nil
+}
+
+func (c *checkpointer) restoreFrom(data []byte, shard uint32, m *metricMap, now time.Time) error {
+	if len(data) < len(checkpointMagic)+checkpointChecksumLen {
+		return errCheckpointTooShort
+	}
+	payload := data[:len(data)-checkpointChecksumLen]
+	expected := binary.BigEndian.Uint32(data[len(payload):])
+	if crc32.ChecksumIEEE(payload) != expected {
+		return errCheckpointChecksumMismatch
+	}
+	dec := newCheckpointDecoder(payload)
+	if !bytes.Equal(dec.readRaw(len(checkpointMagic)), checkpointMagic) {
+		return errCheckpointInvalidMagic
+	}
+	version := dec.readUvarint()
+	checkpointShard := dec.readUvarint()
+	createdAtNanos := dec.readVarint()
+	if dec.err != nil {
+		return dec.err
+	}
+	if version != checkpointVersion {
+		return fmt.Errorf("unknown checkpoint version %d", version)
+	}
+	if checkpointShard != uint64(shard) {
+		return fmt.Errorf("checkpoint is for shard %d instead of shard %d", checkpointShard, shard)
+	}
+	if age := now.Sub(time.Unix(0, createdAtNanos)); age > c.maxAge {
+		c.metrics.restoreTooOld.Inc(1)
+		c.logger.Info("skipping checkpoint older than max age",
+			zap.Uint32("shard", shard),
+			zap.Duration("age", age),
+			zap.Duration("maxAge", c.maxAge),
+		)
+		return nil
+	}
+
+	var numEntries int64
+	for dec.readBool() {
+		category, metricType, id, snapshot := dec.readEntry()
+		if dec.err != nil {
+			return dec.err
+		}
+		if err := m.Restore(category, metricType, id, snapshot); err != nil {
+			c.metrics.entryErrors.Inc(1)
+			c.logger.Error("error restoring entry from checkpoint",
+				zap.Uint32("shard", shard),
+				zap.Stringer("id", id),
+				zap.Error(err),
+			)
+			continue
+		}
+		numEntries++
+	}
+	if dec.err != nil {
+		return dec.err
+	}
+	c.metrics.entriesRestored.Inc(numEntries)
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// checkpointEncoder encodes snapshots in the checkpoint format. The first
+// error encountered is retained and all subsequent writes become no-ops.
+type checkpointEncoder struct {
+	w   io.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func newCheckpointEncoder(w io.Writer) *checkpointEncoder {
+	return &checkpointEncoder{w: w}
+}
+
+func (enc *checkpointEncoder) writeRaw(b []byte) {
+	if enc.err != nil {
+		return
+	}
+	_, enc.err = enc.w.Write(b)
+}
+
+func (enc *checkpointEncoder) writeUvarint(v uint64) {
+	n := binary.PutUvarint(enc.buf[:], v)
+	enc.writeRaw(enc.buf[:n])
+}
+
+func (enc *checkpointEncoder) writeVarint(v int64) {
+	n := binary.PutVarint(enc.buf[:], v)
+	enc.writeRaw(enc.buf[:n])
+}
+
+func (enc *checkpointEncoder) writeFloat64(v float64) {
+	binary.BigEndian.PutUint64(enc.buf[:8], math.Float64bits(v))
+	enc.writeRaw(enc.buf[:8])
+}
+
+func (enc *checkpointEncoder) writeBool(v bool) {
+	if v {
+		enc.writeUvarint(1)
+	} else {
+		enc.writeUvarint(0)
+	}
+}
+
+func (enc *checkpointEncoder) writeBytes(b []byte) {
+	enc.writeUvarint(uint64(len(b)))
+	enc.writeRaw(b)
+}
+
+func (enc *checkpointEncoder) writeEntry(
+	category metricCategory,
+	metricType metric.Type,
+	id metricid.RawID,
+	snapshot entrySnapshot,
+) {
+	enc.writeUvarint(uint64(category))
+	enc.writeUvarint(uint64(metricType))
+	enc.writeBytes(id)
+	enc.writeBool(snapshot.hasDefaultMetadatas)
+	enc.writeVarint(snapshot.cutoverNanos)
+	enc.writeUvarint(uint64(len(snapshot.aggregations)))
+	for _, agg := range snapshot.aggregations {
+		enc.writeAggregationKey(agg.key)
+		enc.writeElem(metricType, agg.elem)
+	}
+}
+
+func (enc *checkpointEncoder) writeAggregationKey(key aggregationKey) {
+	for _, v := range key.aggregationID {
+		enc.writeUvarint(v)
+	}
+	if enc.err != nil {
+		return
+	}
+	var spPB policypb.StoragePolicy
+	if enc.err = key.storagePolicy.ToProto(&spPB); enc.err != nil {
+		return
+	}
+	spBytes, err := spPB.Marshal()
+	if err != nil {
+		enc.err = err
+		return
+	}
+	enc.writeBytes(spBytes)
+	var pipelinePB pipelinepb.AppliedPipeline
+	if enc.err = key.pipeline.ToProto(&pipelinePB); enc.err != nil {
+		return
+	}
+	pipelineBytes, err := pipelinePB.Marshal()
+	if err != nil {
+		enc.err = err
+		return
+	}
+	enc.writeBytes(pipelineBytes)
+	enc.writeUvarint(uint64(key.numForwardedTimes))
+	enc.writeUvarint(uint64(key.idPrefixSuffixType))
+}
+
+func (enc *checkpointEncoder) writeElem(metricType metric.Type, snapshot elemSnapshot) {
+	enc.writeVarint(snapshot.lastConsumedAtNanos)
+	enc.writeUvarint(uint64(len(snapshot.lastConsumedValues)))
+	for _, v := range snapshot.lastConsumedValues {
+		enc.writeFloat64(v)
+	}
+	enc.writeTimedAggregations(metricType, snapshot.values)
+	enc.writeTimedAggregations(metricType, snapshot.windowed)
+	enc.writeVarint(snapshot.nextStepAtNanos)
+}
+
+func (enc *checkpointEncoder) writeTimedAggregations(
+	metricType metric.Type,
+	values []timedAggregationSnapshot,
+) {
+	enc.writeUvarint(uint64(len(values)))
+	for _, v := range values {
+		enc.writeVarint(v.startAtNanos)
+		enc.writeBool(v.sourcesSeen != nil)
+		if v.sourcesSeen != nil {
+			enc.writeUvarint(uint64(len(v.sourcesSeen)))
+			for _, word := range v.sourcesSeen {
+				enc.writeUvarint(word)
+			}
+		}
+		enc.writeBool(v.overflowSourcesSeen != nil)
+		if v.overflowSourcesSeen != nil {
+			enc.writeUvarint(uint64(v.numOverflowSourcesSeen))
+			enc.writeUvarint(uint64(len(v.overflowSourcesSeen)))
+			for _, word := range v.overflowSourcesSeen {
+				enc.writeUvarint(word)
+			}
+		}
+		enc.writeAggregation(metricType, v.aggregation)
+	}
+}
+
+func (enc *checkpointEncoder) writeAggregation(metricType metric.Type, snapshot aggregationSnapshot) {
+	switch metricType {
+	case metric.CounterType:
+		c := snapshot.counter
+		enc.writeVarint(c.Sum)
+		enc.writeVarint(c.SumSq)
+		enc.writeVarint(c.Count)
+		enc.writeVarint(c.Max)
+		enc.writeVarint(c.Min)
+		enc.writeBytes(c.CountDistinctRegisters)
+	case metric.TimerType:
+		t := snapshot.timer
+		enc.writeVarint(t.Count)
+		enc.writeFloat64(t.Sum)
+		enc.writeFloat64(t.SumSq)
+		enc.writeVarint(t.Stream.NumValues)
+		enc.writeUvarint(uint64(len(t.Stream.Samples)))
+		for _, s := range t.Stream.Samples {
+			enc.writeFloat64(s.Value)
+			enc.writeVarint(s.NumRanks)
+			enc.writeVarint(s.Delta)
+		}
+		enc.writeBytes(t.CountDistinctRegisters)
+	case metric.GaugeType:
+		g := snapshot.gauge
+		enc.writeFloat64(g.Last)
+		enc.writeFloat64(g.Sum)
+		enc.writeFloat64(g.SumSq)
+		enc.writeVarint(g.Count)
+		enc.writeFloat64(g.Max)
+		enc.writeFloat64(g.Min)
+		enc.writeBytes(g.CountDistinctRegisters)
+	default:
+		if enc.err == nil {
+			enc.err = errInvalidMetricType
+		}
+	}
+}
+
+// checkpointDecoder decodes snapshots encoded by the checkpoint encoder. The
+// first error encountered is retained and all subsequent reads return zero values.
+type checkpointDecoder struct {
+	r   *bytes.Reader
+	err error
+}
+
+func newCheckpointDecoder(data []byte) *checkpointDecoder {
+	return &checkpointDecoder{r: bytes.NewReader(data)}
+}
+
+func (dec *checkpointDecoder) readRaw(n int) []byte {
+	if dec.err != nil {
+		return nil
+	}
+	if n < 0 || n > dec.r.Len() {
+		dec.err = errCheckpointInvalidLength
+		return nil
+	}
+	b := make([]byte, n)
+	_, dec.err = io.ReadFull(dec.r, b)
+	return b
+}
+
+func (dec *checkpointDecoder) readUvarint() uint64 {
+	if dec.err != nil {
+		return 0
+	}
+	var v uint64
+	v, dec.err = binary.ReadUvarint(dec.r)
+	return v
+}
+
+func (dec *checkpointDecoder) readVarint() int64 {
+	if dec.err != nil {
+		return 0
+	}
+	var v int64
+	v, dec.err = binary.ReadVarint(dec.r)
+	return v
+}
+
+func (dec *checkpointDecoder) readFloat64() float64 {
+	b := dec.readRaw(8)
+	if dec.err != nil {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+func (dec *checkpointDecoder) readBool() bool {
+	return dec.readUvarint() == 1
+}
+
+// readLen reads a length, which is bounded by the number of remaining bytes
+// to avoid large allocations when decoding a malformed checkpoint.
+func (dec *checkpointDecoder) readLen() int {
+	n := dec.readUvarint()
+	if dec.err != nil {
+		return 0
+	}
+	if n > uint64(dec.r.Len()) {
+		dec.err = errCheckpointInvalidLength
+		return 0
+	}
+	return int(n)
+}
+
+func (dec *checkpointDecoder) readBytes() []byte {
+	return dec.readRaw(dec.readLen())
+}
+
+func (dec *checkpointDecoder) readEntry() (metricCategory, metric.Type, metricid.RawID, entrySnapshot) {
+	var (
+		category   = metricCategory(dec.readUvarint())
+		metricType = metric.Type(dec.readUvarint())
+		id         = metricid.RawID(dec.readBytes())
+		snapshot   = entrySnapshot{
+			hasDefaultMetadatas: dec.readBool(),
+			cutoverNanos:        dec.readVarint(),
+		}
+		numAggregations = dec.readLen()
+	)
+	if dec.err != nil {
+		return 0, 0, nil, entrySnapshot{}
+	}
+	snapshot.aggregations = make([]aggregationSnapshotWithKey, 0, numAggregations)
+	for i := 0; i < numAggregations; i++ {
+		key := dec.readAggregationKey()
+		elem := dec.readElem(metricType)
+		if dec.err != nil {
+			return 0, 0, nil, entrySnapshot{}
+		}
+		snapshot.aggregations = append(snapshot.aggregations, aggregationSnapshotWithKey{
+			key:  key,
+			elem: elem,
+		})
+	}
+	return category, metricType, id, snapshot
+}
+
+func (dec *checkpointDecoder) readAggregationKey() aggregationKey {
+	var key aggregationKey
+	for i := range key.aggregationID {
+		key.aggregationID[i] = dec.readUvarint()
+	}
+	spBytes := dec.readBytes()
+	pipelineBytes := dec.readBytes()
+	key.numForwardedTimes = int(dec.readUvarint())
+	key.idPrefixSuffixType = IDPrefixSuffixType(dec.readUvarint())
+	if dec.err != nil {
+		return aggregationKey{}
+	}
+	var spPB policypb.StoragePolicy
+	if dec.err = spPB.Unmarshal(spBytes); dec.err != nil {
+		return aggregationKey{}
+	}
+	if dec.err = key.storagePolicy.FromProto(spPB); dec.err != nil {
+		return aggregationKey{}
+	}
+	var pipelinePB pipelinepb.AppliedPipeline
+	if dec.err = pipelinePB.Unmarshal(pipelineBytes); dec.err != nil {
+		return aggregationKey{}
+	}
+	if dec.err = key.pipeline.FromProto(pipelinePB); dec.err != nil {
+		return aggregationKey{}
+	}
+	return key
+}
+
+func (dec *checkpointDecoder) readElem(metricType metric.Type) elemSnapshot {
+	var snapshot elemSnapshot
+	snapshot.lastConsumedAtNanos = dec.readVarint()
+	if n := dec.readLen(); n > 0 {
+		snapshot.lastConsumedValues = make([]float64, n)
+		for i := range snapshot.lastConsumedValues {
+			snapshot.lastConsumedValues[i] = dec.readFloat64()
+		}
+	}
+	snapshot.values = dec.readTimedAggregations(metricType)
+	snapshot.windowed = dec.readTimedAggregations(metricType)
+	snapshot.nextStepAtNanos = dec.readVarint()
+	if dec.err != nil {
+		return elemSnapshot{}
+	}
+	return snapshot
+}
+
+func (dec *checkpointDecoder) readTimedAggregations(metricType metric.Type) []timedAggregationSnapshot {
+	numValues := dec.readLen()
+	if dec.err != nil {
+		return nil
+	}
+	values := make([]timedAggregationSnapshot, 0, numValues)
+	for i := 0; i < numValues; i++ {
+		v := timedAggregationSnapshot{startAtNanos: dec.readVarint()}
+		if dec.readBool() {
+			v.sourcesSeen = make([]uint64, dec.readLen())
+			for j := range v.sourcesSeen {
+				v.sourcesSeen[j] = dec.readUvarint()
+			}
+		}
+		if dec.readBool() {
+			v.numOverflowSourcesSeen = int(dec.readUvarint())
+			v.overflowSourcesSeen = make([]uint64, dec.readLen())
+			for j := range v.overflowSourcesSeen {
+				v.overflowSourcesSeen[j] = dec.readUvarint()
+			}
+		}
+		v.aggregation = dec.readAggregation(metricType)
+		if dec.err != nil {
+			return nil
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func (dec *checkpointDecoder) readAggregation(metricType metric.Type) aggregationSnapshot {
+	var snapshot aggregationSnapshot
+	switch metricType {
+	case metric.CounterType:
+		snapshot.counter = raggregation.CounterSnapshot{
+			Sum:   dec.readVarint(),
+			SumSq: dec.readVarint(),
+			Count: dec.readVarint(),
+			Max:   dec.readVarint(),
+			Min:   dec.readVarint(),
+
+			CountDistinctRegisters: dec.readBytes(),
+		}
+	case metric.TimerType:
+		snapshot.timer = raggregation.TimerSnapshot{
+			Count: dec.readVarint(),
+			Sum:   dec.readFloat64(),
+			SumSq: dec.readFloat64(),
+			Stream: cm.StreamSnapshot{
+				NumValues: dec.readVarint(),
+			},
+		}
+		numSamples := dec.readLen()
+		if dec.err != nil {
+			return aggregationSnapshot{}
+		}
+		snapshot.timer.Stream.Samples = make([]cm.SampleSnapshot, numSamples)
+		for i := range snapshot.timer.Stream.Samples {
+			snapshot.timer.Stream.Samples[i] = cm.SampleSnapshot{
+				Value:    dec.readFloat64(),
+				NumRanks: dec.readVarint(),
+				Delta:    dec.readVarint(),
+			}
+		}
+		snapshot.timer.CountDistinctRegisters = dec.readBytes()
+	case metric.GaugeType:
+		snapshot.gauge = raggregation.GaugeSnapshot{
+			Last:  dec.readFloat64(),
+			Sum:   dec.readFloat64(),
+			SumSq: dec.readFloat64(),
+			Count: dec.readVarint(),
+			Max:   dec.readFloat64(),
+			Min:   dec.readFloat64(),
+
+			CountDistinctRegisters: dec.readBytes(),
+		}
+	default:
+		if dec.err == nil {
+			dec.err = errInvalidMetricType
+		}
+	}
+	return snapshot
+}
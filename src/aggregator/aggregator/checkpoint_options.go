@@ -0,0 +1,152 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultCheckpointInterval = time.Minute
+	defaultCheckpointMaxAge   = 10 * time.Minute
+)
+
+// CheckpointOptions provide a set of options for checkpointing the in-flight
+// aggregation state of the shards owned by the aggregator.
+type CheckpointOptions interface {
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) CheckpointOptions
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) CheckpointOptions
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetEnabled sets whether checkpointing is enabled.
+	SetEnabled(value bool) CheckpointOptions
+
+	// Enabled returns whether checkpointing is enabled.
+	Enabled() bool
+
+	// SetDirectory sets the directory the checkpoint files are written to.
+	SetDirectory(value string) CheckpointOptions
+
+	// Directory returns the directory the checkpoint files are written to.
+	Directory() string
+
+	// SetInterval sets the interval between checkpoints.
+	SetInterval(value time.Duration) CheckpointOptions
+
+	// Interval returns the interval between checkpoints.
+	Interval() time.Duration
+
+	// SetMaxAge sets the maximum age of a checkpoint that is restored on startup.
+	SetMaxAge(value time.Duration) CheckpointOptions
+
+	// MaxAge returns the maximum age of a checkpoint that is restored on startup.
+	MaxAge() time.Duration
+}
+
+type checkpointOptions struct {
+	clockOpts      clock.Options
+	instrumentOpts instrument.Options
+	enabled        bool
+	directory      string
+	interval       time.Duration
+	maxAge         time.Duration
+}
+
+// NewCheckpointOptions create a new set of checkpoint options.
+func NewCheckpointOptions() CheckpointOptions {
+	return &checkpointOptions{
+		clockOpts:      clock.NewOptions(),
+		instrumentOpts: instrument.NewOptions(),
+		interval:       defaultCheckpointInterval,
+		maxAge:         defaultCheckpointMaxAge,
+	}
+}
+
+func (o *checkpointOptions) SetClockOptions(value clock.Options) CheckpointOptions {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *checkpointOptions) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *checkpointOptions) SetInstrumentOptions(value instrument.Options) CheckpointOptions {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *checkpointOptions) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *checkpointOptions) SetEnabled(value bool) CheckpointOptions {
+	opts := *o
+	opts.enabled = value
+	return &opts
+}
+
+func (o *checkpointOptions) Enabled() bool {
+	return o.enabled
+}
+
+func (o *checkpointOptions) SetDirectory(value string) CheckpointOptions {
+	opts := *o
+	opts.directory = value
+	return &opts
+}
+
+func (o *checkpointOptions) Directory() string {
+	return o.directory
+}
+
+func (o *checkpointOptions) SetInterval(value time.Duration) CheckpointOptions {
+	opts := *o
+	opts.interval = value
+	return &opts
+}
+
+func (o *checkpointOptions) Interval() time.Duration {
+	return o.interval
+}
+
+func (o *checkpointOptions) SetMaxAge(value time.Duration) CheckpointOptions {
+	opts := *o
+	opts.maxAge = value
+	return &opts
+}
+
+func (o *checkpointOptions) MaxAge() time.Duration {
+	return o.maxAge
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointerWriteRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	require.NoError(t, m.AddUntimed(testCounter, testCustomStagedMetadatas))
+	require.NoError(t, m.AddUntimed(testBatchTimer, testCustomStagedMetadatas))
+	require.NoError(t, m.AddUntimed(testGauge, testCustomStagedMetadatas))
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	require.NoError(t, c.Write(testShard, m))
+
+	restored := newMetricMap(testShard, opts)
+	require.NoError(t, c.Restore(testShard, restored, nil))
+	expected := testEntrySnapshots(m)
+	require.Equal(t, 3, len(expected))
+	require.Equal(t, expected, testEntrySnapshots(restored))
+}
+
+func TestCheckpointerWriteRestoreOverflowSources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	limiter := NewCardinalityLimiter(NewCardinalityLimitOptions().SetMaxSeriesPerRollup(1))
+	opts := testOptions(ctrl).SetCardinalityLimiter(limiter)
+	m := newMetricMap(testShard, opts)
+	for _, host := range []string{"a", "b", "c"} {
+		require.NoError(t, m.AddForwarded(aggregated.ForwardedMetric{
+			Type:      metric.CounterType,
+			ID:        testRollupID("foo", "host", host),
+			TimeNanos: 12345,
+			Values:    []float64{1},
+		}, testForwardMetadata))
+	}
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	require.NoError(t, c.Write(testShard, m))
+
+	restored := newMetricMap(testShard, opts)
+	require.NoError(t, c.Restore(testShard, restored, nil))
+	expected := testEntrySnapshots(m)
+	require.Equal(t, 2, len(expected))
+	require.Equal(t, expected, testEntrySnapshots(restored))
+
+	var numOverflowSources int
+	for _, snapshot := range expected {
+		for _, agg := range snapshot.aggregations {
+			for _, v := range agg.elem.values {
+				numOverflowSources += v.numOverflowSourcesSeen
+			}
+		}
+	}
+	require.Equal(t, 2, numOverflowSources)
+}
+
+func TestCheckpointerRestoreDiscardsFlushed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	require.NoError(t, m.AddUntimed(testCounter, testCustomStagedMetadatas))
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	require.NoError(t, c.Write(testShard, m))
+
+	// Everything in the 10s resolution list has been flushed already.
+	flushTimes := &schema.ShardSetFlushTimes{
+		ByShard: map[uint32]*schema.ShardFlushTimes{
+			testShard: &schema.ShardFlushTimes{
+				StandardByResolution: map[int64]int64{
+					int64(10 * time.Second): time.Now().Add(time.Hour).UnixNano(),
+				},
+			},
+		},
+	}
+	restored := newMetricMap(testShard, opts)
+	require.NoError(t, c.Restore(testShard, restored, flushTimes))
+
+	snapshots := testEntrySnapshots(restored)
+	require.Equal(t, 1, len(snapshots))
+	for _, snapshot := range snapshots {
+		require.Equal(t, 3, len(snapshot.aggregations))
+		for _, agg := range snapshot.aggregations {
+			if agg.key.storagePolicy.Resolution().Window == 10*time.Second {
+				require.Equal(t, 0, len(agg.elem.values))
+			} else {
+				require.Equal(t, 1, len(agg.elem.values))
+			}
+		}
+	}
+}
+
+func TestCheckpointerRestoreNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	m := newMetricMap(testShard, testOptions(ctrl))
+	require.NoError(t, c.Restore(testShard, m, nil))
+	require.Equal(t, 0, len(m.entries))
+}
+
+func TestCheckpointerRestoreTooOld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	require.NoError(t, m.AddUntimed(testCounter, testCustomStagedMetadatas))
+
+	checkpointOpts := NewCheckpointOptions().SetDirectory(dir)
+	require.NoError(t, newCheckpointer(checkpointOpts).Write(testShard, m))
+
+	now := time.Now().Add(checkpointOpts.MaxAge() + time.Minute)
+	checkpointOpts = checkpointOpts.SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+	restored := newMetricMap(testShard, opts)
+	require.NoError(t, newCheckpointer(checkpointOpts).Restore(testShard, restored, nil))
+	require.Equal(t, 0, len(restored.entries))
+}
+
+func TestCheckpointerRestoreChecksumMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	require.NoError(t, m.AddUntimed(testCounter, testCustomStagedMetadatas))
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	require.NoError(t, c.Write(testShard, m))
+
+	// Corrupt the checkpoint.
+	path := filepath.Join(dir, fmt.Sprintf(checkpointFileFmt, testShard))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	data[len(checkpointMagic)] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+	restored := newMetricMap(testShard, opts)
+	require.Equal(t, errCheckpointChecksumMismatch, c.Restore(testShard, restored, nil))
+	require.Equal(t, 0, len(restored.entries))
+}
+
+func TestCheckpointerRestoreWrongShard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testCheckpointDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	require.NoError(t, m.AddUntimed(testCounter, testCustomStagedMetadatas))
+
+	c := newCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	require.NoError(t, c.Write(testShard, m))
+
+	// Make the checkpoint of one shard look like the checkpoint of another.
+	otherShard := testShard + 1
+	require.NoError(t, os.Rename(
+		filepath.Join(dir, fmt.Sprintf(checkpointFileFmt, testShard)),
+		filepath.Join(dir, fmt.Sprintf(checkpointFileFmt, otherShard)),
+	))
+	restored := newMetricMap(otherShard, opts)
+	require.Error(t, c.Restore(otherShard, restored, nil))
+	require.Equal(t, 0, len(restored.entries))
+}
+
+func testCheckpointDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	return dir
+}
+
+func testEntrySnapshots(m *metricMap) map[string]entrySnapshot {
+	snapshots := make(map[string]entrySnapshot)
+	m.ForEachSnapshot(func(
+		category metricCategory,
+		metricType metric.Type,
+		id id.RawID,
+		snapshot entrySnapshot,
+	) {
+		snapshots[fmt.Sprintf("%d-%d-%s", category, metricType, id)] = snapshot
+	})
+	return snapshots
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/aggregator/hash"
 	maggregation "github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/id"
@@ -61,6 +62,64 @@ var (
 	errDuplicateForwardingSource = errors.New("duplicate forwarding source")
 )
 
+// overflowSource identifies the source and the original series of values that
+// were collapsed into an overflow series, since the overflow series receives
+// the values of many series from each source.
+type overflowSource struct {
+	sourceID uint32
+	idHash   hash.Hash128
+}
+
+const (
+	// overflowSourceSetBitsPerSource and overflowSourceSetNumHashes keep the
+	// false positive rate of an overflow source set below 1% up to its capacity.
+	overflowSourceSetBitsPerSource = 10
+	overflowSourceSetNumHashes     = 7
+)
+
+// overflowSourceSet is a fixed size bloom filter of the overflow sources whose
+// values were added to an aggregation, so that the memory used to deduplicate
+// the values of an overflow series does not grow with the number of series
+// collapsed into it. Up to its capacity, the values of a source that was not
+// seen are wrongly discarded less than 1% of the time. Beyond its capacity the
+// values of any source are added without being deduplicated, since discarding
+// values would otherwise become increasingly likely.
+type overflowSourceSet struct {
+	bits     *bitset.BitSet
+	numAdded int
+}
+
+func newOverflowSourceSet(capacity int) *overflowSourceSet {
+	// NB: the number of bits is a multiple of the word size so the set has the
+	// same size once restored from its words.
+	numWords := (capacity*overflowSourceSetBitsPerSource + 63) / 64
+	return &overflowSourceSet{bits: bitset.New(uint(numWords * 64))}
+}
+
+// TestAndAdd adds a source to the set and returns whether it was seen before.
+func (s *overflowSourceSet) TestAndAdd(source overflowSource) bool {
+	numBits := uint64(s.bits.Len())
+	if numBits == 0 || s.numAdded >= int(numBits/overflowSourceSetBitsPerSource) {
+		return false
+	}
+	var (
+		h1   = source.idHash[0] ^ uint64(source.sourceID)*0x9e3779b97f4a7c15
+		h2   = source.idHash[1] | 1
+		seen = true
+	)
+	for i := uint64(0); i < overflowSourceSetNumHashes; i++ {
+		idx := uint((h1 + i*h2) % numBits)
+		if !s.bits.Test(idx) {
+			seen = false
+			s.bits.Set(idx)
+		}
+	}
+	if !seen {
+		s.numAdded++
+	}
+	return seen
+}
+
 // isEarlierThanFn determines whether the timestamps of the metrics in a given
 // aggregation window are earlier than the given target time.
 type isEarlierThanFn func(windowStartNanos int64, resolution time.Duration, targetNanos int64) bool
@@ -113,19 +172,35 @@ type metricElem interface {
 	// metrics for elements producing such forwarded metrics.
 	SetForwardedCallbacks(
 		writeFn writeForwardedMetricFn,
+		writeSketchFn writeForwardedSketchFn,
 		onDoneFn onForwardedAggregationDoneFn,
 	)
 
+	// SetAllowedLateness sets how long flushed aggregations are kept around to
+	// be corrected by values arriving after they have been flushed.
+	SetAllowedLateness(value time.Duration)
+
 	// AddUnion adds a metric value union at a given timestamp.
 	AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) error
 
 	// AddMetric adds a metric value at a given timestamp.
 	AddValue(timestamp time.Time, value float64) error
 
-	// AddUnique adds a metric value from a given source at a given timestamp.
-	// If previous values from the same source have already been added to the
-	// same aggregation, the incoming value is discarded.
-	AddUnique(timestamp time.Time, values []float64, sourceID uint32) error
+	// AddUnique adds metric values and a count distinct sketch from a given
+	// source at a given timestamp. If previous values from the same source have
+	// already been added to the same aggregation, the incoming values are discarded.
+	AddUnique(timestamp time.Time, values []float64, countDistinctSketch []uint8, sourceID uint32) error
+
+	// AddUniqueOverflow adds metric values collapsed into an overflow series
+	// from a given source and original series at a given timestamp. If previous
+	// values from the same source and original series have already been added
+	// to the same aggregation, the incoming values are discarded.
+	AddUniqueOverflow(
+		timestamp time.Time,
+		values []float64,
+		countDistinctSketch []uint8,
+		source overflowSource,
+	) error
 
 	// Consume consumes values before a given time and removes
 	// them from the element after they are consumed, returning whether
@@ -136,9 +211,17 @@ type metricElem interface {
 		timestampNanosFn timestampNanosFn,
 		flushLocalFn flushLocalMetricFn,
 		flushForwardedFn flushForwardedMetricFn,
+		flushForwardedSketchFn flushForwardedSketchFn,
 		onForwardedFlushedFn onForwardingElemFlushedFn,
 	) bool
 
+	// Snapshot returns a snapshot of the aggregations that have not been
+	// consumed yet, or false if the element is closed.
+	Snapshot() (elemSnapshot, bool)
+
+	// Restore replaces the aggregations in the element with those in the snapshot.
+	Restore(snapshot elemSnapshot) error
+
 	// MarkAsTombstoned marks an element as tombstoned, which means this element
 	// will be deleted once its aggregated values have been flushed.
 	MarkAsTombstoned()
@@ -162,12 +245,15 @@ type elemBase struct {
 	parsedPipeline                  parsedPipeline
 	numForwardedTimes               int
 	idPrefixSuffixType              IDPrefixSuffixType
+	allowedLateness                 time.Duration
 	writeForwardedMetricFn          writeForwardedMetricFn
+	writeForwardedSketchFn          writeForwardedSketchFn
 	onForwardedAggregationWrittenFn onForwardedAggregationDoneFn
 
 	// Mutable states.
 	tombstoned           bool
 	closed               bool
+	consumeLock          sync.Mutex       // nolint: structcheck
 	cachedSourceSetsLock sync.Mutex       // nolint: structcheck
 	cachedSourceSets     []*bitset.BitSet // nolint: structcheck
 }
@@ -204,17 +290,31 @@ func (e *elemBase) resetSetData(
 	e.tombstoned = false
 	e.closed = false
 	e.idPrefixSuffixType = idPrefixSuffixType
+	e.allowedLateness = 0
 	return nil
 }
 
 func (e *elemBase) SetForwardedCallbacks(
 	writeFn writeForwardedMetricFn,
+	writeSketchFn writeForwardedSketchFn,
 	onDoneFn onForwardedAggregationDoneFn,
 ) {
 	e.writeForwardedMetricFn = writeFn
+	e.writeForwardedSketchFn = writeSketchFn
 	e.onForwardedAggregationWrittenFn = onDoneFn
 }
 
+func (e *elemBase) SetAllowedLateness(value time.Duration) {
+	// NB: flushed aggregations are only kept around for corrections if they are
+	// flushed locally. Forwarded values are de-duplicated by source downstream so
+	// corrections would be dropped, and derivatives depend on the order in which
+	// aggregations are consumed.
+	if e.parsedPipeline.HasRollup || e.parsedPipeline.HasDerivativeTransform {
+		return
+	}
+	e.allowedLateness = value
+}
+
 func (e *elemBase) ID() id.RawID { return e.id }
 
 func (e *elemBase) ForwardedID() (id.RawID, bool) {
@@ -233,6 +333,7 @@ func (e *elemBase) ForwardedAggregationKey() (aggregationKey, bool) {
 		storagePolicy:     e.sp,
 		pipeline:          e.parsedPipeline.Remainder,
 		numForwardedTimes: e.numForwardedTimes + 1,
+		rollupRuleID:      e.parsedPipeline.Rollup.RuleID,
 	}, true
 }
 
@@ -313,6 +414,9 @@ func (e *timerElemBase) ResetSetData(
 	if !aggTypes.IsValidForTimer() {
 		return fmt.Errorf("invalid aggregation types %s for timer", aggTypes.String())
 	}
+	if err := aggTypesOpts.ValidateTimerTypeStrings(aggTypes); err != nil {
+		return err
+	}
 	if useDefaultAggregation {
 		e.quantiles = aggTypesOpts.Quantiles()
 		e.quantilesPool = nil
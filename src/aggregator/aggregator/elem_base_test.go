@@ -139,6 +139,24 @@ func TestElemBaseForwardedAggregationKeyWithCustomPipeline(t *testing.T) {
 	require.Equal(t, expected, aggKey)
 }
 
+func TestElemBaseForwardedAggregationKeyWithRollupRuleID(t *testing.T) {
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Count),
+				RuleID:        []byte("rule"),
+			},
+		},
+	})
+	e := &elemBase{}
+	e.resetSetData(testCounterID, testStoragePolicy, testAggregationTypesExpensive, false, p, 3, WithPrefixWithSuffix)
+	aggKey, ok := e.ForwardedAggregationKey()
+	require.True(t, ok)
+	require.Equal(t, []byte("rule"), aggKey.rollupRuleID)
+}
+
 func TestElemBaseMarkAsTombStoned(t *testing.T) {
 	e := &elemBase{}
 	require.False(t, e.tombstoned)
@@ -262,6 +280,14 @@ func TestTimerElemBaseResetSetDataInvalidTypes(t *testing.T) {
 	require.True(t, strings.Contains(err.Error(), "invalid aggregation types Last for timer"))
 }
 
+func TestTimerElemBaseResetSetDataConflictingTypeStrings(t *testing.T) {
+	e := timerElemBase{}
+	typesOpts := maggregation.NewTypesOptions()
+	typesOpts = typesOpts.SetParameterisedQuantileTypeStringFn(typesOpts.QuantileTypeStringFn())
+	aggTypes := maggregation.Types{maggregation.P95, maggregation.MustQuantileType(0.095)}
+	require.Error(t, e.ResetSetData(typesOpts, aggTypes, false))
+}
+
 func TestGaugeElemBase(t *testing.T) {
 	opts := NewOptions()
 	aggTypesOpts := opts.AggregationTypesOptions()
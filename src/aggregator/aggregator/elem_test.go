@@ -21,12 +21,15 @@
 package aggregator
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"testing"
 	"time"
 
 	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/hash"
 	maggregation "github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/id"
@@ -259,7 +262,7 @@ func TestCounterElemAddUnique(t *testing.T) {
 
 	// Add a metric.
 	source1 := uint32(1234)
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{345}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{345}, nil, source1))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, int64(345), e.values[0].lockedAgg.aggregation.Sum())
@@ -270,7 +273,7 @@ func TestCounterElemAddUnique(t *testing.T) {
 	// Add another metric at slightly different time but still within the
 	// same aggregation interval with a different source.
 	source2 := uint32(5678)
-	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{500}, source2))
+	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{500}, nil, source2))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, int64(845), e.values[0].lockedAgg.aggregation.Sum())
@@ -279,7 +282,7 @@ func TestCounterElemAddUnique(t *testing.T) {
 	require.True(t, e.values[0].lockedAgg.sourcesSeen.Test(uint(source2)))
 
 	// Add the counter metric in the next aggregation interval.
-	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{278}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{278}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -291,7 +294,7 @@ func TestCounterElemAddUnique(t *testing.T) {
 
 	// Add the counter metric in the same aggregation interval with the same
 	// source results in an error.
-	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{278}, source1))
+	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{278}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -303,7 +306,76 @@ func TestCounterElemAddUnique(t *testing.T) {
 
 	// Adding the counter metric to a closed element results in an error.
 	e.closed = true
-	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{100}, 1376))
+	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{100}, nil, 1376))
+}
+
+func TestCounterElemAddUniqueOverflow(t *testing.T) {
+	e, err := NewCounterElem(testCounterID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, err)
+
+	var (
+		source1 = overflowSource{sourceID: 1234, idHash: hash.Murmur3Hash128([]byte("foo"))}
+		source2 = overflowSource{sourceID: 1234, idHash: hash.Murmur3Hash128([]byte("bar"))}
+		source3 = overflowSource{sourceID: 5678, idHash: hash.Murmur3Hash128([]byte("foo"))}
+	)
+
+	// Values of different original series or from different sources are
+	// all added.
+	require.NoError(t, e.AddUniqueOverflow(testTimestamps[0], []float64{1}, nil, source1))
+	require.NoError(t, e.AddUniqueOverflow(testTimestamps[0], []float64{2}, nil, source2))
+	require.NoError(t, e.AddUniqueOverflow(testTimestamps[0], []float64{4}, nil, source3))
+	require.Equal(t, 1, len(e.values))
+	require.Equal(t, int64(7), e.values[0].lockedAgg.aggregation.Sum())
+	require.Equal(t, 3, e.values[0].lockedAgg.overflowSourcesSeen.numAdded)
+
+	// Values of the same original series from the same source are discarded.
+	require.Equal(t, errDuplicateForwardingSource, e.AddUniqueOverflow(testTimestamps[1], []float64{8}, nil, source1))
+	require.Equal(t, int64(7), e.values[0].lockedAgg.aggregation.Sum())
+
+	// The same original series from the same source can be added to the next
+	// aggregation interval.
+	require.NoError(t, e.AddUniqueOverflow(testTimestamps[2], []float64{8}, nil, source1))
+	require.Equal(t, 2, len(e.values))
+	require.Equal(t, int64(8), e.values[1].lockedAgg.aggregation.Sum())
+
+	// Adding the values to a closed element results in an error.
+	e.closed = true
+	require.Equal(t, errElemClosed, e.AddUniqueOverflow(testTimestamps[2], []float64{100}, nil, source2))
+}
+
+func TestCounterElemAddUniqueOverflowBoundedMemory(t *testing.T) {
+	const capacity = 1024
+	opts := NewOptions().SetOverflowSourceSetCapacity(capacity)
+	e, err := NewCounterElem(testCounterID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, opts)
+	require.NoError(t, err)
+
+	// Overflow many more series than the capacity into the same series.
+	var numDiscarded int
+	for i := 0; i < 100*capacity; i++ {
+		source := overflowSource{
+			sourceID: uint32(i % 4),
+			idHash:   hash.Murmur3Hash128([]byte(fmt.Sprintf("series%d", i))),
+		}
+		err := e.AddUniqueOverflow(testTimestamps[0], []float64{1}, nil, source)
+		if err == errDuplicateForwardingSource {
+			numDiscarded++
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	// The memory used to deduplicate the values does not grow with the number
+	// of series, and few values of distinct series are wrongly discarded.
+	require.Equal(t, 1, len(e.values))
+	set := e.values[0].lockedAgg.overflowSourcesSeen
+	require.Equal(t, capacity, set.numAdded)
+	require.Equal(t, capacity*overflowSourceSetBitsPerSource, int(set.bits.Len()))
+	require.True(t, numDiscarded < capacity/100)
+	require.Equal(t, int64(100*capacity-numDiscarded), e.values[0].lockedAgg.aggregation.Sum())
+
+	snapshot, ok := e.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, capacity*overflowSourceSetBitsPerSource/64, len(snapshot.values[0].overflowSourcesSeen))
 }
 
 func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
@@ -312,7 +384,7 @@ func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Add a counter metric.
 	source1 := uint32(1234)
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12}, nil, source1))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, int64(12), e.values[0].lockedAgg.aggregation.Sum())
@@ -323,14 +395,14 @@ func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
 	// Add the counter metric at slightly different time
 	// but still within the same aggregation interval.
 	source2 := uint32(5678)
-	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{14}, source2))
+	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{14}, nil, source2))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, int64(26), e.values[0].lockedAgg.aggregation.Sum())
 	require.Equal(t, int64(14), e.values[0].lockedAgg.aggregation.Max())
 
 	// Add the counter metric in the next aggregation interval.
-	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{20}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{20}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -341,7 +413,7 @@ func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Add the counter metric in the same aggregation interval with the same
 	// source results in an error.
-	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{30}, source1))
+	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{30}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -353,7 +425,7 @@ func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Adding the counter metric to a closed element results in an error.
 	e.closed = true
-	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{40}, 1376))
+	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{40}, nil, 1376))
 }
 
 func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
@@ -365,8 +437,9 @@ func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -376,7 +449,7 @@ func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForCounter(testAlignedStarts[1], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -386,7 +459,7 @@ func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForCounter(testAlignedStarts[2], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -397,7 +470,7 @@ func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -408,7 +481,7 @@ func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -424,8 +497,9 @@ func TestCounterElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -435,7 +509,7 @@ func TestCounterElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForCounter(testAlignedStarts[1], testStoragePolicy, testAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -445,7 +519,7 @@ func TestCounterElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForCounter(testAlignedStarts[2], testStoragePolicy, testAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -456,7 +530,7 @@ func TestCounterElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -467,7 +541,7 @@ func TestCounterElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -511,8 +585,9 @@ func TestCounterElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -529,7 +604,7 @@ func TestCounterElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -553,7 +628,7 @@ func TestCounterElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -566,7 +641,7 @@ func TestCounterElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -577,7 +652,7 @@ func TestCounterElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -598,6 +673,7 @@ func TestCounterElemClose(t *testing.T) {
 	require.Nil(t, e.id)
 	require.Equal(t, parsedPipeline{}, e.parsedPipeline)
 	require.Nil(t, e.writeForwardedMetricFn)
+	require.Nil(t, e.writeForwardedSketchFn)
 	require.Nil(t, e.onForwardedAggregationWrittenFn)
 	require.Nil(t, e.cachedSourceSets)
 	require.Equal(t, 0, len(e.values))
@@ -788,9 +864,9 @@ func TestTimerElemAddUnique(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add a metric.
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{11.1}, 1))
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12.2}, 2))
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{13.3}, 3))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{11.1}, nil, 1))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12.2}, nil, 2))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{13.3}, nil, 3))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	timer := e.values[0].lockedAgg.aggregation
@@ -800,7 +876,7 @@ func TestTimerElemAddUnique(t *testing.T) {
 
 	// Add another metric at slightly different time but still within the
 	// same aggregation interval with a different source.
-	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{14.4}, 4))
+	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{14.4}, nil, 4))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	timer = e.values[0].lockedAgg.aggregation
@@ -808,7 +884,7 @@ func TestTimerElemAddUnique(t *testing.T) {
 	require.InEpsilon(t, 51, timer.Sum(), 1e-10)
 
 	// Add the metric in the next aggregation interval.
-	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{20.0}, 1))
+	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{20.0}, nil, 1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -819,7 +895,7 @@ func TestTimerElemAddUnique(t *testing.T) {
 
 	// Add the metric in the same aggregation interval with the same
 	// source results in an error.
-	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{30.0}, 1))
+	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{30.0}, nil, 1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -831,7 +907,7 @@ func TestTimerElemAddUnique(t *testing.T) {
 
 	// Adding the timer metric to a closed element results in an error.
 	e.closed = true
-	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{100}, 3))
+	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{100}, nil, 3))
 }
 
 func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
@@ -848,8 +924,9 @@ func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -859,7 +936,7 @@ func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForTimer(testAlignedStarts[1], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -869,7 +946,7 @@ func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForTimer(testAlignedStarts[2], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -880,7 +957,7 @@ func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -891,7 +968,7 @@ func TestTimerElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -915,8 +992,9 @@ func TestTimerElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -926,7 +1004,7 @@ func TestTimerElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForTimer(testAlignedStarts[1], testStoragePolicy, testTimerAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -936,7 +1014,7 @@ func TestTimerElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForTimer(testAlignedStarts[2], testStoragePolicy, testTimerAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -947,7 +1025,7 @@ func TestTimerElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -958,7 +1036,7 @@ func TestTimerElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1009,8 +1087,9 @@ func TestTimerElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -1027,7 +1106,7 @@ func TestTimerElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -1051,7 +1130,7 @@ func TestTimerElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -1064,7 +1143,7 @@ func TestTimerElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -1075,7 +1154,7 @@ func TestTimerElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1103,6 +1182,7 @@ func TestTimerElemClose(t *testing.T) {
 	require.Nil(t, e.id)
 	require.Equal(t, parsedPipeline{}, e.parsedPipeline)
 	require.Nil(t, e.writeForwardedMetricFn)
+	require.Nil(t, e.writeForwardedSketchFn)
 	require.Nil(t, e.onForwardedAggregationWrittenFn)
 	require.Nil(t, e.cachedSourceSets)
 	require.Equal(t, 0, len(e.values))
@@ -1300,7 +1380,7 @@ func TestGaugeElemAddUnique(t *testing.T) {
 
 	// Add a metric.
 	source1 := uint32(1234)
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12.3, 34.5}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{12.3, 34.5}, nil, source1))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, 46.8, e.values[0].lockedAgg.aggregation.Sum())
@@ -1311,7 +1391,7 @@ func TestGaugeElemAddUnique(t *testing.T) {
 	// Add another metric at slightly different time but still within the
 	// same aggregation interval with a different source.
 	source2 := uint32(5678)
-	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{50}, source2))
+	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{50}, nil, source2))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, 96.8, e.values[0].lockedAgg.aggregation.Sum())
@@ -1320,7 +1400,7 @@ func TestGaugeElemAddUnique(t *testing.T) {
 	require.True(t, e.values[0].lockedAgg.sourcesSeen.Test(uint(source2)))
 
 	// Add the metric in the next aggregation interval.
-	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{27.8}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{27.8}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -1332,7 +1412,7 @@ func TestGaugeElemAddUnique(t *testing.T) {
 
 	// Add the gauge metric in the same aggregation interval with the same
 	// source results in an error.
-	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{27.8}, source1))
+	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{27.8}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -1344,7 +1424,7 @@ func TestGaugeElemAddUnique(t *testing.T) {
 
 	// Adding the gauge metric to a closed element results in an error.
 	e.closed = true
-	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{10.0}, 3))
+	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{10.0}, nil, 3))
 }
 
 func TestGaugeElemAddUniqueWithCustomAggregation(t *testing.T) {
@@ -1353,7 +1433,7 @@ func TestGaugeElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Add a gauge metric.
 	source1 := uint32(1234)
-	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{1.2}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[0], []float64{1.2}, nil, source1))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.Equal(t, 1.2, e.values[0].lockedAgg.aggregation.Sum())
@@ -1364,14 +1444,14 @@ func TestGaugeElemAddUniqueWithCustomAggregation(t *testing.T) {
 	// Add the gauge metric at slightly different time
 	// but still within the same aggregation interval.
 	source2 := uint32(5678)
-	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{1.4}, source2))
+	require.NoError(t, e.AddUnique(testTimestamps[1], []float64{1.4}, nil, source2))
 	require.Equal(t, 1, len(e.values))
 	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
 	require.InEpsilon(t, 2.6, e.values[0].lockedAgg.aggregation.Sum(), 1e-10)
 	require.Equal(t, 1.4, e.values[0].lockedAgg.aggregation.Max())
 
 	// Add the gauge metric in the next aggregation interval.
-	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{2.0}, source1))
+	require.NoError(t, e.AddUnique(testTimestamps[2], []float64{2.0}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -1382,7 +1462,7 @@ func TestGaugeElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Add the gauge metric in the same aggregation interval with the same
 	// source results in an error.
-	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{3.0}, source1))
+	require.Equal(t, errDuplicateForwardingSource, e.AddUnique(testTimestamps[2], []float64{3.0}, nil, source1))
 	require.Equal(t, 2, len(e.values))
 	for i := 0; i < len(e.values); i++ {
 		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
@@ -1394,7 +1474,7 @@ func TestGaugeElemAddUniqueWithCustomAggregation(t *testing.T) {
 
 	// Adding the gauge metric to a closed element results in an error.
 	e.closed = true
-	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{4.0}, 3))
+	require.Equal(t, errElemClosed, e.AddUnique(testTimestamps[2], []float64{4.0}, nil, 3))
 }
 
 func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
@@ -1406,8 +1486,9 @@ func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1417,7 +1498,7 @@ func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForGauge(testAlignedStarts[1], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1427,7 +1508,7 @@ func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForGauge(testAlignedStarts[2], testStoragePolicy, maggregation.DefaultTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1438,7 +1519,7 @@ func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1449,7 +1530,7 @@ func TestGaugeElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1465,8 +1546,9 @@ func TestGaugeElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1476,7 +1558,7 @@ func TestGaugeElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForGauge(testAlignedStarts[1], testStoragePolicy, testAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1486,7 +1568,7 @@ func TestGaugeElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, expectedLocalMetricsForGauge(testAlignedStarts[2], testStoragePolicy, testAggregationTypes), *localRes)
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1497,7 +1579,7 @@ func TestGaugeElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1508,7 +1590,7 @@ func TestGaugeElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1552,8 +1634,9 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	// Consume values before an early-enough time.
 	localFn, localRes := testFlushLocalMetricFn()
 	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
 	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
-	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(0, isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -1570,7 +1653,7 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -1594,7 +1677,7 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
@@ -1607,7 +1690,7 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.True(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	verifyOnForwardedFlushResult(t, expectedOnFlushedRes, *onForwardedFlushedRes)
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
@@ -1618,7 +1701,7 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
-	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn))
 	require.Equal(t, 0, len(*localRes))
 	require.Equal(t, 0, len(*forwardRes))
 	require.Equal(t, 0, len(*onForwardedFlushedRes))
@@ -1639,6 +1722,7 @@ func TestGaugeElemClose(t *testing.T) {
 	require.Nil(t, e.id)
 	require.Equal(t, parsedPipeline{}, e.parsedPipeline)
 	require.Nil(t, e.writeForwardedMetricFn)
+	require.Nil(t, e.writeForwardedSketchFn)
 	require.Nil(t, e.onForwardedAggregationWrittenFn)
 	require.Nil(t, e.cachedSourceSets)
 	require.Equal(t, 0, len(e.values))
@@ -1720,6 +1804,12 @@ type testForwardedMetricWithMetadata struct {
 	value          float64
 }
 
+type testForwardedSketchWithMetadata struct {
+	aggregationKey aggregationKey
+	timeNanos      int64
+	registers      []uint8
+}
+
 type testOnForwardedFlushedData struct {
 	aggregationKey aggregationKey
 }
@@ -1767,6 +1857,25 @@ func testFlushForwardedMetricFn() (
 	}, &result
 }
 
+func testFlushForwardedSketchFn() (
+	flushForwardedSketchFn,
+	*[]testForwardedSketchWithMetadata,
+) {
+	var result []testForwardedSketchWithMetadata
+	return func(
+		writeFn writeForwardedSketchFn,
+		aggregationKey aggregationKey,
+		timeNanos int64,
+		registers []uint8,
+	) {
+		result = append(result, testForwardedSketchWithMetadata{
+			aggregationKey: aggregationKey,
+			timeNanos:      timeNanos,
+			registers:      registers,
+		})
+	}, &result
+}
+
 func testOnForwardedFlushedFn() (
 	onForwardingElemFlushedFn,
 	*[]testOnForwardedFlushedData,
@@ -1794,6 +1903,299 @@ func testStreamOptions(t *testing.T, size int) (cm.Options, cm.StreamPool, *int)
 	return streamOpts, p, &numAlloc
 }
 
+func TestCounterElemSnapshotRestore(t *testing.T) {
+	opts := NewOptions()
+	e := testCounterElem(testAlignedStarts[:2], testCounterVals, maggregation.DefaultTypes, applied.DefaultPipeline, opts)
+	snapshot, ok := e.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, 2, len(snapshot.values))
+
+	restored := MustNewCounterElem(testCounterID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, WithPrefixWithSuffix, opts)
+	require.NoError(t, restored.Restore(snapshot))
+	require.Equal(t, len(e.values), len(restored.values))
+	for i := range e.values {
+		require.Equal(t, e.values[i].startAtNanos, restored.values[i].startAtNanos)
+		require.Equal(t, e.values[i].lockedAgg.aggregation.Sum(), restored.values[i].lockedAgg.aggregation.Sum())
+		require.Equal(t, e.values[i].lockedAgg.aggregation.Count(), restored.values[i].lockedAgg.aggregation.Count())
+	}
+
+	// Closed elements can neither be snapshotted nor restored.
+	restored.Close()
+	_, ok = restored.Snapshot()
+	require.False(t, ok)
+	require.Equal(t, errElemClosed, restored.Restore(snapshot))
+}
+
+func TestCounterElemSnapshotRestoreSlidingWindow(t *testing.T) {
+	var (
+		sp                      = policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour, 5*time.Minute)
+		resolution              = sp.Resolution().Window
+		start                   = time.Unix(0, testAlignedStarts[0])
+		aggTypes                = maggregation.Types{maggregation.Sum}
+		localFn, localRes       = testFlushLocalMetricFn()
+		forwardFn, _            = testFlushForwardedMetricFn()
+		forwardSketchFn, _      = testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	)
+	consume := func(e *CounterElem, targetNanos int64) []testLocalMetricWithMetadata {
+		*localRes = nil
+		e.Consume(targetNanos, isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+		return *localRes
+	}
+	e := MustNewCounterElem(testCounterID, sp, aggTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, e.AddValue(start, 1))
+	require.NoError(t, e.AddValue(start.Add(resolution), 2))
+	require.NoError(t, e.AddValue(start.Add(3*resolution), 4))
+	require.Equal(t, 2, len(consume(e, start.Add(2*resolution).UnixNano())))
+	require.Equal(t, 2, len(e.windowed))
+	require.Equal(t, 1, len(e.values))
+
+	// The sliding window survives the snapshot being checkpointed and restored.
+	snapshot, ok := e.Snapshot()
+	require.True(t, ok)
+	require.Equal(t, 2, len(snapshot.windowed))
+	require.Equal(t, e.nextStepAtNanos, snapshot.nextStepAtNanos)
+	var buf bytes.Buffer
+	enc := newCheckpointEncoder(&buf)
+	enc.writeElem(metric.CounterType, snapshot)
+	require.NoError(t, enc.err)
+	dec := newCheckpointDecoder(buf.Bytes())
+	decoded := dec.readElem(metric.CounterType)
+	require.NoError(t, dec.err)
+	require.Equal(t, len(snapshot.windowed), len(decoded.windowed))
+	require.Equal(t, snapshot.nextStepAtNanos, decoded.nextStepAtNanos)
+
+	restored := MustNewCounterElem(testCounterID, sp, aggTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, restored.Restore(decoded))
+	require.Equal(t, len(e.windowed), len(restored.windowed))
+	require.Equal(t, e.nextStepAtNanos, restored.nextStepAtNanos)
+
+	// The restored element flushes the same windows as the original one,
+	// including the values consumed before the snapshot.
+	targetNanos := start.Add(4 * resolution).UnixNano()
+	expected := consume(e, targetNanos)
+	require.Equal(t, []float64{3, 7}, testLocalMetricValues(expected))
+	require.Equal(t, expected, consume(restored, targetNanos))
+}
+
+func testLocalMetricValues(metrics []testLocalMetricWithMetadata) []float64 {
+	values := make([]float64, 0, len(metrics))
+	for _, m := range metrics {
+		values = append(values, m.value)
+	}
+	return values
+}
+
+func TestCounterElemConsumeWithAllowedLateness(t *testing.T) {
+	var (
+		resolution              = testStoragePolicy.Resolution().Window
+		start                   = time.Unix(0, testAlignedStarts[0])
+		opts                    = NewOptions()
+		localFn, localRes       = testFlushLocalMetricFn()
+		forwardFn, _            = testFlushForwardedMetricFn()
+		forwardSketchFn, _      = testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	)
+	e := MustNewCounterElem(testCounterID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, opts)
+	require.Equal(t, time.Duration(0), e.allowedLateness)
+	e.SetAllowedLateness(2 * resolution)
+	require.Equal(t, 2*resolution, e.allowedLateness)
+	consume := func(targetNanos int64) {
+		*localRes = nil
+		e.Consume(targetNanos, isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+	}
+
+	// Flushing the window keeps it around for corrections.
+	require.NoError(t, e.AddValue(start, 1))
+	consume(start.Add(resolution).UnixNano())
+	require.Equal(t, 1, len(*localRes))
+	require.Equal(t, start.Add(resolution).UnixNano(), (*localRes)[0].timeNanos)
+	require.Equal(t, 1.0, (*localRes)[0].value)
+	require.Equal(t, 0, len(e.values))
+	require.Equal(t, 1, len(e.flushed))
+
+	// Late values correct the flushed window, which is flushed again.
+	require.NoError(t, e.AddValue(start, 2))
+	require.Equal(t, 0, len(e.values))
+	consume(start.Add(2 * resolution).UnixNano())
+	require.Equal(t, 1, len(*localRes))
+	require.Equal(t, start.Add(resolution).UnixNano(), (*localRes)[0].timeNanos)
+	require.Equal(t, 3.0, (*localRes)[0].value)
+
+	// Windows without late values are not flushed again.
+	consume(start.Add(2 * resolution).UnixNano())
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 1, len(e.flushed))
+
+	// Windows are no longer kept once the allowed lateness has passed.
+	consume(start.Add(3 * resolution).UnixNano())
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 0, len(e.flushed))
+
+	// Elements forwarding their values do not keep flushed windows.
+	e = MustNewCounterElem(testCounterID, testStoragePolicy, maggregation.DefaultTypes, testPipeline, testNumForwardedTimes, NoPrefixNoSuffix, opts)
+	e.SetAllowedLateness(2 * resolution)
+	require.Equal(t, time.Duration(0), e.allowedLateness)
+}
+
+func TestCounterElemConsumeCountDistinctForwardsSketch(t *testing.T) {
+	var (
+		aggTypes       = maggregation.Types{maggregation.CountDistinct}
+		resolution     = testStoragePolicy.Resolution().Window
+		start          = time.Unix(0, testAlignedStarts[0])
+		rollupPipeline = applied.NewPipeline([]applied.OpUnion{
+			{
+				Type: pipeline.RollupOpType,
+				Rollup: applied.RollupOp{
+					ID:            []byte("foo.bar"),
+					AggregationID: maggregation.MustCompressTypes(maggregation.CountDistinct),
+				},
+			},
+		})
+		opts   = NewOptions()
+		rollup = MustNewCounterElem(testCounterID, testStoragePolicy, aggTypes, applied.DefaultPipeline, testNumForwardedTimes+1, NoPrefixNoSuffix, opts)
+	)
+
+	// Each upstream element forwards its sketch instead of its estimate, and the
+	// rollup element merges the sketches from all sources.
+	for sourceID, vals := range [][]int64{{1, 2, 3, 1}, {3, 4, 5}} {
+		e := MustNewCounterElem(testCounterID, testStoragePolicy, aggTypes, rollupPipeline, testNumForwardedTimes, NoPrefixNoSuffix, opts)
+		for _, v := range vals {
+			require.NoError(t, e.AddUnion(start, unaggregated.MetricUnion{Type: metric.CounterType, CounterVal: v}))
+		}
+		localFn, localRes := testFlushLocalMetricFn()
+		forwardFn, forwardRes := testFlushForwardedMetricFn()
+		forwardSketchFn, forwardSketchRes := testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+		e.Consume(start.Add(resolution).UnixNano(), isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+		require.Equal(t, 0, len(*localRes))
+		require.Equal(t, 0, len(*forwardRes))
+		require.Equal(t, 1, len(*forwardSketchRes))
+
+		res := (*forwardSketchRes)[0]
+		require.Equal(t, standardMetricTimestampNanos(testAlignedStarts[0], resolution), res.timeNanos)
+		require.NotEmpty(t, res.registers)
+		require.NoError(t, rollup.AddUnique(start, nil, res.registers, uint32(sourceID)))
+	}
+
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, _ := testFlushForwardedMetricFn()
+	forwardSketchFn, _ := testFlushForwardedSketchFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	rollup.Consume(start.Add(resolution).UnixNano(), isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+	require.Equal(t, 1, len(*localRes))
+	require.InDelta(t, 5.0, (*localRes)[0].value, 0.1)
+}
+
+func TestCounterElemConsumeSlidingWindow(t *testing.T) {
+	var (
+		sp                      = policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour, 30*time.Second)
+		resolution              = sp.Resolution().Window
+		start                   = time.Unix(0, testAlignedStarts[0])
+		aggTypes                = maggregation.Types{maggregation.Sum}
+		localFn, localRes       = testFlushLocalMetricFn()
+		forwardFn, _            = testFlushForwardedMetricFn()
+		forwardSketchFn, _      = testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	)
+	e := MustNewCounterElem(testCounterID, sp, aggTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	consume := func(targetNanos int64) bool {
+		*localRes = nil
+		return e.Consume(targetNanos, isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+	}
+	require.NoError(t, e.AddValue(start, 1))
+	require.NoError(t, e.AddValue(start.Add(resolution), 2))
+	require.NoError(t, e.AddValue(start.Add(3*resolution), 4))
+	e.MarkAsTombstoned()
+
+	// Every step flushes the sum of the values in the last 30 seconds.
+	require.False(t, consume(start.Add(6*resolution).UnixNano()))
+	expected := []float64{1, 3, 3, 6, 4, 4}
+	require.Equal(t, len(expected), len(*localRes))
+	for i, v := range expected {
+		require.Equal(t, start.Add(time.Duration(i+1)*resolution).UnixNano(), (*localRes)[i].timeNanos)
+		require.Equal(t, v, (*localRes)[i].value)
+		require.Equal(t, sp, (*localRes)[i].sp)
+	}
+	require.Equal(t, 1, len(e.windowed))
+
+	// Late values are merged into the window they belong to.
+	require.NoError(t, e.AddValue(start.Add(3*resolution), 8))
+	require.False(t, consume(start.Add(6*resolution).UnixNano()))
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 1, len(e.windowed))
+	require.Equal(t, 0, len(e.values))
+
+	// The element can be collected once all values have slid out of the window.
+	require.True(t, consume(start.Add(10*resolution).UnixNano()))
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 0, len(e.windowed))
+
+	// Elements forwarding their values aggregate tumbling windows.
+	e = MustNewCounterElem(testCounterID, sp, aggTypes, testPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.False(t, e.isSlidingWindow())
+}
+
+func TestTimerElemConsumeSlidingWindow(t *testing.T) {
+	var (
+		sp                      = policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour, 20*time.Second)
+		resolution              = sp.Resolution().Window
+		start                   = time.Unix(0, testAlignedStarts[0])
+		aggTypes                = maggregation.Types{maggregation.Count, maggregation.Max}
+		localFn, localRes       = testFlushLocalMetricFn()
+		forwardFn, _            = testFlushForwardedMetricFn()
+		forwardSketchFn, _      = testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	)
+	e := MustNewTimerElem(testBatchTimerID, sp, aggTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, e.AddValue(start, 1))
+	require.NoError(t, e.AddValue(start, 5))
+	require.NoError(t, e.AddValue(start.Add(resolution), 3))
+	require.NoError(t, e.AddValue(start.Add(2*resolution), 2))
+	e.Consume(start.Add(3*resolution).UnixNano(), isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+
+	expected := [][]float64{{2, 5}, {3, 5}, {2, 3}}
+	require.Equal(t, 2*len(expected), len(*localRes))
+	for i, values := range expected {
+		for j, v := range values {
+			res := (*localRes)[2*i+j]
+			require.Equal(t, start.Add(time.Duration(i+1)*resolution).UnixNano(), res.timeNanos)
+			require.Equal(t, v, res.value)
+		}
+	}
+
+	e.Close()
+	require.Equal(t, 0, len(e.windowed))
+}
+
+func TestGaugeElemConsumeSlidingWindow(t *testing.T) {
+	var (
+		sp                      = policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour, 20*time.Second)
+		resolution              = sp.Resolution().Window
+		start                   = time.Unix(0, testAlignedStarts[0])
+		aggTypes                = maggregation.Types{maggregation.Last, maggregation.Min}
+		localFn, localRes       = testFlushLocalMetricFn()
+		forwardFn, _            = testFlushForwardedMetricFn()
+		forwardSketchFn, _      = testFlushForwardedSketchFn()
+		onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	)
+	e := MustNewGaugeElem(testGaugeID, sp, aggTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, e.AddValue(start, 1))
+	require.NoError(t, e.AddValue(start.Add(resolution), 3))
+	require.NoError(t, e.AddValue(start.Add(2*resolution), 2))
+	e.Consume(start.Add(3*resolution).UnixNano(), isStandardMetricEarlierThan, standardMetricTimestampNanos, localFn, forwardFn, forwardSketchFn, onForwardedFlushedFn)
+
+	expected := [][]float64{{1, 1}, {3, 1}, {2, 2}}
+	require.Equal(t, 2*len(expected), len(*localRes))
+	for i, values := range expected {
+		for j, v := range values {
+			res := (*localRes)[2*i+j]
+			require.Equal(t, start.Add(time.Duration(i+1)*resolution).UnixNano(), res.timeNanos)
+			require.Equal(t, v, res.value)
+		}
+	}
+}
+
 func testCounterElem(
 	alignedstartAtNanos []int64,
 	counterVals []int64,
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/bitset"
+	"github.com/m3db/m3/src/aggregator/hash"
 	"github.com/m3db/m3/src/aggregator/rate"
 	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/metrics/aggregation"
@@ -100,18 +101,20 @@ func newUntimedEntryMetrics(scope tally.Scope) untimedEntryMetrics {
 }
 
 type timedEntryMetrics struct {
-	rateLimit         rateLimitEntryMetrics
-	tooFarInTheFuture tally.Counter
-	tooFarInThePast   tally.Counter
-	metadataUpdates   tally.Counter
+	rateLimit             rateLimitEntryMetrics
+	tooFarInTheFuture     tally.Counter
+	tooFarInThePast       tally.Counter
+	withinAllowedLateness tally.Counter
+	metadataUpdates       tally.Counter
 }
 
 func newTimedEntryMetrics(scope tally.Scope) timedEntryMetrics {
 	return timedEntryMetrics{
-		rateLimit:         newRateLimitEntryMetrics(scope),
-		tooFarInTheFuture: scope.Counter("too-far-in-the-future"),
-		tooFarInThePast:   scope.Counter("too-far-in-the-past"),
-		metadataUpdates:   scope.Counter("metadata-updates"),
+		rateLimit:             newRateLimitEntryMetrics(scope),
+		tooFarInTheFuture:     scope.Counter("too-far-in-the-future"),
+		tooFarInThePast:       scope.Counter("too-far-in-the-past"),
+		withinAllowedLateness: scope.Counter("within-allowed-lateness"),
+		metadataUpdates:       scope.Counter("metadata-updates"),
 	}
 }
 
@@ -265,7 +268,22 @@ func (e *Entry) AddForwarded(
 	if err := e.applyValueRateLimit(1, e.metrics.forwarded.rateLimit); err != nil {
 		return err
 	}
-	return e.addForwarded(metric, metadata)
+	return e.addForwarded(metric, metadata, nil)
+}
+
+// AddForwardedOverflow adds a forwarded metric that has been collapsed into an
+// overflow series alongside its metadata. Unlike AddForwarded, values are
+// deduplicated by both their source and the id of the original series because
+// the overflow series receives the values of many series from each source.
+func (e *Entry) AddForwardedOverflow(
+	metric aggregated.ForwardedMetric,
+	metadata metadata.ForwardMetadata,
+	originalID metricid.RawID,
+) error {
+	if err := e.applyValueRateLimit(1, e.metrics.forwarded.rateLimit); err != nil {
+		return err
+	}
+	return e.addForwarded(metric, metadata, originalID)
 }
 
 // ShouldExpire returns whether the entry should expire.
@@ -308,6 +326,90 @@ func (e *Entry) TryExpire(now time.Time) bool {
 	return true
 }
 
+// ID returns the metric id of the entry, or false if the entry is closed or
+// has no aggregations.
+func (e *Entry) ID() (metricid.RawID, bool) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.closed || len(e.aggregations) == 0 {
+		return nil, false
+	}
+	return e.aggregations[0].elem.Value.(metricElem).ID(), true
+}
+
+// Snapshot returns the metric id alongside a snapshot of the aggregations in
+// the entry, or false if the entry is closed or has no aggregations.
+func (e *Entry) Snapshot() (metricid.RawID, entrySnapshot, bool) {
+	e.RLock()
+	if e.closed || len(e.aggregations) == 0 {
+		e.RUnlock()
+		return nil, entrySnapshot{}, false
+	}
+	snapshot := entrySnapshot{
+		hasDefaultMetadatas: e.hasDefaultMetadatas,
+		cutoverNanos:        e.cutoverNanos,
+		aggregations:        make([]aggregationSnapshotWithKey, 0, len(e.aggregations)),
+	}
+	id := e.aggregations[0].elem.Value.(metricElem).ID()
+	for _, val := range e.aggregations {
+		elemSnapshot, ok := val.elem.Value.(metricElem).Snapshot()
+		if !ok {
+			continue
+		}
+		snapshot.aggregations = append(snapshot.aggregations, aggregationSnapshotWithKey{
+			key:  val.key,
+			elem: elemSnapshot,
+		})
+	}
+	e.RUnlock()
+	return id, snapshot, true
+}
+
+// Restore replaces the aggregations in the entry with those in the snapshot.
+func (e *Entry) Restore(
+	category metricCategory,
+	metricType metric.Type,
+	id metricid.RawID,
+	snapshot entrySnapshot,
+) error {
+	e.Lock()
+	if e.closed {
+		e.Unlock()
+		return errEntryClosed
+	}
+	var (
+		elemID          = e.maybeCopyIDWithLock(id)
+		newAggregations = make(aggregationValues, 0, len(snapshot.aggregations))
+	)
+	for _, agg := range snapshot.aggregations {
+		listID, err := listIDFor(category, agg.key)
+		if err != nil {
+			e.Unlock()
+			return err
+		}
+		newAggregations, err = e.addNewAggregationKeyWithLock(metricType, elemID, agg.key, listID, newAggregations)
+		if err != nil {
+			e.Unlock()
+			return err
+		}
+		idx := newAggregations.index(agg.key)
+		if err = newAggregations[idx].elem.Value.(metricElem).Restore(agg.elem); err != nil {
+			e.Unlock()
+			return err
+		}
+	}
+
+	// Mark the elements not present in the snapshot as tombstoned.
+	e.removeOldAggregations(newAggregations)
+
+	e.aggregations = newAggregations
+	e.hasDefaultMetadatas = snapshot.hasDefaultMetadatas
+	e.cutoverNanos = snapshot.cutoverNanos
+	e.Unlock()
+	return nil
+}
+
 func (e *Entry) writeBatchTimerWithMetadatas(
 	metric unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
@@ -544,6 +646,12 @@ func (e *Entry) addNewAggregationKeyWithLock(
 	if err = newElem.ResetSetData(metricID, key.storagePolicy, aggTypes, key.pipeline, key.numForwardedTimes, key.idPrefixSuffixType); err != nil {
 		return nil, err
 	}
+	// NB: Only timed metrics carry their own timestamps and may arrive after their
+	// aggregation window has been flushed, so untimed and forwarded elements never
+	// keep flushed aggregations around.
+	if listID.listType == timedMetricListType {
+		newElem.SetAllowedLateness(timedAllowedLateness(e.opts, key.storagePolicy))
+	}
 	list, err := e.lists.FindOrCreate(listID)
 	if err != nil {
 		return nil, err
@@ -644,7 +752,7 @@ func (e *Entry) addTimed(
 	if err := e.checkTimestampForTimedMetric(
 		metric,
 		currTime.UnixNano(),
-		metadata.StoragePolicy.Resolution().Window,
+		metadata.StoragePolicy,
 	); err != nil {
 		e.RUnlock()
 		timeLock.RUnlock()
@@ -695,7 +803,7 @@ func (e *Entry) addTimed(
 func (e *Entry) checkTimestampForTimedMetric(
 	metric aggregated.Metric,
 	currNanos int64,
-	resolution time.Duration,
+	sp policy.StoragePolicy,
 ) error {
 	metricTimeNanos := metric.TimeNanos
 	timedBufferFuture := e.opts.BufferForFutureTimedMetric()
@@ -717,8 +825,15 @@ func (e *Entry) checkTimestampForTimedMetric(
 		return xerrors.NewRenamedError(errTooFarInTheFuture, err)
 	}
 	bufferPastFn := e.opts.BufferForPastTimedMetricFn()
-	timedBufferPast := bufferPastFn(resolution)
+	timedBufferPast := bufferPastFn(sp.Resolution().Window)
 	if currNanos-metricTimeNanos > timedBufferPast.Nanoseconds() {
+		// Accept datapoints within the allowed lateness so they can correct
+		// aggregations that have already been flushed.
+		allowedLateness := timedAllowedLateness(e.opts, sp)
+		if currNanos-metricTimeNanos <= (timedBufferPast + allowedLateness).Nanoseconds() {
+			e.metrics.timed.withinAllowedLateness.Inc(1)
+			return nil
+		}
 		e.metrics.timed.tooFarInThePast.Inc(1)
 		if !e.opts.VerboseErrors() {
 			// Don't return verbose errors if not enabled.
@@ -738,6 +853,16 @@ func (e *Entry) checkTimestampForTimedMetric(
 	return nil
 }
 
+// timedAllowedLateness returns how long the aggregations of timed metrics with the
+// given storage policy accept late values after they have been flushed. Sliding
+// windows are never corrected since every value contributes to several flushes.
+func timedAllowedLateness(opts Options, sp policy.StoragePolicy) time.Duration {
+	if sp.IsSlidingWindow() {
+		return 0
+	}
+	return opts.AllowedLatenessFn()(sp)
+}
+
 func (e *Entry) updateTimedMetadataWithLock(
 	metric aggregated.Metric,
 	metadata metadata.TimedMetadata,
@@ -777,6 +902,7 @@ func (e *Entry) addTimedWithLock(
 func (e *Entry) addForwarded(
 	metric aggregated.ForwardedMetric,
 	metadata metadata.ForwardMetadata,
+	originalID metricid.RawID,
 ) error {
 	timeLock := e.opts.TimeLock()
 	timeLock.RLock()
@@ -818,7 +944,7 @@ func (e *Entry) addForwarded(
 		idPrefixSuffixType: WithPrefixWithSuffix,
 	}
 	if idx := e.aggregations.index(key); idx >= 0 {
-		err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID)
+		err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID, originalID)
 		e.RUnlock()
 		timeLock.RUnlock()
 		return err
@@ -833,7 +959,7 @@ func (e *Entry) addForwarded(
 	}
 
 	if idx := e.aggregations.index(key); idx >= 0 {
-		err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID)
+		err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID, originalID)
 		e.Unlock()
 		timeLock.RUnlock()
 		return err
@@ -846,7 +972,7 @@ func (e *Entry) addForwarded(
 		return err
 	}
 	idx := e.aggregations.index(key)
-	err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID)
+	err := e.addForwardedWithLock(e.aggregations[idx], metric, metadata.SourceID, originalID)
 	e.Unlock()
 	timeLock.RUnlock()
 	return err
@@ -919,9 +1045,19 @@ func (e *Entry) addForwardedWithLock(
 	value aggregationValue,
 	metric aggregated.ForwardedMetric,
 	sourceID uint32,
+	originalID metricid.RawID,
 ) error {
-	timestamp := time.Unix(0, metric.TimeNanos)
-	err := value.elem.Value.(metricElem).AddUnique(timestamp, metric.Values, sourceID)
+	var (
+		timestamp = time.Unix(0, metric.TimeNanos)
+		elem      = value.elem.Value.(metricElem)
+		err       error
+	)
+	if originalID == nil {
+		err = elem.AddUnique(timestamp, metric.Values, metric.CountDistinctSketch, sourceID)
+	} else {
+		source := overflowSource{sourceID: sourceID, idHash: hash.Murmur3Hash128(originalID)}
+		err = elem.AddUniqueOverflow(timestamp, metric.Values, metric.CountDistinctSketch, source)
+	}
 	if err == errDuplicateForwardingSource {
 		// Duplicate forwarding sources may occur during a leader re-election and is not
 		// considered an external facing error. Hence, we record it and move on.
@@ -1278,6 +1278,62 @@ func TestEntryAddTimedMetricTooLate(t *testing.T) {
 	}
 }
 
+func TestEntryAddTimedMetricWithinAllowedLateness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		lateSP    = policy.NewStoragePolicy(10*time.Second, xtime.Second, time.Hour)
+		onTimeSP  = policy.NewStoragePolicy(time.Minute, xtime.Minute, time.Hour)
+		slidingSP = policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, time.Hour, time.Minute)
+	)
+	e, _, now := testEntry(ctrl, testEntryOptions{})
+	e.opts = e.opts.
+		SetBufferForPastTimedMetricFn(func(resolution time.Duration) time.Duration {
+			return resolution + time.Second
+		}).
+		SetAllowedLatenessFn(func(sp policy.StoragePolicy) time.Duration {
+			if sp == onTimeSP {
+				return 0
+			}
+			return time.Minute
+		})
+
+	// Metrics within the allowed lateness of their storage policy are accepted,
+	// and their elements keep flushed aggregations around for corrections.
+	metric := testTimedMetric
+	metric.TimeNanos = now.UnixNano() - 40*time.Second.Nanoseconds()
+	require.NoError(t, e.AddTimed(metric, metadata.TimedMetadata{StoragePolicy: lateSP}))
+	require.Equal(t, 1, len(e.aggregations))
+	require.Equal(t, time.Minute, e.aggregations[0].elem.Value.(*CounterElem).allowedLateness)
+
+	// Metrics beyond the allowed lateness are still rejected.
+	metric.TimeNanos = now.UnixNano() - 72*time.Second.Nanoseconds()
+	err := e.AddTimed(metric, metadata.TimedMetadata{StoragePolicy: lateSP})
+	require.Equal(t, errTooFarInThePast, err)
+
+	// Storage policies without an allowed lateness are unaffected.
+	metric.TimeNanos = now.UnixNano() - 62*time.Second.Nanoseconds()
+	err = e.AddTimed(metric, metadata.TimedMetadata{StoragePolicy: onTimeSP})
+	require.Equal(t, errTooFarInThePast, err)
+
+	// Sliding windows are never corrected so late metrics are rejected.
+	metric.TimeNanos = now.UnixNano() - 40*time.Second.Nanoseconds()
+	err = e.AddTimed(metric, metadata.TimedMetadata{StoragePolicy: slidingSP})
+	require.Equal(t, errTooFarInThePast, err)
+
+	// Untimed metrics never arrive late so their elements keep no flushed aggregations.
+	e.opts = e.opts.SetAllowedLatenessFn(func(policy.StoragePolicy) time.Duration {
+		return time.Minute
+	})
+	require.NoError(t, e.AddUntimed(testCounter, testDefaultStagedMetadatas))
+	for _, val := range e.aggregations {
+		if val.key.idPrefixSuffixType == WithPrefixWithSuffix {
+			require.Equal(t, time.Duration(0), val.elem.Value.(*CounterElem).allowedLateness)
+		}
+	}
+}
+
 func TestEntryAddTimedMetricTooEarly(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -100,6 +100,16 @@ type flushForwardedMetricFn func(
 	value float64,
 )
 
+// A flushForwardedSketchFn flushes the count distinct sketch of an aggregation
+// eligible for forwarding by either forwarding it (potentially to a different
+// aggregation server) or dropping it.
+type flushForwardedSketchFn func(
+	writeFn writeForwardedSketchFn,
+	aggregationKey aggregationKey,
+	timeNanos int64,
+	registers []uint8,
+)
+
 // An onForwardingElemFlushedFn is a callback function that should be called
 // when an aggregation element producing forwarded metrics has been flushed.
 type onForwardingElemFlushedFn func(
@@ -88,12 +88,14 @@ var (
 
 type flushTimesManagerMetrics struct {
 	flushTimesUnmarshalErrors tally.Counter
+	flushTimesGetErrors       tally.Counter
 	flushTimesPersist         instrument.MethodMetrics
 }
 
 func newFlushTimesManagerMetrics(scope tally.Scope) flushTimesManagerMetrics {
 	return flushTimesManagerMetrics{
 		flushTimesUnmarshalErrors: scope.Counter("flush-times-unmarshal-errors"),
+		flushTimesGetErrors:       scope.Counter("flush-times-get-errors"),
 		flushTimesPersist:         instrument.NewMethodMetrics(scope, "flush-times-persist", 1.0),
 	}
 }
@@ -157,6 +159,10 @@ func (mgr *flushTimesManager) Open(shardSetID uint32) error {
 		return errFlushTimesManagerAlreadyOpenOrClosed
 	}
 	mgr.flushTimesKey = fmt.Sprintf(mgr.flushTimesKeyFmt, shardSetID)
+	// NB: the flush times are loaded synchronously on a best-effort basis so they
+	// are available to callers right after opening, e.g., to discard the restored
+	// aggregations that have already been flushed.
+	mgr.proto = mgr.loadFlushTimesWithLock()
 	flushTimesWatch, err := mgr.flushTimesStore.Watch(mgr.flushTimesKey)
 	if err != nil {
 		return err
@@ -231,6 +237,31 @@ func (mgr *flushTimesManager) resetWithLock() {
 	mgr.persistWatchable = watch.NewWatchable()
 }
 
+func (mgr *flushTimesManager) loadFlushTimesWithLock() *schema.ShardSetFlushTimes {
+	value, err := mgr.flushTimesStore.Get(mgr.flushTimesKey)
+	if err == kv.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		mgr.metrics.flushTimesGetErrors.Inc(1)
+		mgr.logger.Error("flush times get error",
+			zap.String("flushTimesKey", mgr.flushTimesKey),
+			zap.Error(err),
+		)
+		return nil
+	}
+	var proto schema.ShardSetFlushTimes
+	if err := value.Unmarshal(&proto); err != nil {
+		mgr.metrics.flushTimesUnmarshalErrors.Inc(1)
+		mgr.logger.Error("flush times unmarshal error",
+			zap.String("flushTimesKey", mgr.flushTimesKey),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return &proto
+}
+
 func (mgr *flushTimesManager) watchFlushTimes(flushTimesWatch kv.ValueWatch) {
 	defer mgr.Done()
 
@@ -108,6 +108,18 @@ func TestFlushTimesManagerOpenSuccess(t *testing.T) {
 	require.NoError(t, mgr.Open(testShardSetID))
 }
 
+func TestFlushTimesManagerOpenLoadsExistingFlushTimes(t *testing.T) {
+	mgr, store := testFlushTimesManager()
+	_, err := store.Set(testFlushTimesKey, testFlushTimesProto)
+	require.NoError(t, err)
+	require.NoError(t, mgr.Open(testShardSetID))
+
+	// The existing flush times are available as soon as the manager is open.
+	res, err := mgr.Get()
+	require.NoError(t, err)
+	require.Equal(t, testFlushTimesProto, res)
+}
+
 func TestFlushTimesManagerGetClosed(t *testing.T) {
 	mgr, _ := testFlushTimesManager()
 	_, err := mgr.Get()
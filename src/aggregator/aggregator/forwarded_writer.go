@@ -51,6 +51,12 @@ type writeForwardedMetricFn func(
 	value float64,
 )
 
+type writeForwardedSketchFn func(
+	key aggregationKey,
+	timeNanos int64,
+	registers []uint8,
+)
+
 type onForwardedAggregationDoneFn func(key aggregationKey) error
 
 // forwardededMetricWriter writes forwarded metrics.
@@ -63,7 +69,7 @@ type forwardedMetricWriter interface {
 		metricType metric.Type,
 		metricID id.RawID,
 		aggKey aggregationKey,
-	) (writeForwardedMetricFn, onForwardedAggregationDoneFn, error)
+	) (writeForwardedMetricFn, writeForwardedSketchFn, onForwardedAggregationDoneFn, error)
 
 	// Unregister unregisters a forwarded metric.
 	Unregister(
@@ -161,10 +167,10 @@ func (w *forwardedWriter) Register(
 	metricType metric.Type,
 	metricID id.RawID,
 	aggKey aggregationKey,
-) (writeForwardedMetricFn, onForwardedAggregationDoneFn, error) {
+) (writeForwardedMetricFn, writeForwardedSketchFn, onForwardedAggregationDoneFn, error) {
 	if w.closed {
 		w.metrics.registerWriterClosed.Inc(1)
-		return nil, nil, errForwardedWriterClosed
+		return nil, nil, nil, errForwardedWriterClosed
 	}
 	key := newIDKey(metricType, metricID)
 	fa, exists := w.aggregations[key]
@@ -174,7 +180,7 @@ func (w *forwardedWriter) Register(
 	}
 	fa.add(aggKey)
 	w.metrics.registerSuccess.Inc(1)
-	return fa.writeForwardedMetricFn(), fa.onAggregationKeyDoneFn(), nil
+	return fa.writeForwardedMetricFn(), fa.writeForwardedSketchFn(), fa.onAggregationKeyDoneFn(), nil
 }
 
 func (w *forwardedWriter) Unregister(
@@ -250,8 +256,9 @@ func newIDKey(
 }
 
 type forwardedAggregationBucket struct {
-	timeNanos int64
-	values    []float64
+	timeNanos           int64
+	values              []float64
+	countDistinctSketch []uint8
 }
 
 type forwardedAggregationBuckets []forwardedAggregationBucket
@@ -280,15 +287,43 @@ func (agg *forwardedAggregationWithKey) reset() {
 		agg.buckets[i].values = agg.buckets[i].values[:0]
 		agg.cachedValueArrays = append(agg.cachedValueArrays, agg.buckets[i].values)
 		agg.buckets[i].values = nil
+		agg.buckets[i].countDistinctSketch = nil
 	}
 	agg.buckets = agg.buckets[:0]
 }
 
 func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64) {
+	idx := agg.bucketIndex(timeNanos)
+	agg.buckets[idx].values = append(agg.buckets[idx].values, value)
+}
+
+// addSketch merges the registers of a count distinct sketch into the bucket,
+// keeping the maximum of each register so the sketches produced by all the
+// elements are forwarded as a single sketch.
+func (agg *forwardedAggregationWithKey) addSketch(timeNanos int64, registers []uint8) {
+	if len(registers) == 0 {
+		return
+	}
+	idx := agg.bucketIndex(timeNanos)
+	sketch := agg.buckets[idx].countDistinctSketch
+	if len(sketch) != len(registers) {
+		// NB: the registers are owned by the writer once written.
+		agg.buckets[idx].countDistinctSketch = registers
+		return
+	}
+	for i, value := range registers {
+		if sketch[i] < value {
+			sketch[i] = value
+		}
+	}
+}
+
+// bucketIndex returns the index of the bucket for the given time, creating
+// the bucket if it doesn't exist.
+func (agg *forwardedAggregationWithKey) bucketIndex(timeNanos int64) int {
 	for i := 0; i < len(agg.buckets); i++ {
 		if agg.buckets[i].timeNanos == timeNanos {
-			agg.buckets[i].values = append(agg.buckets[i].values, value)
-			return
+			return i
 		}
 	}
 	var values []float64
@@ -299,12 +334,12 @@ func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64) {
 	} else {
 		values = make([]float64, 0, initialValueArrayCapacity)
 	}
-	values = append(values, value)
 	bucket := forwardedAggregationBucket{
 		timeNanos: timeNanos,
 		values:    values,
 	}
 	agg.buckets = append(agg.buckets, bucket)
+	return len(agg.buckets) - 1
 }
 
 type forwardedAggregationMetrics struct {
@@ -335,10 +370,11 @@ type forwardedAggregation struct {
 	shard      uint32
 	client     client.AdminClient
 
-	byKey    []forwardedAggregationWithKey
-	metrics  *forwardedAggregationMetrics
-	writeFn  writeForwardedMetricFn
-	onDoneFn onForwardedAggregationDoneFn
+	byKey         []forwardedAggregationWithKey
+	metrics       *forwardedAggregationMetrics
+	writeFn       writeForwardedMetricFn
+	writeSketchFn writeForwardedSketchFn
+	onDoneFn      onForwardedAggregationDoneFn
 }
 
 func newForwardedAggregation(
@@ -357,6 +393,7 @@ func newForwardedAggregation(
 		metrics:    fm,
 	}
 	agg.writeFn = agg.write
+	agg.writeSketchFn = agg.writeSketch
 	agg.onDoneFn = agg.onDone
 	return agg
 }
@@ -365,6 +402,10 @@ func (agg *forwardedAggregation) writeForwardedMetricFn() writeForwardedMetricFn
 	return agg.writeFn
 }
 
+func (agg *forwardedAggregation) writeForwardedSketchFn() writeForwardedSketchFn {
+	return agg.writeSketchFn
+}
+
 func (agg *forwardedAggregation) onAggregationKeyDoneFn() onForwardedAggregationDoneFn {
 	return agg.onDoneFn
 }
@@ -423,6 +464,16 @@ func (agg *forwardedAggregation) write(
 	agg.metrics.write.Inc(1)
 }
 
+func (agg *forwardedAggregation) writeSketch(
+	key aggregationKey,
+	timeNanos int64,
+	registers []uint8,
+) {
+	idx := agg.index(key)
+	agg.byKey[idx].addSketch(timeNanos, registers)
+	agg.metrics.write.Inc(1)
+}
+
 func (agg *forwardedAggregation) onDone(key aggregationKey) error {
 	idx := agg.index(key)
 	agg.byKey[idx].currRefCnt++
@@ -439,17 +490,19 @@ func (agg *forwardedAggregation) onDone(key aggregationKey) error {
 				Pipeline:          key.pipeline,
 				SourceID:          agg.shard,
 				NumForwardedTimes: key.numForwardedTimes,
+				RollupRuleID:      key.rollupRuleID,
 			}
 		)
 		for _, b := range agg.byKey[idx].buckets {
-			if len(b.values) == 0 {
+			if len(b.values) == 0 && len(b.countDistinctSketch) == 0 {
 				continue
 			}
 			metric := aggregated.ForwardedMetric{
-				Type:      agg.metricType,
-				ID:        agg.metricID,
-				TimeNanos: b.timeNanos,
-				Values:    b.values,
+				Type:                agg.metricType,
+				ID:                  agg.metricID,
+				TimeNanos:           b.timeNanos,
+				Values:              b.values,
+				CountDistinctSketch: b.countDistinctSketch,
 			}
 			if err := agg.client.WriteForwarded(metric, meta); err != nil {
 				multiErr = multiErr.Add(err)
@@ -57,7 +57,7 @@ func TestForwardedWriterRegisterWriterClosed(t *testing.T) {
 	)
 	w.Close()
 
-	_, _, err := w.Register(mt, mid, aggKey)
+	_, _, _, err := w.Register(mt, mid, aggKey)
 	require.Equal(t, errForwardedWriterClosed, err)
 }
 
@@ -74,7 +74,7 @@ func TestForwardedWriterRegisterNewAggregation(t *testing.T) {
 	)
 
 	// Validate that no error is returned.
-	writeFn, onDoneFn, err := w.Register(mt, mid, aggKey)
+	writeFn, _, onDoneFn, err := w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 	require.NotNil(t, writeFn)
 	require.NotNil(t, onDoneFn)
@@ -150,7 +150,7 @@ func TestForwardedWriterRegisterExistingAggregation(t *testing.T) {
 	)
 
 	// Register an aggregation first.
-	writeFn, onDoneFn, err := w.Register(mt, mid, aggKey)
+	writeFn, _, onDoneFn, err := w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 	require.NotNil(t, writeFn)
 	require.NotNil(t, onDoneFn)
@@ -165,7 +165,7 @@ func TestForwardedWriterRegisterExistingAggregation(t *testing.T) {
 	require.Equal(t, 1, agg.byKey[0].totalRefCnt)
 
 	// Register the same aggregation again.
-	writeFn, onDoneFn, err = w.Register(mt, mid, aggKey)
+	writeFn, _, onDoneFn, err = w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 	require.NotNil(t, writeFn)
 	require.NotNil(t, onDoneFn)
@@ -217,7 +217,7 @@ func TestForwardedWriterUnregisterAggregationKeyNotFound(t *testing.T) {
 	)
 
 	// Register an aggregation first.
-	_, _, err := w.Register(mt, mid, aggKey)
+	_, _, _, err := w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 
 	// Unregister a different aggregation key.
@@ -239,13 +239,13 @@ func TestForwardedWriterUnregister(t *testing.T) {
 	)
 
 	// Register an aggregation first.
-	_, _, err := w.Register(mt, mid, aggKey)
+	_, _, _, err := w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 	fw := w.(*forwardedWriter)
 	require.Equal(t, 1, len(fw.aggregations))
 
 	// Register the aggregation again.
-	_, _, err = w.Register(mt, mid, aggKey)
+	_, _, _, err = w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(fw.aggregations))
 
@@ -272,7 +272,7 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	)
 
 	// Register an aggregation.
-	writeFn, onDoneFn, err := w.Register(mt, mid, aggKey)
+	writeFn, _, onDoneFn, err := w.Register(mt, mid, aggKey)
 	require.NoError(t, err)
 
 	// Write some datapoints.
@@ -281,7 +281,7 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	writeFn(aggKey, 1240, 98.2)
 
 	// Register another aggregation.
-	writeFn2, onDoneFn2, err := w.Register(mt, mid2, aggKey)
+	writeFn2, _, onDoneFn2, err := w.Register(mt, mid2, aggKey)
 	require.NoError(t, err)
 
 	// Write some more datapoints.
@@ -381,6 +381,64 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	require.Equal(t, 0, len(agg.byKey[0].cachedValueArrays))
 }
 
+func TestForwardedWriterWriteSketch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		c      = client.NewMockAdminClient(ctrl)
+		w      = newForwardedWriter(0, c, tally.NoopScope)
+		mt     = metric.CounterType
+		mid    = id.RawID("foo")
+		aggKey = testForwardedWriterAggregationKey
+	)
+
+	// Register the same aggregation for two elements.
+	writeFn, writeSketchFn, onDoneFn, err := w.Register(mt, mid, aggKey)
+	require.NoError(t, err)
+	_, writeSketchFn2, onDoneFn2, err := w.Register(mt, mid, aggKey)
+	require.NoError(t, err)
+
+	// The sketches written by both elements are merged into a single sketch.
+	writeFn(aggKey, 1234, 3.4)
+	writeSketchFn(aggKey, 1234, []uint8{1, 0, 3, 0})
+	writeSketchFn2(aggKey, 1234, []uint8{2, 0, 1, 0})
+	writeSketchFn2(aggKey, 1240, []uint8{0, 4, 0, 0})
+	writeSketchFn2(aggKey, 1250, nil)
+
+	expectedMetric1 := aggregated.ForwardedMetric{
+		Type:                mt,
+		ID:                  mid,
+		TimeNanos:           1234,
+		Values:              []float64{3.4},
+		CountDistinctSketch: []byte{2, 0, 3, 0},
+	}
+	expectedMetric2 := aggregated.ForwardedMetric{
+		Type:                mt,
+		ID:                  mid,
+		TimeNanos:           1240,
+		Values:              []float64{},
+		CountDistinctSketch: []byte{0, 4, 0, 0},
+	}
+	expectedMeta := metadata.ForwardMetadata{
+		AggregationID:     aggregation.MustCompressTypes(aggregation.Count),
+		StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+		SourceID:          0,
+		NumForwardedTimes: 1,
+	}
+	c.EXPECT().WriteForwarded(expectedMetric1, expectedMeta).Return(nil)
+	c.EXPECT().WriteForwarded(expectedMetric2, expectedMeta).Return(nil)
+	require.NoError(t, onDoneFn(aggKey))
+	require.NoError(t, onDoneFn2(aggKey))
+
+	// Sketches are not kept across write cycles.
+	w.Prepare()
+	agg := w.(*forwardedWriter).aggregations[newIDKey(mt, mid)]
+	require.Equal(t, 0, len(agg.byKey[0].buckets))
+	writeFn(aggKey, 1234, 3.4)
+	require.Nil(t, agg.byKey[0].buckets[0].countDistinctSketch)
+}
+
 func TestForwardedWriterCloseWriterClosed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
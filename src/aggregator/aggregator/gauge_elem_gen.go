@@ -43,9 +43,11 @@ import (
 type lockedGaugeAggregation struct {
 	sync.Mutex
 
-	closed      bool
-	sourcesSeen *bitset.BitSet
-	aggregation gaugeAggregation
+	closed              bool
+	dirty               bool // whether values were added since the aggregation was last flushed
+	sourcesSeen         *bitset.BitSet
+	overflowSourcesSeen *overflowSourceSet
+	aggregation         gaugeAggregation
 }
 
 type timedGauge struct {
@@ -64,7 +66,12 @@ type GaugeElem struct {
 	gaugeElemBase
 
 	values              []timedGauge // metric aggregations sorted by time in ascending order
+	flushed             []timedGauge // flushed aggregations kept for corrections within the allowed lateness
 	toConsume           []timedGauge // small buffer to avoid memory allocations during consumption
+	toCorrect           []timedGauge // small buffer to avoid memory allocations during corrections
+	toExpire            []timedGauge // small buffer to avoid memory allocations during expiry
+	windowed            []timedGauge // consumed aggregations within the sliding window sorted by time in ascending order
+	nextStepAtNanos     int64        // start time of the next sliding window step to flush
 	lastConsumedAtNanos int64        // last consumed at in Unix nanoseconds
 	lastConsumedValues  []float64    // last consumed values
 }
@@ -125,6 +132,7 @@ func (e *GaugeElem) ResetSetData(
 	if err := e.gaugeElemBase.ResetSetData(e.aggTypesOpts, aggTypes, useDefaultAggregation); err != nil {
 		return err
 	}
+	e.nextStepAtNanos = 0
 	// If the pipeline contains derivative transformations, we need to store past
 	// values in order to compute the derivatives.
 	if !e.parsedPipeline.HasDerivativeTransform {
@@ -154,6 +162,7 @@ func (e *GaugeElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) e
 		return errAggregationClosed
 	}
 	lockedAgg.aggregation.AddUnion(mu)
+	lockedAgg.dirty = true
 	lockedAgg.Unlock()
 	return nil
 }
@@ -171,14 +180,20 @@ func (e *GaugeElem) AddValue(timestamp time.Time, value float64) error {
 		return errAggregationClosed
 	}
 	lockedAgg.aggregation.Add(value)
+	lockedAgg.dirty = true
 	lockedAgg.Unlock()
 	return nil
 }
 
-// AddUnique adds a metric value from a given source at a given timestamp.
-// If previous values from the same source have already been added to the
-// same aggregation, the incoming value is discarded.
-func (e *GaugeElem) AddUnique(timestamp time.Time, values []float64, sourceID uint32) error {
+// AddUnique adds metric values and a count distinct sketch from a given source
+// at a given timestamp. If previous values from the same source have already
+// been added to the same aggregation, the incoming values are discarded.
+func (e *GaugeElem) AddUnique(
+	timestamp time.Time,
+	values []float64,
+	countDistinctSketch []uint8,
+	sourceID uint32,
+) error {
 	alignedStart := timestamp.Truncate(e.sp.Resolution().Window).UnixNano()
 	lockedAgg, err := e.findOrCreate(alignedStart, createAggregationOptions{initSourceSet: true})
 	if err != nil {
@@ -198,13 +213,56 @@ func (e *GaugeElem) AddUnique(timestamp time.Time, values []float64, sourceID ui
 	for _, v := range values {
 		lockedAgg.aggregation.Add(v)
 	}
+	lockedAgg.aggregation.MergeCountDistinctSketch(countDistinctSketch)
+	lockedAgg.dirty = true
+	lockedAgg.Unlock()
+	return nil
+}
+
+// AddUniqueOverflow adds metric values collapsed into an overflow series from
+// a given source and original series at a given timestamp. If previous values
+// from the same source and original series have already been added to the same
+// aggregation, the incoming values are discarded.
+func (e *GaugeElem) AddUniqueOverflow(
+	timestamp time.Time,
+	values []float64,
+	countDistinctSketch []uint8,
+	source overflowSource,
+) error {
+	alignedStart := timestamp.Truncate(e.sp.Resolution().Window).UnixNano()
+	lockedAgg, err := e.findOrCreate(alignedStart, createAggregationOptions{})
+	if err != nil {
+		return err
+	}
+	lockedAgg.Lock()
+	if lockedAgg.closed {
+		lockedAgg.Unlock()
+		return errAggregationClosed
+	}
+	if lockedAgg.overflowSourcesSeen == nil {
+		lockedAgg.overflowSourcesSeen = newOverflowSourceSet(e.opts.OverflowSourceSetCapacity())
+	}
+	if lockedAgg.overflowSourcesSeen.TestAndAdd(source) {
+		lockedAgg.Unlock()
+		return errDuplicateForwardingSource
+	}
+	for _, v := range values {
+		lockedAgg.aggregation.Add(v)
+	}
+	lockedAgg.aggregation.MergeCountDistinctSketch(countDistinctSketch)
+	lockedAgg.dirty = true
 	lockedAgg.Unlock()
 	return nil
 }
 
 // Consume consumes values before a given time and removes them from the element
 // after they are consumed, returning whether the element can be collected after
-// the consumption is completed.
+// the consumption is completed. If the element has an allowed lateness, consumed
+// aggregations are kept until the allowed lateness has passed, and those that
+// received late values since they were flushed are flushed again. If the element
+// has a sliding window, consumed aggregations are kept until they slide out of
+// the window, and the aggregation of the values in the window is flushed at
+// every step instead.
 // NB: Consume is not thread-safe and must be called within a single goroutine
 // to avoid race conditions.
 func (e *GaugeElem) Consume(
@@ -213,12 +271,15 @@ func (e *GaugeElem) Consume(
 	timestampNanosFn timestampNanosFn,
 	flushLocalFn flushLocalMetricFn,
 	flushForwardedFn flushForwardedMetricFn,
+	flushForwardedSketchFn flushForwardedSketchFn,
 	onForwardedFlushedFn onForwardingElemFlushedFn,
 ) bool {
 	resolution := e.sp.Resolution().Window
+	e.consumeLock.Lock()
 	e.Lock()
 	if e.closed {
 		e.Unlock()
+		e.consumeLock.Unlock()
 		return false
 	}
 	idx := 0
@@ -229,10 +290,35 @@ func (e *GaugeElem) Consume(
 		}
 		idx++
 	}
+	e.toExpire = e.toExpire[:0]
+	e.toCorrect = e.toCorrect[:0]
+	if len(e.flushed) > 0 {
+		// Expire the flushed aggregations that can no longer receive late values.
+		expireBeforeNanos := targetNanos - resolution.Nanoseconds() - e.allowedLateness.Nanoseconds()
+		n := 0
+		for i := range e.flushed {
+			if e.flushed[i].startAtNanos <= expireBeforeNanos {
+				e.toExpire = append(e.toExpire, e.flushed[i])
+				continue
+			}
+			e.flushed[n] = e.flushed[i]
+			n++
+		}
+		for i := n; i < len(e.flushed); i++ {
+			e.flushed[i].Reset()
+		}
+		e.flushed = e.flushed[:n]
+		e.toCorrect = append(e.toCorrect, e.flushed...)
+	}
 	e.toConsume = e.toConsume[:0]
 	if idx > 0 {
 		// Shift remaining values to the left and shrink the values slice.
 		e.toConsume = append(e.toConsume, e.values[:idx]...)
+		// NB: the aggregations are kept as flushed before they are processed so
+		// that late values never create a new aggregation for the same window.
+		if e.allowedLateness > 0 && !e.isSlidingWindow() {
+			e.flushed = append(e.flushed, e.values[:idx]...)
+		}
 		n := copy(e.values[0:], e.values[idx:])
 		// Clear out the invalid items to avoid holding references to objects
 		// for reduced GC overhead..
@@ -244,28 +330,72 @@ func (e *GaugeElem) Consume(
 	canCollect := len(e.values) == 0 && e.tombstoned
 	e.Unlock()
 
+	// Process the flushed aggregations that have received late values.
+	for i := range e.toCorrect {
+		e.toCorrect[i].lockedAgg.Lock()
+		if e.toCorrect[i].lockedAgg.dirty && !e.toCorrect[i].lockedAgg.closed {
+			// NB: corrections are never derivatives, so the last consumed time is
+			// left untouched.
+			lastConsumedAtNanos := e.lastConsumedAtNanos
+			timeNanos := timestampNanosFn(e.toCorrect[i].startAtNanos, resolution)
+			e.processValueWithAggregationLock(
+				timeNanos,
+				e.toCorrect[i].lockedAgg,
+				flushLocalFn,
+				flushForwardedFn,
+				flushForwardedSketchFn,
+			)
+			e.lastConsumedAtNanos = lastConsumedAtNanos
+			e.toCorrect[i].lockedAgg.dirty = false
+		}
+		e.toCorrect[i].lockedAgg.Unlock()
+		e.toCorrect[i].Reset()
+	}
+
+	if e.isSlidingWindow() {
+		e.consumeSlidingWindow(
+			targetNanos,
+			isEarlierThanFn,
+			timestampNanosFn,
+			flushLocalFn,
+			flushForwardedFn,
+			flushForwardedSketchFn,
+		)
+		// Keep the element around until the window no longer has any values.
+		canCollect = canCollect && len(e.windowed) == 0
+	}
+
 	// Process the aggregations that are ready for consumption.
 	for i := range e.toConsume {
 		timeNanos := timestampNanosFn(e.toConsume[i].startAtNanos, resolution)
 		e.toConsume[i].lockedAgg.Lock()
-		e.processValueWithAggregationLock(timeNanos, e.toConsume[i].lockedAgg, flushLocalFn, flushForwardedFn)
-		// Closes the aggregation object after it's processed.
-		e.toConsume[i].lockedAgg.closed = true
-		e.toConsume[i].lockedAgg.aggregation.Close()
-		if e.toConsume[i].lockedAgg.sourcesSeen != nil {
-			e.cachedSourceSetsLock.Lock()
-			// This is to make sure there aren't too many cached source sets taking up
-			// too much space.
-			if len(e.cachedSourceSets) < e.opts.MaxNumCachedSourceSets() {
-				e.cachedSourceSets = append(e.cachedSourceSets, e.toConsume[i].lockedAgg.sourcesSeen)
-			}
-			e.cachedSourceSetsLock.Unlock()
-			e.toConsume[i].lockedAgg.sourcesSeen = nil
+		e.processValueWithAggregationLock(
+			timeNanos,
+			e.toConsume[i].lockedAgg,
+			flushLocalFn,
+			flushForwardedFn,
+			flushForwardedSketchFn,
+		)
+		e.toConsume[i].lockedAgg.dirty = false
+		if e.allowedLateness == 0 {
+			// Closes the aggregation object after it's processed.
+			e.closeAggregationWithLock(e.toConsume[i].lockedAgg)
 		}
 		e.toConsume[i].lockedAgg.Unlock()
 		e.toConsume[i].Reset()
 	}
 
+	// Close the flushed aggregations that have expired.
+	for i := range e.toExpire {
+		e.toExpire[i].lockedAgg.Lock()
+		if !e.toExpire[i].lockedAgg.closed {
+			e.closeAggregationWithLock(e.toExpire[i].lockedAgg)
+		}
+		e.toExpire[i].lockedAgg.Unlock()
+		e.toExpire[i].Reset()
+	}
+	e.consumeLock.Unlock()
+
 	if e.parsedPipeline.HasRollup {
 		forwardedAggregationKey, _ := e.ForwardedAggregationKey()
 		onForwardedFlushedFn(e.onForwardedAggregationWrittenFn, forwardedAggregationKey)
@@ -274,8 +404,258 @@ func (e *GaugeElem) Consume(
 	return canCollect
 }
 
+// isSlidingWindow returns whether the element flushes sliding windows. Elements
+// forwarding to a rollup always aggregate tumbling windows so the values are
+// only windowed once by the element of the last pipeline stage.
+func (e *GaugeElem) isSlidingWindow() bool {
+	return e.sp.IsSlidingWindow() && !e.parsedPipeline.HasRollup
+}
+
+// consumeSlidingWindow adds the consumed aggregations to the sliding window,
+// and flushes the aggregation of the values in the window for every step up
+// to the target time until the window no longer has any values.
+func (e *GaugeElem) consumeSlidingWindow(
+	targetNanos int64,
+	isEarlierThanFn isEarlierThanFn,
+	timestampNanosFn timestampNanosFn,
+	flushLocalFn flushLocalMetricFn,
+	flushForwardedFn flushForwardedMetricFn,
+	flushForwardedSketchFn flushForwardedSketchFn,
+) {
+	var (
+		step        = e.sp.Resolution().Window
+		stepNanos   = step.Nanoseconds()
+		windowNanos = e.sp.SlidingWindow().Nanoseconds()
+	)
+	for i := range e.toConsume {
+		e.addToSlidingWindow(e.toConsume[i])
+		e.toConsume[i].Reset()
+	}
+	e.toConsume = e.toConsume[:0]
+	if len(e.windowed) > 0 && e.nextStepAtNanos < e.windowed[0].startAtNanos {
+		e.nextStepAtNanos = e.windowed[0].startAtNanos
+	}
+
+	for len(e.windowed) > 0 && isEarlierThanFn(e.nextStepAtNanos, step, targetNanos) {
+		stepAtNanos := e.nextStepAtNanos
+		e.nextStepAtNanos += stepNanos
+
+		// Close the aggregations that have slid out of the window.
+		n := 0
+		for n < len(e.windowed) && e.windowed[n].startAtNanos <= stepAtNanos-windowNanos {
+			e.windowed[n].lockedAgg.Lock()
+			e.closeAggregationWithLock(e.windowed[n].lockedAgg)
+			e.windowed[n].lockedAgg.Unlock()
+			n++
+		}
+		if n > 0 {
+			remaining := copy(e.windowed, e.windowed[n:])
+			for i := remaining; i < len(e.windowed); i++ {
+				e.windowed[i].Reset()
+			}
+			e.windowed = e.windowed[:remaining]
+		}
+		if len(e.windowed) == 0 {
+			break
+		}
+		if e.windowed[0].startAtNanos > stepAtNanos {
+			// Skip the steps whose window has not received any values.
+			e.nextStepAtNanos = e.windowed[0].startAtNanos
+			continue
+		}
+
+		windowAgg := lockedGaugeAggregation{aggregation: e.NewAggregation(e.opts, e.aggOpts)}
+		for i := range e.windowed {
+			if e.windowed[i].startAtNanos > stepAtNanos {
+				break
+			}
+			e.windowed[i].lockedAgg.Lock()
+			windowAgg.aggregation.Merge(&e.windowed[i].lockedAgg.aggregation)
+			e.windowed[i].lockedAgg.Unlock()
+		}
+		timeNanos := timestampNanosFn(stepAtNanos, step)
+		e.processValueWithAggregationLock(
+			timeNanos,
+			&windowAgg,
+			flushLocalFn,
+			flushForwardedFn,
+			flushForwardedSketchFn,
+		)
+		windowAgg.aggregation.Close()
+	}
+}
+
+// addToSlidingWindow adds a consumed aggregation to the sliding window. An
+// aggregation for a window that was already consumed, which happens when values
+// arrive late, is merged into the existing aggregation for the same window.
+func (e *GaugeElem) addToSlidingWindow(ta timedGauge) {
+	idx := len(e.windowed)
+	for idx > 0 && e.windowed[idx-1].startAtNanos >= ta.startAtNanos {
+		idx--
+	}
+	if idx < len(e.windowed) && e.windowed[idx].startAtNanos == ta.startAtNanos {
+		existing := e.windowed[idx].lockedAgg
+		existing.Lock()
+		ta.lockedAgg.Lock()
+		existing.aggregation.Merge(&ta.lockedAgg.aggregation)
+		e.closeAggregationWithLock(ta.lockedAgg)
+		ta.lockedAgg.Unlock()
+		existing.Unlock()
+		return
+	}
+	e.windowed = append(e.windowed, timedGauge{})
+	copy(e.windowed[idx+1:], e.windowed[idx:])
+	e.windowed[idx] = ta
+}
+
+// closeAggregationWithLock closes a consumed aggregation and returns its source
+// set to the cache.
+func (e *GaugeElem) closeAggregationWithLock(lockedAgg *lockedGaugeAggregation) {
+	lockedAgg.closed = true
+	lockedAgg.aggregation.Close()
+	lockedAgg.overflowSourcesSeen = nil
+	if lockedAgg.sourcesSeen == nil {
+		return
+	}
+	e.cachedSourceSetsLock.Lock()
+	// This is to make sure there aren't too many cached source sets taking up
+	// too much space.
+	if len(e.cachedSourceSets) < e.opts.MaxNumCachedSourceSets() {
+		e.cachedSourceSets = append(e.cachedSourceSets, lockedAgg.sourcesSeen)
+	}
+	e.cachedSourceSetsLock.Unlock()
+	lockedAgg.sourcesSeen = nil
+}
+
+// Snapshot returns a snapshot of the aggregations that have not been consumed
+// yet and of the sliding window, or false if the element is closed.
+func (e *GaugeElem) Snapshot() (elemSnapshot, bool) {
+	e.consumeLock.Lock()
+	e.RLock()
+	if e.closed {
+		e.RUnlock()
+		e.consumeLock.Unlock()
+		return elemSnapshot{}, false
+	}
+	values := make([]timedGauge, len(e.values))
+	copy(values, e.values)
+	e.RUnlock()
+
+	// NB: the consume lock guards the aggregations in the sliding window.
+	snapshot := elemSnapshot{
+		values:              e.snapshotTimedAggregations(values),
+		windowed:            e.snapshotTimedAggregations(e.windowed),
+		nextStepAtNanos:     e.nextStepAtNanos,
+		lastConsumedAtNanos: e.lastConsumedAtNanos,
+		lastConsumedValues:  append([]float64(nil), e.lastConsumedValues...),
+	}
+	e.consumeLock.Unlock()
+	return snapshot, true
+}
+
+// Restore replaces the aggregations in the element and in the sliding window
+// with those in the snapshot.
+func (e *GaugeElem) Restore(snapshot elemSnapshot) error {
+	e.consumeLock.Lock()
+	e.Lock()
+	if e.closed {
+		e.Unlock()
+		e.consumeLock.Unlock()
+		return errElemClosed
+	}
+	for idx := range e.values {
+		e.values[idx].lockedAgg.closed = true
+		e.values[idx].lockedAgg.aggregation.Close()
+		e.values[idx].Reset()
+	}
+	e.values = e.values[:0]
+	for _, v := range snapshot.values {
+		e.values = append(e.values, e.restoreTimedAggregation(v))
+	}
+	for idx := range e.windowed {
+		e.windowed[idx].lockedAgg.closed = true
+		e.windowed[idx].lockedAgg.aggregation.Close()
+		e.windowed[idx].Reset()
+	}
+	e.windowed = e.windowed[:0]
+	for _, v := range snapshot.windowed {
+		e.windowed = append(e.windowed, e.restoreTimedAggregation(v))
+	}
+	e.nextStepAtNanos = snapshot.nextStepAtNanos
+	e.lastConsumedAtNanos = snapshot.lastConsumedAtNanos
+	if len(snapshot.lastConsumedValues) == len(e.lastConsumedValues) {
+		copy(e.lastConsumedValues, snapshot.lastConsumedValues)
+	}
+	e.Unlock()
+	e.consumeLock.Unlock()
+	return nil
+}
+
+// snapshotTimedAggregations returns the snapshots of the aggregations that
+// are not closed.
+func (e *GaugeElem) snapshotTimedAggregations(values []timedGauge) []timedAggregationSnapshot {
+	snapshots := make([]timedAggregationSnapshot, 0, len(values))
+	for i := range values {
+		values[i].lockedAgg.Lock()
+		if values[i].lockedAgg.closed {
+			values[i].lockedAgg.Unlock()
+			continue
+		}
+		var (
+			sourcesSeen            []uint64
+			overflowSourcesSeen    []uint64
+			numOverflowSourcesSeen int
+		)
+		if values[i].lockedAgg.sourcesSeen != nil {
+			sourcesSeen = append([]uint64(nil), values[i].lockedAgg.sourcesSeen.Bytes()...)
+		}
+		if set := values[i].lockedAgg.overflowSourcesSeen; set != nil {
+			overflowSourcesSeen = append([]uint64(nil), set.bits.Bytes()...)
+			numOverflowSourcesSeen = set.numAdded
+		}
+		snapshots = append(snapshots, timedAggregationSnapshot{
+			startAtNanos:           values[i].startAtNanos,
+			sourcesSeen:            sourcesSeen,
+			overflowSourcesSeen:    overflowSourcesSeen,
+			numOverflowSourcesSeen: numOverflowSourcesSeen,
+			aggregation:            values[i].lockedAgg.aggregation.Snapshot(),
+		})
+		values[i].lockedAgg.Unlock()
+	}
+	return snapshots
+}
+
+// restoreTimedAggregation creates an aggregation from its snapshot.
+func (e *GaugeElem) restoreTimedAggregation(v timedAggregationSnapshot) timedGauge {
+	var sourcesSeen *bitset.BitSet
+	if v.sourcesSeen != nil {
+		sourcesSeen = bitset.From(append([]uint64(nil), v.sourcesSeen...))
+	}
+	var overflowSourcesSeen *overflowSourceSet
+	if v.overflowSourcesSeen != nil {
+		overflowSourcesSeen = &overflowSourceSet{
+			bits:     bitset.From(append([]uint64(nil), v.overflowSourcesSeen...)),
+			numAdded: v.numOverflowSourcesSeen,
+		}
+	}
+	aggregation := e.NewAggregation(e.opts, e.aggOpts)
+	aggregation.Restore(v.aggregation)
+	return timedGauge{
+		startAtNanos: v.startAtNanos,
+		lockedAgg: &lockedGaugeAggregation{
+			sourcesSeen:         sourcesSeen,
+			overflowSourcesSeen: overflowSourcesSeen,
+			aggregation:         aggregation,
+		},
+	}
+}
+
 // Close closes the element.
 func (e *GaugeElem) Close() {
+	// NB: the consume lock guards the aggregations in the sliding window.
+	e.consumeLock.Lock()
+	defer e.consumeLock.Unlock()
+
 	e.Lock()
 	if e.closed {
 		e.Unlock()
@@ -285,6 +665,7 @@ func (e *GaugeElem) Close() {
 	e.id = nil
 	e.parsedPipeline = parsedPipeline{}
 	e.writeForwardedMetricFn = nil
+	e.writeForwardedSketchFn = nil
 	e.onForwardedAggregationWrittenFn = nil
 	for idx := range e.cachedSourceSets {
 		e.cachedSourceSets[idx] = nil
@@ -293,11 +674,28 @@ func (e *GaugeElem) Close() {
 	for idx := range e.values {
 		// Close the underlying aggregation objects.
 		e.values[idx].lockedAgg.sourcesSeen = nil
+		e.values[idx].lockedAgg.overflowSourcesSeen = nil
 		e.values[idx].lockedAgg.aggregation.Close()
 		e.values[idx].Reset()
 	}
 	e.values = e.values[:0]
+	for idx := range e.flushed {
+		e.flushed[idx].lockedAgg.sourcesSeen = nil
+		e.flushed[idx].lockedAgg.overflowSourcesSeen = nil
+		e.flushed[idx].lockedAgg.aggregation.Close()
+		e.flushed[idx].Reset()
+	}
+	e.flushed = e.flushed[:0]
+	for idx := range e.windowed {
+		e.windowed[idx].lockedAgg.sourcesSeen = nil
+		e.windowed[idx].lockedAgg.overflowSourcesSeen = nil
+		e.windowed[idx].lockedAgg.aggregation.Close()
+		e.windowed[idx].Reset()
+	}
+	e.windowed = e.windowed[:0]
 	e.toConsume = e.toConsume[:0]
+	e.toCorrect = e.toCorrect[:0]
+	e.toExpire = e.toExpire[:0]
 	e.lastConsumedValues = e.lastConsumedValues[:0]
 	e.gaugeElemBase.Close()
 	aggTypesPool := e.aggTypesOpts.TypesPool()
@@ -327,6 +725,10 @@ func (e *GaugeElem) findOrCreate(
 		e.RUnlock()
 		return agg, nil
 	}
+	if agg, found := e.flushedWithLock(alignedStart); found {
+		e.RUnlock()
+		return agg, nil
+	}
 	e.RUnlock()
 
 	e.Lock()
@@ -340,6 +742,10 @@ func (e *GaugeElem) findOrCreate(
 		e.Unlock()
 		return agg, nil
 	}
+	if agg, found := e.flushedWithLock(alignedStart); found {
+		e.Unlock()
+		return agg, nil
+	}
 
 	// If not found, create a new aggregation.
 	numValues := len(e.values)
@@ -400,17 +806,35 @@ func (e *GaugeElem) indexOfWithLock(alignedStart int64) (int, bool) {
 	return left, false
 }
 
+// flushedWithLock returns the flushed aggregation for the given start time
+// if it is still kept for corrections.
+func (e *GaugeElem) flushedWithLock(alignedStart int64) (*lockedGaugeAggregation, bool) {
+	for i := len(e.flushed) - 1; i >= 0; i-- {
+		if e.flushed[i].startAtNanos == alignedStart {
+			return e.flushed[i].lockedAgg, true
+		}
+	}
+	return nil, false
+}
+
 func (e *GaugeElem) processValueWithAggregationLock(
 	timeNanos int64,
 	lockedAgg *lockedGaugeAggregation,
 	flushLocalFn flushLocalMetricFn,
 	flushForwardedFn flushForwardedMetricFn,
+	flushForwardedSketchFn flushForwardedSketchFn,
 ) {
 	var (
 		transformations  = e.parsedPipeline.Transformations
 		discardNaNValues = e.opts.DiscardNaNAggregatedValues()
 	)
 	for aggTypeIdx, aggType := range e.aggTypes {
+		if aggType == maggregation.CountDistinct && e.parsedPipeline.HasRollup {
+			// NB: the sketch is forwarded instead of the estimate so the distinct
+			// values can be counted across all the sources of the rollup.
+			e.forwardCountDistinctSketchWithAggregationLock(timeNanos, lockedAgg, flushForwardedSketchFn)
+			continue
+		}
 		value := lockedAgg.aggregation.ValueOf(aggType)
 		for i := 0; i < transformations.Len(); i++ {
 			transformType := transformations.At(i).Transformation.Type
@@ -448,3 +872,16 @@ func (e *GaugeElem) processValueWithAggregationLock(
 	}
 	e.lastConsumedAtNanos = timeNanos
 }
+
+func (e *GaugeElem) forwardCountDistinctSketchWithAggregationLock(
+	timeNanos int64,
+	lockedAgg *lockedGaugeAggregation,
+	flushForwardedSketchFn flushForwardedSketchFn,
+) {
+	registers := lockedAgg.aggregation.CountDistinctSketch().Registers()
+	if registers == nil {
+		return
+	}
+	forwardedAggregationKey, _ := e.ForwardedAggregationKey()
+	flushForwardedSketchFn(e.writeForwardedSketchFn, forwardedAggregationKey, timeNanos, registers)
+}
@@ -33,15 +33,17 @@ import (
 	"github.com/m3db/m3/src/msg/producer"
 	"github.com/m3db/m3/src/msg/producer/config"
 	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
 	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/retry"
 
 	"go.uber.org/zap"
 )
 
 var (
-	errNoHandlerConfiguration                   = errors.New("no handler configuration")
-	errNoDynamicOrStaticBackendConfiguration    = errors.New("neither dynamic nor static backend was configured")
-	errBothDynamicAndStaticBackendConfiguration = errors.New("both dynamic and static backend were configured")
+	errNoHandlerConfiguration       = errors.New("no handler configuration")
+	errNoBackendConfiguration       = errors.New("none of static, dynamic or prometheus backend was configured")
+	errMultipleBackendConfiguration = errors.New("more than one of static, dynamic and prometheus backend were configured")
 )
 
 // FlushHandlerConfiguration configures flush handlers.
@@ -105,6 +107,9 @@ type flushHandlerConfiguration struct {
 
 	// DynamicBackend configures the dynamic backend.
 	DynamicBackend *dynamicBackendConfiguration `yaml:"dynamicBackend"`
+
+	// PrometheusBackend configures the prometheus remote write backend.
+	PrometheusBackend *prometheusBackendConfiguration `yaml:"prometheusBackend"`
 }
 
 func (c flushHandlerConfiguration) newHandler(
@@ -120,6 +125,9 @@ func (c flushHandlerConfiguration) newHandler(
 			instrumentOpts,
 		)
 	}
+	if c.PrometheusBackend != nil {
+		return c.PrometheusBackend.newPrometheusHandler(instrumentOpts)
+	}
 	switch c.StaticBackend.Type {
 	case blackholeType:
 		return NewBlackholeHandler(), nil
@@ -131,11 +139,21 @@ func (c flushHandlerConfiguration) newHandler(
 }
 
 func (c flushHandlerConfiguration) Validate() error {
-	if c.StaticBackend == nil && c.DynamicBackend == nil {
-		return errNoDynamicOrStaticBackendConfiguration
+	var numBackends int
+	if c.StaticBackend != nil {
+		numBackends++
+	}
+	if c.DynamicBackend != nil {
+		numBackends++
+	}
+	if c.PrometheusBackend != nil {
+		numBackends++
+	}
+	if numBackends == 0 {
+		return errNoBackendConfiguration
 	}
-	if c.StaticBackend != nil && c.DynamicBackend != nil {
-		return errBothDynamicAndStaticBackendConfiguration
+	if numBackends > 1 {
+		return errMultipleBackendConfiguration
 	}
 	return nil
 }
@@ -194,6 +212,102 @@ func (c *dynamicBackendConfiguration) newProtobufHandler(
 	return NewProtobufHandler(p, c.HashType, wOpts), nil
 }
 
+type prometheusBackendConfiguration struct {
+	// Name of the backend.
+	Name string `yaml:"name"`
+
+	// Endpoint is the remote write endpoint.
+	Endpoint string `yaml:"endpoint" validate:"nonzero"`
+
+	// Headers are additional headers sent with each remote write request.
+	Headers map[string]string `yaml:"headers"`
+
+	// HTTPClient configs the http client.
+	HTTPClient *xhttp.HTTPClientOptions `yaml:"httpClient"`
+
+	// Retry configs the retries of failed remote write requests.
+	Retry retry.Configuration `yaml:"retry"`
+
+	// TagIDScheme is the scheme used to decode metric ids into labels.
+	TagIDScheme TagIDScheme `yaml:"tagIDScheme"`
+
+	// Hashing function type used to shard series.
+	HashType sharding.HashType `yaml:"hashType"`
+
+	// NumShards is the number of shards series are distributed across.
+	NumShards int `yaml:"numShards" validate:"min=0"`
+
+	// QueueSize is the number of pending remote write requests per shard.
+	QueueSize int `yaml:"queueSize" validate:"min=0"`
+
+	// MaxBatchSize is the maximum number of series in a remote write request.
+	MaxBatchSize int `yaml:"maxBatchSize" validate:"min=0"`
+
+	// StoragePolicies are the storage policies written to the backend,
+	// all storage policies are written if empty. Exactly one storage policy
+	// must be set unless a storage policy label is set.
+	StoragePolicies []policy.StoragePolicy `yaml:"storagePolicies"`
+
+	// StoragePolicyLabel is the name of the label holding the storage policy
+	// of each series, which tells apart the series of different storage policies.
+	StoragePolicyLabel string `yaml:"storagePolicyLabel"`
+}
+
+func (c *prometheusBackendConfiguration) newPrometheusHandler(
+	instrumentOpts instrument.Options,
+) (Handler, error) {
+	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+		"backend":   c.Name,
+		"component": "prometheus",
+	})
+	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
+	opts := writer.NewPrometheusOptions().
+		SetInstrumentOptions(instrumentOpts).
+		SetEndpoint(c.Endpoint).
+		SetHeaders(c.Headers).
+		SetRetryOptions(c.Retry.NewOptions(scope)).
+		SetStoragePolicies(c.StoragePolicies).
+		SetStoragePolicyLabel([]byte(c.StoragePolicyLabel))
+	if c.HTTPClient != nil {
+		opts = opts.SetHTTPClient(xhttp.NewHTTPClient(*c.HTTPClient))
+	}
+	if c.HashType != "" {
+		shardFn, err := c.HashType.ShardFn()
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetShardFn(shardFn)
+	}
+	if c.NumShards != 0 {
+		opts = opts.SetNumShards(c.NumShards)
+	}
+	if c.QueueSize != 0 {
+		opts = opts.SetQueueSize(c.QueueSize)
+	}
+	if c.MaxBatchSize != 0 {
+		opts = opts.SetMaxBatchSize(c.MaxBatchSize)
+	}
+	switch c.TagIDScheme {
+	case "", m3TagIDScheme:
+	case serializeTagIDScheme:
+		nameAndTagsFn, sortedTagIteratorFn := newSerializeTagFns(instrumentOpts)
+		opts = opts.
+			SetNameAndTagsFn(nameAndTagsFn).
+			SetSortedTagIteratorFn(sortedTagIteratorFn)
+	default:
+		return nil, fmt.Errorf("unknown tag id scheme %v", c.TagIDScheme)
+	}
+
+	client, err := writer.NewPrometheusClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	instrumentOpts.Logger().Info("created flush handler with prometheus remote write",
+		zap.String("name", c.Name),
+		zap.String("endpoint", c.Endpoint))
+	return NewPrometheusHandler(client, opts), nil
+}
+
 type storagePolicyFilterConfiguration struct {
 	ServiceID       services.ServiceIDConfiguration `yaml:"serviceID" validate:"nonzero"`
 	StoragePolicies []policy.StoragePolicy          `yaml:"storagePolicies" validate:"nonzero"`
@@ -23,6 +23,8 @@ package handler
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/x/instrument"
+
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -54,7 +56,7 @@ func TestFlushHandlerConfigurationValidate(t *testing.T) {
 	require.NoError(t, yaml.Unmarshal([]byte(neitherConfigured), &cfg))
 	err := cfg.Validate()
 	require.Error(t, err)
-	require.Equal(t, errNoDynamicOrStaticBackendConfiguration, err)
+	require.Equal(t, errNoBackendConfiguration, err)
 
 	bothConfigured := `
 staticBackend:
@@ -65,5 +67,55 @@ dynamicBackend:
 	require.NoError(t, yaml.Unmarshal([]byte(bothConfigured), &cfg))
 	err = cfg.Validate()
 	require.Error(t, err)
-	require.Equal(t, errBothDynamicAndStaticBackendConfiguration, err)
+	require.Equal(t, errMultipleBackendConfiguration, err)
+}
+
+func TestPrometheusBackendConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	str := `
+prometheusBackend:
+  name: prom
+  endpoint: http://localhost:9090/api/v1/write
+  headers:
+    X-Scope-OrgID: m3
+  tagIDScheme: serialize
+  numShards: 8
+  maxBatchSize: 500
+  retry:
+    maxRetries: 3
+  storagePolicies:
+    - 1m:40d
+`
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "http://localhost:9090/api/v1/write", cfg.PrometheusBackend.Endpoint)
+	require.Equal(t, map[string]string{"X-Scope-OrgID": "m3"}, cfg.PrometheusBackend.Headers)
+	require.Equal(t, serializeTagIDScheme, cfg.PrometheusBackend.TagIDScheme)
+	require.Equal(t, 8, cfg.PrometheusBackend.NumShards)
+	require.Equal(t, 500, cfg.PrometheusBackend.MaxBatchSize)
+	require.Equal(t, 3, cfg.PrometheusBackend.Retry.MaxRetries)
+	require.Equal(t, 1, len(cfg.PrometheusBackend.StoragePolicies))
+
+	handler, err := cfg.newHandler(nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handler.Close()
+
+	bothConfigured := `
+staticBackend:
+  type: blackhole
+prometheusBackend:
+  endpoint: http://localhost:9090/api/v1/write
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(bothConfigured), &cfg))
+	require.Equal(t, errMultipleBackendConfiguration, cfg.Validate())
+
+	invalidScheme := `
+prometheusBackend:
+  endpoint: http://localhost:9090/api/v1/write
+  tagIDScheme: graphite
+`
+	cfg = flushHandlerConfiguration{}
+	require.Error(t, yaml.Unmarshal([]byte(invalidScheme), &cfg))
 }
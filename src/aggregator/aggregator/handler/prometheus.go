@@ -0,0 +1,106 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errNoMetricNameTag = errors.New("no metric name tag")
+	serializeNameTag   = []byte("__name__")
+)
+
+type prometheusHandler struct {
+	client writer.PrometheusClient
+	opts   writer.PrometheusOptions
+}
+
+// NewPrometheusHandler creates a new handler writing aggregated metrics
+// to a Prometheus remote write endpoint.
+func NewPrometheusHandler(
+	client writer.PrometheusClient,
+	opts writer.PrometheusOptions,
+) Handler {
+	return prometheusHandler{
+		client: client,
+		opts:   opts,
+	}
+}
+
+func (h prometheusHandler) NewWriter(scope tally.Scope) (writer.Writer, error) {
+	iOpts := h.opts.InstrumentOptions()
+	return writer.NewPrometheusWriter(
+		h.client,
+		h.opts.SetInstrumentOptions(iOpts.SetMetricsScope(scope)),
+	), nil
+}
+
+func (h prometheusHandler) Close() {
+	h.client.Close()
+}
+
+// newSerializeTagFns returns the functions decoding ids made of tags encoded
+// with the serialize package, where the metric name is the __name__ tag.
+func newSerializeTagFns(
+	instrumentOpts instrument.Options,
+) (id.NameAndTagsFn, id.SortedTagIteratorFn) {
+	scope := instrumentOpts.MetricsScope()
+	tagDecoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(),
+		pool.NewObjectPoolOptions().
+			SetInstrumentOptions(instrumentOpts.
+				SetMetricsScope(scope.SubScope("tag-decoder-pool"))),
+	)
+	tagDecoderPool.Init()
+	iterPool := serialize.NewMetricTagsIteratorPool(
+		tagDecoderPool,
+		pool.NewObjectPoolOptions().
+			SetInstrumentOptions(instrumentOpts.
+				SetMetricsScope(scope.SubScope("metric-tags-iterator-pool"))),
+	)
+	iterPool.Init()
+
+	nameAndTagsFn := func(id []byte) ([]byte, []byte, error) {
+		it := iterPool.Get()
+		it.Reset(id)
+		defer it.Close()
+		name, ok := it.TagValue(serializeNameTag)
+		if !ok {
+			return nil, nil, errNoMetricNameTag
+		}
+		// The tags include the name tag, which the writer skips.
+		return name, id, nil
+	}
+	sortedTagIteratorFn := func(tags []byte) id.SortedTagIterator {
+		it := iterPool.Get()
+		it.Reset(tags)
+		return it
+	}
+	return nameAndTagsFn, sortedTagIteratorFn
+}
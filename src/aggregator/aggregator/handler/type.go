@@ -58,3 +58,47 @@ func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return fmt.Errorf("invalid handler type '%s' valid types are: %s",
 		str, strings.Join(validTypes, ", "))
 }
+
+// TagIDScheme is the scheme used to encode metric ids into tags.
+type TagIDScheme string
+
+// A list of supported tag id schemes.
+const (
+	// m3TagIDScheme encodes ids as m3+<name>+<tag pairs>.
+	m3TagIDScheme TagIDScheme = "m3"
+
+	// serializeTagIDScheme encodes ids as serialized tags including a
+	// __name__ tag, which is the scheme used by the coordinator.
+	serializeTagIDScheme TagIDScheme = "serialize"
+
+	defaultTagIDScheme = m3TagIDScheme
+)
+
+var (
+	validTagIDSchemes = []TagIDScheme{
+		m3TagIDScheme,
+		serializeTagIDScheme,
+	}
+)
+
+// UnmarshalYAML unmarshals YAML into a tag id scheme.
+func (s *TagIDScheme) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*s = defaultTagIDScheme
+		return nil
+	}
+	validSchemes := make([]string, 0, len(validTagIDSchemes))
+	for _, valid := range validTagIDSchemes {
+		if str == string(valid) {
+			*s = valid
+			return nil
+		}
+		validSchemes = append(validSchemes, string(valid))
+	}
+	return fmt.Errorf("invalid tag id scheme '%s' valid schemes are: %s",
+		str, strings.Join(validSchemes, ", "))
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	initialPrometheusLabelsCapacity = 16
+)
+
+var (
+	prometheusMetricNameLabel = []byte("__name__")
+)
+
+type prometheusWriterMetrics struct {
+	writerClosed  tally.Counter
+	writeSuccess  tally.Counter
+	decodeErrors  tally.Counter
+	filtered      tally.Counter
+	enqueueErrors tally.Counter
+}
+
+func newPrometheusWriterMetrics(scope tally.Scope) prometheusWriterMetrics {
+	return prometheusWriterMetrics{
+		writerClosed:  scope.Counter("writer-closed"),
+		writeSuccess:  scope.Counter("write-success"),
+		decodeErrors:  scope.Counter("decode-errors"),
+		filtered:      scope.Counter("filtered"),
+		enqueueErrors: scope.Counter("enqueue-errors"),
+	}
+}
+
+// prometheusWriter converts aggregated metrics into Prometheus series and
+// batches them per shard before handing them off to the client.
+type prometheusWriter struct {
+	client              PrometheusClient
+	numShards           uint32
+	shardFn             sharding.ShardFn
+	maxBatchSize        int
+	nameAndTagsFn       id.NameAndTagsFn
+	sortedTagIteratorFn id.SortedTagIteratorFn
+	storagePolicies     []policy.StoragePolicy
+	storagePolicyLabel  []byte
+
+	closed  bool
+	id      []byte
+	pending [][]*prompb.TimeSeries
+	metrics prometheusWriterMetrics
+}
+
+// NewPrometheusWriter creates a new writer writing aggregated metrics to
+// a Prometheus remote write endpoint via the given client.
+func NewPrometheusWriter(
+	client PrometheusClient,
+	opts PrometheusOptions,
+) Writer {
+	numShards := client.NumShards()
+	return &prometheusWriter{
+		client:              client,
+		numShards:           numShards,
+		shardFn:             opts.ShardFn(),
+		maxBatchSize:        opts.MaxBatchSize(),
+		nameAndTagsFn:       opts.NameAndTagsFn(),
+		sortedTagIteratorFn: opts.SortedTagIteratorFn(),
+		storagePolicies:     opts.StoragePolicies(),
+		storagePolicyLabel:  sanitizePrometheusName(append([]byte(nil), opts.StoragePolicyLabel()...), false),
+		pending:             make([][]*prompb.TimeSeries, numShards),
+		metrics:             newPrometheusWriterMetrics(opts.InstrumentOptions().MetricsScope()),
+	}
+}
+
+func (w *prometheusWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	if w.closed {
+		w.metrics.writerClosed.Inc(1)
+		return errWriterClosed
+	}
+	if !w.accepts(mp.StoragePolicy) {
+		w.metrics.filtered.Inc(1)
+		return nil
+	}
+	series, err := w.newSeries(mp)
+	if err != nil {
+		w.metrics.decodeErrors.Inc(1)
+		return err
+	}
+
+	w.id = w.id[:0]
+	w.id = append(w.id, mp.Prefix...)
+	w.id = append(w.id, mp.Data...)
+	w.id = append(w.id, mp.Suffix...)
+	shard := w.shardFn(w.id, w.numShards)
+	w.pending[shard] = append(w.pending[shard], series)
+	w.metrics.writeSuccess.Inc(1)
+	if len(w.pending[shard]) < w.maxBatchSize {
+		return nil
+	}
+	return w.enqueue(shard)
+}
+
+func (w *prometheusWriter) Flush() error {
+	multiErr := xerrors.NewMultiError()
+	for shard := range w.pending {
+		if len(w.pending[shard]) == 0 {
+			continue
+		}
+		if err := w.enqueue(uint32(shard)); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (w *prometheusWriter) Close() error {
+	if w.closed {
+		w.metrics.writerClosed.Inc(1)
+		return errWriterClosed
+	}
+	err := w.Flush()
+	// Don't close the client here, it is shared by other writers.
+	w.closed = true
+	return err
+}
+
+func (w *prometheusWriter) accepts(sp policy.StoragePolicy) bool {
+	if len(w.storagePolicies) == 0 {
+		return true
+	}
+	for _, accepted := range w.storagePolicies {
+		if accepted == sp {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *prometheusWriter) enqueue(shard uint32) error {
+	// The client takes ownership of the pending series.
+	series := w.pending[shard]
+	w.pending[shard] = nil
+	if err := w.client.Enqueue(shard, series); err != nil {
+		w.metrics.enqueueErrors.Inc(1)
+		return err
+	}
+	return nil
+}
+
+// newSeries decodes the metric id into a set of labels. The metric name is
+// the id name surrounded by the prefix and the suffix, and the remaining
+// labels are the id tags and the storage policy label if set. All bytes are
+// copied since the series are sent asynchronously after the underlying id may
+// have been reused.
+func (w *prometheusWriter) newSeries(
+	mp aggregated.ChunkedMetricWithStoragePolicy,
+) (*prompb.TimeSeries, error) {
+	name, tags, err := w.nameAndTagsFn(mp.Data)
+	if err != nil {
+		return nil, err
+	}
+	metricName := make([]byte, 0, len(mp.Prefix)+len(name)+len(mp.Suffix))
+	metricName = append(metricName, mp.Prefix...)
+	metricName = append(metricName, name...)
+	metricName = append(metricName, mp.Suffix...)
+
+	labels := make([]*prompb.Label, 0, initialPrometheusLabelsCapacity)
+	labels = append(labels, &prompb.Label{
+		Name:  prometheusMetricNameLabel,
+		Value: sanitizePrometheusName(metricName, true),
+	})
+	hasStoragePolicyLabel := len(w.storagePolicyLabel) > 0
+	if hasStoragePolicyLabel {
+		labels = append(labels, &prompb.Label{
+			Name:  w.storagePolicyLabel,
+			Value: []byte(mp.StoragePolicy.String()),
+		})
+	}
+	it := w.sortedTagIteratorFn(tags)
+	for it.Next() {
+		tagName, tagValue := it.Current()
+		if bytes.Equal(tagName, prometheusMetricNameLabel) {
+			continue
+		}
+		labelName := sanitizePrometheusName(append([]byte(nil), tagName...), false)
+		if hasStoragePolicyLabel && bytes.Equal(labelName, w.storagePolicyLabel) {
+			continue
+		}
+		labels = append(labels, &prompb.Label{
+			Name:  labelName,
+			Value: append([]byte(nil), tagValue...),
+		})
+	}
+	err = it.Err()
+	it.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(prometheusLabelsByNameAsc(labels))
+
+	return &prompb.TimeSeries{
+		Labels: labels,
+		Samples: []*prompb.Sample{
+			{
+				Value:     mp.Value,
+				Timestamp: mp.TimeNanos / int64(time.Millisecond),
+			},
+		},
+	}, nil
+}
+
+// sanitizePrometheusName replaces the characters not allowed in Prometheus
+// metric names (when allowColon is true) or label names with underscores
+// in place, prepending an underscore if the name starts with a digit.
+func sanitizePrometheusName(name []byte, allowColon bool) []byte {
+	for i, c := range name {
+		valid := c == '_' ||
+			(c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9') ||
+			(allowColon && c == ':')
+		if !valid {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		name = append([]byte{'_'}, name...)
+	}
+	return name
+}
+
+type prometheusLabelsByNameAsc []*prompb.Label
+
+func (l prometheusLabelsByNameAsc) Len() int      { return len(l) }
+func (l prometheusLabelsByNameAsc) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l prometheusLabelsByNameAsc) Less(i, j int) bool {
+	return bytes.Compare(l[i].Name, l[j].Name) < 0
+}
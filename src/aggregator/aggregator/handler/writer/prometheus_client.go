@@ -0,0 +1,236 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/retry"
+
+	"github.com/golang/snappy"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	prometheusRemoteWriteVersion = "0.1.0"
+)
+
+var (
+	errPrometheusClientClosed = errors.New("prometheus client is closed")
+	errPrometheusQueueFull    = errors.New("prometheus client queue is full")
+)
+
+// PrometheusClient sends batches of series to a Prometheus remote write endpoint.
+type PrometheusClient interface {
+	// NumShards returns the number of shards series are distributed across.
+	NumShards() uint32
+
+	// Enqueue enqueues a batch of series belonging to the given shard to be sent
+	// asynchronously. The client takes ownership of the series.
+	Enqueue(shard uint32, series []*prompb.TimeSeries) error
+
+	// Close sends the pending batches and closes the client.
+	Close()
+}
+
+type prometheusClientMetrics struct {
+	clientClosed  tally.Counter
+	queueFull     tally.Counter
+	enqueued      tally.Counter
+	sendSuccess   tally.Counter
+	sendErrors    tally.Counter
+	seriesSent    tally.Counter
+	seriesDropped tally.Counter
+	sendLatency   tally.Timer
+}
+
+func newPrometheusClientMetrics(scope tally.Scope) prometheusClientMetrics {
+	sendScope := scope.SubScope("send")
+	return prometheusClientMetrics{
+		clientClosed:  scope.Counter("client-closed"),
+		queueFull:     scope.Counter("queue-full"),
+		enqueued:      scope.Counter("enqueued"),
+		sendSuccess:   sendScope.Counter("success"),
+		sendErrors:    sendScope.Counter("errors"),
+		seriesSent:    sendScope.Counter("series-sent"),
+		seriesDropped: scope.Counter("series-dropped"),
+		sendLatency:   sendScope.Timer("latency"),
+	}
+}
+
+type prometheusClient struct {
+	sync.RWMutex
+
+	endpoint   string
+	headers    map[string]string
+	httpClient *http.Client
+	retrier    retry.Retrier
+	nowFn      clock.NowFn
+	logger     *zap.Logger
+
+	closed  bool
+	queues  []chan []*prompb.TimeSeries
+	wg      sync.WaitGroup
+	metrics prometheusClientMetrics
+}
+
+// NewPrometheusClient creates a new client sending series to a Prometheus
+// remote write endpoint. Each shard owns a queue drained by a dedicated
+// goroutine so requests for the same series are sent in order.
+func NewPrometheusClient(opts PrometheusOptions) (PrometheusClient, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	instrumentOpts := opts.InstrumentOptions()
+	scope := instrumentOpts.MetricsScope()
+	retryOpts := opts.RetryOptions().SetMetricsScope(scope.SubScope("retry"))
+	c := &prometheusClient{
+		endpoint:   opts.Endpoint(),
+		headers:    opts.Headers(),
+		httpClient: opts.HTTPClient(),
+		retrier:    retry.NewRetrier(retryOpts),
+		nowFn:      opts.ClockOptions().NowFn(),
+		logger:     instrumentOpts.Logger(),
+		queues:     make([]chan []*prompb.TimeSeries, opts.NumShards()),
+		metrics:    newPrometheusClientMetrics(scope),
+	}
+	for i := range c.queues {
+		c.queues[i] = make(chan []*prompb.TimeSeries, opts.QueueSize())
+		c.wg.Add(1)
+		go c.sendLoop(c.queues[i])
+	}
+	return c, nil
+}
+
+func (c *prometheusClient) NumShards() uint32 {
+	return uint32(len(c.queues))
+}
+
+func (c *prometheusClient) Enqueue(shard uint32, series []*prompb.TimeSeries) error {
+	if shard >= uint32(len(c.queues)) {
+		return fmt.Errorf("invalid shard %d, number of shards is %d", shard, len(c.queues))
+	}
+	c.RLock()
+	if c.closed {
+		c.RUnlock()
+		c.metrics.clientClosed.Inc(1)
+		return errPrometheusClientClosed
+	}
+	select {
+	case c.queues[shard] <- series:
+		c.RUnlock()
+		c.metrics.enqueued.Inc(1)
+		return nil
+	default:
+	}
+	c.RUnlock()
+	c.metrics.queueFull.Inc(1)
+	c.metrics.seriesDropped.Inc(int64(len(series)))
+	return errPrometheusQueueFull
+}
+
+func (c *prometheusClient) Close() {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return
+	}
+	c.closed = true
+	for _, queue := range c.queues {
+		close(queue)
+	}
+	c.Unlock()
+
+	c.wg.Wait()
+}
+
+func (c *prometheusClient) sendLoop(queue chan []*prompb.TimeSeries) {
+	defer c.wg.Done()
+
+	var req prompb.WriteRequest
+	for series := range queue {
+		req.Timeseries = series
+		if err := c.write(&req); err != nil {
+			c.metrics.sendErrors.Inc(1)
+			c.metrics.seriesDropped.Inc(int64(len(series)))
+			c.logger.Error("could not send prometheus remote write request",
+				zap.String("endpoint", c.endpoint),
+				zap.Int("numSeries", len(series)),
+				zap.Error(err))
+			continue
+		}
+		c.metrics.sendSuccess.Inc(1)
+		c.metrics.seriesSent.Inc(int64(len(series)))
+	}
+}
+
+func (c *prometheusClient) write(req *prompb.WriteRequest) error {
+	data, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+	body := snappy.Encode(nil, data)
+	start := c.nowFn()
+	err = c.retrier.Attempt(func() error {
+		return c.send(body)
+	})
+	c.metrics.sendLatency.Record(c.nowFn().Sub(start))
+	return err
+}
+
+func (c *prometheusClient) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return retry.NonRetryableError(err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", prometheusRemoteWriteVersion)
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	// Drain the body so the underlying connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	err = fmt.Errorf("remote write endpoint %s returned status code %d", c.endpoint, resp.StatusCode)
+	// Server errors and throttling are transient, everything else is a
+	// malformed request that would fail again.
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return retry.NonRetryableError(err)
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	"github.com/m3db/m3/src/x/retry"
+)
+
+const (
+	defaultPrometheusNumShards    = 4
+	defaultPrometheusQueueSize    = 128
+	defaultPrometheusMaxBatchSize = 1000
+)
+
+var (
+	errPrometheusNoEndpoint        = errors.New("no prometheus remote write endpoint set")
+	errPrometheusInvalidNumShards  = errors.New("prometheus remote write number of shards must be positive")
+	errPrometheusInvalidQueueSize  = errors.New("prometheus remote write queue size must be positive")
+	errPrometheusInvalidBatchSize  = errors.New("prometheus remote write max batch size must be positive")
+	errPrometheusNoNameAndTagsFn   = errors.New("no name and tags function set")
+	errPrometheusNoSortedTagIterFn = errors.New("no sorted tag iterator function set")
+	errPrometheusStoragePolicies   = errors.New("prometheus remote write requires exactly one storage policy unless a storage policy label is set")
+)
+
+// PrometheusOptions provide a set of options for writing aggregated metrics
+// to a Prometheus remote write endpoint.
+type PrometheusOptions interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) PrometheusOptions
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) PrometheusOptions
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetEndpoint sets the remote write endpoint.
+	SetEndpoint(value string) PrometheusOptions
+
+	// Endpoint returns the remote write endpoint.
+	Endpoint() string
+
+	// SetHeaders sets the additional headers sent with each remote write request.
+	SetHeaders(value map[string]string) PrometheusOptions
+
+	// Headers returns the additional headers sent with each remote write request.
+	Headers() map[string]string
+
+	// SetHTTPClient sets the http client.
+	SetHTTPClient(value *http.Client) PrometheusOptions
+
+	// HTTPClient returns the http client.
+	HTTPClient() *http.Client
+
+	// SetRetryOptions sets the retry options for sending remote write requests.
+	SetRetryOptions(value retry.Options) PrometheusOptions
+
+	// RetryOptions returns the retry options for sending remote write requests.
+	RetryOptions() retry.Options
+
+	// SetNumShards sets the number of shards series are distributed across,
+	// each shard sends its remote write requests sequentially so that samples
+	// of the same series are always written in order.
+	SetNumShards(value int) PrometheusOptions
+
+	// NumShards returns the number of shards series are distributed across.
+	NumShards() int
+
+	// SetShardFn sets the function mapping a series to a shard.
+	SetShardFn(value sharding.ShardFn) PrometheusOptions
+
+	// ShardFn returns the function mapping a series to a shard.
+	ShardFn() sharding.ShardFn
+
+	// SetQueueSize sets the number of pending remote write requests per shard.
+	SetQueueSize(value int) PrometheusOptions
+
+	// QueueSize returns the number of pending remote write requests per shard.
+	QueueSize() int
+
+	// SetMaxBatchSize sets the maximum number of series in a remote write request.
+	SetMaxBatchSize(value int) PrometheusOptions
+
+	// MaxBatchSize returns the maximum number of series in a remote write request.
+	MaxBatchSize() int
+
+	// SetNameAndTagsFn sets the function splitting a metric id into its name and tags.
+	SetNameAndTagsFn(value id.NameAndTagsFn) PrometheusOptions
+
+	// NameAndTagsFn returns the function splitting a metric id into its name and tags.
+	NameAndTagsFn() id.NameAndTagsFn
+
+	// SetSortedTagIteratorFn sets the function creating iterators over the metric tags.
+	SetSortedTagIteratorFn(value id.SortedTagIteratorFn) PrometheusOptions
+
+	// SortedTagIteratorFn returns the function creating iterators over the metric tags.
+	SortedTagIteratorFn() id.SortedTagIteratorFn
+
+	// SetStoragePolicies sets the storage policies written to the endpoint, an empty
+	// list means metrics of all storage policies are written. Since the series of
+	// a metric are otherwise identical across storage policies, writing more than
+	// one storage policy requires a storage policy label.
+	SetStoragePolicies(value []policy.StoragePolicy) PrometheusOptions
+
+	// StoragePolicies returns the storage policies written to the endpoint.
+	StoragePolicies() []policy.StoragePolicy
+
+	// SetStoragePolicyLabel sets the name of the label holding the storage policy
+	// of a series, e.g. 1m:40d, no such label is added if empty.
+	SetStoragePolicyLabel(value []byte) PrometheusOptions
+
+	// StoragePolicyLabel returns the name of the label holding the storage policy
+	// of a series.
+	StoragePolicyLabel() []byte
+}
+
+type prometheusOptions struct {
+	clockOpts           clock.Options
+	instrumentOpts      instrument.Options
+	endpoint            string
+	headers             map[string]string
+	httpClient          *http.Client
+	retryOpts           retry.Options
+	numShards           int
+	shardFn             sharding.ShardFn
+	queueSize           int
+	maxBatchSize        int
+	nameAndTagsFn       id.NameAndTagsFn
+	sortedTagIteratorFn id.SortedTagIteratorFn
+	storagePolicies     []policy.StoragePolicy
+	storagePolicyLabel  []byte
+}
+
+// NewPrometheusOptions creates a new set of prometheus options.
+func NewPrometheusOptions() PrometheusOptions {
+	return &prometheusOptions{
+		clockOpts:           clock.NewOptions(),
+		instrumentOpts:      instrument.NewOptions(),
+		httpClient:          xhttp.NewHTTPClient(xhttp.DefaultHTTPClientOptions()),
+		retryOpts:           retry.NewOptions(),
+		numShards:           defaultPrometheusNumShards,
+		shardFn:             sharding.DefaultHash.MustShardFn(),
+		queueSize:           defaultPrometheusQueueSize,
+		maxBatchSize:        defaultPrometheusMaxBatchSize,
+		nameAndTagsFn:       m3.NameAndTags,
+		sortedTagIteratorFn: m3.NewSortedTagIterator,
+	}
+}
+
+func (o *prometheusOptions) Validate() error {
+	if o.endpoint == "" {
+		return errPrometheusNoEndpoint
+	}
+	if o.numShards <= 0 {
+		return errPrometheusInvalidNumShards
+	}
+	if o.queueSize <= 0 {
+		return errPrometheusInvalidQueueSize
+	}
+	if o.maxBatchSize <= 0 {
+		return errPrometheusInvalidBatchSize
+	}
+	if o.nameAndTagsFn == nil {
+		return errPrometheusNoNameAndTagsFn
+	}
+	if o.sortedTagIteratorFn == nil {
+		return errPrometheusNoSortedTagIterFn
+	}
+	if len(o.storagePolicies) != 1 && len(o.storagePolicyLabel) == 0 {
+		return errPrometheusStoragePolicies
+	}
+	return nil
+}
+
+func (o *prometheusOptions) SetClockOptions(value clock.Options) PrometheusOptions {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *prometheusOptions) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *prometheusOptions) SetInstrumentOptions(value instrument.Options) PrometheusOptions {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *prometheusOptions) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *prometheusOptions) SetEndpoint(value string) PrometheusOptions {
+	opts := *o
+	opts.endpoint = value
+	return &opts
+}
+
+func (o *prometheusOptions) Endpoint() string {
+	return o.endpoint
+}
+
+func (o *prometheusOptions) SetHeaders(value map[string]string) PrometheusOptions {
+	opts := *o
+	opts.headers = value
+	return &opts
+}
+
+func (o *prometheusOptions) Headers() map[string]string {
+	return o.headers
+}
+
+func (o *prometheusOptions) SetHTTPClient(value *http.Client) PrometheusOptions {
+	opts := *o
+	opts.httpClient = value
+	return &opts
+}
+
+func (o *prometheusOptions) HTTPClient() *http.Client {
+	return o.httpClient
+}
+
+func (o *prometheusOptions) SetRetryOptions(value retry.Options) PrometheusOptions {
+	opts := *o
+	opts.retryOpts = value
+	return &opts
+}
+
+func (o *prometheusOptions) RetryOptions() retry.Options {
+	return o.retryOpts
+}
+
+func (o *prometheusOptions) SetNumShards(value int) PrometheusOptions {
+	opts := *o
+	opts.numShards = value
+	return &opts
+}
+
+func (o *prometheusOptions) NumShards() int {
+	return o.numShards
+}
+
+func (o *prometheusOptions) SetShardFn(value sharding.ShardFn) PrometheusOptions {
+	opts := *o
+	opts.shardFn = value
+	return &opts
+}
+
+func (o *prometheusOptions) ShardFn() sharding.ShardFn {
+	return o.shardFn
+}
+
+func (o *prometheusOptions) SetQueueSize(value int) PrometheusOptions {
+	opts := *o
+	opts.queueSize = value
+	return &opts
+}
+
+func (o *prometheusOptions) QueueSize() int {
+	return o.queueSize
+}
+
+func (o *prometheusOptions) SetMaxBatchSize(value int) PrometheusOptions {
+	opts := *o
+	opts.maxBatchSize = value
+	return &opts
+}
+
+func (o *prometheusOptions) MaxBatchSize() int {
+	return o.maxBatchSize
+}
+
+func (o *prometheusOptions) SetNameAndTagsFn(value id.NameAndTagsFn) PrometheusOptions {
+	opts := *o
+	opts.nameAndTagsFn = value
+	return &opts
+}
+
+func (o *prometheusOptions) NameAndTagsFn() id.NameAndTagsFn {
+	return o.nameAndTagsFn
+}
+
+func (o *prometheusOptions) SetSortedTagIteratorFn(value id.SortedTagIteratorFn) PrometheusOptions {
+	opts := *o
+	opts.sortedTagIteratorFn = value
+	return &opts
+}
+
+func (o *prometheusOptions) SortedTagIteratorFn() id.SortedTagIteratorFn {
+	return o.sortedTagIteratorFn
+}
+
+func (o *prometheusOptions) SetStoragePolicies(value []policy.StoragePolicy) PrometheusOptions {
+	opts := *o
+	opts.storagePolicies = value
+	return &opts
+}
+
+func (o *prometheusOptions) StoragePolicies() []policy.StoragePolicy {
+	return o.storagePolicies
+}
+
+func (o *prometheusOptions) SetStoragePolicyLabel(value []byte) PrometheusOptions {
+	opts := *o
+	opts.storagePolicyLabel = value
+	return &opts
+}
+
+func (o *prometheusOptions) StoragePolicyLabel() []byte {
+	return o.storagePolicyLabel
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/retry"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPrometheusMetric = aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{
+				Prefix: []byte("stats."),
+				Data:   []byte("m3+requests+service=foo,status-code=200"),
+				Suffix: []byte(".p99"),
+			},
+			TimeNanos: int64(12345 * time.Millisecond),
+			Value:     42,
+		},
+		StoragePolicy: policy.NewStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour),
+	}
+	testPrometheusSeries = &prompb.TimeSeries{
+		Labels: []*prompb.Label{
+			{Name: []byte("__name__"), Value: []byte("stats_requests_p99")},
+			{Name: []byte("service"), Value: []byte("foo")},
+			{Name: []byte("status_code"), Value: []byte("200")},
+		},
+		Samples: []*prompb.Sample{{Value: 42, Timestamp: 12345}},
+	}
+)
+
+type enqueuedSeries struct {
+	shard  uint32
+	series []*prompb.TimeSeries
+}
+
+type mockPrometheusClient struct {
+	numShards uint32
+	enqueued  []enqueuedSeries
+	closed    bool
+}
+
+func (c *mockPrometheusClient) NumShards() uint32 { return c.numShards }
+
+func (c *mockPrometheusClient) Enqueue(shard uint32, series []*prompb.TimeSeries) error {
+	c.enqueued = append(c.enqueued, enqueuedSeries{shard: shard, series: series})
+	return nil
+}
+
+func (c *mockPrometheusClient) Close() { c.closed = true }
+
+func TestPrometheusWriterWriteClosed(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 1}
+	writer := NewPrometheusWriter(client, NewPrometheusOptions())
+	require.NoError(t, writer.Close())
+	require.Equal(t, errWriterClosed, writer.Write(testPrometheusMetric))
+	require.Equal(t, errWriterClosed, writer.Close())
+	require.False(t, client.closed)
+}
+
+func TestPrometheusWriterWriteFlush(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 4}
+	opts := NewPrometheusOptions().
+		SetShardFn(func([]byte, uint32) uint32 { return 2 })
+	writer := NewPrometheusWriter(client, opts)
+
+	require.NoError(t, writer.Write(testPrometheusMetric))
+	require.Empty(t, client.enqueued)
+
+	require.NoError(t, writer.Flush())
+	require.Equal(t, []enqueuedSeries{
+		{shard: 2, series: []*prompb.TimeSeries{testPrometheusSeries}},
+	}, client.enqueued)
+
+	// Nothing is pending after a flush.
+	require.NoError(t, writer.Flush())
+	require.Equal(t, 1, len(client.enqueued))
+}
+
+func TestPrometheusWriterWriteMaxBatchSize(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 2}
+	opts := NewPrometheusOptions().
+		SetMaxBatchSize(2).
+		SetShardFn(func(id []byte, _ uint32) uint32 { return uint32(len(id) % 2) })
+	writer := NewPrometheusWriter(client, opts)
+
+	require.NoError(t, writer.Write(testPrometheusMetric))
+	require.NoError(t, writer.Write(testPrometheusMetric))
+	require.Equal(t, 1, len(client.enqueued))
+	require.Equal(t, 2, len(client.enqueued[0].series))
+
+	require.NoError(t, writer.Write(testPrometheusMetric))
+	require.NoError(t, writer.Close())
+	require.Equal(t, 2, len(client.enqueued))
+	require.Equal(t, 1, len(client.enqueued[1].series))
+}
+
+func TestPrometheusWriterWriteStoragePolicyFilter(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 1}
+	opts := NewPrometheusOptions().
+		SetStoragePolicies([]policy.StoragePolicy{
+			policy.NewStoragePolicy(time.Minute, xtime.Minute, 24*time.Hour),
+		})
+	writer := NewPrometheusWriter(client, opts)
+
+	require.NoError(t, writer.Write(testPrometheusMetric))
+	require.NoError(t, writer.Flush())
+	require.Empty(t, client.enqueued)
+}
+
+func TestPrometheusWriterWriteStoragePolicyLabel(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 1}
+	opts := NewPrometheusOptions().
+		SetStoragePolicyLabel([]byte("storage-policy"))
+	writer := NewPrometheusWriter(client, opts)
+
+	metric := testPrometheusMetric
+	metric.Data = []byte("m3+requests+service=foo,storage-policy=bar")
+	require.NoError(t, writer.Write(metric))
+	metric.StoragePolicy = policy.NewStoragePolicy(time.Minute, xtime.Minute, 24*time.Hour)
+	require.NoError(t, writer.Write(metric))
+	require.NoError(t, writer.Flush())
+
+	// The series of each storage policy are distinct, and the label replaces
+	// any tag of the same name.
+	require.Equal(t, 1, len(client.enqueued))
+	require.Equal(t, 2, len(client.enqueued[0].series))
+	for i, expected := range []string{"10s:6h", "1m:1d"} {
+		require.Equal(t, []*prompb.Label{
+			{Name: []byte("__name__"), Value: []byte("stats_requests_p99")},
+			{Name: []byte("service"), Value: []byte("foo")},
+			{Name: []byte("storage_policy"), Value: []byte(expected)},
+		}, client.enqueued[0].series[i].Labels)
+	}
+}
+
+func TestPrometheusWriterWriteInvalidID(t *testing.T) {
+	client := &mockPrometheusClient{numShards: 1}
+	writer := NewPrometheusWriter(client, NewPrometheusOptions())
+
+	metric := testPrometheusMetric
+	metric.Data = []byte("foo")
+	require.Error(t, writer.Write(metric))
+}
+
+func TestSanitizePrometheusName(t *testing.T) {
+	inputs := []struct {
+		name       string
+		allowColon bool
+		expected   string
+	}{
+		{name: "foo_bar", expected: "foo_bar"},
+		{name: "foo.bar-baz", expected: "foo_bar_baz"},
+		{name: "foo:bar", expected: "foo_bar"},
+		{name: "foo:bar", allowColon: true, expected: "foo:bar"},
+		{name: "1foo", expected: "_1foo"},
+		{name: "", expected: ""},
+	}
+	for _, input := range inputs {
+		res := sanitizePrometheusName([]byte(input.name), input.allowColon)
+		require.Equal(t, input.expected, string(res))
+	}
+}
+
+func TestPrometheusClientSend(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		attempts int
+		received []prompb.WriteRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		require.Equal(t, "bar", r.Header.Get("X-Foo"))
+
+		compressed, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, req.Unmarshal(data))
+		received = append(received, req)
+	}))
+	defer server.Close()
+
+	opts := NewPrometheusOptions().
+		SetEndpoint(server.URL).
+		SetHeaders(map[string]string{"X-Foo": "bar"}).
+		SetNumShards(2).
+		SetStoragePolicies([]policy.StoragePolicy{testPrometheusMetric.StoragePolicy}).
+		SetRetryOptions(retry.NewOptions().
+			SetInitialBackoff(time.Millisecond).
+			SetMaxRetries(1))
+	client, err := NewPrometheusClient(opts)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), client.NumShards())
+
+	series := []*prompb.TimeSeries{testPrometheusSeries}
+	require.NoError(t, client.Enqueue(1, series))
+	require.Error(t, client.Enqueue(2, series))
+	client.Close()
+	require.Equal(t, errPrometheusClientClosed, client.Enqueue(1, series))
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, 2, attempts)
+	require.Equal(t, 1, len(received))
+	require.Equal(t, series, received[0].Timeseries)
+}
+
+func TestPrometheusClientSendNonRetryableError(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		attempts int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		attempts++
+		lock.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	opts := NewPrometheusOptions().
+		SetEndpoint(server.URL).
+		SetStoragePolicies([]policy.StoragePolicy{testPrometheusMetric.StoragePolicy}).
+		SetRetryOptions(retry.NewOptions().
+			SetInitialBackoff(time.Millisecond).
+			SetMaxRetries(3))
+	client, err := NewPrometheusClient(opts)
+	require.NoError(t, err)
+	require.NoError(t, client.Enqueue(0, []*prompb.TimeSeries{testPrometheusSeries}))
+	client.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, 1, attempts)
+}
+
+func TestPrometheusOptionsValidate(t *testing.T) {
+	opts := NewPrometheusOptions()
+	require.Equal(t, errPrometheusNoEndpoint, opts.Validate())
+
+	opts = opts.SetEndpoint("http://localhost:9090/api/v1/write")
+	require.Equal(t, errPrometheusStoragePolicies, opts.Validate())
+
+	// Series of several storage policies must be told apart by a label.
+	twoPolicies := []policy.StoragePolicy{
+		policy.NewStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour),
+		policy.NewStoragePolicy(time.Minute, xtime.Minute, 24*time.Hour),
+	}
+	require.Equal(t, errPrometheusStoragePolicies, opts.SetStoragePolicies(twoPolicies).Validate())
+	require.NoError(t, opts.SetStoragePolicies(twoPolicies).
+		SetStoragePolicyLabel([]byte("storage_policy")).Validate())
+
+	opts = opts.SetStoragePolicies(twoPolicies[:1])
+	require.NoError(t, opts.Validate())
+	require.Equal(t, errPrometheusInvalidNumShards, opts.SetNumShards(0).Validate())
+	require.Equal(t, errPrometheusInvalidQueueSize, opts.SetQueueSize(0).Validate())
+	require.Equal(t, errPrometheusInvalidBatchSize, opts.SetMaxBatchSize(0).Validate())
+}
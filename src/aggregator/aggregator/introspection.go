@@ -0,0 +1,215 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/metrics/metric"
+	metricid "github.com/m3db/m3/src/metrics/metric/id"
+
+	"github.com/willf/bitset"
+)
+
+// NB: the memory used by an entry is estimated from the number of values,
+// samples and sources it holds rather than measured, so it is only good for
+// ranking entries against each other.
+const (
+	estimatedEntryOverheadBytes       = 256
+	estimatedElemOverheadBytes        = 256
+	estimatedAggregationOverheadBytes = 64
+	estimatedSampleBytes              = 48
+	estimatedSourceSetWordBytes       = 8
+)
+
+// EntriesQuery selects the live entries to describe.
+type EntriesQuery struct {
+	// ID selects the entries for a single metric id. If set, the filter is ignored.
+	ID metricid.RawID
+
+	// Filter selects the entries whose metric ids match the filter.
+	Filter filters.Filter
+
+	// Limit is the maximum number of entries returned, or zero for no limit.
+	Limit int
+}
+
+// EntryInfo describes a live entry in the aggregator.
+type EntryInfo struct {
+	ID                  string     `json:"id"`
+	Shard               uint32     `json:"shard"`
+	Category            string     `json:"category"`
+	MetricType          string     `json:"metricType"`
+	HasDefaultMetadatas bool       `json:"hasDefaultMetadatas"`
+	CutoverNanos        int64      `json:"cutoverNanos"`
+	EstimatedBytes      int        `json:"estimatedBytes"`
+	Elems               []ElemInfo `json:"elems"`
+}
+
+// ElemInfo describes an aggregation element of an entry. The aggregation id,
+// storage policy and pipeline are those of the staged metadata matched by the
+// metric when the element was created.
+type ElemInfo struct {
+	AggregationID       string          `json:"aggregationID"`
+	StoragePolicy       string          `json:"storagePolicy"`
+	Pipeline            string          `json:"pipeline"`
+	NumForwardedTimes   int             `json:"numForwardedTimes"`
+	LastConsumedAtNanos int64           `json:"lastConsumedAtNanos,omitempty"`
+	Values              []ElemValueInfo `json:"values"`
+}
+
+// ElemValueInfo describes the partial aggregation of an element for a
+// single aggregation window.
+type ElemValueInfo struct {
+	StartAtNanos int64              `json:"startAtNanos"`
+	Aggregation  map[string]float64 `json:"aggregation"`
+	SourcesSeen  []uint             `json:"sourcesSeen,omitempty"`
+}
+
+// ShardEntries describes the live entries of a shard.
+type ShardEntries struct {
+	Shard   uint32      `json:"shard"`
+	Entries []EntryInfo `json:"entries"`
+}
+
+type entryIDMatchFn func(id metricid.RawID) bool
+
+func newEntryInfo(
+	shard uint32,
+	category metricCategory,
+	metricType metric.Type,
+	id metricid.RawID,
+	snapshot entrySnapshot,
+) EntryInfo {
+	info := EntryInfo{
+		ID:                  string(id),
+		Shard:               shard,
+		Category:            category.String(),
+		MetricType:          metricType.String(),
+		HasDefaultMetadatas: snapshot.hasDefaultMetadatas,
+		CutoverNanos:        snapshot.cutoverNanos,
+		EstimatedBytes:      estimateEntryBytes(id, snapshot),
+		Elems:               make([]ElemInfo, 0, len(snapshot.aggregations)),
+	}
+	for _, agg := range snapshot.aggregations {
+		elem := ElemInfo{
+			AggregationID:       agg.key.aggregationID.String(),
+			StoragePolicy:       agg.key.storagePolicy.String(),
+			Pipeline:            agg.key.pipeline.String(),
+			NumForwardedTimes:   agg.key.numForwardedTimes,
+			LastConsumedAtNanos: agg.elem.lastConsumedAtNanos,
+			Values:              make([]ElemValueInfo, 0, len(agg.elem.values)),
+		}
+		for _, v := range agg.elem.values {
+			elem.Values = append(elem.Values, ElemValueInfo{
+				StartAtNanos: v.startAtNanos,
+				Aggregation:  aggregationValuesFor(metricType, v.aggregation),
+				SourcesSeen:  sourcesFor(v.sourcesSeen),
+			})
+		}
+		info.Elems = append(info.Elems, elem)
+	}
+	return info
+}
+
+func aggregationValuesFor(metricType metric.Type, snapshot aggregationSnapshot) map[string]float64 {
+	switch metricType {
+	case metric.CounterType:
+		return map[string]float64{
+			"sum":   float64(snapshot.counter.Sum),
+			"sumSq": float64(snapshot.counter.SumSq),
+			"count": float64(snapshot.counter.Count),
+			"max":   float64(snapshot.counter.Max),
+			"min":   float64(snapshot.counter.Min),
+		}
+	case metric.TimerType:
+		return map[string]float64{
+			"sum":        snapshot.timer.Sum,
+			"sumSq":      snapshot.timer.SumSq,
+			"count":      float64(snapshot.timer.Count),
+			"numSamples": float64(len(snapshot.timer.Stream.Samples)),
+		}
+	case metric.GaugeType:
+		return map[string]float64{
+			"last":  snapshot.gauge.Last,
+			"sum":   snapshot.gauge.Sum,
+			"sumSq": snapshot.gauge.SumSq,
+			"count": float64(snapshot.gauge.Count),
+			"max":   snapshot.gauge.Max,
+			"min":   snapshot.gauge.Min,
+		}
+	default:
+		return nil
+	}
+}
+
+func sourcesFor(sourcesSeen []uint64) []uint {
+	if len(sourcesSeen) == 0 {
+		return nil
+	}
+	var (
+		bs      = bitset.From(sourcesSeen)
+		sources = make([]uint, 0, bs.Count())
+	)
+	for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+		sources = append(sources, i)
+	}
+	return sources
+}
+
+func estimateEntryBytes(id metricid.RawID, snapshot entrySnapshot) int {
+	size := estimatedEntryOverheadBytes + len(id)
+	for _, agg := range snapshot.aggregations {
+		size += estimatedElemOverheadBytes + len(id)
+		for _, v := range agg.elem.values {
+			size += estimatedAggregationOverheadBytes
+			size += len(v.aggregation.timer.Stream.Samples) * estimatedSampleBytes
+			size += len(v.sourcesSeen) * estimatedSourceSetWordBytes
+			size += len(v.overflowSourcesSeen) * estimatedSourceSetWordBytes
+		}
+	}
+	return size
+}
+
+// entrySizeHeap is a min-heap of entry snapshots ordered by their estimated
+// size, used to keep track of the largest entries seen so far.
+type entrySizeHeap []sizedEntrySnapshot
+
+type sizedEntrySnapshot struct {
+	category   metricCategory
+	metricType metric.Type
+	id         metricid.RawID
+	snapshot   entrySnapshot
+	size       int
+}
+
+func (h entrySizeHeap) Len() int            { return len(h) }
+func (h entrySizeHeap) Less(i, j int) bool  { return h[i].size < h[j].size }
+func (h entrySizeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *entrySizeHeap) Push(x interface{}) { *h = append(*h, x.(sizedEntrySnapshot)) }
+
+func (h *entrySizeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = sizedEntrySnapshot{}
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willf/bitset"
+)
+
+func TestSourcesFor(t *testing.T) {
+	require.Nil(t, sourcesFor(nil))
+
+	bs := bitset.New(128)
+	bs.Set(3)
+	bs.Set(64)
+	bs.Set(100)
+	require.Equal(t, []uint{3, 64, 100}, sourcesFor(bs.Bytes()))
+}
+
+func TestAggregationValuesFor(t *testing.T) {
+	snapshot := aggregationSnapshot{
+		gauge: aggregation.GaugeSnapshot{
+			Last:  3,
+			Sum:   6,
+			SumSq: 14,
+			Count: 3,
+			Max:   3,
+			Min:   1,
+		},
+	}
+	expected := map[string]float64{
+		"last":  3,
+		"sum":   6,
+		"sumSq": 14,
+		"count": 3,
+		"max":   3,
+		"min":   1,
+	}
+	require.Equal(t, expected, aggregationValuesFor(metric.GaugeType, snapshot))
+	require.Nil(t, aggregationValuesFor(metric.UnknownType, snapshot))
+}
@@ -178,6 +178,8 @@ type baseMetricList struct {
 	discardLocalMetricFn        flushLocalMetricFn
 	consumeForwardedMetricFn    flushForwardedMetricFn
 	discardForwardedMetricFn    flushForwardedMetricFn
+	consumeForwardedSketchFn    flushForwardedSketchFn
+	discardForwardedSketchFn    flushForwardedSketchFn
 	onForwardingElemConsumedFn  onForwardingElemFlushedFn
 	onForwardingElemDiscardedFn onForwardingElemFlushedFn
 }
@@ -221,6 +223,8 @@ func newBaseMetricList(
 	l.discardLocalMetricFn = l.discardLocalMetric
 	l.consumeForwardedMetricFn = l.consumeForwardedMetric
 	l.discardForwardedMetricFn = l.discardForwardedMetric
+	l.consumeForwardedSketchFn = l.consumeForwardedSketch
+	l.discardForwardedSketchFn = l.discardForwardedSketch
 	l.onForwardingElemConsumedFn = l.onForwardingElemConsumed
 	l.onForwardingElemDiscardedFn = l.onForwardingElemDiscarded
 
@@ -265,7 +269,7 @@ func (l *baseMetricList) PushBack(value metricElem) (*list.Element, error) {
 		l.Unlock()
 		return elem, nil
 	}
-	writeForwardedFn, onForwardedWrittenFn, err := l.forwardedWriter.Register(
+	writeForwardedFn, writeForwardedSketchFn, onForwardedWrittenFn, err := l.forwardedWriter.Register(
 		forwardedMetricType,
 		forwardedID,
 		forwardedAggregationKey,
@@ -274,7 +278,7 @@ func (l *baseMetricList) PushBack(value metricElem) (*list.Element, error) {
 		l.Unlock()
 		return nil, err
 	}
-	value.SetForwardedCallbacks(writeForwardedFn, onForwardedWrittenFn)
+	value.SetForwardedCallbacks(writeForwardedFn, writeForwardedSketchFn, onForwardedWrittenFn)
 	l.Unlock()
 	return elem, nil
 }
@@ -356,10 +360,12 @@ func (l *baseMetricList) flushBefore(beforeNanos int64, flushType flushType) {
 	l.toCollect = l.toCollect[:0]
 	flushLocalFn := l.consumeLocalMetricFn
 	flushForwardedFn := l.consumeForwardedMetricFn
+	flushForwardedSketchFn := l.consumeForwardedSketchFn
 	onForwardedFlushedFn := l.onForwardingElemConsumedFn
 	if flushType == discardType {
 		flushLocalFn = l.discardLocalMetricFn
 		flushForwardedFn = l.discardForwardedMetricFn
+		flushForwardedSketchFn = l.discardForwardedSketchFn
 		onForwardedFlushedFn = l.onForwardingElemDiscardedFn
 	}
 
@@ -383,6 +389,7 @@ func (l *baseMetricList) flushBefore(beforeNanos int64, flushType flushType) {
 			l.timestampNanosFn,
 			flushLocalFn,
 			flushForwardedFn,
+			flushForwardedSketchFn,
 			onForwardedFlushedFn,
 		) {
 			l.toCollect = append(l.toCollect, e)
@@ -491,6 +498,26 @@ func (l *baseMetricList) discardForwardedMetric(
 	l.metrics.flushForwarded.metricDiscarded.Inc(1)
 }
 
+func (l *baseMetricList) consumeForwardedSketch(
+	writeFn writeForwardedSketchFn,
+	aggregationKey aggregationKey,
+	timeNanos int64,
+	registers []uint8,
+) {
+	writeFn(aggregationKey, timeNanos, registers)
+	l.metrics.flushForwarded.metricConsumed.Inc(1)
+}
+
+// nolint: unparam
+func (l *baseMetricList) discardForwardedSketch(
+	writeFn writeForwardedSketchFn,
+	aggregationKey aggregationKey,
+	timeNanos int64,
+	registers []uint8,
+) {
+	l.metrics.flushForwarded.metricDiscarded.Inc(1)
+}
+
 func (l *baseMetricList) onForwardingElemConsumed(
 	onForwardedWrittenFn onForwardedAggregationDoneFn,
 	aggregationKey aggregationKey,
@@ -893,6 +920,18 @@ func (l *metricLists) Tick() listsTickResult {
 	return res
 }
 
+// ForEachFlushingList calls the list function for each flushing list.
+func (l *metricLists) ForEachFlushingList(listFn flushingMetricListFn) {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, list := range l.lists {
+		if flushingList, ok := list.(flushingMetricList); ok {
+			listFn(flushingList)
+		}
+	}
+}
+
 // Close closes the metric lists.
 func (l *metricLists) Close() {
 	l.Lock()
@@ -61,6 +61,7 @@ func TestBaseMetricListPushBackElemWithDefaultPipeline(t *testing.T) {
 	require.Equal(t, 1, l.aggregations.Len())
 	require.Equal(t, elem, e.Value.(*CounterElem))
 	require.Nil(t, elem.writeForwardedMetricFn)
+	require.Nil(t, elem.writeForwardedSketchFn)
 	require.Nil(t, elem.onForwardedAggregationWrittenFn)
 
 	// Push a counter to a closed list should result in an error.
@@ -87,6 +88,7 @@ func TestBaseMetricListPushBackElemWithForwardingPipeline(t *testing.T) {
 	require.Equal(t, 1, l.aggregations.Len())
 	require.Equal(t, elem, e.Value.(*CounterElem))
 	require.NotNil(t, elem.writeForwardedMetricFn)
+	require.NotNil(t, elem.writeForwardedSketchFn)
 	require.NotNil(t, elem.onForwardedAggregationWrittenFn)
 }
 
@@ -830,8 +832,8 @@ func TestForwardedMetricListFlushConsumingAndCollectingForwardedMetrics(t *testi
 	}
 
 	for _, ep := range elemPairs {
-		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos), ep.metric.Values, sourceID))
-		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Values, sourceID))
+		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos), ep.metric.Values, nil, sourceID))
+		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Values, nil, sourceID))
 		_, err := l.PushBack(ep.elem)
 		require.NoError(t, err)
 	}
@@ -1017,8 +1019,8 @@ func TestForwardedMetricListLastStepLocalFlush(t *testing.T) {
 	}
 
 	for _, ep := range elemPairs {
-		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos), ep.metric.Values, sourceID))
-		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Values, sourceID))
+		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos), ep.metric.Values, nil, sourceID))
+		require.NoError(t, ep.elem.AddUnique(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Values, nil, sourceID))
 		_, err := l.PushBack(ep.elem)
 		require.NoError(t, err)
 	}
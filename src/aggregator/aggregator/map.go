@@ -21,6 +21,8 @@
 package aggregator
 
 import (
+	"bytes"
+	"container/heap"
 	"container/list"
 	"errors"
 	"math"
@@ -33,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	metricid "github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/close"
@@ -61,6 +64,24 @@ const (
 	timedMetric
 )
 
+var (
+	metricCategories = []metricCategory{untimedMetric, forwardedMetric, timedMetric}
+	metricTypes      = []metric.Type{metric.CounterType, metric.TimerType, metric.GaugeType}
+)
+
+func (c metricCategory) String() string {
+	switch c {
+	case untimedMetric:
+		return "untimed"
+	case forwardedMetric:
+		return "forwarded"
+	case timedMetric:
+		return "timed"
+	default:
+		return "unknown"
+	}
+}
+
 type entryKey struct {
 	metricCategory metricCategory
 	metricType     metric.Type
@@ -68,8 +89,9 @@ type entryKey struct {
 }
 
 type hashedEntry struct {
-	key   entryKey
-	entry *Entry
+	key         entryKey
+	entry       *Entry
+	reservation CardinalityReservation
 }
 
 type metricMapMetrics struct {
@@ -100,33 +122,35 @@ type metricMap struct {
 	entryPool    EntryPool
 	batchPercent float64
 
-	closed            bool
-	metricLists       *metricLists
-	entries           map[entryKey]*list.Element
-	entryList         *list.List
-	entryListDelLock  sync.Mutex // Must be held when deleting elements from the entry list
-	firstInsertAt     time.Time
-	rateLimiter       *rate.Limiter
-	runtimeOpts       runtime.Options
-	runtimeOptsCloser close.SimpleCloser
-	sleepFn           sleepFn
-	metrics           metricMapMetrics
+	closed             bool
+	metricLists        *metricLists
+	entries            map[entryKey]*list.Element
+	entryList          *list.List
+	entryListDelLock   sync.Mutex // Must be held when deleting elements from the entry list
+	firstInsertAt      time.Time
+	rateLimiter        *rate.Limiter
+	cardinalityLimiter CardinalityLimiter
+	runtimeOpts        runtime.Options
+	runtimeOptsCloser  close.SimpleCloser
+	sleepFn            sleepFn
+	metrics            metricMapMetrics
 }
 
 func newMetricMap(shard uint32, opts Options) *metricMap {
 	metricLists := newMetricLists(shard, opts)
 	scope := opts.InstrumentOptions().MetricsScope().SubScope("map")
 	m := &metricMap{
-		shard:        shard,
-		opts:         opts,
-		nowFn:        opts.ClockOptions().NowFn(),
-		entryPool:    opts.EntryPool(),
-		batchPercent: opts.EntryCheckBatchPercent(),
-		metricLists:  metricLists,
-		entries:      make(map[entryKey]*list.Element),
-		entryList:    list.New(),
-		sleepFn:      time.Sleep,
-		metrics:      newMetricMapMetrics(scope),
+		shard:              shard,
+		opts:               opts,
+		nowFn:              opts.ClockOptions().NowFn(),
+		entryPool:          opts.EntryPool(),
+		batchPercent:       opts.EntryCheckBatchPercent(),
+		metricLists:        metricLists,
+		entries:            make(map[entryKey]*list.Element),
+		entryList:          list.New(),
+		cardinalityLimiter: opts.CardinalityLimiter(),
+		sleepFn:            time.Sleep,
+		metrics:            newMetricMapMetrics(scope),
 	}
 
 	runtimeOptsManager := opts.RuntimeOptionsManager()
@@ -151,7 +175,7 @@ func (m *metricMap) AddUntimed(
 		metricType:     metric.Type,
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, _, err := m.findOrCreate(key, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -169,7 +193,7 @@ func (m *metricMap) AddTimed(
 		metricType:     metric.Type,
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, _, err := m.findOrCreate(key, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -187,15 +211,43 @@ func (m *metricMap) AddForwarded(
 		metricType:     metric.Type,
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	// NB: forwarded metrics are the outputs of rollups, and are therefore
+	// subject to the cardinality limits.
+	entry, overflowID, err := m.findOrCreate(key, metadata.RollupRuleID, metric.ID)
 	if err != nil {
 		return err
 	}
+	if overflowID != nil {
+		return m.addForwardedOverflow(overflowID, metric, metadata)
+	}
 	err = entry.AddForwarded(metric, metadata)
 	entry.DecWriter()
 	return err
 }
 
+// addForwardedOverflow collapses a forwarded metric whose series exceeds the
+// cardinality limits into the overflow series with the given id.
+func (m *metricMap) addForwardedOverflow(
+	overflowID []byte,
+	metric aggregated.ForwardedMetric,
+	metadata metadata.ForwardMetadata,
+) error {
+	originalID := metric.ID
+	metric.ID = overflowID
+	key := entryKey{
+		metricCategory: forwardedMetric,
+		metricType:     metric.Type,
+		idHash:         hash.Murmur3Hash128(overflowID),
+	}
+	entry, _, err := m.findOrCreate(key, nil, nil)
+	if err != nil {
+		return err
+	}
+	err = entry.AddForwardedOverflow(metric, metadata, originalID)
+	entry.DecWriter()
+	return err
+}
+
 func (m *metricMap) Tick(target time.Duration) tickResult {
 	mapTickRes := m.tick(target)
 	listsTickRes := m.metricLists.Tick()
@@ -225,6 +277,159 @@ func (m *metricMap) SetRuntimeOptions(opts runtime.Options) {
 	m.entryListDelLock.Unlock()
 }
 
+// ForEachSnapshot takes a snapshot of each entry in the map and passes it to
+// the snapshot function.
+func (m *metricMap) ForEachSnapshot(snapshotFn entrySnapshotFn) {
+	m.ForEachMatchingSnapshot(nil, snapshotFn)
+}
+
+// ForEachMatchingSnapshot takes a snapshot of each entry whose id matches and
+// passes it to the snapshot function. A nil match function matches all entries.
+func (m *metricMap) ForEachMatchingSnapshot(matchFn entryIDMatchFn, snapshotFn entrySnapshotFn) {
+	// NB: the entry list deletion lock is held so no entries get deleted
+	// while we iterate over the list, same as when setting runtime options.
+	m.entryListDelLock.Lock()
+	m.forEachEntry(func(entry hashedEntry) {
+		if matchFn != nil {
+			id, ok := entry.entry.ID()
+			if !ok || !matchFn(id) {
+				return
+			}
+		}
+		id, snapshot, ok := entry.entry.Snapshot()
+		if !ok {
+			return
+		}
+		snapshotFn(entry.key.metricCategory, entry.key.metricType, id, snapshot)
+	})
+	m.entryListDelLock.Unlock()
+}
+
+// Entries returns descriptions of the entries for the given id.
+func (m *metricMap) Entries(id metricid.RawID) []EntryInfo {
+	idHash := hash.Murmur3Hash128(id)
+	m.RLock()
+	found := make([]hashedEntry, 0, len(metricCategories))
+	for _, category := range metricCategories {
+		for _, metricType := range metricTypes {
+			key := entryKey{
+				metricCategory: category,
+				metricType:     metricType,
+				idHash:         idHash,
+			}
+			if elem, exists := m.entries[key]; exists {
+				found = append(found, elem.Value.(hashedEntry))
+			}
+		}
+	}
+	m.RUnlock()
+
+	res := make([]EntryInfo, 0, len(found))
+	for _, entry := range found {
+		entryID, snapshot, ok := entry.entry.Snapshot()
+		if !ok || !bytes.Equal(entryID, id) {
+			continue
+		}
+		info := newEntryInfo(m.shard, entry.key.metricCategory, entry.key.metricType, entryID, snapshot)
+		res = append(res, info)
+	}
+	return res
+}
+
+// MatchingEntries returns descriptions of the entries whose ids match, up to
+// the given limit. A limit of zero means no limit.
+func (m *metricMap) MatchingEntries(matchFn entryIDMatchFn, limit int) []EntryInfo {
+	var res []EntryInfo
+	m.ForEachMatchingSnapshot(func(id metricid.RawID) bool {
+		return (limit <= 0 || len(res) < limit) && matchFn(id)
+	}, func(
+		category metricCategory,
+		metricType metric.Type,
+		id metricid.RawID,
+		snapshot entrySnapshot,
+	) {
+		res = append(res, newEntryInfo(m.shard, category, metricType, id, snapshot))
+	})
+	return res
+}
+
+// LargestEntries returns descriptions of the entries with the largest
+// estimated memory use, largest first.
+func (m *metricMap) LargestEntries(limit int) []EntryInfo {
+	if limit <= 0 {
+		return nil
+	}
+	h := make(entrySizeHeap, 0, limit)
+	m.ForEachSnapshot(func(
+		category metricCategory,
+		metricType metric.Type,
+		id metricid.RawID,
+		snapshot entrySnapshot,
+	) {
+		size := estimateEntryBytes(id, snapshot)
+		if len(h) == limit {
+			if h[0].size >= size {
+				return
+			}
+			heap.Pop(&h)
+		}
+		heap.Push(&h, sizedEntrySnapshot{
+			category:   category,
+			metricType: metricType,
+			id:         id,
+			snapshot:   snapshot,
+			size:       size,
+		})
+	})
+	res := make([]EntryInfo, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		e := heap.Pop(&h).(sizedEntrySnapshot)
+		res[i] = newEntryInfo(m.shard, e.category, e.metricType, e.id, e.snapshot)
+	}
+	return res
+}
+
+// Restore restores an entry from its snapshot. Restored entries are not
+// subject to the new metric rate limit or the cardinality limits.
+func (m *metricMap) Restore(
+	category metricCategory,
+	metricType metric.Type,
+	id metricid.RawID,
+	snapshot entrySnapshot,
+) error {
+	key := entryKey{
+		metricCategory: category,
+		metricType:     metricType,
+		idHash:         hash.Murmur3Hash128(id),
+	}
+	m.Lock()
+	if m.closed {
+		m.Unlock()
+		return errMetricMapClosed
+	}
+	entry, found := m.lookupEntryWithLock(key)
+	if !found {
+		entry = m.entryPool.Get()
+		entry.ResetSetData(m.metricLists, m.runtimeOpts, m.opts)
+		m.entries[key] = m.entryList.PushBack(hashedEntry{
+			key:   key,
+			entry: entry,
+		})
+		m.metrics.newEntries.Inc(1)
+	}
+	entry.IncWriter()
+	m.Unlock()
+
+	err := entry.Restore(category, metricType, id, snapshot)
+	entry.DecWriter()
+	return err
+}
+
+// ForEachFlushingList calls the list function for each flushing list in the map.
+func (m *metricMap) ForEachFlushingList(listFn flushingMetricListFn) {
+	m.metricLists.ForEachFlushingList(listFn)
+}
+
 func (m *metricMap) Close() {
 	m.Lock()
 	defer m.Unlock()
@@ -234,14 +439,25 @@ func (m *metricMap) Close() {
 	}
 	m.runtimeOptsCloser.Close()
 	m.metricLists.Close()
+	for elem := m.entryList.Front(); elem != nil; elem = elem.Next() {
+		m.cardinalityLimiter.Release(elem.Value.(hashedEntry).reservation)
+	}
 	m.closed = true
 }
 
-func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
+// findOrCreate finds or creates the entry for the given key. If the limited id
+// is not nil, the creation of the entry is subject to the cardinality limits of
+// the given rollup rule, and the id of the overflow series is returned instead of
+// an entry if the series of the limited id exceeds the limits.
+func (m *metricMap) findOrCreate(
+	key entryKey,
+	rollupRuleID []byte,
+	limitedID []byte,
+) (*Entry, []byte, error) {
 	m.RLock()
 	if m.closed {
 		m.RUnlock()
-		return nil, errMetricMapClosed
+		return nil, nil, errMetricMapClosed
 	}
 	if entry, found := m.lookupEntryWithLock(key); found {
 		// NB(xichen): it is important to increase number of writers
@@ -249,20 +465,31 @@ func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
 		// when deleting expired entries.
 		entry.IncWriter()
 		m.RUnlock()
-		return entry, nil
+		return entry, nil, nil
 	}
 	m.RUnlock()
 
 	m.Lock()
 	if m.closed {
 		m.Unlock()
-		return nil, errMetricMapClosed
+		return nil, nil, errMetricMapClosed
 	}
 	entry, found := m.lookupEntryWithLock(key)
 	if found {
 		entry.IncWriter()
 		m.Unlock()
-		return entry, nil
+		return entry, nil, nil
+	}
+
+	// Check if the new series exceeds the cardinality limits.
+	var reservation CardinalityReservation
+	if limitedID != nil {
+		var overflowID []byte
+		reservation, overflowID = m.cardinalityLimiter.Acquire(rollupRuleID, limitedID)
+		if overflowID != nil {
+			m.Unlock()
+			return nil, overflowID, nil
+		}
 	}
 
 	// Check if we are allowed to insert a new metric.
@@ -271,20 +498,22 @@ func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
 		m.firstInsertAt = now
 	}
 	if err := m.applyNewMetricRateLimitWithLock(now); err != nil {
+		m.cardinalityLimiter.Release(reservation)
 		m.Unlock()
-		return nil, err
+		return nil, nil, err
 	}
 	entry = m.entryPool.Get()
 	entry.ResetSetData(m.metricLists, m.runtimeOpts, m.opts)
 	m.entries[key] = m.entryList.PushBack(hashedEntry{
-		key:   key,
-		entry: entry,
+		key:         key,
+		entry:       entry,
+		reservation: reservation,
 	})
 	entry.IncWriter()
 	m.Unlock()
 	m.metrics.newEntries.Inc(1)
 
-	return entry, nil
+	return entry, nil, nil
 }
 
 func (m *metricMap) lookupEntryWithLock(key entryKey) (*Entry, bool) {
@@ -396,6 +625,7 @@ func (m *metricMap) purgeExpired(
 				numTimedExpired++
 			}
 			elem := m.entries[key]
+			m.cardinalityLimiter.Release(entries[i].reservation)
 			delete(m.entries, key)
 			elem.Value = nil
 			m.entryList.Remove(elem)
@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"bytes"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -33,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
@@ -464,6 +466,110 @@ func TestMetricMapAddForwardedNoRateLimit(t *testing.T) {
 	require.False(t, e1 == e4)
 }
 
+func TestMetricMapAddForwardedCardinalityLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limiter := NewCardinalityLimiter(NewCardinalityLimitOptions().SetMaxSeriesPerRollup(1))
+	opts := testOptions(ctrl).SetCardinalityLimiter(limiter)
+	m := newMetricMap(testShard, opts)
+
+	// Add three series of the same rollup from the same source.
+	for _, host := range []string{"a", "b", "c"} {
+		am := aggregated.ForwardedMetric{
+			Type:      metric.CounterType,
+			ID:        testRollupID("foo", "host", host),
+			TimeNanos: 12345,
+			Values:    []float64{1},
+		}
+		require.NoError(t, m.AddForwarded(am, testForwardMetadata))
+	}
+
+	// Resending the value of a collapsed series from the same source is
+	// deduplicated.
+	require.NoError(t, m.AddForwarded(aggregated.ForwardedMetric{
+		Type:      metric.CounterType,
+		ID:        testRollupID("foo", "host", "b"),
+		TimeNanos: 12345,
+		Values:    []float64{1},
+	}, testForwardMetadata))
+
+	// Only the first series gets its own entry, and the values of the other
+	// series are collapsed into the overflow series.
+	require.Equal(t, 2, len(m.entries))
+	overflowID := m3.NewRollupID([]byte("foo"), []id.TagPair{defaultCardinalityOverflowTagPair})
+	key := entryKey{
+		metricCategory: forwardedMetric,
+		metricType:     metric.CounterType,
+		idHash:         hash.Murmur3Hash128(overflowID),
+	}
+	elem, exists := m.entries[key]
+	require.True(t, exists)
+	entry := elem.Value.(hashedEntry).entry
+	require.Equal(t, 1, len(entry.aggregations))
+	counterElem := entry.aggregations[0].elem.Value.(*CounterElem)
+	require.Equal(t, 1, len(counterElem.values))
+	require.Equal(t, int64(2), counterElem.values[0].lockedAgg.aggregation.Sum())
+
+	// Closing the map releases the reserved capacity.
+	m.Close()
+	reservation, overflowID := limiter.Acquire(nil, testRollupID("foo", "host", "d"))
+	require.True(t, reservation.reserved)
+	require.Nil(t, overflowID)
+}
+
+func TestMetricMapEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newMetricMap(testShard, testOptions(ctrl))
+	for _, host := range []string{"a", "bb", "ccc"} {
+		am := aggregated.ForwardedMetric{
+			Type:      metric.CounterType,
+			ID:        testRollupID("foo", "host", host),
+			TimeNanos: 12345,
+			Values:    []float64{1, 2},
+		}
+		require.NoError(t, m.AddForwarded(am, testForwardMetadata))
+	}
+
+	// Look up the entries of a single id.
+	metricID := testRollupID("foo", "host", "bb")
+	entries := m.Entries(metricID)
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, string(metricID), entries[0].ID)
+	require.Equal(t, testShard, entries[0].Shard)
+	require.Equal(t, "forwarded", entries[0].Category)
+	require.Equal(t, "counter", entries[0].MetricType)
+	require.Equal(t, 1, len(entries[0].Elems))
+	elem := entries[0].Elems[0]
+	require.Equal(t, testForwardMetadata.StoragePolicy.String(), elem.StoragePolicy)
+	require.Equal(t, testForwardMetadata.Pipeline.String(), elem.Pipeline)
+	require.Equal(t, 1, len(elem.Values))
+	require.Equal(t, float64(3), elem.Values[0].Aggregation["sum"])
+	require.Equal(t, float64(2), elem.Values[0].Aggregation["count"])
+	require.Equal(t, []uint{uint(testForwardMetadata.SourceID)}, elem.Values[0].SourcesSeen)
+	require.Equal(t, 0, len(m.Entries(id.RawID("baz"))))
+
+	// Look up the entries matching a filter.
+	excluded := testRollupID("foo", "host", "a")
+	matched := m.MatchingEntries(func(entryID id.RawID) bool { return !bytes.Equal(entryID, excluded) }, 0)
+	require.Equal(t, 2, len(matched))
+	for _, entry := range matched {
+		require.NotEqual(t, string(excluded), entry.ID)
+	}
+	matched = m.MatchingEntries(func(id.RawID) bool { return true }, 1)
+	require.Equal(t, 1, len(matched))
+
+	// The largest entries are returned largest first.
+	largest := m.LargestEntries(2)
+	require.Equal(t, 2, len(largest))
+	require.Equal(t, string(testRollupID("foo", "host", "ccc")), largest[0].ID)
+	require.Equal(t, string(testRollupID("foo", "host", "bb")), largest[1].ID)
+	require.True(t, largest[0].EstimatedBytes > largest[1].EstimatedBytes)
+	require.Equal(t, 0, len(m.LargestEntries(0)))
+}
+
 func TestMetricMapDeleteExpired(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -47,6 +47,7 @@ var (
 	defaultEntryCheckBatchPercent     = 0.01
 	defaultMaxTimerBatchSizePerWrite  = 0
 	defaultMaxNumCachedSourceSets     = 2
+	defaultOverflowSourceSetCapacity  = 1 << 14
 	defaultDiscardNaNAggregatedValues = true
 	defaultResignTimeout              = 5 * time.Minute
 	defaultDefaultStoragePolicies     = []policy.StoragePolicy{
@@ -81,6 +82,11 @@ type MaxAllowedForwardingDelayFn func(resolution time.Duration, numForwardedTime
 // BufferForPastTimedMetricFn returns the buffer duration for past timed metrics.
 type BufferForPastTimedMetricFn func(resolution time.Duration) time.Duration
 
+// AllowedLatenessFn returns how long aggregation windows for the given storage
+// policy are kept after they are flushed so that late timed metrics can correct
+// them. A zero duration disables corrections for the storage policy.
+type AllowedLatenessFn func(sp policy.StoragePolicy) time.Duration
+
 // Options provide a set of base and derived options for the aggregator.
 type Options interface {
 	/// Read-write base options.
@@ -181,6 +187,18 @@ type Options interface {
 	// FlushTimesManager returns the flush times manager.
 	FlushTimesManager() FlushTimesManager
 
+	// SetCheckpointOptions sets the checkpoint options.
+	SetCheckpointOptions(value CheckpointOptions) Options
+
+	// CheckpointOptions returns the checkpoint options.
+	CheckpointOptions() CheckpointOptions
+
+	// SetCardinalityLimiter sets the limiter for the number of distinct rollup series.
+	SetCardinalityLimiter(value CardinalityLimiter) Options
+
+	// CardinalityLimiter returns the limiter for the number of distinct rollup series.
+	CardinalityLimiter() CardinalityLimiter
+
 	// SetElectionManager sets the election manager.
 	SetElectionManager(value ElectionManager) Options
 
@@ -249,6 +267,14 @@ type Options interface {
 	// BufferForPastTimedMetricFn returns the size of the buffer for timed metrics in the past.
 	BufferForPastTimedMetricFn() BufferForPastTimedMetricFn
 
+	// SetAllowedLatenessFn sets the function that determines the allowed lateness
+	// of timed metrics for a given storage policy.
+	SetAllowedLatenessFn(value AllowedLatenessFn) Options
+
+	// AllowedLatenessFn returns the function that determines the allowed lateness
+	// of timed metrics for a given storage policy.
+	AllowedLatenessFn() AllowedLatenessFn
+
 	// SetBufferForFutureTimedMetric sets the size of the buffer for timed metrics in the future.
 	SetBufferForFutureTimedMetric(value time.Duration) Options
 
@@ -261,6 +287,14 @@ type Options interface {
 	// MaxNumCachedSourceSets returns the maximum number of cached source sets.
 	MaxNumCachedSourceSets() int
 
+	// SetOverflowSourceSetCapacity sets the number of sources and original
+	// series whose values an aggregation of an overflow series deduplicates.
+	SetOverflowSourceSetCapacity(value int) Options
+
+	// OverflowSourceSetCapacity returns the number of sources and original
+	// series whose values an aggregation of an overflow series deduplicates.
+	OverflowSourceSetCapacity() int
+
 	// SetDiscardNaNAggregatedValues determines whether NaN aggregated values are discarded.
 	SetDiscardNaNAggregatedValues(value bool) Options
 
@@ -334,12 +368,16 @@ type options struct {
 	maxTimerBatchSizePerWrite        int
 	defaultStoragePolicies           []policy.StoragePolicy
 	flushTimesManager                FlushTimesManager
+	checkpointOpts                   CheckpointOptions
+	cardinalityLimiter               CardinalityLimiter
 	electionManager                  ElectionManager
 	resignTimeout                    time.Duration
 	maxAllowedForwardingDelayFn      MaxAllowedForwardingDelayFn
 	bufferForPastTimedMetricFn       BufferForPastTimedMetricFn
+	allowedLatenessFn                AllowedLatenessFn
 	bufferForFutureTimedMetric       time.Duration
 	maxNumCachedSourceSets           int
+	overflowSourceSetCapacity        int
 	discardNaNAggregatedValues       bool
 	entryPool                        EntryPool
 	counterElemPool                  CounterElemPool
@@ -372,6 +410,8 @@ func NewOptions() Options {
 		streamOpts:                       cm.NewOptions(),
 		runtimeOptsManager:               runtime.NewOptionsManager(runtime.NewOptions()),
 		shardFn:                          sharding.Murmur32Hash.MustShardFn(),
+		checkpointOpts:                   NewCheckpointOptions(),
+		cardinalityLimiter:               NewCardinalityLimiter(NewCardinalityLimitOptions()),
 		bufferDurationBeforeShardCutover: defaultBufferDurationBeforeShardCutover,
 		bufferDurationAfterShardCutoff:   defaultBufferDurationAfterShardCutoff,
 		entryTTL:                         defaultEntryTTL,
@@ -382,8 +422,10 @@ func NewOptions() Options {
 		resignTimeout:                    defaultResignTimeout,
 		maxAllowedForwardingDelayFn:      defaultMaxAllowedForwardingDelayFn,
 		bufferForPastTimedMetricFn:       defaultBufferForPastTimedMetricFn,
+		allowedLatenessFn:                defaultAllowedLatenessFn,
 		bufferForFutureTimedMetric:       defaultTimedMetricBuffer,
 		maxNumCachedSourceSets:           defaultMaxNumCachedSourceSets,
+		overflowSourceSetCapacity:        defaultOverflowSourceSetCapacity,
 		discardNaNAggregatedValues:       defaultDiscardNaNAggregatedValues,
 		verboseErrors:                    defaultVerboseErrors,
 	}
@@ -561,6 +603,26 @@ func (o *options) FlushTimesManager() FlushTimesManager {
 	return o.flushTimesManager
 }
 
+func (o *options) SetCheckpointOptions(value CheckpointOptions) Options {
+	opts := *o
+	opts.checkpointOpts = value
+	return &opts
+}
+
+func (o *options) CheckpointOptions() CheckpointOptions {
+	return o.checkpointOpts
+}
+
+func (o *options) SetCardinalityLimiter(value CardinalityLimiter) Options {
+	opts := *o
+	opts.cardinalityLimiter = value
+	return &opts
+}
+
+func (o *options) CardinalityLimiter() CardinalityLimiter {
+	return o.cardinalityLimiter
+}
+
 func (o *options) SetElectionManager(value ElectionManager) Options {
 	opts := *o
 	opts.electionManager = value
@@ -671,6 +733,16 @@ func (o *options) BufferForPastTimedMetricFn() BufferForPastTimedMetricFn {
 	return o.bufferForPastTimedMetricFn
 }
 
+func (o *options) SetAllowedLatenessFn(value AllowedLatenessFn) Options {
+	opts := *o
+	opts.allowedLatenessFn = value
+	return &opts
+}
+
+func (o *options) AllowedLatenessFn() AllowedLatenessFn {
+	return o.allowedLatenessFn
+}
+
 func (o *options) SetBufferForFutureTimedMetric(value time.Duration) Options {
 	opts := *o
 	opts.bufferForFutureTimedMetric = value
@@ -691,6 +763,16 @@ func (o *options) MaxNumCachedSourceSets() int {
 	return o.maxNumCachedSourceSets
 }
 
+func (o *options) SetOverflowSourceSetCapacity(value int) Options {
+	opts := *o
+	opts.overflowSourceSetCapacity = value
+	return &opts
+}
+
+func (o *options) OverflowSourceSetCapacity() int {
+	return o.overflowSourceSetCapacity
+}
+
 func (o *options) SetDiscardNaNAggregatedValues(value bool) Options {
 	opts := *o
 	opts.discardNaNAggregatedValues = value
@@ -831,3 +913,7 @@ func defaultMaxAllowedForwardingDelayFn(
 func defaultBufferForPastTimedMetricFn(resolution time.Duration) time.Duration {
 	return resolution + defaultTimedMetricBuffer
 }
+
+func defaultAllowedLatenessFn(policy.StoragePolicy) time.Duration {
+	return 0
+}
@@ -29,8 +29,10 @@ import (
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/aggregator/runtime"
+	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -173,11 +175,32 @@ func TestSetTimedAggregationBufferPastFn(t *testing.T) {
 	require.Equal(t, 2*time.Minute, fn(time.Minute))
 }
 
+func TestSetAllowedLatenessFn(t *testing.T) {
+	sp := policy.NewStoragePolicy(time.Minute, xtime.Minute, 48*time.Hour)
+	require.Equal(t, time.Duration(0), NewOptions().AllowedLatenessFn()(sp))
+
+	value := func(policy.StoragePolicy) time.Duration { return 10 * time.Minute }
+	o := NewOptions().SetAllowedLatenessFn(value)
+	require.Equal(t, 10*time.Minute, o.AllowedLatenessFn()(sp))
+}
+
 func TestSetTimedAggregationBufferFutureFn(t *testing.T) {
 	o := NewOptions().SetBufferForFutureTimedMetric(3 * time.Minute)
 	require.Equal(t, 3*time.Minute, o.BufferForFutureTimedMetric())
 }
 
+func TestSetCheckpointOptions(t *testing.T) {
+	value := NewCheckpointOptions().SetEnabled(true).SetDirectory("/tmp/checkpoints")
+	o := NewOptions().SetCheckpointOptions(value)
+	require.Equal(t, value, o.CheckpointOptions())
+}
+
+func TestSetCardinalityLimiter(t *testing.T) {
+	value := NewCardinalityLimiter(NewCardinalityLimitOptions().SetMaxSeriesPerRollup(10))
+	o := NewOptions().SetCardinalityLimiter(value)
+	require.Equal(t, value, o.CardinalityLimiter())
+}
+
 func TestSetEntryCheckInterval(t *testing.T) {
 	value := time.Minute
 	o := NewOptions().SetEntryCheckInterval(value)
@@ -202,6 +225,12 @@ func TestSetMaxNumCachedSourceSets(t *testing.T) {
 	require.Equal(t, value, o.MaxNumCachedSourceSets())
 }
 
+func TestSetOverflowSourceSetCapacity(t *testing.T) {
+	value := 1024
+	o := NewOptions().SetOverflowSourceSetCapacity(value)
+	require.Equal(t, value, o.OverflowSourceSetCapacity())
+}
+
 func TestSetDiscardNaNAggregatedValues(t *testing.T) {
 	value := false
 	o := NewOptions().SetDiscardNaNAggregatedValues(value)
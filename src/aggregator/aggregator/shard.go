@@ -27,8 +27,10 @@ import (
 	"sync"
 	"time"
 
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	metricid "github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/x/clock"
 
@@ -228,6 +230,66 @@ func (s *aggregatorShard) Tick(target time.Duration) tickResult {
 	return s.metricMap.Tick(target)
 }
 
+// WriteCheckpoint writes a checkpoint of the aggregations in the shard.
+func (s *aggregatorShard) WriteCheckpoint(c *checkpointer) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.closed {
+		return errAggregatorShardClosed
+	}
+	return c.Write(s.shard, s.metricMap)
+}
+
+// RestoreCheckpoint restores the aggregations in the shard from its checkpoint.
+func (s *aggregatorShard) RestoreCheckpoint(
+	c *checkpointer,
+	flushTimes *schema.ShardSetFlushTimes,
+) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.closed {
+		return errAggregatorShardClosed
+	}
+	return c.Restore(s.shard, s.metricMap, flushTimes)
+}
+
+// Entries returns descriptions of the entries in the shard for the given id.
+func (s *aggregatorShard) Entries(id metricid.RawID) ([]EntryInfo, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.closed {
+		return nil, errAggregatorShardClosed
+	}
+	return s.metricMap.Entries(id), nil
+}
+
+// MatchingEntries returns descriptions of the entries in the shard whose ids
+// match, up to the given limit.
+func (s *aggregatorShard) MatchingEntries(matchFn entryIDMatchFn, limit int) ([]EntryInfo, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.closed {
+		return nil, errAggregatorShardClosed
+	}
+	return s.metricMap.MatchingEntries(matchFn, limit), nil
+}
+
+// LargestEntries returns descriptions of the entries in the shard with the
+// largest estimated memory use.
+func (s *aggregatorShard) LargestEntries(limit int) ([]EntryInfo, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.closed {
+		return nil, errAggregatorShardClosed
+	}
+	return s.metricMap.LargestEntries(limit), nil
+}
+
 func (s *aggregatorShard) Close() {
 	s.Lock()
 	defer s.Unlock()
@@ -24,29 +24,47 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/filters"
 	xerrors "github.com/m3db/m3/src/x/errors"
 )
 
 // A list of HTTP endpoints.
 const (
-	HealthPath = "/health"
-	ResignPath = "/resign"
-	StatusPath = "/status"
+	HealthPath         = "/health"
+	ResignPath         = "/resign"
+	StatusPath         = "/status"
+	EntriesPath        = "/entries"
+	LargestEntriesPath = "/entries/largest"
+)
+
+const (
+	idParam                       = "id"
+	filterParam                   = "filter"
+	limitParam                    = "limit"
+	defaultEntriesLimit           = 100
+	maxEntriesLimit               = 10000
+	defaultLargestEntriesPerShard = 10
+	maxLargestEntriesPerShard     = 1000
 )
 
 var (
-	errRequestMustBeGet  = xerrors.NewInvalidParamsError(errors.New("request must be GET"))
-	errRequestMustBePost = xerrors.NewInvalidParamsError(errors.New("request must be POST"))
+	errRequestMustBeGet     = xerrors.NewInvalidParamsError(errors.New("request must be GET"))
+	errRequestMustBePost    = xerrors.NewInvalidParamsError(errors.New("request must be POST"))
+	errEntriesQueryRequired = xerrors.NewInvalidParamsError(errors.New("either id or filter must be specified"))
 )
 
-func registerHandlers(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+func registerHandlers(mux *http.ServeMux, aggregator aggregator.Aggregator, opts Options) {
 	registerHealthHandler(mux)
 	registerResignHandler(mux, aggregator)
 	registerStatusHandler(mux, aggregator)
+	registerEntriesHandler(mux, aggregator, opts)
+	registerLargestEntriesHandler(mux, aggregator)
 }
 
 func registerHealthHandler(mux *http.ServeMux) {
@@ -92,6 +110,99 @@ func registerStatusHandler(mux *http.ServeMux, aggregator aggregator.Aggregator)
 	})
 }
 
+func registerEntriesHandler(mux *http.ServeMux, aggregator aggregator.Aggregator, opts Options) {
+	mux.HandleFunc(EntriesPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if httpMethod := strings.ToUpper(r.Method); httpMethod != http.MethodGet {
+			writeErrorResponse(w, errRequestMustBeGet)
+			return
+		}
+
+		query, err := parseEntriesQuery(r, opts)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		entries, err := aggregator.Entries(query)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		writeEntriesResponse(w, entries)
+	})
+}
+
+func registerLargestEntriesHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(LargestEntriesPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if httpMethod := strings.ToUpper(r.Method); httpMethod != http.MethodGet {
+			writeErrorResponse(w, errRequestMustBeGet)
+			return
+		}
+
+		limit, err := parseLimit(r, defaultLargestEntriesPerShard,
+			maxLargestEntriesPerShard)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		shards, err := aggregator.LargestEntries(limit)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		writeLargestEntriesResponse(w, shards)
+	})
+}
+
+func parseEntriesQuery(r *http.Request, opts Options) (aggregator.EntriesQuery, error) {
+	var (
+		values = r.URL.Query()
+		id     = values.Get(idParam)
+		filter = values.Get(filterParam)
+	)
+	limit, err := parseLimit(r, defaultEntriesLimit, maxEntriesLimit)
+	if err != nil {
+		return aggregator.EntriesQuery{}, err
+	}
+	if id != "" {
+		return aggregator.EntriesQuery{ID: []byte(id), Limit: limit}, nil
+	}
+	if filter == "" {
+		return aggregator.EntriesQuery{}, errEntriesQueryRequired
+	}
+	filterValues, err := filters.ParseTagFilterValueMap(filter)
+	if err != nil {
+		return aggregator.EntriesQuery{}, xerrors.NewInvalidParamsError(err)
+	}
+	tagsFilter, err := filters.NewTagsFilter(filterValues, filters.Conjunction, opts.TagsFilterOptions())
+	if err != nil {
+		return aggregator.EntriesQuery{}, xerrors.NewInvalidParamsError(err)
+	}
+	return aggregator.EntriesQuery{Filter: tagsFilter, Limit: limit}, nil
+}
+
+// parseLimit returns the limit of the request, or the default limit if not
+// set. The limit must be positive and at most the max limit so that a request
+// cannot return an unbounded number of entries.
+func parseLimit(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	str := r.URL.Query().Get(limitParam)
+	if str == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(str)
+	if err != nil || limit <= 0 {
+		return 0, xerrors.NewInvalidParamsError(fmt.Errorf("invalid limit: %s", str))
+	}
+	if limit > maxLimit {
+		return 0, xerrors.NewInvalidParamsError(
+			fmt.Errorf("limit %d exceeds max limit %d", limit, maxLimit))
+	}
+	return limit, nil
+}
+
 // Response is an HTTP response.
 type Response struct {
 	State string `json:"state,omitempty"`
@@ -104,12 +215,31 @@ type StatusResponse struct {
 	Status aggregator.RuntimeStatus `json:"status,omitempty"`
 }
 
+// EntriesResponse is a response describing live entries.
+type EntriesResponse struct {
+	Response
+	Entries []aggregator.EntryInfo `json:"entries"`
+}
+
+// LargestEntriesResponse is a response describing the largest live entries
+// in each shard.
+type LargestEntriesResponse struct {
+	Response
+	Shards []aggregator.ShardEntries `json:"shards"`
+}
+
 // NewResponse creates a new empty response.
 func NewResponse() Response { return Response{} }
 
 // NewStatusResponse creates a new empty status response.
 func NewStatusResponse() StatusResponse { return StatusResponse{} }
 
+// NewEntriesResponse creates a new empty entries response.
+func NewEntriesResponse() EntriesResponse { return EntriesResponse{} }
+
+// NewLargestEntriesResponse creates a new empty largest entries response.
+func NewLargestEntriesResponse() LargestEntriesResponse { return LargestEntriesResponse{} }
+
 func newSuccessResponse() Response {
 	return Response{State: "OK"}
 }
@@ -137,6 +267,18 @@ func writeStatusResponse(w http.ResponseWriter, status aggregator.RuntimeStatus)
 	writeResponse(w, response, nil)
 }
 
+func writeEntriesResponse(w http.ResponseWriter, entries []aggregator.EntryInfo) {
+	response := NewEntriesResponse()
+	response.Entries = entries
+	writeResponse(w, response, nil)
+}
+
+func writeLargestEntriesResponse(w http.ResponseWriter, shards []aggregator.ShardEntries) {
+	response := NewLargestEntriesResponse()
+	response.Shards = shards
+	writeResponse(w, response, nil)
+}
+
 func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
 	buf := bytes.NewBuffer(nil)
 	if encodeErr := json.NewEncoder(buf).Encode(&resp); encodeErr != nil {
@@ -20,13 +20,20 @@
 
 package http
 
-import "time"
+import (
+	"time"
+
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+)
 
 const (
 	defaultReadTimeout  = 10 * time.Second
 	defaultWriteTimeout = 10 * time.Second
 )
 
+var defaultNameTagKey = []byte("name")
+
 // Options is a set of server options.
 type Options interface {
 	// SetReadTimeout sets the read timeout.
@@ -40,11 +47,20 @@ type Options interface {
 
 	// WriteTimeout returns the write timeout.
 	WriteTimeout() time.Duration
+
+	// SetTagsFilterOptions sets the options used to build the tag filters
+	// for selecting entries.
+	SetTagsFilterOptions(value filters.TagsFilterOptions) Options
+
+	// TagsFilterOptions returns the options used to build the tag filters
+	// for selecting entries.
+	TagsFilterOptions() filters.TagsFilterOptions
 }
 
 type options struct {
-	readTimeout  time.Duration
-	writeTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	tagsFilterOptions filters.TagsFilterOptions
 }
 
 // NewOptions creates a new set of server options.
@@ -52,6 +68,11 @@ func NewOptions() Options {
 	return &options{
 		readTimeout:  defaultReadTimeout,
 		writeTimeout: defaultWriteTimeout,
+		tagsFilterOptions: filters.TagsFilterOptions{
+			NameTagKey:          defaultNameTagKey,
+			NameAndTagsFn:       m3.NameAndTags,
+			SortedTagIteratorFn: m3.NewSortedTagIterator,
+		},
 	}
 }
 
@@ -74,3 +95,13 @@ func (o *options) SetWriteTimeout(value time.Duration) Options {
 func (o *options) WriteTimeout() time.Duration {
 	return o.writeTimeout
 }
+
+func (o *options) SetTagsFilterOptions(value filters.TagsFilterOptions) Options {
+	opts := *o
+	opts.tagsFilterOptions = value
+	return &opts
+}
+
+func (o *options) TagsFilterOptions() filters.TagsFilterOptions {
+	return o.tagsFilterOptions
+}
@@ -72,7 +72,7 @@ func (s *server) ListenAndServe() error {
 
 func (s *server) Serve(l net.Listener) error {
 	mux := http.NewServeMux()
-	registerHandlers(mux, s.aggregator)
+	registerHandlers(mux, s.aggregator, s.opts)
 	pprof.RegisterHandler(mux)
 
 	// create and register debug handler
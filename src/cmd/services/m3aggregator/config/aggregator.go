@@ -41,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/metrics/aggregation"
+	metricid "github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/pipeline/applied"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
@@ -100,12 +101,22 @@ type AggregatorConfiguration struct {
 	// Amount of time we buffer timed metrics in the future.
 	BufferDurationForFutureTimedMetric time.Duration `yaml:"bufferDurationForFutureTimedMetric"`
 
+	// Amount of time timed metrics may arrive after their buffer in the past to
+	// correct aggregations already flushed, by storage policy.
+	AllowedLateness []allowedLatenessConfiguration `yaml:"allowedLateness"`
+
 	// Resign timeout.
 	ResignTimeout time.Duration `yaml:"resignTimeout"`
 
 	// Flush times manager.
 	FlushTimesManager flushTimesManagerConfiguration `yaml:"flushTimesManager"`
 
+	// Checkpointing of in-flight aggregations.
+	Checkpoint checkpointConfiguration `yaml:"checkpoint"`
+
+	// Limits on the number of distinct rollup series.
+	CardinalityLimits cardinalityLimitsConfiguration `yaml:"cardinalityLimits"`
+
 	// Election manager.
 	ElectionManager electionManagerConfiguration `yaml:"electionManager"`
 
@@ -136,6 +147,10 @@ type AggregatorConfiguration struct {
 	// Maximum number of cached source sets.
 	MaxNumCachedSourceSets *int `yaml:"maxNumCachedSourceSets"`
 
+	// Number of sources and original series whose values each aggregation of
+	// an overflow series deduplicates.
+	OverflowSourceSetCapacity *int `yaml:"overflowSourceSetCapacity"`
+
 	// Whether to discard NaN aggregated values.
 	DiscardNaNAggregatedValues *bool `yaml:"discardNaNAggregatedValues"`
 
@@ -235,6 +250,9 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	if c.BufferDurationForFutureTimedMetric != 0 {
 		opts = opts.SetBufferForFutureTimedMetric(c.BufferDurationForFutureTimedMetric)
 	}
+	if len(c.AllowedLateness) > 0 {
+		opts = opts.SetAllowedLatenessFn(allowedLatenessFn(c.AllowedLateness))
+	}
 
 	// Set resign timeout.
 	if c.ResignTimeout != 0 {
@@ -249,6 +267,14 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	}
 	opts = opts.SetFlushTimesManager(flushTimesManager)
 
+	// Set checkpoint options.
+	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("checkpoint"))
+	opts = opts.SetCheckpointOptions(c.Checkpoint.NewCheckpointOptions(iOpts))
+
+	// Set cardinality limiter.
+	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("cardinality-limiter"))
+	opts = opts.SetCardinalityLimiter(c.CardinalityLimits.NewCardinalityLimiter(iOpts))
+
 	// Set election manager.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("election-manager"))
 	placementNamespace := c.PlacementManager.KVConfig.Namespace
@@ -316,6 +342,9 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	if c.MaxNumCachedSourceSets != nil {
 		opts = opts.SetMaxNumCachedSourceSets(*c.MaxNumCachedSourceSets)
 	}
+	if c.OverflowSourceSetCapacity != nil {
+		opts = opts.SetOverflowSourceSetCapacity(*c.OverflowSourceSetCapacity)
+	}
 
 	// Set whether to discard NaN aggregated values.
 	if c.DiscardNaNAggregatedValues != nil {
@@ -386,6 +415,25 @@ func bufferForPastTimedMetricFn(buffer time.Duration) aggregator.BufferForPastTi
 	}
 }
 
+// allowedLatenessConfiguration contains the allowed lateness for a storage policy.
+type allowedLatenessConfiguration struct {
+	// Storage policy.
+	StoragePolicy policy.StoragePolicy `yaml:"storagePolicy"`
+
+	// Amount of time flushed aggregations are kept for late timed metrics.
+	Lateness time.Duration `yaml:"lateness" validate:"nonzero"`
+}
+
+func allowedLatenessFn(configs []allowedLatenessConfiguration) aggregator.AllowedLatenessFn {
+	latenessBySP := make(map[policy.StoragePolicy]time.Duration, len(configs))
+	for _, c := range configs {
+		latenessBySP[c.StoragePolicy] = c.Lateness
+	}
+	return func(sp policy.StoragePolicy) time.Duration {
+		return latenessBySP[sp]
+	}
+}
+
 // streamConfiguration contains configuration for quantile-related metric streams.
 type streamConfiguration struct {
 	// Error epsilon for quantile computation.
@@ -532,6 +580,75 @@ func (c flushTimesManagerConfiguration) NewFlushTimesManager(
 	return aggregator.NewFlushTimesManager(flushTimesManagerOpts), nil
 }
 
+type checkpointConfiguration struct {
+	// Whether checkpointing is enabled.
+	Enabled bool `yaml:"enabled"`
+
+	// Directory the checkpoints are written to.
+	Directory string `yaml:"directory"`
+
+	// How often checkpoints are written.
+	Interval time.Duration `yaml:"interval"`
+
+	// Checkpoints older than the max age are not restored.
+	MaxAge time.Duration `yaml:"maxAge"`
+}
+
+func (c checkpointConfiguration) NewCheckpointOptions(
+	instrumentOpts instrument.Options,
+) aggregator.CheckpointOptions {
+	opts := aggregator.NewCheckpointOptions().
+		SetInstrumentOptions(instrumentOpts).
+		SetEnabled(c.Enabled).
+		SetDirectory(c.Directory)
+	if c.Interval != 0 {
+		opts = opts.SetInterval(c.Interval)
+	}
+	if c.MaxAge != 0 {
+		opts = opts.SetMaxAge(c.MaxAge)
+	}
+	return opts
+}
+
+type cardinalityLimitsConfiguration struct {
+	// Maximum number of distinct series for each rollup metric name.
+	MaxSeriesPerRollup int `yaml:"maxSeriesPerRollup" validate:"min=0"`
+
+	// Name of the tag whose values have their number of distinct series limited.
+	TagName string `yaml:"tagName"`
+
+	// Maximum number of distinct series for each value of the tag.
+	MaxSeriesPerTagValue int `yaml:"maxSeriesPerTagValue" validate:"min=0"`
+
+	// Tag identifying the overflow series that series exceeding the limits
+	// are collapsed into.
+	OverflowTag *tagConfiguration `yaml:"overflowTag"`
+}
+
+type tagConfiguration struct {
+	Name  string `yaml:"name" validate:"nonzero"`
+	Value string `yaml:"value" validate:"nonzero"`
+}
+
+func (c cardinalityLimitsConfiguration) NewCardinalityLimiter(
+	instrumentOpts instrument.Options,
+) aggregator.CardinalityLimiter {
+	opts := aggregator.NewCardinalityLimitOptions().
+		SetInstrumentOptions(instrumentOpts).
+		SetMaxSeriesPerRollup(c.MaxSeriesPerRollup).
+		SetMaxSeriesPerTagValue(c.MaxSeriesPerTagValue)
+	if c.TagName != "" {
+		opts = opts.SetLimitTagName([]byte(c.TagName))
+	}
+	if c.OverflowTag != nil {
+		opts = opts.SetOverflowTagPair(metricid.TagPair{
+			Name:  []byte(c.OverflowTag.Name),
+			Value: []byte(c.OverflowTag.Value),
+		})
+	}
+	return aggregator.NewCardinalityLimiter(opts)
+}
+
 type electionManagerConfiguration struct {
 	Election                   electionConfiguration  `yaml:"election"`
 	ServiceID                  serviceIDConfiguration `yaml:"serviceID"`
@@ -24,6 +24,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/policy"
+
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -118,3 +120,29 @@ func TestMaxAllowedForwardingDelayFnJitterDisabled(t *testing.T) {
 		require.Equal(t, input.expected, fn(input.resolution, input.numForwardedTimes))
 	}
 }
+
+func TestAllowedLatenessFn(t *testing.T) {
+	str := `
+- storagePolicy: 10s:2d
+  lateness: 10m
+- storagePolicy: 1m:40d
+  lateness: 1h
+`
+	var configs []allowedLatenessConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &configs))
+	fn := allowedLatenessFn(configs)
+
+	inputs := []struct {
+		storagePolicy string
+		expected      time.Duration
+	}{
+		{storagePolicy: "10s:2d", expected: 10 * time.Minute},
+		{storagePolicy: "1m:40d", expected: time.Hour},
+		{storagePolicy: "1m:2d", expected: 0},
+	}
+	for _, input := range inputs {
+		sp, err := policy.ParseStoragePolicy(input.storagePolicy)
+		require.NoError(t, err)
+		require.Equal(t, input.expected, fn(sp))
+	}
+}
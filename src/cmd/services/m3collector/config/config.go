@@ -23,6 +23,7 @@ package config
 import (
 	"github.com/m3db/m3/src/aggregator/client"
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	"github.com/m3db/m3/src/collector/statsd"
 	"github.com/m3db/m3/src/metrics/matcher"
 	"github.com/m3db/m3/src/metrics/matcher/cache"
 	"github.com/m3db/m3/src/x/clock"
@@ -40,6 +41,7 @@ type Configuration struct {
 	ListenAddress listenaddress.Configuration     `yaml:"listenAddress" validate:"nonzero"`
 	Etcd          etcdclient.Configuration        `yaml:"etcd"`
 	Reporter      ReporterConfiguration           `yaml:"reporter"`
+	StatsD        *statsd.Configuration           `yaml:"statsd"`
 }
 
 // ReporterConfiguration is the collector
@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/serialize"
 	xsync "github.com/m3db/m3/src/x/sync"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
@@ -52,6 +53,10 @@ var (
 
 var (
 	aggregationSuffixTag = []byte("agg")
+	// slidingWindowTag distinguishes sliding window outputs from the tumbling
+	// window outputs with the same resolution and retention, which would
+	// otherwise be written to the same series.
+	slidingWindowTag = []byte("sliding_window")
 )
 
 type downsamplerFlushHandler struct {
@@ -133,6 +138,9 @@ func (w *downsamplerFlushHandlerWriter) Write(
 		if len(chunkSuffix) != 0 {
 			expected++
 		}
+		if mp.StoragePolicy.IsSlidingWindow() {
+			expected++
+		}
 
 		tags := models.NewTags(expected, w.tagOptions)
 		for iter.Next() {
@@ -158,6 +166,12 @@ func (w *downsamplerFlushHandlerWriter) Write(
 		if len(chunkSuffix) != 0 {
 			tags = tags.AddTag(models.Tag{Name: aggregationSuffixTag, Value: chunkSuffix}.Clone())
 		}
+		if mp.StoragePolicy.IsSlidingWindow() {
+			tags = tags.AddTag(models.Tag{
+				Name:  slidingWindowTag,
+				Value: []byte(xtime.ToExtendedString(mp.StoragePolicy.SlidingWindow())),
+			})
+		}
 
 		err := iter.Err()
 		iter.Close()
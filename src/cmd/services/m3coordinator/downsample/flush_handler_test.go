@@ -104,3 +104,60 @@ func TestDownsamplerFlushHandlerCopiesTags(t *testing.T) {
 	assert.False(t, xtest.ByteSlicesBackedBySameData(tagName, tag.Name))
 	assert.False(t, xtest.ByteSlicesBackedBySameData(tagValue, tag.Value))
 }
+
+func TestDownsamplerFlushHandlerTagsSlidingWindowOutputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock.NewMockStorage()
+	pool := serialize.NewMockMetricTagsIteratorPool(ctrl)
+
+	workers := xsync.NewWorkerPool(1)
+	workers.Init()
+
+	handler := newDownsamplerFlushHandler(store, pool,
+		workers, models.NewTagOptions(), instrument.NewOptions())
+	writer, err := handler.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+
+	var (
+		expectedID = []byte("foo")
+		tagName    = []byte("name")
+		tagValue   = []byte("value")
+	)
+	for _, sp := range []string{"10s:2d", "10s~5m:2d"} {
+		iter := serialize.NewMockMetricTagsIterator(ctrl)
+		gomock.InOrder(
+			iter.EXPECT().Reset(expectedID),
+			iter.EXPECT().NumTags().Return(1),
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(tagName, tagValue),
+			iter.EXPECT().Next().Return(false),
+			iter.EXPECT().Err().Return(nil),
+			iter.EXPECT().Close(),
+		)
+		pool.EXPECT().Get().Return(iter)
+
+		err = writer.Write(aggregated.ChunkedMetricWithStoragePolicy{
+			ChunkedMetric: aggregated.ChunkedMetric{
+				ChunkedID: id.ChunkedID{Data: expectedID},
+				TimeNanos: 123,
+				Value:     42.42,
+			},
+			StoragePolicy: policy.MustParseStoragePolicy(sp),
+		})
+		require.NoError(t, err)
+		require.NoError(t, writer.Flush())
+	}
+
+	writes := store.Writes()
+	require.Equal(t, 2, len(writes))
+	require.Equal(t, writes[0].Attributes, writes[1].Attributes)
+
+	require.Equal(t, 1, len(writes[0].Tags.Tags))
+	tags := writes[1].Tags.Tags
+	require.Equal(t, 2, len(tags))
+	assert.True(t, bytes.Equal(slidingWindowTag, tags[1].Name))
+	assert.Equal(t, "5m", string(tags[1].Value))
+	assert.NotEqual(t, writes[0].Tags.ID(), writes[1].Tags.ID())
+}
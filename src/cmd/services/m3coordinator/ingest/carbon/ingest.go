@@ -21,12 +21,16 @@
 package ingestcarbon
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"regexp"
+	"strconv"
 	"sync"
 	"time"
 
@@ -36,6 +40,7 @@ import (
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/carbon"
 	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/api/v1/handler/graphite/pickle"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -53,6 +58,11 @@ const (
 	maxResourcePoolNameSize = 1024
 	maxPooledTagsSize       = 16
 	defaultResourcePoolSize = 4096
+
+	// maxPickleMessageSize matches the MAX_LENGTH enforced by the carbon
+	// pickle receiver.
+	maxPickleMessageSize = 1024 * 1024
+	pickleHeaderSize     = 4
 )
 
 var (
@@ -63,8 +73,25 @@ var (
 	errCannotGenerateTagsFromEmptyName = errors.New("cannot generate tags from empty name")
 	errIOptsMustBeSet                  = errors.New("carbon ingester options: instrument options must be st")
 	errWorkerPoolMustBeSet             = errors.New("carbon ingester options: worker pool must be set")
+	errPickleMessageTooLarge           = errors.New("carbon pickle message too large")
+
+	// Tag names with this prefix are reserved for the path tags.
+	reservedTagNamePrefix = []byte("__")
 )
 
+// Ingester handles carbon metrics sent in the plaintext protocol over TCP
+// and UDP as well as in the pickle protocol over TCP.
+type Ingester interface {
+	m3xserver.Handler
+
+	// PickleHandler returns a handler for connections sending length
+	// prefixed pickled metrics.
+	PickleHandler() m3xserver.Handler
+
+	// HandlePacket handles a UDP packet containing plaintext carbon lines.
+	HandlePacket(packet []byte)
+}
+
 // Options configures the ingester.
 type Options struct {
 	Debug             bool
@@ -95,7 +122,7 @@ func NewIngester(
 	downsamplerAndWriter ingest.DownsamplerAndWriter,
 	rules CarbonIngesterRules,
 	opts Options,
-) (m3xserver.Handler, error) {
+) (Ingester, error) {
 	err := opts.Validate()
 	if err != nil {
 		return nil, err
@@ -154,49 +181,70 @@ type ingester struct {
 }
 
 func (i *ingester) Handle(conn net.Conn) {
+	i.logger.Debug("handling new carbon ingestion connection")
+	i.handlePlaintext(conn)
+	// Don't close the connection, that is the server's responsibility.
+}
+
+func (i *ingester) HandlePacket(packet []byte) {
+	i.handlePlaintext(bytes.NewReader(packet))
+}
+
+func (i *ingester) PickleHandler() m3xserver.Handler {
+	return &pickleHandler{ingester: i}
+}
+
+func (i *ingester) handlePlaintext(r io.Reader) {
 	var (
-		// Interfaces require a context be passed, but M3DB client already has timeouts
-		// built in and allocating a new context each time is expensive so we just pass
-		// the same context always and rely on M3DB client timeouts.
-		ctx    = context.Background()
-		wg     = sync.WaitGroup{}
-		s      = carbon.NewScanner(conn, i.opts.InstrumentOptions)
-		logger = i.opts.InstrumentOptions.Logger()
+		wg = sync.WaitGroup{}
+		s  = carbon.NewScanner(r, i.opts.InstrumentOptions)
 	)
 
-	logger.Debug("handling new carbon ingestion connection")
 	for s.Scan() {
 		name, timestamp, value := s.Metric()
-
-		resources := i.getLineResources()
-		// Copy name since scanner bytes are recycled.
-		resources.name = append(resources.name[:0], name...)
-
-		wg.Add(1)
-		i.opts.WorkerPool.Go(func() {
-			ok := i.write(ctx, resources, timestamp, value)
-			if ok {
-				i.metrics.success.Inc(1)
-			}
-			// The contract is that after the DownsamplerAndWriter returns, any resources
-			// that it needed to hold onto have already been copied.
-			i.putLineResources(resources)
-			wg.Done()
-		})
+		i.writeAsync(&wg, name, timestamp, value)
 
 		i.metrics.malformed.Inc(int64(s.MalformedCount))
 		s.MalformedCount = 0
 	}
 
 	if err := s.Err(); err != nil {
-		logger.Error("encountered error during carbon ingestion when scanning connection", zap.Error(err))
+		i.logger.Error("encountered error during carbon ingestion when scanning connection", zap.Error(err))
 	}
 
-	logger.Debug("waiting for outstanding carbon ingestion writes to complete")
+	i.logger.Debug("waiting for outstanding carbon ingestion writes to complete")
 	wg.Wait()
-	logger.Debug("all outstanding writes completed, shutting down carbon ingestion handler")
+	i.logger.Debug("all outstanding writes completed, shutting down carbon ingestion handler")
+}
 
-	// Don't close the connection, that is the server's responsibility.
+// writeAsync copies the name and writes the metric using the worker pool,
+// the wait group is marked done once the write completes.
+func (i *ingester) writeAsync(
+	wg *sync.WaitGroup,
+	name []byte,
+	timestamp time.Time,
+	value float64,
+) {
+	// Interfaces require a context be passed, but M3DB client already has timeouts
+	// built in and allocating a new context each time is expensive so we just pass
+	// the same context always and rely on M3DB client timeouts.
+	ctx := context.Background()
+
+	resources := i.getLineResources()
+	// Copy name since scanner bytes are recycled.
+	resources.name = append(resources.name[:0], name...)
+
+	wg.Add(1)
+	i.opts.WorkerPool.Go(func() {
+		ok := i.write(ctx, resources, timestamp, value)
+		if ok {
+			i.metrics.success.Inc(1)
+		}
+		// The contract is that after the DownsamplerAndWriter returns, any resources
+		// that it needed to hold onto have already been copied.
+		i.putLineResources(resources)
+		wg.Done()
+	})
 }
 
 func (i *ingester) write(
@@ -274,6 +322,151 @@ func (i *ingester) Close() {
 	// We don't maintain any state in-between connections so there is nothing to do here.
 }
 
+// pickleHandler handles connections from carbon relays using the pickle
+// protocol, each message is a 4 byte big endian length followed by a
+// pickled list of (path, (timestamp, value)) tuples.
+type pickleHandler struct {
+	ingester *ingester
+}
+
+func (h *pickleHandler) Handle(conn net.Conn) {
+	var (
+		i       = h.ingester
+		wg      = sync.WaitGroup{}
+		r       = bufio.NewReader(conn)
+		header  = make([]byte, pickleHeaderSize)
+		payload []byte
+		err     error
+	)
+
+	i.logger.Debug("handling new carbon pickle ingestion connection")
+	for {
+		payload, err = readPickleMessage(r, header, payload)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// We can't find the start of the next message once the framing is
+			// lost so stop reading from the connection.
+			i.logger.Error("encountered error during carbon pickle ingestion when reading connection",
+				zap.Error(err))
+			i.metrics.malformed.Inc(1)
+			break
+		}
+
+		i.handlePickle(&wg, payload)
+	}
+
+	i.logger.Debug("waiting for outstanding carbon pickle ingestion writes to complete")
+	wg.Wait()
+	i.logger.Debug("all outstanding writes completed, shutting down carbon pickle ingestion handler")
+
+	// Don't close the connection, that is the server's responsibility.
+}
+
+func (h *pickleHandler) Close() {
+	// We don't maintain any state in-between connections so there is nothing to do here.
+}
+
+func readPickleMessage(r io.Reader, header, buf []byte) ([]byte, error) {
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > maxPickleMessageSize {
+		return nil, errPickleMessageTooLarge
+	}
+
+	if cap(buf) < int(size) {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (i *ingester) handlePickle(wg *sync.WaitGroup, payload []byte) {
+	value, err := pickle.NewReader(payload).Read()
+	if err != nil {
+		i.logger.Error("err unpickling carbon metrics", zap.Error(err))
+		i.metrics.malformed.Inc(1)
+		return
+	}
+
+	metrics, ok := value.([]interface{})
+	if !ok {
+		i.logger.Error("carbon pickle message is not a list",
+			zap.String("type", fmt.Sprintf("%T", value)))
+		i.metrics.malformed.Inc(1)
+		return
+	}
+
+	for _, metric := range metrics {
+		name, timestamp, value, ok := parsePickleMetric(metric)
+		if !ok {
+			i.metrics.malformed.Inc(1)
+			continue
+		}
+
+		i.writeAsync(wg, name, timestamp, value)
+	}
+}
+
+// parsePickleMetric parses a (path, (timestamp, value)) tuple, timestamps
+// are truncated to seconds in the same way as the plaintext protocol.
+func parsePickleMetric(
+	metric interface{},
+) ([]byte, time.Time, float64, bool) {
+	tuple, ok := metric.([]interface{})
+	if !ok || len(tuple) != 2 {
+		return nil, time.Time{}, 0, false
+	}
+
+	name, ok := tuple[0].(string)
+	if !ok || len(name) == 0 {
+		return nil, time.Time{}, 0, false
+	}
+
+	datapoint, ok := tuple[1].([]interface{})
+	if !ok || len(datapoint) != 2 {
+		return nil, time.Time{}, 0, false
+	}
+
+	timestamp, ok := pickleNumber(datapoint[0])
+	if !ok {
+		return nil, time.Time{}, 0, false
+	}
+
+	value, ok := pickleNumber(datapoint[1])
+	if !ok {
+		return nil, time.Time{}, 0, false
+	}
+
+	return []byte(name), time.Unix(int64(timestamp), 0), value, true
+}
+
+// pickleNumber returns the numeric value of an unpickled value, relays
+// may forward values as strings if they were received as such.
+func pickleNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func newCarbonIngesterMetrics(m tally.Scope) carbonIngesterMetrics {
 	return carbonIngesterMetrics{
 		success:   m.Counter("success"),
@@ -295,6 +488,9 @@ type carbonIngesterMetrics struct {
 //      __g0__:foo
 //      __g1__:bar
 //      __g2__:baz
+// Graphite tagged names such as foo.bar;env=prod are also accepted in which
+// case the tags are added alongside the path tags, i.e. env:prod in addition
+// to the tags for foo.bar.
 func GenerateTagsFromName(
 	name []byte,
 	opts models.TagOptions,
@@ -321,7 +517,24 @@ func generateTagsFromName(
 		return models.EmptyTags(), errCannotGenerateTagsFromEmptyName
 	}
 
-	numTags := bytes.Count(name, carbonSeparatorBytes) + 1
+	var (
+		fullName   = name
+		taggedTags []carbon.Tag
+	)
+	if carbon.IsTaggedName(name) {
+		var (
+			path []byte
+			err  error
+		)
+		path, taggedTags, err = carbon.ParseTaggedName(name, nil)
+		if err != nil {
+			return models.EmptyTags(),
+				fmt.Errorf("carbon metric: %s has invalid tags: %v", string(fullName), err)
+		}
+		name = path
+	}
+
+	numTags := bytes.Count(name, carbonSeparatorBytes) + 1 + len(taggedTags)
 
 	if cap(tags) >= numTags {
 		tags = tags[:0]
@@ -335,7 +548,7 @@ func generateTagsFromName(
 		if charByte == carbonSeparatorByte {
 			if i+1 < len(name) && name[i+1] == carbonSeparatorByte {
 				return models.EmptyTags(),
-					fmt.Errorf("carbon metric: %s has duplicate separator", string(name))
+					fmt.Errorf("carbon metric: %s has duplicate separator", string(fullName))
 			}
 
 			tags = append(tags, models.Tag{
@@ -363,7 +576,23 @@ func generateTagsFromName(
 		})
 	}
 
-	return models.Tags{Opts: opts, Tags: tags}, nil
+	if len(taggedTags) == 0 {
+		return models.Tags{Opts: opts, Tags: tags}, nil
+	}
+
+	for _, tag := range taggedTags {
+		if bytes.HasPrefix(tag.Name, reservedTagNamePrefix) {
+			return models.EmptyTags(),
+				fmt.Errorf("carbon metric: %s has reserved tag name %s",
+					string(fullName), string(tag.Name))
+		}
+
+		tags = append(tags, models.Tag{Name: tag.Name, Value: tag.Value})
+	}
+
+	// Sort the tags so that tagged names with the same tags in a different
+	// order resolve to the same series.
+	return models.Tags{Opts: opts, Tags: tags}.Normalize(), nil
 }
 
 // Compile all the carbon ingestion rules into regexp so that we can
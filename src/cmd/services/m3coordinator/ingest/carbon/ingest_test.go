@@ -23,9 +23,11 @@ package ingestcarbon
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"net"
 	"reflect"
@@ -300,6 +302,95 @@ func TestIngesterHonorsPatterns(t *testing.T) {
 	}, found)
 }
 
+func TestIngesterHandlePickle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	found := expectTestWrites(mockDownsamplerAndWriter)
+
+	var stream []byte
+	stream = append(stream, pickleMessage([]pickleTestMetric{
+		{name: "foo.bar.baz", timestamp: 1, value: 1.5},
+		{name: "foo.bar.qux", timestamp: 2, value: 2},
+		// Invalid name (too many separators).
+		{name: "foo..bar", timestamp: 3, value: 3},
+	})...)
+	// A frame that cannot be unpickled is skipped.
+	stream = append(stream, 0, 0, 0, 2, opProtoTest, 2)
+	stream = append(stream, pickleMessage([]pickleTestMetric{
+		{name: "foo.bar.quux", timestamp: 4, value: 4},
+	})...)
+
+	ingester, err := NewIngester(mockDownsamplerAndWriter, testRulesMatchAll, testOptions)
+	require.NoError(t, err)
+	ingester.PickleHandler().Handle(&byteConn{b: bytes.NewBuffer(stream)})
+
+	assertTestMetricsAreEqual(t, []testMetric{
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.baz")), timestamp: 1, value: 1.5},
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.qux")), timestamp: 2, value: 2},
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.quux")), timestamp: 4, value: 4},
+	}, found.metrics())
+}
+
+func TestIngesterHandlePickleMessageTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	found := expectTestWrites(mockDownsamplerAndWriter)
+
+	// The connection is abandoned once a message exceeds the maximum size
+	// since the framing of subsequent messages can't be trusted.
+	stream := []byte{0xff, 0xff, 0xff, 0xff}
+	stream = append(stream, pickleMessage([]pickleTestMetric{
+		{name: "foo.bar.baz", timestamp: 1, value: 1},
+	})...)
+
+	ingester, err := NewIngester(mockDownsamplerAndWriter, testRulesMatchAll, testOptions)
+	require.NoError(t, err)
+	ingester.PickleHandler().Handle(&byteConn{b: bytes.NewBuffer(stream)})
+
+	require.Equal(t, 0, len(found.metrics()))
+}
+
+func TestParsePickleMetric(t *testing.T) {
+	name, timestamp, value, ok := parsePickleMetric([]interface{}{
+		"foo.bar", []interface{}{float64(10.9), "3.5"},
+	})
+	require.True(t, ok)
+	assert.Equal(t, []byte("foo.bar"), name)
+	assert.Equal(t, time.Unix(10, 0), timestamp)
+	assert.Equal(t, 3.5, value)
+
+	invalid := []interface{}{
+		"foo.bar",
+		[]interface{}{"foo.bar"},
+		[]interface{}{"", []interface{}{int64(1), int64(1)}},
+		[]interface{}{int64(1), []interface{}{int64(1), int64(1)}},
+		[]interface{}{"foo.bar", []interface{}{int64(1)}},
+		[]interface{}{"foo.bar", []interface{}{nil, int64(1)}},
+		[]interface{}{"foo.bar", []interface{}{int64(1), "abc"}},
+	}
+	for _, metric := range invalid {
+		_, _, _, ok := parsePickleMetric(metric)
+		assert.False(t, ok, fmt.Sprintf("%v", metric))
+	}
+}
+
+func TestIngesterHandlePacket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	found := expectTestWrites(mockDownsamplerAndWriter)
+
+	ingester, err := NewIngester(mockDownsamplerAndWriter, testRulesMatchAll, testOptions)
+	require.NoError(t, err)
+	ingester.HandlePacket([]byte("foo.bar.baz 1 1\ngarbage\nfoo.bar.qux 2 2"))
+	ingester.HandlePacket([]byte("foo.bar.quux 3 3\n"))
+
+	assertTestMetricsAreEqual(t, []testMetric{
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.baz")), timestamp: 1, value: 1},
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.qux")), timestamp: 2, value: 2},
+		{tags: mustGenerateTagsFromName(t, []byte("foo.bar.quux")), timestamp: 3, value: 3},
+	}, found.metrics())
+}
+
 func TestGenerateTagsFromName(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -342,6 +433,26 @@ func TestGenerateTagsFromName(t *testing.T) {
 			expectedErr:  fmt.Errorf("carbon metric: foo.bar.baz.. has duplicate separator"),
 			expectedTags: []models.Tag{},
 		},
+		{
+			name: "foo.bar;env=prod;dc=us.east",
+			id:   "foo.bar;dc=us.east;env=prod",
+			expectedTags: []models.Tag{
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: graphite.TagName(1), Value: []byte("bar")},
+				{Name: []byte("dc"), Value: []byte("us.east")},
+				{Name: []byte("env"), Value: []byte("prod")},
+			},
+		},
+		{
+			name:         "foo.bar;env",
+			expectedErr:  fmt.Errorf("carbon metric: foo.bar;env has invalid tags: tagged name has an invalid tag"),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         "foo.bar;__g0__=baz",
+			expectedErr:  fmt.Errorf("carbon metric: foo.bar;__g0__=baz has reserved tag name __g0__"),
+			expectedTags: []models.Tag{},
+		},
 	}
 
 	opts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
@@ -401,6 +512,68 @@ func (b *byteConn) SetWriteDeadline(t time.Time) error {
 	panic("not_implemented")
 }
 
+type foundMetrics struct {
+	sync.Mutex
+	found []testMetric
+}
+
+func (f *foundMetrics) metrics() []testMetric {
+	f.Lock()
+	defer f.Unlock()
+	return append([]testMetric(nil), f.found...)
+}
+
+func expectTestWrites(mockDownsamplerAndWriter *ingest.MockDownsamplerAndWriter) *foundMetrics {
+	found := &foundMetrics{}
+	mockDownsamplerAndWriter.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any(), xtime.Second, gomock.Any()).DoAndReturn(func(
+		_ context.Context,
+		tags models.Tags,
+		dp ts.Datapoints,
+		unit xtime.Unit,
+		_ ingest.WriteOptions,
+	) interface{} {
+		found.Lock()
+		// Clone tags because they (and their underlying bytes) are pooled.
+		found.found = append(found.found, testMetric{
+			tags: tags.Clone(), timestamp: int(dp[0].Timestamp.Unix()), value: dp[0].Value})
+		found.Unlock()
+		return nil
+	}).AnyTimes()
+	return found
+}
+
+const opProtoTest = 0x80
+
+type pickleTestMetric struct {
+	name      string
+	timestamp int32
+	value     float64
+}
+
+// pickleMessage returns a length prefixed protocol 2 pickle of the metrics
+// in the same format that carbon relays send.
+func pickleMessage(metrics []pickleTestMetric) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{opProtoTest, 2, ']', '('})
+	for _, m := range metrics {
+		buf.WriteByte('X')
+		binary.Write(&buf, binary.LittleEndian, uint32(len(m.name)))
+		buf.WriteString(m.name)
+		buf.WriteByte('J')
+		binary.Write(&buf, binary.LittleEndian, m.timestamp)
+		buf.WriteByte('G')
+		binary.Write(&buf, binary.BigEndian, math.Float64bits(m.value))
+		// Build the (timestamp, value) and (name, (timestamp, value)) tuples.
+		buf.Write([]byte{0x86, 0x86})
+	}
+	buf.Write([]byte{'e', '.'})
+
+	msg := make([]byte, 4, 4+buf.Len())
+	binary.BigEndian.PutUint32(msg, uint32(buf.Len()))
+	return append(msg, buf.Bytes()...)
+}
+
 type testMetric struct {
 	metric    []byte
 	tags      models.Tags
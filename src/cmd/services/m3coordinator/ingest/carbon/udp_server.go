@@ -0,0 +1,128 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingestcarbon
+
+import (
+	"net"
+	"runtime"
+	"sync"
+
+	"github.com/m3db/m3/src/x/instrument"
+
+	"go.uber.org/zap"
+)
+
+// maxUDPPacketSize is the largest possible UDP payload.
+const maxUDPPacketSize = 65535
+
+// UDPServer receives plaintext carbon metrics over UDP.
+type UDPServer struct {
+	sync.Mutex
+
+	address  string
+	ingester Ingester
+	readers  int
+	logger   *zap.Logger
+	conn     net.PacketConn
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewUDPServer returns a new UDP server that passes each packet received
+// on the address to the ingester. Packets are read by the given number of
+// readers, each of which waits for the writes of the packet it read before
+// reading the next one, defaults to the number of CPUs if not positive.
+func NewUDPServer(
+	address string,
+	ingester Ingester,
+	readers int,
+	iOpts instrument.Options,
+) *UDPServer {
+	if readers <= 0 {
+		readers = runtime.NumCPU()
+	}
+
+	return &UDPServer{
+		address:  address,
+		ingester: ingester,
+		readers:  readers,
+		logger:   iOpts.Logger(),
+	}
+}
+
+// ListenAndServe starts listening on the address and serves packets in the
+// background.
+func (s *UDPServer) ListenAndServe() error {
+	conn, err := net.ListenPacket("udp", s.address)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	s.conn = conn
+	s.Unlock()
+
+	for i := 0; i < s.readers; i++ {
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+	return nil
+}
+
+func (s *UDPServer) serve(conn net.PacketConn) {
+	defer s.wg.Done()
+
+	buf := make([]byte, maxUDPPacketSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			s.logger.Error("error reading carbon packet", zap.Error(err))
+			continue
+		}
+		s.ingester.HandlePacket(buf[:n])
+	}
+}
+
+func (s *UDPServer) isClosed() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.closed
+}
+
+// Close stops the server and waits for the packets being handled to be
+// written.
+func (s *UDPServer) Close() {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return
+	}
+	s.closed = true
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.Unlock()
+
+	s.wg.Wait()
+}
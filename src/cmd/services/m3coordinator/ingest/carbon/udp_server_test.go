@@ -0,0 +1,87 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingestcarbon
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+	m3xserver "github.com/m3db/m3/src/x/server"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blockingPacketIngester struct {
+	m3xserver.Handler
+
+	packets chan string
+	block   chan struct{}
+}
+
+func (i *blockingPacketIngester) PickleHandler() m3xserver.Handler {
+	return nil
+}
+
+func (i *blockingPacketIngester) HandlePacket(packet []byte) {
+	p := string(packet)
+	i.packets <- p
+	if p == "block" {
+		<-i.block
+	}
+}
+
+func TestUDPServerHandlesPacketsWhileWriting(t *testing.T) {
+	ingester := &blockingPacketIngester{
+		packets: make(chan string, 2),
+		block:   make(chan struct{}),
+	}
+
+	server := NewUDPServer("127.0.0.1:0", ingester, 2, instrument.NewOptions())
+	require.NoError(t, server.ListenAndServe())
+
+	conn, err := net.Dial("udp", server.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The first packet blocks its reader until released.
+	_, err = conn.Write([]byte("block"))
+	require.NoError(t, err)
+	requirePacket(t, ingester.packets, "block")
+
+	// The next packet must be handled by another reader in the meantime.
+	_, err = conn.Write([]byte("foo.bar 1 1"))
+	require.NoError(t, err)
+	requirePacket(t, ingester.packets, "foo.bar 1 1")
+
+	close(ingester.block)
+	server.Close()
+}
+
+func requirePacket(t *testing.T, packets <-chan string, expected string) {
+	select {
+	case p := <-packets:
+		require.Equal(t, expected, p)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for packet", expected)
+	}
+}
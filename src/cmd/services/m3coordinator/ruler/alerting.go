@@ -0,0 +1,278 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	// alertMetricName is the name of the series written for each pending
+	// and firing alert, as with Prometheus.
+	alertMetricName = "ALERTS"
+
+	alertNameLabel  = "alertname"
+	alertStateLabel = "alertstate"
+
+	// resolvedRetention is how long resolved alerts are kept, and sent, so
+	// that the Alertmanager learns they were resolved.
+	resolvedRetention = 15 * time.Minute
+)
+
+// AlertState is the state of an alert.
+type AlertState int
+
+const (
+	// StateInactive is the state of an alert which is resolved.
+	StateInactive AlertState = iota
+	// StatePending is the state of an alert which is active but has not
+	// been active for the for duration of its rule yet.
+	StatePending
+	// StateFiring is the state of an alert which has been active for the
+	// for duration of its rule.
+	StateFiring
+)
+
+func (s AlertState) String() string {
+	switch s {
+	case StateInactive:
+		return "inactive"
+	case StatePending:
+		return "pending"
+	case StateFiring:
+		return "firing"
+	default:
+		return "unknown"
+	}
+}
+
+// Alert is an alert of an alerting rule.
+type Alert struct {
+	State       AlertState
+	Labels      models.Tags
+	Annotations map[string]string
+	Value       float64
+	ActiveAt    time.Time
+	FiredAt     time.Time
+	ResolvedAt  time.Time
+	LastSentAt  time.Time
+	ValidUntil  time.Time
+}
+
+func (a *Alert) needsSending(t time.Time, resendDelay time.Duration) bool {
+	if a.State == StatePending {
+		return false
+	}
+
+	// Send resolved alerts as soon as they are resolved.
+	if a.ResolvedAt.After(a.LastSentAt) {
+		return true
+	}
+
+	return a.LastSentAt.Add(resendDelay).Before(t)
+}
+
+// AlertingRule creates alerts for each series of an expression, an alert
+// fires once its series has been returned for the for duration of the rule.
+type AlertingRule struct {
+	sync.Mutex
+
+	name        string
+	expr        string
+	holdFor     time.Duration
+	labels      map[string]string
+	annotations map[string]string
+	tagOptions  models.TagOptions
+
+	// active are the pending, firing and recently resolved alerts by the
+	// hashed ID of their labels.
+	active map[uint64]*Alert
+}
+
+// NewAlertingRule returns a new alerting rule.
+func NewAlertingRule(
+	name string,
+	expr string,
+	holdFor time.Duration,
+	labels map[string]string,
+	annotations map[string]string,
+	tagOptions models.TagOptions,
+) *AlertingRule {
+	return &AlertingRule{
+		name:        name,
+		expr:        expr,
+		holdFor:     holdFor,
+		labels:      labels,
+		annotations: annotations,
+		tagOptions:  tagOptions,
+		active:      make(map[uint64]*Alert),
+	}
+}
+
+// Name returns the name of the alert.
+func (r *AlertingRule) Name() string {
+	return r.name
+}
+
+// Eval evaluates the expression, updates the state of the alerts of the rule
+// and returns an ALERTS sample for each pending and firing alert.
+func (r *AlertingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	result, err := query(ctx, r.expr, t)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	returned := make(map[uint64]struct{}, len(result))
+	for _, sample := range result {
+		var (
+			sampleLabels = tagsToMap(sample.Tags)
+			labels       = make(map[string]string, len(r.labels)+1)
+			annotations  = make(map[string]string, len(r.annotations))
+		)
+
+		for name, value := range r.labels {
+			labels[name] = expandTemplate(name, value, sampleLabels, sample.Value)
+		}
+
+		labels[alertNameLabel] = r.name
+		for name, value := range r.annotations {
+			annotations[name] = expandTemplate(name, value, sampleLabels, sample.Value)
+		}
+
+		tags := withLabels(sample.Tags.WithoutName(), labels)
+		h := tags.HashedID()
+		returned[h] = struct{}{}
+		if alert, ok := r.active[h]; ok && alert.State != StateInactive {
+			alert.Value = sample.Value
+			alert.Annotations = annotations
+			continue
+		}
+
+		r.active[h] = &Alert{
+			State:       StatePending,
+			Labels:      tags,
+			Annotations: annotations,
+			Value:       sample.Value,
+			ActiveAt:    t,
+		}
+	}
+
+	var vector Vector
+	for h, alert := range r.active {
+		if _, ok := returned[h]; !ok {
+			// Pending alerts are removed immediately while firing alerts are
+			// resolved and kept so that they are sent as resolved.
+			if alert.State == StatePending ||
+				(!alert.ResolvedAt.IsZero() && t.Sub(alert.ResolvedAt) > resolvedRetention) {
+				delete(r.active, h)
+			}
+
+			if alert.State != StateInactive {
+				alert.State = StateInactive
+				alert.ResolvedAt = t
+			}
+
+			continue
+		}
+
+		if alert.State == StatePending && t.Sub(alert.ActiveAt) >= r.holdFor {
+			alert.State = StateFiring
+			alert.FiredAt = t
+		}
+
+		vector = append(vector, Sample{
+			Tags: alert.Labels.Clone().
+				SetName([]byte(alertMetricName)).
+				AddOrUpdateTag(models.Tag{
+					Name:  []byte(alertStateLabel),
+					Value: []byte(alert.State.String()),
+				}),
+			Value: 1,
+		})
+	}
+
+	return vector, nil
+}
+
+// ActiveAlerts returns a copy of the pending, firing and recently resolved
+// alerts of the rule ordered by labels.
+func (r *AlertingRule) ActiveAlerts() []Alert {
+	r.Lock()
+	defer r.Unlock()
+
+	alerts := make([]Alert, 0, len(r.active))
+	for _, alert := range r.active {
+		alerts = append(alerts, *alert)
+	}
+
+	sortAlerts(alerts)
+	return alerts
+}
+
+// alertsToSend returns copies of the alerts which need to be sent and marks
+// them as sent.
+func (r *AlertingRule) alertsToSend(
+	t time.Time,
+	resendDelay time.Duration,
+	interval time.Duration,
+) []Alert {
+	r.Lock()
+	defer r.Unlock()
+
+	var alerts []Alert
+	for _, alert := range r.active {
+		if !alert.needsSending(t, resendDelay) {
+			continue
+		}
+
+		alert.LastSentAt = t
+		delta := resendDelay
+		if interval > resendDelay {
+			delta = interval
+		}
+
+		// NB: the Alertmanager resolves alerts that are not sent again
+		// before they are valid until, allow for a few failed evaluations.
+		alert.ValidUntil = t.Add(4 * delta)
+		alerts = append(alerts, *alert)
+	}
+
+	sortAlerts(alerts)
+	return alerts
+}
+
+func sortAlerts(alerts []Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		return string(alerts[i].Labels.ID()) < string(alerts[j].Labels.ID())
+	})
+}
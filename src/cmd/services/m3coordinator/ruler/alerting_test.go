@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSample(value float64, tags ...string) Sample {
+	result := models.NewTags(len(tags)/2, models.NewTagOptions())
+	for i := 0; i+1 < len(tags); i += 2 {
+		result = result.AddTag(models.Tag{
+			Name:  []byte(tags[i]),
+			Value: []byte(tags[i+1]),
+		})
+	}
+
+	return Sample{Tags: result, Value: value}
+}
+
+func sampleTags(vector Vector) []map[string]string {
+	result := make([]map[string]string, 0, len(vector))
+	for _, sample := range vector {
+		result = append(result, tagsToMap(sample.Tags))
+	}
+
+	return result
+}
+
+func TestAlertingRuleStates(t *testing.T) {
+	var result Vector
+	query := func(context.Context, string, time.Time) (Vector, error) {
+		return result, nil
+	}
+
+	rule := NewAlertingRule("HighLatency", "latency > 1", 2*time.Minute,
+		map[string]string{"severity": "page"},
+		map[string]string{"summary": "{{ $labels.job }} latency is {{ $value }}"},
+		models.NewTagOptions())
+
+	var (
+		ctx   = context.Background()
+		start = time.Unix(1000, 0)
+	)
+
+	// Active but not yet active for the for duration.
+	result = Vector{testSample(2, "__name__", "latency", "job", "api")}
+	vector, err := rule.Eval(ctx, start, query)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{
+		"__name__":   "ALERTS",
+		"alertname":  "HighLatency",
+		"alertstate": "pending",
+		"job":        "api",
+		"severity":   "page",
+	}}, sampleTags(vector))
+
+	alerts := rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, StatePending, alerts[0].State)
+	assert.Equal(t, "api latency is 2", alerts[0].Annotations["summary"])
+	assert.Empty(t, rule.alertsToSend(start, time.Minute, time.Minute))
+
+	// Fires once active for the for duration.
+	result = Vector{testSample(3, "__name__", "latency", "job", "api")}
+	vector, err = rule.Eval(ctx, start.Add(2*time.Minute), query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t, "firing", tagsToMap(vector[0].Tags)["alertstate"])
+
+	sent := rule.alertsToSend(start.Add(2*time.Minute), time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, StateFiring, sent[0].State)
+	assert.Equal(t, start, sent[0].ActiveAt)
+	assert.Equal(t, "api latency is 3", sent[0].Annotations["summary"])
+	assert.Equal(t, start.Add(6*time.Minute), sent[0].ValidUntil)
+
+	// Not resent until the resend delay has passed.
+	assert.Empty(t, rule.alertsToSend(start.Add(150*time.Second), time.Minute, time.Minute))
+
+	// Resolved once no longer returned and sent as resolved.
+	result = nil
+	vector, err = rule.Eval(ctx, start.Add(3*time.Minute), query)
+	require.NoError(t, err)
+	assert.Empty(t, vector)
+
+	sent = rule.alertsToSend(start.Add(3*time.Minute), time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, StateInactive, sent[0].State)
+	assert.Equal(t, start.Add(3*time.Minute), sent[0].ResolvedAt)
+
+	// Removed once resolved for longer than the resolved retention.
+	_, err = rule.Eval(ctx, start.Add(3*time.Minute+resolvedRetention+time.Second), query)
+	require.NoError(t, err)
+	assert.Empty(t, rule.ActiveAlerts())
+}
+
+func TestAlertingRulePendingRemoved(t *testing.T) {
+	var result Vector
+	query := func(context.Context, string, time.Time) (Vector, error) {
+		return result, nil
+	}
+
+	rule := NewAlertingRule("HighLatency", "latency > 1", time.Minute,
+		nil, nil, models.NewTagOptions())
+
+	result = Vector{testSample(2, "job", "api")}
+	_, err := rule.Eval(context.Background(), time.Unix(1000, 0), query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rule.ActiveAlerts()))
+
+	result = nil
+	_, err = rule.Eval(context.Background(), time.Unix(1030, 0), query)
+	require.NoError(t, err)
+	assert.Empty(t, rule.ActiveAlerts())
+}
+
+func TestRecordingRuleEval(t *testing.T) {
+	query := func(context.Context, string, time.Time) (Vector, error) {
+		return Vector{
+			testSample(1, "job", "a", "instance", "x"),
+			testSample(2, "job", "b", "instance", "y"),
+		}, nil
+	}
+
+	rule := NewRecordingRule("job:up:sum", "sum by (job) (up)",
+		map[string]string{"team": "c", "instance": ""})
+	vector, err := rule.Eval(context.Background(), time.Unix(1000, 0), query)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"__name__": "job:up:sum", "job": "a", "team": "c"},
+		{"__name__": "job:up:sum", "job": "b", "team": "c"},
+	}, sampleTags(vector))
+	assert.Equal(t, 1.0, vector[0].Value)
+	assert.Equal(t, 2.0, vector[1].Value)
+
+	// Removing the instance tag makes the series the same.
+	query = func(context.Context, string, time.Time) (Vector, error) {
+		return Vector{
+			testSample(1, "job", "a", "instance", "x"),
+			testSample(2, "job", "a", "instance", "y"),
+		}, nil
+	}
+
+	_, err = rule.Eval(context.Background(), time.Unix(1000, 0), query)
+	require.Error(t, err)
+}
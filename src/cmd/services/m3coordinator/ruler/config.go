@@ -0,0 +1,208 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultEvaluationInterval  = time.Minute
+	defaultQueryTimeout        = time.Minute
+	defaultAlertmanagerTimeout = 10 * time.Second
+	defaultResendDelay         = time.Minute
+	defaultElectionID          = "m3coordinator-ruler"
+)
+
+var errNoClusterClient = errors.New(
+	"ruler election requires a cluster management client")
+
+// Configuration is the configuration for the ruler.
+//
+// Every coordinator with the ruler configured evaluates every rule group and
+// so writes duplicate recording rule series and sends duplicate alerts,
+// either configure the ruler on a single coordinator or set election so that
+// only the elected leader evaluates the groups.
+type Configuration struct {
+	// RuleFiles are the Prometheus rule group files to load.
+	RuleFiles []string `yaml:"ruleFiles"`
+
+	// EvaluationInterval is the interval rule groups are evaluated at unless
+	// set by the group, defaults to one minute.
+	EvaluationInterval time.Duration `yaml:"evaluationInterval"`
+
+	// QueryTimeout is the timeout of rule expression queries, defaults to
+	// one minute.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+
+	// Alertmanager is the webhook alerts are sent to, alerts are only
+	// written as the ALERTS series if not set.
+	Alertmanager *AlertmanagerConfiguration `yaml:"alertmanager"`
+
+	// Election elects a single coordinator to evaluate the rule groups using
+	// the cluster management etcd, every coordinator evaluates them if not
+	// set.
+	Election *ElectionConfiguration `yaml:"election"`
+}
+
+// ElectionConfiguration is the configuration of the leader election between
+// the coordinators running the ruler.
+type ElectionConfiguration struct {
+	// ServiceID is the service the election is held under.
+	ServiceID services.ServiceIDConfiguration `yaml:"serviceID"`
+
+	// ElectionID is the ID of the election, coordinators evaluating the
+	// same rule files must share it, defaults to m3coordinator-ruler.
+	ElectionID string `yaml:"electionID"`
+
+	// LeaderValue is the value announced by the leader, defaults to the
+	// hostname.
+	LeaderValue string `yaml:"leaderValue"`
+
+	// Election configures the election timeouts and TTL.
+	Election services.ElectionConfiguration `yaml:"election"`
+}
+
+// NewElection returns a new election with the cluster client.
+func (c ElectionConfiguration) NewElection(
+	clusterClient clusterclient.Client,
+	instrumentOpts instrument.Options,
+) (Election, error) {
+	if clusterClient == nil {
+		return nil, errNoClusterClient
+	}
+
+	svcs, err := clusterClient.Services(services.NewOverrideOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	leaderService, err := svcs.LeaderService(c.ServiceID.NewServiceID(),
+		c.Election.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	campaignOpts, err := services.NewCampaignOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	leaderValue := c.LeaderValue
+	if leaderValue == "" {
+		leaderValue, err = os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	electionID := defaultElectionID
+	if c.ElectionID != "" {
+		electionID = c.ElectionID
+	}
+
+	return NewLeaderElection(leaderService, electionID,
+		campaignOpts.SetLeaderValue(leaderValue), instrumentOpts), nil
+}
+
+// AlertmanagerConfiguration is the configuration of the Alertmanager
+// compatible webhook alerts are sent to.
+type AlertmanagerConfiguration struct {
+	// URL is the URL alerts are posted to, such as
+	// http://alertmanager:9093/api/v2/alerts.
+	URL string `yaml:"url" validate:"nonzero"`
+
+	// Timeout is the timeout of requests to the webhook, defaults to ten
+	// seconds.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// ResendDelay is how long to wait before resending firing alerts,
+	// defaults to one minute.
+	ResendDelay time.Duration `yaml:"resendDelay"`
+}
+
+// NewManager loads the rule files and returns a manager which evaluates the
+// rules with the engine and writes results with the writer, the cluster
+// client is only required if election is set.
+func (c Configuration) NewManager(
+	engine executor.Engine,
+	writer ingest.DownsamplerAndWriter,
+	clusterClient clusterclient.Client,
+	tagOptions models.TagOptions,
+	instrumentOpts instrument.Options,
+) (*Manager, error) {
+	groups, err := LoadRuleGroups(c.RuleFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationInterval := defaultEvaluationInterval
+	if c.EvaluationInterval > 0 {
+		evaluationInterval = c.EvaluationInterval
+	}
+
+	queryTimeout := defaultQueryTimeout
+	if c.QueryTimeout > 0 {
+		queryTimeout = c.QueryTimeout
+	}
+
+	opts := Options{
+		QueryFunc:         NewEngineQueryFunc(engine, tagOptions, queryTimeout),
+		Writer:            writer,
+		TagOptions:        tagOptions,
+		NowFn:             time.Now,
+		InstrumentOptions: instrumentOpts,
+	}
+
+	if am := c.Alertmanager; am != nil {
+		timeout := defaultAlertmanagerTimeout
+		if am.Timeout > 0 {
+			timeout = am.Timeout
+		}
+
+		opts.ResendDelay = defaultResendDelay
+		if am.ResendDelay > 0 {
+			opts.ResendDelay = am.ResendDelay
+		}
+
+		opts.Notifier = NewWebhookNotifier(am.URL, timeout)
+	}
+
+	if c.Election != nil {
+		election, err := c.Election.NewElection(clusterClient, instrumentOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Election = election
+	}
+
+	return NewManager(groups, evaluationInterval, opts)
+}
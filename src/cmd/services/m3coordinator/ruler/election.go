@@ -0,0 +1,227 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultCampaignRetryDelay is how long to wait before campaigning again
+	// after a campaign failed or its session expired.
+	defaultCampaignRetryDelay = 5 * time.Second
+)
+
+var (
+	errElectionAlreadyStarted = errors.New("election already started")
+	errElectionNotStarted     = errors.New("election not started")
+)
+
+// Election decides whether this coordinator evaluates the rule groups.
+type Election interface {
+	// Start starts campaigning for leadership.
+	Start() error
+
+	// IsLeader returns whether this coordinator is currently the leader.
+	IsLeader() bool
+
+	// Close stops campaigning and resigns leadership if held.
+	Close() error
+}
+
+type leaderElectionMetrics struct {
+	leader          tally.Gauge
+	campaignErrors  tally.Counter
+	campaignResigns tally.Counter
+}
+
+func newLeaderElectionMetrics(scope tally.Scope) leaderElectionMetrics {
+	return leaderElectionMetrics{
+		leader:          scope.Gauge("leader"),
+		campaignErrors:  scope.Counter("campaign-errors"),
+		campaignResigns: scope.Counter("campaign-resigns"),
+	}
+}
+
+type leaderElection struct {
+	sync.RWMutex
+
+	leaderService services.LeaderService
+	electionID    string
+	campaignOpts  services.CampaignOptions
+	retryDelay    time.Duration
+	metrics       leaderElectionMetrics
+	logger        *zap.Logger
+
+	started bool
+	leader  bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewLeaderElection returns an election which campaigns for leadership of
+// the election ID with the leader service, so that a single coordinator of
+// those sharing the ID evaluates the rule groups at a time.
+func NewLeaderElection(
+	leaderService services.LeaderService,
+	electionID string,
+	campaignOpts services.CampaignOptions,
+	instrumentOpts instrument.Options,
+) Election {
+	if instrumentOpts == nil {
+		instrumentOpts = instrument.NewOptions()
+	}
+
+	scope := instrumentOpts.MetricsScope().SubScope("election")
+	return &leaderElection{
+		leaderService: leaderService,
+		electionID:    electionID,
+		campaignOpts:  campaignOpts,
+		retryDelay:    defaultCampaignRetryDelay,
+		metrics:       newLeaderElectionMetrics(scope),
+		logger: instrumentOpts.Logger().With(
+			zap.String("electionID", electionID)),
+	}
+}
+
+func (e *leaderElection) Start() error {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.started {
+		return errElectionAlreadyStarted
+	}
+
+	e.started = true
+	e.closeCh = make(chan struct{})
+	e.doneCh = make(chan struct{})
+	go e.campaignLoop(e.closeCh, e.doneCh)
+	return nil
+}
+
+func (e *leaderElection) IsLeader() bool {
+	e.RLock()
+	leader := e.leader
+	e.RUnlock()
+	return leader
+}
+
+func (e *leaderElection) Close() error {
+	e.Lock()
+	if !e.started {
+		e.Unlock()
+		return errElectionNotStarted
+	}
+
+	e.started = false
+	close(e.closeCh)
+	doneCh := e.doneCh
+	e.Unlock()
+
+	<-doneCh
+	return nil
+}
+
+func (e *leaderElection) campaignLoop(closeCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	for {
+		statusCh, err := e.leaderService.Campaign(e.electionID, e.campaignOpts)
+		if err != nil {
+			e.metrics.campaignErrors.Inc(1)
+			e.logger.Error("could not campaign for ruler leadership", zap.Error(err))
+		} else if closed := e.watchCampaign(statusCh, closeCh); closed {
+			return
+		}
+
+		// NB: the campaign failed or its session expired, stop evaluating
+		// until leadership is regained.
+		e.setLeader(false)
+		select {
+		case <-closeCh:
+			return
+		case <-time.After(e.retryDelay):
+		}
+	}
+}
+
+// watchCampaign follows the campaign until its status channel is closed,
+// returning true if it stopped because the election was closed.
+func (e *leaderElection) watchCampaign(
+	statusCh <-chan campaign.Status,
+	closeCh chan struct{},
+) bool {
+	for {
+		select {
+		case status, ok := <-statusCh:
+			if !ok {
+				return false
+			}
+
+			switch status.State {
+			case campaign.Leader:
+				e.logger.Info("elected ruler leader")
+				e.setLeader(true)
+			case campaign.Follower:
+				e.setLeader(false)
+			case campaign.Error:
+				e.metrics.campaignErrors.Inc(1)
+				e.logger.Error("ruler leadership campaign error",
+					zap.Error(status.Err))
+				e.setLeader(false)
+			}
+		case <-closeCh:
+			e.setLeader(false)
+			// NB: the status channel must be consumed until it is closed.
+			go func() {
+				for range statusCh {
+				}
+			}()
+			if err := e.leaderService.Resign(e.electionID); err != nil {
+				e.logger.Warn("could not resign ruler leadership", zap.Error(err))
+			} else {
+				e.metrics.campaignResigns.Inc(1)
+			}
+			return true
+		}
+	}
+}
+
+func (e *leaderElection) setLeader(leader bool) {
+	e.Lock()
+	e.leader = leader
+	e.Unlock()
+
+	if leader {
+		e.metrics.leader.Update(1)
+	} else {
+		e.metrics.leader.Update(0)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignOpts, err := services.NewCampaignOptions()
+	require.NoError(t, err)
+
+	statusCh := make(chan campaign.Status)
+	leaderService := services.NewMockLeaderService(ctrl)
+	leaderService.EXPECT().
+		Campaign("ruler", campaignOpts).
+		Return((<-chan campaign.Status)(statusCh), nil)
+	leaderService.EXPECT().
+		Resign("ruler").
+		DoAndReturn(func(string) error {
+			close(statusCh)
+			return nil
+		})
+
+	election := NewLeaderElection(leaderService, "ruler", campaignOpts, nil)
+	require.NoError(t, election.Start())
+	assert.Equal(t, errElectionAlreadyStarted, election.Start())
+	assert.False(t, election.IsLeader())
+
+	statusCh <- campaign.NewStatus(campaign.Leader)
+	require.True(t, waitUntil(election.IsLeader, time.Second))
+
+	statusCh <- campaign.NewStatus(campaign.Follower)
+	require.True(t, waitUntil(func() bool {
+		return !election.IsLeader()
+	}, time.Second))
+
+	statusCh <- campaign.NewStatus(campaign.Leader)
+	require.True(t, waitUntil(election.IsLeader, time.Second))
+
+	require.NoError(t, election.Close())
+	assert.False(t, election.IsLeader())
+	assert.Equal(t, errElectionNotStarted, election.Close())
+}
+
+func TestGroupShouldEval(t *testing.T) {
+	election := &testElection{}
+	group := &Group{opts: Options{Election: election}}
+	assert.False(t, group.shouldEval())
+
+	election.leader = true
+	assert.True(t, group.shouldEval())
+
+	group.opts.Election = nil
+	assert.True(t, group.shouldEval())
+}
+
+type testElection struct {
+	leader bool
+}
+
+func (e *testElection) Start() error   { return nil }
+func (e *testElection) IsLeader() bool { return e.leader }
+func (e *testElection) Close() error   { return nil }
+
+func waitUntil(fn func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if fn() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
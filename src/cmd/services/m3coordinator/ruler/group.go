@@ -0,0 +1,275 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var (
+	errNoQueryFunc  = errors.New("no query func set")
+	errNoWriter     = errors.New("no downsampler and writer set")
+	errNoTagOptions = errors.New("no tag options set")
+)
+
+// Options are the options of rule groups.
+type Options struct {
+	// QueryFunc evaluates the expressions of rules.
+	QueryFunc QueryFunc
+	// Writer writes the results of recording rules and the ALERTS series.
+	Writer ingest.DownsamplerAndWriter
+	// Notifier sends firing and resolved alerts, alerts are not sent if nil.
+	Notifier Notifier
+	// ResendDelay is how long to wait before resending a firing alert.
+	ResendDelay time.Duration
+	// TagOptions are the tag options of written series.
+	TagOptions models.TagOptions
+	// NowFn returns the current time.
+	NowFn clock.NowFn
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+	// Election decides whether groups are evaluated by this coordinator,
+	// groups are always evaluated if nil.
+	Election Election
+}
+
+// Validate validates the options.
+func (o Options) Validate() error {
+	if o.QueryFunc == nil {
+		return errNoQueryFunc
+	}
+	if o.Writer == nil {
+		return errNoWriter
+	}
+	if o.TagOptions == nil {
+		return errNoTagOptions
+	}
+	return nil
+}
+
+type groupMetrics struct {
+	evaluations        tally.Counter
+	evaluationsSkipped tally.Counter
+	evaluationErrors   tally.Counter
+	evaluationLatency  tally.Timer
+	writeErrors        tally.Counter
+	alertsSent         tally.Counter
+	notificationErrors tally.Counter
+}
+
+func newGroupMetrics(scope tally.Scope) groupMetrics {
+	return groupMetrics{
+		evaluations:        scope.Counter("evaluations"),
+		evaluationsSkipped: scope.Counter("evaluations-skipped"),
+		evaluationErrors:   scope.Counter("evaluation-errors"),
+		evaluationLatency:  scope.Timer("evaluation-latency"),
+		writeErrors:        scope.Counter("write-errors"),
+		alertsSent:         scope.Counter("alerts-sent"),
+		notificationErrors: scope.Counter("notification-errors"),
+	}
+}
+
+// Group is a group of rules which are evaluated sequentially at an interval.
+type Group struct {
+	name     string
+	interval time.Duration
+	rules    []Rule
+	opts     Options
+	metrics  groupMetrics
+	logger   *zap.Logger
+}
+
+// NewGroup returns a new rule group, the interval of the group is used if
+// set and otherwise the default interval.
+func NewGroup(
+	cfg RuleGroupConfiguration,
+	defaultInterval time.Duration,
+	opts Options,
+) (*Group, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	interval := defaultInterval
+	if cfg.Interval > 0 {
+		interval = cfg.Interval
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("group %s: interval must be positive", cfg.Name)
+	}
+
+	rules := make([]Rule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("group %s: %v", cfg.Name, err)
+		}
+
+		if _, err := promql.Parse(r.Expr, opts.TagOptions); err != nil {
+			return nil, fmt.Errorf("group %s: invalid expr %q: %v",
+				cfg.Name, r.Expr, err)
+		}
+
+		if r.Record != "" {
+			rules = append(rules, NewRecordingRule(r.Record, r.Expr, r.Labels))
+			continue
+		}
+
+		rules = append(rules, NewAlertingRule(r.Alert, r.Expr, r.For,
+			r.Labels, r.Annotations, opts.TagOptions))
+	}
+
+	iOpts := opts.InstrumentOptions
+	if iOpts == nil {
+		iOpts = instrument.NewOptions()
+	}
+
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	scope := iOpts.MetricsScope().Tagged(map[string]string{"group": cfg.Name})
+	return &Group{
+		name:     cfg.Name,
+		interval: interval,
+		rules:    rules,
+		opts:     opts,
+		metrics:  newGroupMetrics(scope),
+		logger:   iOpts.Logger().With(zap.String("group", cfg.Name)),
+	}, nil
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Interval returns the interval the group is evaluated at.
+func (g *Group) Interval() time.Duration {
+	return g.interval
+}
+
+// Rules returns the rules of the group.
+func (g *Group) Rules() []Rule {
+	return g.rules
+}
+
+// Eval evaluates the rules of the group in order at the given time, writes
+// their results and sends the alerts of alerting rules.
+func (g *Group) Eval(ctx context.Context, t time.Time) {
+	start := g.opts.NowFn()
+	defer func() {
+		g.metrics.evaluationLatency.Record(g.opts.NowFn().Sub(start))
+	}()
+
+	for _, rule := range g.rules {
+		g.metrics.evaluations.Inc(1)
+		vector, err := rule.Eval(ctx, t, g.opts.QueryFunc)
+		if err != nil {
+			g.metrics.evaluationErrors.Inc(1)
+			g.logger.Error("rule evaluation error",
+				zap.String("rule", rule.Name()), zap.Error(err))
+			continue
+		}
+
+		for _, sample := range vector {
+			err := g.opts.Writer.Write(ctx, sample.Tags, ts.Datapoints{{
+				Timestamp: t,
+				Value:     sample.Value,
+			}}, xtime.Millisecond, ingest.WriteOptions{})
+			if err != nil {
+				g.metrics.writeErrors.Inc(1)
+				g.logger.Error("rule write error",
+					zap.String("rule", rule.Name()), zap.Error(err))
+			}
+		}
+
+		if alertingRule, ok := rule.(*AlertingRule); ok {
+			g.sendAlerts(ctx, alertingRule, t)
+		}
+	}
+}
+
+func (g *Group) sendAlerts(ctx context.Context, rule *AlertingRule, t time.Time) {
+	if g.opts.Notifier == nil {
+		return
+	}
+
+	alerts := rule.alertsToSend(t, g.opts.ResendDelay, g.interval)
+	if len(alerts) == 0 {
+		return
+	}
+
+	if err := g.opts.Notifier.Send(ctx, alerts); err != nil {
+		g.metrics.notificationErrors.Inc(1)
+		g.logger.Error("alert notification error",
+			zap.String("rule", rule.Name()), zap.Error(err))
+		return
+	}
+
+	g.metrics.alertsSent.Inc(int64(len(alerts)))
+}
+
+// run evaluates the group at each interval until closed.
+func (g *Group) run(closeCh <-chan struct{}) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		if g.shouldEval() {
+			g.evalWithTimeout(g.opts.NowFn())
+		} else {
+			g.metrics.evaluationsSkipped.Inc(1)
+		}
+		select {
+		case <-closeCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shouldEval returns whether this coordinator evaluates the group, only the
+// leader does so if an election is set.
+func (g *Group) shouldEval() bool {
+	return g.opts.Election == nil || g.opts.Election.IsLeader()
+}
+
+func (g *Group) evalWithTimeout(t time.Time) {
+	// NB: an evaluation must not run into the next one.
+	ctx, cancel := context.WithTimeout(context.Background(), g.interval)
+	defer cancel()
+	g.Eval(ctx, t)
+}
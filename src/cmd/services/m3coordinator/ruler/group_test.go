@@ -0,0 +1,199 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNotifier struct {
+	alerts []Alert
+}
+
+func (n *testNotifier) Send(_ context.Context, alerts []Alert) error {
+	n.alerts = append(n.alerts, alerts...)
+	return nil
+}
+
+func TestGroupEval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now      = time.Unix(1000, 0)
+		writer   = ingest.NewMockDownsamplerAndWriter(ctrl)
+		notifier = &testNotifier{}
+		queries  []string
+	)
+
+	query := func(_ context.Context, query string, at time.Time) (Vector, error) {
+		assert.Equal(t, now, at)
+		queries = append(queries, query)
+		return Vector{testSample(5, "job", "api")}, nil
+	}
+
+	group, err := NewGroup(RuleGroupConfiguration{
+		Name: "example",
+		Rules: []RuleConfiguration{
+			{Record: "job:errors:rate1m", Expr: "sum by (job) (rate(errors[1m]))"},
+			{Alert: "HighErrors", Expr: "job:errors:rate1m > 1"},
+		},
+	}, time.Minute, Options{
+		QueryFunc:  query,
+		Writer:     writer,
+		Notifier:   notifier,
+		TagOptions: models.NewTagOptions(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, group.Interval())
+	assert.Equal(t, 2, len(group.Rules()))
+
+	var written []map[string]string
+	writer.EXPECT().
+		Write(gomock.Any(), gomock.Any(), ts.Datapoints{{Timestamp: now, Value: 5}},
+			xtime.Millisecond, ingest.WriteOptions{}).
+		DoAndReturn(func(
+			_ context.Context,
+			tags models.Tags,
+			_ ts.Datapoints,
+			_ xtime.Unit,
+			_ ingest.WriteOptions,
+		) error {
+			written = append(written, tagsToMap(tags))
+			return nil
+		})
+	writer.EXPECT().
+		Write(gomock.Any(), gomock.Any(), ts.Datapoints{{Timestamp: now, Value: 1}},
+			xtime.Millisecond, ingest.WriteOptions{}).
+		DoAndReturn(func(
+			_ context.Context,
+			tags models.Tags,
+			_ ts.Datapoints,
+			_ xtime.Unit,
+			_ ingest.WriteOptions,
+		) error {
+			written = append(written, tagsToMap(tags))
+			return nil
+		})
+
+	group.Eval(context.Background(), now)
+	assert.Equal(t, []string{
+		"sum by (job) (rate(errors[1m]))",
+		"job:errors:rate1m > 1",
+	}, queries)
+	assert.Equal(t, []map[string]string{
+		{"__name__": "job:errors:rate1m", "job": "api"},
+		{
+			"__name__":   "ALERTS",
+			"alertname":  "HighErrors",
+			"alertstate": "firing",
+			"job":        "api",
+		},
+	}, written)
+
+	// Alerts without a for duration fire immediately.
+	require.Equal(t, 1, len(notifier.alerts))
+	assert.Equal(t, StateFiring, notifier.alerts[0].State)
+}
+
+func TestNewGroupInvalidRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := NewGroup(RuleGroupConfiguration{
+		Name:  "example",
+		Rules: []RuleConfiguration{{Record: "a", Expr: "sum(("}},
+	}, time.Minute, Options{
+		QueryFunc:  func(context.Context, string, time.Time) (Vector, error) { return nil, nil },
+		Writer:     ingest.NewMockDownsamplerAndWriter(ctrl),
+		TagOptions: models.NewTagOptions(),
+	})
+	require.Error(t, err)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received []postableAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	var (
+		start    = time.Unix(1000, 0).UTC()
+		notifier = NewWebhookNotifier(server.URL, time.Second)
+		labels   = testSample(0, "alertname", "HighErrors", "job", "api").Tags
+	)
+
+	require.NoError(t, notifier.Send(context.Background(), []Alert{
+		{
+			State:       StateFiring,
+			Labels:      labels,
+			Annotations: map[string]string{"summary": "errors"},
+			ActiveAt:    start,
+			ValidUntil:  start.Add(4 * time.Minute),
+		},
+		{
+			State:      StateInactive,
+			Labels:     labels,
+			ActiveAt:   start,
+			ResolvedAt: start.Add(time.Minute),
+			ValidUntil: start.Add(4 * time.Minute),
+		},
+	}))
+
+	require.Equal(t, 2, len(received))
+	assert.Equal(t, map[string]string{"alertname": "HighErrors", "job": "api"},
+		received[0].Labels)
+	assert.Equal(t, map[string]string{"summary": "errors"}, received[0].Annotations)
+	assert.True(t, start.Equal(received[0].StartsAt))
+	assert.True(t, start.Add(4*time.Minute).Equal(received[0].EndsAt))
+	assert.True(t, start.Add(time.Minute).Equal(received[1].EndsAt))
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second)
+	err := notifier.Send(context.Background(), []Alert{{
+		State:  StateFiring,
+		Labels: testSample(0, "alertname", "HighErrors").Tags,
+	}})
+	require.Error(t, err)
+}
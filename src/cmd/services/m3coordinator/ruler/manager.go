@@ -0,0 +1,130 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package ruler evaluates Prometheus recording and alerting rules in the
+// coordinator.
+package ruler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	errManagerAlreadyStarted = errors.New("ruler already started")
+	errManagerNotStarted     = errors.New("ruler not started")
+)
+
+// Manager evaluates rule groups at their intervals.
+type Manager struct {
+	sync.Mutex
+
+	groups   []*Group
+	election Election
+	started  bool
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewManager returns a new manager for the rule groups, groups with the same
+// name are not allowed.
+func NewManager(
+	groups []RuleGroupConfiguration,
+	defaultInterval time.Duration,
+	opts Options,
+) (*Manager, error) {
+	names := make(map[string]struct{}, len(groups))
+	result := make([]*Group, 0, len(groups))
+	for _, cfg := range groups {
+		if _, ok := names[cfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate group name: %s", cfg.Name)
+		}
+
+		names[cfg.Name] = struct{}{}
+		group, err := NewGroup(cfg, defaultInterval, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, group)
+	}
+
+	return &Manager{
+		groups:   result,
+		election: opts.Election,
+		closeCh:  make(chan struct{}),
+	}, nil
+}
+
+// Groups returns the rule groups of the manager.
+func (m *Manager) Groups() []*Group {
+	return m.groups
+}
+
+// Start starts evaluating the rule groups, and campaigning for leadership if
+// an election is set.
+func (m *Manager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.started {
+		return errManagerAlreadyStarted
+	}
+
+	if m.election != nil {
+		if err := m.election.Start(); err != nil {
+			return err
+		}
+	}
+
+	m.started = true
+	for _, group := range m.groups {
+		group := group
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			group.run(m.closeCh)
+		}()
+	}
+
+	return nil
+}
+
+// Close stops evaluating the rule groups, waits for any evaluations in
+// progress to finish and then resigns leadership if an election is set.
+func (m *Manager) Close() error {
+	m.Lock()
+	if !m.started {
+		m.Unlock()
+		return errManagerNotStarted
+	}
+
+	m.started = false
+	close(m.closeCh)
+	m.Unlock()
+
+	m.wg.Wait()
+	if m.election != nil {
+		return m.election.Close()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+// Notifier sends alerts.
+type Notifier interface {
+	// Send sends firing and resolved alerts.
+	Send(ctx context.Context, alerts []Alert) error
+}
+
+// postableAlert is an alert in the format of the Alertmanager alerts API.
+type postableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier which posts alerts as JSON in the
+// format of the Alertmanager alerts API to the URL, such as
+// http://alertmanager:9093/api/v2/alerts.
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	opts := xhttp.DefaultHTTPClientOptions()
+	opts.RequestTimeout = timeout
+	return &webhookNotifier{
+		url:    url,
+		client: xhttp.NewHTTPClient(opts),
+	}
+}
+
+func (n *webhookNotifier) Send(ctx context.Context, alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(toPostableAlerts(alerts))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d from %s",
+			resp.StatusCode, n.url)
+	}
+
+	return nil
+}
+
+func toPostableAlerts(alerts []Alert) []postableAlert {
+	result := make([]postableAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		endsAt := alert.ValidUntil
+		if !alert.ResolvedAt.IsZero() {
+			endsAt = alert.ResolvedAt
+		}
+
+		result = append(result, postableAlert{
+			Labels:      tagsToMap(alert.Labels),
+			Annotations: alert.Annotations,
+			StartsAt:    alert.ActiveAt,
+			EndsAt:      endsAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+const (
+	// instantQueryStep is the step of the instant queries evaluated by
+	// rules, the value at the last step is the value of the query.
+	instantQueryStep = time.Second
+)
+
+// Sample is the value of a series at the evaluation time of a rule.
+type Sample struct {
+	Tags  models.Tags
+	Value float64
+}
+
+// Vector is the result of evaluating a rule expression.
+type Vector []Sample
+
+// QueryFunc evaluates a PromQL expression as an instant query at a time.
+type QueryFunc func(ctx context.Context, query string, t time.Time) (Vector, error)
+
+// NewEngineQueryFunc returns a query func which evaluates instant queries
+// with the engine.
+func NewEngineQueryFunc(
+	engine executor.Engine,
+	tagOptions models.TagOptions,
+	timeout time.Duration,
+) QueryFunc {
+	return func(ctx context.Context, query string, t time.Time) (Vector, error) {
+		parser, err := promql.Parse(query, tagOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		fetchOpts := storage.NewFetchOptions()
+		fetchOpts.Step = instantQueryStep
+		params := models.RequestParams{
+			Start:            t,
+			End:              t,
+			Now:              t,
+			Timeout:          timeout,
+			Step:             instantQueryStep,
+			Query:            query,
+			IncludeEnd:       true,
+			BlockType:        models.TypeSingleBlock,
+			LookbackDuration: engine.Options().LookbackDuration(),
+		}
+
+		result, err := engine.ExecuteExpr(ctx, parser,
+			&executor.QueryOptions{}, fetchOpts, params)
+		if err != nil {
+			return nil, err
+		}
+
+		resultChan := result.ResultChan()
+		defer func() {
+			for range resultChan {
+				// NB: drain result channel in case of early termination.
+			}
+		}()
+
+		var vector Vector
+		for r := range resultChan {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+
+			vector, err = appendBlockSamples(vector, r.Block)
+			r.Block.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return vector, nil
+	}
+}
+
+// appendBlockSamples appends the last value of each series of the block,
+// series without a value at the last step are skipped.
+func appendBlockSamples(vector Vector, b block.Block) (Vector, error) {
+	iter, err := b.SeriesIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		commonTags = b.Meta().Tags.Tags
+		seriesMeta = iter.SeriesMeta()
+	)
+
+	for i := 0; iter.Next(); i++ {
+		series := iter.Current()
+		if series.Len() == 0 || i >= len(seriesMeta) {
+			continue
+		}
+
+		value := series.ValueAtStep(series.Len() - 1)
+		if math.IsNaN(value) {
+			continue
+		}
+
+		// NB: the tags are cloned since the block is closed once read.
+		vector = append(vector, Sample{
+			Tags:  seriesMeta[i].Tags.AddTags(commonTags).Clone(),
+			Value: value,
+		})
+	}
+
+	return vector, iter.Err()
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendBlockSamples(t *testing.T) {
+	var (
+		tagOpts = models.NewTagOptions()
+		bounds  = models.Bounds{
+			Start:    time.Unix(1000, 0),
+			Duration: 2 * time.Second,
+			StepSize: time.Second,
+		}
+		meta = block.Metadata{
+			Bounds: bounds,
+			Tags: models.NewTags(1, tagOpts).AddTag(models.Tag{
+				Name:  []byte("job"),
+				Value: []byte("api"),
+			}),
+		}
+		seriesMeta = []block.SeriesMeta{
+			{Tags: testSample(0, "instance", "a").Tags},
+			{Tags: testSample(0, "instance", "b").Tags},
+			{Tags: testSample(0, "instance", "c").Tags},
+		}
+	)
+
+	b := test.NewBlockFromValuesWithMetaAndSeriesMeta(meta, seriesMeta,
+		[][]float64{{1, 2}, {3, math.NaN()}, {4, 5}})
+	defer b.Close()
+
+	vector, err := appendBlockSamples(nil, b)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"instance": "a", "job": "api"},
+		{"instance": "c", "job": "api"},
+	}, sampleTags(vector))
+	assert.Equal(t, 2.0, vector[0].Value)
+	assert.Equal(t, 5.0, vector[1].Value)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// Rule is a recording or alerting rule.
+type Rule interface {
+	// Name returns the name of the rule.
+	Name() string
+
+	// Eval evaluates the rule at the given time and returns the samples
+	// to write.
+	Eval(ctx context.Context, t time.Time, query QueryFunc) (Vector, error)
+}
+
+// RecordingRule records the result of an expression as a new series.
+type RecordingRule struct {
+	name   string
+	expr   string
+	labels map[string]string
+}
+
+// NewRecordingRule returns a new recording rule.
+func NewRecordingRule(
+	name string,
+	expr string,
+	labels map[string]string,
+) *RecordingRule {
+	return &RecordingRule{
+		name:   name,
+		expr:   expr,
+		labels: labels,
+	}
+}
+
+// Name returns the name of the recorded series.
+func (r *RecordingRule) Name() string {
+	return r.name
+}
+
+// Eval evaluates the expression and returns its result with the name and
+// labels of the rule.
+func (r *RecordingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	vector, err := query(ctx, r.expr, t)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]struct{}, len(vector))
+	for i := range vector {
+		tags := withLabels(vector[i].Tags.Clone(), r.labels).
+			SetName([]byte(r.name))
+		id := string(tags.ID())
+		if _, ok := ids[id]; ok {
+			return nil, fmt.Errorf(
+				"vector contains series with the same tags after applying "+
+					"rule labels: %s", tags.String())
+		}
+
+		ids[id] = struct{}{}
+		vector[i].Tags = tags
+	}
+
+	return vector, nil
+}
+
+// withLabels adds the labels to the tags, replacing tags with the same name
+// and removing tags set to an empty value.
+func withLabels(tags models.Tags, labels map[string]string) models.Tags {
+	for name, value := range labels {
+		if value == "" {
+			tags = tags.TagsWithoutKeys([][]byte{[]byte(name)})
+			continue
+		}
+
+		tags = tags.AddOrUpdateTag(models.Tag{
+			Name:  []byte(name),
+			Value: []byte(value),
+		})
+	}
+
+	return tags
+}
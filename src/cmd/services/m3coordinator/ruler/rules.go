@@ -0,0 +1,175 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	metricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegexp  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// RuleGroupsConfiguration is the configuration of a Prometheus rule group
+// file.
+type RuleGroupsConfiguration struct {
+	Groups []RuleGroupConfiguration `yaml:"groups"`
+}
+
+// RuleGroupConfiguration is the configuration of a group of rules which are
+// evaluated sequentially at the same interval.
+type RuleGroupConfiguration struct {
+	// Name is the name of the group, unique within a file.
+	Name string `yaml:"name"`
+
+	// Interval is the interval the group is evaluated at, defaults to the
+	// evaluation interval of the ruler.
+	Interval time.Duration `yaml:"interval"`
+
+	// Rules are the recording and alerting rules of the group.
+	Rules []RuleConfiguration `yaml:"rules"`
+}
+
+// RuleConfiguration is the configuration of a recording or alerting rule.
+type RuleConfiguration struct {
+	// Record is the name of the series written by a recording rule.
+	Record string `yaml:"record"`
+
+	// Alert is the name of the alert of an alerting rule.
+	Alert string `yaml:"alert"`
+
+	// Expr is the PromQL expression evaluated by the rule.
+	Expr string `yaml:"expr"`
+
+	// For is how long an alerting rule must be active before it fires.
+	For time.Duration `yaml:"for"`
+
+	// Labels are added to the series written by a recording rule or to the
+	// alerts of an alerting rule, alerting rule labels may be templated.
+	Labels map[string]string `yaml:"labels"`
+
+	// Annotations are the templated annotations of the alerts of an
+	// alerting rule.
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// LoadRuleGroups loads and validates the rule groups of Prometheus rule group
+// files.
+func LoadRuleGroups(files []string) ([]RuleGroupConfiguration, error) {
+	var groups []RuleGroupConfiguration
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var cfg RuleGroupsConfiguration
+		if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+			return nil, fmt.Errorf("unable to parse rule file %s: %v", file, err)
+		}
+
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rule file %s: %v", file, err)
+		}
+
+		groups = append(groups, cfg.Groups...)
+	}
+
+	return groups, nil
+}
+
+// Validate validates the rule groups.
+func (c RuleGroupsConfiguration) Validate() error {
+	names := make(map[string]struct{}, len(c.Groups))
+	for _, g := range c.Groups {
+		if g.Name == "" {
+			return errors.New("group name must not be empty")
+		}
+
+		if _, ok := names[g.Name]; ok {
+			return fmt.Errorf("duplicate group name: %s", g.Name)
+		}
+
+		names[g.Name] = struct{}{}
+		if g.Interval < 0 {
+			return fmt.Errorf("group %s: interval must not be negative", g.Name)
+		}
+
+		for i, r := range g.Rules {
+			if err := r.Validate(); err != nil {
+				return fmt.Errorf("group %s, rule %d: %v", g.Name, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the rule, the expression is validated when parsed.
+func (c RuleConfiguration) Validate() error {
+	switch {
+	case c.Record != "" && c.Alert != "":
+		return errors.New("only one of record and alert may be set")
+	case c.Record == "" && c.Alert == "":
+		return errors.New("one of record or alert must be set")
+	case c.Expr == "":
+		return errors.New("expr must be set")
+	}
+
+	if c.Record != "" {
+		if !metricNameRegexp.MatchString(c.Record) {
+			return fmt.Errorf("invalid recording rule name: %s", c.Record)
+		}
+
+		if len(c.Annotations) > 0 {
+			return errors.New("recording rules must not have annotations")
+		}
+
+		if c.For != 0 {
+			return errors.New("recording rules must not have a for duration")
+		}
+	}
+
+	if c.For < 0 {
+		return errors.New("for duration must not be negative")
+	}
+
+	for name := range c.Labels {
+		if !labelNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid label name: %s", name)
+		}
+	}
+
+	for name := range c.Annotations {
+		if !labelNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid annotation name: %s", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRuleFile(t *testing.T, dir, contents string) string {
+	file := filepath.Join(dir, "rules.yml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(contents), 0644))
+	return file
+}
+
+func TestLoadRuleGroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ruler")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := writeRuleFile(t, dir, `
+groups:
+  - name: example
+    interval: 30s
+    rules:
+      - record: job:http_requests:rate5m
+        expr: sum by (job) (rate(http_requests_total[5m]))
+        labels:
+          team: a
+      - alert: HighErrorRate
+        expr: job:http_requests:rate5m > 10
+        for: 10m
+        labels:
+          severity: page
+        annotations:
+          summary: "High error rate for {{ $labels.job }}"
+`)
+
+	groups, err := LoadRuleGroups([]string{file})
+	require.NoError(t, err)
+	require.Equal(t, []RuleGroupConfiguration{{
+		Name:     "example",
+		Interval: 30 * time.Second,
+		Rules: []RuleConfiguration{
+			{
+				Record: "job:http_requests:rate5m",
+				Expr:   "sum by (job) (rate(http_requests_total[5m]))",
+				Labels: map[string]string{"team": "a"},
+			},
+			{
+				Alert:       "HighErrorRate",
+				Expr:        "job:http_requests:rate5m > 10",
+				For:         10 * time.Minute,
+				Labels:      map[string]string{"severity": "page"},
+				Annotations: map[string]string{"summary": "High error rate for {{ $labels.job }}"},
+			},
+		},
+	}}, groups)
+}
+
+func TestLoadRuleGroupsInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ruler")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{
+			name:     "unknown field",
+			contents: "groups:\n  - name: a\n    unknown: b\n",
+		},
+		{
+			name:     "duplicate group",
+			contents: "groups:\n  - name: a\n  - name: a\n",
+		},
+		{
+			name:     "record and alert",
+			contents: "groups:\n  - name: a\n    rules:\n      - record: b\n        alert: c\n        expr: up\n",
+		},
+		{
+			name:     "no expr",
+			contents: "groups:\n  - name: a\n    rules:\n      - record: b\n",
+		},
+		{
+			name:     "invalid record name",
+			contents: "groups:\n  - name: a\n    rules:\n      - record: b-c\n        expr: up\n",
+		},
+		{
+			name:     "recording rule annotations",
+			contents: "groups:\n  - name: a\n    rules:\n      - record: b\n        expr: up\n        annotations:\n          c: d\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := writeRuleFile(t, dir, test.contents)
+			_, err := LoadRuleGroups([]string{file})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	// templateDefs lets templates refer to the labels and value of a sample
+	// as $labels and $value, as with Prometheus.
+	templateDefs = "{{$labels := .Labels}}{{$value := .Value}}"
+)
+
+type templateData struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// expandTemplate expands a label or annotation template of an alerting rule,
+// errors are returned in place of the expanded text as with Prometheus so
+// that alerts are still sent.
+func expandTemplate(
+	name string,
+	text string,
+	labels map[string]string,
+	value float64,
+) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	tmpl, err := template.New(name).
+		Option("missingkey=zero").
+		Parse(templateDefs + text)
+	if err != nil {
+		return fmt.Sprintf("<error expanding template: %v>", err)
+	}
+
+	var buf bytes.Buffer
+	data := templateData{Labels: labels, Value: value}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<error expanding template: %v>", err)
+	}
+
+	return buf.String()
+}
+
+func tagsToMap(tags models.Tags) map[string]string {
+	result := make(map[string]string, tags.Len())
+	for _, tag := range tags.Tags {
+		result[string(tag.Name)] = string(tag.Value)
+	}
+
+	return result
+}
@@ -100,6 +100,10 @@ type kvStoreConfig struct {
 
 	// Validation configuration.
 	Validation *validator.Configuration `yaml:"validation"`
+
+	// NameTagKey is the tag key for metric names used when matching metric IDs
+	// against rulesets.
+	NameTagKey string `yaml:"nameTagKey"`
 }
 
 // NewStore creates a new KV backed R2 store.
@@ -132,5 +136,11 @@ func (c kvStoreConfig) NewStore(instrumentOpts instrument.Options) (r2store.Stor
 		SetInstrumentOptions(instrumentOpts).
 		SetRuleUpdatePropagationDelay(c.PropagationDelay).
 		SetValidator(validator)
+	if c.NameTagKey != "" {
+		ruleSetOpts := r2StoreOpts.RuleSetOptions()
+		tagsFilterOpts := ruleSetOpts.TagsFilterOptions()
+		tagsFilterOpts.NameTagKey = []byte(c.NameTagKey)
+		r2StoreOpts = r2StoreOpts.SetRuleSetOptions(ruleSetOpts.SetTagsFilterOptions(tagsFilterOpts))
+	}
 	return r2kv.NewStore(rulesStore, r2StoreOpts), nil
 }
@@ -92,6 +92,18 @@ type DBConfiguration struct {
 	// The HTTP host and port on which to listen for the cluster service.
 	HTTPClusterListenAddress string `yaml:"httpClusterListenAddress" validate:"nonzero"`
 
+	// The gRPC host and port on which to listen for the node service, the
+	// gRPC node service is disabled if not set.
+	GRPCNodeListenAddress string `yaml:"grpcNodeListenAddress"`
+
+	// The max size in bytes of a message received by the gRPC node service,
+	// the gRPC node service default is used if not set.
+	GRPCNodeMaxRecvMsgSize int `yaml:"grpcNodeMaxRecvMsgSize"`
+
+	// The max size in bytes of a message sent by the gRPC node service,
+	// the gRPC node service default is used if not set.
+	GRPCNodeMaxSendMsgSize int `yaml:"grpcNodeMaxSendMsgSize"`
+
 	// The host and port on which to listen for debug endpoints.
 	DebugListenAddress string `yaml:"debugListenAddress"`
 
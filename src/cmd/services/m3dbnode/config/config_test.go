@@ -65,6 +65,7 @@ db:
   clusterListenAddress: 0.0.0.0:9001
   httpNodeListenAddress: 0.0.0.0:9002
   httpClusterListenAddress: 0.0.0.0:9003
+  grpcNodeListenAddress: 0.0.0.0:9005
   debugListenAddress: 0.0.0.0:9004
 
   hostID:
@@ -362,6 +363,9 @@ func TestConfiguration(t *testing.T) {
   clusterListenAddress: 0.0.0.0:9001
   httpNodeListenAddress: 0.0.0.0:9002
   httpClusterListenAddress: 0.0.0.0:9003
+  grpcNodeListenAddress: 0.0.0.0:9005
+  grpcNodeMaxRecvMsgSize: 0
+  grpcNodeMaxSendMsgSize: 0
   debugListenAddress: 0.0.0.0:9004
   hostID:
     resolver: config
@@ -668,6 +672,11 @@ func TestConfiguration(t *testing.T) {
     maxOutstandingWriteRequests: 0
     maxOutstandingReadRequests: 0
     maxOutstandingRepairedBytes: 0
+    series:
+      maxSeriesPerNamespace: 0
+      namespaceMaxSeries: {}
+      tagName: ""
+      maxSeriesPerTagValue: 0
 coordinator: null
 `
 
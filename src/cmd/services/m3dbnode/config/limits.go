@@ -20,6 +20,10 @@
 
 package config
 
+import (
+	"github.com/m3db/m3/src/dbnode/runtime"
+)
+
 // Limits contains configuration for configurable limits that can be applied to M3DB.
 type Limits struct {
 	// MaxOutstandingWriteRequests controls the maximum number of outstanding write requests
@@ -39,4 +43,36 @@ type Limits struct {
 	// process would pause until some of the repaired bytes had been persisted to disk (and subsequently
 	// evicted from memory) at which point it would resume.
 	MaxOutstandingRepairedBytes int64 `yaml:"maxOutstandingRepairedBytes" validate:"min=0"`
+
+	// Series controls the hard limits on the number of active series per namespace
+	// and per value of a tag, writes that would create series beyond these limits are
+	// rejected. These limits can also be updated dynamically through KV.
+	Series SeriesLimits `yaml:"series"`
+}
+
+// SeriesLimits contains configuration for limiting the number of active series held
+// by a node, zero values specify that no limit should be enforced.
+type SeriesLimits struct {
+	// MaxSeriesPerNamespace is the default maximum number of active series per namespace.
+	MaxSeriesPerNamespace int64 `yaml:"maxSeriesPerNamespace" validate:"min=0"`
+
+	// NamespaceMaxSeries overrides MaxSeriesPerNamespace for specific namespaces.
+	NamespaceMaxSeries map[string]int64 `yaml:"namespaceMaxSeries"`
+
+	// TagName is the name of the tag to limit active series per value of, i.e. "service".
+	TagName string `yaml:"tagName"`
+
+	// MaxSeriesPerTagValue is the maximum number of active series per namespace that
+	// share the same value for TagName.
+	MaxSeriesPerTagValue int64 `yaml:"maxSeriesPerTagValue" validate:"min=0"`
+}
+
+// RuntimeSeriesLimits returns the runtime series limits for the configuration.
+func (c SeriesLimits) RuntimeSeriesLimits() runtime.SeriesLimits {
+	return runtime.SeriesLimits{
+		MaxSeriesPerNamespace: c.MaxSeriesPerNamespace,
+		NamespaceMaxSeries:    c.NamespaceMaxSeries,
+		TagName:               c.TagName,
+		MaxSeriesPerTagValue:  c.MaxSeriesPerTagValue,
+	}
 }
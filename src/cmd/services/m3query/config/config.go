@@ -28,13 +28,17 @@ import (
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	ingestm3msg "github.com/m3db/m3/src/cmd/services/m3coordinator/ingest/m3msg"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ruler"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/influxdb"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/exemplar"
 	"github.com/m3db/m3/src/query/storage/m3"
 	xconfig "github.com/m3db/m3/src/x/config"
 	"github.com/m3db/m3/src/x/config/listenaddress"
@@ -125,6 +129,21 @@ type Configuration struct {
 	// Carbon is the carbon configuration.
 	Carbon *CarbonConfiguration `yaml:"carbon"`
 
+	// InfluxDB is the InfluxDB line protocol write configuration.
+	InfluxDB influxdb.WriteConfiguration `yaml:"influxdb"`
+
+	// OTLP is the OpenTelemetry OTLP metrics receiver configuration.
+	OTLP otlp.Configuration `yaml:"otlp"`
+
+	// Exemplars is the configuration for the exemplars of Prometheus remote
+	// writes, which are kept in memory by each coordinator and are not shared
+	// with other coordinators.
+	Exemplars exemplar.Configuration `yaml:"exemplars"`
+
+	// Ruler is the Prometheus recording and alerting rule evaluation
+	// configuration.
+	Ruler *ruler.Configuration `yaml:"ruler"`
+
 	// Limits specifies limits on per-query resource usage.
 	Limits LimitsConfiguration `yaml:"limits"`
 
@@ -292,8 +311,18 @@ type CarbonConfiguration struct {
 
 // CarbonIngesterConfiguration is the configuration struct for carbon ingestion.
 type CarbonIngesterConfiguration struct {
-	Debug          bool                              `yaml:"debug"`
-	ListenAddress  string                            `yaml:"listenAddress"`
+	Debug         bool   `yaml:"debug"`
+	ListenAddress string `yaml:"listenAddress"`
+	// PickleListenAddress is the TCP address to receive pickled metrics on,
+	// the pickle listener is disabled if not set.
+	PickleListenAddress string `yaml:"pickleListenAddress"`
+	// UDPListenAddress is the address to receive plaintext metrics over UDP
+	// on, the UDP listener is disabled if not set.
+	UDPListenAddress string `yaml:"udpListenAddress"`
+	// UDPReaders is the number of goroutines reading UDP packets, each waits
+	// for the writes of the packet it read before reading the next one,
+	// defaults to the number of CPUs.
+	UDPReaders     int                               `yaml:"udpReaders"`
 	MaxConcurrency int                               `yaml:"maxConcurrency"`
 	Rules          []CarbonIngesterRuleConfiguration `yaml:"rules"`
 }
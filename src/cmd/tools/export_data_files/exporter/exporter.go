@@ -0,0 +1,169 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	blockStartPartitionPrefix = "block_start="
+	shardFilePrefix           = "shard-"
+)
+
+type exporter struct {
+	source Source
+	opts   Options
+	logger *zap.Logger
+}
+
+// NewExporter returns a new exporter that reads data from the given source.
+func NewExporter(source Source, opts Options) (Exporter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &exporter{
+		source: source,
+		opts:   opts,
+		logger: opts.InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (e *exporter) Export(start, end time.Time) (Result, error) {
+	var result Result
+	for _, shard := range e.opts.Shards() {
+		shardResult, err := e.exportShard(shard, start, end)
+		result.SeriesBlocks += shardResult.SeriesBlocks
+		result.Rows += shardResult.Rows
+		result.Files += shardResult.Files
+		if err != nil {
+			return result, err
+		}
+
+		e.logger.Info("exported shard",
+			zap.Uint32("shard", shard),
+			zap.Int("seriesBlocks", shardResult.SeriesBlocks),
+			zap.Int("rows", shardResult.Rows))
+	}
+	return result, nil
+}
+
+func (e *exporter) exportShard(
+	shard uint32,
+	start, end time.Time,
+) (Result, error) {
+	var (
+		result   Result
+		mappings = e.opts.ColumnMappings()
+		columns  = make([]string, 0, len(mappings))
+		writers  = make(map[xtime.UnixNano]RowWriter)
+		row      = Row{Columns: make([]string, len(mappings))}
+	)
+	for _, m := range mappings {
+		columns = append(columns, m.Column)
+	}
+
+	err := e.source.ReadShard(shard, start, end, func(block SeriesBlock) error {
+		key := xtime.ToUnixNano(block.BlockStart)
+		w, ok := writers[key]
+		if !ok {
+			var err error
+			w, err = e.newRowWriter(shard, block.BlockStart, columns)
+			if err != nil {
+				return err
+			}
+			writers[key] = w
+			result.Files++
+		}
+
+		for i := range row.Columns {
+			row.Columns[i] = ""
+		}
+		for block.Tags.Next() {
+			tag := block.Tags.Current()
+			for i, m := range mappings {
+				if m.Tag == tag.Name.String() {
+					row.Columns[i] = tag.Value.String()
+				}
+			}
+		}
+		if err := block.Tags.Err(); err != nil {
+			return fmt.Errorf("unable to read tags of series %s: %v", block.ID.String(), err)
+		}
+
+		row.ID = block.ID.String()
+		result.SeriesBlocks++
+		for block.Iter.Next() {
+			dp, _, _ := block.Iter.Current()
+			if dp.Timestamp.Before(start) || !dp.Timestamp.Before(end) {
+				continue
+			}
+
+			row.Timestamp = dp.Timestamp
+			row.Value = dp.Value
+			if err := w.Write(row); err != nil {
+				return err
+			}
+			result.Rows++
+		}
+		if err := block.Iter.Err(); err != nil {
+			return fmt.Errorf("unable to decode series %s: %v", block.ID.String(), err)
+		}
+		return nil
+	})
+
+	for _, w := range writers {
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return result, err
+}
+
+func (e *exporter) newRowWriter(
+	shard uint32,
+	blockStart time.Time,
+	columns []string,
+) (RowWriter, error) {
+	dir := PartitionPath(e.opts.OutputPath(), e.opts.NamespaceID().String(), blockStart)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	name := shardFilePrefix + strconv.Itoa(int(shard)) + e.opts.Format().FileExtension()
+	return e.opts.NewRowWriterFn()(path.Join(dir, name), columns)
+}
+
+// PartitionPath returns the directory files for the given namespace and
+// block start are written to, partitioned the same way as Hive so that the
+// block start can be used as a partition column by readers such as Spark.
+func PartitionPath(outputPath, namespace string, blockStart time.Time) string {
+	partition := blockStartPartitionPrefix + strconv.FormatInt(blockStart.UnixNano(), 10)
+	return path.Join(outputPath, namespace, partition)
+}
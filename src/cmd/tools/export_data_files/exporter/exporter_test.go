@@ -0,0 +1,188 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSeries struct {
+	id         string
+	tags       ident.Tags
+	blockStart time.Time
+	datapoints []ts.Datapoint
+}
+
+type testSource struct {
+	t      *testing.T
+	series map[uint32][]testSeries
+}
+
+func (s *testSource) ReadShard(
+	shard uint32,
+	start, end time.Time,
+	fn SeriesBlockFn,
+) error {
+	encodingOpts := encoding.NewOptions()
+	for _, series := range s.series[shard] {
+		enc := m3tsz.NewEncoder(series.blockStart, nil, true, encodingOpts)
+		for _, dp := range series.datapoints {
+			require.NoError(s.t, enc.Encode(dp, xtime.Second, nil))
+		}
+		stream, ok := enc.Stream(encoding.StreamOptions{})
+		require.True(s.t, ok)
+
+		iter := m3tsz.NewReaderIterator(stream, true, encodingOpts)
+		err := fn(SeriesBlock{
+			ID:         ident.StringID(series.id),
+			Tags:       ident.NewTagsIterator(series.tags),
+			BlockStart: series.blockStart,
+			Iter:       iter,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range validFormats {
+		parsed, err := ParseFormat(f.String())
+		require.NoError(t, err)
+		assert.Equal(t, f, parsed)
+	}
+
+	_, err := ParseFormat("xml")
+	require.Error(t, err)
+}
+
+func TestOptionsValidateDuplicateColumn(t *testing.T) {
+	opts := NewOptions().
+		SetNamespaceID(ident.StringID("metrics")).
+		SetShards([]uint32{0}).
+		SetOutputPath("/tmp").
+		SetColumnMappings([]ColumnMapping{{Tag: "value", Column: "value"}})
+	require.Error(t, opts.Validate())
+}
+
+func TestExportCSV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Now().Truncate(blockSize)
+		source     = &testSource{
+			t: t,
+			series: map[uint32][]testSeries{
+				1: {
+					{
+						id: "foo",
+						tags: ident.NewTags(
+							ident.StringTag("city", "nyc"),
+							ident.StringTag("host", "a")),
+						blockStart: blockStart,
+						datapoints: []ts.Datapoint{
+							{Timestamp: blockStart, Value: 1},
+							{Timestamp: blockStart.Add(time.Minute), Value: 2.5},
+						},
+					},
+					{
+						id:         "bar",
+						tags:       ident.NewTags(ident.StringTag("host", "b")),
+						blockStart: blockStart,
+						datapoints: []ts.Datapoint{
+							{Timestamp: blockStart.Add(time.Hour), Value: 3},
+						},
+					},
+				},
+			},
+		}
+		opts = NewOptions().
+			SetNamespaceID(ident.StringID("metrics")).
+			SetShards([]uint32{0, 1}).
+			SetOutputPath(dir).
+			SetColumnMappings([]ColumnMapping{
+				{Tag: "city", Column: "location"},
+			})
+	)
+
+	exp, err := NewExporter(source, opts)
+	require.NoError(t, err)
+
+	result, err := exp.Export(blockStart, blockStart.Add(blockSize))
+	require.NoError(t, err)
+	assert.Equal(t, Result{SeriesBlocks: 2, Rows: 3, Files: 1}, result)
+
+	data, err := ioutil.ReadFile(path.Join(
+		PartitionPath(dir, "metrics", blockStart), "shard-1.csv"))
+	require.NoError(t, err)
+
+	var expected bytes.Buffer
+	expected.WriteString("id,timestamp,value,location\n")
+	expected.WriteString("foo," + nanos(blockStart) + ",1,nyc\n")
+	expected.WriteString("foo," + nanos(blockStart.Add(time.Minute)) + ",2.5,nyc\n")
+	expected.WriteString("bar," + nanos(blockStart.Add(time.Hour)) + ",3,\n")
+	assert.Equal(t, expected.String(), string(data))
+}
+
+func TestBlockOverlaps(t *testing.T) {
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Unix(0, 0).Add(10 * blockSize)
+	)
+	tests := []struct {
+		start, end time.Time
+		expected   bool
+	}{
+		{start: blockStart, end: blockStart.Add(blockSize), expected: true},
+		// Blocks starting before the range that extend into it overlap.
+		{start: blockStart.Add(time.Hour), end: blockStart.Add(3 * time.Hour), expected: true},
+		{start: blockStart.Add(-time.Hour), end: blockStart.Add(time.Minute), expected: true},
+		{start: blockStart.Add(blockSize), end: blockStart.Add(2 * blockSize), expected: false},
+		{start: blockStart.Add(-time.Hour), end: blockStart, expected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected,
+			blockOverlaps(blockStart, blockSize, test.start, test.end))
+	}
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	errNamespaceIDNotSet = errors.New("namespace ID not set")
+	errNoShards          = errors.New("no shards to export")
+	errOutputPathNotSet  = errors.New("output path not set")
+)
+
+// reservedColumns are the columns every exported row has.
+var reservedColumns = []string{"id", "timestamp", "value"}
+
+type options struct {
+	namespaceID    ident.ID
+	shards         []uint32
+	outputPath     string
+	format         Format
+	columnMappings []ColumnMapping
+	newRowWriterFn NewRowWriterFn
+	encodingOpts   encoding.Options
+	iOpts          instrument.Options
+}
+
+// NewOptions returns a new set of export options.
+func NewOptions() Options {
+	return &options{
+		format:       CSVFormat,
+		encodingOpts: encoding.NewOptions(),
+		iOpts:        instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.namespaceID == nil {
+		return errNamespaceIDNotSet
+	}
+	if len(o.shards) == 0 {
+		return errNoShards
+	}
+	if o.outputPath == "" {
+		return errOutputPathNotSet
+	}
+	if _, err := ParseFormat(o.format.String()); err != nil {
+		return err
+	}
+
+	columns := make(map[string]struct{}, len(reservedColumns)+len(o.columnMappings))
+	for _, c := range reservedColumns {
+		columns[c] = struct{}{}
+	}
+	for _, m := range o.columnMappings {
+		if m.Tag == "" || m.Column == "" {
+			return fmt.Errorf("invalid column mapping, tag and column required: %+v", m)
+		}
+		if _, ok := columns[m.Column]; ok {
+			return fmt.Errorf("duplicate column: %s", m.Column)
+		}
+		columns[m.Column] = struct{}{}
+	}
+	return nil
+}
+
+func (o *options) SetNamespaceID(value ident.ID) Options {
+	opts := *o
+	opts.namespaceID = value
+	return &opts
+}
+
+func (o *options) NamespaceID() ident.ID {
+	return o.namespaceID
+}
+
+func (o *options) SetShards(value []uint32) Options {
+	opts := *o
+	opts.shards = value
+	return &opts
+}
+
+func (o *options) Shards() []uint32 {
+	return o.shards
+}
+
+func (o *options) SetOutputPath(value string) Options {
+	opts := *o
+	opts.outputPath = value
+	return &opts
+}
+
+func (o *options) OutputPath() string {
+	return o.outputPath
+}
+
+func (o *options) SetFormat(value Format) Options {
+	opts := *o
+	opts.format = value
+	return &opts
+}
+
+func (o *options) Format() Format {
+	return o.format
+}
+
+func (o *options) SetColumnMappings(value []ColumnMapping) Options {
+	opts := *o
+	opts.columnMappings = value
+	return &opts
+}
+
+func (o *options) ColumnMappings() []ColumnMapping {
+	return o.columnMappings
+}
+
+func (o *options) SetNewRowWriterFn(value NewRowWriterFn) Options {
+	opts := *o
+	opts.newRowWriterFn = value
+	return &opts
+}
+
+func (o *options) NewRowWriterFn() NewRowWriterFn {
+	if o.newRowWriterFn != nil {
+		return o.newRowWriterFn
+	}
+	switch o.format {
+	case ParquetFormat:
+		return NewParquetRowWriter
+	default:
+		return NewCSVRowWriter
+	}
+}
+
+func (o *options) SetEncodingOptions(value encoding.Options) Options {
+	opts := *o
+	opts.encodingOpts = value
+	return &opts
+}
+
+func (o *options) EncodingOptions() encoding.Options {
+	return o.encodingOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.iOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
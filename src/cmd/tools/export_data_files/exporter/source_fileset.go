@@ -0,0 +1,165 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+)
+
+type fileSetSource struct {
+	namespaceID  ident.ID
+	blockSize    time.Duration
+	fsOpts       fs.Options
+	bytesPool    pool.CheckedBytesPool
+	encodingOpts encoding.Options
+}
+
+// NewFileSetSource returns a source that reads the latest complete volume
+// of each flushed data fileset of a namespace with the given block size from
+// local disk.
+func NewFileSetSource(
+	namespaceID ident.ID,
+	blockSize time.Duration,
+	fsOpts fs.Options,
+	bytesPool pool.CheckedBytesPool,
+	encodingOpts encoding.Options,
+) Source {
+	return &fileSetSource{
+		namespaceID:  namespaceID,
+		blockSize:    blockSize,
+		fsOpts:       fsOpts,
+		bytesPool:    bytesPool,
+		encodingOpts: encodingOpts,
+	}
+}
+
+func (s *fileSetSource) ReadShard(
+	shard uint32,
+	start, end time.Time,
+	fn SeriesBlockFn,
+) error {
+	files, err := fs.DataFiles(s.fsOpts.FilePathPrefix(), s.namespaceID, shard)
+	if err != nil {
+		return fmt.Errorf("unable to list filesets for shard %d: %v", shard, err)
+	}
+
+	var (
+		blockStarts []time.Time
+		seen        = make(map[int64]struct{})
+	)
+	for _, f := range files {
+		blockStart := f.ID.BlockStart
+		if !blockOverlaps(blockStart, s.blockSize, start, end) {
+			continue
+		}
+		if _, ok := seen[blockStart.UnixNano()]; ok {
+			continue
+		}
+		seen[blockStart.UnixNano()] = struct{}{}
+		blockStarts = append(blockStarts, blockStart)
+	}
+	sort.Slice(blockStarts, func(i, j int) bool {
+		return blockStarts[i].Before(blockStarts[j])
+	})
+
+	reader, err := fs.NewReader(s.bytesPool, s.fsOpts)
+	if err != nil {
+		return fmt.Errorf("unable to create fileset reader: %v", err)
+	}
+
+	iter := m3tsz.NewReaderIterator(nil, m3tsz.DefaultIntOptimizationEnabled, s.encodingOpts)
+	defer iter.Close()
+
+	for _, blockStart := range blockStarts {
+		f, ok := files.LatestVolumeForBlock(blockStart)
+		if !ok {
+			// No complete volume for this block.
+			continue
+		}
+		if err := s.readFileSet(reader, f.ID, iter, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fileSetSource) readFileSet(
+	reader fs.DataFileSetReader,
+	id fs.FileSetFileIdentifier,
+	iter encoding.ReaderIterator,
+	fn SeriesBlockFn,
+) error {
+	err := reader.Open(fs.DataReaderOpenOptions{
+		Identifier:  id,
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to open fileset %+v: %v", id, err)
+	}
+
+	for {
+		seriesID, tags, data, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			reader.Close()
+			return fmt.Errorf("unable to read fileset %+v: %v", id, err)
+		}
+
+		data.IncRef()
+		iter.Reset(bytes.NewReader(data.Bytes()), nil)
+		err = fn(SeriesBlock{
+			ID:         seriesID,
+			Tags:       tags,
+			BlockStart: id.BlockStart,
+			Iter:       iter,
+		})
+		data.DecRef()
+		data.Finalize()
+		tags.Close()
+		seriesID.Finalize()
+		if err != nil {
+			reader.Close()
+			return err
+		}
+	}
+
+	return reader.Close()
+}
+
+// blockOverlaps returns whether the block starting at the given time overlaps
+// the range between start inclusive and end exclusive.
+func blockOverlaps(blockStart time.Time, blockSize time.Duration, start, end time.Time) bool {
+	return blockStart.Add(blockSize).After(start) && blockStart.Before(end)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+type peersSource struct {
+	session          client.AdminSession
+	nsMetadata       namespace.Metadata
+	consistencyLevel topology.ReadConsistencyLevel
+	resultOpts       result.Options
+	encodingOpts     encoding.Options
+}
+
+// NewPeersSource returns a source that streams blocks from the peers of a
+// live cluster, reading each series block from a single replica.
+func NewPeersSource(
+	session client.AdminSession,
+	nsMetadata namespace.Metadata,
+	consistencyLevel topology.ReadConsistencyLevel,
+	resultOpts result.Options,
+	encodingOpts encoding.Options,
+) Source {
+	return &peersSource{
+		session:          session,
+		nsMetadata:       nsMetadata,
+		consistencyLevel: consistencyLevel,
+		resultOpts:       resultOpts,
+		encodingOpts:     encodingOpts,
+	}
+}
+
+type seriesBlockKey struct {
+	id         string
+	blockStart int64
+}
+
+func (s *peersSource) ReadShard(
+	shard uint32,
+	start, end time.Time,
+	fn SeriesBlockFn,
+) error {
+	metadataIter, err := s.session.FetchBlocksMetadataFromPeers(
+		s.nsMetadata.ID(), shard, start, end, s.consistencyLevel, s.resultOpts)
+	if err != nil {
+		return fmt.Errorf("unable to fetch blocks metadata for shard %d: %v", shard, err)
+	}
+
+	var (
+		metadatas []block.ReplicaMetadata
+		tagsByID  = make(map[string]ident.Tags)
+		seen      = make(map[seriesBlockKey]struct{})
+	)
+	for metadataIter.Next() {
+		host, metadata := metadataIter.Current()
+		key := seriesBlockKey{
+			id:         metadata.ID.String(),
+			blockStart: metadata.Start.UnixNano(),
+		}
+		// Every replica returns metadata for its copy of the block,
+		// only fetch one of them.
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		tagsByID[key.id] = metadata.Tags
+		metadatas = append(metadatas, block.ReplicaMetadata{
+			Metadata: metadata,
+			Host:     host,
+		})
+	}
+	if err := metadataIter.Err(); err != nil {
+		return fmt.Errorf("unable to fetch blocks metadata for shard %d: %v", shard, err)
+	}
+
+	blocksIter, err := s.session.FetchBlocksFromPeers(s.nsMetadata, shard,
+		s.consistencyLevel, metadatas, s.resultOpts)
+	if err != nil {
+		return fmt.Errorf("unable to fetch blocks for shard %d: %v", shard, err)
+	}
+
+	iter := m3tsz.NewReaderIterator(nil, m3tsz.DefaultIntOptimizationEnabled, s.encodingOpts)
+	defer iter.Close()
+
+	for blocksIter.Next() {
+		_, id, dbBlock := blocksIter.Current()
+		if err := s.readBlock(id, tagsByID[id.String()], dbBlock, iter, fn); err != nil {
+			return err
+		}
+	}
+	if err := blocksIter.Err(); err != nil {
+		return fmt.Errorf("unable to fetch blocks for shard %d: %v", shard, err)
+	}
+
+	return nil
+}
+
+func (s *peersSource) readBlock(
+	id ident.ID,
+	tags ident.Tags,
+	dbBlock block.DatabaseBlock,
+	iter encoding.ReaderIterator,
+	fn SeriesBlockFn,
+) error {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	reader, err := dbBlock.Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to stream block of series %s: %v", id.String(), err)
+	}
+
+	iter.Reset(reader, nil)
+	return fn(SeriesBlock{
+		ID:         id,
+		Tags:       ident.NewTagsIterator(tags),
+		BlockStart: dbBlock.StartTime(),
+		Iter:       iter,
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Format is the file format exported data is written in.
+type Format int
+
+const (
+	// CSVFormat writes comma separated values with a header row.
+	CSVFormat Format = iota
+	// ParquetFormat writes Parquet files.
+	ParquetFormat
+)
+
+var validFormats = []Format{
+	CSVFormat,
+	ParquetFormat,
+}
+
+func (f Format) String() string {
+	switch f {
+	case CSVFormat:
+		return "csv"
+	case ParquetFormat:
+		return "parquet"
+	default:
+		return "unknown"
+	}
+}
+
+// FileExtension returns the file extension used for files of the format.
+func (f Format) FileExtension() string {
+	return "." + f.String()
+}
+
+// ParseFormat parses a format from its string representation.
+func ParseFormat(str string) (Format, error) {
+	for _, valid := range validFormats {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid format '%s', valid formats are: %v", str, validFormats)
+}
+
+// ColumnMapping maps the value of a tag to a column of the exported rows.
+type ColumnMapping struct {
+	Tag    string
+	Column string
+}
+
+// SeriesBlock is the encoded data of a single series for a single block.
+type SeriesBlock struct {
+	ID         ident.ID
+	Tags       ident.TagIterator
+	BlockStart time.Time
+	Iter       encoding.ReaderIterator
+}
+
+// SeriesBlockFn is called for each series block read from a source, the
+// series block is only valid for the duration of the call.
+type SeriesBlockFn func(block SeriesBlock) error
+
+// Source reads encoded series blocks for a namespace.
+type Source interface {
+	// ReadShard calls fn for each series block of the given shard with a
+	// block start in the range [start, end).
+	ReadShard(shard uint32, start, end time.Time, fn SeriesBlockFn) error
+}
+
+// Row is a single exported datapoint.
+type Row struct {
+	ID        string
+	Timestamp time.Time
+	Value     float64
+	// Columns holds the values of the mapped tag columns, in the order
+	// of the column mappings.
+	Columns []string
+}
+
+// RowWriter writes rows to a single exported file.
+type RowWriter interface {
+	// Write writes a single row.
+	Write(row Row) error
+
+	// Close flushes and closes the file.
+	Close() error
+}
+
+// NewRowWriterFn creates a row writer for the file at the given path with
+// the given mapped tag column names.
+type NewRowWriterFn func(path string, columns []string) (RowWriter, error)
+
+// Exporter exports data of a namespace to files partitioned by block start.
+type Exporter interface {
+	// Export exports all series blocks of the configured shards in the
+	// range [start, end).
+	Export(start, end time.Time) (Result, error)
+}
+
+// Result describes the outcome of an export.
+type Result struct {
+	SeriesBlocks int
+	Rows         int
+	Files        int
+}
+
+// Options represents the knobs available while exporting.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetNamespaceID sets the namespace being exported.
+	SetNamespaceID(value ident.ID) Options
+
+	// NamespaceID returns the namespace being exported.
+	NamespaceID() ident.ID
+
+	// SetShards sets the shards to export.
+	SetShards(value []uint32) Options
+
+	// Shards returns the shards to export.
+	Shards() []uint32
+
+	// SetOutputPath sets the directory exported files are written under.
+	SetOutputPath(value string) Options
+
+	// OutputPath returns the directory exported files are written under.
+	OutputPath() string
+
+	// SetFormat sets the format of exported files.
+	SetFormat(value Format) Options
+
+	// Format returns the format of exported files.
+	Format() Format
+
+	// SetColumnMappings sets the tags to export as columns.
+	SetColumnMappings(value []ColumnMapping) Options
+
+	// ColumnMappings returns the tags to export as columns.
+	ColumnMappings() []ColumnMapping
+
+	// SetNewRowWriterFn sets the function used to create row writers,
+	// by default the writer for the configured format is used.
+	SetNewRowWriterFn(value NewRowWriterFn) Options
+
+	// NewRowWriterFn returns the function used to create row writers.
+	NewRowWriterFn() NewRowWriterFn
+
+	// SetEncodingOptions sets the encoding options.
+	SetEncodingOptions(value encoding.Options) Options
+
+	// EncodingOptions returns the encoding options.
+	EncodingOptions() encoding.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"os"
+	"strconv"
+)
+
+type csvRowWriter struct {
+	file   *os.File
+	buffer *bufio.Writer
+	writer *csv.Writer
+	record []string
+}
+
+// NewCSVRowWriter returns a row writer that writes a CSV file with a header
+// row, timestamps are written as unix nanoseconds.
+func NewCSVRowWriter(path string, columns []string) (RowWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		buffer = bufio.NewWriter(file)
+		writer = csv.NewWriter(buffer)
+		header = make([]string, 0, len(reservedColumns)+len(columns))
+	)
+	header = append(header, reservedColumns...)
+	header = append(header, columns...)
+	if err := writer.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &csvRowWriter{
+		file:   file,
+		buffer: buffer,
+		writer: writer,
+		record: make([]string, len(header)),
+	}, nil
+}
+
+func (w *csvRowWriter) Write(row Row) error {
+	w.record[0] = row.ID
+	w.record[1] = strconv.FormatInt(row.Timestamp.UnixNano(), 10)
+	w.record[2] = strconv.FormatFloat(row.Value, 'g', -1, 64)
+	copy(w.record[len(reservedColumns):], row.Columns)
+	return w.writer.Write(w.record)
+}
+
+func (w *csvRowWriter) Close() error {
+	w.writer.Flush()
+	err := w.writer.Error()
+	if err == nil {
+		err = w.buffer.Flush()
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const parquetWriterParallelism = 4
+
+type parquetRowWriter struct {
+	file   source.ParquetFile
+	writer *writer.CSVWriter
+	record []interface{}
+}
+
+// NewParquetRowWriter returns a row writer that writes a Parquet file,
+// timestamps are written as unix nanoseconds.
+func NewParquetRowWriter(path string, columns []string) (RowWriter, error) {
+	schema := []string{
+		fmt.Sprintf("name=%s, type=UTF8, encoding=PLAIN_DICTIONARY", reservedColumns[0]),
+		fmt.Sprintf("name=%s, type=INT64", reservedColumns[1]),
+		fmt.Sprintf("name=%s, type=DOUBLE", reservedColumns[2]),
+	}
+	for _, c := range columns {
+		schema = append(schema,
+			fmt.Sprintf("name=%s, type=UTF8, encoding=PLAIN_DICTIONARY", c))
+	}
+
+	file, err := local.NewLocalFileWriter(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := writer.NewCSVWriter(schema, file, parquetWriterParallelism)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &parquetRowWriter{
+		file:   file,
+		writer: w,
+		record: make([]interface{}, len(schema)),
+	}, nil
+}
+
+func (w *parquetRowWriter) Write(row Row) error {
+	w.record[0] = row.ID
+	w.record[1] = row.Timestamp.UnixNano()
+	w.record[2] = row.Value
+	for i, v := range row.Columns {
+		w.record[len(reservedColumns)+i] = v
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *parquetRowWriter) Close() error {
+	err := w.writer.WriteStop()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
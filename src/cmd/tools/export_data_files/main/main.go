@@ -0,0 +1,212 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/tools"
+	"github.com/m3db/m3/src/cmd/tools/export_data_files/exporter"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xconfig "github.com/m3db/m3/src/x/config"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/pborman/getopt"
+	"go.uber.org/zap"
+)
+
+// peersConfiguration is the configuration used to export from a live cluster.
+type peersConfiguration struct {
+	// Client is the M3DB client configuration.
+	Client client.Configuration `yaml:"client"`
+
+	// ConsistencyLevel is the consistency level used to fetch blocks,
+	// defaults to majority.
+	ConsistencyLevel *topology.ReadConsistencyLevel `yaml:"consistencyLevel"`
+}
+
+func main() {
+	var (
+		optPathPrefix   = getopt.StringLong("path-prefix", 'p', "", "Path prefix, to export from local filesets [e.g. /var/lib/m3db]")
+		optClientConfig = getopt.StringLong("client-config", 'c', "", "Client config file, to export from a live cluster")
+		optNamespace    = getopt.StringLong("namespace", 'n', "", "Namespace [e.g. metrics]")
+		optShards       = getopt.StringLong("shards", 's', "", "Comma separated shards [e.g. 0,1,2]")
+		optStart        = getopt.Int64Long("start", 'b', 0, "Start time, inclusive [in nsec]")
+		optEnd          = getopt.Int64Long("end", 'e', 0, "End time, exclusive [in nsec]")
+		optBlockSize    = getopt.DurationLong("block-size", 'z', 2*time.Hour, "Namespace block size [e.g. 2h]")
+		optOutputPath   = getopt.StringLong("output-path", 'o', "", "Output path [e.g. /tmp/export]")
+		optFormat       = getopt.StringLong("format", 'f', exporter.CSVFormat.String(),
+			fmt.Sprintf("Output format [%s|%s]", exporter.CSVFormat, exporter.ParquetFormat))
+		optColumns = getopt.StringLong("columns", 't', "", "Comma separated tag to column mappings [e.g. city:location,host:host]")
+	)
+	getopt.Parse()
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	if (*optPathPrefix == "") == (*optClientConfig == "") ||
+		*optNamespace == "" ||
+		*optShards == "" ||
+		*optStart <= 0 ||
+		*optEnd <= *optStart ||
+		*optOutputPath == "" {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	shards, err := parseShards(*optShards)
+	if err != nil {
+		logger.Fatalf("invalid shards: %v", err)
+	}
+	format, err := exporter.ParseFormat(*optFormat)
+	if err != nil {
+		logger.Fatalf("invalid format: %v", err)
+	}
+	mappings, err := parseColumnMappings(*optColumns)
+	if err != nil {
+		logger.Fatalf("invalid columns: %v", err)
+	}
+
+	var (
+		nsID         = ident.StringID(*optNamespace)
+		iOpts        = instrument.NewOptions().SetLogger(rawLogger)
+		bytesPool    = tools.NewCheckedBytesPool()
+		encodingOpts = encoding.NewOptions().SetBytesPool(bytesPool)
+		source       exporter.Source
+	)
+	if *optPathPrefix != "" {
+		source = exporter.NewFileSetSource(nsID, *optBlockSize,
+			fs.NewOptions().SetFilePathPrefix(*optPathPrefix), bytesPool, encodingOpts)
+	} else {
+		source, err = newPeersSource(*optClientConfig, nsID, *optBlockSize, iOpts, encodingOpts)
+		if err != nil {
+			logger.Fatalf("unable to create peers source: %v", err)
+		}
+	}
+
+	opts := exporter.NewOptions().
+		SetNamespaceID(nsID).
+		SetShards(shards).
+		SetOutputPath(*optOutputPath).
+		SetFormat(format).
+		SetColumnMappings(mappings).
+		SetEncodingOptions(encodingOpts).
+		SetInstrumentOptions(iOpts)
+
+	exp, err := exporter.NewExporter(source, opts)
+	if err != nil {
+		logger.Fatalf("unable to create exporter: %v", err)
+	}
+
+	res, err := exp.Export(time.Unix(0, *optStart), time.Unix(0, *optEnd))
+	if err != nil {
+		logger.Fatalf("unable to export: %v", err)
+	}
+
+	logger.Infof("successfully exported %d series blocks, %d rows to %d files",
+		res.SeriesBlocks, res.Rows, res.Files)
+}
+
+func newPeersSource(
+	configFile string,
+	nsID ident.ID,
+	blockSize time.Duration,
+	iOpts instrument.Options,
+	encodingOpts encoding.Options,
+) (exporter.Source, error) {
+	var cfg peersConfiguration
+	if err := xconfig.LoadFile(&cfg, configFile, xconfig.Options{}); err != nil {
+		return nil, err
+	}
+
+	adminClient, err := cfg.Client.NewAdminClient(client.ConfigurationParameters{
+		InstrumentOptions: iOpts,
+		EncodingOptions:   encodingOpts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := adminClient.DefaultAdminSession()
+	if err != nil {
+		return nil, err
+	}
+
+	nsMetadata, err := namespace.NewMetadata(nsID, namespace.NewOptions().
+		SetRetentionOptions(retention.NewOptions().SetBlockSize(blockSize)))
+	if err != nil {
+		return nil, err
+	}
+
+	consistencyLevel := topology.ReadConsistencyLevelMajority
+	if cfg.ConsistencyLevel != nil {
+		consistencyLevel = *cfg.ConsistencyLevel
+	}
+
+	return exporter.NewPeersSource(session, nsMetadata, consistencyLevel,
+		result.NewOptions(), encodingOpts), nil
+}
+
+func parseShards(str string) ([]uint32, error) {
+	var shards []uint32
+	for _, s := range strings.Split(str, ",") {
+		shard, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, uint32(shard))
+	}
+	return shards, nil
+}
+
+func parseColumnMappings(str string) ([]exporter.ColumnMapping, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	var mappings []exporter.ColumnMapping
+	for _, m := range strings.Split(str, ",") {
+		parts := strings.Split(m, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected tag:column, got: %s", m)
+		}
+		mappings = append(mappings, exporter.ColumnMapping{
+			Tag:    parts[0],
+			Column: parts[1],
+		})
+	}
+	return mappings, nil
+}
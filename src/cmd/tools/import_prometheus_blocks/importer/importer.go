@@ -0,0 +1,488 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	m3ninxpersist "github.com/m3db/m3/src/m3ninx/persist"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+	"go.uber.org/zap"
+)
+
+// allSeriesMatcher matches every series in a Prometheus block, all
+// Prometheus series are required to have a non-empty metric name.
+var allSeriesMatcher = labels.NewMustRegexpMatcher(labels.MetricName, ".+")
+
+type importer struct {
+	opts   Options
+	logger *zap.Logger
+}
+
+// NewImporter returns a new Prometheus block importer.
+func NewImporter(opts Options) (Importer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &importer{
+		opts:   opts,
+		logger: opts.InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (i *importer) Import(blockDirs []string) (Result, error) {
+	var result Result
+
+	blocks, err := openBlocks(blockDirs)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		for _, b := range blocks {
+			if err := b.Close(); err != nil {
+				i.logger.Warn("unable to close prometheus block",
+					zap.String("dir", b.Dir()), zap.Error(err))
+			}
+		}
+	}()
+
+	if len(blocks) == 0 {
+		return result, nil
+	}
+
+	var (
+		blockSize = i.opts.BlockSize()
+		minTime   = blocks[0].Meta().MinTime
+		maxTime   = blocks[0].Meta().MaxTime
+		builders  = make(map[xtime.UnixNano]segment.DocumentsBuilder)
+		seen      = make(map[string]struct{})
+	)
+	for _, b := range blocks[1:] {
+		if meta := b.Meta(); meta.MinTime < minTime {
+			minTime = meta.MinTime
+		}
+		if meta := b.Meta(); meta.MaxTime > maxTime {
+			maxTime = meta.MaxTime
+		}
+	}
+
+	var (
+		start = timeFromMillis(minTime).Truncate(blockSize)
+		end   = timeFromMillis(maxTime)
+	)
+	for blockStart := start; blockStart.Before(end); blockStart = blockStart.Add(blockSize) {
+		window, err := i.readWindow(blocks, blockStart, blockStart.Add(blockSize))
+		if err != nil {
+			return result, err
+		}
+		if len(window) == 0 {
+			continue
+		}
+
+		for _, s := range window {
+			if _, ok := seen[string(s.id)]; !ok {
+				seen[string(s.id)] = struct{}{}
+				result.Series++
+			}
+			result.Datapoints += len(s.datapoints)
+			if err := i.indexSeries(builders, s); err != nil {
+				return result, err
+			}
+		}
+
+		written, err := i.writeData(blockStart, window)
+		if err != nil {
+			return result, err
+		}
+		result.DataFileSets += written
+
+		i.logger.Info("imported data block",
+			zap.Time("blockStart", blockStart),
+			zap.Int("series", len(window)))
+	}
+
+	written, err := i.writeIndex(builders)
+	if err != nil {
+		return result, err
+	}
+	result.IndexFileSets = written
+
+	return result, nil
+}
+
+// importSeries is a single series read from one or more Prometheus
+// blocks for a single m3db block.
+type importSeries struct {
+	id         []byte
+	tags       models.Tags
+	datapoints []ts.Datapoint
+}
+
+func (i *importer) readWindow(
+	blocks []*tsdb.Block,
+	start, end time.Time,
+) ([]*importSeries, error) {
+	var (
+		startMillis = millisFromTime(start)
+		// NB: Prometheus query time ranges are inclusive of max time.
+		endMillis = millisFromTime(end) - 1
+		byID      = make(map[string]*importSeries)
+		result    []*importSeries
+	)
+	for _, b := range blocks {
+		meta := b.Meta()
+		if meta.MaxTime <= startMillis || meta.MinTime > endMillis {
+			continue
+		}
+
+		q, err := tsdb.NewBlockQuerier(b, startMillis, endMillis)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query block %s: %v", b.Dir(), err)
+		}
+
+		seriesSet, err := q.Select(allSeriesMatcher)
+		if err != nil {
+			q.Close()
+			return nil, fmt.Errorf("unable to select series in block %s: %v", b.Dir(), err)
+		}
+
+		for seriesSet.Next() {
+			series := seriesSet.At()
+			tags := labelsToTags(series.Labels(), i.opts.TagOptions())
+			id := tags.ID()
+
+			entry, ok := byID[string(id)]
+			if !ok {
+				entry = &importSeries{id: id, tags: tags}
+				byID[string(id)] = entry
+				result = append(result, entry)
+			}
+
+			iter := series.Iterator()
+			for iter.Next() {
+				t, v := iter.At()
+				if t < startMillis || t > endMillis {
+					continue
+				}
+				entry.datapoints = append(entry.datapoints, ts.Datapoint{
+					Timestamp: timeFromMillis(t),
+					Value:     v,
+				})
+			}
+			if err := iter.Err(); err != nil {
+				q.Close()
+				return nil, fmt.Errorf("unable to read series %s in block %s: %v",
+					id, b.Dir(), err)
+			}
+		}
+
+		err = seriesSet.Err()
+		if closeErr := q.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read block %s: %v", b.Dir(), err)
+		}
+	}
+
+	// Overlapping blocks may each contribute samples for the same series, and
+	// series can have chunks that overlap the window without any samples in it.
+	nonEmpty := result[:0]
+	for _, s := range result {
+		if len(s.datapoints) == 0 {
+			continue
+		}
+		s.datapoints = sortAndDedupeDatapoints(s.datapoints)
+		nonEmpty = append(nonEmpty, s)
+	}
+
+	return nonEmpty, nil
+}
+
+func (i *importer) writeData(
+	blockStart time.Time,
+	window []*importSeries,
+) (int, error) {
+	var (
+		shardSet = i.opts.ShardSet()
+		byShard  = make(map[uint32][]*importSeries, len(shardSet.AllIDs()))
+	)
+	for _, s := range window {
+		shard := shardSet.Lookup(ident.BytesID(s.id))
+		byShard[shard] = append(byShard[shard], s)
+	}
+
+	writer, err := fs.NewWriter(i.opts.FilesystemOptions())
+	if err != nil {
+		return 0, fmt.Errorf("unable to create fileset writer: %v", err)
+	}
+
+	var (
+		filePathPrefix = i.opts.FilesystemOptions().FilePathPrefix()
+		nsID           = i.opts.NamespaceID()
+		encoder        = m3tsz.NewEncoder(blockStart, nil,
+			m3tsz.DefaultIntOptimizationEnabled, i.opts.EncodingOptions())
+		data    = make([]checked.Bytes, 2)
+		written int
+	)
+	// NB: Write a fileset for every shard, including empty ones, so that
+	// the filesystem bootstrapper considers the whole block fulfilled.
+	for _, shard := range shardSet.AllIDs() {
+		exists, err := fs.DataFileSetExists(filePathPrefix, nsID, shard, blockStart, 0)
+		if err != nil {
+			return written, err
+		}
+		if exists {
+			return written, fmt.Errorf(
+				"fileset already exists: namespace=%s, shard=%d, blockStart=%v",
+				nsID.String(), shard, blockStart)
+		}
+
+		err = writer.Open(fs.DataWriterOpenOptions{
+			BlockSize: i.opts.BlockSize(),
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  nsID,
+				Shard:      shard,
+				BlockStart: blockStart,
+			},
+			FileSetType: persist.FileSetFlushType,
+		})
+		if err != nil {
+			return written, fmt.Errorf("unable to open fileset writer: %v", err)
+		}
+
+		for _, s := range byShard[shard] {
+			for _, dp := range s.datapoints {
+				if err := encoder.Encode(dp, xtime.Millisecond, nil); err != nil {
+					return written, fmt.Errorf("unable to encode series %s: %v", s.id, err)
+				}
+			}
+
+			segment := encoder.DiscardReset(blockStart, 0, nil)
+			data[0] = segment.Head
+			data[1] = segment.Tail
+			checksum := digest.SegmentChecksum(segment)
+			err := writer.WriteAll(ident.BytesID(s.id), tagsToIdentTags(s.tags), data, checksum)
+			segment.Finalize()
+			if err != nil {
+				return written, fmt.Errorf("unable to write series %s: %v", s.id, err)
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			return written, fmt.Errorf("unable to close fileset writer: %v", err)
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+func (i *importer) indexSeries(
+	builders map[xtime.UnixNano]segment.DocumentsBuilder,
+	s *importSeries,
+) error {
+	if len(s.datapoints) == 0 {
+		return nil
+	}
+
+	var (
+		indexBlockSize = i.opts.IndexBlockSize()
+		first          = s.datapoints[0].Timestamp.Truncate(indexBlockSize)
+		last           = s.datapoints[len(s.datapoints)-1].Timestamp
+	)
+	for blockStart := first; !blockStart.After(last); blockStart = blockStart.Add(indexBlockSize) {
+		key := xtime.ToUnixNano(blockStart)
+		b, ok := builders[key]
+		if !ok {
+			var err error
+			b, err = builder.NewBuilderFromDocuments(builder.NewOptions())
+			if err != nil {
+				return err
+			}
+			builders[key] = b
+		}
+
+		d, err := convert.FromMetric(ident.BytesID(s.id), tagsToIdentTags(s.tags))
+		if err != nil {
+			return fmt.Errorf("unable to index series %s: %v", s.id, err)
+		}
+		// NB: Series that span several data blocks will be inserted once
+		// per data block, the first insert is the only one that matters.
+		if _, err := b.Insert(d); err != nil && err != m3ninxindex.ErrDuplicateID {
+			return fmt.Errorf("unable to index series %s: %v", s.id, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *importer) writeIndex(
+	builders map[xtime.UnixNano]segment.DocumentsBuilder,
+) (int, error) {
+	if len(builders) == 0 {
+		return 0, nil
+	}
+
+	writer, err := fs.NewIndexWriter(i.opts.FilesystemOptions())
+	if err != nil {
+		return 0, fmt.Errorf("unable to create index writer: %v", err)
+	}
+	segmentWriter, err := m3ninxpersist.NewMutableSegmentFileSetWriter()
+	if err != nil {
+		return 0, fmt.Errorf("unable to create index segment writer: %v", err)
+	}
+
+	var (
+		filePathPrefix = i.opts.FilesystemOptions().FilePathPrefix()
+		nsID           = i.opts.NamespaceID()
+		shards         = make(map[uint32]struct{})
+		written        int
+	)
+	for _, shard := range i.opts.ShardSet().AllIDs() {
+		shards[shard] = struct{}{}
+	}
+
+	for key, b := range builders {
+		blockStart := key.ToTime()
+		volumeIndex, err := fs.NextIndexFileSetVolumeIndex(filePathPrefix, nsID, blockStart)
+		if err != nil {
+			return written, err
+		}
+
+		err = writer.Open(fs.IndexWriterOpenOptions{
+			Identifier: fs.FileSetFileIdentifier{
+				FileSetContentType: persist.FileSetIndexContentType,
+				Namespace:          nsID,
+				BlockStart:         blockStart,
+				VolumeIndex:        volumeIndex,
+			},
+			BlockSize:   i.opts.IndexBlockSize(),
+			FileSetType: persist.FileSetFlushType,
+			Shards:      shards,
+		})
+		if err != nil {
+			return written, fmt.Errorf("unable to open index writer: %v", err)
+		}
+
+		if err := segmentWriter.Reset(b); err != nil {
+			return written, fmt.Errorf("unable to build index segment: %v", err)
+		}
+		if err := writer.WriteSegmentFileSet(segmentWriter); err != nil {
+			return written, fmt.Errorf("unable to write index segment: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			return written, fmt.Errorf("unable to close index writer: %v", err)
+		}
+		written++
+
+		i.logger.Info("imported index block",
+			zap.Time("blockStart", blockStart),
+			zap.Int("series", len(b.Docs())))
+	}
+
+	return written, nil
+}
+
+func openBlocks(dirs []string) ([]*tsdb.Block, error) {
+	blocks := make([]*tsdb.Block, 0, len(dirs))
+	for _, dir := range dirs {
+		b, err := tsdb.OpenBlock(nil, dir, nil)
+		if err != nil {
+			for _, opened := range blocks {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("unable to open prometheus block %s: %v", dir, err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// labelsToTags converts Prometheus labels to tags the same way the
+// coordinator does for Prometheus remote write, so that imported series
+// share IDs with series written through the coordinator.
+func labelsToTags(lbls labels.Labels, opts models.TagOptions) models.Tags {
+	promLabels := make([]*prompb.Label, 0, len(lbls))
+	for _, l := range lbls {
+		promLabels = append(promLabels, &prompb.Label{
+			Name:  []byte(l.Name),
+			Value: []byte(l.Value),
+		})
+	}
+	return storage.PromLabelsToM3Tags(promLabels, opts)
+}
+
+func tagsToIdentTags(tags models.Tags) ident.Tags {
+	identTags := make([]ident.Tag, 0, tags.Len())
+	for _, t := range tags.Tags {
+		identTags = append(identTags, ident.Tag{
+			Name:  ident.BytesID(t.Name),
+			Value: ident.BytesID(t.Value),
+		})
+	}
+	return ident.NewTags(identTags...)
+}
+
+// sortAndDedupeDatapoints sorts datapoints by timestamp, keeping the last
+// value seen for any duplicate timestamps.
+func sortAndDedupeDatapoints(dps []ts.Datapoint) []ts.Datapoint {
+	sort.SliceStable(dps, func(i, j int) bool {
+		return dps[i].Timestamp.Before(dps[j].Timestamp)
+	})
+
+	deduped := dps[:0]
+	for _, dp := range dps {
+		if n := len(deduped); n > 0 && deduped[n-1].Timestamp.Equal(dp.Timestamp) {
+			deduped[n-1] = dp
+			continue
+		}
+		deduped = append(deduped, dp)
+	}
+	return deduped
+}
+
+func timeFromMillis(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
+
+func millisFromTime(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
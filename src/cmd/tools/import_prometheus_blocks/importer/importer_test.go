@@ -0,0 +1,327 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package importer
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/tools"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/prometheus/tsdb"
+	"github.com/prometheus/tsdb/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPromSeries struct {
+	labels     labels.Labels
+	datapoints []ts.Datapoint
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions()
+	require.Equal(t, errNamespaceIDNotSet, opts.Validate())
+}
+
+func TestLabelsToTags(t *testing.T) {
+	opts := models.NewTagOptions().SetIDSchemeType(models.TypeQuoted)
+	lbls := labels.FromStrings("__name__", "http_requests_total", "le", "0.5", "job", "api")
+
+	tags := labelsToTags(lbls, opts)
+	name, ok := tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "http_requests_total", string(name))
+	bucket, ok := tags.Bucket()
+	require.True(t, ok)
+	assert.Equal(t, "0.5", string(bucket))
+
+	expected := models.NewTags(3, opts).
+		SetName([]byte("http_requests_total")).
+		SetBucket([]byte("0.5")).
+		AddTag(models.Tag{Name: []byte("job"), Value: []byte("api")})
+	assert.Equal(t, string(expected.ID()), string(tags.ID()))
+
+	identTags := tagsToIdentTags(tags)
+	require.Equal(t, 3, len(identTags.Values()))
+}
+
+func TestSortAndDedupeDatapoints(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	dps := []ts.Datapoint{
+		{Timestamp: start.Add(2 * time.Second), Value: 3},
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(time.Second), Value: 2},
+		{Timestamp: start.Add(2 * time.Second), Value: 4},
+	}
+
+	assert.Equal(t, []ts.Datapoint{
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(time.Second), Value: 2},
+		{Timestamp: start.Add(2 * time.Second), Value: 4},
+	}, sortAndDedupeDatapoints(dps))
+}
+
+func TestMillisRoundTrip(t *testing.T) {
+	now := time.Unix(1500000000, int64(123*time.Millisecond))
+	assert.True(t, now.Equal(timeFromMillis(millisFromTime(now))))
+}
+
+func TestImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Unix(1500000000, 0).Truncate(blockSize)
+		nsID       = ident.StringID("metrics")
+		fsOpts     = fs.NewOptions().SetFilePathPrefix(path.Join(dir, "m3db"))
+		series     = []testPromSeries{
+			{
+				labels: labels.FromStrings("__name__", "up", "job", "api"),
+				datapoints: []ts.Datapoint{
+					{Timestamp: blockStart, Value: 1},
+					{Timestamp: blockStart.Add(time.Minute), Value: 0},
+				},
+			},
+			{
+				// Spans two blocks.
+				labels: labels.FromStrings("__name__", "requests", "job", "db"),
+				datapoints: []ts.Datapoint{
+					{Timestamp: blockStart.Add(time.Hour), Value: 5},
+					{Timestamp: blockStart.Add(blockSize + time.Minute), Value: 7.5},
+				},
+			},
+		}
+	)
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1}, shard.Available),
+		sharding.DefaultHashFn(2))
+	require.NoError(t, err)
+
+	opts := NewOptions().
+		SetNamespaceID(nsID).
+		SetShardSet(shardSet).
+		SetBlockSize(blockSize).
+		SetIndexBlockSize(blockSize).
+		SetFilesystemOptions(fsOpts)
+	imp, err := NewImporter(opts)
+	require.NoError(t, err)
+
+	result, err := imp.Import(writePromBlocks(t, path.Join(dir, "prometheus"), series))
+	require.NoError(t, err)
+	assert.Equal(t, Result{
+		Series:        2,
+		Datapoints:    4,
+		DataFileSets:  4,
+		IndexFileSets: 2,
+	}, result)
+
+	var (
+		expectedData = make(map[string][]ts.Datapoint)
+		expectedDocs = make(map[time.Time][]doc.Document)
+	)
+	for _, s := range series {
+		tags := labelsToTags(s.labels, opts.TagOptions())
+		expectedData[string(tags.ID())] = s.datapoints
+
+		d, err := convert.FromMetric(ident.BytesID(tags.ID()), tagsToIdentTags(tags))
+		require.NoError(t, err)
+		for _, dp := range s.datapoints {
+			start := dp.Timestamp.Truncate(blockSize)
+			docs := expectedDocs[start]
+			if n := len(docs); n == 0 || !docs[n-1].Equal(d) {
+				expectedDocs[start] = append(docs, d)
+			}
+		}
+	}
+
+	data := make(map[string][]ts.Datapoint)
+	for _, start := range []time.Time{blockStart, blockStart.Add(blockSize)} {
+		for _, shard := range shardSet.AllIDs() {
+			readFileSet(t, fsOpts, nsID, shard, start, data)
+		}
+	}
+	assert.Equal(t, expectedData, data)
+
+	for start, expected := range expectedDocs {
+		docs := readIndexDocs(t, fsOpts, nsID, start)
+		sortDocs(expected)
+		sortDocs(docs)
+		require.Equal(t, len(expected), len(docs), start.String())
+		for i := range expected {
+			assert.True(t, expected[i].Equal(docs[i]),
+				"expected %v, got %v", expected[i], docs[i])
+		}
+	}
+}
+
+func sortDocs(docs []doc.Document) {
+	sort.Slice(docs, func(i, j int) bool {
+		return bytes.Compare(docs[i].ID, docs[j].ID) < 0
+	})
+}
+
+// writePromBlocks writes the series to a Prometheus database and returns
+// the directories of the blocks in a snapshot of it.
+func writePromBlocks(t *testing.T, dir string, series []testPromSeries) []string {
+	db, err := tsdb.Open(path.Join(dir, "db"), nil, nil, tsdb.DefaultOptions)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	db.DisableCompactions()
+
+	app := db.Appender()
+	for _, s := range series {
+		for _, dp := range s.datapoints {
+			_, err := app.Add(s.labels, millisFromTime(dp.Timestamp), dp.Value)
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, app.Commit())
+
+	snapshotDir := path.Join(dir, "snapshot")
+	require.NoError(t, db.Snapshot(snapshotDir, true))
+
+	infos, err := ioutil.ReadDir(snapshotDir)
+	require.NoError(t, err)
+	blockDirs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() {
+			blockDirs = append(blockDirs, path.Join(snapshotDir, info.Name()))
+		}
+	}
+	require.NotEmpty(t, blockDirs)
+	return blockDirs
+}
+
+func readFileSet(
+	t *testing.T,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	data map[string][]ts.Datapoint,
+) {
+	bytesPool := tools.NewCheckedBytesPool()
+	bytesPool.Init()
+
+	reader, err := fs.NewReader(bytesPool, fsOpts)
+	require.NoError(t, err)
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	for {
+		id, tags, segment, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		tags.Close()
+
+		iter := m3tsz.NewReaderIterator(bytes.NewReader(segment.Bytes()),
+			m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			data[id.String()] = append(data[id.String()], dp)
+		}
+		require.NoError(t, iter.Err())
+		iter.Close()
+	}
+}
+
+func readIndexDocs(
+	t *testing.T,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	blockStart time.Time,
+) []doc.Document {
+	segments, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+		ReaderOptions: fs.IndexReaderOpenOptions{
+			Identifier: fs.FileSetFileIdentifier{
+				FileSetContentType: persist.FileSetIndexContentType,
+				Namespace:          nsID,
+				BlockStart:         blockStart,
+			},
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: fsOpts,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(segments))
+	defer segments[0].Close()
+
+	reader, err := segments[0].Reader()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	iter, err := reader.AllDocs()
+	require.NoError(t, err)
+	defer iter.Close()
+
+	// NB: Copy the documents since they reference the segment's bytes,
+	// which are released when the segment is closed.
+	var docs []doc.Document
+	for iter.Next() {
+		d := iter.Current()
+		fields := make([]doc.Field, 0, len(d.Fields))
+		for _, f := range d.Fields {
+			fields = append(fields, doc.Field{
+				Name:  append([]byte(nil), f.Name...),
+				Value: append([]byte(nil), f.Value...),
+			})
+		}
+		docs = append(docs, doc.Document{
+			ID:     append([]byte(nil), d.ID...),
+			Fields: fields,
+		})
+	}
+	require.NoError(t, iter.Err())
+	return docs
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package importer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultBlockSize      = 2 * time.Hour
+	defaultIndexBlockSize = 2 * time.Hour
+)
+
+var (
+	errNamespaceIDNotSet     = errors.New("namespace ID not set")
+	errShardSetNotSet        = errors.New("shard set not set")
+	errBlockSizeInvalid      = errors.New("block size must be positive")
+	errIndexBlockSizeInvalid = errors.New("index block size must be positive")
+	errTagOptionsNotSet      = errors.New("tag options not set")
+)
+
+type options struct {
+	namespaceID    ident.ID
+	shardSet       sharding.ShardSet
+	blockSize      time.Duration
+	indexBlockSize time.Duration
+	tagOptions     models.TagOptions
+	encodingOpts   encoding.Options
+	fsOpts         fs.Options
+	iOpts          instrument.Options
+}
+
+// NewOptions returns a new set of import options.
+func NewOptions() Options {
+	return &options{
+		blockSize:      defaultBlockSize,
+		indexBlockSize: defaultIndexBlockSize,
+		tagOptions:     models.NewTagOptions(),
+		encodingOpts:   encoding.NewOptions(),
+		fsOpts:         fs.NewOptions(),
+		iOpts:          instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.namespaceID == nil {
+		return errNamespaceIDNotSet
+	}
+	if o.shardSet == nil {
+		return errShardSetNotSet
+	}
+	if o.blockSize <= 0 {
+		return errBlockSizeInvalid
+	}
+	if o.indexBlockSize <= 0 {
+		return errIndexBlockSizeInvalid
+	}
+	if o.tagOptions == nil {
+		return errTagOptionsNotSet
+	}
+	if err := o.tagOptions.Validate(); err != nil {
+		return err
+	}
+	return o.fsOpts.Validate()
+}
+
+func (o *options) SetNamespaceID(value ident.ID) Options {
+	opts := *o
+	opts.namespaceID = value
+	return &opts
+}
+
+func (o *options) NamespaceID() ident.ID {
+	return o.namespaceID
+}
+
+func (o *options) SetShardSet(value sharding.ShardSet) Options {
+	opts := *o
+	opts.shardSet = value
+	return &opts
+}
+
+func (o *options) ShardSet() sharding.ShardSet {
+	return o.shardSet
+}
+
+func (o *options) SetBlockSize(value time.Duration) Options {
+	opts := *o
+	opts.blockSize = value
+	return &opts
+}
+
+func (o *options) BlockSize() time.Duration {
+	return o.blockSize
+}
+
+func (o *options) SetIndexBlockSize(value time.Duration) Options {
+	opts := *o
+	opts.indexBlockSize = value
+	return &opts
+}
+
+func (o *options) IndexBlockSize() time.Duration {
+	return o.indexBlockSize
+}
+
+func (o *options) SetTagOptions(value models.TagOptions) Options {
+	opts := *o
+	opts.tagOptions = value
+	return &opts
+}
+
+func (o *options) TagOptions() models.TagOptions {
+	return o.tagOptions
+}
+
+func (o *options) SetEncodingOptions(value encoding.Options) Options {
+	opts := *o
+	opts.encodingOpts = value
+	return &opts
+}
+
+func (o *options) EncodingOptions() encoding.Options {
+	return o.encodingOpts
+}
+
+func (o *options) SetFilesystemOptions(value fs.Options) Options {
+	opts := *o
+	opts.fsOpts = value
+	return &opts
+}
+
+func (o *options) FilesystemOptions() fs.Options {
+	return o.fsOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.iOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package importer
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Importer imports Prometheus TSDB blocks into m3db filesets.
+type Importer interface {
+	// Import reads the given Prometheus block directories and writes their
+	// contents out as data filesets and index filesets.
+	Import(blockDirs []string) (Result, error)
+}
+
+// Result describes the outcome of an import.
+type Result struct {
+	Series        int
+	Datapoints    int
+	DataFileSets  int
+	IndexFileSets int
+}
+
+// Options represents the knobs available while importing.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetNamespaceID sets the namespace to write filesets for.
+	SetNamespaceID(value ident.ID) Options
+
+	// NamespaceID returns the namespace to write filesets for.
+	NamespaceID() ident.ID
+
+	// SetShardSet sets the shard set used to assign series to shards.
+	SetShardSet(value sharding.ShardSet) Options
+
+	// ShardSet returns the shard set used to assign series to shards.
+	ShardSet() sharding.ShardSet
+
+	// SetBlockSize sets the data block size of the namespace.
+	SetBlockSize(value time.Duration) Options
+
+	// BlockSize returns the data block size of the namespace.
+	BlockSize() time.Duration
+
+	// SetIndexBlockSize sets the index block size of the namespace.
+	SetIndexBlockSize(value time.Duration) Options
+
+	// IndexBlockSize returns the index block size of the namespace.
+	IndexBlockSize() time.Duration
+
+	// SetTagOptions sets the tag options used to generate series IDs.
+	SetTagOptions(value models.TagOptions) Options
+
+	// TagOptions returns the tag options used to generate series IDs.
+	TagOptions() models.TagOptions
+
+	// SetEncodingOptions sets the encoding options.
+	SetEncodingOptions(value encoding.Options) Options
+
+	// EncodingOptions returns the encoding options.
+	EncodingOptions() encoding.Options
+
+	// SetFilesystemOptions sets the filesystem options, including the file
+	// path prefix filesets are written under.
+	SetFilesystemOptions(value fs.Options) Options
+
+	// FilesystemOptions returns the filesystem options.
+	FilesystemOptions() fs.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/tools/import_prometheus_blocks/importer"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/pborman/getopt"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		optPathPrefix     = getopt.StringLong("path-prefix", 'p', "", "Path prefix [e.g. /var/lib/m3db]")
+		optNamespace      = getopt.StringLong("namespace", 'n', "", "Namespace [e.g. metrics]")
+		optNumShards      = getopt.Uint32Long("num-shards", 's', 0, "Number of shards of the namespace [expected format uint32]")
+		optBlockSize      = getopt.DurationLong("block-size", 'b', 2*time.Hour, "Namespace block size [e.g. 2h]")
+		optIndexBlockSize = getopt.DurationLong("index-block-size", 'i', 2*time.Hour, "Namespace index block size [e.g. 2h]")
+		optIDScheme       = getopt.StringLong("id-scheme", 'd', models.TypeQuoted.String(),
+			fmt.Sprintf("Coordinator tag ID scheme [%s|%s|%s]",
+				models.TypeLegacy, models.TypeQuoted, models.TypePrependMeta))
+	)
+	getopt.SetParameters("<prometheus block dir> [<prometheus block dir> ...]")
+	getopt.Parse()
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	blockDirs := getopt.Args()
+	if *optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optNumShards == 0 ||
+		len(blockDirs) == 0 {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	idScheme, err := parseIDScheme(*optIDScheme)
+	if err != nil {
+		logger.Fatalf("invalid id scheme: %v", err)
+	}
+
+	shardIDs := make([]uint32, 0, *optNumShards)
+	for i := uint32(0); i < *optNumShards; i++ {
+		shardIDs = append(shardIDs, i)
+	}
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards(shardIDs, shard.Available),
+		sharding.DefaultHashFn(int(*optNumShards)))
+	if err != nil {
+		logger.Fatalf("unable to create shard set: %v", err)
+	}
+
+	opts := importer.NewOptions().
+		SetNamespaceID(ident.StringID(*optNamespace)).
+		SetShardSet(shardSet).
+		SetBlockSize(*optBlockSize).
+		SetIndexBlockSize(*optIndexBlockSize).
+		SetTagOptions(models.NewTagOptions().SetIDSchemeType(idScheme)).
+		SetFilesystemOptions(fs.NewOptions().SetFilePathPrefix(*optPathPrefix)).
+		SetInstrumentOptions(instrument.NewOptions().SetLogger(rawLogger))
+
+	imp, err := importer.NewImporter(opts)
+	if err != nil {
+		logger.Fatalf("unable to create importer: %v", err)
+	}
+
+	result, err := imp.Import(blockDirs)
+	if err != nil {
+		logger.Fatalf("unable to import blocks: %v", err)
+	}
+
+	logger.Infof("successfully imported %d series, %d datapoints, %d data filesets, %d index filesets",
+		result.Series, result.Datapoints, result.DataFileSets, result.IndexFileSets)
+}
+
+func parseIDScheme(str string) (models.IDSchemeType, error) {
+	for _, scheme := range []models.IDSchemeType{
+		models.TypeLegacy,
+		models.TypeQuoted,
+		models.TypePrependMeta,
+	} {
+		if str == scheme.String() {
+			return scheme, nil
+		}
+	}
+	return models.TypeDefault, fmt.Errorf("unknown id scheme: %s", str)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// sync_rules is a tool for syncing a namespace's ruleset with a YAML ruleset
+// definition through the r2 API.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/rules/view"
+	"github.com/m3db/m3/src/metrics/rules/view/changes"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type headers []string
+
+func (h *headers) String() string { return strings.Join(*h, ",") }
+
+func (h *headers) Set(value string) error {
+	if !strings.Contains(value, ":") {
+		return fmt.Errorf("header %s is not of the form name:value", value)
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+type ruleSetDiff struct {
+	RuleSetChanges changes.RuleSetChanges `json:"rulesetChanges"`
+	RuleSetVersion int                    `json:"rulesetVersion"`
+}
+
+type apiResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type client struct {
+	endpoint string
+	headers  headers
+	client   *http.Client
+}
+
+func (c client) post(path, contentType string, body []byte, result interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for _, h := range c.headers {
+		parts := strings.SplitN(h, ":", 2)
+		req.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiResp apiResponse
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err := json.Unmarshal(data, &apiResp); err != nil || apiResp.Message == "" {
+			apiResp.Message = strings.TrimSpace(string(data))
+		}
+		return resp.StatusCode, fmt.Errorf("%s: %s", resp.Status, apiResp.Message)
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(result)
+}
+
+func printChanges(diff ruleSetDiff, desired view.RuleSet) {
+	var (
+		tombstonedMappingRules = make(map[string]struct{})
+		tombstonedRollupRules  = make(map[string]struct{})
+	)
+	for _, rule := range desired.MappingRules {
+		if rule.Tombstoned {
+			tombstonedMappingRules[rule.Name] = struct{}{}
+		}
+	}
+	for _, rule := range desired.RollupRules {
+		if rule.Tombstoned {
+			tombstonedRollupRules[rule.Name] = struct{}{}
+		}
+	}
+
+	for _, change := range diff.RuleSetChanges.MappingRuleChanges {
+		_, tombstoned := tombstonedMappingRules[change.RuleData.Name]
+		printChange("mapping", change.Op, tombstoned, change.RuleID, change.RuleData.Name)
+	}
+	for _, change := range diff.RuleSetChanges.RollupRuleChanges {
+		_, tombstoned := tombstonedRollupRules[change.RuleData.Name]
+		printChange("rollup", change.Op, tombstoned, change.RuleID, change.RuleData.Name)
+	}
+}
+
+func printChange(kind string, op changes.Op, tombstoned bool, ruleID *string, name string) {
+	var action string
+	switch op {
+	case changes.AddOp:
+		action = "add"
+	case changes.ChangeOp:
+		action = "update"
+	case changes.DeleteOp:
+		action = "delete"
+		if tombstoned {
+			action = "tombstone"
+		}
+	default:
+		action = string(op)
+	}
+	if ruleID == nil {
+		fmt.Printf("%-9s %s rule %s\n", action, kind, name)
+		return
+	}
+	fmt.Printf("%-9s %s rule %s (%s)\n", action, kind, name, *ruleID)
+}
+
+func main() {
+	var (
+		endpoint  = flag.String("endpoint", "http://localhost:9000/r2/v1", "r2 API endpoint")
+		file      = flag.String("file", "", "YAML file containing the full desired ruleset")
+		namespace = flag.String("namespace", "", "Namespace to sync, defaults to the id in the ruleset file")
+		apply     = flag.Bool("apply", false, "Apply the changes instead of only printing them")
+		timeout   = flag.Duration("timeout", 30*time.Second, "Timeout for each r2 API request")
+		reqHeader headers
+	)
+	flag.Var(&reqHeader, "header", "Header to set on r2 API requests in the form name:value, may be repeated")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("unable to read ruleset file: %v", err)
+	}
+	var desired view.RuleSet
+	if err := yaml.UnmarshalStrict(data, &desired); err != nil {
+		log.Fatalf("unable to parse ruleset file: %v", err)
+	}
+	if *namespace == "" {
+		*namespace = desired.Namespace
+	}
+	if *namespace == "" {
+		log.Fatalf("no namespace specified in the flags or the ruleset file")
+	}
+
+	c := client{
+		endpoint: strings.TrimSuffix(*endpoint, "/"),
+		headers:  reqHeader,
+		client:   &http.Client{Timeout: *timeout},
+	}
+	rulesetPath := fmt.Sprintf("/namespaces/%s/ruleset", *namespace)
+
+	var diff ruleSetDiff
+	if _, err := c.post(rulesetPath+"/diff", "application/x-yaml", data, &diff); err != nil {
+		log.Fatalf("unable to diff ruleset: %v", err)
+	}
+	if len(diff.RuleSetChanges.MappingRuleChanges) == 0 &&
+		len(diff.RuleSetChanges.RollupRuleChanges) == 0 {
+		fmt.Printf("ruleset for namespace %s at version %d is up to date\n", *namespace, diff.RuleSetVersion)
+		return
+	}
+
+	fmt.Printf("changes to ruleset for namespace %s at version %d:\n", *namespace, diff.RuleSetVersion)
+	printChanges(diff, desired)
+	if !*apply {
+		fmt.Println("run with -apply to apply these changes")
+		return
+	}
+
+	body, err := json.Marshal(diff)
+	if err != nil {
+		log.Fatalf("unable to encode ruleset changes: %v", err)
+	}
+	var updated view.RuleSet
+	status, err := c.post(rulesetPath+"/update", "application/json", body, &updated)
+	if status == http.StatusConflict {
+		log.Fatalf("ruleset was modified since version %d, rerun to recompute the changes: %v",
+			diff.RuleSetVersion, err)
+	}
+	if err != nil {
+		log.Fatalf("unable to update ruleset: %v", err)
+	}
+	fmt.Printf("applied changes, ruleset for namespace %s is now at version %d\n", *namespace, updated.Version)
+}
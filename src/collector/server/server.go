@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/collector/api/v1/httpd"
 	"github.com/m3db/m3/src/collector/reporter"
 	"github.com/m3db/m3/src/collector/reporter/m3aggregator"
+	"github.com/m3db/m3/src/collector/statsd"
 	xconfig "github.com/m3db/m3/src/x/config"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
@@ -150,6 +151,23 @@ func Run(runOpts RunOptions) {
 		}
 	}()
 
+	if cfg.StatsD != nil {
+		logger.Info("creating statsd server")
+		statsdServer, err := statsd.NewServer(*cfg.StatsD, reporter,
+			tagEncoderPool, tagDecoderPool, instrumentOpts)
+		if err != nil {
+			logger.Fatal("unable to create statsd server", zap.Error(err))
+		}
+
+		logger.Info("starting statsd server",
+			zap.String("udpAddress", cfg.StatsD.UDPListenAddress),
+			zap.String("tcpAddress", cfg.StatsD.TCPListenAddress))
+		if err := statsdServer.ListenAndServe(); err != nil {
+			logger.Fatal("unable to start statsd server", zap.Error(err))
+		}
+		defer statsdServer.Close()
+	}
+
 	var interruptCh <-chan error = make(chan error)
 	if runOpts.InterruptCh != nil {
 		interruptCh = runOpts.InterruptCh
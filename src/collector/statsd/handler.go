@@ -0,0 +1,298 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/collector/reporter"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var (
+	errEncoderNoBytes = errors.New("tags encoder has no access to bytes")
+	errMalformedValue = errors.New("malformed value")
+)
+
+type handlerMetrics struct {
+	success      tally.Counter
+	malformed    tally.Counter
+	skipped      tally.Counter
+	reportErrors tally.Counter
+}
+
+func newHandlerMetrics(scope tally.Scope) handlerMetrics {
+	return handlerMetrics{
+		success:      scope.Counter("success"),
+		malformed:    scope.Counter("malformed"),
+		skipped:      scope.Counter("skipped"),
+		reportErrors: scope.Counter("report-errors"),
+	}
+}
+
+// handler parses StatsD lines and reports them as untimed metrics, the
+// reporter matches each metric against the mapping and rollup rules before
+// sending it to the aggregator. Counters, gauges and timers map to the
+// aggregator metric type of the same kind, sets are reported as a counter
+// incremented once for each distinct value seen in the set window so that
+// summing the counter over the window yields the set cardinality.
+type handler struct {
+	reporter    reporter.Reporter
+	encoderPool serialize.TagEncoderPool
+	decoderPool serialize.TagDecoderPool
+	tagOptions  models.TagOptions
+	sets        *setFilter
+	logger      *zap.Logger
+	metrics     handlerMetrics
+}
+
+func newHandler(
+	reporter reporter.Reporter,
+	encoderPool serialize.TagEncoderPool,
+	decoderPool serialize.TagDecoderPool,
+	setWindow time.Duration,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) *handler {
+	return &handler{
+		reporter:    reporter,
+		encoderPool: encoderPool,
+		decoderPool: decoderPool,
+		tagOptions:  models.NewTagOptions(),
+		sets:        newSetFilter(setWindow, nowFn),
+		logger:      instrumentOpts.Logger(),
+		metrics:     newHandlerMetrics(instrumentOpts.MetricsScope()),
+	}
+}
+
+// Handle handles a TCP connection with newline delimited lines.
+func (h *handler) Handle(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		h.handleLine(scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		h.logger.Error("error reading statsd connection", zap.Error(err))
+	}
+
+	// Don't close the connection, that is the server's responsibility.
+}
+
+// Close closes the handler.
+func (h *handler) Close() {}
+
+// handlePacket handles a UDP packet with one or more newline delimited
+// lines.
+func (h *handler) handlePacket(packet []byte) {
+	for len(packet) > 0 {
+		var line []byte
+		if idx := bytes.IndexByte(packet, '\n'); idx >= 0 {
+			line, packet = packet[:idx], packet[idx+1:]
+		} else {
+			line, packet = packet, nil
+		}
+		h.handleLine(line)
+	}
+}
+
+func (h *handler) handleLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	s, err := parseLine(line)
+	if err == errSkipLine {
+		h.metrics.skipped.Inc(1)
+		return
+	}
+	if err != nil {
+		h.metrics.malformed.Inc(1)
+		h.logger.Debug("malformed statsd line",
+			zap.ByteString("line", line), zap.Error(err))
+		return
+	}
+
+	if err := h.report(s); err != nil {
+		if err == errMalformedValue {
+			h.metrics.malformed.Inc(1)
+			return
+		}
+		h.metrics.reportErrors.Inc(1)
+		h.logger.Error("unable to report statsd metric",
+			zap.ByteString("name", s.name), zap.Error(err))
+		return
+	}
+	h.metrics.success.Inc(1)
+}
+
+func (h *handler) report(s sample) error {
+	encoded, err := h.encodeID(s)
+	if err != nil {
+		return err
+	}
+
+	switch s.metricType {
+	case counterType:
+		var total float64
+		for _, b := range s.values {
+			value, err := parseValue(b)
+			if err != nil {
+				return errMalformedValue
+			}
+			total += value / s.sampleRate
+		}
+		return h.reporter.ReportCounter(h.newID(encoded), int64(math.Round(total)))
+
+	case gaugeType:
+		values := make([]float64, 0, len(s.values))
+		for _, b := range s.values {
+			value, err := parseValue(b)
+			if err != nil {
+				return errMalformedValue
+			}
+			values = append(values, value)
+		}
+		for _, value := range values {
+			if err := h.reporter.ReportGauge(h.newID(encoded), value); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case timerType:
+		// The sample rate cannot be applied to timer values and is ignored,
+		// the aggregator computes quantiles from the values received.
+		values := make([]float64, 0, len(s.values))
+		for _, b := range s.values {
+			value, err := parseValue(b)
+			if err != nil {
+				return errMalformedValue
+			}
+			values = append(values, value)
+		}
+		return h.reporter.ReportBatchTimer(h.newID(encoded), values)
+
+	case setType:
+		var distinct int64
+		for _, value := range s.values {
+			if h.sets.add(encoded, value) {
+				distinct++
+			}
+		}
+		if distinct == 0 {
+			return nil
+		}
+		return h.reporter.ReportCounter(h.newID(encoded), distinct)
+	}
+
+	return nil
+}
+
+// encodeID returns the encoded tags of the sample's metric ID, the metric
+// name is stored in the name tag.
+func (h *handler) encodeID(s sample) ([]byte, error) {
+	tags := models.NewTags(len(s.tags)+1, h.tagOptions)
+	for _, t := range s.tags {
+		tags = tags.AddOrUpdateTag(models.Tag{Name: t.name, Value: t.value})
+	}
+	tags = tags.SetName(s.name)
+	tagsIter := storage.TagsToIdentTagIterator(tags)
+
+	encoder := h.encoderPool.Get()
+	encoder.Reset()
+	defer encoder.Finalize()
+
+	if err := encoder.Encode(tagsIter); err != nil {
+		return nil, err
+	}
+
+	data, ok := encoder.Data()
+	if !ok {
+		return nil, errEncoderNoBytes
+	}
+
+	// Take a copy of the pooled encoder's bytes.
+	return append([]byte(nil), data.Bytes()...), nil
+}
+
+func (h *handler) newID(encoded []byte) id.ID {
+	metricTagsIter := serialize.NewMetricTagsIterator(h.decoderPool.Get(), nil)
+	metricTagsIter.Reset(encoded)
+	return metricTagsIter
+}
+
+// setFilter tracks the distinct values of each set within a window, the
+// values are forgotten once the window elapses.
+type setFilter struct {
+	sync.Mutex
+
+	window      time.Duration
+	nowFn       clock.NowFn
+	windowStart time.Time
+	seen        map[string]struct{}
+}
+
+func newSetFilter(window time.Duration, nowFn clock.NowFn) *setFilter {
+	return &setFilter{
+		window:      window,
+		nowFn:       nowFn,
+		windowStart: nowFn(),
+		seen:        make(map[string]struct{}),
+	}
+}
+
+// add returns true if the value has not been seen for the set with the
+// given ID in the current window.
+func (f *setFilter) add(setID []byte, value []byte) bool {
+	key := make([]byte, 0, len(setID)+len(value)+1)
+	key = append(key, setID...)
+	key = append(key, 0)
+	key = append(key, value...)
+
+	f.Lock()
+	defer f.Unlock()
+
+	if now := f.nowFn(); now.Sub(f.windowStart) >= f.window {
+		f.windowStart = now
+		f.seen = make(map[string]struct{}, len(f.seen))
+	}
+
+	if _, ok := f.seen[string(key)]; ok {
+		return false
+	}
+	f.seen[string(key)] = struct{}{}
+	return true
+}
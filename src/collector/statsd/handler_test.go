@@ -0,0 +1,142 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/collector/reporter"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTestHandler(
+	ctrl *gomock.Controller,
+	nowFn func() time.Time,
+) (*handler, *reporter.MockReporter, tally.TestScope) {
+	reporter := reporter.NewMockReporter(ctrl)
+	poolOpts := pool.NewObjectPoolOptions().SetSize(1)
+	tagEncoderPool := serialize.NewTagEncoderPool(
+		serialize.NewTagEncoderOptions(), poolOpts)
+	tagEncoderPool.Init()
+	tagDecoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(), poolOpts)
+	tagDecoderPool.Init()
+	scope := tally.NewTestScope("", nil)
+	instrumentOpts := instrument.NewOptions().SetMetricsScope(scope)
+
+	handler := newHandler(reporter, tagEncoderPool, tagDecoderPool,
+		10*time.Second, nowFn, instrumentOpts)
+	return handler, reporter, scope
+}
+
+func assertTag(t *testing.T, id id.ID, name, expected string) {
+	value, ok := id.TagValue([]byte(name))
+	require.True(t, ok, name)
+	assert.Equal(t, expected, string(value))
+}
+
+func TestHandlerReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, reporter, scope := newTestHandler(ctrl, time.Now)
+
+	gomock.InOrder(
+		reporter.EXPECT().
+			ReportCounter(gomock.Any(), int64(4)).
+			DoAndReturn(func(id id.ID, value int64) error {
+				assertTag(t, id, "__name__", "requests")
+				assertTag(t, id, "env", "prod")
+				return nil
+			}),
+		reporter.EXPECT().
+			ReportGauge(gomock.Any(), 42.5).
+			DoAndReturn(func(id id.ID, value float64) error {
+				assertTag(t, id, "__name__", "queue.size")
+				return nil
+			}),
+		reporter.EXPECT().
+			ReportBatchTimer(gomock.Any(), []float64{1, 2.5}).
+			DoAndReturn(func(id id.ID, values []float64) error {
+				assertTag(t, id, "__name__", "latency")
+				return nil
+			}),
+	)
+
+	handler.handlePacket([]byte("requests:2|c|@0.5|#env:prod\n" +
+		"queue.size:42.5|g\r\n" +
+		"latency:1:2.5|ms\n" +
+		"malformed\n" +
+		"_e{5,4}:title|text\n"))
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(3), counters["success+"].Value())
+	assert.Equal(t, int64(1), counters["malformed+"].Value())
+	assert.Equal(t, int64(1), counters["skipped+"].Value())
+}
+
+func TestHandlerReportSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1500000000, 0)
+	handler, reporter, _ := newTestHandler(ctrl, func() time.Time {
+		return now
+	})
+
+	gomock.InOrder(
+		reporter.EXPECT().ReportCounter(gomock.Any(), int64(2)).Return(nil),
+		reporter.EXPECT().ReportCounter(gomock.Any(), int64(1)).Return(nil),
+		reporter.EXPECT().ReportCounter(gomock.Any(), int64(1)).Return(nil),
+	)
+
+	handler.handlePacket([]byte("users:alice:bob|s\nusers:alice|s\nusers:carol|s"))
+
+	// Values are counted again once the set window elapses.
+	now = now.Add(10 * time.Second)
+	handler.handlePacket([]byte("users:alice|s"))
+}
+
+func TestHandlerReportError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, reporter, scope := newTestHandler(ctrl, time.Now)
+	reporter.EXPECT().
+		ReportCounter(gomock.Any(), int64(1)).
+		Return(errors.New("an error"))
+
+	handler.handlePacket([]byte("requests:1|c\nrequests:x|c"))
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["report-errors+"].Value())
+	assert.Equal(t, int64(1), counters["malformed+"].Value())
+}
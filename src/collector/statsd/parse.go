@@ -0,0 +1,177 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type metricType int
+
+const (
+	counterType metricType = iota
+	gaugeType
+	timerType
+	setType
+)
+
+var (
+	errEmptyName         = errors.New("metric has no name")
+	errNoValue           = errors.New("metric has no value")
+	errNoType            = errors.New("metric has no type")
+	errInvalidSampleRate = errors.New("sample rate must be in (0, 1]")
+	errGaugeDelta        = errors.New("signed gauge deltas are not supported")
+
+	// errSkipLine is returned for DogStatsD events and service checks which
+	// are valid but have no metric equivalent.
+	errSkipLine = errors.New("line is not a metric")
+
+	eventPrefix        = []byte("_e{")
+	serviceCheckPrefix = []byte("_sc|")
+)
+
+// tag is a DogStatsD tag.
+type tag struct {
+	name  []byte
+	value []byte
+}
+
+// sample is a single parsed StatsD line, DogStatsD allows several values of
+// the same type to be packed into one line.
+type sample struct {
+	name       []byte
+	metricType metricType
+	values     [][]byte
+	sampleRate float64
+	tags       []tag
+}
+
+// parseLine parses a StatsD line of the form
+// <name>:<value>[:<value>...]|<type>[|@<sample rate>][|#<tag>:<value>,...]
+// where the multiple values and tags are DogStatsD extensions. The returned sample references the line so it is only valid until the
+// line is reused.
+func parseLine(line []byte) (sample, error) {
+	if bytes.HasPrefix(line, eventPrefix) ||
+		bytes.HasPrefix(line, serviceCheckPrefix) {
+		return sample{}, errSkipLine
+	}
+
+	nameEnd := bytes.IndexByte(line, ':')
+	if nameEnd <= 0 {
+		return sample{}, errEmptyName
+	}
+
+	s := sample{
+		name:       line[:nameEnd],
+		sampleRate: 1,
+	}
+
+	sections := bytes.Split(line[nameEnd+1:], []byte{'|'})
+	if len(sections) < 2 {
+		return sample{}, errNoType
+	}
+	if len(sections[0]) == 0 {
+		return sample{}, errNoValue
+	}
+	s.values = bytes.Split(sections[0], []byte{':'})
+
+	switch string(sections[1]) {
+	case "c":
+		s.metricType = counterType
+	case "g":
+		s.metricType = gaugeType
+	case "ms", "h", "d":
+		s.metricType = timerType
+	case "s":
+		s.metricType = setType
+	case "":
+		return sample{}, errNoType
+	default:
+		return sample{}, fmt.Errorf("unknown metric type: %s", sections[1])
+	}
+
+	for _, section := range sections[2:] {
+		if len(section) == 0 {
+			continue
+		}
+		switch section[0] {
+		case '@':
+			rate, err := strconv.ParseFloat(string(section[1:]), 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return sample{}, errInvalidSampleRate
+			}
+			s.sampleRate = rate
+		case '#':
+			s.tags = parseTags(section[1:], s.tags)
+		default:
+			// Ignore other DogStatsD extensions such as container IDs and
+			// client side timestamps.
+		}
+	}
+
+	if s.metricType == gaugeType {
+		for _, value := range s.values {
+			if len(value) > 0 && (value[0] == '+' || value[0] == '-') {
+				// Deltas need the current value of the gauge which is only
+				// known once aggregated.
+				return sample{}, errGaugeDelta
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// parseTags parses comma separated DogStatsD tags, tags without a value
+// have no tag equivalent and are skipped.
+func parseTags(b []byte, tags []tag) []tag {
+	for len(b) > 0 {
+		var t []byte
+		if idx := bytes.IndexByte(b, ','); idx >= 0 {
+			t, b = b[:idx], b[idx+1:]
+		} else {
+			t, b = b, nil
+		}
+
+		idx := bytes.IndexByte(t, ':')
+		if idx <= 0 || idx == len(t)-1 {
+			continue
+		}
+		tags = append(tags, tag{name: t[:idx], value: t[idx+1:]})
+	}
+	return tags
+}
+
+// parseValue parses a counter, gauge or timer value.
+func parseValue(b []byte) (float64, error) {
+	value, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid value: %s", b)
+	}
+	return value, nil
+}
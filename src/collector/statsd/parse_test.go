@@ -0,0 +1,119 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected sample
+	}{
+		{
+			line: "requests:1|c",
+			expected: sample{
+				name:       []byte("requests"),
+				metricType: counterType,
+				values:     [][]byte{[]byte("1")},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "requests:2|c|@0.5|#env:prod,region:us-east,novalue",
+			expected: sample{
+				name:       []byte("requests"),
+				metricType: counterType,
+				values:     [][]byte{[]byte("2")},
+				sampleRate: 0.5,
+				tags: []tag{
+					{name: []byte("env"), value: []byte("prod")},
+					{name: []byte("region"), value: []byte("us-east")},
+				},
+			},
+		},
+		{
+			line: "queue.size:42.5|g",
+			expected: sample{
+				name:       []byte("queue.size"),
+				metricType: gaugeType,
+				values:     [][]byte{[]byte("42.5")},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "latency:1:2:3|h|#env:prod|c:abc",
+			expected: sample{
+				name:       []byte("latency"),
+				metricType: timerType,
+				values:     [][]byte{[]byte("1"), []byte("2"), []byte("3")},
+				sampleRate: 1,
+				tags:       []tag{{name: []byte("env"), value: []byte("prod")}},
+			},
+		},
+		{
+			line: "users:alice|s",
+			expected: sample{
+				name:       []byte("users"),
+				metricType: setType,
+				values:     [][]byte{[]byte("alice")},
+				sampleRate: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := parseLine([]byte(test.line))
+		require.NoError(t, err, test.line)
+		assert.Equal(t, test.expected, s, test.line)
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected error
+	}{
+		{line: ":1|c", expected: errEmptyName},
+		{line: "requests", expected: errEmptyName},
+		{line: "requests:1", expected: errNoType},
+		{line: "requests:|c", expected: errNoValue},
+		{line: "requests:1|", expected: errNoType},
+		{line: "requests:1|c|@2", expected: errInvalidSampleRate},
+		{line: "requests:1|c|@0", expected: errInvalidSampleRate},
+		{line: "queue:-1|g", expected: errGaugeDelta},
+		{line: "_e{5,4}:title|text", expected: errSkipLine},
+		{line: "_sc|check|0", expected: errSkipLine},
+	}
+
+	for _, test := range tests {
+		_, err := parseLine([]byte(test.line))
+		assert.Equal(t, test.expected, err, test.line)
+	}
+
+	_, err := parseLine([]byte("requests:1|x"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown metric type")
+}
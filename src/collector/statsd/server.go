@@ -0,0 +1,218 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"errors"
+	"net"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/collector/reporter"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/serialize"
+	xserver "github.com/m3db/m3/src/x/server"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSetWindow     = 10 * time.Second
+	defaultMaxPacketSize = 65535
+
+	minReadErrorBackoff = 5 * time.Millisecond
+	maxReadErrorBackoff = time.Second
+)
+
+var (
+	errNoListenAddress = errors.New("no statsd udp or tcp listen address set")
+)
+
+// Configuration is the configuration for the StatsD server.
+type Configuration struct {
+	// UDPListenAddress is the address to receive StatsD packets on.
+	UDPListenAddress string `yaml:"udpListenAddress"`
+
+	// TCPListenAddress is the address to accept newline delimited StatsD
+	// connections on.
+	TCPListenAddress string `yaml:"tcpListenAddress"`
+
+	// SetWindow is the window distinct set values are tracked for, it
+	// should match the resolution sets are aggregated at.
+	SetWindow time.Duration `yaml:"setWindow"`
+
+	// MaxPacketSize is the maximum size of a UDP packet, larger packets
+	// are truncated.
+	MaxPacketSize int `yaml:"maxPacketSize"`
+
+	// UDPReaders is the number of goroutines reading UDP packets, each
+	// with its own buffer, defaults to the number of CPUs.
+	UDPReaders int `yaml:"udpReaders"`
+}
+
+// Server is a StatsD server that accepts metrics over UDP and TCP.
+type Server struct {
+	sync.Mutex
+
+	cfg        Configuration
+	handler    *handler
+	logger     *zap.Logger
+	serverOpts xserver.Options
+
+	udpConn   net.PacketConn
+	tcpServer xserver.Server
+	closed    bool
+	closedCh  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a new StatsD server that reports metrics with the
+// given reporter.
+func NewServer(
+	cfg Configuration,
+	reporter reporter.Reporter,
+	encoderPool serialize.TagEncoderPool,
+	decoderPool serialize.TagDecoderPool,
+	instrumentOpts instrument.Options,
+) (*Server, error) {
+	if cfg.UDPListenAddress == "" && cfg.TCPListenAddress == "" {
+		return nil, errNoListenAddress
+	}
+	if cfg.SetWindow <= 0 {
+		cfg.SetWindow = defaultSetWindow
+	}
+	if cfg.MaxPacketSize <= 0 {
+		cfg.MaxPacketSize = defaultMaxPacketSize
+	}
+	if cfg.UDPReaders <= 0 {
+		cfg.UDPReaders = runtime.NumCPU()
+	}
+
+	instrumentOpts = instrumentOpts.SetMetricsScope(
+		instrumentOpts.MetricsScope().SubScope("statsd"))
+	return &Server{
+		cfg: cfg,
+		handler: newHandler(reporter, encoderPool, decoderPool,
+			cfg.SetWindow, clock.NewOptions().NowFn(), instrumentOpts),
+		logger:     instrumentOpts.Logger(),
+		serverOpts: xserver.NewOptions().SetInstrumentOptions(instrumentOpts),
+		closedCh:   make(chan struct{}),
+	}, nil
+}
+
+// ListenAndServe starts listening on the configured addresses and serves
+// requests in the background.
+func (s *Server) ListenAndServe() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.cfg.UDPListenAddress != "" {
+		conn, err := net.ListenPacket("udp", s.cfg.UDPListenAddress)
+		if err != nil {
+			return err
+		}
+		s.udpConn = conn
+		for i := 0; i < s.cfg.UDPReaders; i++ {
+			s.wg.Add(1)
+			go s.serveUDP(conn)
+		}
+	}
+
+	if s.cfg.TCPListenAddress != "" {
+		server := xserver.NewServer(s.cfg.TCPListenAddress, s.handler,
+			s.serverOpts)
+		if err := server.ListenAndServe(); err != nil {
+			if s.udpConn != nil {
+				s.udpConn.Close()
+			}
+			return err
+		}
+		s.tcpServer = server
+	}
+
+	return nil
+}
+
+func (s *Server) serveUDP(conn net.PacketConn) {
+	defer s.wg.Done()
+
+	var (
+		buf     = make([]byte, s.cfg.MaxPacketSize)
+		backoff time.Duration
+	)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			s.logger.Error("error reading statsd packet", zap.Error(err))
+
+			// Back off on consecutive errors so that a persistent error
+			// does not spin the reader and flood the logs.
+			if backoff == 0 {
+				backoff = minReadErrorBackoff
+			} else {
+				backoff *= 2
+			}
+			if backoff > maxReadErrorBackoff {
+				backoff = maxReadErrorBackoff
+			}
+			select {
+			case <-time.After(backoff):
+			case <-s.closedCh:
+				return
+			}
+			continue
+		}
+		backoff = 0
+		s.handler.handlePacket(buf[:n])
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.closed
+}
+
+// Close stops the server and waits for outstanding UDP packets to be
+// handled.
+func (s *Server) Close() {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.closedCh)
+	if s.udpConn != nil {
+		s.udpConn.Close()
+	}
+	if s.tcpServer != nil {
+		s.tcpServer.Close()
+	}
+	s.Unlock()
+
+	s.wg.Wait()
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package statsd
+
+import (
+	"errors"
+	"net"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/collector/reporter"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type erroringPacketConn struct {
+	net.PacketConn
+
+	reads int64
+}
+
+func (c *erroringPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	atomic.AddInt64(&c.reads, 1)
+	return 0, nil, errors.New("read error")
+}
+
+func newTestServer(t *testing.T, ctrl *gomock.Controller, cfg Configuration) *Server {
+	server, err := NewServer(cfg, reporter.NewMockReporter(ctrl), nil, nil,
+		instrument.NewOptions())
+	require.NoError(t, err)
+	return server
+}
+
+func TestServerUDPReadersDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, ctrl, Configuration{UDPListenAddress: "127.0.0.1:0"})
+	require.Equal(t, runtime.NumCPU(), server.cfg.UDPReaders)
+
+	server = newTestServer(t, ctrl, Configuration{
+		UDPListenAddress: "127.0.0.1:0",
+		UDPReaders:       3,
+	})
+	require.Equal(t, 3, server.cfg.UDPReaders)
+}
+
+func TestServerUDPReadErrorBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, ctrl, Configuration{UDPListenAddress: "127.0.0.1:0"})
+	conn := &erroringPacketConn{}
+	server.wg.Add(1)
+	go server.serveUDP(conn)
+
+	// Without a backoff the reader would retry the read in a tight loop.
+	time.Sleep(100 * time.Millisecond)
+	server.Close()
+
+	reads := atomic.LoadInt64(&conn.reads)
+	require.True(t, reads >= 2, "expected at least 2 reads, got %d", reads)
+	require.True(t, reads <= 10, "expected at most 10 reads, got %d", reads)
+}
@@ -24,21 +24,52 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"reflect"
 	"strings"
 
+	"github.com/m3db/m3/src/metrics/rules/view"
 	"github.com/m3db/m3/src/metrics/rules/view/changes"
 
 	validator "gopkg.in/go-playground/validator.v9"
+	yaml "gopkg.in/yaml.v2"
 )
 
+var yamlContentTypes = map[string]struct{}{
+	"application/x-yaml": {},
+	"application/yaml":   {},
+	"text/yaml":          {},
+}
+
 // TODO(dgromov): Make this return a list of validation errors
 func parseRequest(s interface{}, body io.ReadCloser) error {
 	if err := json.NewDecoder(body).Decode(s); err != nil {
 		return NewBadInputError(fmt.Sprintf("Malformed Json: %s", err.Error()))
 	}
+	return validateRequest(s)
+}
 
+// parseYAMLOrJSONRequest parses the request body as YAML if the request has a
+// YAML content type, and as JSON otherwise.
+func parseYAMLOrJSONRequest(s interface{}, r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if _, ok := yamlContentTypes[mediaType]; !ok {
+		return parseRequest(s, r.Body)
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return NewBadInputError(fmt.Sprintf("Unable to read request body: %s", err.Error()))
+	}
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return NewBadInputError(fmt.Sprintf("Malformed YAML: %s", err.Error()))
+	}
+	return validateRequest(s)
+}
+
+func validateRequest(s interface{}) error {
 	// Invoking the validation explictely to have control over the format of the error output.
 	validate := validator.New()
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -88,3 +119,13 @@ type updateRuleSetRequest struct {
 	RuleSetChanges changes.RuleSetChanges `json:"rulesetChanges"`
 	RuleSetVersion int                    `json:"rulesetVersion"`
 }
+
+// diffRuleSetResponse can be posted as is to the update ruleset endpoint.
+type diffRuleSetResponse updateRuleSetRequest
+
+type matchRuleSetRequest struct {
+	IDs     []string            `json:"ids"`
+	TagSets []map[string]string `json:"tagSets"`
+	// RuleSet is the proposed ruleset to match against instead of the latest one.
+	RuleSet *view.RuleSet `json:"ruleset,omitempty"`
+}
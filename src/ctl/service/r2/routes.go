@@ -25,6 +25,7 @@ import (
 	"net/http"
 
 	"github.com/m3db/m3/src/metrics/rules/view"
+	"github.com/m3db/m3/src/metrics/rules/view/changes"
 
 	"github.com/gorilla/mux"
 )
@@ -93,6 +94,61 @@ func updateRuleSet(s *service, r *http.Request) (data interface{}, err error) {
 	return s.store.UpdateRuleSet(req.RuleSetChanges, req.RuleSetVersion, uOpts)
 }
 
+func matchRuleSet(s *service, r *http.Request) (data interface{}, err error) {
+	var req matchRuleSetRequest
+	if err := parseRequest(&req, r.Body); err != nil {
+		return nil, err
+	}
+	if len(req.IDs) == 0 && len(req.TagSets) == 0 {
+		return nil, NewBadInputError(
+			"invalid request: no metric IDs or tag sets to match",
+		)
+	}
+
+	namespaceID := mux.Vars(r)[namespaceIDVar]
+	if req.RuleSet != nil && req.RuleSet.Namespace != namespaceID {
+		return nil, NewBadInputError(fmt.Sprintf(
+			"namespaceID param %s and ruleset namespaceID %s do not match",
+			namespaceID,
+			req.RuleSet.Namespace,
+		))
+	}
+
+	return s.store.MatchRuleSet(namespaceID, req.IDs, req.TagSets, req.RuleSet)
+}
+
+func diffRuleSet(s *service, r *http.Request) (data interface{}, err error) {
+	var desired view.RuleSet
+	if err := parseYAMLOrJSONRequest(&desired, r); err != nil {
+		return nil, err
+	}
+
+	namespaceID := mux.Vars(r)[namespaceIDVar]
+	if desired.Namespace == "" {
+		desired.Namespace = namespaceID
+	}
+	if desired.Namespace != namespaceID {
+		return nil, NewBadInputError(fmt.Sprintf(
+			"namespaceID param %s and ruleset namespaceID %s do not match",
+			namespaceID,
+			desired.Namespace,
+		))
+	}
+
+	current, err := s.store.FetchRuleSetSnapshot(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	rsChanges, err := changes.NewRuleSetChanges(current, desired)
+	if err != nil {
+		return nil, NewBadInputError(err.Error())
+	}
+	return diffRuleSetResponse{
+		RuleSetChanges: rsChanges,
+		RuleSetVersion: current.Version,
+	}, nil
+}
+
 func deleteNamespace(s *service, r *http.Request) (data interface{}, err error) {
 	vars := mux.Vars(r)
 	namespaceID := vars[namespaceIDVar]
@@ -294,6 +294,199 @@ func TestUpdateRuleSetEmptyRequest(t *testing.T) {
 	println(err.Error())
 }
 
+func TestMatchRuleSet(t *testing.T) {
+	namespaceID := "testNamespace"
+	body := matchRuleSetRequest{
+		IDs:     []string{"m3+foo+service=bar"},
+		TagSets: []map[string]string{{"name": "foo", "service": "bar"}},
+	}
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("/namespaces/%s/ruleset/match", namespaceID),
+		bytes.NewBuffer(bodyBytes),
+	)
+	require.NoError(t, err)
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": namespaceID,
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	storeMock.EXPECT().MatchRuleSet(namespaceID, body.IDs, body.TagSets, gomock.Any()).Return(
+		view.MatchResults{
+			Namespace: namespaceID,
+			Version:   2,
+		},
+		nil,
+	)
+
+	service := newTestService(storeMock)
+	resp, err := matchRuleSet(service, req)
+	require.NoError(t, err)
+	typedResp := resp.(view.MatchResults)
+	require.Equal(t, 2, typedResp.Version)
+}
+
+func TestMatchRuleSetEmptyRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	service := newTestService(storeMock)
+	resp, err := matchRuleSet(service, newTestPostRequest([]byte(`{}`)))
+	require.Nil(t, resp)
+	require.Error(t, err)
+	require.IsType(t, NewBadInputError(""), err)
+}
+
+func TestMatchRuleSetNamespaceMismatch(t *testing.T) {
+	req := newTestPostRequest([]byte(`{"ids": ["m3+foo+service=bar"], "ruleset": {"id": "otherNamespace"}}`))
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": "testNamespace",
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	service := newTestService(storeMock)
+	resp, err := matchRuleSet(service, req)
+	require.Nil(t, resp)
+	require.Error(t, err)
+	require.IsType(t, NewBadInputError(""), err)
+}
+
+func TestDiffRuleSetYAML(t *testing.T) {
+	namespaceID := "testNamespace"
+	body := `
+mappingRules:
+  - name: mr
+    filter: tag1:value2
+rollupRules:
+  - name: rr
+    tombstoned: true
+    filter: tag2:value2
+    targets: []
+`
+	req := newTestPostRequest([]byte(body))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": namespaceID,
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	storeMock.EXPECT().FetchRuleSetSnapshot(namespaceID).Return(
+		view.RuleSet{
+			Namespace: namespaceID,
+			Version:   3,
+			MappingRules: []view.MappingRule{
+				{ID: "mrID", Name: "mr", Filter: "tag1:value1"},
+			},
+			RollupRules: []view.RollupRule{
+				{ID: "rrID", Name: "rr", Filter: "tag2:value2"},
+			},
+		},
+		nil,
+	)
+
+	service := newTestService(storeMock)
+	resp, err := diffRuleSet(service, req)
+	require.NoError(t, err)
+	typedResp := resp.(diffRuleSetResponse)
+	require.Equal(t, 3, typedResp.RuleSetVersion)
+	require.Equal(t, namespaceID, typedResp.RuleSetChanges.Namespace)
+	require.Len(t, typedResp.RuleSetChanges.MappingRuleChanges, 1)
+	require.Equal(t, changes.ChangeOp, typedResp.RuleSetChanges.MappingRuleChanges[0].Op)
+	require.Equal(t, "mrID", *typedResp.RuleSetChanges.MappingRuleChanges[0].RuleID)
+	require.Len(t, typedResp.RuleSetChanges.RollupRuleChanges, 1)
+	require.Equal(t, changes.DeleteOp, typedResp.RuleSetChanges.RollupRuleChanges[0].Op)
+	require.Equal(t, "rrID", *typedResp.RuleSetChanges.RollupRuleChanges[0].RuleID)
+}
+
+func TestDiffRuleSetJSONNoChanges(t *testing.T) {
+	namespaceID := "testNamespace"
+	req := newTestPostRequest([]byte(`{"id": "testNamespace", "mappingRules": [{"name": "mr", "filter": "tag1:value1"}]}`))
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": namespaceID,
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	storeMock.EXPECT().FetchRuleSetSnapshot(namespaceID).Return(
+		view.RuleSet{
+			Namespace: namespaceID,
+			Version:   3,
+			MappingRules: []view.MappingRule{
+				{ID: "mrID", Name: "mr", Filter: "tag1:value1"},
+			},
+		},
+		nil,
+	)
+
+	service := newTestService(storeMock)
+	resp, err := diffRuleSet(service, req)
+	require.NoError(t, err)
+	require.Equal(t, diffRuleSetResponse{
+		RuleSetChanges: changes.RuleSetChanges{Namespace: namespaceID},
+		RuleSetVersion: 3,
+	}, resp)
+}
+
+func TestDiffRuleSetInvalidYAML(t *testing.T) {
+	req := newTestPostRequest([]byte("mappingRules:\n  - unknownField: foo\n"))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": "testNamespace",
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	service := newTestService(storeMock)
+	resp, err := diffRuleSet(service, req)
+	require.Nil(t, resp)
+	require.Error(t, err)
+	require.IsType(t, NewBadInputError(""), err)
+}
+
+func TestDiffRuleSetNamespaceMismatch(t *testing.T) {
+	req := newTestPostRequest([]byte(`{"id": "otherNamespace"}`))
+	req = mux.SetURLVars(
+		req,
+		map[string]string{
+			"namespaceID": "testNamespace",
+		},
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	storeMock := store.NewMockStore(ctrl)
+	service := newTestService(storeMock)
+	resp, err := diffRuleSet(service, req)
+	require.Nil(t, resp)
+	require.Error(t, err)
+	require.IsType(t, NewBadInputError(""), err)
+}
+
 func newTestService(store store.Store) *service {
 	if store == nil {
 		store = newMockStore()
@@ -412,6 +605,15 @@ func (s mockStore) UpdateRuleSet(rsChanges changes.RuleSetChanges, version int,
 	return view.RuleSet{}, nil
 }
 
+func (s mockStore) MatchRuleSet(
+	namespaceID string,
+	ids []string,
+	tagSets []map[string]string,
+	proposed *view.RuleSet,
+) (view.MatchResults, error) {
+	return view.MatchResults{}, nil
+}
+
 func (s mockStore) CreateNamespace(namespaceID string, uOpts store.UpdateOptions) (view.Namespace, error) {
 	return view.Namespace{}, nil
 }
@@ -49,6 +49,8 @@ var (
 	namespacePrefix     = fmt.Sprintf("%s/{%s}", namespacePath, namespaceIDVar)
 	validateRuleSetPath = fmt.Sprintf("%s/{%s}/ruleset/validate", namespacePath, namespaceIDVar)
 	updateRuleSetPath   = fmt.Sprintf("%s/{%s}/ruleset/update", namespacePath, namespaceIDVar)
+	matchRuleSetPath    = fmt.Sprintf("%s/{%s}/ruleset/match", namespacePath, namespaceIDVar)
+	diffRuleSetPath     = fmt.Sprintf("%s/{%s}/ruleset/diff", namespacePath, namespaceIDVar)
 
 	mappingRuleRoot        = fmt.Sprintf("%s/%s", namespacePrefix, mappingRulePrefix)
 	mappingRuleWithIDPath  = fmt.Sprintf("%s/{%s}", mappingRuleRoot, ruleIDVar)
@@ -78,6 +80,8 @@ type serviceMetrics struct {
 	deleteRollupRule        instrument.MethodMetrics
 	fetchRollupRuleHistory  instrument.MethodMetrics
 	updateRuleSet           instrument.MethodMetrics
+	matchRuleSet            instrument.MethodMetrics
+	diffRuleSet             instrument.MethodMetrics
 }
 
 func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
@@ -98,12 +102,18 @@ func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 		deleteRollupRule:        instrument.NewMethodMetrics(scope, "deleteRollupRule", samplingRate),
 		fetchRollupRuleHistory:  instrument.NewMethodMetrics(scope, "fetchRollupRuleHistory", samplingRate),
 		updateRuleSet:           instrument.NewMethodMetrics(scope, "updateRuleSet", samplingRate),
+		matchRuleSet:            instrument.NewMethodMetrics(scope, "matchRuleSet", samplingRate),
+		diffRuleSet:             instrument.NewMethodMetrics(scope, "diffRuleSet", samplingRate),
 	}
 }
 
 var authorizationRegistry = map[route]auth.AuthorizationType{
 	// This validation route should only require read access.
 	{path: validateRuleSetPath, method: http.MethodPost}: auth.ReadOnlyAuthorization,
+	// Matching metric IDs against a ruleset never modifies the ruleset.
+	{path: matchRuleSetPath, method: http.MethodPost}: auth.ReadOnlyAuthorization,
+	// Diffing a ruleset only computes the changes without applying them.
+	{path: diffRuleSetPath, method: http.MethodPost}: auth.ReadOnlyAuthorization,
 }
 
 func defaultAuthorizationTypeForHTTPMethod(method string) (auth.AuthorizationType, error) {
@@ -174,6 +184,8 @@ func (s *service) RegisterHandlers(router *mux.Router) error {
 		{route: route{path: namespacePrefix, method: http.MethodDelete}, handler: s.deleteNamespace},
 		{route: route{path: validateRuleSetPath, method: http.MethodPost}, handler: s.validateRuleSet},
 		{route: route{path: updateRuleSetPath, method: http.MethodPost}, handler: s.updateRuleSet},
+		{route: route{path: matchRuleSetPath, method: http.MethodPost}, handler: s.matchRuleSet},
+		{route: route{path: diffRuleSetPath, method: http.MethodPost}, handler: s.diffRuleSet},
 
 		// Mapping Rule actions.
 		{route: route{path: mappingRuleRoot, method: http.MethodPost}, handler: s.createMappingRule},
@@ -284,6 +296,22 @@ func (s *service) updateRuleSet(w http.ResponseWriter, r *http.Request) error {
 	return s.sendResponse(w, http.StatusOK, data)
 }
 
+func (s *service) matchRuleSet(w http.ResponseWriter, r *http.Request) error {
+	data, err := s.handleRoute(matchRuleSet, r, s.metrics.matchRuleSet)
+	if err != nil {
+		return err
+	}
+	return s.sendResponse(w, http.StatusOK, data)
+}
+
+func (s *service) diffRuleSet(w http.ResponseWriter, r *http.Request) error {
+	data, err := s.handleRoute(diffRuleSet, r, s.metrics.diffRuleSet)
+	if err != nil {
+		return err
+	}
+	return s.sendResponse(w, http.StatusOK, data)
+}
+
 func (s *service) deleteNamespace(w http.ResponseWriter, r *http.Request) error {
 	data, err := s.handleRoute(deleteNamespace, r, s.metrics.deleteNamespace)
 	if err != nil {
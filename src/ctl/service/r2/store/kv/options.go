@@ -23,6 +23,8 @@ package kv
 import (
 	"time"
 
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
 	"github.com/m3db/m3/src/metrics/rules"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
@@ -32,6 +34,10 @@ const (
 	defaultRuleUpdatePropagationDelay = time.Minute
 )
 
+var (
+	defaultNameTagKey = []byte("name")
+)
+
 // StoreOptions is a set of options for a kv backed store.
 type StoreOptions interface {
 	// SetClockOptions sets the clock options.
@@ -57,6 +63,12 @@ type StoreOptions interface {
 
 	// ValidatprOptions returns the validator for the store.
 	Validator() rules.Validator
+
+	// SetRuleSetOptions sets the options used to match metric IDs against rulesets.
+	SetRuleSetOptions(value rules.Options) StoreOptions
+
+	// RuleSetOptions returns the options used to match metric IDs against rulesets.
+	RuleSetOptions() rules.Options
 }
 
 type storeOptions struct {
@@ -64,6 +76,7 @@ type storeOptions struct {
 	instrumentOpts             instrument.Options
 	ruleUpdatePropagationDelay time.Duration
 	validator                  rules.Validator
+	ruleSetOpts                rules.Options
 }
 
 // NewStoreOptions creates a new set of store options.
@@ -72,6 +85,7 @@ func NewStoreOptions() StoreOptions {
 		clockOpts:                  clock.NewOptions(),
 		instrumentOpts:             instrument.NewOptions(),
 		ruleUpdatePropagationDelay: defaultRuleUpdatePropagationDelay,
+		ruleSetOpts:                defaultRuleSetOptions(),
 	}
 }
 
@@ -114,3 +128,27 @@ func (o *storeOptions) SetValidator(value rules.Validator) StoreOptions {
 func (o *storeOptions) Validator() rules.Validator {
 	return o.validator
 }
+
+func (o *storeOptions) SetRuleSetOptions(value rules.Options) StoreOptions {
+	opts := *o
+	opts.ruleSetOpts = value
+	return &opts
+}
+
+func (o *storeOptions) RuleSetOptions() rules.Options {
+	return o.ruleSetOpts
+}
+
+func defaultRuleSetOptions() rules.Options {
+	isRollupIDFn := func(name []byte, tags []byte) bool {
+		return m3.IsRollupID(name, tags, nil)
+	}
+	return rules.NewOptions().
+		SetTagsFilterOptions(filters.TagsFilterOptions{
+			NameTagKey:          defaultNameTagKey,
+			NameAndTagsFn:       m3.NameAndTags,
+			SortedTagIteratorFn: m3.NewSortedTagIterator,
+		}).
+		SetNewRollupIDFn(m3.NewRollupID).
+		SetIsRollupIDFn(isRollupIDFn)
+}
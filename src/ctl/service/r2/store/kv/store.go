@@ -23,10 +23,14 @@ package kv
 import (
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/m3db/m3/src/ctl/service/r2"
 	r2store "github.com/m3db/m3/src/ctl/service/r2/store"
 	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
 	"github.com/m3db/m3/src/metrics/rules"
 	"github.com/m3db/m3/src/metrics/rules/view"
 	"github.com/m3db/m3/src/metrics/rules/view/changes"
@@ -41,6 +45,11 @@ type store struct {
 	updateHelper rules.RuleSetUpdateHelper
 }
 
+const (
+	nanosPerMilli = int64(1000000)
+	maxTimeNanos  = int64(math.MaxInt64)
+)
+
 var errNilValidator = errors.New("no validator set on StoreOptions so validation is not applicable")
 
 // NewStore returns a new service that knows how to talk to a kv backed r2 store.
@@ -123,6 +132,47 @@ func (s *store) UpdateRuleSet(
 	return s.FetchRuleSetSnapshot(rsChanges.Namespace)
 }
 
+func (s *store) MatchRuleSet(
+	namespaceID string,
+	ids []string,
+	tagSets []map[string]string,
+	proposed *view.RuleSet,
+) (view.MatchResults, error) {
+	rs, err := s.matchableRuleSet(namespaceID, proposed)
+	if err != nil {
+		return view.MatchResults{}, err
+	}
+
+	matchIDs := make([][]byte, 0, len(ids)+len(tagSets))
+	for _, metricID := range ids {
+		matchIDs = append(matchIDs, []byte(metricID))
+	}
+	for _, tags := range tagSets {
+		metricID, err := s.newMetricID(tags)
+		if err != nil {
+			return view.MatchResults{}, err
+		}
+		matchIDs = append(matchIDs, metricID)
+	}
+
+	// NB: matching up to the end of time also surfaces the rule changes that
+	// have not taken effect yet.
+	var (
+		nowNanos = s.nowFn().UnixNano()
+		matcher  = rs.ActiveSet(nowNanos)
+		results  = make([]view.MatchResult, 0, len(matchIDs))
+	)
+	for _, metricID := range matchIDs {
+		res := matcher.ForwardMatch(metricID, nowNanos, maxTimeNanos)
+		results = append(results, newMatchResultView(metricID, res, nowNanos))
+	}
+	return view.MatchResults{
+		Namespace: namespaceID,
+		Version:   rs.Version(),
+		Results:   results,
+	}, nil
+}
+
 func (s *store) CreateNamespace(
 	namespaceID string,
 	uOpts r2store.UpdateOptions,
@@ -445,6 +495,118 @@ func (s *store) newUpdateMeta(uOpts r2store.UpdateOptions) rules.UpdateMetadata
 	return s.updateHelper.NewUpdateMetadata(s.nowFn().UnixNano(), uOpts.Author())
 }
 
+// matchableRuleSet returns the ruleset to match metric IDs against, which is
+// the proposed ruleset if there is one, and the latest ruleset otherwise.
+func (s *store) matchableRuleSet(
+	namespaceID string,
+	proposed *view.RuleSet,
+) (rules.RuleSet, error) {
+	var (
+		version int
+		mutable rules.MutableRuleSet
+	)
+	if proposed == nil {
+		rs, err := s.ruleStore.ReadRuleSet(namespaceID)
+		if err != nil {
+			return nil, handleUpstreamError(err)
+		}
+		version = rs.Version()
+		mutable = rs.ToMutableRuleSet()
+	} else {
+		if validator := s.opts.Validator(); validator != nil {
+			if err := validator.ValidateSnapshot(*proposed); err != nil {
+				return nil, handleUpstreamError(err)
+			}
+		}
+		// The proposed rules take effect immediately so they are matched as if
+		// they were already live.
+		meta := rules.NewRuleSetUpdateHelper(0).NewUpdateMetadata(s.nowFn().UnixNano(), "")
+		mutable = rules.NewEmptyRuleSet(namespaceID, meta)
+		for _, mr := range proposed.MappingRules {
+			if _, err := mutable.AddMappingRule(mr, meta); err != nil {
+				return nil, r2.NewBadInputError(err.Error())
+			}
+		}
+		for _, rr := range proposed.RollupRules {
+			if _, err := mutable.AddRollupRule(rr, meta); err != nil {
+				return nil, r2.NewBadInputError(err.Error())
+			}
+		}
+		version = proposed.Version
+	}
+
+	// NB: the rules store does not know how metric IDs are parsed, so the ruleset
+	// is rebuilt with the options used by the aggregator to match metric IDs.
+	pb, err := mutable.Proto()
+	if err != nil {
+		return nil, handleUpstreamError(err)
+	}
+	rs, err := rules.NewRuleSetFromProto(version, pb, s.opts.RuleSetOptions())
+	if err != nil {
+		return nil, handleUpstreamError(err)
+	}
+	return rs, nil
+}
+
+// newMetricID creates the metric ID for a tag set, where the metric name is
+// the value of the name tag.
+func (s *store) newMetricID(tags map[string]string) ([]byte, error) {
+	nameTagKey := string(s.opts.RuleSetOptions().TagsFilterOptions().NameTagKey)
+	name, exists := tags[nameTagKey]
+	if !exists {
+		return nil, r2.NewBadInputError(fmt.Sprintf("tag set %v has no %s tag", tags, nameTagKey))
+	}
+	tagPairs := make([]id.TagPair, 0, len(tags)-1)
+	for tagName, tagValue := range tags {
+		if tagName == nameTagKey {
+			continue
+		}
+		tagPairs = append(tagPairs, id.TagPair{Name: []byte(tagName), Value: []byte(tagValue)})
+	}
+	return m3.NewMetricID([]byte(name), tagPairs), nil
+}
+
+func newMatchResultView(metricID []byte, res rules.MatchResult, timeNanos int64) view.MatchResult {
+	numNewRollupIDs := res.NumNewRollupIDs()
+	forNewRollupIDs := make([]view.RollupIDMatches, 0, numNewRollupIDs)
+	for i := 0; i < numNewRollupIDs; i++ {
+		rollup := res.ForNewRollupIDsAt(i, timeNanos)
+		forNewRollupIDs = append(forNewRollupIDs, view.RollupIDMatches{
+			ID:        string(rollup.ID),
+			Metadatas: newStagedMetadataViews(rollup.Metadatas),
+		})
+	}
+	return view.MatchResult{
+		ID:              string(metricID),
+		ForExistingID:   newStagedMetadataViews(res.ForExistingIDAt(timeNanos)),
+		ForNewRollupIDs: forNewRollupIDs,
+	}
+}
+
+func newStagedMetadataViews(metadatas metadata.StagedMetadatas) []view.StagedMetadata {
+	views := make([]view.StagedMetadata, 0, len(metadatas))
+	for _, sm := range metadatas {
+		pipelines := make([]view.PipelineMetadata, 0, len(sm.Pipelines))
+		for _, pm := range sm.Pipelines {
+			pipeline := view.PipelineMetadata{
+				AggregationID:   pm.AggregationID,
+				StoragePolicies: pm.StoragePolicies,
+				DropPolicy:      pm.DropPolicy,
+			}
+			if !pm.Pipeline.IsEmpty() {
+				pipeline.Pipeline = pm.Pipeline.String()
+			}
+			pipelines = append(pipelines, pipeline)
+		}
+		views = append(views, view.StagedMetadata{
+			CutoverMillis: sm.CutoverNanos / nanosPerMilli,
+			Tombstoned:    sm.Tombstoned,
+			Pipelines:     pipelines,
+		})
+	}
+	return views
+}
+
 func mappingRuleNotFoundError(namespaceID, mappingRuleID string) error {
 	return r2.NewNotFoundError(
 		fmt.Sprintf("mapping rule: %s doesn't exist in Namespace: %s",
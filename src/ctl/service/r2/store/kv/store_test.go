@@ -250,6 +250,140 @@ func TestUpdateRuleSetWriteFailure(t *testing.T) {
 	require.IsType(t, r2.NewConflictError(""), err)
 }
 
+func TestMatchRuleSet(t *testing.T) {
+	helper := rules.NewRuleSetUpdateHelper(0)
+	initialRuleSet, err := testMatchRuleSet(1, helper.NewUpdateMetadata(time.Unix(100, 0).UnixNano(), "validUser"))
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockedStore := rules.NewMockStore(ctrl)
+	mockedStore.EXPECT().ReadRuleSet("testNamespace").Return(
+		initialRuleSet,
+		nil,
+	)
+
+	storeOpts := NewStoreOptions().SetClockOptions(
+		clock.NewOptions().SetNowFn(func() time.Time {
+			return time.Unix(200, 0)
+		}),
+	)
+	rulesStore := NewStore(mockedStore, storeOpts)
+	res, err := rulesStore.MatchRuleSet(
+		"testNamespace",
+		[]string{"m3+foo+env=bar,service=baz", "m3+other+service=baz"},
+		[]map[string]string{{"name": "foo", "service": "baz", "env": "bar"}},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "testNamespace", res.Namespace)
+	require.Equal(t, 1, res.Version)
+	require.Equal(t, 3, len(res.Results))
+
+	// Metric IDs and tag sets are matched the same way.
+	for _, idx := range []int{0, 2} {
+		match := res.Results[idx]
+		require.Equal(t, "m3+foo+env=bar,service=baz", match.ID)
+		require.Equal(t, []view.StagedMetadata{
+			{
+				CutoverMillis: 100000,
+				Pipelines: []view.PipelineMetadata{
+					{
+						AggregationID: aggregation.DefaultID,
+						StoragePolicies: policy.StoragePolicies{
+							policy.MustParseStoragePolicy("1s:6h"),
+						},
+					},
+				},
+			},
+		}, match.ForExistingID)
+		require.Equal(t, 1, len(match.ForNewRollupIDs))
+		rollup := match.ForNewRollupIDs[0]
+		require.Equal(t, "m3+testTarget+m3_rollup=true,service=baz", rollup.ID)
+		require.Equal(t, 1, len(rollup.Metadatas))
+		require.Equal(t, int64(100000), rollup.Metadatas[0].CutoverMillis)
+		require.Equal(t, 1, len(rollup.Metadatas[0].Pipelines))
+		require.Equal(t, policy.StoragePolicies{
+			policy.MustParseStoragePolicy("1m:10d"),
+		}, rollup.Metadatas[0].Pipelines[0].StoragePolicies)
+	}
+
+	// Metric IDs not matching any rules use the default pipelines.
+	require.Equal(t, "m3+other+service=baz", res.Results[1].ID)
+	require.Equal(t, 0, len(res.Results[1].ForNewRollupIDs))
+	require.Equal(t, 1, len(res.Results[1].ForExistingID))
+	require.Equal(t, 1, len(res.Results[1].ForExistingID[0].Pipelines))
+	require.True(t, res.Results[1].ForExistingID[0].Pipelines[0].StoragePolicies.IsDefault())
+}
+
+func TestMatchRuleSetProposedRuleSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockedStore := rules.NewMockStore(ctrl)
+
+	storeOpts := NewStoreOptions().SetClockOptions(
+		clock.NewOptions().SetNowFn(func() time.Time {
+			return time.Unix(200, 0)
+		}),
+	)
+	rulesStore := NewStore(mockedStore, storeOpts)
+	proposed := &view.RuleSet{
+		Namespace: "testNamespace",
+		Version:   3,
+		MappingRules: []view.MappingRule{
+			{
+				Name:   "proposedMappingRule",
+				Filter: "name:foo",
+				StoragePolicies: policy.StoragePolicies{
+					policy.MustParseStoragePolicy("10s:2d"),
+				},
+			},
+		},
+	}
+	res, err := rulesStore.MatchRuleSet("testNamespace", []string{"m3+foo+service=baz"}, nil, proposed)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Version)
+	require.Equal(t, 1, len(res.Results))
+	require.Equal(t, []view.StagedMetadata{
+		{
+			CutoverMillis: 200000,
+			Pipelines: []view.PipelineMetadata{
+				{
+					AggregationID: aggregation.DefaultID,
+					StoragePolicies: policy.StoragePolicies{
+						policy.MustParseStoragePolicy("10s:2d"),
+					},
+				},
+			},
+		},
+	}, res.Results[0].ForExistingID)
+	require.Equal(t, 0, len(res.Results[0].ForNewRollupIDs))
+}
+
+func TestMatchRuleSetTagSetWithoutName(t *testing.T) {
+	helper := rules.NewRuleSetUpdateHelper(0)
+	initialRuleSet, err := testMatchRuleSet(1, helper.NewUpdateMetadata(100, "validUser"))
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockedStore := rules.NewMockStore(ctrl)
+	mockedStore.EXPECT().ReadRuleSet("testNamespace").Return(
+		initialRuleSet,
+		nil,
+	)
+
+	rulesStore := NewStore(mockedStore, NewStoreOptions())
+	_, err = rulesStore.MatchRuleSet(
+		"testNamespace",
+		nil,
+		[]map[string]string{{"service": "baz"}},
+		nil,
+	)
+	require.Error(t, err)
+	require.IsType(t, r2.NewBadInputError(""), err)
+}
+
 func newTestRuleSetChanges(mrs view.MappingRules, rrs view.RollupRules) changes.RuleSetChanges {
 	mrChanges := make([]changes.MappingRuleChange, 0, len(mrs))
 	for uuid := range mrs {
@@ -360,3 +494,44 @@ func newEmptyTestRuleSet(version int, meta rules.UpdateMetadata) (rules.RuleSet,
 
 	return ruleSet, nil
 }
+
+func testMatchRuleSet(version int, meta rules.UpdateMetadata) (rules.RuleSet, error) {
+	mutable := rules.NewEmptyRuleSet("testNamespace", meta)
+	if _, err := mutable.AddMappingRule(view.MappingRule{
+		Name:   "mappingRule",
+		Filter: "name:foo",
+		StoragePolicies: policy.StoragePolicies{
+			policy.MustParseStoragePolicy("1s:6h"),
+		},
+	}, meta); err != nil {
+		return nil, err
+	}
+	if _, err := mutable.AddRollupRule(view.RollupRule{
+		Name:   "rollupRule",
+		Filter: "name:foo",
+		Targets: []view.RollupTarget{
+			{
+				Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+					{
+						Type: pipeline.RollupOpType,
+						Rollup: pipeline.RollupOp{
+							NewName:       []byte("testTarget"),
+							Tags:          [][]byte{[]byte("service")},
+							AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+						},
+					},
+				}),
+				StoragePolicies: policy.StoragePolicies{
+					policy.MustParseStoragePolicy("1m:10d"),
+				},
+			},
+		},
+	}, meta); err != nil {
+		return nil, err
+	}
+	proto, err := mutable.Proto()
+	if err != nil {
+		return nil, err
+	}
+	return rules.NewRuleSetFromProto(version, proto, rules.NewOptions())
+}
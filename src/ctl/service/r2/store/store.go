@@ -45,6 +45,15 @@ type Store interface {
 	// UpdateRuleSet updates a ruleset with a given namespace.
 	UpdateRuleSet(rsChanges changes.RuleSetChanges, version int, uOpts UpdateOptions) (view.RuleSet, error)
 
+	// MatchRuleSet matches metric IDs and tag sets against the latest ruleset of the
+	// given namespace, or against the proposed ruleset instead if it is not nil.
+	MatchRuleSet(
+		namespaceID string,
+		ids []string,
+		tagSets []map[string]string,
+		proposed *view.RuleSet,
+	) (view.MatchResults, error)
+
 	// FetchMappingRule fetches the mapping rule for the given namespace ID and rule ID.
 	FetchMappingRule(namespaceID, mappingRuleID string) (view.MappingRule, error)
 
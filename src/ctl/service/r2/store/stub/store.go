@@ -394,6 +394,16 @@ func (s *store) UpdateRuleSet(
 	return view.RuleSet{}, errNotImplemented
 }
 
+// This function is not supported. Use mocks package.
+func (s *store) MatchRuleSet(
+	namespaceID string,
+	ids []string,
+	tagSets []map[string]string,
+	proposed *view.RuleSet,
+) (view.MatchResults, error) {
+	return view.MatchResults{}, errNotImplemented
+}
+
 func (s *store) DeleteNamespace(namespaceID string, uOpts r2store.UpdateOptions) error {
 	switch namespaceID {
 	case s.data.ErrorNamespace:
@@ -21,7 +21,10 @@
 package client
 
 import (
+	"context"
+
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xretry "github.com/m3db/m3/src/x/retry"
@@ -41,6 +44,7 @@ type aggregateAttempt struct {
 }
 
 type aggregateAttemptArgs struct {
+	ctx   context.Context
 	ns    ident.ID
 	query index.Query
 	opts  index.AggregationOptions
@@ -55,7 +59,11 @@ func (f *aggregateAttempt) reset() {
 func (f *aggregateAttempt) performAttempt() error {
 	var err error
 	f.resultIter, f.resultExhaustive, err = f.session.aggregateAttempt(
-		f.args.ns, f.args.query, f.args.opts)
+		f.args.ctx, f.args.ns, f.args.query, f.args.opts)
+	if err != nil && f.args.ctx.Err() != nil {
+		// NB: do not retry aggregates once the request has been cancelled.
+		return xerrors.NewNonRetryableError(f.args.ctx.Err())
+	}
 	return err
 }
 
@@ -0,0 +1,132 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+)
+
+type cardinalityOp struct {
+	request      rpc.CardinalityRequest
+	completionFn completionFn
+}
+
+func (c *cardinalityOp) Size() int {
+	// Cardinality is always a single op
+	return 1
+}
+
+func (c *cardinalityOp) CompletionFn() completionFn {
+	return c.completionFn
+}
+
+// cardinalityResultAccumulator merges the cardinality results returned by
+// each host. Every series is owned by one host per replica, so series counts
+// are summed across hosts and divided by the number of replicas. Distinct
+// term counts per field can not be summed since hosts share terms, so the
+// maximum seen by any host is used as a lower bound.
+// NB: Each host only returns its own top N entries so merged counts for
+// entries near the bottom of the lists are approximate.
+type cardinalityResultAccumulator struct {
+	sync.Mutex
+
+	replicas    int
+	numSeries   int64
+	byName      map[string]int64
+	byLabelName map[string]int64
+	byPair      map[string]int64
+	exhaustive  bool
+}
+
+func newCardinalityResultAccumulator(replicas int) *cardinalityResultAccumulator {
+	if replicas < 1 {
+		replicas = 1
+	}
+	return &cardinalityResultAccumulator{
+		replicas:    replicas,
+		byName:      make(map[string]int64),
+		byLabelName: make(map[string]int64),
+		byPair:      make(map[string]int64),
+		exhaustive:  true,
+	}
+}
+
+func (a *cardinalityResultAccumulator) add(result *rpc.CardinalityResult_) {
+	a.Lock()
+	a.numSeries += result.NumSeries
+	for _, e := range result.SeriesCountByMetricName {
+		a.byName[string(e.Name)] += e.Value
+	}
+	for _, e := range result.LabelValueCountByLabelName {
+		if e.Value > a.byLabelName[string(e.Name)] {
+			a.byLabelName[string(e.Name)] = e.Value
+		}
+	}
+	for _, e := range result.SeriesCountByLabelValuePair {
+		a.byPair[string(e.Name)] += e.Value
+	}
+	a.exhaustive = a.exhaustive && result.Exhaustive
+	a.Unlock()
+}
+
+func (a *cardinalityResultAccumulator) result(topN int) index.CardinalityQueryResult {
+	a.Lock()
+	defer a.Unlock()
+
+	replicas := int64(a.replicas)
+	return index.CardinalityQueryResult{
+		Stats: index.CardinalityStats{
+			NumSeries:                   a.numSeries / replicas,
+			SeriesCountByMetricName:     cardinalityEntries(a.byName, replicas, topN),
+			LabelValueCountByLabelName:  cardinalityEntries(a.byLabelName, 1, topN),
+			SeriesCountByLabelValuePair: cardinalityEntries(a.byPair, replicas, topN),
+		},
+		Exhaustive: a.exhaustive,
+	}
+}
+
+func cardinalityEntries(
+	counts map[string]int64,
+	divisor int64,
+	topN int,
+) []index.CardinalityEntry {
+	entries := make([]index.CardinalityEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, index.CardinalityEntry{
+			Name:  name,
+			Value: count / divisor,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if topN > 0 && len(entries) > topN {
+		entries = entries[:topN]
+	}
+	return entries
+}
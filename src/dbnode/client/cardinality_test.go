@@ -0,0 +1,74 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardinalityResultAccumulator(t *testing.T) {
+	acc := newCardinalityResultAccumulator(2)
+	acc.add(&rpc.CardinalityResult_{
+		NumSeries: 4,
+		SeriesCountByMetricName: []*rpc.CardinalityEntry{
+			{Name: []byte("up"), Value: 4},
+		},
+		LabelValueCountByLabelName: []*rpc.CardinalityEntry{
+			{Name: []byte("job"), Value: 2},
+		},
+		SeriesCountByLabelValuePair: []*rpc.CardinalityEntry{
+			{Name: []byte("job=a"), Value: 4},
+		},
+		Exhaustive: true,
+	})
+	acc.add(&rpc.CardinalityResult_{
+		NumSeries: 6,
+		SeriesCountByMetricName: []*rpc.CardinalityEntry{
+			{Name: []byte("up"), Value: 2},
+			{Name: []byte("cpu"), Value: 4},
+		},
+		LabelValueCountByLabelName: []*rpc.CardinalityEntry{
+			{Name: []byte("job"), Value: 3},
+		},
+		SeriesCountByLabelValuePair: []*rpc.CardinalityEntry{
+			{Name: []byte("job=a"), Value: 2},
+		},
+		Exhaustive: false,
+	})
+
+	result := acc.result(1)
+	assert.False(t, result.Exhaustive)
+	assert.Equal(t, int64(5), result.Stats.NumSeries)
+	assert.Equal(t, []index.CardinalityEntry{
+		{Name: "up", Value: 3},
+	}, result.Stats.SeriesCountByMetricName)
+	assert.Equal(t, []index.CardinalityEntry{
+		{Name: "job", Value: 3},
+	}, result.Stats.LabelValueCountByLabelName)
+	assert.Equal(t, []index.CardinalityEntry{
+		{Name: "job=a", Value: 3},
+	}, result.Stats.SeriesCountByLabelValuePair)
+}
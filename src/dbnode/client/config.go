@@ -104,6 +104,29 @@ type Configuration struct {
 	// HostQueueFlushInterval sets the interval at which the m3db client will flush the queue for a
 	// given host regardless of the number of batched operations.
 	HostQueueFlushInterval *time.Duration `yaml:"hostQueueFlushInterval"`
+
+	// Transport is the configuration for the transport used to talk to nodes.
+	Transport *TransportConfiguration `yaml:"transport"`
+}
+
+// TransportConfiguration is the configuration for the transport used to
+// talk to nodes.
+type TransportConfiguration struct {
+	// Type is the transport type, either tchannel or grpc.
+	Type TransportType `yaml:"type"`
+
+	// GRPCPort is the port of the gRPC node service and is required when
+	// using the gRPC transport, since requests the gRPC node service does
+	// not serve are still sent over TChannel to the host endpoint.
+	GRPCPort int `yaml:"grpcPort"`
+
+	// GRPCMaxCallRecvMsgSize is the max size in bytes of a message received
+	// from the gRPC node service, if zero the client default is used.
+	GRPCMaxCallRecvMsgSize int `yaml:"grpcMaxCallRecvMsgSize"`
+
+	// GRPCMaxCallSendMsgSize is the max size in bytes of a message sent to
+	// the gRPC node service, if zero the client default is used.
+	GRPCMaxCallSendMsgSize int `yaml:"grpcMaxCallSendMsgSize"`
 }
 
 // ProtoConfiguration is the configuration for running with ProtoDataMode enabled.
@@ -195,6 +218,25 @@ func (c *Configuration) Validate() error {
 		return fmt.Errorf("host queue flush interval must be larger than zero but was: %s", c.HostQueueFlushInterval.String())
 	}
 
+	if c.Transport != nil && (c.Transport.GRPCPort < 0 || c.Transport.GRPCPort > 65535) {
+		return fmt.Errorf("m3db client transport grpcPort was: %d but must be >= 0 and <= 65535",
+			c.Transport.GRPCPort)
+	}
+
+	if c.Transport != nil && c.Transport.Type == GRPCTransportType && c.Transport.GRPCPort == 0 {
+		return errors.New("m3db client transport grpcPort must be set when using the grpc transport")
+	}
+
+	if c.Transport != nil && c.Transport.GRPCMaxCallRecvMsgSize < 0 {
+		return fmt.Errorf("m3db client transport grpcMaxCallRecvMsgSize was: %d but must be >= 0",
+			c.Transport.GRPCMaxCallRecvMsgSize)
+	}
+
+	if c.Transport != nil && c.Transport.GRPCMaxCallSendMsgSize < 0 {
+		return fmt.Errorf("m3db client transport grpcMaxCallSendMsgSize was: %d but must be >= 0",
+			c.Transport.GRPCMaxCallSendMsgSize)
+	}
+
 	if err := c.Proto.Validate(); err != nil {
 		return fmt.Errorf("error validating M3DB client proto configuration: %v", err)
 	}
@@ -328,6 +370,17 @@ func (c Configuration) NewAdminClient(
 		v = v.SetAsyncWriteMaxConcurrency(*c.AsyncWriteMaxConcurrency)
 	}
 
+	if c.Transport != nil {
+		v = v.SetTransportType(c.Transport.Type).
+			SetGRPCPort(c.Transport.GRPCPort)
+		if c.Transport.GRPCMaxCallRecvMsgSize > 0 {
+			v = v.SetGRPCMaxCallRecvMsgSize(c.Transport.GRPCMaxCallRecvMsgSize)
+		}
+		if c.Transport.GRPCMaxCallSendMsgSize > 0 {
+			v = v.SetGRPCMaxCallSendMsgSize(c.Transport.GRPCMaxCallSendMsgSize)
+		}
+	}
+
 	if c.WriteConsistencyLevel != nil {
 		v = v.SetWriteConsistencyLevel(*c.WriteConsistencyLevel)
 	}
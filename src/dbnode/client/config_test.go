@@ -128,3 +128,13 @@ proto:
 
 	assert.Equal(t, expected, cfg)
 }
+
+func TestConfigurationValidateGRPCTransport(t *testing.T) {
+	cfg := Configuration{
+		Transport: &TransportConfiguration{Type: GRPCTransportType},
+	}
+	require.Error(t, cfg.Validate())
+
+	cfg.Transport.GRPCPort = 9005
+	require.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	xclose "github.com/m3db/m3/src/x/close"
+
+	"github.com/uber/tchannel-go/thrift"
+	"google.golang.org/grpc"
+)
+
+var (
+	errGRPCPortNotSet = errors.New("gRPC transport requires the gRPC port of the node service")
+)
+
+// newGRPCConn dials the gRPC node service of a host and returns an adapter
+// that satisfies the thrift node client interface so that the session and
+// host queues can use either transport interchangeably. Requests that the
+// gRPC node service does not serve, such as fetches by ID and the peer
+// bootstrap streaming endpoints, are sent using the given TChannel client.
+func newGRPCConn(
+	address string,
+	opts Options,
+	fallbackCloser xclose.SimpleCloser,
+	fallback rpc.TChanNode,
+) (xclose.SimpleCloser, rpc.TChanNode, error) {
+	address, err := grpcAddress(address, opts.GRPCPort())
+	if err != nil {
+		fallbackCloser.Close()
+		return nil, nil, err
+	}
+	conn, err := grpc.Dial(address,
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(opts.GRPCMaxCallRecvMsgSize()),
+			grpc.MaxCallSendMsgSize(opts.GRPCMaxCallSendMsgSize()),
+		))
+	if err != nil {
+		fallbackCloser.Close()
+		return nil, nil, err
+	}
+
+	client := &grpcNodeClient{
+		TChanNode: fallback,
+		client:    nodepb.NewNodeClient(conn),
+	}
+	closer := grpcConnCloser{conn: conn, fallbackCloser: fallbackCloser}
+	return closer, client, nil
+}
+
+func grpcAddress(address string, port int) (string, error) {
+	if port == 0 {
+		return "", errGRPCPortNotSet
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+type grpcConnCloser struct {
+	conn           *grpc.ClientConn
+	fallbackCloser xclose.SimpleCloser
+}
+
+func (c grpcConnCloser) Close() {
+	c.conn.Close()
+	c.fallbackCloser.Close()
+}
+
+// grpcNodeClient serves the requests supported by the gRPC node service
+// using gRPC and falls back to the embedded TChannel client for the rest.
+type grpcNodeClient struct {
+	rpc.TChanNode
+
+	client nodepb.NodeClient
+}
+
+func (c *grpcNodeClient) Health(ctx thrift.Context) (*rpc.NodeHealthResult_, error) {
+	res, err := c.client.Health(ctx, &nodepb.HealthRequest{})
+	if err != nil {
+		return nil, convert.FromGRPCError(err)
+	}
+	return &rpc.NodeHealthResult_{
+		Ok:           res.Ok,
+		Status:       res.Status,
+		Bootstrapped: res.Bootstrapped,
+	}, nil
+}
+
+func (c *grpcNodeClient) Write(ctx thrift.Context, req *rpc.WriteRequest) error {
+	if _, err := c.client.Write(ctx, convert.FromRPCWriteRequest(req)); err != nil {
+		return convert.FromGRPCError(err)
+	}
+	return nil
+}
+
+func (c *grpcNodeClient) WriteTagged(ctx thrift.Context, req *rpc.WriteTaggedRequest) error {
+	if _, err := c.client.WriteTagged(ctx, convert.FromRPCWriteTaggedRequest(req)); err != nil {
+		return convert.FromGRPCError(err)
+	}
+	return nil
+}
+
+func (c *grpcNodeClient) WriteBatchRaw(ctx thrift.Context, req *rpc.WriteBatchRawRequest) error {
+	res, err := c.client.WriteBatch(ctx, convert.FromRPCWriteBatchRawRequest(req))
+	return writeBatchRawErrors(res, err)
+}
+
+func (c *grpcNodeClient) WriteTaggedBatchRaw(ctx thrift.Context, req *rpc.WriteTaggedBatchRawRequest) error {
+	res, err := c.client.WriteTaggedBatch(ctx, convert.FromRPCWriteTaggedBatchRawRequest(req))
+	return writeBatchRawErrors(res, err)
+}
+
+// writeBatchRawErrors returns the result of a gRPC batch write in the same
+// form as the thrift batch write endpoints.
+func writeBatchRawErrors(res *nodepb.WriteBatchResponse, err error) error {
+	if err != nil {
+		return convert.FromGRPCError(err)
+	}
+	if batchErrs := convert.ToRPCWriteBatchRawErrors(res); batchErrs != nil {
+		return batchErrs
+	}
+	return nil
+}
+
+func (c *grpcNodeClient) FetchTagged(ctx thrift.Context, req *rpc.FetchTaggedRequest) (*rpc.FetchTaggedResult_, error) {
+	stream, err := c.client.FetchTagged(ctx, convert.FromRPCFetchTaggedRequest(req))
+	if err != nil {
+		return nil, convert.FromGRPCError(err)
+	}
+
+	result := rpc.NewFetchTaggedResult_()
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, convert.FromGRPCError(err)
+		}
+		for _, elem := range res.Elements {
+			result.Elements = append(result.Elements, convert.ToRPCFetchTaggedIDResult(elem))
+		}
+		result.Exhaustive = res.Exhaustive
+	}
+	return result, nil
+}
+
+func (c *grpcNodeClient) AggregateRaw(ctx thrift.Context, req *rpc.AggregateQueryRawRequest) (*rpc.AggregateQueryRawResult_, error) {
+	res, err := c.client.Aggregate(ctx, convert.FromRPCAggregateQueryRawRequest(req))
+	if err != nil {
+		return nil, convert.FromGRPCError(err)
+	}
+	return convert.ToRPCAggregateQueryRawResult(res), nil
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestGRPCAddress(t *testing.T) {
+	addr, err := grpcAddress("testhost:9000", 9005)
+	require.NoError(t, err)
+	assert.Equal(t, "testhost:9005", addr)
+
+	_, err = grpcAddress("testhost:9000", 0)
+	assert.Equal(t, errGRPCPortNotSet, err)
+
+	_, err = grpcAddress("testhost", 9005)
+	require.Error(t, err)
+}
+
+func TestGRPCNodeClientWriteTaggedBatchRaw(t *testing.T) {
+	fake := &fakeNodeClient{
+		writeBatchErrs: []*nodepb.WriteBatchError{
+			{
+				Index: 1,
+				Err: &nodepb.Error{
+					Type:    nodepb.ErrorType_BAD_REQUEST,
+					Message: "bad series",
+				},
+			},
+		},
+	}
+	client := newTestGRPCNodeClient(fake, nil)
+
+	tctx, _ := thrift.NewContext(time.Minute)
+	err := client.WriteTaggedBatchRaw(tctx, &rpc.WriteTaggedBatchRawRequest{
+		NameSpace: []byte("testNs"),
+		Elements: []*rpc.WriteTaggedBatchRawRequestElement{
+			{
+				ID:          []byte("foo"),
+				EncodedTags: []byte("fooTags"),
+				Datapoint:   &rpc.Datapoint{Timestamp: 1, Value: 1},
+			},
+			{
+				ID:          []byte("bar"),
+				EncodedTags: []byte("barTags"),
+				Datapoint:   &rpc.Datapoint{Timestamp: 1, Value: 2},
+			},
+		},
+	})
+	require.Error(t, err)
+
+	batchErrs, ok := err.(*rpc.WriteBatchRawErrors)
+	require.True(t, ok)
+	require.Equal(t, 1, len(batchErrs.Errors))
+	assert.Equal(t, int64(1), batchErrs.Errors[0].Index)
+	assert.Equal(t, rpc.ErrorType_BAD_REQUEST, batchErrs.Errors[0].Err.Type)
+	assert.Equal(t, "bad series", batchErrs.Errors[0].Err.Message)
+
+	require.Equal(t, 1, len(fake.writeTaggedBatch))
+	req := fake.writeTaggedBatch[0]
+	assert.Equal(t, "testNs", string(req.NameSpace))
+	require.Equal(t, 2, len(req.Elements))
+	assert.Equal(t, "foo", string(req.Elements[0].Id))
+	assert.Equal(t, "fooTags", string(req.Elements[0].EncodedTags))
+	assert.Equal(t, 1.0, req.Elements[0].Datapoint.Value)
+	assert.Equal(t, "bar", string(req.Elements[1].Id))
+	assert.Equal(t, "barTags", string(req.Elements[1].EncodedTags))
+	assert.Equal(t, 2.0, req.Elements[1].Datapoint.Value)
+}
+
+func TestGRPCNodeClientWriteBatchRawNoErrors(t *testing.T) {
+	fake := &fakeNodeClient{}
+	client := newTestGRPCNodeClient(fake, nil)
+
+	tctx, _ := thrift.NewContext(time.Minute)
+	err := client.WriteBatchRaw(tctx, &rpc.WriteBatchRawRequest{
+		NameSpace: []byte("testNs"),
+		Elements: []*rpc.WriteBatchRawRequestElement{
+			{
+				ID:        []byte("foo"),
+				Datapoint: &rpc.Datapoint{Timestamp: 1, Value: 1},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(fake.writeBatch))
+}
+
+func TestGRPCNodeClientFetchTaggedMergesStream(t *testing.T) {
+	fake := &fakeNodeClient{
+		fetchTagged: []*nodepb.FetchTaggedResponse{
+			{
+				Elements: []*nodepb.FetchTaggedIDResult{
+					{Id: []byte("foo"), NameSpace: []byte("testNs")},
+					{Id: []byte("bar"), NameSpace: []byte("testNs")},
+				},
+				Exhaustive: true,
+			},
+			{
+				Elements: []*nodepb.FetchTaggedIDResult{
+					{Id: []byte("baz"), NameSpace: []byte("testNs")},
+				},
+				Exhaustive: true,
+			},
+		},
+	}
+	client := newTestGRPCNodeClient(fake, nil)
+
+	tctx, _ := thrift.NewContext(time.Minute)
+	result, err := client.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace: []byte("testNs"),
+		Query:     []byte("query"),
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Exhaustive)
+	require.Equal(t, 3, len(result.Elements))
+	assert.Equal(t, "foo", string(result.Elements[0].ID))
+	assert.Equal(t, "bar", string(result.Elements[1].ID))
+	assert.Equal(t, "baz", string(result.Elements[2].ID))
+}
+
+func TestGRPCNodeClientFallsBackToTChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &rpc.FetchBatchRawRequest{NameSpace: []byte("testNs")}
+	expected := &rpc.FetchBatchRawResult_{}
+	fallback := rpc.NewMockTChanNode(ctrl)
+	fallback.EXPECT().FetchBatchRaw(gomock.Any(), req).Return(expected, nil)
+
+	client := newTestGRPCNodeClient(&fakeNodeClient{}, fallback)
+
+	tctx, _ := thrift.NewContext(time.Minute)
+	result, err := client.FetchBatchRaw(tctx, req)
+	require.NoError(t, err)
+	assert.True(t, expected == result)
+}
+
+func TestGRPCConnReceivesLargeResponse(t *testing.T) {
+	// Respond with more than the 4mb gRPC default max receive message size.
+	tagValues := make([][]byte, 0, 6)
+	for i := 0; i < cap(tagValues); i++ {
+		tagValues = append(tagValues, make([]byte, 1024*1024))
+	}
+	res := &nodepb.AggregateResponse{
+		Results: []*nodepb.AggregateTagNameElement{
+			{TagName: []byte("foo"), TagValues: tagValues},
+		},
+		Exhaustive: true,
+	}
+	port, stop := newTestGRPCNodeServer(t, &aggregateNodeServer{res: res})
+	defer stop()
+	req := &rpc.AggregateQueryRawRequest{
+		NameSpace: []byte("testNs"),
+		Query:     []byte("query"),
+	}
+
+	opts := NewOptions().SetGRPCPort(port)
+	closer, client, err := newGRPCConn("127.0.0.1:9000", opts, noopCloser{}, nil)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	tctx, _ := thrift.NewContext(time.Minute)
+	result, err := client.AggregateRaw(tctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Results))
+	assert.Equal(t, len(tagValues), len(result.Results[0].TagValues))
+
+	// Limiting the max receive message size below the response size fails.
+	opts = opts.SetGRPCMaxCallRecvMsgSize(4 * 1024 * 1024)
+	closer, client, err = newGRPCConn("127.0.0.1:9000", opts, noopCloser{}, nil)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	_, err = client.AggregateRaw(tctx, req)
+	require.Error(t, err)
+}
+
+func newTestGRPCNodeServer(t *testing.T, srv nodepb.NodeServer) (int, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	nodepb.RegisterNodeServer(server, srv)
+	go server.Serve(listener)
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return p, server.Stop
+}
+
+// aggregateNodeServer is a gRPC node server that only serves aggregate
+// requests, the embedded server is nil and panics for any other request.
+type aggregateNodeServer struct {
+	nodepb.NodeServer
+
+	res *nodepb.AggregateResponse
+}
+
+func (s *aggregateNodeServer) Aggregate(
+	ctx context.Context,
+	req *nodepb.AggregateRequest,
+) (*nodepb.AggregateResponse, error) {
+	return s.res, nil
+}
+
+func newTestGRPCNodeClient(
+	client nodepb.NodeClient,
+	fallback rpc.TChanNode,
+) *grpcNodeClient {
+	return &grpcNodeClient{
+		TChanNode: fallback,
+		client:    client,
+	}
+}
+
+type fakeNodeClient struct {
+	sync.Mutex
+	writeBatch       []*nodepb.WriteBatchRequest
+	writeTaggedBatch []*nodepb.WriteBatchRequest
+	writeBatchErrs   []*nodepb.WriteBatchError
+	fetchTagged      []*nodepb.FetchTaggedResponse
+}
+
+func (c *fakeNodeClient) Health(
+	ctx context.Context,
+	in *nodepb.HealthRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.HealthResponse, error) {
+	return &nodepb.HealthResponse{Ok: true}, nil
+}
+
+func (c *fakeNodeClient) Write(
+	ctx context.Context,
+	in *nodepb.WriteRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.WriteResponse, error) {
+	return &nodepb.WriteResponse{}, nil
+}
+
+func (c *fakeNodeClient) WriteTagged(
+	ctx context.Context,
+	in *nodepb.WriteTaggedRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.WriteResponse, error) {
+	return &nodepb.WriteResponse{}, nil
+}
+
+func (c *fakeNodeClient) WriteBatch(
+	ctx context.Context,
+	in *nodepb.WriteBatchRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.WriteBatchResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.writeBatch = append(c.writeBatch, in)
+	return &nodepb.WriteBatchResponse{Errors: c.writeBatchErrs}, nil
+}
+
+func (c *fakeNodeClient) WriteTaggedBatch(
+	ctx context.Context,
+	in *nodepb.WriteBatchRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.WriteBatchResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.writeTaggedBatch = append(c.writeTaggedBatch, in)
+	return &nodepb.WriteBatchResponse{Errors: c.writeBatchErrs}, nil
+}
+
+func (c *fakeNodeClient) FetchTagged(
+	ctx context.Context,
+	in *nodepb.FetchTaggedRequest,
+	opts ...grpc.CallOption,
+) (nodepb.Node_FetchTaggedClient, error) {
+	return &fakeFetchTaggedStream{responses: c.fetchTagged}, nil
+}
+
+func (c *fakeNodeClient) Aggregate(
+	ctx context.Context,
+	in *nodepb.AggregateRequest,
+	opts ...grpc.CallOption,
+) (*nodepb.AggregateResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+type fakeFetchTaggedStream struct {
+	grpc.ClientStream
+	responses []*nodepb.FetchTaggedResponse
+}
+
+func (s *fakeFetchTaggedStream) Recv() (*nodepb.FetchTaggedResponse, error) {
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+	res := s.responses[0]
+	s.responses = s.responses[1:]
+	return res, nil
+}
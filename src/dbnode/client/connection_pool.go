@@ -304,6 +304,9 @@ func newConn(channelName string, address string, opts Options) (xclose.SimpleClo
 	endpoint := &thrift.ClientOptions{HostPort: address}
 	thriftClient := thrift.NewClient(channel, nchannel.ChannelName, endpoint)
 	client := rpc.NewTChanNodeClient(thriftClient)
+	if opts.TransportType() == GRPCTransportType {
+		return newGRPCConn(address, opts, channel, client)
+	}
 	return channel, client, nil
 }
 
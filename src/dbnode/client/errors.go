@@ -54,6 +54,18 @@ func IsBadRequestError(err error) bool {
 	return false
 }
 
+// IsSeriesLimitExceededError determines if the error is a write rejected
+// because it would have created a new series beyond the series limits.
+func IsSeriesLimitExceededError(err error) bool {
+	for err != nil {
+		if e, ok := err.(*rpc.Error); ok && tterrors.IsSeriesLimitExceededError(e) {
+			return true
+		}
+		err = xerrors.InnerError(err)
+	}
+	return false
+}
+
 // IsConsistencyResultError determines if the error is a consistency result error.
 func IsConsistencyResultError(err error) bool {
 	_, ok := err.(consistencyResultErr)
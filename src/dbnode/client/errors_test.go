@@ -52,3 +52,23 @@ func TestConsistencyResultError(t *testing.T) {
 	assert.Equal(t, 1, NumSuccess(err))
 	assert.Equal(t, 2, NumError(err))
 }
+
+func TestConsistencyResultSeriesLimitExceededError(t *testing.T) {
+	seriesLimitErr := xerrors.NewRenamedError(&rpc.Error{
+		Type:  rpc.ErrorType_BAD_REQUEST,
+		Flags: int64(rpc.ErrorFlags_SERIES_LIMIT_EXCEEDED),
+	}, fmt.Errorf("renamed error"))
+
+	level := topology.ConsistencyLevelMajority
+	errs := []error{fmt.Errorf("another error"), seriesLimitErr}
+
+	err := error(newConsistencyResultError(level, 3, 3, errs))
+	assert.True(t, IsBadRequestError(err))
+	assert.True(t, IsSeriesLimitExceededError(err))
+
+	err = error(newConsistencyResultError(level, 3, 3, []error{&rpc.Error{
+		Type: rpc.ErrorType_BAD_REQUEST,
+	}}))
+	assert.True(t, IsBadRequestError(err))
+	assert.False(t, IsSeriesLimitExceededError(err))
+}
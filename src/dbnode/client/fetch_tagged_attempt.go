@@ -21,8 +21,11 @@
 package client
 
 import (
+	"context"
+
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xretry "github.com/m3db/m3/src/x/retry"
@@ -45,6 +48,7 @@ type fetchTaggedAttempt struct {
 }
 
 type fetchTaggedAttemptArgs struct {
+	ctx   context.Context
 	ns    ident.ID
 	query index.Query
 	opts  index.QueryOptions
@@ -62,14 +66,22 @@ func (f *fetchTaggedAttempt) reset() {
 func (f *fetchTaggedAttempt) performIDsAttempt() error {
 	var err error
 	f.idsResultIter, f.idsResultExhaustive, err = f.session.fetchTaggedIDsAttempt(
-		f.args.ns, f.args.query, f.args.opts)
+		f.args.ctx, f.args.ns, f.args.query, f.args.opts)
+	if err != nil && f.args.ctx.Err() != nil {
+		// NB: do not retry fetches once the request has been cancelled.
+		return xerrors.NewNonRetryableError(f.args.ctx.Err())
+	}
 	return err
 }
 
 func (f *fetchTaggedAttempt) performDataAttempt() error {
 	var err error
 	f.dataResultIters, f.dataResultExhaustive, err = f.session.fetchTaggedAttempt(
-		f.args.ns, f.args.query, f.args.opts)
+		f.args.ctx, f.args.ns, f.args.query, f.args.opts)
+	if err != nil && f.args.ctx.Err() != nil {
+		// NB: do not retry fetches once the request has been cancelled.
+		return xerrors.NewNonRetryableError(f.args.ctx.Err())
+	}
 	return err
 }
 
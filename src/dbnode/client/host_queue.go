@@ -21,6 +21,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	xsync "github.com/m3db/m3/src/x/sync"
 
 	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go"
 	"github.com/uber/tchannel-go/thrift"
 )
 
@@ -277,6 +279,8 @@ func (q *queue) drain() {
 				q.asyncAggregate(v)
 			case *truncateOp:
 				q.asyncTruncate(v)
+			case *cardinalityOp:
+				q.asyncCardinality(v)
 			default:
 				completionFn := ops[i].CompletionFn()
 				completionFn(nil, errQueueUnknownOperation(q.host.ID()))
@@ -510,8 +514,9 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		ctx, cancel := newRequestContext(op.ctx, q.opts.FetchRequestTimeout())
 		result, err := client.FetchTagged(ctx, &op.request)
+		cancel()
 		if err != nil {
 			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
@@ -543,8 +548,9 @@ func (q *queue) asyncAggregate(op *aggregateOp) {
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		ctx, cancel := newRequestContext(op.ctx, q.opts.FetchRequestTimeout())
 		result, err := client.AggregateRaw(ctx, &op.request)
+		cancel()
 		if err != nil {
 			op.CompletionFn()(aggregateResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
@@ -584,6 +590,31 @@ func (q *queue) asyncTruncate(op *truncateOp) {
 	})
 }
 
+func (q *queue) asyncCardinality(op *cardinalityOp) {
+	q.Add(1)
+
+	q.workerPool.Go(func() {
+		cleanup := q.Done
+
+		client, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(nil, err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		if res, err := client.Cardinality(ctx, &op.request); err != nil {
+			op.completionFn(nil, err)
+		} else {
+			op.completionFn(res, nil)
+		}
+
+		cleanup()
+	})
+}
+
 func (q *queue) Len() int {
 	q.RLock()
 	v := q.opsSumSize
@@ -795,3 +826,15 @@ func (s namespaceWriteTaggedBatchOpsSlice) resetAt(
 	s[index].ops = nil
 	s[index].elems = nil
 }
+
+// newRequestContext returns a thrift context with the given timeout, which is
+// also cancelled when the parent context is cancelled if one is provided.
+func newRequestContext(
+	parent context.Context,
+	timeout time.Duration,
+) (thrift.Context, context.CancelFunc) {
+	if parent == nil {
+		return thrift.NewContext(timeout)
+	}
+	return tchannel.NewContextBuilder(timeout).SetParentContext(parent).Build()
+}
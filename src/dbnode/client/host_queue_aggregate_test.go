@@ -21,6 +21,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -157,6 +158,69 @@ func TestHostQueueAggregateErrorOnAggregateError(t *testing.T) {
 	})
 }
 
+func TestHostQueueAggregateCancelledContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnPool := NewMockconnectionPool(ctrl)
+
+	opts := newHostQueueTestOptions().
+		SetHostQueueOpsFlushInterval(time.Millisecond)
+	queue := newTestHostQueue(opts)
+	queue.connPool = mockConnPool
+
+	// Open
+	mockConnPool.EXPECT().Open()
+	queue.Open()
+	assert.Equal(t, statusOpen, queue.status)
+
+	// Prepare callback for aggregates
+	var (
+		results []hostQueueResult
+		wg      sync.WaitGroup
+	)
+	callback := func(r interface{}, err error) {
+		results = append(results, hostQueueResult{r, err})
+		wg.Done()
+	}
+
+	// Prepare aggregate op with an already cancelled query context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	aggregate := testAggregateOp("testNs", callback)
+	aggregate.ctx = ctx
+	wg.Add(1)
+
+	// The request context handed to the host must be cancelled too
+	mockClient := rpc.NewMockTChanNode(ctrl)
+	aggregateExec := func(ctx thrift.Context, req *rpc.AggregateQueryRawRequest) {
+		assert.Equal(t, context.Canceled, ctx.Err())
+	}
+	mockClient.EXPECT().
+		AggregateRaw(gomock.Any(), gomock.Any()).
+		Do(aggregateExec).
+		Return(nil, context.Canceled)
+	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
+
+	// Aggregate
+	assert.NoError(t, queue.Enqueue(aggregate))
+
+	// Wait for aggregate to complete
+	wg.Wait()
+
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, context.Canceled, results[0].err)
+
+	// Close
+	var closeWg sync.WaitGroup
+	closeWg.Add(1)
+	mockConnPool.EXPECT().Close().Do(func() {
+		closeWg.Done()
+	})
+	queue.Close()
+	closeWg.Wait()
+}
+
 type testHostQueueAggregateOptions struct {
 	nextClientErr error
 	aggregateErr  error
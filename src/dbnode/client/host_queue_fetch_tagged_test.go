@@ -21,6 +21,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -157,6 +158,69 @@ func TestHostQueueFetchTaggedErrorOnFetchTaggedError(t *testing.T) {
 	})
 }
 
+func TestHostQueueFetchTaggedCancelledContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnPool := NewMockconnectionPool(ctrl)
+
+	opts := newHostQueueTestOptions().
+		SetHostQueueOpsFlushInterval(time.Millisecond)
+	queue := newTestHostQueue(opts)
+	queue.connPool = mockConnPool
+
+	// Open
+	mockConnPool.EXPECT().Open()
+	queue.Open()
+	assert.Equal(t, statusOpen, queue.status)
+
+	// Prepare callback for fetches
+	var (
+		results []hostQueueResult
+		wg      sync.WaitGroup
+	)
+	callback := func(r interface{}, err error) {
+		results = append(results, hostQueueResult{r, err})
+		wg.Done()
+	}
+
+	// Prepare fetch op with an already cancelled query context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetchTagged := testFetchTaggedOp("testNs", callback)
+	fetchTagged.ctx = ctx
+	wg.Add(1)
+
+	// The request context handed to the host must be cancelled too
+	mockClient := rpc.NewMockTChanNode(ctrl)
+	fetchTaggedExec := func(ctx thrift.Context, req *rpc.FetchTaggedRequest) {
+		assert.Equal(t, context.Canceled, ctx.Err())
+	}
+	mockClient.EXPECT().
+		FetchTagged(gomock.Any(), gomock.Any()).
+		Do(fetchTaggedExec).
+		Return(nil, context.Canceled)
+	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
+
+	// Fetch
+	assert.NoError(t, queue.Enqueue(fetchTagged))
+
+	// Wait for fetch to complete
+	wg.Wait()
+
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, context.Canceled, results[0].err)
+
+	// Close
+	var closeWg sync.WaitGroup
+	closeWg.Add(1)
+	mockConnPool.EXPECT().Close().Do(func() {
+		closeWg.Done()
+	})
+	queue.Close()
+	closeWg.Wait()
+}
+
 type testHostQueueFetchTaggedOptions struct {
 	nextClientErr  error
 	fetchTaggedErr error
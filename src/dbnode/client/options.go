@@ -58,6 +58,18 @@ const (
 	// defaultBootstrapConsistencyLevel is the default bootstrap consistency level
 	defaultBootstrapConsistencyLevel = m3dbruntime.DefaultBootstrapConsistencyLevel
 
+	// defaultTransportType is the default transport type
+	defaultTransportType = TChannelTransportType
+
+	// defaultGRPCMaxCallRecvMsgSize is the default max size of a message
+	// received over gRPC, well above the gRPC default of 4mb since fetch
+	// and aggregate responses regularly exceed it.
+	defaultGRPCMaxCallRecvMsgSize = 64 * 1024 * 1024
+
+	// defaultGRPCMaxCallSendMsgSize is the default max size of a message
+	// sent over gRPC.
+	defaultGRPCMaxCallSendMsgSize = 64 * 1024 * 1024
+
 	// defaultMaxConnectionCount is the default max connection count
 	defaultMaxConnectionCount = 32
 
@@ -207,6 +219,10 @@ type options struct {
 	writeConsistencyLevel                   topology.ConsistencyLevel
 	bootstrapConsistencyLevel               topology.ReadConsistencyLevel
 	channelOptions                          *tchannel.ChannelOptions
+	transportType                           TransportType
+	grpcPort                                int
+	grpcMaxCallRecvMsgSize                  int
+	grpcMaxCallSendMsgSize                  int
 	maxConnectionCount                      int
 	minConnectionCount                      int
 	hostConnectTimeout                      time.Duration
@@ -303,6 +319,9 @@ func newOptions() *options {
 		writeConsistencyLevel:                   defaultWriteConsistencyLevel,
 		readConsistencyLevel:                    defaultReadConsistencyLevel,
 		bootstrapConsistencyLevel:               defaultBootstrapConsistencyLevel,
+		transportType:                           defaultTransportType,
+		grpcMaxCallRecvMsgSize:                  defaultGRPCMaxCallRecvMsgSize,
+		grpcMaxCallSendMsgSize:                  defaultGRPCMaxCallSendMsgSize,
 		maxConnectionCount:                      defaultMaxConnectionCount,
 		minConnectionCount:                      defaultMinConnectionCount,
 		hostConnectTimeout:                      defaultHostConnectTimeout,
@@ -482,6 +501,46 @@ func (o *options) ChannelOptions() *tchannel.ChannelOptions {
 	return o.channelOptions
 }
 
+func (o *options) SetTransportType(value TransportType) Options {
+	opts := *o
+	opts.transportType = value
+	return &opts
+}
+
+func (o *options) TransportType() TransportType {
+	return o.transportType
+}
+
+func (o *options) SetGRPCPort(value int) Options {
+	opts := *o
+	opts.grpcPort = value
+	return &opts
+}
+
+func (o *options) GRPCPort() int {
+	return o.grpcPort
+}
+
+func (o *options) SetGRPCMaxCallRecvMsgSize(value int) Options {
+	opts := *o
+	opts.grpcMaxCallRecvMsgSize = value
+	return &opts
+}
+
+func (o *options) GRPCMaxCallRecvMsgSize() int {
+	return o.grpcMaxCallRecvMsgSize
+}
+
+func (o *options) SetGRPCMaxCallSendMsgSize(value int) Options {
+	opts := *o
+	opts.grpcMaxCallSendMsgSize = value
+	return &opts
+}
+
+func (o *options) GRPCMaxCallSendMsgSize() int {
+	return o.grpcMaxCallSendMsgSize
+}
+
 func (o *options) SetMaxConnectionCount(value int) Options {
 	opts := *o
 	opts.maxConnectionCount = value
@@ -21,6 +21,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -227,16 +228,36 @@ func (s replicatedSession) Aggregate(
 	return s.session.Aggregate(ns, q, opts)
 }
 
+// AggregateWithContext aggregates values from the database for the given set of constraints,
+// cancelling the requests in flight when the context is cancelled.
+func (s replicatedSession) AggregateWithContext(
+	ctx context.Context, ns ident.ID, q index.Query, opts index.AggregationOptions,
+) (AggregatedTagsIterator, bool, error) {
+	return s.session.AggregateWithContext(ctx, ns, q, opts)
+}
+
 // FetchTagged resolves the provided query to known IDs, and fetches the data for them.
 func (s replicatedSession) FetchTagged(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error) {
 	return s.session.FetchTagged(namespace, q, opts)
 }
 
+// FetchTaggedWithContext resolves the provided query to known IDs, and fetches the data for them,
+// cancelling the requests in flight when the context is cancelled.
+func (s replicatedSession) FetchTaggedWithContext(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error) {
+	return s.session.FetchTaggedWithContext(ctx, namespace, q, opts)
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s replicatedSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error) {
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
+// FetchTaggedIDsWithContext resolves the provided query to known IDs, cancelling
+// the requests in flight when the context is cancelled.
+func (s replicatedSession) FetchTaggedIDsWithContext(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error) {
+	return s.session.FetchTaggedIDsWithContext(ctx, namespace, q, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -283,6 +304,15 @@ func (s replicatedSession) Truncate(namespace ident.ID) (int64, error) {
 	return s.session.Truncate(namespace)
 }
 
+// Cardinality returns the number of series per tag name and value known
+// to the index of the namespace across all hosts.
+func (s replicatedSession) Cardinality(
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityQueryResult, error) {
+	return s.session.Cardinality(namespace, opts)
+}
+
 // FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 // for each series using the runtime configurable bootstrap level consistency.
 func (s replicatedSession) FetchBootstrapBlocksFromPeers(
@@ -22,6 +22,7 @@ package client
 
 import (
 	"bytes"
+	stdctx "context"
 	"errors"
 	"fmt"
 	"math"
@@ -1121,8 +1122,15 @@ func (s *session) FetchIDs(
 
 func (s *session) Aggregate(
 	ns ident.ID, q index.Query, opts index.AggregationOptions,
+) (AggregatedTagsIterator, bool, error) {
+	return s.AggregateWithContext(stdctx.Background(), ns, q, opts)
+}
+
+func (s *session) AggregateWithContext(
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.AggregationOptions,
 ) (AggregatedTagsIterator, bool, error) {
 	f := s.pools.aggregateAttempt.Get()
+	f.args.ctx = ctx
 	f.args.ns = ns
 	f.args.query = q
 	f.args.opts = opts
@@ -1133,8 +1141,11 @@ func (s *session) Aggregate(
 }
 
 func (s *session) aggregateAttempt(
-	ns ident.ID, q index.Query, opts index.AggregationOptions,
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.AggregationOptions,
 ) (AggregatedTagsIterator, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, xerrors.NewNonRetryableError(err)
+	}
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
@@ -1154,6 +1165,7 @@ func (s *session) aggregateAttempt(
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
 		stateType:        aggregateFetchState,
+		ctx:              ctx,
 		aggregateRequest: req,
 		startInclusive:   opts.StartInclusive,
 		endExclusive:     opts.EndExclusive,
@@ -1182,8 +1194,15 @@ func (s *session) aggregateAttempt(
 
 func (s *session) FetchTagged(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (encoding.SeriesIterators, bool, error) {
+	return s.FetchTaggedWithContext(stdctx.Background(), ns, q, opts)
+}
+
+func (s *session) FetchTaggedWithContext(
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.QueryOptions,
 ) (encoding.SeriesIterators, bool, error) {
 	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ctx = ctx
 	f.args.ns = ns
 	f.args.query = q
 	f.args.opts = opts
@@ -1195,8 +1214,15 @@ func (s *session) FetchTagged(
 
 func (s *session) FetchTaggedIDs(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (TaggedIDsIterator, bool, error) {
+	return s.FetchTaggedIDsWithContext(stdctx.Background(), ns, q, opts)
+}
+
+func (s *session) FetchTaggedIDsWithContext(
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.QueryOptions,
 ) (TaggedIDsIterator, bool, error) {
 	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ctx = ctx
 	f.args.ns = ns
 	f.args.query = q
 	f.args.opts = opts
@@ -1207,8 +1233,11 @@ func (s *session) FetchTaggedIDs(
 }
 
 func (s *session) fetchTaggedAttempt(
-	ns ident.ID, q index.Query, opts index.QueryOptions,
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.QueryOptions,
 ) (encoding.SeriesIterators, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, xerrors.NewNonRetryableError(err)
+	}
 	nsCtx, err := s.nsCtxFor(ns)
 	if err != nil {
 		return nil, false, err
@@ -1237,6 +1266,7 @@ func (s *session) fetchTaggedAttempt(
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
 		stateType:          fetchTaggedFetchState,
+		ctx:                ctx,
 		fetchTaggedRequest: req,
 		startInclusive:     opts.StartInclusive,
 		endExclusive:       opts.EndExclusive,
@@ -1264,8 +1294,11 @@ func (s *session) fetchTaggedAttempt(
 }
 
 func (s *session) fetchTaggedIDsAttempt(
-	ns ident.ID, q index.Query, opts index.QueryOptions,
+	ctx stdctx.Context, ns ident.ID, q index.Query, opts index.QueryOptions,
 ) (TaggedIDsIterator, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, xerrors.NewNonRetryableError(err)
+	}
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
@@ -1290,6 +1323,7 @@ func (s *session) fetchTaggedIDsAttempt(
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
 		stateType:          fetchTaggedFetchState,
+		ctx:                ctx,
 		fetchTaggedRequest: req,
 		startInclusive:     opts.StartInclusive,
 		endExclusive:       opts.EndExclusive,
@@ -1321,6 +1355,9 @@ type newFetchStateOpts struct {
 	startInclusive time.Time
 	endExclusive   time.Time
 
+	// ctx cancels the requests in flight to the hosts, it may be nil
+	ctx stdctx.Context
+
 	// only valid if stateType == fetchTaggedFetchState
 	fetchTaggedRequest rpc.FetchTaggedRequest
 
@@ -1353,7 +1390,7 @@ func (s *session) newFetchStateWithRLock(
 		fetchOp := s.pools.fetchTaggedOp.Get()
 		fetchOp.incRef()        // indicate current go-routine has a reference to the op
 		closer = fetchOp.decRef // release the ref for the current go-routine
-		fetchOp.update(opts.fetchTaggedRequest, fetchState.completionFn)
+		fetchOp.update(opts.ctx, opts.fetchTaggedRequest, fetchState.completionFn)
 		fetchState.ResetFetchTagged(opts.startInclusive, opts.endExclusive,
 			fetchOp, topoMap, s.state.majority, s.state.readLevel)
 		op = fetchOp
@@ -1362,7 +1399,7 @@ func (s *session) newFetchStateWithRLock(
 		aggOp := s.pools.aggregateOp.Get()
 		aggOp.incRef()        // indicate current go-routine has a reference to the op
 		closer = aggOp.decRef // release the ref for the current go-routine
-		aggOp.update(opts.aggregateRequest, fetchState.completionFn)
+		aggOp.update(opts.ctx, opts.aggregateRequest, fetchState.completionFn)
 		fetchState.ResetAggregate(opts.startInclusive, opts.endExclusive,
 			aggOp, topoMap, s.state.majority, s.state.readLevel)
 		op = aggOp
@@ -1815,6 +1852,59 @@ func (s *session) Truncate(namespace ident.ID) (int64, error) {
 	return truncated, resultErr.FinalError()
 }
 
+func (s *session) Cardinality(
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityQueryResult, error) {
+	req, err := convert.ToRPCCardinalityRequest(namespace, opts)
+	if err != nil {
+		return index.CardinalityQueryResult{}, err
+	}
+
+	var (
+		wg            sync.WaitGroup
+		enqueueErr    xerrors.MultiError
+		resultErrLock sync.Mutex
+		resultErr     xerrors.MultiError
+	)
+
+	s.state.RLock()
+	acc := newCardinalityResultAccumulator(s.state.replicas)
+	c := &cardinalityOp{request: req}
+	c.completionFn = func(result interface{}, err error) {
+		if err != nil {
+			resultErrLock.Lock()
+			resultErr = resultErr.Add(err)
+			resultErrLock.Unlock()
+		} else {
+			acc.add(result.(*rpc.CardinalityResult_))
+		}
+		wg.Done()
+	}
+
+	for idx := range s.state.queues {
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(c); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return index.CardinalityQueryResult{}, err
+	}
+
+	// Wait for all hosts to respond since each only owns a subset of shards.
+	wg.Wait()
+
+	if err := resultErr.FinalError(); err != nil {
+		return index.CardinalityQueryResult{}, err
+	}
+	return acc.result(opts.TopN), nil
+}
+
 // NB(r): Excluding maligned struct check here as we can
 // live with a few extra bytes since this struct is only
 // ever passed by stack, its much more readable not optimized
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TransportType is the transport used by the client to talk to nodes.
+type TransportType int
+
+const (
+	// TChannelTransportType uses TChannel and Thrift to talk to nodes.
+	TChannelTransportType TransportType = iota
+
+	// GRPCTransportType uses gRPC to talk to nodes for the requests served
+	// by the gRPC node service and TChannel for the rest, nodes must have
+	// the gRPC node service enabled.
+	GRPCTransportType
+)
+
+var (
+	validTransportTypes = []TransportType{
+		TChannelTransportType,
+		GRPCTransportType,
+	}
+
+	errTransportTypeUnspecified = errors.New("transport type not specified")
+)
+
+func (t TransportType) String() string {
+	switch t {
+	case TChannelTransportType:
+		return "tchannel"
+	case GRPCTransportType:
+		return "grpc"
+	}
+	return "unknown"
+}
+
+// UnmarshalYAML unmarshals a TransportType into a valid type from string.
+func (t *TransportType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		return errTransportTypeUnspecified
+	}
+	strs := make([]string, 0, len(validTransportTypes))
+	for _, valid := range validTransportTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+		strs = append(strs, "'"+valid.String()+"'")
+	}
+	return fmt.Errorf("invalid TransportType '%s' valid types are: %s",
+		str, strings.Join(strs, ", "))
+}
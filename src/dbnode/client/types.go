@@ -21,6 +21,7 @@
 package client
 
 import (
+	stdctx "context"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
@@ -76,12 +77,24 @@ type Session interface {
 	// FetchTagged resolves the provided query to known IDs, and fetches the data for them.
 	FetchTagged(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error)
 
+	// FetchTaggedWithContext is the same as FetchTagged, except requests in
+	// flight to the hosts are cancelled when the context is cancelled.
+	FetchTaggedWithContext(ctx stdctx.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error)
+
 	// FetchTaggedIDs resolves the provided query to known IDs.
 	FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error)
 
+	// FetchTaggedIDsWithContext is the same as FetchTaggedIDs, except requests
+	// in flight to the hosts are cancelled when the context is cancelled.
+	FetchTaggedIDsWithContext(ctx stdctx.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error)
+
 	// Aggregate aggregates values from the database for the given set of constraints.
 	Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (iter AggregatedTagsIterator, exhaustive bool, err error)
 
+	// AggregateWithContext is the same as Aggregate, except requests in
+	// flight to the hosts are cancelled when the context is cancelled.
+	AggregateWithContext(ctx stdctx.Context, namespace ident.ID, q index.Query, opts index.AggregationOptions) (iter AggregatedTagsIterator, exhaustive bool, err error)
+
 	// ShardID returns the given shard for an ID for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing.
@@ -187,6 +200,13 @@ type AdminSession interface {
 	// Truncate will truncate the namespace for a given shard.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// Cardinality returns the number of series per tag name and value known
+	// to the index of the namespace across all hosts.
+	Cardinality(
+		namespace ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityQueryResult, error)
+
 	// FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 	// for each series using the runtime configurable bootstrap level consistency.
 	FetchBootstrapBlocksFromPeers(
@@ -282,6 +302,36 @@ type Options interface {
 	// ChannelOptions returns the channelOptions.
 	ChannelOptions() *tchannel.ChannelOptions
 
+	// SetTransportType sets the transport used to talk to nodes.
+	SetTransportType(value TransportType) Options
+
+	// TransportType returns the transport used to talk to nodes.
+	TransportType() TransportType
+
+	// SetGRPCPort sets the port of the gRPC node service, which must be set
+	// when using the gRPC transport.
+	SetGRPCPort(value int) Options
+
+	// GRPCPort returns the port of the gRPC node service, which must be set
+	// when using the gRPC transport.
+	GRPCPort() int
+
+	// SetGRPCMaxCallRecvMsgSize sets the max size in bytes of a message
+	// received from the gRPC node service.
+	SetGRPCMaxCallRecvMsgSize(value int) Options
+
+	// GRPCMaxCallRecvMsgSize returns the max size in bytes of a message
+	// received from the gRPC node service.
+	GRPCMaxCallRecvMsgSize() int
+
+	// SetGRPCMaxCallSendMsgSize sets the max size in bytes of a message
+	// sent to the gRPC node service.
+	SetGRPCMaxCallSendMsgSize(value int) Options
+
+	// GRPCMaxCallSendMsgSize returns the max size in bytes of a message
+	// sent to the gRPC node service.
+	GRPCMaxCallSendMsgSize() int
+
 	// SetMaxConnectionCount sets the maxConnectionCount.
 	SetMaxConnectionCount(value int) Options
 
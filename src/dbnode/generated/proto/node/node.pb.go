@@ -0,0 +1,5004 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: github.com/m3db/m3/src/dbnode/generated/proto/node/node.proto
+
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+/*
+	Package node is a generated protocol buffer package.
+
+	It is generated from these files:
+		github.com/m3db/m3/src/dbnode/generated/proto/node/node.proto
+
+	It has these top-level messages:
+		HealthRequest
+		HealthResponse
+		Datapoint
+		Tag
+		WriteRequest
+		WriteTaggedRequest
+		WriteResponse
+		FetchTaggedRequest
+		FetchTaggedResponse
+		FetchTaggedIDResult
+		Segments
+		Segment
+		Error
+		AggregateRequest
+		AggregateResponse
+		AggregateTagNameElement
+		WriteBatchRequest
+		WriteBatchRequestElement
+		WriteBatchResponse
+		WriteBatchError
+*/
+package node
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import context "golang.org/x/net/context"
+import grpc "google.golang.org/grpc"
+
+import binary "encoding/binary"
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type TimeType int32
+
+const (
+	TimeType_UNIX_SECONDS      TimeType = 0
+	TimeType_UNIX_MICROSECONDS TimeType = 1
+	TimeType_UNIX_MILLISECONDS TimeType = 2
+	TimeType_UNIX_NANOSECONDS  TimeType = 3
+)
+
+var TimeType_name = map[int32]string{
+	0: "UNIX_SECONDS",
+	1: "UNIX_MICROSECONDS",
+	2: "UNIX_MILLISECONDS",
+	3: "UNIX_NANOSECONDS",
+}
+var TimeType_value = map[string]int32{
+	"UNIX_SECONDS":      0,
+	"UNIX_MICROSECONDS": 1,
+	"UNIX_MILLISECONDS": 2,
+	"UNIX_NANOSECONDS":  3,
+}
+
+func (x TimeType) String() string {
+	return proto.EnumName(TimeType_name, int32(x))
+}
+func (TimeType) EnumDescriptor() ([]byte, []int) { return fileDescriptorNode, []int{0} }
+
+type ErrorType int32
+
+const (
+	ErrorType_INTERNAL_ERROR ErrorType = 0
+	ErrorType_BAD_REQUEST    ErrorType = 1
+)
+
+var ErrorType_name = map[int32]string{
+	0: "INTERNAL_ERROR",
+	1: "BAD_REQUEST",
+}
+var ErrorType_value = map[string]int32{
+	"INTERNAL_ERROR": 0,
+	"BAD_REQUEST":    1,
+}
+
+func (x ErrorType) String() string {
+	return proto.EnumName(ErrorType_name, int32(x))
+}
+func (ErrorType) EnumDescriptor() ([]byte, []int) { return fileDescriptorNode, []int{1} }
+
+type AggregateQueryType int32
+
+const (
+	AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE AggregateQueryType = 0
+	AggregateQueryType_AGGREGATE_BY_TAG_NAME       AggregateQueryType = 1
+)
+
+var AggregateQueryType_name = map[int32]string{
+	0: "AGGREGATE_BY_TAG_NAME_VALUE",
+	1: "AGGREGATE_BY_TAG_NAME",
+}
+var AggregateQueryType_value = map[string]int32{
+	"AGGREGATE_BY_TAG_NAME_VALUE": 0,
+	"AGGREGATE_BY_TAG_NAME":       1,
+}
+
+func (x AggregateQueryType) String() string {
+	return proto.EnumName(AggregateQueryType_name, int32(x))
+}
+func (AggregateQueryType) EnumDescriptor() ([]byte, []int) { return fileDescriptorNode, []int{2} }
+
+type HealthRequest struct {
+}
+
+func (m *HealthRequest) Reset()                    { *m = HealthRequest{} }
+func (m *HealthRequest) String() string            { return proto.CompactTextString(m) }
+func (*HealthRequest) ProtoMessage()               {}
+func (*HealthRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{0} }
+
+type HealthResponse struct {
+	Ok           bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Status       string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Bootstrapped bool   `protobuf:"varint,3,opt,name=bootstrapped,proto3" json:"bootstrapped,omitempty"`
+}
+
+func (m *HealthResponse) Reset()                    { *m = HealthResponse{} }
+func (m *HealthResponse) String() string            { return proto.CompactTextString(m) }
+func (*HealthResponse) ProtoMessage()               {}
+func (*HealthResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{1} }
+
+func (m *HealthResponse) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+func (m *HealthResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *HealthResponse) GetBootstrapped() bool {
+	if m != nil {
+		return m.Bootstrapped
+	}
+	return false
+}
+
+type Datapoint struct {
+	Timestamp         int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value             float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Annotation        []byte   `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	TimestampTimeType TimeType `protobuf:"varint,4,opt,name=timestampTimeType,proto3,enum=node.TimeType" json:"timestampTimeType,omitempty"`
+}
+
+func (m *Datapoint) Reset()                    { *m = Datapoint{} }
+func (m *Datapoint) String() string            { return proto.CompactTextString(m) }
+func (*Datapoint) ProtoMessage()               {}
+func (*Datapoint) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{2} }
+
+func (m *Datapoint) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Datapoint) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Datapoint) GetAnnotation() []byte {
+	if m != nil {
+		return m.Annotation
+	}
+	return nil
+}
+
+func (m *Datapoint) GetTimestampTimeType() TimeType {
+	if m != nil {
+		return m.TimestampTimeType
+	}
+	return TimeType_UNIX_SECONDS
+}
+
+type Tag struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *Tag) Reset()                    { *m = Tag{} }
+func (m *Tag) String() string            { return proto.CompactTextString(m) }
+func (*Tag) ProtoMessage()               {}
+func (*Tag) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{3} }
+
+func (m *Tag) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Tag) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type WriteRequest struct {
+	NameSpace string     `protobuf:"bytes,1,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	Id        string     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Datapoint *Datapoint `protobuf:"bytes,3,opt,name=datapoint" json:"datapoint,omitempty"`
+}
+
+func (m *WriteRequest) Reset()                    { *m = WriteRequest{} }
+func (m *WriteRequest) String() string            { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()               {}
+func (*WriteRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{4} }
+
+func (m *WriteRequest) GetNameSpace() string {
+	if m != nil {
+		return m.NameSpace
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetDatapoint() *Datapoint {
+	if m != nil {
+		return m.Datapoint
+	}
+	return nil
+}
+
+type WriteTaggedRequest struct {
+	NameSpace string     `protobuf:"bytes,1,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	Id        string     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Tags      []*Tag     `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"`
+	Datapoint *Datapoint `protobuf:"bytes,4,opt,name=datapoint" json:"datapoint,omitempty"`
+}
+
+func (m *WriteTaggedRequest) Reset()                    { *m = WriteTaggedRequest{} }
+func (m *WriteTaggedRequest) String() string            { return proto.CompactTextString(m) }
+func (*WriteTaggedRequest) ProtoMessage()               {}
+func (*WriteTaggedRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{5} }
+
+func (m *WriteTaggedRequest) GetNameSpace() string {
+	if m != nil {
+		return m.NameSpace
+	}
+	return ""
+}
+
+func (m *WriteTaggedRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WriteTaggedRequest) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *WriteTaggedRequest) GetDatapoint() *Datapoint {
+	if m != nil {
+		return m.Datapoint
+	}
+	return nil
+}
+
+type WriteResponse struct {
+}
+
+func (m *WriteResponse) Reset()                    { *m = WriteResponse{} }
+func (m *WriteResponse) String() string            { return proto.CompactTextString(m) }
+func (*WriteResponse) ProtoMessage()               {}
+func (*WriteResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{6} }
+
+type FetchTaggedRequest struct {
+	NameSpace     []byte   `protobuf:"bytes,1,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	Query         []byte   `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	RangeStart    int64    `protobuf:"varint,3,opt,name=rangeStart,proto3" json:"rangeStart,omitempty"`
+	RangeEnd      int64    `protobuf:"varint,4,opt,name=rangeEnd,proto3" json:"rangeEnd,omitempty"`
+	FetchData     bool     `protobuf:"varint,5,opt,name=fetchData,proto3" json:"fetchData,omitempty"`
+	Limit         int64    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	RangeTimeType TimeType `protobuf:"varint,7,opt,name=rangeTimeType,proto3,enum=node.TimeType" json:"rangeTimeType,omitempty"`
+}
+
+func (m *FetchTaggedRequest) Reset()                    { *m = FetchTaggedRequest{} }
+func (m *FetchTaggedRequest) String() string            { return proto.CompactTextString(m) }
+func (*FetchTaggedRequest) ProtoMessage()               {}
+func (*FetchTaggedRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{7} }
+
+func (m *FetchTaggedRequest) GetNameSpace() []byte {
+	if m != nil {
+		return m.NameSpace
+	}
+	return nil
+}
+
+func (m *FetchTaggedRequest) GetQuery() []byte {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+func (m *FetchTaggedRequest) GetRangeStart() int64 {
+	if m != nil {
+		return m.RangeStart
+	}
+	return 0
+}
+
+func (m *FetchTaggedRequest) GetRangeEnd() int64 {
+	if m != nil {
+		return m.RangeEnd
+	}
+	return 0
+}
+
+func (m *FetchTaggedRequest) GetFetchData() bool {
+	if m != nil {
+		return m.FetchData
+	}
+	return false
+}
+
+func (m *FetchTaggedRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *FetchTaggedRequest) GetRangeTimeType() TimeType {
+	if m != nil {
+		return m.RangeTimeType
+	}
+	return TimeType_UNIX_SECONDS
+}
+
+type FetchTaggedResponse struct {
+	Elements   []*FetchTaggedIDResult `protobuf:"bytes,1,rep,name=elements" json:"elements,omitempty"`
+	Exhaustive bool                   `protobuf:"varint,2,opt,name=exhaustive,proto3" json:"exhaustive,omitempty"`
+}
+
+func (m *FetchTaggedResponse) Reset()                    { *m = FetchTaggedResponse{} }
+func (m *FetchTaggedResponse) String() string            { return proto.CompactTextString(m) }
+func (*FetchTaggedResponse) ProtoMessage()               {}
+func (*FetchTaggedResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{8} }
+
+func (m *FetchTaggedResponse) GetElements() []*FetchTaggedIDResult {
+	if m != nil {
+		return m.Elements
+	}
+	return nil
+}
+
+func (m *FetchTaggedResponse) GetExhaustive() bool {
+	if m != nil {
+		return m.Exhaustive
+	}
+	return false
+}
+
+type FetchTaggedIDResult struct {
+	Id          []byte      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NameSpace   []byte      `protobuf:"bytes,2,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	EncodedTags []byte      `protobuf:"bytes,3,opt,name=encodedTags,proto3" json:"encodedTags,omitempty"`
+	Segments    []*Segments `protobuf:"bytes,4,rep,name=segments" json:"segments,omitempty"`
+	Err         *Error      `protobuf:"bytes,5,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *FetchTaggedIDResult) Reset()                    { *m = FetchTaggedIDResult{} }
+func (m *FetchTaggedIDResult) String() string            { return proto.CompactTextString(m) }
+func (*FetchTaggedIDResult) ProtoMessage()               {}
+func (*FetchTaggedIDResult) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{9} }
+
+func (m *FetchTaggedIDResult) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *FetchTaggedIDResult) GetNameSpace() []byte {
+	if m != nil {
+		return m.NameSpace
+	}
+	return nil
+}
+
+func (m *FetchTaggedIDResult) GetEncodedTags() []byte {
+	if m != nil {
+		return m.EncodedTags
+	}
+	return nil
+}
+
+func (m *FetchTaggedIDResult) GetSegments() []*Segments {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *FetchTaggedIDResult) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+type Segments struct {
+	Merged   *Segment   `protobuf:"bytes,1,opt,name=merged" json:"merged,omitempty"`
+	Unmerged []*Segment `protobuf:"bytes,2,rep,name=unmerged" json:"unmerged,omitempty"`
+}
+
+func (m *Segments) Reset()                    { *m = Segments{} }
+func (m *Segments) String() string            { return proto.CompactTextString(m) }
+func (*Segments) ProtoMessage()               {}
+func (*Segments) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{10} }
+
+func (m *Segments) GetMerged() *Segment {
+	if m != nil {
+		return m.Merged
+	}
+	return nil
+}
+
+func (m *Segments) GetUnmerged() []*Segment {
+	if m != nil {
+		return m.Unmerged
+	}
+	return nil
+}
+
+type Segment struct {
+	Head      []byte `protobuf:"bytes,1,opt,name=head,proto3" json:"head,omitempty"`
+	Tail      []byte `protobuf:"bytes,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	StartTime int64  `protobuf:"varint,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	BlockSize int64  `protobuf:"varint,4,opt,name=blockSize,proto3" json:"blockSize,omitempty"`
+}
+
+func (m *Segment) Reset()                    { *m = Segment{} }
+func (m *Segment) String() string            { return proto.CompactTextString(m) }
+func (*Segment) ProtoMessage()               {}
+func (*Segment) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{11} }
+
+func (m *Segment) GetHead() []byte {
+	if m != nil {
+		return m.Head
+	}
+	return nil
+}
+
+func (m *Segment) GetTail() []byte {
+	if m != nil {
+		return m.Tail
+	}
+	return nil
+}
+
+func (m *Segment) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *Segment) GetBlockSize() int64 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+type Error struct {
+	Type    ErrorType `protobuf:"varint,1,opt,name=type,proto3,enum=node.ErrorType" json:"type,omitempty"`
+	Message string    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Flags   int64     `protobuf:"varint,3,opt,name=flags,proto3" json:"flags,omitempty"`
+}
+
+func (m *Error) Reset()                    { *m = Error{} }
+func (m *Error) String() string            { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()               {}
+func (*Error) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{12} }
+
+func (m *Error) GetType() ErrorType {
+	if m != nil {
+		return m.Type
+	}
+	return ErrorType_INTERNAL_ERROR
+}
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Error) GetFlags() int64 {
+	if m != nil {
+		return m.Flags
+	}
+	return 0
+}
+
+type AggregateRequest struct {
+	Query              []byte             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	RangeStart         int64              `protobuf:"varint,2,opt,name=rangeStart,proto3" json:"rangeStart,omitempty"`
+	RangeEnd           int64              `protobuf:"varint,3,opt,name=rangeEnd,proto3" json:"rangeEnd,omitempty"`
+	NameSpace          []byte             `protobuf:"bytes,4,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	Limit              int64              `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	TagNameFilter      [][]byte           `protobuf:"bytes,6,rep,name=tagNameFilter" json:"tagNameFilter,omitempty"`
+	AggregateQueryType AggregateQueryType `protobuf:"varint,7,opt,name=aggregateQueryType,proto3,enum=node.AggregateQueryType" json:"aggregateQueryType,omitempty"`
+	RangeTimeType      TimeType           `protobuf:"varint,8,opt,name=rangeTimeType,proto3,enum=node.TimeType" json:"rangeTimeType,omitempty"`
+}
+
+func (m *AggregateRequest) Reset()                    { *m = AggregateRequest{} }
+func (m *AggregateRequest) String() string            { return proto.CompactTextString(m) }
+func (*AggregateRequest) ProtoMessage()               {}
+func (*AggregateRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{13} }
+
+func (m *AggregateRequest) GetQuery() []byte {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+func (m *AggregateRequest) GetRangeStart() int64 {
+	if m != nil {
+		return m.RangeStart
+	}
+	return 0
+}
+
+func (m *AggregateRequest) GetRangeEnd() int64 {
+	if m != nil {
+		return m.RangeEnd
+	}
+	return 0
+}
+
+func (m *AggregateRequest) GetNameSpace() []byte {
+	if m != nil {
+		return m.NameSpace
+	}
+	return nil
+}
+
+func (m *AggregateRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AggregateRequest) GetTagNameFilter() [][]byte {
+	if m != nil {
+		return m.TagNameFilter
+	}
+	return nil
+}
+
+func (m *AggregateRequest) GetAggregateQueryType() AggregateQueryType {
+	if m != nil {
+		return m.AggregateQueryType
+	}
+	return AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE
+}
+
+func (m *AggregateRequest) GetRangeTimeType() TimeType {
+	if m != nil {
+		return m.RangeTimeType
+	}
+	return TimeType_UNIX_SECONDS
+}
+
+type AggregateResponse struct {
+	Results    []*AggregateTagNameElement `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	Exhaustive bool                       `protobuf:"varint,2,opt,name=exhaustive,proto3" json:"exhaustive,omitempty"`
+}
+
+func (m *AggregateResponse) Reset()                    { *m = AggregateResponse{} }
+func (m *AggregateResponse) String() string            { return proto.CompactTextString(m) }
+func (*AggregateResponse) ProtoMessage()               {}
+func (*AggregateResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{14} }
+
+func (m *AggregateResponse) GetResults() []*AggregateTagNameElement {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *AggregateResponse) GetExhaustive() bool {
+	if m != nil {
+		return m.Exhaustive
+	}
+	return false
+}
+
+type AggregateTagNameElement struct {
+	TagName   []byte   `protobuf:"bytes,1,opt,name=tagName,proto3" json:"tagName,omitempty"`
+	TagValues [][]byte `protobuf:"bytes,2,rep,name=tagValues" json:"tagValues,omitempty"`
+}
+
+func (m *AggregateTagNameElement) Reset()                    { *m = AggregateTagNameElement{} }
+func (m *AggregateTagNameElement) String() string            { return proto.CompactTextString(m) }
+func (*AggregateTagNameElement) ProtoMessage()               {}
+func (*AggregateTagNameElement) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{15} }
+
+func (m *AggregateTagNameElement) GetTagName() []byte {
+	if m != nil {
+		return m.TagName
+	}
+	return nil
+}
+
+func (m *AggregateTagNameElement) GetTagValues() [][]byte {
+	if m != nil {
+		return m.TagValues
+	}
+	return nil
+}
+
+type WriteBatchRequest struct {
+	NameSpace []byte                      `protobuf:"bytes,1,opt,name=nameSpace,proto3" json:"nameSpace,omitempty"`
+	Elements  []*WriteBatchRequestElement `protobuf:"bytes,2,rep,name=elements" json:"elements,omitempty"`
+}
+
+func (m *WriteBatchRequest) Reset()                    { *m = WriteBatchRequest{} }
+func (m *WriteBatchRequest) String() string            { return proto.CompactTextString(m) }
+func (*WriteBatchRequest) ProtoMessage()               {}
+func (*WriteBatchRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{16} }
+
+func (m *WriteBatchRequest) GetNameSpace() []byte {
+	if m != nil {
+		return m.NameSpace
+	}
+	return nil
+}
+
+func (m *WriteBatchRequest) GetElements() []*WriteBatchRequestElement {
+	if m != nil {
+		return m.Elements
+	}
+	return nil
+}
+
+type WriteBatchRequestElement struct {
+	Id          []byte     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EncodedTags []byte     `protobuf:"bytes,2,opt,name=encodedTags,proto3" json:"encodedTags,omitempty"`
+	Datapoint   *Datapoint `protobuf:"bytes,3,opt,name=datapoint" json:"datapoint,omitempty"`
+}
+
+func (m *WriteBatchRequestElement) Reset()                    { *m = WriteBatchRequestElement{} }
+func (m *WriteBatchRequestElement) String() string            { return proto.CompactTextString(m) }
+func (*WriteBatchRequestElement) ProtoMessage()               {}
+func (*WriteBatchRequestElement) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{17} }
+
+func (m *WriteBatchRequestElement) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *WriteBatchRequestElement) GetEncodedTags() []byte {
+	if m != nil {
+		return m.EncodedTags
+	}
+	return nil
+}
+
+func (m *WriteBatchRequestElement) GetDatapoint() *Datapoint {
+	if m != nil {
+		return m.Datapoint
+	}
+	return nil
+}
+
+type WriteBatchResponse struct {
+	Errors []*WriteBatchError `protobuf:"bytes,1,rep,name=errors" json:"errors,omitempty"`
+}
+
+func (m *WriteBatchResponse) Reset()                    { *m = WriteBatchResponse{} }
+func (m *WriteBatchResponse) String() string            { return proto.CompactTextString(m) }
+func (*WriteBatchResponse) ProtoMessage()               {}
+func (*WriteBatchResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{18} }
+
+func (m *WriteBatchResponse) GetErrors() []*WriteBatchError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type WriteBatchError struct {
+	Index int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Err   *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *WriteBatchError) Reset()                    { *m = WriteBatchError{} }
+func (m *WriteBatchError) String() string            { return proto.CompactTextString(m) }
+func (*WriteBatchError) ProtoMessage()               {}
+func (*WriteBatchError) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{19} }
+
+func (m *WriteBatchError) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *WriteBatchError) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*HealthRequest)(nil), "node.HealthRequest")
+	proto.RegisterType((*HealthResponse)(nil), "node.HealthResponse")
+	proto.RegisterType((*Datapoint)(nil), "node.Datapoint")
+	proto.RegisterType((*Tag)(nil), "node.Tag")
+	proto.RegisterType((*WriteRequest)(nil), "node.WriteRequest")
+	proto.RegisterType((*WriteTaggedRequest)(nil), "node.WriteTaggedRequest")
+	proto.RegisterType((*WriteResponse)(nil), "node.WriteResponse")
+	proto.RegisterType((*FetchTaggedRequest)(nil), "node.FetchTaggedRequest")
+	proto.RegisterType((*FetchTaggedResponse)(nil), "node.FetchTaggedResponse")
+	proto.RegisterType((*FetchTaggedIDResult)(nil), "node.FetchTaggedIDResult")
+	proto.RegisterType((*Segments)(nil), "node.Segments")
+	proto.RegisterType((*Segment)(nil), "node.Segment")
+	proto.RegisterType((*Error)(nil), "node.Error")
+	proto.RegisterType((*AggregateRequest)(nil), "node.AggregateRequest")
+	proto.RegisterType((*AggregateResponse)(nil), "node.AggregateResponse")
+	proto.RegisterType((*AggregateTagNameElement)(nil), "node.AggregateTagNameElement")
+	proto.RegisterType((*WriteBatchRequest)(nil), "node.WriteBatchRequest")
+	proto.RegisterType((*WriteBatchRequestElement)(nil), "node.WriteBatchRequestElement")
+	proto.RegisterType((*WriteBatchResponse)(nil), "node.WriteBatchResponse")
+	proto.RegisterType((*WriteBatchError)(nil), "node.WriteBatchError")
+	proto.RegisterEnum("node.TimeType", TimeType_name, TimeType_value)
+	proto.RegisterEnum("node.ErrorType", ErrorType_name, ErrorType_value)
+	proto.RegisterEnum("node.AggregateQueryType", AggregateQueryType_name, AggregateQueryType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Node service
+
+type NodeClient interface {
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error)
+	WriteTagged(ctx context.Context, in *WriteTaggedRequest, opts ...grpc.CallOption) (*WriteResponse, error)
+	WriteBatch(ctx context.Context, in *WriteBatchRequest, opts ...grpc.CallOption) (*WriteBatchResponse, error)
+	WriteTaggedBatch(ctx context.Context, in *WriteBatchRequest, opts ...grpc.CallOption) (*WriteBatchResponse, error)
+	FetchTagged(ctx context.Context, in *FetchTaggedRequest, opts ...grpc.CallOption) (Node_FetchTaggedClient, error)
+	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error)
+}
+
+type nodeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNodeClient(cc *grpc.ClientConn) NodeClient {
+	return &nodeClient{cc}
+}
+
+func (c *nodeClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	err := grpc.Invoke(ctx, "/node.Node/Health", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error) {
+	out := new(WriteResponse)
+	err := grpc.Invoke(ctx, "/node.Node/Write", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) WriteTagged(ctx context.Context, in *WriteTaggedRequest, opts ...grpc.CallOption) (*WriteResponse, error) {
+	out := new(WriteResponse)
+	err := grpc.Invoke(ctx, "/node.Node/WriteTagged", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) WriteBatch(ctx context.Context, in *WriteBatchRequest, opts ...grpc.CallOption) (*WriteBatchResponse, error) {
+	out := new(WriteBatchResponse)
+	err := grpc.Invoke(ctx, "/node.Node/WriteBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) WriteTaggedBatch(ctx context.Context, in *WriteBatchRequest, opts ...grpc.CallOption) (*WriteBatchResponse, error) {
+	out := new(WriteBatchResponse)
+	err := grpc.Invoke(ctx, "/node.Node/WriteTaggedBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) FetchTagged(ctx context.Context, in *FetchTaggedRequest, opts ...grpc.CallOption) (Node_FetchTaggedClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Node_serviceDesc.Streams[0], c.cc, "/node.Node/FetchTagged", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeFetchTaggedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Node_FetchTaggedClient interface {
+	Recv() (*FetchTaggedResponse, error)
+	grpc.ClientStream
+}
+
+type nodeFetchTaggedClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeFetchTaggedClient) Recv() (*FetchTaggedResponse, error) {
+	m := new(FetchTaggedResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *nodeClient) Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error) {
+	out := new(AggregateResponse)
+	err := grpc.Invoke(ctx, "/node.Node/Aggregate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Node service
+
+type NodeServer interface {
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	Write(context.Context, *WriteRequest) (*WriteResponse, error)
+	WriteTagged(context.Context, *WriteTaggedRequest) (*WriteResponse, error)
+	WriteBatch(context.Context, *WriteBatchRequest) (*WriteBatchResponse, error)
+	WriteTaggedBatch(context.Context, *WriteBatchRequest) (*WriteBatchResponse, error)
+	FetchTagged(*FetchTaggedRequest, Node_FetchTaggedServer) error
+	Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error)
+}
+
+func RegisterNodeServer(s *grpc.Server, srv NodeServer) {
+	s.RegisterService(&_Node_serviceDesc, srv)
+}
+
+func _Node_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/Health",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/Write",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).Write(ctx, req.(*WriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_WriteTagged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteTaggedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).WriteTagged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/WriteTagged",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).WriteTagged(ctx, req.(*WriteTaggedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_WriteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).WriteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/WriteBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).WriteBatch(ctx, req.(*WriteBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_WriteTaggedBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).WriteTaggedBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/WriteTaggedBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).WriteTaggedBatch(ctx, req.(*WriteBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_FetchTagged_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchTaggedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServer).FetchTagged(m, &nodeFetchTaggedServer{stream})
+}
+
+type Node_FetchTaggedServer interface {
+	Send(*FetchTaggedResponse) error
+	grpc.ServerStream
+}
+
+type nodeFetchTaggedServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeFetchTaggedServer) Send(m *FetchTaggedResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Node_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/node.Node/Aggregate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).Aggregate(ctx, req.(*AggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Node_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "node.Node",
+	HandlerType: (*NodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Health",
+			Handler:    _Node_Health_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _Node_Write_Handler,
+		},
+		{
+			MethodName: "WriteTagged",
+			Handler:    _Node_WriteTagged_Handler,
+		},
+		{
+			MethodName: "WriteBatch",
+			Handler:    _Node_WriteBatch_Handler,
+		},
+		{
+			MethodName: "WriteTaggedBatch",
+			Handler:    _Node_WriteTaggedBatch_Handler,
+		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _Node_Aggregate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchTagged",
+			Handler:       _Node_FetchTagged_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "github.com/m3db/m3/src/dbnode/generated/proto/node/node.proto",
+}
+
+func (m *HealthRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HealthRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *HealthResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HealthResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Ok {
+		dAtA[i] = 0x8
+		i++
+		if m.Ok {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Status) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Status)))
+		i += copy(dAtA[i:], m.Status)
+	}
+	if m.Bootstrapped {
+		dAtA[i] = 0x18
+		i++
+		if m.Bootstrapped {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Datapoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Datapoint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.Value != 0 {
+		dAtA[i] = 0x11
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if len(m.Annotation) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Annotation)))
+		i += copy(dAtA[i:], m.Annotation)
+	}
+	if m.TimestampTimeType != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.TimestampTimeType))
+	}
+	return i, nil
+}
+
+func (m *Tag) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	return i, nil
+}
+
+func (m *WriteRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Datapoint != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Datapoint.Size()))
+		n1, err := m.Datapoint.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *WriteTaggedRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteTaggedRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Tags) > 0 {
+		for _, msg := range m.Tags {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Datapoint != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Datapoint.Size()))
+		n2, err := m.Datapoint.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	return i, nil
+}
+
+func (m *WriteResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *FetchTaggedRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchTaggedRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if len(m.Query) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Query)))
+		i += copy(dAtA[i:], m.Query)
+	}
+	if m.RangeStart != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeStart))
+	}
+	if m.RangeEnd != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeEnd))
+	}
+	if m.FetchData {
+		dAtA[i] = 0x28
+		i++
+		if m.FetchData {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Limit != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Limit))
+	}
+	if m.RangeTimeType != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeTimeType))
+	}
+	return i, nil
+}
+
+func (m *FetchTaggedResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchTaggedResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Elements) > 0 {
+		for _, msg := range m.Elements {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Exhaustive {
+		dAtA[i] = 0x10
+		i++
+		if m.Exhaustive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FetchTaggedIDResult) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchTaggedIDResult) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if len(m.EncodedTags) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.EncodedTags)))
+		i += copy(dAtA[i:], m.EncodedTags)
+	}
+	if len(m.Segments) > 0 {
+		for _, msg := range m.Segments {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Err != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Err.Size()))
+		n3, err := m.Err.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *Segments) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Segments) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Merged != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Merged.Size()))
+		n4, err := m.Merged.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.Unmerged) > 0 {
+		for _, msg := range m.Unmerged {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Segment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Segment) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Head) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Head)))
+		i += copy(dAtA[i:], m.Head)
+	}
+	if len(m.Tail) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Tail)))
+		i += copy(dAtA[i:], m.Tail)
+	}
+	if m.StartTime != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.StartTime))
+	}
+	if m.BlockSize != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.BlockSize))
+	}
+	return i, nil
+}
+
+func (m *Error) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Error) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Type))
+	}
+	if len(m.Message) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
+	}
+	if m.Flags != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Flags))
+	}
+	return i, nil
+}
+
+func (m *AggregateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregateRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Query) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Query)))
+		i += copy(dAtA[i:], m.Query)
+	}
+	if m.RangeStart != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeStart))
+	}
+	if m.RangeEnd != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeEnd))
+	}
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if m.Limit != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Limit))
+	}
+	if len(m.TagNameFilter) > 0 {
+		for _, b := range m.TagNameFilter {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if m.AggregateQueryType != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.AggregateQueryType))
+	}
+	if m.RangeTimeType != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.RangeTimeType))
+	}
+	return i, nil
+}
+
+func (m *AggregateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregateResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Results) > 0 {
+		for _, msg := range m.Results {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Exhaustive {
+		dAtA[i] = 0x10
+		i++
+		if m.Exhaustive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *AggregateTagNameElement) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregateTagNameElement) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TagName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.TagName)))
+		i += copy(dAtA[i:], m.TagName)
+	}
+	if len(m.TagValues) > 0 {
+		for _, b := range m.TagValues {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *WriteBatchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteBatchRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.NameSpace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.NameSpace)))
+		i += copy(dAtA[i:], m.NameSpace)
+	}
+	if len(m.Elements) > 0 {
+		for _, msg := range m.Elements {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *WriteBatchRequestElement) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteBatchRequestElement) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.EncodedTags) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(len(m.EncodedTags)))
+		i += copy(dAtA[i:], m.EncodedTags)
+	}
+	if m.Datapoint != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Datapoint.Size()))
+		n5, err := m.Datapoint.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+
+func (m *WriteBatchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteBatchResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Errors) > 0 {
+		for _, msg := range m.Errors {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintNode(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *WriteBatchError) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteBatchError) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Index != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Index))
+	}
+	if m.Err != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNode(dAtA, i, uint64(m.Err.Size()))
+		n6, err := m.Err.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	return i, nil
+}
+
+func encodeVarintNode(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *HealthRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *HealthResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Ok {
+		n += 2
+	}
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Bootstrapped {
+		n += 2
+	}
+	return n
+}
+
+func (m *Datapoint) Size() (n int) {
+	var l int
+	_ = l
+	if m.Timestamp != 0 {
+		n += 1 + sovNode(uint64(m.Timestamp))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	l = len(m.Annotation)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.TimestampTimeType != 0 {
+		n += 1 + sovNode(uint64(m.TimestampTimeType))
+	}
+	return n
+}
+
+func (m *Tag) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Datapoint != nil {
+		l = m.Datapoint.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteTaggedRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, e := range m.Tags {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Datapoint != nil {
+		l = m.Datapoint.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteResponse) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *FetchTaggedRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.RangeStart != 0 {
+		n += 1 + sovNode(uint64(m.RangeStart))
+	}
+	if m.RangeEnd != 0 {
+		n += 1 + sovNode(uint64(m.RangeEnd))
+	}
+	if m.FetchData {
+		n += 2
+	}
+	if m.Limit != 0 {
+		n += 1 + sovNode(uint64(m.Limit))
+	}
+	if m.RangeTimeType != 0 {
+		n += 1 + sovNode(uint64(m.RangeTimeType))
+	}
+	return n
+}
+
+func (m *FetchTaggedResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Elements) > 0 {
+		for _, e := range m.Elements {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Exhaustive {
+		n += 2
+	}
+	return n
+}
+
+func (m *FetchTaggedIDResult) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.EncodedTags)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func (m *Segments) Size() (n int) {
+	var l int
+	_ = l
+	if m.Merged != nil {
+		l = m.Merged.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Unmerged) > 0 {
+		for _, e := range m.Unmerged {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Segment) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Head)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.Tail)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.StartTime != 0 {
+		n += 1 + sovNode(uint64(m.StartTime))
+	}
+	if m.BlockSize != 0 {
+		n += 1 + sovNode(uint64(m.BlockSize))
+	}
+	return n
+}
+
+func (m *Error) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovNode(uint64(m.Type))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Flags != 0 {
+		n += 1 + sovNode(uint64(m.Flags))
+	}
+	return n
+}
+
+func (m *AggregateRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.RangeStart != 0 {
+		n += 1 + sovNode(uint64(m.RangeStart))
+	}
+	if m.RangeEnd != 0 {
+		n += 1 + sovNode(uint64(m.RangeEnd))
+	}
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovNode(uint64(m.Limit))
+	}
+	if len(m.TagNameFilter) > 0 {
+		for _, b := range m.TagNameFilter {
+			l = len(b)
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.AggregateQueryType != 0 {
+		n += 1 + sovNode(uint64(m.AggregateQueryType))
+	}
+	if m.RangeTimeType != 0 {
+		n += 1 + sovNode(uint64(m.RangeTimeType))
+	}
+	return n
+}
+
+func (m *AggregateResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Results) > 0 {
+		for _, e := range m.Results {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Exhaustive {
+		n += 2
+	}
+	return n
+}
+
+func (m *AggregateTagNameElement) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TagName)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.TagValues) > 0 {
+		for _, b := range m.TagValues {
+			l = len(b)
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *WriteBatchRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.NameSpace)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Elements) > 0 {
+		for _, e := range m.Elements {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *WriteBatchRequestElement) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	l = len(m.EncodedTags)
+	if l > 0 {
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Datapoint != nil {
+		l = m.Datapoint.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteBatchResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Errors) > 0 {
+		for _, e := range m.Errors {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *WriteBatchError) Size() (n int) {
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovNode(uint64(m.Index))
+	}
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	return n
+}
+
+func sovNode(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozNode(x uint64) (n int) {
+	return sovNode(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *HealthRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HealthResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ok", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Ok = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bootstrapped", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Bootstrapped = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Datapoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Datapoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Datapoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Annotation", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Annotation = append(m.Annotation[:0], dAtA[iNdEx:postIndex]...)
+			if m.Annotation == nil {
+				m.Annotation = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampTimeType", wireType)
+			}
+			m.TimestampTimeType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampTimeType |= (TimeType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Tag) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tag: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tag: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datapoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Datapoint == nil {
+				m.Datapoint = &Datapoint{}
+			}
+			if err := m.Datapoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteTaggedRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteTaggedRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteTaggedRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tags = append(m.Tags, &Tag{})
+			if err := m.Tags[len(m.Tags)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datapoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Datapoint == nil {
+				m.Datapoint = &Datapoint{}
+			}
+			if err := m.Datapoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchTaggedRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchTaggedRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchTaggedRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = append(m.NameSpace[:0], dAtA[iNdEx:postIndex]...)
+			if m.NameSpace == nil {
+				m.NameSpace = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = append(m.Query[:0], dAtA[iNdEx:postIndex]...)
+			if m.Query == nil {
+				m.Query = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeStart", wireType)
+			}
+			m.RangeStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeStart |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeEnd", wireType)
+			}
+			m.RangeEnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeEnd |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchData", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FetchData = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeTimeType", wireType)
+			}
+			m.RangeTimeType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeTimeType |= (TimeType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchTaggedResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchTaggedResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchTaggedResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Elements", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Elements = append(m.Elements, &FetchTaggedIDResult{})
+			if err := m.Elements[len(m.Elements)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exhaustive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exhaustive = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchTaggedIDResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchTaggedIDResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchTaggedIDResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = append(m.NameSpace[:0], dAtA[iNdEx:postIndex]...)
+			if m.NameSpace == nil {
+				m.NameSpace = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodedTags", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EncodedTags = append(m.EncodedTags[:0], dAtA[iNdEx:postIndex]...)
+			if m.EncodedTags == nil {
+				m.EncodedTags = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segments = append(m.Segments, &Segments{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Segments) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Segments: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Segments: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Merged", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Merged == nil {
+				m.Merged = &Segment{}
+			}
+			if err := m.Merged.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unmerged", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unmerged = append(m.Unmerged, &Segment{})
+			if err := m.Unmerged[len(m.Unmerged)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Segment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Segment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Segment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Head", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Head = append(m.Head[:0], dAtA[iNdEx:postIndex]...)
+			if m.Head == nil {
+				m.Head = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tail", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tail = append(m.Tail[:0], dAtA[iNdEx:postIndex]...)
+			if m.Tail == nil {
+				m.Tail = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
+			}
+			m.StartTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockSize", wireType)
+			}
+			m.BlockSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockSize |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Error) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Error: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Error: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (ErrorType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flags", wireType)
+			}
+			m.Flags = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Flags |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = append(m.Query[:0], dAtA[iNdEx:postIndex]...)
+			if m.Query == nil {
+				m.Query = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeStart", wireType)
+			}
+			m.RangeStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeStart |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeEnd", wireType)
+			}
+			m.RangeEnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeEnd |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = append(m.NameSpace[:0], dAtA[iNdEx:postIndex]...)
+			if m.NameSpace == nil {
+				m.NameSpace = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagNameFilter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagNameFilter = append(m.TagNameFilter, make([]byte, postIndex-iNdEx))
+			copy(m.TagNameFilter[len(m.TagNameFilter)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregateQueryType", wireType)
+			}
+			m.AggregateQueryType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AggregateQueryType |= (AggregateQueryType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeTimeType", wireType)
+			}
+			m.RangeTimeType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RangeTimeType |= (TimeType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Results", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Results = append(m.Results, &AggregateTagNameElement{})
+			if err := m.Results[len(m.Results)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exhaustive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exhaustive = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregateTagNameElement) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregateTagNameElement: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregateTagNameElement: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagName = append(m.TagName[:0], dAtA[iNdEx:postIndex]...)
+			if m.TagName == nil {
+				m.TagName = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagValues = append(m.TagValues, make([]byte, postIndex-iNdEx))
+			copy(m.TagValues[len(m.TagValues)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteBatchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteBatchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteBatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameSpace", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameSpace = append(m.NameSpace[:0], dAtA[iNdEx:postIndex]...)
+			if m.NameSpace == nil {
+				m.NameSpace = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Elements", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Elements = append(m.Elements, &WriteBatchRequestElement{})
+			if err := m.Elements[len(m.Elements)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteBatchRequestElement) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteBatchRequestElement: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteBatchRequestElement: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodedTags", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EncodedTags = append(m.EncodedTags[:0], dAtA[iNdEx:postIndex]...)
+			if m.EncodedTags == nil {
+				m.EncodedTags = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datapoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Datapoint == nil {
+				m.Datapoint = &Datapoint{}
+			}
+			if err := m.Datapoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteBatchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteBatchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteBatchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errors = append(m.Errors, &WriteBatchError{})
+			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteBatchError) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteBatchError: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteBatchError: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipNode(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthNode
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowNode
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipNode(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthNode = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowNode   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/dbnode/generated/proto/node/node.proto", fileDescriptorNode)
+}
+
+var fileDescriptorNode = []byte{
+	// 1201 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x5f, 0x6f, 0xdb, 0x54,
+	0x14, 0xaf, 0xe3, 0x24, 0x4d, 0x4e, 0xd2, 0x26, 0xbd, 0xed, 0x36, 0xaf, 0xb0, 0x10, 0x19, 0x90,
+	0x4a, 0xa5, 0x2d, 0x53, 0x0a, 0x42, 0x42, 0x43, 0x28, 0x5d, 0xbd, 0xae, 0x52, 0x97, 0xb1, 0x9b,
+	0x6c, 0x80, 0x34, 0x29, 0xdc, 0xc4, 0x77, 0x8e, 0xd5, 0xd8, 0xce, 0xec, 0x9b, 0x69, 0x83, 0x2f,
+	0xb1, 0x2f, 0xc0, 0x77, 0xe0, 0x8d, 0x37, 0x9e, 0x79, 0xe4, 0x23, 0xa0, 0xf1, 0xce, 0x67, 0x40,
+	0xf7, 0x8f, 0x1d, 0x3b, 0x4e, 0xd9, 0xd8, 0x4b, 0x94, 0xf3, 0xbb, 0xe7, 0x9c, 0x7b, 0xce, 0xef,
+	0xfc, 0xf1, 0x85, 0xaf, 0x1d, 0x97, 0x4d, 0x17, 0xe3, 0x5b, 0x93, 0xc0, 0xeb, 0x78, 0x47, 0xf6,
+	0xb8, 0xe3, 0x1d, 0x75, 0xa2, 0x70, 0xd2, 0xb1, 0xc7, 0x7e, 0x60, 0xd3, 0x8e, 0x43, 0x7d, 0x1a,
+	0x12, 0x46, 0xed, 0xce, 0x3c, 0x0c, 0x58, 0xd0, 0x11, 0x20, 0xff, 0xb9, 0x25, 0x64, 0x54, 0xe4,
+	0xff, 0xcd, 0x06, 0x6c, 0xdd, 0xa7, 0x64, 0xc6, 0xa6, 0x98, 0x3e, 0x5f, 0xd0, 0x88, 0x99, 0x4f,
+	0x61, 0x3b, 0x06, 0xa2, 0x79, 0xe0, 0x47, 0x14, 0x6d, 0x43, 0x21, 0xb8, 0x30, 0xb4, 0xb6, 0x76,
+	0x50, 0xc1, 0x85, 0xe0, 0x02, 0x5d, 0x85, 0x72, 0xc4, 0x08, 0x5b, 0x44, 0x46, 0xa1, 0xad, 0x1d,
+	0x54, 0xb1, 0x92, 0x90, 0x09, 0xf5, 0x71, 0x10, 0xb0, 0x88, 0x85, 0x64, 0x3e, 0xa7, 0xb6, 0xa1,
+	0x0b, 0x8b, 0x0c, 0x66, 0xfe, 0xa2, 0x41, 0xf5, 0x84, 0x30, 0x32, 0x0f, 0x5c, 0x9f, 0xa1, 0x0f,
+	0xa1, 0xca, 0x5c, 0x8f, 0x46, 0x8c, 0x78, 0x73, 0x71, 0x81, 0x8e, 0x97, 0x00, 0xda, 0x83, 0xd2,
+	0x0b, 0x32, 0x5b, 0x50, 0x71, 0x8d, 0x86, 0xa5, 0x80, 0x5a, 0x00, 0xc4, 0xf7, 0x03, 0x46, 0x98,
+	0x1b, 0xf8, 0xe2, 0x8e, 0x3a, 0x4e, 0x21, 0xe8, 0x0e, 0xec, 0x24, 0x2e, 0x86, 0xae, 0x47, 0x87,
+	0xaf, 0xe6, 0xd4, 0x28, 0xb6, 0xb5, 0x83, 0xed, 0xee, 0xf6, 0x2d, 0x91, 0x7e, 0x8c, 0xe2, 0xbc,
+	0xa2, 0xd9, 0x01, 0x7d, 0x48, 0x1c, 0x84, 0xa0, 0xe8, 0x13, 0x8f, 0x8a, 0x98, 0xaa, 0x58, 0xfc,
+	0xcf, 0x86, 0x53, 0x55, 0xe1, 0x98, 0x17, 0x50, 0xff, 0x2e, 0x74, 0x19, 0x55, 0xf4, 0xf1, 0x94,
+	0xb8, 0xf6, 0x60, 0x4e, 0x26, 0xb1, 0xf9, 0x12, 0xe0, 0x54, 0xba, 0xb6, 0x72, 0x50, 0x70, 0x6d,
+	0x74, 0x13, 0xaa, 0x76, 0xcc, 0x86, 0xc8, 0xa5, 0xd6, 0x6d, 0xc8, 0x20, 0x13, 0x92, 0xf0, 0x52,
+	0xc3, 0x7c, 0xad, 0x01, 0x12, 0xb7, 0x0d, 0x89, 0xe3, 0x50, 0xfb, 0xfd, 0xee, 0xbc, 0x01, 0x45,
+	0x46, 0x9c, 0xc8, 0xd0, 0xdb, 0xfa, 0x41, 0xad, 0x5b, 0x55, 0x9c, 0x10, 0x07, 0x0b, 0x38, 0x1b,
+	0x52, 0xf1, 0xad, 0x21, 0x35, 0x60, 0x4b, 0xe5, 0x2f, 0xbb, 0xc5, 0xfc, 0x47, 0x03, 0x74, 0x8f,
+	0xb2, 0xc9, 0xf4, 0x2d, 0x31, 0xd6, 0xd3, 0x31, 0xee, 0x41, 0xe9, 0xf9, 0x82, 0x86, 0xaf, 0x44,
+	0x98, 0x75, 0x2c, 0x05, 0x5e, 0xea, 0x90, 0xf8, 0x0e, 0x1d, 0x30, 0x12, 0x4a, 0x7a, 0x74, 0x9c,
+	0x42, 0xd0, 0x3e, 0x54, 0x84, 0x64, 0xf9, 0xb6, 0x88, 0x54, 0xc7, 0x89, 0xcc, 0xef, 0x7b, 0xc6,
+	0xa3, 0xe0, 0x41, 0x1b, 0x25, 0xd1, 0x89, 0x4b, 0x80, 0xdf, 0x37, 0x73, 0x3d, 0x97, 0x19, 0x65,
+	0x61, 0x26, 0x05, 0xf4, 0x39, 0x6c, 0x09, 0xfb, 0xa4, 0x6d, 0x36, 0xd7, 0xb6, 0x4d, 0x56, 0xc9,
+	0x9c, 0xc1, 0x6e, 0x26, 0x5f, 0x35, 0x35, 0x5f, 0x40, 0x85, 0xce, 0xa8, 0x47, 0x7d, 0x16, 0x19,
+	0x9a, 0xa0, 0xfa, 0xba, 0xf4, 0x93, 0x52, 0x3e, 0x3b, 0xc1, 0x34, 0x5a, 0xcc, 0x18, 0x4e, 0x54,
+	0x79, 0xce, 0xf4, 0xe5, 0x94, 0x2c, 0x22, 0xe6, 0xbe, 0x90, 0xad, 0x56, 0xc1, 0x29, 0xc4, 0xfc,
+	0x55, 0x83, 0xdd, 0x35, 0x1e, 0x54, 0x95, 0x25, 0xb1, 0xbc, 0xca, 0x19, 0xbe, 0x0b, 0xab, 0x7c,
+	0xb7, 0xa1, 0x46, 0xfd, 0x49, 0x60, 0x53, 0x7b, 0x28, 0x5b, 0x81, 0x9f, 0xa7, 0x21, 0x74, 0x08,
+	0x95, 0x88, 0x3a, 0x32, 0xfc, 0xa2, 0x08, 0x5f, 0xd1, 0x30, 0x50, 0x28, 0x4e, 0xce, 0xd1, 0x0d,
+	0xd0, 0x69, 0x18, 0x0a, 0x96, 0x6b, 0xdd, 0x9a, 0x54, 0xb3, 0xc2, 0x30, 0x08, 0x31, 0xc7, 0xcd,
+	0xa7, 0x50, 0x89, 0x8d, 0xd0, 0xa7, 0x50, 0xf6, 0x68, 0xe8, 0x50, 0x19, 0x6a, 0xad, 0xbb, 0x95,
+	0x71, 0x8a, 0xd5, 0x21, 0xfa, 0x0c, 0x2a, 0x0b, 0x5f, 0x29, 0x16, 0xda, 0x7a, 0x5e, 0x31, 0x39,
+	0x36, 0x3d, 0xd8, 0x54, 0x20, 0x9f, 0xda, 0x29, 0x25, 0x31, 0x0b, 0xe2, 0x3f, 0xc7, 0x18, 0x71,
+	0x67, 0x8a, 0x02, 0xf1, 0x9f, 0x73, 0x13, 0xf1, 0x06, 0xe2, 0x25, 0x54, 0x6d, 0xb5, 0x04, 0xf8,
+	0xe9, 0x78, 0x16, 0x4c, 0x2e, 0x06, 0xee, 0x4f, 0x54, 0xb5, 0xd5, 0x12, 0x30, 0x9f, 0x42, 0x49,
+	0xa4, 0x86, 0x3e, 0x86, 0x22, 0xe3, 0x3d, 0xa2, 0x89, 0x1e, 0x69, 0xa4, 0xb2, 0x16, 0x4d, 0x22,
+	0x0e, 0x91, 0x01, 0x9b, 0x1e, 0x8d, 0x22, 0xe2, 0xc4, 0x5b, 0x23, 0x16, 0x79, 0x07, 0x3e, 0x9b,
+	0xc5, 0xdc, 0xeb, 0x58, 0x0a, 0xe6, 0xef, 0x05, 0x68, 0xf6, 0x1c, 0x27, 0xa4, 0x0e, 0x59, 0xae,
+	0x94, 0x64, 0x38, 0xb4, 0xcb, 0x87, 0xa3, 0xf0, 0x9f, 0xc3, 0xa1, 0xe7, 0x87, 0x63, 0xd9, 0x1c,
+	0xc5, 0x35, 0xc3, 0x28, 0x87, 0xa3, 0x94, 0x1e, 0x8e, 0x4f, 0x60, 0x8b, 0x11, 0xa7, 0x4f, 0x3c,
+	0x7a, 0xcf, 0x9d, 0x31, 0x1a, 0x1a, 0xe5, 0xb6, 0x7e, 0x50, 0xc7, 0x59, 0x10, 0xdd, 0x07, 0x44,
+	0xe2, 0xf8, 0x1f, 0xf1, 0x38, 0x53, 0x73, 0x64, 0x48, 0x8e, 0x7a, 0xb9, 0x73, 0xbc, 0xc6, 0x26,
+	0x3f, 0x8c, 0x95, 0x77, 0x1b, 0xc6, 0x9d, 0x14, 0x7f, 0x6a, 0x14, 0xbf, 0x84, 0xcd, 0x50, 0x4c,
+	0x49, 0x3c, 0x89, 0x37, 0x56, 0x22, 0x19, 0xca, 0x1c, 0x2c, 0x39, 0x84, 0x38, 0xd6, 0x7e, 0xeb,
+	0x30, 0x3e, 0x82, 0x6b, 0x97, 0xf8, 0xe0, 0x95, 0x57, 0xcc, 0xa8, 0xb2, 0xc5, 0xa2, 0xf8, 0xe8,
+	0x11, 0xe7, 0x09, 0xff, 0x7a, 0x44, 0xa2, 0xb9, 0xeb, 0x78, 0x09, 0x98, 0x1e, 0xec, 0x88, 0x7d,
+	0x7a, 0x4c, 0xd8, 0x64, 0xfa, 0x6e, 0xcb, 0xf3, 0xab, 0xd4, 0xa6, 0x91, 0xc3, 0xd2, 0x92, 0xf9,
+	0xe5, 0x1c, 0xc5, 0x09, 0x26, 0xfa, 0xe6, 0xcf, 0x60, 0x5c, 0xa6, 0x95, 0x5b, 0x29, 0x2b, 0x4b,
+	0xa3, 0x90, 0x5f, 0x1a, 0xff, 0xf3, 0x73, 0x76, 0x17, 0x50, 0xfa, 0x72, 0x55, 0xad, 0x9b, 0x50,
+	0xa6, 0x7c, 0x8c, 0xe2, 0x62, 0x5d, 0x59, 0x4d, 0x46, 0xae, 0x16, 0xa5, 0x64, 0xde, 0x83, 0xc6,
+	0xca, 0x11, 0x6f, 0x60, 0xd7, 0xb7, 0xe9, 0x4b, 0xf5, 0xa4, 0x90, 0x42, 0xbc, 0xa5, 0x0a, 0xeb,
+	0xb7, 0xd4, 0xe1, 0x8f, 0x50, 0x89, 0xbb, 0x08, 0x35, 0xa1, 0xfe, 0xb8, 0x7f, 0xf6, 0xfd, 0x68,
+	0x60, 0xdd, 0x7d, 0xd8, 0x3f, 0x19, 0x34, 0x37, 0xd0, 0x15, 0xd8, 0x11, 0xc8, 0x83, 0xb3, 0xbb,
+	0xf8, 0x61, 0x0c, 0x6b, 0x29, 0xf8, 0xfc, 0xfc, 0x2c, 0x86, 0x0b, 0x68, 0x0f, 0x9a, 0x02, 0xee,
+	0xf7, 0xfa, 0x89, 0xb2, 0x7e, 0x78, 0x1b, 0xaa, 0xc9, 0x7e, 0x40, 0x08, 0xb6, 0xcf, 0xfa, 0x43,
+	0x0b, 0xf7, 0x7b, 0xe7, 0x23, 0x0b, 0xe3, 0x87, 0xb8, 0xb9, 0x81, 0x1a, 0x50, 0x3b, 0xee, 0x9d,
+	0x8c, 0xb0, 0xf5, 0xe8, 0xb1, 0x35, 0x18, 0x36, 0xb5, 0xc3, 0x6f, 0x01, 0xe5, 0xa7, 0x05, 0x7d,
+	0x04, 0x1f, 0xf4, 0x4e, 0x4f, 0xb1, 0x75, 0xda, 0x1b, 0x5a, 0xa3, 0xe3, 0x1f, 0x46, 0xc3, 0xde,
+	0xe9, 0xa8, 0xdf, 0x7b, 0x60, 0x8d, 0x9e, 0xf4, 0xce, 0x1f, 0x5b, 0xcd, 0x0d, 0x74, 0x1d, 0xae,
+	0xac, 0x55, 0x68, 0x6a, 0xdd, 0xdf, 0x74, 0x28, 0xf6, 0x03, 0x9b, 0xa2, 0x23, 0x28, 0xcb, 0x67,
+	0x1e, 0xda, 0x95, 0x54, 0x64, 0x5e, 0x81, 0xfb, 0x7b, 0x59, 0x50, 0x95, 0xe6, 0x36, 0x94, 0x04,
+	0xd7, 0x08, 0xa5, 0x6a, 0x12, 0x9b, 0xec, 0x66, 0x30, 0x65, 0x71, 0x07, 0x6a, 0xa9, 0x07, 0x0b,
+	0x32, 0x52, 0x3a, 0x99, 0xf7, 0xc1, 0x7a, 0xeb, 0x6f, 0x00, 0x96, 0xb5, 0x45, 0xd7, 0x2e, 0xe9,
+	0xea, 0x7d, 0x23, 0x7f, 0xa0, 0x1c, 0x58, 0xd0, 0x4c, 0xdd, 0xf5, 0xde, 0x6e, 0x4e, 0xa0, 0x96,
+	0xfa, 0xe6, 0xc6, 0x59, 0xe4, 0x5f, 0x39, 0xfb, 0xd7, 0xd7, 0x9c, 0x48, 0x1f, 0xb7, 0x35, 0x74,
+	0x07, 0xaa, 0x49, 0x35, 0xd1, 0xd5, 0x95, 0x15, 0x14, 0x7b, 0xb8, 0x96, 0xc3, 0xa5, 0xfd, 0x71,
+	0xf3, 0x8f, 0x37, 0x2d, 0xed, 0xcf, 0x37, 0x2d, 0xed, 0xaf, 0x37, 0x2d, 0xed, 0xf5, 0xdf, 0xad,
+	0x8d, 0x71, 0x59, 0xbc, 0xe3, 0x8f, 0xfe, 0x1d, 0x00, 0x3b, 0xd1, 0x3f, 0x88, 0x08, 0x0c, 0x00,
+	0x00,
+}
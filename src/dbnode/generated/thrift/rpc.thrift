@@ -32,9 +32,15 @@ enum ErrorType {
 	BAD_REQUEST
 }
 
+enum ErrorFlags {
+	NONE = 0x00,
+	SERIES_LIMIT_EXCEEDED = 0x01
+}
+
 exception Error {
 	1: required ErrorType type = ErrorType.INTERNAL_ERROR
 	2: required string message
+	3: optional i64 flags = 0
 }
 
 exception WriteBatchRawErrors {
@@ -46,6 +52,7 @@ service Node {
 	QueryResult query(1: QueryRequest req) throws (1: Error err)
 	AggregateQueryRawResult aggregateRaw(1: AggregateQueryRawRequest req) throws (1: Error err)
 	AggregateQueryResult aggregate(1: AggregateQueryRequest req) throws (1: Error err)
+	CardinalityResult cardinality(1: CardinalityRequest req) throws (1: Error err)
 	FetchResult fetch(1: FetchRequest req) throws (1: Error err)
 	FetchTaggedResult fetchTagged(1: FetchTaggedRequest req) throws (1: Error err)
 	void write(1: WriteRequest req) throws (1: Error err)
@@ -381,6 +388,32 @@ struct AggregateQueryResultTagValueElement {
 	1: required string tagValue
 }
 
+// CardinalityRequest requests the number of series per tag name and value
+// known to the index of a namespace, only the top N entries of each list are
+// returned.
+struct CardinalityRequest {
+	1: required binary nameSpace
+	2: required i64 rangeStart
+	3: required i64 rangeEnd
+	4: optional i64 limit
+	5: optional i64 topN
+	6: optional binary nameField
+	7: optional TimeType rangeType = TimeType.UNIX_SECONDS
+}
+
+struct CardinalityResult {
+	1: required i64 numSeries
+	2: required list<CardinalityEntry> seriesCountByMetricName
+	3: required list<CardinalityEntry> labelValueCountByLabelName
+	4: required list<CardinalityEntry> seriesCountByLabelValuePair
+	5: required bool exhaustive
+}
+
+struct CardinalityEntry {
+	1: required binary name
+	2: required i64 value
+}
+
 // Query wrapper types for simple non-optimized query use
 struct QueryRequest {
 	1: required Query query
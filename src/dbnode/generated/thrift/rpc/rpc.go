@@ -162,6 +162,64 @@ func (p *ErrorType) Value() (driver.Value, error) {
 	return int64(*p), nil
 }
 
+type ErrorFlags int64
+
+const (
+	ErrorFlags_NONE                  ErrorFlags = 0
+	ErrorFlags_SERIES_LIMIT_EXCEEDED ErrorFlags = 1
+)
+
+func (p ErrorFlags) String() string {
+	switch p {
+	case ErrorFlags_NONE:
+		return "NONE"
+	case ErrorFlags_SERIES_LIMIT_EXCEEDED:
+		return "SERIES_LIMIT_EXCEEDED"
+	}
+	return "<UNSET>"
+}
+
+func ErrorFlagsFromString(s string) (ErrorFlags, error) {
+	switch s {
+	case "NONE":
+		return ErrorFlags_NONE, nil
+	case "SERIES_LIMIT_EXCEEDED":
+		return ErrorFlags_SERIES_LIMIT_EXCEEDED, nil
+	}
+	return ErrorFlags(0), fmt.Errorf("not a valid ErrorFlags string")
+}
+
+func ErrorFlagsPtr(v ErrorFlags) *ErrorFlags { return &v }
+
+func (p ErrorFlags) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *ErrorFlags) UnmarshalText(text []byte) error {
+	q, err := ErrorFlagsFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
+func (p *ErrorFlags) Scan(value interface{}) error {
+	v, ok := value.(int64)
+	if !ok {
+		return errors.New("Scan value is not int64")
+	}
+	*p = ErrorFlags(v)
+	return nil
+}
+
+func (p *ErrorFlags) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return int64(*p), nil
+}
+
 type AggregateQueryType int64
 
 const (
@@ -223,9 +281,11 @@ func (p *AggregateQueryType) Value() (driver.Value, error) {
 // Attributes:
 //  - Type
 //  - Message
+//  - Flags
 type Error struct {
 	Type    ErrorType `thrift:"type,1,required" db:"type" json:"type"`
 	Message string    `thrift:"message,2,required" db:"message" json:"message"`
+	Flags   int64     `thrift:"flags,3" db:"flags" json:"flags,omitempty"`
 }
 
 func NewError() *Error {
@@ -241,6 +301,16 @@ func (p *Error) GetType() ErrorType {
 func (p *Error) GetMessage() string {
 	return p.Message
 }
+
+var Error_Flags_DEFAULT int64 = 0
+
+func (p *Error) GetFlags() int64 {
+	return p.Flags
+}
+func (p *Error) IsSetFlags() bool {
+	return p.Flags != Error_Flags_DEFAULT
+}
+
 func (p *Error) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -268,6 +338,10 @@ func (p *Error) Read(iprot thrift.TProtocol) error {
 				return err
 			}
 			issetMessage = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -308,6 +382,15 @@ func (p *Error) ReadField2(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *Error) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.Flags = v
+	}
+	return nil
+}
+
 func (p *Error) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("Error"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -319,6 +402,9 @@ func (p *Error) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField2(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -355,6 +441,21 @@ func (p *Error) writeField2(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *Error) writeField3(oprot thrift.TProtocol) (err error) {
+	if p.IsSetFlags() {
+		if err := oprot.WriteFieldBegin("flags", thrift.I64, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:flags: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(p.Flags)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.flags (3) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:flags: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *Error) String() string {
 	if p == nil {
 		return "<nil>"
@@ -9541,112 +9642,94 @@ func (p *AggregateQueryResultTagValueElement) String() string {
 }
 
 // Attributes:
-//  - Query
+//  - NameSpace
 //  - RangeStart
 //  - RangeEnd
-//  - NameSpace
 //  - Limit
-//  - NoData
+//  - TopN
+//  - NameField
 //  - RangeType
-//  - ResultTimeType
-type QueryRequest struct {
-	Query          *Query   `thrift:"query,1,required" db:"query" json:"query"`
-	RangeStart     int64    `thrift:"rangeStart,2,required" db:"rangeStart" json:"rangeStart"`
-	RangeEnd       int64    `thrift:"rangeEnd,3,required" db:"rangeEnd" json:"rangeEnd"`
-	NameSpace      string   `thrift:"nameSpace,4,required" db:"nameSpace" json:"nameSpace"`
-	Limit          *int64   `thrift:"limit,5" db:"limit" json:"limit,omitempty"`
-	NoData         *bool    `thrift:"noData,6" db:"noData" json:"noData,omitempty"`
-	RangeType      TimeType `thrift:"rangeType,7" db:"rangeType" json:"rangeType,omitempty"`
-	ResultTimeType TimeType `thrift:"resultTimeType,8" db:"resultTimeType" json:"resultTimeType,omitempty"`
-}
-
-func NewQueryRequest() *QueryRequest {
-	return &QueryRequest{
+type CardinalityRequest struct {
+	NameSpace  []byte   `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	RangeStart int64    `thrift:"rangeStart,2,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd   int64    `thrift:"rangeEnd,3,required" db:"rangeEnd" json:"rangeEnd"`
+	Limit      *int64   `thrift:"limit,4" db:"limit" json:"limit,omitempty"`
+	TopN       *int64   `thrift:"topN,5" db:"topN" json:"topN,omitempty"`
+	NameField  []byte   `thrift:"nameField,6" db:"nameField" json:"nameField,omitempty"`
+	RangeType  TimeType `thrift:"rangeType,7" db:"rangeType" json:"rangeType,omitempty"`
+}
+
+func NewCardinalityRequest() *CardinalityRequest {
+	return &CardinalityRequest{
 		RangeType: 0,
-
-		ResultTimeType: 0,
 	}
 }
 
-var QueryRequest_Query_DEFAULT *Query
-
-func (p *QueryRequest) GetQuery() *Query {
-	if !p.IsSetQuery() {
-		return QueryRequest_Query_DEFAULT
-	}
-	return p.Query
+func (p *CardinalityRequest) GetNameSpace() []byte {
+	return p.NameSpace
 }
 
-func (p *QueryRequest) GetRangeStart() int64 {
+func (p *CardinalityRequest) GetRangeStart() int64 {
 	return p.RangeStart
 }
 
-func (p *QueryRequest) GetRangeEnd() int64 {
+func (p *CardinalityRequest) GetRangeEnd() int64 {
 	return p.RangeEnd
 }
 
-func (p *QueryRequest) GetNameSpace() string {
-	return p.NameSpace
-}
-
-var QueryRequest_Limit_DEFAULT int64
+var CardinalityRequest_Limit_DEFAULT int64
 
-func (p *QueryRequest) GetLimit() int64 {
+func (p *CardinalityRequest) GetLimit() int64 {
 	if !p.IsSetLimit() {
-		return QueryRequest_Limit_DEFAULT
+		return CardinalityRequest_Limit_DEFAULT
 	}
 	return *p.Limit
 }
 
-var QueryRequest_NoData_DEFAULT bool
+var CardinalityRequest_TopN_DEFAULT int64
 
-func (p *QueryRequest) GetNoData() bool {
-	if !p.IsSetNoData() {
-		return QueryRequest_NoData_DEFAULT
+func (p *CardinalityRequest) GetTopN() int64 {
+	if !p.IsSetTopN() {
+		return CardinalityRequest_TopN_DEFAULT
 	}
-	return *p.NoData
+	return *p.TopN
 }
 
-var QueryRequest_RangeType_DEFAULT TimeType = 0
+var CardinalityRequest_NameField_DEFAULT []byte
 
-func (p *QueryRequest) GetRangeType() TimeType {
-	return p.RangeType
+func (p *CardinalityRequest) GetNameField() []byte {
+	return p.NameField
 }
 
-var QueryRequest_ResultTimeType_DEFAULT TimeType = 0
+var CardinalityRequest_RangeType_DEFAULT TimeType = 0
 
-func (p *QueryRequest) GetResultTimeType() TimeType {
-	return p.ResultTimeType
-}
-func (p *QueryRequest) IsSetQuery() bool {
-	return p.Query != nil
+func (p *CardinalityRequest) GetRangeType() TimeType {
+	return p.RangeType
 }
-
-func (p *QueryRequest) IsSetLimit() bool {
+func (p *CardinalityRequest) IsSetLimit() bool {
 	return p.Limit != nil
 }
 
-func (p *QueryRequest) IsSetNoData() bool {
-	return p.NoData != nil
+func (p *CardinalityRequest) IsSetTopN() bool {
+	return p.TopN != nil
 }
 
-func (p *QueryRequest) IsSetRangeType() bool {
-	return p.RangeType != QueryRequest_RangeType_DEFAULT
+func (p *CardinalityRequest) IsSetNameField() bool {
+	return p.NameField != nil
 }
 
-func (p *QueryRequest) IsSetResultTimeType() bool {
-	return p.ResultTimeType != QueryRequest_ResultTimeType_DEFAULT
+func (p *CardinalityRequest) IsSetRangeType() bool {
+	return p.RangeType != CardinalityRequest_RangeType_DEFAULT
 }
 
-func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
+func (p *CardinalityRequest) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	var issetQuery bool = false
+	var issetNameSpace bool = false
 	var issetRangeStart bool = false
 	var issetRangeEnd bool = false
-	var issetNameSpace bool = false
 
 	for {
 		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
@@ -9661,7 +9744,7 @@ func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField1(iprot); err != nil {
 				return err
 			}
-			issetQuery = true
+			issetNameSpace = true
 		case 2:
 			if err := p.ReadField2(iprot); err != nil {
 				return err
@@ -9676,7 +9759,6 @@ func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField4(iprot); err != nil {
 				return err
 			}
-			issetNameSpace = true
 		case 5:
 			if err := p.ReadField5(iprot); err != nil {
 				return err
@@ -9689,10 +9771,6 @@ func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField7(iprot); err != nil {
 				return err
 			}
-		case 8:
-			if err := p.ReadField8(iprot); err != nil {
-				return err
-			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -9705,8 +9783,8 @@ func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
 	if err := iprot.ReadStructEnd(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if !issetQuery {
-		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Query is not set"))
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
 	if !issetRangeStart {
 		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
@@ -9714,21 +9792,19 @@ func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
 	if !issetRangeEnd {
 		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
 	}
-	if !issetNameSpace {
-		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
-	}
 	return nil
 }
 
-func (p *QueryRequest) ReadField1(iprot thrift.TProtocol) error {
-	p.Query = &Query{}
-	if err := p.Query.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Query), err)
+func (p *CardinalityRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
 	return nil
 }
 
-func (p *QueryRequest) ReadField2(iprot thrift.TProtocol) error {
+func (p *CardinalityRequest) ReadField2(iprot thrift.TProtocol) error {
 	if v, err := iprot.ReadI64(); err != nil {
 		return thrift.PrependError("error reading field 2: ", err)
 	} else {
@@ -9737,7 +9813,7 @@ func (p *QueryRequest) ReadField2(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *QueryRequest) ReadField3(iprot thrift.TProtocol) error {
+func (p *CardinalityRequest) ReadField3(iprot thrift.TProtocol) error {
 	if v, err := iprot.ReadI64(); err != nil {
 		return thrift.PrependError("error reading field 3: ", err)
 	} else {
@@ -9746,34 +9822,34 @@ func (p *QueryRequest) ReadField3(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *QueryRequest) ReadField4(iprot thrift.TProtocol) error {
-	if v, err := iprot.ReadString(); err != nil {
+func (p *CardinalityRequest) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
 		return thrift.PrependError("error reading field 4: ", err)
 	} else {
-		p.NameSpace = v
+		p.Limit = &v
 	}
 	return nil
 }
 
-func (p *QueryRequest) ReadField5(iprot thrift.TProtocol) error {
+func (p *CardinalityRequest) ReadField5(iprot thrift.TProtocol) error {
 	if v, err := iprot.ReadI64(); err != nil {
 		return thrift.PrependError("error reading field 5: ", err)
 	} else {
-		p.Limit = &v
+		p.TopN = &v
 	}
 	return nil
 }
 
-func (p *QueryRequest) ReadField6(iprot thrift.TProtocol) error {
-	if v, err := iprot.ReadBool(); err != nil {
+func (p *CardinalityRequest) ReadField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
 		return thrift.PrependError("error reading field 6: ", err)
 	} else {
-		p.NoData = &v
+		p.NameField = v
 	}
 	return nil
 }
 
-func (p *QueryRequest) ReadField7(iprot thrift.TProtocol) error {
+func (p *CardinalityRequest) ReadField7(iprot thrift.TProtocol) error {
 	if v, err := iprot.ReadI32(); err != nil {
 		return thrift.PrependError("error reading field 7: ", err)
 	} else {
@@ -9783,18 +9859,8 @@ func (p *QueryRequest) ReadField7(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *QueryRequest) ReadField8(iprot thrift.TProtocol) error {
-	if v, err := iprot.ReadI32(); err != nil {
-		return thrift.PrependError("error reading field 8: ", err)
-	} else {
-		temp := TimeType(v)
-		p.ResultTimeType = temp
-	}
-	return nil
-}
-
-func (p *QueryRequest) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("QueryRequest"); err != nil {
+func (p *CardinalityRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityRequest"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -9819,9 +9885,6 @@ func (p *QueryRequest) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField7(oprot); err != nil {
 			return err
 		}
-		if err := p.writeField8(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -9832,20 +9895,20 @@ func (p *QueryRequest) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *QueryRequest) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("query", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:query: ", p), err)
+func (p *CardinalityRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	if err := p.Query.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Query), err)
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
 	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:query: ", p), err)
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
 	}
 	return err
 }
 
-func (p *QueryRequest) writeField2(oprot thrift.TProtocol) (err error) {
+func (p *CardinalityRequest) writeField2(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 2); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:rangeStart: ", p), err)
 	}
@@ -9858,7 +9921,7 @@ func (p *QueryRequest) writeField2(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *QueryRequest) writeField3(oprot thrift.TProtocol) (err error) {
+func (p *CardinalityRequest) writeField3(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 3); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeEnd: ", p), err)
 	}
@@ -9871,109 +9934,964 @@ func (p *QueryRequest) writeField3(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *QueryRequest) writeField4(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 4); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:nameSpace: ", p), err)
-	}
-	if err := oprot.WriteString(string(p.NameSpace)); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (4) field write error: ", p), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:nameSpace: ", p), err)
-	}
-	return err
-}
-
-func (p *QueryRequest) writeField5(oprot thrift.TProtocol) (err error) {
+func (p *CardinalityRequest) writeField4(oprot thrift.TProtocol) (err error) {
 	if p.IsSetLimit() {
-		if err := oprot.WriteFieldBegin("limit", thrift.I64, 5); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:limit: ", p), err)
+		if err := oprot.WriteFieldBegin("limit", thrift.I64, 4); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:limit: ", p), err)
 		}
 		if err := oprot.WriteI64(int64(*p.Limit)); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T.limit (5) field write error: ", p), err)
+			return thrift.PrependError(fmt.Sprintf("%T.limit (4) field write error: ", p), err)
 		}
 		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:limit: ", p), err)
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 4:limit: ", p), err)
 		}
 	}
 	return err
 }
 
-func (p *QueryRequest) writeField6(oprot thrift.TProtocol) (err error) {
-	if p.IsSetNoData() {
-		if err := oprot.WriteFieldBegin("noData", thrift.BOOL, 6); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:noData: ", p), err)
+func (p *CardinalityRequest) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetTopN() {
+		if err := oprot.WriteFieldBegin("topN", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:topN: ", p), err)
 		}
-		if err := oprot.WriteBool(bool(*p.NoData)); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T.noData (6) field write error: ", p), err)
+		if err := oprot.WriteI64(int64(*p.TopN)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.topN (5) field write error: ", p), err)
 		}
 		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:noData: ", p), err)
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:topN: ", p), err)
 		}
 	}
 	return err
 }
 
-func (p *QueryRequest) writeField7(oprot thrift.TProtocol) (err error) {
-	if p.IsSetRangeType() {
-		if err := oprot.WriteFieldBegin("rangeType", thrift.I32, 7); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:rangeType: ", p), err)
+func (p *CardinalityRequest) writeField6(oprot thrift.TProtocol) (err error) {
+	if p.IsSetNameField() {
+		if err := oprot.WriteFieldBegin("nameField", thrift.STRING, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:nameField: ", p), err)
 		}
-		if err := oprot.WriteI32(int32(p.RangeType)); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T.rangeType (7) field write error: ", p), err)
+		if err := oprot.WriteBinary(p.NameField); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.nameField (6) field write error: ", p), err)
 		}
 		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:rangeType: ", p), err)
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:nameField: ", p), err)
 		}
 	}
 	return err
 }
 
-func (p *QueryRequest) writeField8(oprot thrift.TProtocol) (err error) {
-	if p.IsSetResultTimeType() {
-		if err := oprot.WriteFieldBegin("resultTimeType", thrift.I32, 8); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:resultTimeType: ", p), err)
+func (p *CardinalityRequest) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetRangeType() {
+		if err := oprot.WriteFieldBegin("rangeType", thrift.I32, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:rangeType: ", p), err)
 		}
-		if err := oprot.WriteI32(int32(p.ResultTimeType)); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T.resultTimeType (8) field write error: ", p), err)
+		if err := oprot.WriteI32(int32(p.RangeType)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.rangeType (7) field write error: ", p), err)
 		}
 		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:resultTimeType: ", p), err)
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:rangeType: ", p), err)
 		}
 	}
 	return err
 }
 
-func (p *QueryRequest) String() string {
+func (p *CardinalityRequest) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("QueryRequest(%+v)", *p)
+	return fmt.Sprintf("CardinalityRequest(%+v)", *p)
 }
 
+
 // Attributes:
-//  - Results
+//  - NumSeries
+//  - SeriesCountByMetricName
+//  - LabelValueCountByLabelName
+//  - SeriesCountByLabelValuePair
 //  - Exhaustive
-type QueryResult_ struct {
-	Results    []*QueryResultElement `thrift:"results,1,required" db:"results" json:"results"`
-	Exhaustive bool                  `thrift:"exhaustive,2,required" db:"exhaustive" json:"exhaustive"`
+type CardinalityResult_ struct {
+	NumSeries                   int64               `thrift:"numSeries,1,required" db:"numSeries" json:"numSeries"`
+	SeriesCountByMetricName     []*CardinalityEntry `thrift:"seriesCountByMetricName,2,required" db:"seriesCountByMetricName" json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []*CardinalityEntry `thrift:"labelValueCountByLabelName,3,required" db:"labelValueCountByLabelName" json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []*CardinalityEntry `thrift:"seriesCountByLabelValuePair,4,required" db:"seriesCountByLabelValuePair" json:"seriesCountByLabelValuePair"`
+	Exhaustive                  bool                `thrift:"exhaustive,5,required" db:"exhaustive" json:"exhaustive"`
 }
 
-func NewQueryResult_() *QueryResult_ {
-	return &QueryResult_{}
+func NewCardinalityResult_() *CardinalityResult_ {
+	return &CardinalityResult_{}
 }
 
-func (p *QueryResult_) GetResults() []*QueryResultElement {
-	return p.Results
+func (p *CardinalityResult_) GetNumSeries() int64 {
+	return p.NumSeries
 }
 
-func (p *QueryResult_) GetExhaustive() bool {
-	return p.Exhaustive
+func (p *CardinalityResult_) GetSeriesCountByMetricName() []*CardinalityEntry {
+	return p.SeriesCountByMetricName
 }
-func (p *QueryResult_) Read(iprot thrift.TProtocol) error {
-	if _, err := iprot.ReadStructBegin(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
-	}
+
+func (p *CardinalityResult_) GetLabelValueCountByLabelName() []*CardinalityEntry {
+	return p.LabelValueCountByLabelName
+}
+
+func (p *CardinalityResult_) GetSeriesCountByLabelValuePair() []*CardinalityEntry {
+	return p.SeriesCountByLabelValuePair
+}
+
+func (p *CardinalityResult_) GetExhaustive() bool {
+	return p.Exhaustive
+}
+func (p *CardinalityResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetNumSeries bool = false
+	var issetSeriesCountByMetricName bool = false
+	var issetLabelValueCountByLabelName bool = false
+	var issetSeriesCountByLabelValuePair bool = false
+	var issetExhaustive bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNumSeries = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetSeriesCountByMetricName = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetLabelValueCountByLabelName = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetSeriesCountByLabelValuePair = true
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
+			issetExhaustive = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetNumSeries {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NumSeries is not set"))
+	}
+	if !issetSeriesCountByMetricName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field SeriesCountByMetricName is not set"))
+	}
+	if !issetLabelValueCountByLabelName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field LabelValueCountByLabelName is not set"))
+	}
+	if !issetSeriesCountByLabelValuePair {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field SeriesCountByLabelValuePair is not set"))
+	}
+	if !issetExhaustive {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Exhaustive is not set"))
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NumSeries = v
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*CardinalityEntry, 0, size)
+	p.SeriesCountByMetricName = tSlice
+	for i := 0; i < size; i++ {
+		_elem27 := &CardinalityEntry{}
+		if err := _elem27.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem27), err)
+		}
+		p.SeriesCountByMetricName = append(p.SeriesCountByMetricName, _elem27)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField3(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*CardinalityEntry, 0, size)
+	p.LabelValueCountByLabelName = tSlice
+	for i := 0; i < size; i++ {
+		_elem28 := &CardinalityEntry{}
+		if err := _elem28.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem28), err)
+		}
+		p.LabelValueCountByLabelName = append(p.LabelValueCountByLabelName, _elem28)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField4(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*CardinalityEntry, 0, size)
+	p.SeriesCountByLabelValuePair = tSlice
+	for i := 0; i < size; i++ {
+		_elem29 := &CardinalityEntry{}
+		if err := _elem29.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem29), err)
+		}
+		p.SeriesCountByLabelValuePair = append(p.SeriesCountByLabelValuePair, _elem29)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.Exhaustive = v
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *CardinalityResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("numSeries", thrift.I64, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:numSeries: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.NumSeries)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.numSeries (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:numSeries: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityResult_) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("seriesCountByMetricName", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:seriesCountByMetricName: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.SeriesCountByMetricName)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.SeriesCountByMetricName {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:seriesCountByMetricName: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityResult_) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("labelValueCountByLabelName", thrift.LIST, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:labelValueCountByLabelName: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.LabelValueCountByLabelName)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.LabelValueCountByLabelName {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:labelValueCountByLabelName: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityResult_) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("seriesCountByLabelValuePair", thrift.LIST, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:seriesCountByLabelValuePair: ", p), err)
+	}
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.SeriesCountByLabelValuePair)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.SeriesCountByLabelValuePair {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:seriesCountByLabelValuePair: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityResult_) writeField5(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("exhaustive", thrift.BOOL, 5); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:exhaustive: ", p), err)
+	}
+	if err := oprot.WriteBool(bool(p.Exhaustive)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.exhaustive (5) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 5:exhaustive: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityResult_) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("CardinalityResult_(%+v)", *p)
+}
+
+
+// Attributes:
+//  - Name
+//  - Value
+type CardinalityEntry struct {
+	Name  []byte `thrift:"name,1,required" db:"name" json:"name"`
+	Value int64  `thrift:"value,2,required" db:"value" json:"value"`
+}
+
+func NewCardinalityEntry() *CardinalityEntry {
+	return &CardinalityEntry{}
+}
+
+func (p *CardinalityEntry) GetName() []byte {
+	return p.Name
+}
+
+func (p *CardinalityEntry) GetValue() int64 {
+	return p.Value
+}
+func (p *CardinalityEntry) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetName bool = false
+	var issetValue bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetName = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetValue = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Name is not set"))
+	}
+	if !issetValue {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Value is not set"))
+	}
+	return nil
+}
+
+func (p *CardinalityEntry) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Name = v
+	}
+	return nil
+}
+
+func (p *CardinalityEntry) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Value = v
+	}
+	return nil
+}
+
+func (p *CardinalityEntry) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityEntry"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *CardinalityEntry) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("name", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:name: ", p), err)
+	}
+	if err := oprot.WriteBinary(p.Name); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.name (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:name: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityEntry) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("value", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:value: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.Value)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.value (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:value: ", p), err)
+	}
+	return err
+}
+
+func (p *CardinalityEntry) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("CardinalityEntry(%+v)", *p)
+}
+
+// Attributes:
+//  - Query
+//  - RangeStart
+//  - RangeEnd
+//  - NameSpace
+//  - Limit
+//  - NoData
+//  - RangeType
+//  - ResultTimeType
+type QueryRequest struct {
+	Query          *Query   `thrift:"query,1,required" db:"query" json:"query"`
+	RangeStart     int64    `thrift:"rangeStart,2,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd       int64    `thrift:"rangeEnd,3,required" db:"rangeEnd" json:"rangeEnd"`
+	NameSpace      string   `thrift:"nameSpace,4,required" db:"nameSpace" json:"nameSpace"`
+	Limit          *int64   `thrift:"limit,5" db:"limit" json:"limit,omitempty"`
+	NoData         *bool    `thrift:"noData,6" db:"noData" json:"noData,omitempty"`
+	RangeType      TimeType `thrift:"rangeType,7" db:"rangeType" json:"rangeType,omitempty"`
+	ResultTimeType TimeType `thrift:"resultTimeType,8" db:"resultTimeType" json:"resultTimeType,omitempty"`
+}
+
+func NewQueryRequest() *QueryRequest {
+	return &QueryRequest{
+		RangeType: 0,
+
+		ResultTimeType: 0,
+	}
+}
+
+var QueryRequest_Query_DEFAULT *Query
+
+func (p *QueryRequest) GetQuery() *Query {
+	if !p.IsSetQuery() {
+		return QueryRequest_Query_DEFAULT
+	}
+	return p.Query
+}
+
+func (p *QueryRequest) GetRangeStart() int64 {
+	return p.RangeStart
+}
+
+func (p *QueryRequest) GetRangeEnd() int64 {
+	return p.RangeEnd
+}
+
+func (p *QueryRequest) GetNameSpace() string {
+	return p.NameSpace
+}
+
+var QueryRequest_Limit_DEFAULT int64
+
+func (p *QueryRequest) GetLimit() int64 {
+	if !p.IsSetLimit() {
+		return QueryRequest_Limit_DEFAULT
+	}
+	return *p.Limit
+}
+
+var QueryRequest_NoData_DEFAULT bool
+
+func (p *QueryRequest) GetNoData() bool {
+	if !p.IsSetNoData() {
+		return QueryRequest_NoData_DEFAULT
+	}
+	return *p.NoData
+}
+
+var QueryRequest_RangeType_DEFAULT TimeType = 0
+
+func (p *QueryRequest) GetRangeType() TimeType {
+	return p.RangeType
+}
+
+var QueryRequest_ResultTimeType_DEFAULT TimeType = 0
+
+func (p *QueryRequest) GetResultTimeType() TimeType {
+	return p.ResultTimeType
+}
+func (p *QueryRequest) IsSetQuery() bool {
+	return p.Query != nil
+}
+
+func (p *QueryRequest) IsSetLimit() bool {
+	return p.Limit != nil
+}
+
+func (p *QueryRequest) IsSetNoData() bool {
+	return p.NoData != nil
+}
+
+func (p *QueryRequest) IsSetRangeType() bool {
+	return p.RangeType != QueryRequest_RangeType_DEFAULT
+}
+
+func (p *QueryRequest) IsSetResultTimeType() bool {
+	return p.ResultTimeType != QueryRequest_ResultTimeType_DEFAULT
+}
+
+func (p *QueryRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetQuery bool = false
+	var issetRangeStart bool = false
+	var issetRangeEnd bool = false
+	var issetNameSpace bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetQuery = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetRangeStart = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetRangeEnd = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
+		case 6:
+			if err := p.ReadField6(iprot); err != nil {
+				return err
+			}
+		case 7:
+			if err := p.ReadField7(iprot); err != nil {
+				return err
+			}
+		case 8:
+			if err := p.ReadField8(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetQuery {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Query is not set"))
+	}
+	if !issetRangeStart {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
+	}
+	if !issetRangeEnd {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
+	}
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField1(iprot thrift.TProtocol) error {
+	p.Query = &Query{}
+	if err := p.Query.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Query), err)
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.RangeStart = v
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.RangeEnd = v
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.NameSpace = v
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.Limit = &v
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.NoData = &v
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField7(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		temp := TimeType(v)
+		p.RangeType = temp
+	}
+	return nil
+}
+
+func (p *QueryRequest) ReadField8(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		temp := TimeType(v)
+		p.ResultTimeType = temp
+	}
+	return nil
+}
+
+func (p *QueryRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("QueryRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField7(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *QueryRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("query", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:query: ", p), err)
+	}
+	if err := p.Query.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Query), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:query: ", p), err)
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:rangeStart: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.RangeStart)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeStart (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:rangeStart: ", p), err)
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeEnd: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.RangeEnd)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeEnd (3) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:rangeEnd: ", p), err)
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:nameSpace: ", p), err)
+	}
+	if err := oprot.WriteString(string(p.NameSpace)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (4) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:nameSpace: ", p), err)
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetLimit() {
+		if err := oprot.WriteFieldBegin("limit", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:limit: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.Limit)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.limit (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:limit: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField6(oprot thrift.TProtocol) (err error) {
+	if p.IsSetNoData() {
+		if err := oprot.WriteFieldBegin("noData", thrift.BOOL, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:noData: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.NoData)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.noData (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:noData: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetRangeType() {
+		if err := oprot.WriteFieldBegin("rangeType", thrift.I32, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:rangeType: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(p.RangeType)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.rangeType (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:rangeType: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *QueryRequest) writeField8(oprot thrift.TProtocol) (err error) {
+	if p.IsSetResultTimeType() {
+		if err := oprot.WriteFieldBegin("resultTimeType", thrift.I32, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:resultTimeType: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(p.ResultTimeType)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.resultTimeType (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:resultTimeType: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *QueryRequest) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("QueryRequest(%+v)", *p)
+}
+
+// Attributes:
+//  - Results
+//  - Exhaustive
+type QueryResult_ struct {
+	Results    []*QueryResultElement `thrift:"results,1,required" db:"results" json:"results"`
+	Exhaustive bool                  `thrift:"exhaustive,2,required" db:"exhaustive" json:"exhaustive"`
+}
+
+func NewQueryResult_() *QueryResult_ {
+	return &QueryResult_{}
+}
+
+func (p *QueryResult_) GetResults() []*QueryResultElement {
+	return p.Results
+}
+
+func (p *QueryResult_) GetExhaustive() bool {
+	return p.Exhaustive
+}
+func (p *QueryResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
 
 	var issetResults bool = false
 	var issetExhaustive bool = false
@@ -11488,6 +12406,9 @@ type Node interface {
 	Aggregate(req *AggregateQueryRequest) (r *AggregateQueryResult_, err error)
 	// Parameters:
 	//  - Req
+	Cardinality(req *CardinalityRequest) (r *CardinalityResult_, err error)
+	// Parameters:
+	//  - Req
 	Fetch(req *FetchRequest) (r *FetchResult_, err error)
 	// Parameters:
 	//  - Req
@@ -11807,6 +12728,87 @@ func (p *NodeClient) recvAggregate() (value *AggregateQueryResult_, err error) {
 	return
 }
 
+// Parameters:
+//  - Req
+func (p *NodeClient) Cardinality(req *CardinalityRequest) (r *CardinalityResult_, err error) {
+	if err = p.sendCardinality(req); err != nil {
+		return
+	}
+	return p.recvCardinality()
+}
+
+func (p *NodeClient) sendCardinality(req *CardinalityRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("cardinality", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeCardinalityArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvCardinality() (value *CardinalityResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "cardinality" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "cardinality failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "cardinality failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error29 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error30 error
+		error30, err = error29.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error30
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "cardinality failed: invalid message type")
+		return
+	}
+	result := NodeCardinalityResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
 // Parameters:
 //  - Req
 func (p *NodeClient) Fetch(req *FetchRequest) (r *FetchResult_, err error) {
@@ -13576,6 +14578,7 @@ func NewNodeProcessor(handler Node) *NodeProcessor {
 	self77.processorMap["query"] = &nodeProcessorQuery{handler: handler}
 	self77.processorMap["aggregateRaw"] = &nodeProcessorAggregateRaw{handler: handler}
 	self77.processorMap["aggregate"] = &nodeProcessorAggregate{handler: handler}
+	self77.processorMap["cardinality"] = &nodeProcessorCardinality{handler: handler}
 	self77.processorMap["fetch"] = &nodeProcessorFetch{handler: handler}
 	self77.processorMap["fetchTagged"] = &nodeProcessorFetchTagged{handler: handler}
 	self77.processorMap["write"] = &nodeProcessorWrite{handler: handler}
@@ -13640,13 +14643,66 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	result := NodeQueryResult{}
 	var retval *QueryResult_
 	var err2 error
-	if retval, err2 = p.handler.Query(args.Req); err2 != nil {
+	if retval, err2 = p.handler.Query(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing query: "+err2.Error())
+			oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("query", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorAggregateRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeAggregateRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeAggregateRawResult{}
+	var retval *AggregateQueryRawResult_
+	var err2 error
+	if retval, err2 = p.handler.AggregateRaw(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing query: "+err2.Error())
-			oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateRaw: "+err2.Error())
+			oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -13655,7 +14711,7 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("query", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("aggregateRaw", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -13673,16 +14729,16 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	return true, err
 }
 
-type nodeProcessorAggregateRaw struct {
+type nodeProcessorAggregate struct {
 	handler Node
 }
 
-func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeAggregateRawArgs{}
+func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeAggregateArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -13690,16 +14746,16 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeAggregateRawResult{}
-	var retval *AggregateQueryRawResult_
+	result := NodeAggregateResult{}
+	var retval *AggregateQueryResult_
 	var err2 error
-	if retval, err2 = p.handler.AggregateRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.Aggregate(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateRaw: "+err2.Error())
-			oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregate: "+err2.Error())
+			oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -13708,7 +14764,7 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("aggregateRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("aggregate", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -13726,16 +14782,16 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	return true, err
 }
 
-type nodeProcessorAggregate struct {
+type nodeProcessorCardinality struct {
 	handler Node
 }
 
-func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeAggregateArgs{}
+func (p *nodeProcessorCardinality) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeCardinalityArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("cardinality", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -13743,16 +14799,16 @@ func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProto
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeAggregateResult{}
-	var retval *AggregateQueryResult_
+	result := NodeCardinalityResult{}
+	var retval *CardinalityResult_
 	var err2 error
-	if retval, err2 = p.handler.Aggregate(args.Req); err2 != nil {
+	if retval, err2 = p.handler.Cardinality(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregate: "+err2.Error())
-			oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing cardinality: "+err2.Error())
+			oprot.WriteMessageBegin("cardinality", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -13761,7 +14817,7 @@ func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProto
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("aggregate", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("cardinality", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -15697,6 +16753,259 @@ func (p *NodeAggregateResult) String() string {
 	return fmt.Sprintf("NodeAggregateResult(%+v)", *p)
 }
 
+// Attributes:
+//  - Req
+type NodeCardinalityArgs struct {
+	Req *CardinalityRequest `thrift:"req,1" db:"req" json:"req"`
+}
+
+func NewNodeCardinalityArgs() *NodeCardinalityArgs {
+	return &NodeCardinalityArgs{}
+}
+
+var NodeCardinalityArgs_Req_DEFAULT *CardinalityRequest
+
+func (p *NodeCardinalityArgs) GetReq() *CardinalityRequest {
+	if !p.IsSetReq() {
+		return NodeCardinalityArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeCardinalityArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeCardinalityArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &CardinalityRequest{
+		RangeType: 0,
+	}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("cardinality_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeCardinalityArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeCardinalityArgs(%+v)", *p)
+}
+
+// Attributes:
+//  - Success
+//  - Err
+type NodeCardinalityResult struct {
+	Success *CardinalityResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                    `thrift:"err,1" db:"err" json:"err,omitempty"`
+}
+
+func NewNodeCardinalityResult() *NodeCardinalityResult {
+	return &NodeCardinalityResult{}
+}
+
+var NodeCardinalityResult_Success_DEFAULT *CardinalityResult_
+
+func (p *NodeCardinalityResult) GetSuccess() *CardinalityResult_ {
+	if !p.IsSetSuccess() {
+		return NodeCardinalityResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeCardinalityResult_Err_DEFAULT *Error
+
+func (p *NodeCardinalityResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeCardinalityResult_Err_DEFAULT
+	}
+	return p.Err
+}
+func (p *NodeCardinalityResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeCardinalityResult) IsSetErr() bool {
+	return p.Err != nil
+}
+
+func (p *NodeCardinalityResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &CardinalityResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("cardinality_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *NodeCardinalityResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeCardinalityResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeCardinalityResult) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeCardinalityResult(%+v)", *p)
+}
+
 // Attributes:
 //  - Req
 type NodeFetchArgs struct {
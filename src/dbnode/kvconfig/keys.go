@@ -33,6 +33,11 @@ const (
 	// configuration specifying a hard limit for a cluster new series insertions.
 	ClusterNewSeriesInsertLimitKey = "m3db.node.cluster-new-series-insert-limit"
 
+	// SeriesLimitsKey is the KV config key for the runtime configuration
+	// specifying the hard limits on active series per namespace and per
+	// tag value as a YAML encoded string.
+	SeriesLimitsKey = "m3db.node.series-limits"
+
 	// ClientBootstrapConsistencyLevel is the KV config key for the runtime
 	// configuration specifying the client bootstrap consistency level
 	ClientBootstrapConsistencyLevel = "m3db.client.bootstrap-consistency-level"
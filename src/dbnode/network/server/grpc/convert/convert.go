@@ -0,0 +1,509 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package convert converts between the gRPC node protobuf types and the
+// TChannel Thrift node types so both transports can share the same handlers.
+package convert
+
+import (
+	"errors"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// ToRPCTimeType converts a protobuf time type to a thrift time type.
+func ToRPCTimeType(value nodepb.TimeType) rpc.TimeType {
+	switch value {
+	case nodepb.TimeType_UNIX_MICROSECONDS:
+		return rpc.TimeType_UNIX_MICROSECONDS
+	case nodepb.TimeType_UNIX_MILLISECONDS:
+		return rpc.TimeType_UNIX_MILLISECONDS
+	case nodepb.TimeType_UNIX_NANOSECONDS:
+		return rpc.TimeType_UNIX_NANOSECONDS
+	default:
+		return rpc.TimeType_UNIX_SECONDS
+	}
+}
+
+// FromRPCTimeType converts a thrift time type to a protobuf time type.
+func FromRPCTimeType(value rpc.TimeType) nodepb.TimeType {
+	switch value {
+	case rpc.TimeType_UNIX_MICROSECONDS:
+		return nodepb.TimeType_UNIX_MICROSECONDS
+	case rpc.TimeType_UNIX_MILLISECONDS:
+		return nodepb.TimeType_UNIX_MILLISECONDS
+	case rpc.TimeType_UNIX_NANOSECONDS:
+		return nodepb.TimeType_UNIX_NANOSECONDS
+	default:
+		return nodepb.TimeType_UNIX_SECONDS
+	}
+}
+
+// ToRPCDatapoint converts a protobuf datapoint to a thrift datapoint.
+func ToRPCDatapoint(dp *nodepb.Datapoint) *rpc.Datapoint {
+	if dp == nil {
+		return nil
+	}
+	return &rpc.Datapoint{
+		Timestamp:         dp.Timestamp,
+		Value:             dp.Value,
+		Annotation:        dp.Annotation,
+		TimestampTimeType: ToRPCTimeType(dp.TimestampTimeType),
+	}
+}
+
+// FromRPCDatapoint converts a thrift datapoint to a protobuf datapoint.
+func FromRPCDatapoint(dp *rpc.Datapoint) *nodepb.Datapoint {
+	if dp == nil {
+		return nil
+	}
+	return &nodepb.Datapoint{
+		Timestamp:         dp.Timestamp,
+		Value:             dp.Value,
+		Annotation:        dp.Annotation,
+		TimestampTimeType: FromRPCTimeType(dp.TimestampTimeType),
+	}
+}
+
+// ToRPCWriteRequest converts a protobuf write request to a thrift write request.
+func ToRPCWriteRequest(req *nodepb.WriteRequest) *rpc.WriteRequest {
+	return &rpc.WriteRequest{
+		NameSpace: req.NameSpace,
+		ID:        req.Id,
+		Datapoint: ToRPCDatapoint(req.Datapoint),
+	}
+}
+
+// FromRPCWriteRequest converts a thrift write request to a protobuf write request.
+func FromRPCWriteRequest(req *rpc.WriteRequest) *nodepb.WriteRequest {
+	return &nodepb.WriteRequest{
+		NameSpace: req.NameSpace,
+		Id:        req.ID,
+		Datapoint: FromRPCDatapoint(req.Datapoint),
+	}
+}
+
+// ToRPCWriteTaggedRequest converts a protobuf write tagged request to a
+// thrift write tagged request.
+func ToRPCWriteTaggedRequest(req *nodepb.WriteTaggedRequest) *rpc.WriteTaggedRequest {
+	var tags []*rpc.Tag
+	if req.Tags != nil {
+		tags = make([]*rpc.Tag, 0, len(req.Tags))
+		for _, tag := range req.Tags {
+			tags = append(tags, &rpc.Tag{Name: tag.Name, Value: tag.Value})
+		}
+	}
+	return &rpc.WriteTaggedRequest{
+		NameSpace: req.NameSpace,
+		ID:        req.Id,
+		Tags:      tags,
+		Datapoint: ToRPCDatapoint(req.Datapoint),
+	}
+}
+
+// FromRPCWriteTaggedRequest converts a thrift write tagged request to a
+// protobuf write tagged request.
+func FromRPCWriteTaggedRequest(req *rpc.WriteTaggedRequest) *nodepb.WriteTaggedRequest {
+	tags := make([]*nodepb.Tag, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		tags = append(tags, &nodepb.Tag{Name: tag.Name, Value: tag.Value})
+	}
+	return &nodepb.WriteTaggedRequest{
+		NameSpace: req.NameSpace,
+		Id:        req.ID,
+		Tags:      tags,
+		Datapoint: FromRPCDatapoint(req.Datapoint),
+	}
+}
+
+// ToRPCWriteBatchRawRequest converts a protobuf write batch request to a
+// thrift write batch raw request.
+func ToRPCWriteBatchRawRequest(req *nodepb.WriteBatchRequest) *rpc.WriteBatchRawRequest {
+	elements := make([]*rpc.WriteBatchRawRequestElement, 0, len(req.Elements))
+	for _, elem := range req.Elements {
+		elements = append(elements, &rpc.WriteBatchRawRequestElement{
+			ID:        elem.Id,
+			Datapoint: ToRPCDatapoint(elem.Datapoint),
+		})
+	}
+	return &rpc.WriteBatchRawRequest{
+		NameSpace: req.NameSpace,
+		Elements:  elements,
+	}
+}
+
+// FromRPCWriteBatchRawRequest converts a thrift write batch raw request to a
+// protobuf write batch request.
+func FromRPCWriteBatchRawRequest(req *rpc.WriteBatchRawRequest) *nodepb.WriteBatchRequest {
+	elements := make([]*nodepb.WriteBatchRequestElement, 0, len(req.Elements))
+	for _, elem := range req.Elements {
+		elements = append(elements, &nodepb.WriteBatchRequestElement{
+			Id:        elem.ID,
+			Datapoint: FromRPCDatapoint(elem.Datapoint),
+		})
+	}
+	return &nodepb.WriteBatchRequest{
+		NameSpace: req.NameSpace,
+		Elements:  elements,
+	}
+}
+
+// ToRPCWriteTaggedBatchRawRequest converts a protobuf write batch request to a
+// thrift write tagged batch raw request.
+func ToRPCWriteTaggedBatchRawRequest(req *nodepb.WriteBatchRequest) *rpc.WriteTaggedBatchRawRequest {
+	elements := make([]*rpc.WriteTaggedBatchRawRequestElement, 0, len(req.Elements))
+	for _, elem := range req.Elements {
+		elements = append(elements, &rpc.WriteTaggedBatchRawRequestElement{
+			ID:          elem.Id,
+			EncodedTags: elem.EncodedTags,
+			Datapoint:   ToRPCDatapoint(elem.Datapoint),
+		})
+	}
+	return &rpc.WriteTaggedBatchRawRequest{
+		NameSpace: req.NameSpace,
+		Elements:  elements,
+	}
+}
+
+// FromRPCWriteTaggedBatchRawRequest converts a thrift write tagged batch raw
+// request to a protobuf write batch request.
+func FromRPCWriteTaggedBatchRawRequest(req *rpc.WriteTaggedBatchRawRequest) *nodepb.WriteBatchRequest {
+	elements := make([]*nodepb.WriteBatchRequestElement, 0, len(req.Elements))
+	for _, elem := range req.Elements {
+		elements = append(elements, &nodepb.WriteBatchRequestElement{
+			Id:          elem.ID,
+			EncodedTags: elem.EncodedTags,
+			Datapoint:   FromRPCDatapoint(elem.Datapoint),
+		})
+	}
+	return &nodepb.WriteBatchRequest{
+		NameSpace: req.NameSpace,
+		Elements:  elements,
+	}
+}
+
+// ToRPCWriteBatchRawErrors converts the errors of a protobuf write batch
+// response to thrift write batch raw errors, or nil if no element failed.
+func ToRPCWriteBatchRawErrors(res *nodepb.WriteBatchResponse) *rpc.WriteBatchRawErrors {
+	if len(res.Errors) == 0 {
+		return nil
+	}
+	result := rpc.NewWriteBatchRawErrors()
+	result.Errors = make([]*rpc.WriteBatchRawError, 0, len(res.Errors))
+	for _, err := range res.Errors {
+		result.Errors = append(result.Errors, &rpc.WriteBatchRawError{
+			Index: err.Index,
+			Err:   ToRPCError(err.Err),
+		})
+	}
+	return result
+}
+
+// FromRPCWriteBatchRawErrors converts thrift write batch raw errors to a
+// protobuf write batch response.
+func FromRPCWriteBatchRawErrors(errs *rpc.WriteBatchRawErrors) *nodepb.WriteBatchResponse {
+	result := &nodepb.WriteBatchResponse{
+		Errors: make([]*nodepb.WriteBatchError, 0, len(errs.Errors)),
+	}
+	for _, err := range errs.Errors {
+		result.Errors = append(result.Errors, &nodepb.WriteBatchError{
+			Index: err.Index,
+			Err:   FromRPCError(err.Err),
+		})
+	}
+	return result
+}
+
+// ToRPCFetchTaggedRequest converts a protobuf fetch tagged request to a
+// thrift fetch tagged request.
+func ToRPCFetchTaggedRequest(req *nodepb.FetchTaggedRequest) *rpc.FetchTaggedRequest {
+	result := &rpc.FetchTaggedRequest{
+		NameSpace:     req.NameSpace,
+		Query:         req.Query,
+		RangeStart:    req.RangeStart,
+		RangeEnd:      req.RangeEnd,
+		FetchData:     req.FetchData,
+		RangeTimeType: ToRPCTimeType(req.RangeTimeType),
+	}
+	if req.Limit > 0 {
+		limit := req.Limit
+		result.Limit = &limit
+	}
+	return result
+}
+
+// FromRPCFetchTaggedRequest converts a thrift fetch tagged request to a
+// protobuf fetch tagged request.
+func FromRPCFetchTaggedRequest(req *rpc.FetchTaggedRequest) *nodepb.FetchTaggedRequest {
+	result := &nodepb.FetchTaggedRequest{
+		NameSpace:     req.NameSpace,
+		Query:         req.Query,
+		RangeStart:    req.RangeStart,
+		RangeEnd:      req.RangeEnd,
+		FetchData:     req.FetchData,
+		RangeTimeType: FromRPCTimeType(req.RangeTimeType),
+	}
+	if req.Limit != nil {
+		result.Limit = *req.Limit
+	}
+	return result
+}
+
+// ToRPCFetchTaggedIDResult converts a protobuf fetch tagged ID result to a
+// thrift fetch tagged ID result.
+func ToRPCFetchTaggedIDResult(elem *nodepb.FetchTaggedIDResult) *rpc.FetchTaggedIDResult_ {
+	result := &rpc.FetchTaggedIDResult_{
+		ID:          elem.Id,
+		NameSpace:   elem.NameSpace,
+		EncodedTags: elem.EncodedTags,
+	}
+	if elem.Segments != nil {
+		result.Segments = make([]*rpc.Segments, 0, len(elem.Segments))
+		for _, segs := range elem.Segments {
+			result.Segments = append(result.Segments, toRPCSegments(segs))
+		}
+	}
+	if elem.Err != nil {
+		result.Err = ToRPCError(elem.Err)
+	}
+	return result
+}
+
+// FromRPCFetchTaggedIDResult converts a thrift fetch tagged ID result to a
+// protobuf fetch tagged ID result.
+func FromRPCFetchTaggedIDResult(elem *rpc.FetchTaggedIDResult_) *nodepb.FetchTaggedIDResult {
+	result := &nodepb.FetchTaggedIDResult{
+		Id:          elem.ID,
+		NameSpace:   elem.NameSpace,
+		EncodedTags: elem.EncodedTags,
+	}
+	if elem.Segments != nil {
+		result.Segments = make([]*nodepb.Segments, 0, len(elem.Segments))
+		for _, segs := range elem.Segments {
+			result.Segments = append(result.Segments, fromRPCSegments(segs))
+		}
+	}
+	if elem.Err != nil {
+		result.Err = FromRPCError(elem.Err)
+	}
+	return result
+}
+
+func toRPCSegments(segs *nodepb.Segments) *rpc.Segments {
+	result := &rpc.Segments{}
+	if segs.Merged != nil {
+		result.Merged = toRPCSegment(segs.Merged)
+	}
+	if segs.Unmerged != nil {
+		result.Unmerged = make([]*rpc.Segment, 0, len(segs.Unmerged))
+		for _, seg := range segs.Unmerged {
+			result.Unmerged = append(result.Unmerged, toRPCSegment(seg))
+		}
+	}
+	return result
+}
+
+func toRPCSegment(seg *nodepb.Segment) *rpc.Segment {
+	result := &rpc.Segment{
+		Head: seg.Head,
+		Tail: seg.Tail,
+	}
+	if seg.StartTime != 0 {
+		startTime := seg.StartTime
+		result.StartTime = &startTime
+	}
+	if seg.BlockSize != 0 {
+		blockSize := seg.BlockSize
+		result.BlockSize = &blockSize
+	}
+	return result
+}
+
+func fromRPCSegments(segs *rpc.Segments) *nodepb.Segments {
+	result := &nodepb.Segments{}
+	if segs.Merged != nil {
+		result.Merged = fromRPCSegment(segs.Merged)
+	}
+	if segs.Unmerged != nil {
+		result.Unmerged = make([]*nodepb.Segment, 0, len(segs.Unmerged))
+		for _, seg := range segs.Unmerged {
+			result.Unmerged = append(result.Unmerged, fromRPCSegment(seg))
+		}
+	}
+	return result
+}
+
+func fromRPCSegment(seg *rpc.Segment) *nodepb.Segment {
+	result := &nodepb.Segment{
+		Head: seg.Head,
+		Tail: seg.Tail,
+	}
+	if seg.StartTime != nil {
+		result.StartTime = *seg.StartTime
+	}
+	if seg.BlockSize != nil {
+		result.BlockSize = *seg.BlockSize
+	}
+	return result
+}
+
+// ToRPCAggregateQueryRawRequest converts a protobuf aggregate request to a
+// thrift aggregate raw request.
+func ToRPCAggregateQueryRawRequest(req *nodepb.AggregateRequest) *rpc.AggregateQueryRawRequest {
+	result := &rpc.AggregateQueryRawRequest{
+		Query:         req.Query,
+		RangeStart:    req.RangeStart,
+		RangeEnd:      req.RangeEnd,
+		NameSpace:     req.NameSpace,
+		TagNameFilter: req.TagNameFilter,
+		RangeType:     ToRPCTimeType(req.RangeTimeType),
+	}
+	if req.Limit > 0 {
+		limit := req.Limit
+		result.Limit = &limit
+	}
+	switch req.AggregateQueryType {
+	case nodepb.AggregateQueryType_AGGREGATE_BY_TAG_NAME:
+		result.AggregateQueryType = rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME
+	default:
+		result.AggregateQueryType = rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE
+	}
+	return result
+}
+
+// FromRPCAggregateQueryRawRequest converts a thrift aggregate raw request to
+// a protobuf aggregate request.
+func FromRPCAggregateQueryRawRequest(req *rpc.AggregateQueryRawRequest) *nodepb.AggregateRequest {
+	result := &nodepb.AggregateRequest{
+		Query:         req.Query,
+		RangeStart:    req.RangeStart,
+		RangeEnd:      req.RangeEnd,
+		NameSpace:     req.NameSpace,
+		TagNameFilter: req.TagNameFilter,
+		RangeTimeType: FromRPCTimeType(req.RangeType),
+	}
+	if req.Limit != nil {
+		result.Limit = *req.Limit
+	}
+	switch req.AggregateQueryType {
+	case rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME:
+		result.AggregateQueryType = nodepb.AggregateQueryType_AGGREGATE_BY_TAG_NAME
+	default:
+		result.AggregateQueryType = nodepb.AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE
+	}
+	return result
+}
+
+// ToRPCAggregateQueryRawResult converts a protobuf aggregate response to a
+// thrift aggregate raw result.
+func ToRPCAggregateQueryRawResult(res *nodepb.AggregateResponse) *rpc.AggregateQueryRawResult_ {
+	result := &rpc.AggregateQueryRawResult_{
+		Results:    make([]*rpc.AggregateQueryRawResultTagNameElement, 0, len(res.Results)),
+		Exhaustive: res.Exhaustive,
+	}
+	for _, elem := range res.Results {
+		tagValues := make([]*rpc.AggregateQueryRawResultTagValueElement, 0, len(elem.TagValues))
+		for _, value := range elem.TagValues {
+			tagValues = append(tagValues, &rpc.AggregateQueryRawResultTagValueElement{
+				TagValue: value,
+			})
+		}
+		result.Results = append(result.Results, &rpc.AggregateQueryRawResultTagNameElement{
+			TagName:   elem.TagName,
+			TagValues: tagValues,
+		})
+	}
+	return result
+}
+
+// FromRPCAggregateQueryRawResult converts a thrift aggregate raw result to a
+// protobuf aggregate response.
+func FromRPCAggregateQueryRawResult(res *rpc.AggregateQueryRawResult_) *nodepb.AggregateResponse {
+	result := &nodepb.AggregateResponse{
+		Results:    make([]*nodepb.AggregateTagNameElement, 0, len(res.Results)),
+		Exhaustive: res.Exhaustive,
+	}
+	for _, elem := range res.Results {
+		tagValues := make([][]byte, 0, len(elem.TagValues))
+		for _, value := range elem.TagValues {
+			tagValues = append(tagValues, value.TagValue)
+		}
+		result.Results = append(result.Results, &nodepb.AggregateTagNameElement{
+			TagName:   elem.TagName,
+			TagValues: tagValues,
+		})
+	}
+	return result
+}
+
+// ToRPCError converts a protobuf error to a thrift error.
+func ToRPCError(err *nodepb.Error) *rpc.Error {
+	var result *rpc.Error
+	if err.Type == nodepb.ErrorType_BAD_REQUEST {
+		result = tterrors.NewBadRequestError(errors.New(err.Message))
+	} else {
+		result = tterrors.NewInternalError(errors.New(err.Message))
+	}
+	result.Flags = err.Flags
+	return result
+}
+
+// FromRPCError converts a thrift error to a protobuf error.
+func FromRPCError(err *rpc.Error) *nodepb.Error {
+	result := &nodepb.Error{Message: err.Message, Flags: err.Flags}
+	if tterrors.IsBadRequestError(err) {
+		result.Type = nodepb.ErrorType_BAD_REQUEST
+	}
+	return result
+}
+
+// ToGRPCError converts an error returned by a thrift handler to a gRPC error,
+// bad request errors are returned with the invalid argument code so that
+// clients know not to retry them and series limit exceeded errors are
+// returned with the resource exhausted code.
+func ToGRPCError(err error) error {
+	rpcErr, ok := err.(*rpc.Error)
+	if !ok {
+		return grpc.Errorf(codes.Internal, "%s", err.Error())
+	}
+	if tterrors.IsSeriesLimitExceededError(rpcErr) {
+		return grpc.Errorf(codes.ResourceExhausted, "%s", rpcErr.Message)
+	}
+	if tterrors.IsBadRequestError(rpcErr) {
+		return grpc.Errorf(codes.InvalidArgument, "%s", rpcErr.Message)
+	}
+	return grpc.Errorf(codes.Internal, "%s", rpcErr.Message)
+}
+
+// FromGRPCError converts an error returned by a gRPC call to a thrift error.
+func FromGRPCError(err error) *rpc.Error {
+	switch grpc.Code(err) {
+	case codes.InvalidArgument:
+		return tterrors.NewBadRequestError(errors.New(grpc.ErrorDesc(err)))
+	case codes.ResourceExhausted:
+		return tterrors.NewSeriesLimitExceededError(errors.New(grpc.ErrorDesc(err)))
+	}
+	return tterrors.NewInternalError(errors.New(grpc.ErrorDesc(err)))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package convert_test
+
+import (
+	"errors"
+	"testing"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWriteTaggedRequestRoundTrip(t *testing.T) {
+	req := &rpc.WriteTaggedRequest{
+		NameSpace: "ns",
+		ID:        "foo",
+		Tags: []*rpc.Tag{
+			{Name: "a", Value: "b"},
+			{Name: "c", Value: "d"},
+		},
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         1000,
+			Value:             42.5,
+			Annotation:        []byte("annotation"),
+			TimestampTimeType: rpc.TimeType_UNIX_MILLISECONDS,
+		},
+	}
+
+	pb := convert.FromRPCWriteTaggedRequest(req)
+	assert.Equal(t, nodepb.TimeType_UNIX_MILLISECONDS, pb.Datapoint.TimestampTimeType)
+	assert.Equal(t, req, convert.ToRPCWriteTaggedRequest(pb))
+}
+
+func TestConvertFetchTaggedRequestLimit(t *testing.T) {
+	limit := int64(10)
+	req := &rpc.FetchTaggedRequest{
+		NameSpace:     []byte("ns"),
+		Query:         []byte("query"),
+		RangeStart:    1,
+		RangeEnd:      2,
+		FetchData:     true,
+		Limit:         &limit,
+		RangeTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+	}
+	assert.Equal(t, req, convert.ToRPCFetchTaggedRequest(convert.FromRPCFetchTaggedRequest(req)))
+
+	// Zero limit in protobuf means no limit.
+	req.Limit = nil
+	assert.Equal(t, req, convert.ToRPCFetchTaggedRequest(convert.FromRPCFetchTaggedRequest(req)))
+}
+
+func TestConvertFetchTaggedIDResultRoundTrip(t *testing.T) {
+	startTime, blockSize := int64(100), int64(200)
+	elem := &rpc.FetchTaggedIDResult_{
+		ID:          []byte("foo"),
+		NameSpace:   []byte("ns"),
+		EncodedTags: []byte("tags"),
+		Segments: []*rpc.Segments{
+			{Merged: &rpc.Segment{
+				Head:      []byte("head"),
+				Tail:      []byte("tail"),
+				StartTime: &startTime,
+				BlockSize: &blockSize,
+			}},
+			{Unmerged: []*rpc.Segment{
+				{Head: []byte("a"), Tail: []byte("b")},
+				{Head: []byte("c"), Tail: []byte("d")},
+			}},
+		},
+		Err: tterrors.NewBadRequestError(errors.New("bad")),
+	}
+
+	result := convert.ToRPCFetchTaggedIDResult(convert.FromRPCFetchTaggedIDResult(elem))
+	assert.Equal(t, elem, result)
+}
+
+func TestConvertAggregateRoundTrip(t *testing.T) {
+	limit := int64(5)
+	req := &rpc.AggregateQueryRawRequest{
+		Query:              []byte("query"),
+		RangeStart:         1,
+		RangeEnd:           2,
+		NameSpace:          []byte("ns"),
+		Limit:              &limit,
+		TagNameFilter:      [][]byte{[]byte("a")},
+		AggregateQueryType: rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME,
+		RangeType:          rpc.TimeType_UNIX_SECONDS,
+	}
+	assert.Equal(t, req, convert.ToRPCAggregateQueryRawRequest(
+		convert.FromRPCAggregateQueryRawRequest(req)))
+
+	// The zero value must map to the thrift default of tag names and values.
+	pb := convert.FromRPCAggregateQueryRawRequest(req)
+	pb.AggregateQueryType = 0
+	assert.Equal(t, rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE,
+		convert.ToRPCAggregateQueryRawRequest(pb).AggregateQueryType)
+
+	res := &rpc.AggregateQueryRawResult_{
+		Results: []*rpc.AggregateQueryRawResultTagNameElement{
+			{
+				TagName: []byte("a"),
+				TagValues: []*rpc.AggregateQueryRawResultTagValueElement{
+					{TagValue: []byte("b")},
+					{TagValue: []byte("c")},
+				},
+			},
+		},
+		Exhaustive: true,
+	}
+	assert.Equal(t, res, convert.ToRPCAggregateQueryRawResult(
+		convert.FromRPCAggregateQueryRawResult(res)))
+}
+
+func TestConvertGRPCErrors(t *testing.T) {
+	badRequest := convert.FromGRPCError(
+		convert.ToGRPCError(tterrors.NewBadRequestError(errors.New("bad"))))
+	require.True(t, tterrors.IsBadRequestError(badRequest))
+	require.False(t, tterrors.IsSeriesLimitExceededError(badRequest))
+	assert.Equal(t, "bad", badRequest.Message)
+
+	seriesLimit := convert.FromGRPCError(
+		convert.ToGRPCError(tterrors.NewSeriesLimitExceededError(errors.New("limit"))))
+	require.True(t, tterrors.IsBadRequestError(seriesLimit))
+	require.True(t, tterrors.IsSeriesLimitExceededError(seriesLimit))
+	assert.Equal(t, "limit", seriesLimit.Message)
+
+	internal := convert.FromGRPCError(
+		convert.ToGRPCError(tterrors.NewInternalError(errors.New("internal"))))
+	require.True(t, tterrors.IsInternalError(internal))
+	assert.Equal(t, "internal", internal.Message)
+
+	other := convert.FromGRPCError(convert.ToGRPCError(errors.New("other")))
+	require.True(t, tterrors.IsInternalError(other))
+	assert.Equal(t, "other", other.Message)
+}
+
+func TestConvertRPCErrorRoundTrip(t *testing.T) {
+	rpcErr := tterrors.NewSeriesLimitExceededError(errors.New("limit"))
+	result := convert.ToRPCError(convert.FromRPCError(rpcErr))
+	require.True(t, tterrors.IsBadRequestError(result))
+	require.True(t, tterrors.IsSeriesLimitExceededError(result))
+	assert.Equal(t, "limit", result.Message)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultFetchTaggedBatchSize = 128
+
+	// defaultMaxRecvMsgSize is the default max size of a received message,
+	// well above the gRPC default of 4mb since batched writes regularly
+	// exceed it.
+	defaultMaxRecvMsgSize = 64 * 1024 * 1024
+
+	// defaultMaxSendMsgSize is the default max size of a sent message.
+	defaultMaxSendMsgSize = 64 * 1024 * 1024
+)
+
+// Options is a set of gRPC node server options.
+type Options interface {
+	// SetFetchTaggedBatchSize sets the max number of series sent per
+	// streamed fetch tagged response and returns a new Options.
+	SetFetchTaggedBatchSize(value int) Options
+
+	// FetchTaggedBatchSize returns the max number of series sent per
+	// streamed fetch tagged response.
+	FetchTaggedBatchSize() int
+
+	// SetMaxRecvMsgSize sets the max size in bytes of a message the server
+	// receives and returns a new Options.
+	SetMaxRecvMsgSize(value int) Options
+
+	// MaxRecvMsgSize returns the max size in bytes of a message the server
+	// receives.
+	MaxRecvMsgSize() int
+
+	// SetMaxSendMsgSize sets the max size in bytes of a message the server
+	// sends and returns a new Options.
+	SetMaxSendMsgSize(value int) Options
+
+	// MaxSendMsgSize returns the max size in bytes of a message the server
+	// sends.
+	MaxSendMsgSize() int
+
+	// SetServerOptions sets the gRPC server options and returns a new Options.
+	SetServerOptions(value []grpc.ServerOption) Options
+
+	// ServerOptions returns the gRPC server options.
+	ServerOptions() []grpc.ServerOption
+}
+
+type options struct {
+	fetchTaggedBatchSize int
+	maxRecvMsgSize       int
+	maxSendMsgSize       int
+	serverOptions        []grpc.ServerOption
+}
+
+// NewOptions creates a new set of gRPC node server options with defaults.
+func NewOptions() Options {
+	return &options{
+		fetchTaggedBatchSize: defaultFetchTaggedBatchSize,
+		maxRecvMsgSize:       defaultMaxRecvMsgSize,
+		maxSendMsgSize:       defaultMaxSendMsgSize,
+	}
+}
+
+func (o *options) SetFetchTaggedBatchSize(value int) Options {
+	opts := *o
+	opts.fetchTaggedBatchSize = value
+	return &opts
+}
+
+func (o *options) FetchTaggedBatchSize() int {
+	return o.fetchTaggedBatchSize
+}
+
+func (o *options) SetMaxRecvMsgSize(value int) Options {
+	opts := *o
+	opts.maxRecvMsgSize = value
+	return &opts
+}
+
+func (o *options) MaxRecvMsgSize() int {
+	return o.maxRecvMsgSize
+}
+
+func (o *options) SetMaxSendMsgSize(value int) Options {
+	opts := *o
+	opts.maxSendMsgSize = value
+	return &opts
+}
+
+func (o *options) MaxSendMsgSize() int {
+	return o.maxSendMsgSize
+}
+
+func (o *options) SetServerOptions(value []grpc.ServerOption) Options {
+	opts := *o
+	opts.serverOptions = value
+	return &opts
+}
+
+func (o *options) ServerOptions() []grpc.ServerOption {
+	return o.serverOptions
+}
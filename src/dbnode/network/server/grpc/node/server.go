@@ -0,0 +1,85 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"net"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	ns "github.com/m3db/m3/src/dbnode/network/server"
+	"github.com/m3db/m3/src/x/context"
+
+	"google.golang.org/grpc"
+)
+
+type server struct {
+	service     rpc.TChanNode
+	address     string
+	contextPool context.Pool
+	opts        Options
+}
+
+// NewServer creates a new node gRPC network service that serves requests
+// using the given node service
+func NewServer(
+	service rpc.TChanNode,
+	address string,
+	contextPool context.Pool,
+	opts Options,
+) ns.NetworkService {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	return &server{
+		service:     service,
+		address:     address,
+		contextPool: contextPool,
+		opts:        opts,
+	}
+}
+
+func (s *server) ListenAndServe() (ns.Close, error) {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := s.newGRPCServer()
+	go func() {
+		server.Serve(listener)
+	}()
+
+	return server.Stop, nil
+}
+
+// newGRPCServer returns a gRPC server with the node service registered,
+// the configured server options are applied after the message size limits
+// so that they take precedence.
+func (s *server) newGRPCServer() *grpc.Server {
+	serverOpts := append([]grpc.ServerOption{
+		grpc.MaxRecvMsgSize(s.opts.MaxRecvMsgSize()),
+		grpc.MaxSendMsgSize(s.opts.MaxSendMsgSize()),
+	}, s.opts.ServerOptions()...)
+	server := grpc.NewServer(serverOpts...)
+	nodepb.RegisterNodeServer(server, newService(s.service, s.contextPool, s.opts))
+	return server
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package node
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/x/context"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestServerLargeMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Send and receive more than the 4mb gRPC default max message size.
+	const valueSize = 1024 * 1024
+	tagValues := make([]*rpc.AggregateQueryRawResultTagValueElement, 0, 6)
+	for i := 0; i < cap(tagValues); i++ {
+		tagValues = append(tagValues, &rpc.AggregateQueryRawResultTagValueElement{
+			TagValue: make([]byte, valueSize),
+		})
+	}
+	mockService := rpc.NewMockTChanNode(ctrl)
+	mockService.EXPECT().
+		AggregateRaw(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ thrift.Context, req *rpc.AggregateQueryRawRequest) (*rpc.AggregateQueryRawResult_, error) {
+			assert.Equal(t, 6*valueSize, len(req.Query))
+			return &rpc.AggregateQueryRawResult_{
+				Results: []*rpc.AggregateQueryRawResultTagNameElement{
+					{TagName: []byte("a"), TagValues: tagValues},
+				},
+				Exhaustive: true,
+			}, nil
+		})
+
+	s := NewServer(mockService, "127.0.0.1:0",
+		context.NewPool(context.NewOptions()), nil).(*server)
+	grpcServer := s.newGRPCServer()
+	defer grpcServer.Stop()
+
+	listener, err := net.Listen("tcp", s.address)
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(),
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(defaultMaxSendMsgSize),
+			grpc.MaxCallSendMsgSize(defaultMaxRecvMsgSize),
+		))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := xnetcontext.WithTimeout(xnetcontext.Background(), time.Minute)
+	defer cancel()
+
+	res, err := nodepb.NewNodeClient(conn).Aggregate(ctx, &nodepb.AggregateRequest{
+		NameSpace: []byte("ns"),
+		Query:     make([]byte, 6*valueSize),
+	})
+	require.NoError(t, err)
+	require.True(t, res.Exhaustive)
+	require.Equal(t, 1, len(res.Results))
+	assert.Equal(t, len(tagValues), len(res.Results[0].TagValues))
+}
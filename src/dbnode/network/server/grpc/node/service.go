@@ -0,0 +1,199 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/x/context"
+
+	"github.com/uber/tchannel-go/thrift"
+	xnetcontext "golang.org/x/net/context"
+)
+
+type service struct {
+	service              rpc.TChanNode
+	contextPool          context.Pool
+	fetchTaggedBatchSize int
+}
+
+// newService returns a gRPC node service that delegates each request to
+// the TChannel Thrift node service so both transports share the same
+// handlers, metrics and limits.
+func newService(
+	svc rpc.TChanNode,
+	contextPool context.Pool,
+	opts Options,
+) nodepb.NodeServer {
+	return &service{
+		service:              svc,
+		contextPool:          contextPool,
+		fetchTaggedBatchSize: opts.FetchTaggedBatchSize(),
+	}
+}
+
+// newContext returns a thrift context with a pooled M3DB context embedded
+// in the same way the TChannel server does, the returned func must be
+// called once the response has been sent so any resources referenced by
+// the response are released.
+func (s *service) newContext(ctx xnetcontext.Context) (thrift.Context, func()) {
+	xCtx := s.contextPool.Get()
+	xCtx.SetGoContext(ctx)
+	return tchannelthrift.NewContextWithValue(ctx, xCtx), xCtx.Close
+}
+
+func (s *service) Health(
+	ctx xnetcontext.Context,
+	req *nodepb.HealthRequest,
+) (*nodepb.HealthResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	result, err := s.service.Health(tctx)
+	if err != nil {
+		return nil, convert.ToGRPCError(err)
+	}
+
+	return &nodepb.HealthResponse{
+		Ok:           result.Ok,
+		Status:       result.Status,
+		Bootstrapped: result.Bootstrapped,
+	}, nil
+}
+
+func (s *service) Write(
+	ctx xnetcontext.Context,
+	req *nodepb.WriteRequest,
+) (*nodepb.WriteResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	if err := s.service.Write(tctx, convert.ToRPCWriteRequest(req)); err != nil {
+		return nil, convert.ToGRPCError(err)
+	}
+
+	return &nodepb.WriteResponse{}, nil
+}
+
+func (s *service) WriteTagged(
+	ctx xnetcontext.Context,
+	req *nodepb.WriteTaggedRequest,
+) (*nodepb.WriteResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	if err := s.service.WriteTagged(tctx, convert.ToRPCWriteTaggedRequest(req)); err != nil {
+		return nil, convert.ToGRPCError(err)
+	}
+
+	return &nodepb.WriteResponse{}, nil
+}
+
+func (s *service) WriteBatch(
+	ctx xnetcontext.Context,
+	req *nodepb.WriteBatchRequest,
+) (*nodepb.WriteBatchResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	err := s.service.WriteBatchRaw(tctx, convert.ToRPCWriteBatchRawRequest(req))
+	return writeBatchResponse(err)
+}
+
+func (s *service) WriteTaggedBatch(
+	ctx xnetcontext.Context,
+	req *nodepb.WriteBatchRequest,
+) (*nodepb.WriteBatchResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	err := s.service.WriteTaggedBatchRaw(tctx, convert.ToRPCWriteTaggedBatchRawRequest(req))
+	return writeBatchResponse(err)
+}
+
+// writeBatchResponse returns the per element errors of a batch write in the
+// response, so that only errors failing the whole batch fail the call.
+func writeBatchResponse(err error) (*nodepb.WriteBatchResponse, error) {
+	if err == nil {
+		return &nodepb.WriteBatchResponse{}, nil
+	}
+	if batchErrs, ok := err.(*rpc.WriteBatchRawErrors); ok {
+		return convert.FromRPCWriteBatchRawErrors(batchErrs), nil
+	}
+	return nil, convert.ToGRPCError(err)
+}
+
+func (s *service) FetchTagged(
+	req *nodepb.FetchTaggedRequest,
+	stream nodepb.Node_FetchTaggedServer,
+) error {
+	tctx, done := s.newContext(stream.Context())
+	defer done()
+
+	result, err := s.service.FetchTagged(tctx, convert.ToRPCFetchTaggedRequest(req))
+	if err != nil {
+		return convert.ToGRPCError(err)
+	}
+
+	// NB: Always send at least one response so that the client receives the
+	// exhaustive flag even when no series matched.
+	elements := result.Elements
+	for {
+		size := len(elements)
+		if s.fetchTaggedBatchSize > 0 && size > s.fetchTaggedBatchSize {
+			size = s.fetchTaggedBatchSize
+		}
+
+		response := &nodepb.FetchTaggedResponse{
+			Elements:   make([]*nodepb.FetchTaggedIDResult, 0, size),
+			Exhaustive: result.Exhaustive,
+		}
+		for _, elem := range elements[:size] {
+			response.Elements = append(response.Elements,
+				convert.FromRPCFetchTaggedIDResult(elem))
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+
+		elements = elements[size:]
+		if len(elements) == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *service) Aggregate(
+	ctx xnetcontext.Context,
+	req *nodepb.AggregateRequest,
+) (*nodepb.AggregateResponse, error) {
+	tctx, done := s.newContext(ctx)
+	defer done()
+
+	result, err := s.service.AggregateRaw(tctx, convert.ToRPCAggregateQueryRawRequest(req))
+	if err != nil {
+		return nil, convert.ToGRPCError(err)
+	}
+
+	return convert.FromRPCAggregateQueryRawResult(result), nil
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	nodepb "github.com/m3db/m3/src/dbnode/generated/proto/node"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/x/context"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type testFetchTaggedStream struct {
+	grpc.ServerStream
+
+	responses []*nodepb.FetchTaggedResponse
+}
+
+func (s *testFetchTaggedStream) Context() xnetcontext.Context {
+	return xnetcontext.Background()
+}
+
+func (s *testFetchTaggedStream) Send(m *nodepb.FetchTaggedResponse) error {
+	s.responses = append(s.responses, m)
+	return nil
+}
+
+func newTestService(ctrl *gomock.Controller, opts Options) (nodepb.NodeServer, *rpc.MockTChanNode) {
+	mockService := rpc.NewMockTChanNode(ctrl)
+	contextPool := context.NewPool(context.NewOptions())
+	return newService(mockService, contextPool, opts), mockService
+}
+
+func TestServiceWriteTagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		WriteTagged(gomock.Any(), &rpc.WriteTaggedRequest{
+			NameSpace: "ns",
+			ID:        "foo",
+			Tags:      []*rpc.Tag{{Name: "a", Value: "b"}},
+			Datapoint: &rpc.Datapoint{
+				Timestamp:         10,
+				Value:             1.5,
+				TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			},
+		}).
+		DoAndReturn(func(tctx thrift.Context, _ *rpc.WriteTaggedRequest) error {
+			// Handlers must be able to retrieve the embedded M3DB context.
+			require.NotNil(t, tchannelthrift.Context(tctx))
+			return nil
+		})
+
+	_, err := svc.WriteTagged(xnetcontext.Background(), &nodepb.WriteTaggedRequest{
+		NameSpace: "ns",
+		Id:        "foo",
+		Tags:      []*nodepb.Tag{{Name: "a", Value: "b"}},
+		Datapoint: &nodepb.Datapoint{Timestamp: 10, Value: 1.5},
+	})
+	require.NoError(t, err)
+}
+
+func TestServiceWriteBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		Write(gomock.Any(), gomock.Any()).
+		Return(tterrors.NewBadRequestError(errors.New("requires datapoint")))
+
+	_, err := svc.Write(xnetcontext.Background(), &nodepb.WriteRequest{
+		NameSpace: "ns",
+		Id:        "foo",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, "requires datapoint", grpc.ErrorDesc(err))
+}
+
+func TestServiceWriteTaggedBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		WriteTaggedBatchRaw(gomock.Any(), &rpc.WriteTaggedBatchRawRequest{
+			NameSpace: []byte("ns"),
+			Elements: []*rpc.WriteTaggedBatchRawRequestElement{
+				{
+					ID:          []byte("foo"),
+					EncodedTags: []byte("tags"),
+					Datapoint: &rpc.Datapoint{
+						Timestamp:         10,
+						Value:             1.5,
+						TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+					},
+				},
+				{ID: []byte("bar"), EncodedTags: []byte("tags")},
+			},
+		}).
+		Return(&rpc.WriteBatchRawErrors{
+			Errors: []*rpc.WriteBatchRawError{
+				{Index: 1, Err: tterrors.NewBadRequestError(errors.New("requires datapoint"))},
+			},
+		})
+
+	res, err := svc.WriteTaggedBatch(xnetcontext.Background(), &nodepb.WriteBatchRequest{
+		NameSpace: []byte("ns"),
+		Elements: []*nodepb.WriteBatchRequestElement{
+			{
+				Id:          []byte("foo"),
+				EncodedTags: []byte("tags"),
+				Datapoint:   &nodepb.Datapoint{Timestamp: 10, Value: 1.5},
+			},
+			{Id: []byte("bar"), EncodedTags: []byte("tags")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*nodepb.WriteBatchError{
+		{
+			Index: 1,
+			Err:   &nodepb.Error{Type: nodepb.ErrorType_BAD_REQUEST, Message: "requires datapoint"},
+		},
+	}, res.Errors)
+}
+
+func TestServiceWriteBatchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		WriteBatchRaw(gomock.Any(), gomock.Any()).
+		Return(tterrors.NewInternalError(errors.New("not bootstrapped")))
+
+	_, err := svc.WriteBatch(xnetcontext.Background(), &nodepb.WriteBatchRequest{
+		NameSpace: []byte("ns"),
+		Elements:  []*nodepb.WriteBatchRequestElement{{Id: []byte("foo")}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Internal, grpc.Code(err))
+}
+
+func TestServiceFetchTaggedStreamsBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions().SetFetchTaggedBatchSize(2))
+
+	result := &rpc.FetchTaggedResult_{Exhaustive: true}
+	for i := 0; i < 5; i++ {
+		result.Elements = append(result.Elements, &rpc.FetchTaggedIDResult_{
+			ID:        []byte(fmt.Sprintf("id%d", i)),
+			NameSpace: []byte("ns"),
+		})
+	}
+	mockService.EXPECT().
+		FetchTagged(gomock.Any(), gomock.Any()).
+		Return(result, nil)
+
+	stream := &testFetchTaggedStream{}
+	err := svc.FetchTagged(&nodepb.FetchTaggedRequest{NameSpace: []byte("ns")}, stream)
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(stream.responses))
+	var ids []string
+	for i, response := range stream.responses {
+		assert.True(t, response.Exhaustive)
+		if i < 2 {
+			assert.Equal(t, 2, len(response.Elements))
+		}
+		for _, elem := range response.Elements {
+			ids = append(ids, string(elem.Id))
+		}
+	}
+	assert.Equal(t, []string{"id0", "id1", "id2", "id3", "id4"}, ids)
+}
+
+func TestServiceFetchTaggedEmptyResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		FetchTagged(gomock.Any(), gomock.Any()).
+		Return(&rpc.FetchTaggedResult_{Exhaustive: false}, nil)
+
+	stream := &testFetchTaggedStream{}
+	err := svc.FetchTagged(&nodepb.FetchTaggedRequest{NameSpace: []byte("ns")}, stream)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(stream.responses))
+	assert.Equal(t, 0, len(stream.responses[0].Elements))
+	assert.False(t, stream.responses[0].Exhaustive)
+}
+
+func TestServiceAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, mockService := newTestService(ctrl, NewOptions())
+
+	mockService.EXPECT().
+		AggregateRaw(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ thrift.Context, req *rpc.AggregateQueryRawRequest) (*rpc.AggregateQueryRawResult_, error) {
+			assert.Equal(t, rpc.AggregateQueryType_AGGREGATE_BY_TAG_NAME_VALUE, req.AggregateQueryType)
+			return &rpc.AggregateQueryRawResult_{
+				Results: []*rpc.AggregateQueryRawResultTagNameElement{
+					{
+						TagName: []byte("a"),
+						TagValues: []*rpc.AggregateQueryRawResultTagValueElement{
+							{TagValue: []byte("b")},
+						},
+					},
+				},
+				Exhaustive: true,
+			}, nil
+		})
+
+	res, err := svc.Aggregate(xnetcontext.Background(), &nodepb.AggregateRequest{
+		NameSpace: []byte("ns"),
+	})
+	require.NoError(t, err)
+	require.True(t, res.Exhaustive)
+	require.Equal(t, 1, len(res.Results))
+	assert.Equal(t, "a", string(res.Results[0].TagName))
+	assert.Equal(t, [][]byte{[]byte("b")}, res.Results[0].TagValues)
+}
@@ -130,7 +130,7 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 	}
 
 	if req.NoData != nil && *req.NoData {
-		results, exhaustive, err := session.FetchTaggedIDs(nsID,
+		results, exhaustive, err := session.FetchTaggedIDsWithContext(tctx, nsID,
 			index.Query{Query: q}, opts)
 		if err != nil {
 			return nil, convert.ToRPCError(err)
@@ -165,7 +165,7 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 		return result, nil
 	}
 
-	results, exhaustive, err := session.FetchTagged(nsID,
+	results, exhaustive, err := session.FetchTaggedWithContext(tctx, nsID,
 		index.Query{Query: q}, opts)
 	if err != nil {
 		return nil, convert.ToRPCError(err)
@@ -280,7 +280,7 @@ func (s *service) Aggregate(ctx thrift.Context, req *rpc.AggregateQueryRequest)
 		return nil, tterrors.NewBadRequestError(err)
 	}
 
-	iter, exhaustive, err := session.Aggregate(ns, query, opts)
+	iter, exhaustive, err := session.AggregateWithContext(ctx, ns, query, opts)
 	if err != nil {
 		return nil, convert.ToRPCError(err)
 	}
@@ -54,6 +54,13 @@ func NewContext(timeout time.Duration) (thrift.Context, xnetcontext.CancelFunc)
 	return thrift.WithHeaders(ctxWithValue, nil), cancel
 }
 
+// NewContextWithValue returns a new thrift context derived from the given context
+// with the M3DB context embedded, the caller is responsible for closing the M3DB context
+func NewContextWithValue(ctx xnetcontext.Context, value context.Context) thrift.Context {
+	ctxWithValue := xnetcontext.WithValue(ctx, contextKey, value)
+	return thrift.WithHeaders(ctxWithValue, nil)
+}
+
 // Context returns an M3DB context from the thrift context
 func Context(ctx thrift.Context) context.Context {
 	return ctx.Value(contextKey).(context.Context)
@@ -368,6 +368,108 @@ func FromRPCAggregateQueryRawRequest(
 	return ns, index.Query{Query: query}, opts, nil
 }
 
+// FromRPCCardinalityRequest converts the rpc request type for CardinalityRequest into corresponding Go types.
+func FromRPCCardinalityRequest(
+	req *rpc.CardinalityRequest,
+	pools FetchTaggedConversionPools,
+) (ident.ID, index.CardinalityOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, fetchTaggedTimeType)
+	if rangeStartErr != nil {
+		return nil, index.CardinalityOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, fetchTaggedTimeType)
+	if rangeEndErr != nil {
+		return nil, index.CardinalityOptions{}, rangeEndErr
+	}
+
+	opts := index.CardinalityOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		},
+		NameField: req.NameField,
+	}
+	if l := req.Limit; l != nil {
+		opts.Limit = int(*l)
+	}
+	if n := req.TopN; n != nil {
+		opts.TopN = int(*n)
+	}
+
+	var ns ident.ID
+	if pools != nil {
+		nsBytes := pools.CheckedBytesWrapper().Get(req.NameSpace)
+		ns = pools.ID().BinaryID(nsBytes)
+	} else {
+		ns = ident.StringID(string(req.NameSpace))
+	}
+	return ns, opts, nil
+}
+
+// ToRPCCardinalityRequest converts the Go `client/` types into rpc request type for CardinalityRequest.
+func ToRPCCardinalityRequest(
+	ns ident.ID,
+	opts index.CardinalityOptions,
+) (rpc.CardinalityRequest, error) {
+	rangeStart, tsErr := ToValue(opts.StartInclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.CardinalityRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(opts.EndExclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.CardinalityRequest{}, tsErr
+	}
+
+	request := rpc.CardinalityRequest{
+		NameSpace:  ns.Bytes(),
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+	}
+
+	if opts.Limit > 0 {
+		l := int64(opts.Limit)
+		request.Limit = &l
+	}
+
+	if opts.TopN > 0 {
+		n := int64(opts.TopN)
+		request.TopN = &n
+	}
+
+	if len(opts.NameField) > 0 {
+		request.NameField = append([]byte(nil), opts.NameField...)
+	}
+
+	return request, nil
+}
+
+// ToRPCCardinalityResult converts the index cardinality stats into the rpc
+// result type for CardinalityResult.
+func ToRPCCardinalityResult(
+	result index.CardinalityQueryResult,
+) *rpc.CardinalityResult_ {
+	return &rpc.CardinalityResult_{
+		NumSeries:                   result.Stats.NumSeries,
+		SeriesCountByMetricName:     toRPCCardinalityEntries(result.Stats.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  toRPCCardinalityEntries(result.Stats.LabelValueCountByLabelName),
+		SeriesCountByLabelValuePair: toRPCCardinalityEntries(result.Stats.SeriesCountByLabelValuePair),
+		Exhaustive:                  result.Exhaustive,
+	}
+}
+
+func toRPCCardinalityEntries(entries []index.CardinalityEntry) []*rpc.CardinalityEntry {
+	result := make([]*rpc.CardinalityEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &rpc.CardinalityEntry{
+			Name:  []byte(entry.Name),
+			Value: entry.Value,
+		})
+	}
+	return result
+}
+
 // ToRPCAggregateQueryRawRequest converts the Go `client/` types into rpc request type for AggregateQueryRawRequest.
 func ToRPCAggregateQueryRawRequest(
 	ns ident.ID,
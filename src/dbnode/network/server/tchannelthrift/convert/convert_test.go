@@ -165,6 +165,75 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertCardinalityRequest(t *testing.T) {
+	ns := ident.StringID("abc")
+	opts := index.CardinalityOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: time.Now().Add(-900 * time.Hour),
+			EndExclusive:   time.Now(),
+			Limit:          100,
+		},
+		TopN:      10,
+		NameField: []byte("__name__"),
+	}
+	var (
+		limit int64 = 100
+		topN  int64 = 10
+	)
+	expected := rpc.CardinalityRequest{
+		NameSpace:  ns.Bytes(),
+		RangeStart: mustToRpcTime(t, opts.StartInclusive),
+		RangeEnd:   mustToRpcTime(t, opts.EndExclusive),
+		Limit:      &limit,
+		TopN:       &topN,
+		NameField:  []byte("__name__"),
+	}
+
+	observed, err := convert.ToRPCCardinalityRequest(ns, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "", cmp.Diff(expected, observed))
+
+	for _, pools := range []struct {
+		name string
+		pool convert.FetchTaggedConversionPools
+	}{
+		{"nil pools", nil},
+		{"valid pools", newTestPools()},
+	} {
+		t.Run(pools.name, func(t *testing.T) {
+			id, observedOpts, err := convert.FromRPCCardinalityRequest(&expected, pools.pool)
+			require.NoError(t, err)
+			require.Equal(t, ns.String(), id.String())
+			assert.Equal(t, "", cmp.Diff(opts, observedOpts))
+		})
+	}
+}
+
+func TestConvertCardinalityResult(t *testing.T) {
+	result := convert.ToRPCCardinalityResult(index.CardinalityQueryResult{
+		Stats: index.CardinalityStats{
+			NumSeries: 3,
+			SeriesCountByMetricName: []index.CardinalityEntry{
+				{Name: "up", Value: 3},
+			},
+			LabelValueCountByLabelName: []index.CardinalityEntry{
+				{Name: "job", Value: 2},
+			},
+		},
+		Exhaustive: true,
+	})
+
+	require.Equal(t, int64(3), result.NumSeries)
+	require.True(t, result.Exhaustive)
+	require.Equal(t, []*rpc.CardinalityEntry{
+		{Name: []byte("up"), Value: 3},
+	}, result.SeriesCountByMetricName)
+	require.Equal(t, []*rpc.CardinalityEntry{
+		{Name: []byte("job"), Value: 2},
+	}, result.LabelValueCountByLabelName)
+	require.Equal(t, []*rpc.CardinalityEntry{}, result.SeriesCountByLabelValuePair)
+}
+
 func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	ns := ident.StringID("abc")
 	opts := index.AggregationOptions{
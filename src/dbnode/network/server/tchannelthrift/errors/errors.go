@@ -43,6 +43,12 @@ func IsBadRequestError(err *rpc.Error) bool {
 	return err != nil && err.Type == rpc.ErrorType_BAD_REQUEST
 }
 
+// IsSeriesLimitExceededError returns whether the error is a series limit exceeded error
+func IsSeriesLimitExceededError(err *rpc.Error) bool {
+	return err != nil &&
+		err.Flags&int64(rpc.ErrorFlags_SERIES_LIMIT_EXCEEDED) != 0
+}
+
 // NewInternalError creates a new internal error
 func NewInternalError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_INTERNAL_ERROR, err)
@@ -53,6 +59,14 @@ func NewBadRequestError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_BAD_REQUEST, err)
 }
 
+// NewSeriesLimitExceededError creates a new bad request error flagged
+// as a series limit exceeded error
+func NewSeriesLimitExceededError(err error) *rpc.Error {
+	rpcErr := newError(rpc.ErrorType_BAD_REQUEST, err)
+	rpcErr.Flags = int64(rpc.ErrorFlags_SERIES_LIMIT_EXCEEDED)
+	return rpcErr
+}
+
 // NewWriteBatchRawError creates a new write batch error
 func NewWriteBatchRawError(index int, err error) *rpc.WriteBatchRawError {
 	batchErr := rpc.NewWriteBatchRawError()
@@ -68,3 +82,12 @@ func NewBadRequestWriteBatchRawError(index int, err error) *rpc.WriteBatchRawErr
 	batchErr.Err = NewBadRequestError(err)
 	return batchErr
 }
+
+// NewSeriesLimitExceededWriteBatchRawError creates a new series limit
+// exceeded write batch error
+func NewSeriesLimitExceededWriteBatchRawError(index int, err error) *rpc.WriteBatchRawError {
+	batchErr := rpc.NewWriteBatchRawError()
+	batchErr.Index = int64(index)
+	batchErr.Err = NewSeriesLimitExceededError(err)
+	return batchErr
+}
@@ -103,6 +103,7 @@ type serviceMetrics struct {
 	fetch                   instrument.MethodMetrics
 	fetchTagged             instrument.MethodMetrics
 	aggregate               instrument.MethodMetrics
+	cardinality             instrument.MethodMetrics
 	write                   instrument.MethodMetrics
 	writeTagged             instrument.MethodMetrics
 	fetchBlocks             instrument.MethodMetrics
@@ -123,6 +124,7 @@ func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 		fetch:                   instrument.NewMethodMetrics(scope, "fetch", samplingRate),
 		fetchTagged:             instrument.NewMethodMetrics(scope, "fetchTagged", samplingRate),
 		aggregate:               instrument.NewMethodMetrics(scope, "aggregate", samplingRate),
+		cardinality:             instrument.NewMethodMetrics(scope, "cardinality", samplingRate),
 		write:                   instrument.NewMethodMetrics(scope, "write", samplingRate),
 		writeTagged:             instrument.NewMethodMetrics(scope, "writeTagged", samplingRate),
 		fetchBlocks:             instrument.NewMethodMetrics(scope, "fetchBlocks", samplingRate),
@@ -748,6 +750,33 @@ func (s *service) AggregateRaw(tctx thrift.Context, req *rpc.AggregateQueryRawRe
 	return response, nil
 }
 
+func (s *service) Cardinality(tctx thrift.Context, req *rpc.CardinalityRequest) (*rpc.CardinalityResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted()
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	ns, opts, err := convert.FromRPCCardinalityRequest(req, s.pools)
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	queryResult, err := db.CardinalityQuery(ctx, ns, opts)
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	response := convert.ToRPCCardinalityResult(queryResult)
+	s.metrics.cardinality.ReportSuccess(s.nowFn().Sub(callStart))
+	return response, nil
+}
+
 func (s *service) encodeTags(
 	enc serialize.TagEncoder,
 	tags ident.TagIterator,
@@ -1080,7 +1109,7 @@ func (s *service) Write(tctx thrift.Context, req *rpc.WriteRequest) error {
 		dp.Annotation,
 	); err != nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
-		return convert.ToRPCError(err)
+		return toRPCWriteError(err)
 	}
 
 	s.metrics.write.ReportSuccess(s.nowFn().Sub(callStart))
@@ -1134,7 +1163,7 @@ func (s *service) WriteTagged(tctx thrift.Context, req *rpc.WriteTaggedRequest)
 		iter, xtime.FromNormalizedTime(dp.Timestamp, d),
 		dp.Value, unit, dp.Annotation); err != nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return convert.ToRPCError(err)
+		return toRPCWriteError(err)
 	}
 
 	s.metrics.writeTagged.ReportSuccess(s.nowFn().Sub(callStart))
@@ -1705,6 +1734,16 @@ func (c closeableMetadataV2Result) Finalize() {
 	c.s.pools.blockMetadataV2Slice.Put(c.result.Elements)
 }
 
+// toRPCWriteError converts a write error to an RPC error, flagging series
+// limit exceeded errors so that clients can tell them apart from other bad
+// requests.
+func toRPCWriteError(err error) *rpc.Error {
+	if storage.IsSeriesLimitExceededError(err) {
+		return tterrors.NewSeriesLimitExceededError(err)
+	}
+	return convert.ToRPCError(err)
+}
+
 type writeBatchPooledReq struct {
 	pooledIDs      []writeBatchPooledReqID
 	pooledIDsUsed  int
@@ -1807,6 +1846,14 @@ func (r *writeBatchPooledReq) HandleError(index int, err error) {
 		return
 	}
 
+	if storage.IsSeriesLimitExceededError(err) {
+		r.nonRetryableErrors++
+		r.errs = append(
+			r.errs,
+			tterrors.NewSeriesLimitExceededWriteBatchRawError(index, err))
+		return
+	}
+
 	if xerrors.IsInvalidParams(err) {
 		r.nonRetryableErrors++
 		r.errs = append(
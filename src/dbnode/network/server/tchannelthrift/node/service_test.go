@@ -46,6 +46,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/x/checked"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/serialize"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -1807,6 +1808,67 @@ func TestServiceAggregate(t *testing.T) {
 	require.Equal(t, 0, len(r.Results[1].TagValues))
 }
 
+func TestServiceCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	mockDB.EXPECT().CardinalityQuery(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.CardinalityOptions{
+			QueryOptions: index.QueryOptions{
+				StartInclusive: start,
+				EndExclusive:   end,
+			},
+			TopN:      5,
+			NameField: []byte("__name__"),
+		}).Return(index.CardinalityQueryResult{
+		Stats: index.CardinalityStats{
+			NumSeries: 2,
+			SeriesCountByMetricName: []index.CardinalityEntry{
+				{Name: "up", Value: 2},
+			},
+		},
+		Exhaustive: true,
+	}, nil)
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	var topN int64 = 5
+	r, err := service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace:  []byte(nsID),
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		TopN:       &topN,
+		NameField:  []byte("__name__"),
+	})
+	require.NoError(t, err)
+
+	require.True(t, r.Exhaustive)
+	require.Equal(t, int64(2), r.NumSeries)
+	require.Equal(t, 1, len(r.SeriesCountByMetricName))
+	require.Equal(t, "up", string(r.SeriesCountByMetricName[0].Name))
+	require.Equal(t, int64(2), r.SeriesCountByMetricName[0].Value)
+}
+
 func TestServiceWrite(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1845,6 +1907,51 @@ func TestServiceWrite(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServiceWriteSeriesLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	nsID := "metrics"
+
+	id := "foo"
+
+	at := time.Now().Truncate(time.Second)
+	value := 42.42
+
+	mockDB.EXPECT().
+		Write(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher(id), at, value,
+			xtime.Second, nil).
+		Return(xerrors.NewInvalidParamsError(storage.SeriesLimitExceededError{
+			Namespace: nsID,
+			Limit:     1,
+		}))
+
+	mockDB.EXPECT().IsOverloaded().Return(false)
+	err := service.Write(tctx, &rpc.WriteRequest{
+		NameSpace: nsID,
+		ID:        id,
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         at.Unix(),
+			TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			Value:             value,
+		},
+	})
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsBadRequestError(rpcErr))
+	require.True(t, tterrors.IsSeriesLimitExceededError(rpcErr))
+}
+
 func TestServiceWriteOverloaded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
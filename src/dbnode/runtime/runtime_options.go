@@ -62,6 +62,10 @@ var (
 		"write new series backoff duration cannot be negative")
 	errWriteNewSeriesLimitPerShardPerSecondIsNegative = errors.New(
 		"write new series limit per shard per cannot be negative")
+	errSeriesLimitsIsNegative = errors.New(
+		"series limits cannot be negative")
+	errSeriesLimitsTagNameMissing = errors.New(
+		"series limits tag name must be set when limiting series per tag value")
 	errTickSeriesBatchSizeMustBePositive = errors.New(
 		"tick series batch size must be positive")
 	errTickPerSeriesSleepDurationMustBePositive = errors.New(
@@ -73,6 +77,7 @@ type options struct {
 	writeNewSeriesAsync                  bool
 	writeNewSeriesBackoffDuration        time.Duration
 	writeNewSeriesLimitPerShardPerSecond int
+	seriesLimits                         SeriesLimits
 	tickSeriesBatchSize                  int
 	tickPerSeriesSleepDuration           time.Duration
 	tickMinimumInterval                  time.Duration
@@ -115,6 +120,20 @@ func (o *options) Validate() error {
 		return errWriteNewSeriesLimitPerShardPerSecondIsNegative
 	}
 
+	// seriesLimits can be zero to specify that no limit should be enforced
+	if o.seriesLimits.MaxSeriesPerNamespace < 0 ||
+		o.seriesLimits.MaxSeriesPerTagValue < 0 {
+		return errSeriesLimitsIsNegative
+	}
+	for _, v := range o.seriesLimits.NamespaceMaxSeries {
+		if v < 0 {
+			return errSeriesLimitsIsNegative
+		}
+	}
+	if o.seriesLimits.MaxSeriesPerTagValue > 0 && o.seriesLimits.TagName == "" {
+		return errSeriesLimitsTagNameMissing
+	}
+
 	if !(o.tickSeriesBatchSize > 0) {
 		return errTickSeriesBatchSizeMustBePositive
 	}
@@ -168,6 +187,16 @@ func (o *options) WriteNewSeriesLimitPerShardPerSecond() int {
 	return o.writeNewSeriesLimitPerShardPerSecond
 }
 
+func (o *options) SetSeriesLimits(value SeriesLimits) Options {
+	opts := *o
+	opts.seriesLimits = value
+	return &opts
+}
+
+func (o *options) SeriesLimits() SeriesLimits {
+	return o.seriesLimits
+}
+
 func (o *options) SetTickSeriesBatchSize(value int) Options {
 	opts := *o
 	opts.tickSeriesBatchSize = value
@@ -77,6 +77,16 @@ type Options interface {
 	// time series being inserted.
 	WriteNewSeriesLimitPerShardPerSecond() int
 
+	// SetSeriesLimits sets the limits on the number of active series held
+	// per namespace and per value of a configurable tag, writes that would
+	// create a series beyond these limits are rejected.
+	SetSeriesLimits(value SeriesLimits) Options
+
+	// SeriesLimits returns the limits on the number of active series held
+	// per namespace and per value of a configurable tag, writes that would
+	// create a series beyond these limits are rejected.
+	SeriesLimits() SeriesLimits
+
 	// SetTickSeriesBatchSize sets the batch size to process series together
 	// during a tick before yielding and sleeping the per series duration
 	// multiplied by the batch size.
@@ -182,6 +192,35 @@ type Options interface {
 	FlushIndexBlockNumSegments() uint
 }
 
+// SeriesLimits is a set of hard limits on the number of active series
+// held by a node, zero values specify that no limit should be enforced.
+type SeriesLimits struct {
+	// MaxSeriesPerNamespace is the default maximum number of active series
+	// per namespace.
+	MaxSeriesPerNamespace int64
+
+	// NamespaceMaxSeries overrides MaxSeriesPerNamespace for the namespaces
+	// with a matching ID.
+	NamespaceMaxSeries map[string]int64
+
+	// TagName is the name of the tag to limit the number of active series
+	// per value of, i.e. "service".
+	TagName string
+
+	// MaxSeriesPerTagValue is the maximum number of active series per
+	// namespace that share the same value for TagName.
+	MaxSeriesPerTagValue int64
+}
+
+// MaxSeriesForNamespace returns the max number of active series for a
+// namespace, zero specifies no limit.
+func (l SeriesLimits) MaxSeriesForNamespace(namespace string) int64 {
+	if v, ok := l.NamespaceMaxSeries[namespace]; ok {
+		return v
+	}
+	return l.MaxSeriesPerNamespace
+}
+
 // OptionsManager updates and supplies runtime options.
 type OptionsManager interface {
 	// Update updates the current runtime options.
@@ -46,6 +46,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/kvconfig"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	grpcnode "github.com/m3db/m3/src/dbnode/network/server/grpc/node"
 	hjcluster "github.com/m3db/m3/src/dbnode/network/server/httpjson/cluster"
 	hjnode "github.com/m3db/m3/src/dbnode/network/server/httpjson/node"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
@@ -86,6 +87,7 @@ import (
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
+	yaml "gopkg.in/yaml.v2"
 )
 
 const (
@@ -344,7 +346,8 @@ func Run(runOpts RunOptions) {
 			SetLimitMbps(cfg.Filesystem.ThroughputLimitMbpsOrDefault()).
 			SetLimitCheckEvery(cfg.Filesystem.ThroughputCheckEveryOrDefault())).
 		SetWriteNewSeriesAsync(cfg.WriteNewSeriesAsync).
-		SetWriteNewSeriesBackoffDuration(cfg.WriteNewSeriesBackoffDuration)
+		SetWriteNewSeriesBackoffDuration(cfg.WriteNewSeriesBackoffDuration).
+		SetSeriesLimits(cfg.Limits.Series.RuntimeSeriesLimits())
 	if lruCfg := cfg.Cache.SeriesConfiguration().LRU; lruCfg != nil {
 		runtimeOpts = runtimeOpts.SetMaxWiredBlocks(lruCfg.MaxBlocks)
 	}
@@ -602,6 +605,24 @@ func Run(runOpts RunOptions) {
 	defer httpjsonNodeClose()
 	logger.Info("node httpjson: listening", zap.String("address", cfg.HTTPNodeListenAddress))
 
+	if cfg.GRPCNodeListenAddress != "" {
+		grpcNodeOpts := grpcnode.NewOptions()
+		if cfg.GRPCNodeMaxRecvMsgSize > 0 {
+			grpcNodeOpts = grpcNodeOpts.SetMaxRecvMsgSize(cfg.GRPCNodeMaxRecvMsgSize)
+		}
+		if cfg.GRPCNodeMaxSendMsgSize > 0 {
+			grpcNodeOpts = grpcNodeOpts.SetMaxSendMsgSize(cfg.GRPCNodeMaxSendMsgSize)
+		}
+		grpcNodeClose, err := grpcnode.NewServer(service,
+			cfg.GRPCNodeListenAddress, contextPool, grpcNodeOpts).ListenAndServe()
+		if err != nil {
+			logger.Fatal("could not open grpc interface",
+				zap.String("address", cfg.GRPCNodeListenAddress), zap.Error(err))
+		}
+		defer grpcNodeClose()
+		logger.Info("node grpc: listening", zap.String("address", cfg.GRPCNodeListenAddress))
+	}
+
 	if cfg.DebugListenAddress != "" {
 		go func() {
 			mux := http.DefaultServeMux
@@ -777,6 +798,8 @@ func Run(runOpts RunOptions) {
 			}
 		})
 
+	kvWatchSeriesLimits(syncCfg.KVStore, logger, runtimeOptsMgr, cfg.Limits.Series)
+
 	// Start the cluster services now that the M3DB client is available.
 	tchannelthriftClusterClose, err := ttcluster.NewServer(m3dbClient,
 		cfg.ClusterListenAddress, contextPool, tchannelOpts).ListenAndServe()
@@ -1025,6 +1048,31 @@ func kvWatchClientConsistencyLevels(
 		})
 }
 
+func kvWatchSeriesLimits(
+	store kv.Store,
+	logger *zap.Logger,
+	runtimeOptsMgr m3dbruntime.OptionsManager,
+	defaultLimits config.SeriesLimits,
+) {
+	setSeriesLimits := func(limits config.SeriesLimits) error {
+		return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+			SetSeriesLimits(limits.RuntimeSeriesLimits()))
+	}
+
+	kvWatchStringValue(store, logger,
+		kvconfig.SeriesLimitsKey,
+		func(value string) error {
+			var limits config.SeriesLimits
+			if err := yaml.Unmarshal([]byte(value), &limits); err != nil {
+				return fmt.Errorf("invalid series limits set: %v", err)
+			}
+			return setSeriesLimits(limits)
+		},
+		func() error {
+			return setSeriesLimits(defaultLimits)
+		})
+}
+
 func kvWatchStringValue(
 	store kv.Store,
 	logger *zap.Logger,
@@ -773,6 +773,20 @@ func (d *db) AggregateQuery(
 	return n.AggregateQuery(ctx, query, aggResultOpts)
 }
 
+func (d *db) CardinalityQuery(
+	ctx context.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityQueryResult, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceQueryIDs.Inc(1)
+		return index.CardinalityQueryResult{}, err
+	}
+
+	return n.CardinalityQuery(ctx, opts)
+}
+
 func (d *db) ReadEncoded(
 	ctx context.Context,
 	namespace ident.ID,
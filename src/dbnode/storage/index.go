@@ -1060,6 +1060,37 @@ func (i *nsIndex) AggregateQuery(
 	}, nil
 }
 
+func (i *nsIndex) CardinalityQuery(
+	ctx context.Context,
+	opts index.CardinalityOptions,
+) (index.CardinalityQueryResult, error) {
+	logFields := []opentracinglog.Field{
+		opentracinglog.String("namespace", i.nsMetadata.ID().String()),
+		opentracinglog.Int("limit", opts.Limit),
+		opentracinglog.Int("topN", opts.TopN),
+		xopentracing.Time("queryStart", opts.StartInclusive),
+		xopentracing.Time("queryEnd", opts.EndExclusive),
+	}
+
+	ctx, sp := ctx.StartTraceSpan(tracepoint.NSIdxCardinalityQuery)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	results := index.NewCardinalityResults(i.nsMetadata.ID(), i.opts.IndexOptions())
+	exhaustive, err := i.query(ctx, allQuery, results, opts.QueryOptions,
+		i.execBlockCardinalityQueryFn, logFields)
+	if err != nil {
+		return index.CardinalityQueryResult{}, err
+	}
+
+	stats := results.Stats(opts.TopN, opts.NameField)
+	results.Finalize()
+	return index.CardinalityQueryResult{
+		Stats:      stats,
+		Exhaustive: exhaustive,
+	}, nil
+}
+
 func (i *nsIndex) query(
 	ctx context.Context,
 	query index.Query,
@@ -1321,6 +1352,50 @@ func (i *nsIndex) execBlockAggregateQueryFn(
 	state.exhaustive = state.exhaustive && blockExhaustive
 }
 
+func (i *nsIndex) execBlockCardinalityQueryFn(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	block index.Block,
+	query index.Query,
+	opts index.QueryOptions,
+	state *asyncQueryExecState,
+	results index.BaseResults,
+	logFields []opentracinglog.Field,
+) {
+	logFields = append(logFields,
+		xopentracing.Time("blockStart", block.StartTime()),
+		xopentracing.Time("blockEnd", block.EndTime()),
+	)
+
+	ctx, sp := ctx.StartTraceSpan(tracepoint.NSIdxBlockCardinalityQuery)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	cardResults, ok := results.(index.CardinalityResults)
+	if !ok { // should never happen
+		state.Lock()
+		err := fmt.Errorf("unknown results type [%T] received during cardinality query", results)
+		state.multiErr = state.multiErr.Add(err)
+		state.Unlock()
+		return
+	}
+
+	blockExhaustive, err := block.Cardinality(ctx, cancellable, opts, cardResults, logFields)
+	if err == index.ErrUnableToQueryBlockClosed {
+		// NB: Same as aggregate queries, a block closed during the query has
+		// slid out of retention and its results are no longer valid.
+		err = nil
+	}
+
+	state.Lock()
+	defer state.Unlock()
+	if err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+		state.multiErr = state.multiErr.Add(err)
+	}
+	state.exhaustive = state.exhaustive && blockExhaustive
+}
+
 func (i *nsIndex) timeoutForQueryWithRLock(
 	ctx context.Context,
 ) time.Duration {
@@ -1135,6 +1135,128 @@ func (b *block) addAggregateResults(
 	return batch, size, nil
 }
 
+// Cardinality acquires a read lock on the block so that the segments
+// are guaranteed to not be freed/released while counting series.
+// NB: Like Aggregate, Cardinality relies on the FSTs underlying the index
+// and the size of each term's postings list rather than going to documents.
+// Counts are summed across the segments of the block.
+func (b *block) Cardinality(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	opts QueryOptions,
+	results CardinalityResults,
+	logFields []opentracinglog.Field,
+) (bool, error) {
+	_, sp := ctx.StartTraceSpan(tracepoint.BlockCardinality)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	exhaustive, err := b.cardinalityWithSpan(cancellable, opts, results)
+	if err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+	}
+
+	return exhaustive, err
+}
+
+func (b *block) cardinalityWithSpan(
+	cancellable *resource.CancellableLifetime,
+	opts QueryOptions,
+	results CardinalityResults,
+) (bool, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return false, ErrUnableToQueryBlockClosed
+	}
+
+	var (
+		counts = CardinalityCounts{
+			Fields: make(map[string]map[string]int64),
+		}
+		size       = results.Size()
+		numEntries = 0
+	)
+	for _, s := range b.segmentsWithRLock() {
+		counts.NumSeries += s.Size()
+		if err := b.addSegmentCardinality(s, &counts, &numEntries); err != nil {
+			return false, err
+		}
+		if opts.LimitExceeded(size + numEntries) {
+			break
+		}
+	}
+
+	// checkout the lifetime of the query before adding results.
+	queryValid := cancellable.TryCheckout()
+	if !queryValid {
+		// query not valid any longer, do not add results and return early.
+		return false, errCancelledQuery
+	}
+
+	size = results.AddBlockCounts(counts)
+
+	// immediately release the checkout on the lifetime of query.
+	cancellable.ReleaseCheckout()
+
+	exhaustive := !opts.LimitExceeded(size)
+	return exhaustive, nil
+}
+
+func (b *block) addSegmentCardinality(
+	s segment.Segment,
+	counts *CardinalityCounts,
+	numEntries *int,
+) error {
+	fieldsIter, err := s.FieldsIterable().Fields()
+	if err != nil {
+		return err
+	}
+
+	for fieldsIter.Next() {
+		field := fieldsIter.Current()
+		// skip any field names that we shouldn't count.
+		if bytes.Equal(field, doc.IDReservedFieldName) {
+			continue
+		}
+
+		terms, ok := counts.Fields[string(field)]
+		if !ok {
+			terms = make(map[string]int64)
+			counts.Fields[string(field)] = terms
+		}
+
+		termsIter, err := s.TermsIterable().Terms(field)
+		if err != nil {
+			fieldsIter.Close()
+			return err
+		}
+		for termsIter.Next() {
+			term, pl := termsIter.Current()
+			if _, ok := terms[string(term)]; !ok {
+				*numEntries++
+			}
+			terms[string(term)] += int64(pl.Len())
+		}
+		if err := termsIter.Err(); err != nil {
+			termsIter.Close()
+			fieldsIter.Close()
+			return err
+		}
+		if err := termsIter.Close(); err != nil {
+			fieldsIter.Close()
+			return err
+		}
+	}
+
+	if err := fieldsIter.Err(); err != nil {
+		fieldsIter.Close()
+		return err
+	}
+	return fieldsIter.Close()
+}
+
 func (b *block) AddResults(
 	results result.IndexBlock,
 ) error {
@@ -1844,6 +1844,72 @@ func TestBlockE2EInsertAggregate(t *testing.T) {
 	require.Equal(t, tracepoint.BlockAggregate, spans[2].OperationName)
 }
 
+func TestBlockE2EInsertCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+
+	testMD := newTestNSMetadata(t)
+	now := time.Now()
+	blockStart := now.Truncate(blockSize)
+
+	nowNotBlockStartAligned := now.
+		Truncate(blockSize).
+		Add(time.Minute)
+
+	blk, err := NewBlock(blockStart, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for _, d := range []doc.Document{testDoc1(), testDoc2(), testDoc3()} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     nowNotBlockStartAligned,
+			OnIndexSeries: h,
+		}, d)
+	}
+
+	res, err := b.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), res.NumSuccess)
+
+	results := NewCardinalityResults(ident.StringID("ns"), testOpts)
+	exhaustive, err := b.Cardinality(context.NewContext(), resource.NewCancellableLifetime(),
+		QueryOptions{Limit: 10}, results, emptyLogFields)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+
+	stats := results.Stats(0, []byte("bar"))
+	require.Equal(t, int64(3), stats.NumSeries)
+	require.Equal(t, []CardinalityEntry{
+		{Name: "baz", Value: 2},
+		{Name: "qux", Value: 1},
+	}, stats.SeriesCountByMetricName)
+	require.Equal(t, []CardinalityEntry{
+		{Name: "bar", Value: 2},
+		{Name: "some", Value: 2},
+	}, stats.LabelValueCountByLabelName)
+
+	// A limit lower than the number of field and term pairs is not exhaustive.
+	results = NewCardinalityResults(ident.StringID("ns"), testOpts)
+	exhaustive, err = b.Cardinality(context.NewContext(), resource.NewCancellableLifetime(),
+		QueryOptions{Limit: 2}, results, emptyLogFields)
+	require.NoError(t, err)
+	require.False(t, exhaustive)
+
+	require.NoError(t, b.Close())
+	_, err = b.Cardinality(context.NewContext(), resource.NewCancellableLifetime(),
+		QueryOptions{}, results, emptyLogFields)
+	require.Equal(t, ErrUnableToQueryBlockClosed, err)
+}
+
 func assertAggregateResultsMapEquals(t *testing.T, expected map[string][]string, observed AggregateResults) {
 	aggResultsMap := observed.Map()
 	// ensure `expected` contained in `observed`
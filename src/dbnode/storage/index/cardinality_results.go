@@ -0,0 +1,178 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+var errCardinalityResultsAddDocuments = errors.New("cardinality results do not support adding documents")
+
+type cardinalityResults struct {
+	sync.RWMutex
+
+	nsID      ident.ID
+	numSeries int64
+	fields    map[string]map[string]int64
+	size      int
+
+	idPool ident.Pool
+}
+
+// NewCardinalityResults returns a new CardinalityResults object.
+func NewCardinalityResults(
+	namespaceID ident.ID,
+	opts Options,
+) CardinalityResults {
+	r := &cardinalityResults{
+		fields: make(map[string]map[string]int64),
+		idPool: opts.IdentifierPool(),
+	}
+	r.Reset(namespaceID)
+	return r
+}
+
+func (r *cardinalityResults) Reset(nsID ident.ID) {
+	r.Lock()
+
+	// finalize existing held nsID
+	if r.nsID != nil {
+		r.nsID.Finalize()
+	}
+
+	// make an independent copy of the new nsID
+	if nsID != nil {
+		nsID = r.idPool.Clone(nsID)
+	}
+	r.nsID = nsID
+
+	r.numSeries = 0
+	r.fields = make(map[string]map[string]int64)
+	r.size = 0
+
+	r.Unlock()
+}
+
+func (r *cardinalityResults) Namespace() ident.ID {
+	r.RLock()
+	ns := r.nsID
+	r.RUnlock()
+	return ns
+}
+
+// Size returns the number of distinct field and term pairs tracked.
+func (r *cardinalityResults) Size() int {
+	r.RLock()
+	size := r.size
+	r.RUnlock()
+	return size
+}
+
+func (r *cardinalityResults) AddDocuments(batch []doc.Document) (int, error) {
+	return r.Size(), errCardinalityResultsAddDocuments
+}
+
+func (r *cardinalityResults) AddBlockCounts(counts CardinalityCounts) int {
+	r.Lock()
+	if counts.NumSeries > r.numSeries {
+		r.numSeries = counts.NumSeries
+	}
+	for field, terms := range counts.Fields {
+		existing, ok := r.fields[field]
+		if !ok {
+			existing = make(map[string]int64, len(terms))
+			r.fields[field] = existing
+		}
+		for term, count := range terms {
+			curr, ok := existing[term]
+			if !ok {
+				r.size++
+			}
+			if count > curr {
+				existing[term] = count
+			}
+		}
+	}
+	size := r.size
+	r.Unlock()
+	return size
+}
+
+func (r *cardinalityResults) Stats(topN int, nameField []byte) CardinalityStats {
+	r.RLock()
+	defer r.RUnlock()
+
+	var (
+		byName      []CardinalityEntry
+		byLabelName = make([]CardinalityEntry, 0, len(r.fields))
+		byPair      []CardinalityEntry
+	)
+	for field, terms := range r.fields {
+		byLabelName = append(byLabelName, CardinalityEntry{
+			Name:  field,
+			Value: int64(len(terms)),
+		})
+		isName := field == string(nameField)
+		for term, count := range terms {
+			if isName {
+				byName = append(byName, CardinalityEntry{Name: term, Value: count})
+			}
+			byPair = append(byPair, CardinalityEntry{
+				Name:  field + "=" + term,
+				Value: count,
+			})
+		}
+	}
+
+	return CardinalityStats{
+		NumSeries:                   r.numSeries,
+		SeriesCountByMetricName:     topCardinalityEntries(byName, topN),
+		LabelValueCountByLabelName:  topCardinalityEntries(byLabelName, topN),
+		SeriesCountByLabelValuePair: topCardinalityEntries(byPair, topN),
+	}
+}
+
+func (r *cardinalityResults) Finalize() {
+	r.Reset(nil)
+}
+
+// topCardinalityEntries sorts entries by descending value, breaking ties by
+// name so results are stable, and truncates to at most n entries if n > 0.
+func topCardinalityEntries(entries []CardinalityEntry, n int) []CardinalityEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	if entries == nil {
+		entries = []CardinalityEntry{}
+	}
+	return entries
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityResultsAddDocumentsUnsupported(t *testing.T) {
+	res := NewCardinalityResults(nil, testOpts)
+	_, err := res.AddDocuments(nil)
+	require.Error(t, err)
+}
+
+func TestCardinalityResultsAddBlockCounts(t *testing.T) {
+	res := NewCardinalityResults(ident.StringID("ns"), testOpts)
+
+	size := res.AddBlockCounts(CardinalityCounts{
+		NumSeries: 3,
+		Fields: map[string]map[string]int64{
+			"__name__": {"up": 2, "cpu": 1},
+			"job":      {"a": 3},
+		},
+	})
+	require.Equal(t, 3, size)
+
+	// Counts from a second block are merged by taking the maximum.
+	size = res.AddBlockCounts(CardinalityCounts{
+		NumSeries: 4,
+		Fields: map[string]map[string]int64{
+			"__name__": {"up": 1, "cpu": 3},
+			"job":      {"a": 1, "b": 3},
+		},
+	})
+	require.Equal(t, 4, size)
+	require.Equal(t, 4, res.Size())
+
+	stats := res.Stats(0, []byte("__name__"))
+	assert.Equal(t, int64(4), stats.NumSeries)
+	assert.Equal(t, []CardinalityEntry{
+		{Name: "cpu", Value: 3},
+		{Name: "up", Value: 2},
+	}, stats.SeriesCountByMetricName)
+	assert.Equal(t, []CardinalityEntry{
+		{Name: "__name__", Value: 2},
+		{Name: "job", Value: 2},
+	}, stats.LabelValueCountByLabelName)
+	assert.Equal(t, []CardinalityEntry{
+		{Name: "__name__=cpu", Value: 3},
+		{Name: "job=a", Value: 3},
+		{Name: "job=b", Value: 3},
+		{Name: "__name__=up", Value: 2},
+	}, stats.SeriesCountByLabelValuePair)
+}
+
+func TestCardinalityResultsStatsTopN(t *testing.T) {
+	res := NewCardinalityResults(nil, testOpts)
+	res.AddBlockCounts(CardinalityCounts{
+		NumSeries: 6,
+		Fields: map[string]map[string]int64{
+			"__name__": {"a": 1, "b": 2, "c": 3},
+		},
+	})
+
+	stats := res.Stats(2, []byte("__name__"))
+	assert.Equal(t, []CardinalityEntry{
+		{Name: "c", Value: 3},
+		{Name: "b", Value: 2},
+	}, stats.SeriesCountByMetricName)
+	assert.Equal(t, 2, len(stats.SeriesCountByLabelValuePair))
+
+	stats = res.Stats(2, []byte("missing"))
+	assert.Equal(t, []CardinalityEntry{}, stats.SeriesCountByMetricName)
+}
+
+func TestCardinalityResultsReset(t *testing.T) {
+	res := NewCardinalityResults(ident.StringID("ns"), testOpts)
+	res.AddBlockCounts(CardinalityCounts{
+		NumSeries: 1,
+		Fields:    map[string]map[string]int64{"foo": {"bar": 1}},
+	})
+	require.Equal(t, 1, res.Size())
+
+	res.Reset(ident.StringID("other"))
+	require.Equal(t, 0, res.Size())
+	require.Equal(t, "other", res.Namespace().String())
+	require.Equal(t, int64(0), res.Stats(0, nil).NumSeries)
+}
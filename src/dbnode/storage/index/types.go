@@ -106,6 +106,46 @@ type AggregateQueryResult struct {
 	Exhaustive bool
 }
 
+// CardinalityOptions enables users to specify constraints on cardinality queries.
+type CardinalityOptions struct {
+	QueryOptions
+
+	// TopN is the number of entries returned in each cardinality list.
+	TopN int
+
+	// NameField is the field that metric names are indexed under.
+	NameField []byte
+}
+
+// CardinalityQueryResult is the result of a cardinality query.
+type CardinalityQueryResult struct {
+	Stats      CardinalityStats
+	Exhaustive bool
+}
+
+// CardinalityStats describes which metric names, fields and terms contribute
+// the most series to an index.
+type CardinalityStats struct {
+	// NumSeries is the number of series in the index.
+	NumSeries int64
+
+	// SeriesCountByMetricName is the number of series per metric name.
+	SeriesCountByMetricName []CardinalityEntry
+
+	// LabelValueCountByLabelName is the number of distinct terms per field.
+	LabelValueCountByLabelName []CardinalityEntry
+
+	// SeriesCountByLabelValuePair is the number of series per field and
+	// term, named as "field=term".
+	SeriesCountByLabelValuePair []CardinalityEntry
+}
+
+// CardinalityEntry is a single named count in a cardinality list.
+type CardinalityEntry struct {
+	Name  string
+	Value int64
+}
+
 // BaseResults is a collection of basic results for a generic query, it is
 // synchronized when access to the results set is used as documented by the
 // methods.
@@ -209,6 +249,31 @@ type AggregateResults interface {
 	Map() *AggregateResultsMap
 }
 
+// CardinalityResults is a collection of the number of series per field and
+// term of an index, it is synchronized when access to the results set is
+// used as documented by the methods.
+type CardinalityResults interface {
+	BaseResults
+
+	// Reset resets the CardinalityResults object to initial state.
+	Reset(nsID ident.ID)
+
+	// AddBlockCounts adds the counts of a single index block to the results,
+	// counts are combined across blocks by taking the maximum of each count
+	// since the same series is indexed in every block it is written to.
+	AddBlockCounts(counts CardinalityCounts) (size int)
+
+	// Stats returns the top N entries of each cardinality list.
+	Stats(topN int, nameField []byte) CardinalityStats
+}
+
+// CardinalityCounts is the number of series per field and term of a single
+// index block.
+type CardinalityCounts struct {
+	NumSeries int64
+	Fields    map[string]map[string]int64
+}
+
 // AggregateFieldFilter dictates which fields will appear in the aggregated
 // result; if filter values exist, only those whose fields matches a value in the
 // filter are returned.
@@ -329,6 +394,17 @@ type Block interface {
 		logFields []opentracinglog.Field,
 	) (exhaustive bool, err error)
 
+	// Cardinality counts the series per known tag name and value.
+	// NB: like Aggregate, this relies purely on the indexed FSTs and the
+	// size of their postings lists rather than going to documents.
+	Cardinality(
+		ctx context.Context,
+		cancellable *resource.CancellableLifetime,
+		opts QueryOptions,
+		results CardinalityResults,
+		logFields []opentracinglog.Field,
+	) (exhaustive bool, err error)
+
 	// AddResults adds bootstrap results to the block.
 	AddResults(results result.IndexBlock) error
 
@@ -129,6 +129,10 @@ type dbNamespace struct {
 	commitLogWriter commitLogWriter
 	reverseIndex    namespaceIndex
 
+	// seriesLimiter is shared by all shards to enforce the series limits.
+	seriesLimiter        *seriesLimiter
+	seriesLimitsListener xclose.SimpleCloser
+
 	tickWorkers            xsync.WorkerPool
 	tickWorkersConcurrency int
 	statsLastTick          databaseNamespaceStatsLastTick
@@ -162,6 +166,7 @@ type databaseNamespaceMetrics struct {
 	fetchBlocksMetadata instrument.MethodMetrics
 	queryIDs            instrument.MethodMetrics
 	aggregateQuery      instrument.MethodMetrics
+	cardinalityQuery    instrument.MethodMetrics
 	unfulfilled         tally.Counter
 	bootstrapStart      tally.Counter
 	bootstrapEnd        tally.Counter
@@ -241,6 +246,7 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 		fetchBlocksMetadata: instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		queryIDs:            instrument.NewMethodMetrics(scope, "queryIDs", samplingRate),
 		aggregateQuery:      instrument.NewMethodMetrics(scope, "aggregateQuery", samplingRate),
+		cardinalityQuery:    instrument.NewMethodMetrics(scope, "cardinalityQuery", samplingRate),
 		unfulfilled:         scope.Counter("bootstrap.unfulfilled"),
 		bootstrapStart:      scope.Counter("bootstrap.start"),
 		bootstrapEnd:        scope.Counter("bootstrap.end"),
@@ -347,6 +353,7 @@ func newDatabaseNamespace(
 		increasingIndex:        increasingIndex,
 		commitLogWriter:        commitLogWriter,
 		reverseIndex:           index,
+		seriesLimiter:          newSeriesLimiter(id, scope),
 		tickWorkers:            tickWorkers,
 		tickWorkersConcurrency: tickWorkersConcurrency,
 		metrics:                newDatabaseNamespaceMetrics(scope, iops.MetricsSamplingRate()),
@@ -361,6 +368,7 @@ func newDatabaseNamespace(
 			metadata.ID().String(), err)
 	}
 	n.schemaListener = sl
+	n.seriesLimitsListener = opts.RuntimeOptionsManager().RegisterListener(n.seriesLimiter)
 	n.initShards(nopts.BootstrapEnabled())
 	go n.reportStatusLoop(opts.InstrumentOptions().ReportInterval())
 
@@ -471,7 +479,7 @@ func (n *dbNamespace) AssignShardSet(shardSet sharding.ShardSet) {
 			bootstrapEnabled := n.nopts.BootstrapEnabled()
 			n.shards[shard] = newDatabaseShard(metadata, shard, n.blockRetriever,
 				n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex,
-				bootstrapEnabled, n.opts, n.seriesOpts)
+				n.seriesLimiter, bootstrapEnabled, n.opts, n.seriesOpts)
 			n.metrics.shards.add.Inc(1)
 		}
 	}
@@ -724,6 +732,28 @@ func (n *dbNamespace) AggregateQuery(
 	return res, err
 }
 
+func (n *dbNamespace) CardinalityQuery(
+	ctx context.Context,
+	opts index.CardinalityOptions,
+) (index.CardinalityQueryResult, error) {
+	callStart := n.nowFn()
+	if n.reverseIndex == nil { // only happens if indexing is enabled.
+		n.metrics.cardinalityQuery.ReportError(n.nowFn().Sub(callStart))
+		return index.CardinalityQueryResult{}, errNamespaceIndexingDisabled
+	}
+
+	if n.reverseIndex.BootstrapsDone() < 1 {
+		// Similar to reading shard data, return not bootstrapped
+		n.metrics.cardinalityQuery.ReportError(n.nowFn().Sub(callStart))
+		return index.CardinalityQueryResult{},
+			xerrors.NewRetryableError(errIndexNotBootstrappedToRead)
+	}
+
+	res, err := n.reverseIndex.CardinalityQuery(ctx, opts)
+	n.metrics.cardinalityQuery.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+	return res, err
+}
+
 func (n *dbNamespace) ReadEncoded(
 	ctx context.Context,
 	id ident.ID,
@@ -1362,7 +1392,7 @@ func (n *dbNamespace) initShards(needBootstrap bool) {
 	for _, shard := range shards {
 		dbShards[shard] = newDatabaseShard(n.metadata, shard, n.blockRetriever,
 			n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex,
-			needBootstrap, n.opts, n.seriesOpts)
+			n.seriesLimiter, needBootstrap, n.opts, n.seriesOpts)
 	}
 	n.shards = dbShards
 	n.Unlock()
@@ -1381,6 +1411,7 @@ func (n *dbNamespace) Close() error {
 	n.Unlock()
 	n.namespaceReaderMgr.close()
 	n.closeShards(shards, true)
+	n.seriesLimitsListener.Close()
 	close(n.shutdownCh)
 	if n.reverseIndex != nil {
 		return n.reverseIndex.Close()
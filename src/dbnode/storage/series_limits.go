@@ -0,0 +1,226 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/runtime"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/uber-go/tally"
+)
+
+// SeriesLimitExceededError is returned when a write would create a new
+// series beyond the active series limits of a namespace.
+type SeriesLimitExceededError struct {
+	// Namespace is the namespace the series was written to.
+	Namespace string
+	// TagName is the name of the limited tag, empty if the namespace
+	// limit was exceeded.
+	TagName string
+	// TagValue is the value of the limited tag, empty if the namespace
+	// limit was exceeded.
+	TagValue string
+	// Limit is the limit that was exceeded.
+	Limit int64
+}
+
+func (e SeriesLimitExceededError) Error() string {
+	if e.TagName == "" {
+		return fmt.Sprintf("series limit exceeded: namespace %s at max active series %d",
+			e.Namespace, e.Limit)
+	}
+	return fmt.Sprintf("series limit exceeded: namespace %s at max active series %d for %s=%s",
+		e.Namespace, e.Limit, e.TagName, e.TagValue)
+}
+
+// IsSeriesLimitExceededError returns true if the error is or contains
+// a series limit exceeded error.
+func IsSeriesLimitExceededError(err error) bool {
+	for err != nil {
+		if _, ok := err.(SeriesLimitExceededError); ok {
+			return true
+		}
+		err = xerrors.InnerError(err)
+	}
+	return false
+}
+
+type seriesLimiterMetrics struct {
+	namespaceRejected tally.Counter
+	tagValueRejected  tally.Counter
+}
+
+func newSeriesLimiterMetrics(scope tally.Scope) seriesLimiterMetrics {
+	rejectedName := "rejected"
+	limitTagName := "limit"
+	return seriesLimiterMetrics{
+		namespaceRejected: scope.Tagged(map[string]string{
+			limitTagName: "namespace",
+		}).Counter(rejectedName),
+		tagValueRejected: scope.Tagged(map[string]string{
+			limitTagName: "tag-value",
+		}).Counter(rejectedName),
+	}
+}
+
+// seriesLimiter tracks the number of active series held by all the shards
+// of a namespace and enforces the series limits set by runtime options.
+// The limits are applied per node since each node only tracks the series
+// of the shards it owns.
+type seriesLimiter struct {
+	sync.Mutex
+
+	namespace string
+
+	// limits, protected by mutex
+	maxSeries      int64
+	tagName        []byte
+	maxPerTagValue int64
+
+	numSeries           int64
+	numSeriesByTagValue map[string]int64
+
+	metrics seriesLimiterMetrics
+}
+
+func newSeriesLimiter(namespace ident.ID, scope tally.Scope) *seriesLimiter {
+	return &seriesLimiter{
+		namespace:           namespace.String(),
+		numSeriesByTagValue: make(map[string]int64),
+		metrics:             newSeriesLimiterMetrics(scope.SubScope("series-limits")),
+	}
+}
+
+func (l *seriesLimiter) SetRuntimeOptions(value runtime.Options) {
+	limits := value.SeriesLimits()
+	l.Lock()
+	l.maxSeries = limits.MaxSeriesForNamespace(l.namespace)
+	l.maxPerTagValue = limits.MaxSeriesPerTagValue
+	if tagName := []byte(limits.TagName); !bytes.Equal(tagName, l.tagName) {
+		// NB: Counts are only tracked for the values of the current tag, if
+		// the tag changes then the counts are only accurate for series
+		// inserted after the change until existing series are purged.
+		l.tagName = tagName
+		l.numSeriesByTagValue = make(map[string]int64)
+	}
+	l.Unlock()
+}
+
+// Check returns an error if inserting a series with the given tags would
+// exceed the series limits, it does not account for the series.
+func (l *seriesLimiter) Check(tags ident.Tags) error {
+	l.Lock()
+	err := l.checkWithLock(l.tagValueWithLock(tags))
+	l.Unlock()
+	return err
+}
+
+// TryAdd accounts for a new series with the given tags if doing so does
+// not exceed the series limits, otherwise it returns an error.
+func (l *seriesLimiter) TryAdd(tags ident.Tags) error {
+	l.Lock()
+	defer l.Unlock()
+
+	tagValue := l.tagValueWithLock(tags)
+	if err := l.checkWithLock(tagValue); err != nil {
+		return err
+	}
+	l.addWithLock(tagValue)
+	return nil
+}
+
+// Add accounts for a new series with the given tags regardless of the
+// series limits, i.e. for series being bootstrapped.
+func (l *seriesLimiter) Add(tags ident.Tags) {
+	l.Lock()
+	l.addWithLock(l.tagValueWithLock(tags))
+	l.Unlock()
+}
+
+// Remove releases a series with the given tags that is no longer active.
+func (l *seriesLimiter) Remove(tags ident.Tags) {
+	l.Lock()
+	if l.numSeries > 0 {
+		l.numSeries--
+	}
+	if tagValue := l.tagValueWithLock(tags); tagValue != nil {
+		key := string(tagValue)
+		if n := l.numSeriesByTagValue[key]; n > 1 {
+			l.numSeriesByTagValue[key] = n - 1
+		} else {
+			delete(l.numSeriesByTagValue, key)
+		}
+	}
+	l.Unlock()
+}
+
+// NumSeries returns the number of active series accounted for.
+func (l *seriesLimiter) NumSeries() int64 {
+	l.Lock()
+	n := l.numSeries
+	l.Unlock()
+	return n
+}
+
+func (l *seriesLimiter) checkWithLock(tagValue []byte) error {
+	if l.maxSeries > 0 && l.numSeries >= l.maxSeries {
+		l.metrics.namespaceRejected.Inc(1)
+		return xerrors.NewInvalidParamsError(SeriesLimitExceededError{
+			Namespace: l.namespace,
+			Limit:     l.maxSeries,
+		})
+	}
+	if l.maxPerTagValue > 0 && tagValue != nil &&
+		l.numSeriesByTagValue[string(tagValue)] >= l.maxPerTagValue {
+		l.metrics.tagValueRejected.Inc(1)
+		return xerrors.NewInvalidParamsError(SeriesLimitExceededError{
+			Namespace: l.namespace,
+			TagName:   string(l.tagName),
+			TagValue:  string(tagValue),
+			Limit:     l.maxPerTagValue,
+		})
+	}
+	return nil
+}
+
+func (l *seriesLimiter) addWithLock(tagValue []byte) {
+	l.numSeries++
+	if tagValue != nil {
+		l.numSeriesByTagValue[string(tagValue)]++
+	}
+}
+
+func (l *seriesLimiter) tagValueWithLock(tags ident.Tags) []byte {
+	if len(l.tagName) == 0 {
+		return nil
+	}
+	for _, tag := range tags.Values() {
+		if bytes.Equal(tag.Name.Bytes(), l.tagName) {
+			return tag.Value.Bytes()
+		}
+	}
+	return nil
+}
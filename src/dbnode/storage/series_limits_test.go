@@ -0,0 +1,117 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/runtime"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func testSeriesLimiterTags(service string) ident.Tags {
+	return ident.NewTags(
+		ident.StringTag("service", service),
+		ident.StringTag("host", "a"),
+	)
+}
+
+func TestSeriesLimiterNoLimits(t *testing.T) {
+	limiter := newSeriesLimiter(ident.StringID("ns"), tally.NoopScope)
+	limiter.SetRuntimeOptions(runtime.NewOptions())
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("foo")))
+	}
+	require.Equal(t, int64(10), limiter.NumSeries())
+}
+
+func TestSeriesLimiterNamespaceLimit(t *testing.T) {
+	limiter := newSeriesLimiter(ident.StringID("ns"), tally.NoopScope)
+	limiter.SetRuntimeOptions(runtime.NewOptions().
+		SetSeriesLimits(runtime.SeriesLimits{
+			MaxSeriesPerNamespace: 10,
+			NamespaceMaxSeries:    map[string]int64{"ns": 2, "other": 5},
+		}))
+
+	require.NoError(t, limiter.Check(testSeriesLimiterTags("foo")))
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("foo")))
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("bar")))
+
+	err := limiter.TryAdd(testSeriesLimiterTags("baz"))
+	require.Error(t, err)
+	require.True(t, IsSeriesLimitExceededError(err))
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.Equal(t, SeriesLimitExceededError{Namespace: "ns", Limit: 2},
+		xerrors.GetInnerInvalidParamsError(err))
+	require.Error(t, limiter.Check(testSeriesLimiterTags("baz")))
+
+	// Bootstrapped series are accounted for regardless of the limits.
+	limiter.Add(testSeriesLimiterTags("baz"))
+	require.Equal(t, int64(3), limiter.NumSeries())
+
+	limiter.Remove(testSeriesLimiterTags("baz"))
+	limiter.Remove(testSeriesLimiterTags("bar"))
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("qux")))
+	require.Equal(t, int64(2), limiter.NumSeries())
+}
+
+func TestSeriesLimiterTagValueLimit(t *testing.T) {
+	limiter := newSeriesLimiter(ident.StringID("ns"), tally.NoopScope)
+	limiter.SetRuntimeOptions(runtime.NewOptions().
+		SetSeriesLimits(runtime.SeriesLimits{
+			TagName:              "service",
+			MaxSeriesPerTagValue: 1,
+		}))
+
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("foo")))
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("bar")))
+
+	// Series without the tag are only subject to the namespace limit.
+	require.NoError(t, limiter.TryAdd(ident.NewTags(ident.StringTag("host", "a"))))
+
+	err := limiter.TryAdd(testSeriesLimiterTags("foo"))
+	require.Error(t, err)
+	require.True(t, IsSeriesLimitExceededError(err))
+	require.Equal(t, SeriesLimitExceededError{
+		Namespace: "ns",
+		TagName:   "service",
+		TagValue:  "foo",
+		Limit:     1,
+	}, xerrors.GetInnerInvalidParamsError(err))
+
+	limiter.Remove(testSeriesLimiterTags("foo"))
+	require.NoError(t, limiter.TryAdd(testSeriesLimiterTags("foo")))
+}
+
+func TestIsSeriesLimitExceededError(t *testing.T) {
+	err := SeriesLimitExceededError{Namespace: "ns", Limit: 1}
+	require.True(t, IsSeriesLimitExceededError(err))
+	require.True(t, IsSeriesLimitExceededError(xerrors.NewInvalidParamsError(err)))
+	require.True(t, IsSeriesLimitExceededError(xerrors.Wrap(err, "write failed")))
+	require.False(t, IsSeriesLimitExceededError(errors.New("other")))
+	require.False(t, IsSeriesLimitExceededError(nil))
+}
@@ -158,6 +158,7 @@ type dbShard struct {
 	increasingIndex          increasingIndex
 	seriesPool               series.DatabaseSeriesPool
 	reverseIndex             namespaceIndex
+	seriesLimiter            *seriesLimiter
 	insertQueue              *dbShardInsertQueue
 	lookup                   *shardMap
 	list                     *list.List
@@ -201,6 +202,7 @@ type dbShardMetrics struct {
 	insertAsyncInsertErrors       tally.Counter
 	insertAsyncBootstrapErrors    tally.Counter
 	insertAsyncWriteErrors        tally.Counter
+	insertAsyncSeriesLimitErrors  tally.Counter
 	seriesBootstrapBlocksToBuffer tally.Counter
 	seriesBootstrapBlocksMerged   tally.Counter
 	seriesTicked                  tally.Gauge
@@ -222,6 +224,9 @@ func newDatabaseShardMetrics(shardID uint32, scope tally.Scope) dbShardMetrics {
 		insertAsyncWriteErrors: scope.Tagged(map[string]string{
 			"error_type": "write-value",
 		}).Counter("insert-async.errors"),
+		insertAsyncSeriesLimitErrors: scope.Tagged(map[string]string{
+			"error_type": "series-limit",
+		}).Counter("insert-async.errors"),
 		seriesBootstrapBlocksToBuffer: seriesBootstrapScope.Counter("blocks-to-buffer"),
 		seriesBootstrapBlocksMerged:   seriesBootstrapScope.Counter("blocks-merged"),
 		seriesTicked: scope.Tagged(map[string]string{
@@ -255,6 +260,7 @@ func newDatabaseShard(
 	namespaceReaderMgr databaseNamespaceReaderManager,
 	increasingIndex increasingIndex,
 	reverseIndex namespaceIndex,
+	seriesLimiter *seriesLimiter,
 	needsBootstrap bool,
 	opts Options,
 	seriesOpts series.Options,
@@ -273,6 +279,7 @@ func newDatabaseShard(
 		increasingIndex:      increasingIndex,
 		seriesPool:           opts.DatabaseSeriesPool(),
 		reverseIndex:         reverseIndex,
+		seriesLimiter:        seriesLimiter,
 		lookup:               newShardMap(shardMapOptions{}),
 		list:                 list.New(),
 		newMergerFn:          fs.NewMerger,
@@ -290,7 +297,7 @@ func newDatabaseShard(
 		metrics:              newDatabaseShardMetrics(shard, scope),
 	}
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
-		s.nowFn, scope)
+		s.nowFn, seriesLimiter, scope)
 
 	registerRuntimeOptionsListener := func(listener runtime.OptionsListener) {
 		elem := opts.RuntimeOptionsManager().RegisterListener(listener)
@@ -807,6 +814,7 @@ func (s *dbShard) purgeExpiredSeries(expiredEntries []*lookup.Entry) {
 		// NB(xichen): if we get here, we are guaranteed that there can be
 		// no more reads/writes to this series while the lock is held, so it's
 		// safe to remove it.
+		s.seriesLimiter.Remove(series.Tags())
 		series.Close()
 		s.list.Remove(elem)
 		s.lookup.Delete(id)
@@ -1274,6 +1282,9 @@ func (s *dbShard) insertSeriesSync(
 		}
 	}
 
+	// Series inserted synchronously are being bootstrapped and so are
+	// accounted for regardless of the series limits.
+	s.seriesLimiter.Add(entry.Series.Tags())
 	s.insertNewShardEntryWithLock(entry)
 	return entry, nil
 }
@@ -1294,6 +1305,7 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 	var (
 		anyPendingAction   = false
 		numPendingIndexing = 0
+		seriesLimitErrs    xerrors.MultiError
 	)
 
 	s.Lock()
@@ -1304,6 +1316,7 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 		hasPendingWrite := inserts[i].opts.hasPendingWrite
 		hasPendingIndexing := inserts[i].opts.hasPendingIndexing
 		hasPendingRetrievedBlock := inserts[i].opts.hasPendingRetrievedBlock
+		newSeriesWrite := inserts[i].newSeriesWrite()
 		anyPendingAction = anyPendingAction || hasPendingWrite ||
 			hasPendingRetrievedBlock || hasPendingIndexing
 
@@ -1352,6 +1365,23 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 
 		// Insert still pending, perform the insert
 		entry = inserts[i].entry
+		if !newSeriesWrite {
+			s.seriesLimiter.Add(entry.Series.Tags())
+		} else if err := s.seriesLimiter.TryAdd(entry.Series.Tags()); err != nil {
+			// Series limits were reached after the insert was enqueued, drop
+			// any pending actions since the series will not be inserted.
+			s.metrics.insertAsyncSeriesLimitErrors.Inc(1)
+			seriesLimitErrs = seriesLimitErrs.Add(err)
+			if hasPendingWrite {
+				if annotation := inserts[i].opts.pendingWrite.annotation; annotation != nil {
+					annotation.DecRef()
+					annotation.Finalize()
+				}
+			}
+			inserts[i].opts.hasPendingWrite = false
+			inserts[i].opts.hasPendingIndexing = false
+			continue
+		}
 		if s.newSeriesBootstrapped {
 			_, err := entry.Series.Load(
 				series.LoadOptions{Bootstrap: true},
@@ -1365,6 +1395,16 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 	}
 	s.Unlock()
 
+	if n := seriesLimitErrs.NumErrors(); n > 0 {
+		// NB: writes of new series inserted asynchronously have already been
+		// acknowledged, so the dropped writes can only be surfaced here.
+		// Writers waiting on the insert retry it once it completes and are
+		// returned the series limit error by the insert queue instead.
+		s.logger.Warn("dropped inserts of new series exceeding the series limits",
+			zap.Int("numDropped", n),
+			zap.Error(seriesLimitErrs.LastError()))
+	}
+
 	if !anyPendingAction {
 		return nil
 	}
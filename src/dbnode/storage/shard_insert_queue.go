@@ -59,6 +59,7 @@ type dbShardInsertQueue struct {
 	nowFn              clock.NowFn
 	insertEntryBatchFn dbShardInsertEntryBatchFn
 	sleepFn            func(time.Duration)
+	seriesLimiter      *seriesLimiter
 
 	// rate limits, protected by mutex
 	insertBatchBackoff   time.Duration
@@ -123,6 +124,12 @@ type dbShardInsert struct {
 
 var dbShardInsertZeroed = dbShardInsert{}
 
+// newSeriesWrite returns whether the insert may create a new series on
+// behalf of a write, such inserts are subject to the series limits.
+func (i dbShardInsert) newSeriesWrite() bool {
+	return !i.opts.hasPendingRetrievedBlock && !i.opts.entryRefCountIncremented
+}
+
 type dbShardPendingWrite struct {
 	timestamp  time.Time
 	value      float64
@@ -171,9 +178,13 @@ type dbShardInsertEntryBatchFn func(inserts []dbShardInsert) error
 // The batching as it is without any sleep and just relying on a notification
 // trigger and hot looping when being flooded improved by a factor of roughly
 // 4x during floods of new series.
+//
+// The series limiter is used to reject new series early when a namespace is
+// already at its series limits, it may be nil to not check any limits.
 func newDatabaseShardInsertQueue(
 	insertEntryBatchFn dbShardInsertEntryBatchFn,
 	nowFn clock.NowFn,
+	seriesLimiter *seriesLimiter,
 	scope tally.Scope,
 ) *dbShardInsertQueue {
 	currBatch := &dbShardInsertBatch{}
@@ -183,6 +194,7 @@ func newDatabaseShardInsertQueue(
 		nowFn:              nowFn,
 		insertEntryBatchFn: insertEntryBatchFn,
 		sleepFn:            time.Sleep,
+		seriesLimiter:      seriesLimiter,
 		currBatch:          currBatch,
 		notifyInsert:       make(chan struct{}, 1),
 		closeCh:            make(chan struct{}, 1),
@@ -291,6 +303,15 @@ func (q *dbShardInsertQueue) Stop() error {
 }
 
 func (q *dbShardInsertQueue) Insert(insert dbShardInsert) (*sync.WaitGroup, error) {
+	if q.seriesLimiter != nil && insert.newSeriesWrite() {
+		// Reject new series early when already at the series limits, the
+		// limits are enforced again when the series is inserted since other
+		// inserts may be pending.
+		if err := q.seriesLimiter.Check(insert.entry.Series.Tags()); err != nil {
+			return nil, err
+		}
+	}
+
 	windowNanos := q.nowFn().Truncate(time.Second).UnixNano()
 
 	q.Lock()
@@ -64,7 +64,7 @@ func TestShardInsertQueueBatchBackoff(t *testing.T) {
 		timeLock.Lock()
 		defer timeLock.Unlock()
 		return currTime
-	}, tally.NoopScope)
+	}, nil, tally.NoopScope)
 
 	q.insertBatchBackoff = backoff
 
@@ -142,7 +142,7 @@ func TestShardInsertQueueRateLimit(t *testing.T) {
 		timeLock.Lock()
 		defer timeLock.Unlock()
 		return currTime
-	}, tally.NoopScope)
+	}, nil, tally.NoopScope)
 
 	q.insertPerSecondLimit = 2
 
@@ -203,7 +203,7 @@ func TestShardInsertQueueFlushedOnClose(t *testing.T) {
 	q := newDatabaseShardInsertQueue(func(value []dbShardInsert) error {
 		atomic.AddInt64(&numInsertObserved, int64(len(value)))
 		return nil
-	}, func() time.Time { return currTime }, tally.NoopScope)
+	}, func() time.Time { return currTime }, nil, tally.NoopScope)
 
 	require.NoError(t, q.Start())
 
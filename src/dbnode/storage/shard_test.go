@@ -47,6 +47,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -83,7 +84,8 @@ func testDatabaseShardWithIndexFn(
 		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
 		SetBufferBucketPool(series.NewBufferBucketPool(nil))
 	return newDatabaseShard(metadata, 0, nil, nsReaderMgr,
-		&testIncreasingIndex{}, idx, true, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, idx, newSeriesLimiter(metadata.ID(), tally.NoopScope),
+		true, opts, seriesOpts).(*dbShard)
 }
 
 func addMockSeries(ctrl *gomock.Controller, shard *dbShard, id ident.ID, tags ident.Tags, index uint64) *series.MockDatabaseSeries {
@@ -103,7 +105,7 @@ func TestShardDontNeedBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, testNs.seriesLimiter, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -116,7 +118,7 @@ func TestShardErrorIfDoubleBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, testNs.seriesLimiter, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -1171,6 +1173,124 @@ func testShardWriteAsync(t *testing.T, writes []testWrite) {
 	require.True(t, ok)
 }
 
+func TestShardWriteSeriesLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	retriever := series.NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(gomock.Any()).Return(false, nil).AnyTimes()
+	shard.seriesBlockRetriever = retriever
+	defer shard.Close()
+
+	shard.seriesLimiter.SetRuntimeOptions(runtime.NewOptions().
+		SetSeriesLimits(runtime.SeriesLimits{MaxSeriesPerNamespace: 1}))
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	nowFn := opts.ClockOptions().NowFn()
+	_, _, err := shard.Write(ctx, ident.StringID("foo"), nowFn(), 1.0,
+		xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+
+	// Writes to existing series are still accepted.
+	_, _, err = shard.Write(ctx, ident.StringID("foo"), nowFn().Add(time.Second), 2.0,
+		xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+
+	_, _, err = shard.Write(ctx, ident.StringID("bar"), nowFn(), 1.0,
+		xtime.Second, nil, series.WriteOptions{})
+	require.Error(t, err)
+	require.True(t, IsSeriesLimitExceededError(err))
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	shard.RLock()
+	require.Equal(t, 1, shard.lookup.Len())
+	shard.RUnlock()
+	require.Equal(t, int64(1), shard.seriesLimiter.NumSeries())
+}
+
+func TestShardInsertSeriesBatchSeriesLimitExceeded(t *testing.T) {
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	shard.seriesLimiter.SetRuntimeOptions(runtime.NewOptions().
+		SetSeriesLimits(runtime.SeriesLimits{MaxSeriesPerNamespace: 1}))
+
+	// Both inserts passed the limits check when they were enqueued.
+	var inserts []dbShardInsert
+	for _, id := range []string{"foo", "bar"} {
+		entry, err := shard.newShardEntry(ident.StringID(id),
+			newTagsIterArg(ident.EmptyTagIterator))
+		require.NoError(t, err)
+		inserts = append(inserts, dbShardInsert{entry: entry})
+	}
+
+	require.NoError(t, shard.insertSeriesBatch(inserts))
+
+	shard.RLock()
+	require.Equal(t, 1, shard.lookup.Len())
+	shard.RUnlock()
+	require.Equal(t, int64(1), shard.seriesLimiter.NumSeries())
+}
+
+func TestShardWriteSeriesLimitExceededWhileInsertPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	retriever := series.NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(gomock.Any()).Return(false, nil).AnyTimes()
+	shard.seriesBlockRetriever = retriever
+	defer shard.Close()
+
+	shard.seriesLimiter.SetRuntimeOptions(runtime.NewOptions().
+		SetSeriesLimits(runtime.SeriesLimits{MaxSeriesPerNamespace: 1}))
+	// Backoff between insert batches so the new series are likely to be
+	// enqueued before either is inserted.
+	shard.insertQueue.SetRuntimeOptions(runtime.NewOptions().
+		SetWriteNewSeriesBackoffDuration(100 * time.Millisecond))
+
+	var (
+		nowFn = opts.ClockOptions().NowFn()
+		ids   = []string{"foo", "bar"}
+		errs  = make([]error, len(ids))
+		wg    sync.WaitGroup
+	)
+	for i, id := range ids {
+		i, id := i, id
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := opts.ContextPool().Get()
+			defer ctx.Close()
+			_, _, errs[i] = shard.Write(ctx, ident.StringID(id), nowFn(), 1.0,
+				xtime.Second, nil, series.WriteOptions{})
+		}()
+	}
+	wg.Wait()
+
+	// Exactly one of the writes is rejected, regardless of whether the
+	// rejection happened when enqueueing or inserting the new series.
+	var numRejected int
+	for _, err := range errs {
+		if err != nil {
+			require.True(t, IsSeriesLimitExceededError(err))
+			numRejected++
+		}
+	}
+	require.Equal(t, 1, numRejected)
+
+	shard.RLock()
+	require.Equal(t, 1, shard.lookup.Len())
+	shard.RUnlock()
+	require.Equal(t, int64(1), shard.seriesLimiter.NumSeries())
+}
+
 // This tests a race in shard ticking with an empty series pending expiration.
 func TestShardTickRace(t *testing.T) {
 	opts := DefaultTestOptions()
@@ -157,6 +157,13 @@ type Database interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// CardinalityQuery counts the series per tag name and value of the index.
+	CardinalityQuery(
+		ctx context.Context,
+		namespace ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityQueryResult, error)
+
 	// ReadEncoded retrieves encoded segments for an ID
 	ReadEncoded(
 		ctx context.Context,
@@ -307,6 +314,12 @@ type databaseNamespace interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// CardinalityQuery counts the series per tag name and value of the index.
+	CardinalityQuery(
+		ctx context.Context,
+		opts index.CardinalityOptions,
+	) (index.CardinalityQueryResult, error)
+
 	// ReadEncoded reads data for given id within [start, end).
 	ReadEncoded(
 		ctx context.Context,
@@ -543,6 +556,12 @@ type namespaceIndex interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// CardinalityQuery counts the series per tag name and value of the index.
+	CardinalityQuery(
+		ctx context.Context,
+		opts index.CardinalityOptions,
+	) (index.CardinalityQueryResult, error)
+
 	// Bootstrap bootstraps the index the provided segments.
 	Bootstrap(
 		bootstrapResults result.IndexResults,
@@ -46,6 +46,9 @@ const (
 	// NSIdxAggregateQuery is the operation name for the nsIndex AggregateQuery path.
 	NSIdxAggregateQuery = "storage.nsIndex.AggregateQuery"
 
+	// NSIdxCardinalityQuery is the operation name for the nsIndex CardinalityQuery path.
+	NSIdxCardinalityQuery = "storage.nsIndex.CardinalityQuery"
+
 	// NSIdxQueryHelper is the operation name for the nsIndex query path.
 	NSIdxQueryHelper = "storage.nsIndex.query"
 
@@ -55,9 +58,15 @@ const (
 	// NSIdxBlockAggregateQuery is the operation name for the nsIndex block aggregate query path.
 	NSIdxBlockAggregateQuery = "storage.nsIndex.blockAggregateQuery"
 
+	// NSIdxBlockCardinalityQuery is the operation name for the nsIndex block cardinality query path.
+	NSIdxBlockCardinalityQuery = "storage.nsIndex.blockCardinalityQuery"
+
 	// BlockQuery is the operation name for the index block query path.
 	BlockQuery = "storage/index.block.Query"
 
 	// BlockAggregate is the operation name for the index block aggregate path.
 	BlockAggregate = "storage/index.block.Aggregate"
+
+	// BlockCardinality is the operation name for the index block cardinality path.
+	BlockCardinality = "storage/index.block.Cardinality"
 )
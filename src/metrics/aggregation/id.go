@@ -23,15 +23,32 @@ package aggregation
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/m3db/m3/src/metrics/generated/proto/aggregationpb"
 )
 
 const (
-	// IDLen is the length of the ID.
+	// IDLen is the number of words of the ID holding the bitset of predefined
+	// types, which is the length of the ID in its encodings.
 	// The IDLen will be 1 when maxTypeID <= 63.
 	IDLen = (maxTypeID)/64 + 1
 
+	// Parameterised quantile types do not fit in the bitset, and are instead stored
+	// in the quantileIDLen words following it, in slots each holding the quantile
+	// in units of quantileTypeResolution. Slots are sorted in ascending order with
+	// empty slots last so equal sets of types have equal ids. Encodings carry the
+	// quantiles as a separate optional field rather than as part of the ID, so
+	// ids without them are encoded as before.
+	quantileIDLen          = 2
+	idQuantileSlotBits     = 16
+	idQuantileSlotMask     = 1<<idQuantileSlotBits - 1
+	idQuantileSlotsPerWord = 64 / idQuantileSlotBits
+	maxQuantileTypesPerID  = quantileIDLen * idQuantileSlotsPerWord
+
+	// idLen is the length of the ID including the quantile slots.
+	idLen = IDLen + quantileIDLen
+
 	// ID uses an array of int64 to represent aggregation types.
 	idBitShift = 6
 	idBitMask  = 63
@@ -43,7 +60,7 @@ var (
 )
 
 // ID represents a compressed view of Types.
-type ID [IDLen]uint64
+type ID [idLen]uint64
 
 // NewIDFromProto creates an ID from proto.
 func NewIDFromProto(input []aggregationpb.AggregationType) (ID, error) {
@@ -76,6 +93,14 @@ func (id ID) Contains(aggType Type) bool {
 	if !aggType.IsValid() {
 		return false
 	}
+	if aggType.IsParameterisedQuantile() {
+		for i := 0; i < maxQuantileTypesPerID; i++ {
+			if quantileTypeInSlot(id, i) == aggType {
+				return true
+			}
+		}
+		return false
+	}
 	idx := int(aggType) >> idBitShift   // aggType / 64
 	offset := uint(aggType) & idBitMask // aggType % 64
 	return (id[idx] & (1 << offset)) > 0
@@ -146,6 +171,7 @@ func (id ID) ToProto(pb *aggregationpb.AggregationID) error {
 		return fmt.Errorf("id length %d cannot be represented by a single integer", IDLen)
 	}
 	pb.Id = id[0]
+	pb.Quantiles = id.AppendQuantileUnits(pb.Quantiles[:0])
 	return nil
 }
 
@@ -154,7 +180,45 @@ func (id *ID) FromProto(pb aggregationpb.AggregationID) error {
 	if IDLen != 1 {
 		return fmt.Errorf("id length %d cannot be represented by a single integer", IDLen)
 	}
+	*id = DefaultID
 	(*id)[0] = pb.Id
+	return id.SetQuantileUnits(pb.Quantiles)
+}
+
+// HasQuantileTypes returns true if the id contains parameterised quantile types.
+func (id ID) HasQuantileTypes() bool {
+	return quantileUnitsInSlot(id, 0) != 0
+}
+
+// AppendQuantileUnits appends the parameterised quantile types of the id, in
+// units of 0.0001, to the given slice. Encodings carry them separately from
+// the first IDLen words of the id.
+func (id ID) AppendQuantileUnits(units []uint64) []uint64 {
+	for slot := 0; slot < maxQuantileTypesPerID; slot++ {
+		u := quantileUnitsInSlot(id, slot)
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return units
+}
+
+// SetQuantileUnits replaces the parameterised quantile types of the id with
+// the given quantiles in units of 0.0001, the units are sorted in place.
+func (id *ID) SetQuantileUnits(units []uint64) error {
+	if len(units) > maxQuantileTypesPerID {
+		return fmt.Errorf("could not decode more than %d parameterised quantile types", maxQuantileTypesPerID)
+	}
+	for _, u := range units {
+		if !(quantileTypeOffset + Type(u)).IsParameterisedQuantile() {
+			return fmt.Errorf("invalid parameterised quantile %d", u)
+		}
+	}
+	for i := IDLen; i < idLen; i++ {
+		(*id)[i] = 0
+	}
+	setQuantileSlots(id, units)
 	return nil
 }
 
@@ -172,3 +236,30 @@ func MustCompressTypes(aggTypes ...Type) ID {
 	}
 	return res
 }
+
+// quantileTypeInSlot returns the parameterised quantile type stored in the given
+// slot of the id, or UnknownType if the slot is empty.
+func quantileTypeInSlot(id ID, slot int) Type {
+	units := quantileUnitsInSlot(id, slot)
+	if units == 0 {
+		return UnknownType
+	}
+	return quantileTypeOffset + Type(units)
+}
+
+func quantileUnitsInSlot(id ID, slot int) uint64 {
+	word := IDLen + slot/idQuantileSlotsPerWord
+	shift := uint(slot%idQuantileSlotsPerWord) * idQuantileSlotBits
+	return (id[word] >> shift) & idQuantileSlotMask
+}
+
+// setQuantileSlots stores the given quantile units in the quantile slots of the
+// id in ascending order, the units are sorted in place.
+func setQuantileSlots(id *ID, units []uint64) {
+	sort.Slice(units, func(i, j int) bool { return units[i] < units[j] })
+	for slot, u := range units {
+		word := IDLen + slot/idQuantileSlotsPerWord
+		shift := uint(slot%idQuantileSlotsPerWord) * idQuantileSlotBits
+		(*id)[word] |= u << shift
+	}
+}
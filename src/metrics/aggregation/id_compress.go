@@ -60,24 +60,50 @@ func NewIDCompressor() IDCompressor {
 }
 
 func (c *idCompressor) Compress(aggTypes Types) (ID, error) {
+	var (
+		quantileSlots    [maxQuantileTypesPerID]uint64
+		numQuantileSlots int
+	)
 	c.bs.ClearAll()
 	for _, aggType := range aggTypes {
 		if !aggType.IsValid() {
 			return DefaultID, fmt.Errorf("could not compress invalid Type %v", aggType)
 		}
-		c.bs.Set(uint(aggType.ID()))
+		if !aggType.IsParameterisedQuantile() {
+			c.bs.Set(uint(aggType.ID()))
+			continue
+		}
+		units := uint64(aggType - quantileTypeOffset)
+		if containsSlot(quantileSlots[:numQuantileSlots], units) {
+			continue
+		}
+		if numQuantileSlots == maxQuantileTypesPerID {
+			return DefaultID, fmt.Errorf("could not compress more than %d parameterised quantile types in %v", maxQuantileTypesPerID, aggTypes)
+		}
+		quantileSlots[numQuantileSlots] = units
+		numQuantileSlots++
 	}
 
 	codes := c.bs.Bytes()
 	var id ID
-	// NB(cw) it's guaranteed that len(id) == len(codes) == IDLen, we need to copy
+	// NB(cw) it's guaranteed that len(codes) == IDLen, we need to copy
 	// the words in bitset out because the bitset contains a slice internally.
 	for i := 0; i < IDLen; i++ {
 		id[i] = codes[i]
 	}
+	setQuantileSlots(&id, quantileSlots[:numQuantileSlots])
 	return id, nil
 }
 
+func containsSlot(slots []uint64, units uint64) bool {
+	for _, s := range slots {
+		if s == units {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *idCompressor) MustCompress(aggTypes Types) ID {
 	id, err := c.Compress(aggTypes)
 	if err != nil {
@@ -111,9 +137,9 @@ func (d *idDecompressor) Decompress(id ID) (Types, error) {
 	if id.IsDefault() {
 		return DefaultTypes, nil
 	}
-	// NB(cw) it's guaranteed that len(c.buf) == len(id) == IDLen, we need to copy
+	// NB(cw) it's guaranteed that len(c.buf) == IDLen, we need to copy
 	// the words from id into a slice to be used in bitset.
-	for i := range id {
+	for i := range d.buf {
 		d.buf[i] = id[i]
 	}
 
@@ -132,6 +158,16 @@ func (d *idDecompressor) Decompress(id ID) (Types, error) {
 
 		res = append(res, aggType)
 	}
+	for i := 0; i < maxQuantileTypesPerID; i++ {
+		aggType := quantileTypeInSlot(id, i)
+		if aggType == UnknownType {
+			break
+		}
+		if !aggType.IsValid() {
+			return DefaultTypes, fmt.Errorf("invalid Type: %s", aggType.String())
+		}
+		res = append(res, aggType)
+	}
 
 	return res, nil
 }
@@ -42,6 +42,39 @@ func TestIDCompressRoundTrip(t *testing.T) {
 		{[]Type{1, 5, 9, 3, 2}, []Type{1, 2, 3, 5, 9}, false},
 		// 50 is an unknown aggregation type.
 		{[]Type{10, 50}, DefaultTypes, true},
+		{
+			[]Type{MustQuantileType(0.995), Max, MustQuantileType(0.75)},
+			[]Type{Max, MustQuantileType(0.75), MustQuantileType(0.995)},
+			false,
+		},
+		{
+			[]Type{MustQuantileType(0.75), MustQuantileType(0.75)},
+			[]Type{MustQuantileType(0.75)},
+			false,
+		},
+		{
+			[]Type{
+				MustQuantileType(0.9995), MustQuantileType(0.0001), MustQuantileType(0.75),
+				MustQuantileType(0.98), MustQuantileType(0.995), MustQuantileType(0.25),
+				MustQuantileType(0.055), Max, MustQuantileType(0.0055),
+			},
+			[]Type{
+				Max, MustQuantileType(0.0001), MustQuantileType(0.0055), MustQuantileType(0.055),
+				MustQuantileType(0.25), MustQuantileType(0.75), MustQuantileType(0.98),
+				MustQuantileType(0.995), MustQuantileType(0.9995),
+			},
+			false,
+		},
+		// At most eight parameterised quantile types fit in an id.
+		{
+			[]Type{
+				MustQuantileType(0.9995), MustQuantileType(0.0001), MustQuantileType(0.75),
+				MustQuantileType(0.98), MustQuantileType(0.995), MustQuantileType(0.25),
+				MustQuantileType(0.055), MustQuantileType(0.0055), MustQuantileType(0.005),
+			},
+			DefaultTypes,
+			true,
+		},
 	}
 
 	p := NewTypesPool(pool.NewObjectPoolOptions().SetSize(1))
@@ -63,10 +96,10 @@ func TestIDCompressRoundTrip(t *testing.T) {
 
 func TestIDDecompressError(t *testing.T) {
 	compressor, decompressor := NewIDCompressor(), NewIDDecompressor()
-	_, err := decompressor.Decompress([IDLen]uint64{1})
+	_, err := decompressor.Decompress(ID{1})
 	require.Error(t, err)
 
-	max, err := compressor.Compress([]Type{Last, Min, Max, Mean, Median, Count, Sum, SumSq, Stdev, P95, P99, P999, P9999})
+	max, err := compressor.Compress([]Type{Last, Min, Max, Mean, Median, Count, Sum, SumSq, Stdev, P95, P99, P999, P9999, CountDistinct})
 	require.NoError(t, err)
 
 	max[0] = max[0] << 1
@@ -85,7 +118,7 @@ func TestIDMustDecompress(t *testing.T) {
 			shouldPanic: false,
 		},
 		{
-			id:          [IDLen]uint64{1},
+			id:          ID{1},
 			shouldPanic: true,
 		},
 	}
@@ -99,3 +132,14 @@ func TestIDMustDecompress(t *testing.T) {
 	}
 
 }
+
+func TestIDCompressParameterisedQuantilesOrder(t *testing.T) {
+	var (
+		p75  = MustQuantileType(0.75)
+		p995 = MustQuantileType(0.995)
+	)
+	require.Equal(t, MustCompressTypes(p75, p995), MustCompressTypes(p995, p75))
+	require.True(t, MustCompressTypes(Sum, p995).Contains(p995))
+	require.False(t, MustCompressTypes(Sum, p995).Contains(p75))
+	require.False(t, MustCompressTypes(Sum).Contains(p995))
+}
@@ -59,6 +59,34 @@ func TestIDRoundTrip(t *testing.T) {
 	require.Equal(t, testID, res)
 }
 
+func TestIDRoundTripParameterisedQuantiles(t *testing.T) {
+	var (
+		pb  aggregationpb.AggregationID
+		res ID
+		id  = MustCompressTypes(
+			Max, MustQuantileType(0.995), MustQuantileType(0.0055), MustQuantileType(0.75),
+		)
+	)
+	require.NoError(t, id.ToProto(&pb))
+	require.Equal(t, MustCompressTypes(Max)[0], pb.Id)
+	require.Equal(t, []uint64{55, 7500, 9950}, pb.Quantiles)
+	require.NoError(t, res.FromProto(pb))
+	require.Equal(t, id, res)
+	types, err := res.Types()
+	require.NoError(t, err)
+	require.Equal(t, Types{
+		Max, MustQuantileType(0.0055), MustQuantileType(0.75), MustQuantileType(0.995),
+	}, types)
+}
+
+func TestIDFromProtoInvalidQuantiles(t *testing.T) {
+	var res ID
+	require.Error(t, res.FromProto(aggregationpb.AggregationID{Quantiles: []uint64{10000}}))
+	require.Error(t, res.FromProto(aggregationpb.AggregationID{
+		Quantiles: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+	}))
+}
+
 func TestIDMarshalJSON(t *testing.T) {
 	inputs := []struct {
 		id       ID
@@ -88,6 +116,7 @@ func TestIDMarshalRoundtrip(t *testing.T) {
 	inputs := []ID{
 		DefaultID,
 		testID,
+		MustCompressTypes(Max, MustQuantileType(0.75), MustQuantileType(0.995)),
 	}
 	testmarshal.TestMarshalersRoundtrip(t, inputs, []testmarshal.Marshaler{testmarshal.YAMLMarshaler, testmarshal.JSONMarshaler})
 }
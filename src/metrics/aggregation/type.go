@@ -22,6 +22,8 @@ package aggregation
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/m3db/m3/src/metrics/generated/proto/aggregationpb"
@@ -53,6 +55,7 @@ const (
 	P99
 	P999
 	P9999
+	CountDistinct
 
 	nextTypeID = iota
 )
@@ -66,6 +69,13 @@ const (
 	maxTypeID = nextTypeID - 1
 
 	typesSeparator = ","
+
+	// quantileTypeOffset is the offset of the parameterised quantile aggregation
+	// types. Each parameterised quantile type carries its quantile in units of
+	// quantileTypeResolution on top of the offset, e.g. p99.5 is the type
+	// quantileTypeOffset + 9950.
+	quantileTypeOffset     Type = 1 << 16
+	quantileTypeResolution      = 10000
 )
 
 var (
@@ -76,36 +86,97 @@ var (
 
 	// ValidTypes is the list of all the valid aggregation types.
 	ValidTypes = map[Type]struct{}{
-		Last:   emptyStruct,
-		Min:    emptyStruct,
-		Max:    emptyStruct,
-		Mean:   emptyStruct,
-		Median: emptyStruct,
-		Count:  emptyStruct,
-		Sum:    emptyStruct,
-		SumSq:  emptyStruct,
-		Stdev:  emptyStruct,
-		P10:    emptyStruct,
-		P20:    emptyStruct,
-		P30:    emptyStruct,
-		P40:    emptyStruct,
-		P50:    emptyStruct,
-		P60:    emptyStruct,
-		P70:    emptyStruct,
-		P80:    emptyStruct,
-		P90:    emptyStruct,
-		P95:    emptyStruct,
-		P99:    emptyStruct,
-		P999:   emptyStruct,
-		P9999:  emptyStruct,
+		Last:          emptyStruct,
+		Min:           emptyStruct,
+		Max:           emptyStruct,
+		Mean:          emptyStruct,
+		Median:        emptyStruct,
+		Count:         emptyStruct,
+		Sum:           emptyStruct,
+		SumSq:         emptyStruct,
+		Stdev:         emptyStruct,
+		P10:           emptyStruct,
+		P20:           emptyStruct,
+		P30:           emptyStruct,
+		P40:           emptyStruct,
+		P50:           emptyStruct,
+		P60:           emptyStruct,
+		P70:           emptyStruct,
+		P80:           emptyStruct,
+		P90:           emptyStruct,
+		P95:           emptyStruct,
+		P99:           emptyStruct,
+		P999:          emptyStruct,
+		P9999:         emptyStruct,
+		CountDistinct: emptyStruct,
 	}
 
 	typeStringMap map[string]Type
+
+	typeNames = [...]string{
+		UnknownType:   "UnknownType",
+		Last:          "Last",
+		Min:           "Min",
+		Max:           "Max",
+		Mean:          "Mean",
+		Median:        "Median",
+		Count:         "Count",
+		Sum:           "Sum",
+		SumSq:         "SumSq",
+		Stdev:         "Stdev",
+		P10:           "P10",
+		P20:           "P20",
+		P30:           "P30",
+		P40:           "P40",
+		P50:           "P50",
+		P60:           "P60",
+		P70:           "P70",
+		P80:           "P80",
+		P90:           "P90",
+		P95:           "P95",
+		P99:           "P99",
+		P999:          "P999",
+		P9999:         "P9999",
+		CountDistinct: "CountDistinct",
+	}
+
+	// fixedQuantileTypes are the quantile aggregation types with a dedicated id,
+	// which take precedence over parameterised quantile types.
+	fixedQuantileTypes = []Type{P10, P20, P30, P40, P50, P60, P70, P80, P90, P95, P99, P999, P9999}
 )
 
 // Type defines an aggregation function.
 type Type int
 
+// NewQuantileType returns the aggregation type computing the given quantile. The
+// quantile must be between 0 and 1 exclusive with at most four decimal places.
+// Quantiles that have a dedicated aggregation type, e.g. 0.99, return that type.
+func NewQuantileType(q float64) (Type, error) {
+	units := math.Round(q * quantileTypeResolution)
+	if !(q > 0 && q < 1) || units < 1 || units >= quantileTypeResolution {
+		return UnknownType, fmt.Errorf("invalid quantile %v: must be between 0 and 1 exclusive", q)
+	}
+	if math.Abs(q*quantileTypeResolution-units) > 1e-6 {
+		return UnknownType, fmt.Errorf("invalid quantile %v: must have at most four decimal places", q)
+	}
+	for _, aggType := range fixedQuantileTypes {
+		if fixed, _ := aggType.Quantile(); math.Round(fixed*quantileTypeResolution) == units {
+			return aggType, nil
+		}
+	}
+	return quantileTypeOffset + Type(units), nil
+}
+
+// MustQuantileType returns the aggregation type computing the given quantile,
+// and panics if the quantile is invalid.
+func MustQuantileType(q float64) Type {
+	aggType, err := NewQuantileType(q)
+	if err != nil {
+		panic(err)
+	}
+	return aggType
+}
+
 // NewTypeFromProto creates an aggregation type from a proto.
 func NewTypeFromProto(input aggregationpb.AggregationType) (Type, error) {
 	aggType := Type(input)
@@ -122,14 +193,22 @@ func (a Type) ID() int {
 
 // IsValid checks if an Type is valid.
 func (a Type) IsValid() bool {
-	_, ok := ValidTypes[a]
-	return ok
+	if _, ok := ValidTypes[a]; ok {
+		return true
+	}
+	return a.IsParameterisedQuantile()
+}
+
+// IsParameterisedQuantile checks if the Type is a parameterised quantile type
+// created by NewQuantileType rather than one of the predefined types.
+func (a Type) IsParameterisedQuantile() bool {
+	return a > quantileTypeOffset && a < quantileTypeOffset+quantileTypeResolution
 }
 
 // IsValidForGauge if an Type is valid for Gauge.
 func (a Type) IsValidForGauge() bool {
 	switch a {
-	case Last, Min, Max, Mean, Count, Sum, SumSq, Stdev:
+	case Last, Min, Max, Mean, Count, Sum, SumSq, Stdev, CountDistinct:
 		return true
 	default:
 		return false
@@ -139,7 +218,7 @@ func (a Type) IsValidForGauge() bool {
 // IsValidForCounter if an Type is valid for Counter.
 func (a Type) IsValidForCounter() bool {
 	switch a {
-	case Min, Max, Mean, Count, Sum, SumSq, Stdev:
+	case Min, Max, Mean, Count, Sum, SumSq, Stdev, CountDistinct:
 		return true
 	default:
 		return false
@@ -186,13 +265,33 @@ func (a Type) Quantile() (float64, bool) {
 	case P9999:
 		return 0.9999, true
 	default:
+		if a.IsParameterisedQuantile() {
+			return float64(a-quantileTypeOffset) / quantileTypeResolution, true
+		}
 		return 0, false
 	}
 }
 
-// Proto returns the proto of the aggregation type.
+// String returns the name of the aggregation type. Parameterised quantile types
+// are named after their percentile, e.g. P99.5.
+func (a Type) String() string {
+	if a.IsParameterisedQuantile() {
+		percentile := float64(a-quantileTypeOffset) / (quantileTypeResolution / 100)
+		return "P" + strconv.FormatFloat(percentile, 'f', -1, 64)
+	}
+	if a >= 0 && int(a) < len(typeNames) {
+		return typeNames[a]
+	}
+	return fmt.Sprintf("Type(%d)", a)
+}
+
+// Proto returns the proto of the aggregation type. Parameterised quantile types
+// are represented by their type id, which lies outside the enumerated values.
 func (a Type) Proto() (aggregationpb.AggregationType, error) {
 	s := aggregationpb.AggregationType(a)
+	if a.IsParameterisedQuantile() {
+		return s, nil
+	}
 	if err := validateProtoType(s); err != nil {
 		return aggregationpb.AggregationType_UNKNOWN, err
 	}
@@ -246,11 +345,30 @@ func ParseType(str string) (Type, error) {
 	}
 
 	if !exactMatch && !looseMatch {
+		if aggType, ok := parseQuantileType(str); ok {
+			return aggType, nil
+		}
 		return UnknownType, fmt.Errorf("invalid aggregation type: %s", str)
 	}
 	return aggType, nil
 }
 
+// parseQuantileType parses a quantile type named after its percentile, e.g. P75 or P99.5.
+func parseQuantileType(str string) (Type, bool) {
+	if len(str) < 2 || (str[0] != 'P' && str[0] != 'p') {
+		return UnknownType, false
+	}
+	percentile, err := strconv.ParseFloat(str[1:], 64)
+	if err != nil {
+		return UnknownType, false
+	}
+	aggType, err := NewQuantileType(percentile / 100)
+	if err != nil {
+		return UnknownType, false
+	}
+	return aggType, true
+}
+
 // Types is a list of Types.
 type Types []Type
 
@@ -57,6 +57,33 @@ gaugeTransformFnType: empty
 	require.Equal(t, []byte(nil), opts.TypeStringForGauge(Last))
 }
 
+func TestTypesConfigurationParameterisedQuantiles(t *testing.T) {
+	str := `
+defaultTimerAggregationTypes:
+  - P75
+  - P99.5
+timerTransformFnType: suffix
+`
+
+	var cfg TypesConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	opts, err := cfg.NewOptions(instrument.NewOptions())
+	require.NoError(t, err)
+	p75, p995 := MustQuantileType(0.75), MustQuantileType(0.995)
+	require.Equal(t, Types{p75, p995}, opts.DefaultTimerAggregationTypes())
+	require.Equal(t, []float64{0.75, 0.995}, opts.Quantiles())
+	require.Equal(t, []byte(".p75"), opts.TypeStringForTimer(p75))
+	require.Equal(t, []byte(".p99_5"), opts.TypeStringForTimer(p995))
+	require.Equal(t, []byte(".p98"), opts.TypeStringForTimer(MustQuantileType(0.98)))
+	require.Equal(t, []byte(".p5_5"), opts.TypeStringForTimer(MustQuantileType(0.055)))
+	require.Equal(t, []byte(".p0_55"), opts.TypeStringForTimer(MustQuantileType(0.0055)))
+	require.Equal(t, []byte(".p9_5"), opts.TypeStringForTimer(MustQuantileType(0.095)))
+	require.Equal(t, p995, opts.TypeForTimer([]byte(".p99_5")))
+	require.Equal(t, MustQuantileType(0.0055), opts.TypeForTimer([]byte(".p0_55")))
+	require.Equal(t, P95, opts.TypeForTimer([]byte(".p95")))
+	require.Equal(t, UnknownType, opts.TypeForTimer([]byte(".p995")))
+}
+
 func TestTypesConfigurationNoTransformFnType(t *testing.T) {
 	str := `
 defaultGaugeAggregationTypes: [Max]
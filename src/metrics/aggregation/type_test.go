@@ -23,6 +23,7 @@ package aggregation
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/m3db/m3/src/x/pool"
@@ -34,15 +35,70 @@ import (
 
 func TestTypeIsValid(t *testing.T) {
 	require.True(t, P9999.IsValid())
-	require.False(t, Type(int(P9999)+1).IsValid())
+	require.True(t, CountDistinct.IsValid())
+	require.False(t, Type(int(CountDistinct)+1).IsValid())
 }
 
 func TestTypeMaxID(t *testing.T) {
-	require.Equal(t, maxTypeID, P9999.ID())
-	require.Equal(t, P9999, Type(maxTypeID))
+	require.Equal(t, maxTypeID, CountDistinct.ID())
+	require.Equal(t, CountDistinct, Type(maxTypeID))
 	require.Equal(t, maxTypeID, len(ValidTypes))
 }
 
+func TestNewQuantileType(t *testing.T) {
+	inputs := []struct {
+		quantile    float64
+		expected    Type
+		expectedStr string
+		expectedErr bool
+	}{
+		{quantile: 0.99, expected: P99, expectedStr: "P99"},
+		{quantile: 0.9999, expected: P9999, expectedStr: "P9999"},
+		{quantile: 0.75, expected: quantileTypeOffset + 7500, expectedStr: "P75"},
+		{quantile: 0.995, expected: quantileTypeOffset + 9950, expectedStr: "P99.5"},
+		{quantile: 0.0001, expected: quantileTypeOffset + 1, expectedStr: "P0.01"},
+		{quantile: 0, expectedErr: true},
+		{quantile: 1, expectedErr: true},
+		{quantile: 0.99995, expectedErr: true},
+		{quantile: math.NaN(), expectedErr: true},
+	}
+	for _, input := range inputs {
+		aggType, err := NewQuantileType(input.quantile)
+		if input.expectedErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, input.expected, aggType)
+		require.True(t, aggType.IsValid())
+		require.True(t, aggType.IsValidForTimer())
+		require.False(t, aggType.IsValidForCounter())
+		require.False(t, aggType.IsValidForGauge())
+		q, ok := aggType.Quantile()
+		require.True(t, ok)
+		require.Equal(t, input.quantile, q)
+		require.Equal(t, input.expectedStr, aggType.String())
+
+		parsed, err := ParseType(aggType.String())
+		require.NoError(t, err)
+		require.Equal(t, aggType, parsed)
+	}
+}
+
+func TestTypeProtoRoundTrip(t *testing.T) {
+	types := []Type{MustQuantileType(0.75), MustQuantileType(0.995)}
+	for aggType := range ValidTypes {
+		types = append(types, aggType)
+	}
+	for _, aggType := range types {
+		pb, err := aggType.Proto()
+		require.NoError(t, err)
+		res, err := NewTypeFromProto(pb)
+		require.NoError(t, err)
+		require.Equal(t, aggType, res)
+	}
+}
+
 func TestTypeUnmarshalYAML(t *testing.T) {
 	inputs := []struct {
 		str         string
@@ -100,6 +156,10 @@ func TestTypesMarshalJSON(t *testing.T) {
 			types:    Types{Mean, Max, P99, P9999},
 			expected: `["Mean","Max","P99","P9999"]`,
 		},
+		{
+			types:    Types{Max, MustQuantileType(0.995)},
+			expected: `["Max","P99.5"]`,
+		},
 		{
 			types:       Types{Type(1000)},
 			expectedErr: true,
@@ -318,6 +378,7 @@ func TestIDContains(t *testing.T) {
 	require.False(t, MustCompressTypes(Sum, Last, P999).Contains(P9999))
 	require.False(t, MustCompressTypes().Contains(P99))
 	require.False(t, MustCompressTypes(P99, P95).Contains(P9999))
+	require.True(t, MustCompressTypes(Sum, CountDistinct).Contains(CountDistinct))
 }
 
 func TestCompressedTypesIsDefault(t *testing.T) {
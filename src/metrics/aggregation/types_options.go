@@ -22,8 +22,11 @@ package aggregation
 
 import (
 	"bytes"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/x/pool"
@@ -63,6 +66,15 @@ type TypesOptions interface {
 	// QuantileTypeStringFn returns the quantile type string function for timers.
 	QuantileTypeStringFn() QuantileTypeStringFn
 
+	// SetParameterisedQuantileTypeStringFn sets the quantile type string function
+	// for parameterised quantile types, which must map distinct quantiles to
+	// distinct type strings.
+	SetParameterisedQuantileTypeStringFn(value QuantileTypeStringFn) TypesOptions
+
+	// ParameterisedQuantileTypeStringFn returns the quantile type string function
+	// for parameterised quantile types.
+	ParameterisedQuantileTypeStringFn() QuantileTypeStringFn
+
 	// SetCounterTypeStringTransformFn sets the transformation function for counter type strings.
 	SetCounterTypeStringTransformFn(value TypeStringTransformFn) TypesOptions
 
@@ -116,6 +128,11 @@ type TypesOptions interface {
 	// Quantiles returns the quantiles for timers.
 	Quantiles() []float64
 
+	// ValidateTimerTypeStrings returns an error if any of the parameterised quantile
+	// types has the same timer type string as a different aggregation type, in
+	// which case they would be written to the same series.
+	ValidateTimerTypeStrings(aggTypes Types) error
+
 	// IsContainedInDefaultAggregationTypes checks if the given aggregation type is
 	// contained in the default aggregation types for the metric type.
 	IsContainedInDefaultAggregationTypes(at Type, mt metric.Type) bool
@@ -142,15 +159,16 @@ var (
 		Last,
 	}
 	defaultTypeStringsMap = map[Type][]byte{
-		Last:   []byte("last"),
-		Sum:    []byte("sum"),
-		SumSq:  []byte("sum_sq"),
-		Mean:   []byte("mean"),
-		Min:    []byte("lower"),
-		Max:    []byte("upper"),
-		Count:  []byte("count"),
-		Stdev:  []byte("stdev"),
-		Median: []byte("median"),
+		Last:          []byte("last"),
+		Sum:           []byte("sum"),
+		SumSq:         []byte("sum_sq"),
+		Mean:          []byte("mean"),
+		Min:           []byte("lower"),
+		Max:           []byte("upper"),
+		Count:         []byte("count"),
+		Stdev:         []byte("stdev"),
+		Median:        []byte("median"),
+		CountDistinct: []byte("count_distinct"),
 	}
 )
 
@@ -159,6 +177,7 @@ type options struct {
 	defaultTimerAggregationTypes   Types
 	defaultGaugeAggregationTypes   Types
 	quantileTypeStringFn           QuantileTypeStringFn
+	paramQuantileTypeStringFn      QuantileTypeStringFn
 	counterTypeStringTransformFn   TypeStringTransformFn
 	timerTypeStringTransformFn     TypeStringTransformFn
 	gaugeTypeStringTransformFn     TypeStringTransformFn
@@ -169,6 +188,10 @@ type options struct {
 	timerTypeStrings   [][]byte
 	gaugeTypeStrings   [][]byte
 	quantiles          []float64
+
+	counterQuantileTypeStrings *quantileTypeStrings
+	timerQuantileTypeStrings   *quantileTypeStrings
+	gaugeQuantileTypeStrings   *quantileTypeStrings
 }
 
 // NewTypesOptions returns a default TypesOptions.
@@ -178,6 +201,7 @@ func NewTypesOptions() TypesOptions {
 		defaultGaugeAggregationTypes:   defaultDefaultGaugeAggregationTypes,
 		defaultTimerAggregationTypes:   defaultDefaultTimerAggregationTypes,
 		quantileTypeStringFn:           defaultQuantileTypeStringFn,
+		paramQuantileTypeStringFn:      defaultParameterisedQuantileTypeStringFn,
 		counterTypeStringTransformFn:   NoOpTransform,
 		timerTypeStringTransformFn:     NoOpTransform,
 		gaugeTypeStringTransformFn:     NoOpTransform,
@@ -241,6 +265,17 @@ func (o *options) QuantileTypeStringFn() QuantileTypeStringFn {
 	return o.quantileTypeStringFn
 }
 
+func (o *options) SetParameterisedQuantileTypeStringFn(value QuantileTypeStringFn) TypesOptions {
+	opts := *o
+	opts.paramQuantileTypeStringFn = value
+	opts.computeAllDerived()
+	return &opts
+}
+
+func (o *options) ParameterisedQuantileTypeStringFn() QuantileTypeStringFn {
+	return o.paramQuantileTypeStringFn
+}
+
 func (o *options) SetCounterTypeStringTransformFn(value TypeStringTransformFn) TypesOptions {
 	opts := *o
 	opts.counterTypeStringTransformFn = value
@@ -295,33 +330,73 @@ func (o *options) QuantilesPool() pool.FloatsPool {
 }
 
 func (o *options) TypeStringForCounter(aggType Type) []byte {
+	if aggType.IsParameterisedQuantile() {
+		return o.counterQuantileTypeStrings.TypeStringFor(aggType)
+	}
 	return o.counterTypeStrings[aggType.ID()]
 }
 
 func (o *options) TypeStringForTimer(aggType Type) []byte {
+	if aggType.IsParameterisedQuantile() {
+		return o.timerQuantileTypeStrings.TypeStringFor(aggType)
+	}
 	return o.timerTypeStrings[aggType.ID()]
 }
 
 func (o *options) TypeStringForGauge(aggType Type) []byte {
+	if aggType.IsParameterisedQuantile() {
+		return o.gaugeQuantileTypeStrings.TypeStringFor(aggType)
+	}
 	return o.gaugeTypeStrings[aggType.ID()]
 }
 
 func (o *options) TypeForCounter(value []byte) Type {
-	return typeFor(value, o.counterTypeStrings)
+	if aggType := typeFor(value, o.counterTypeStrings); aggType != UnknownType {
+		return aggType
+	}
+	return o.counterQuantileTypeStrings.TypeFor(value)
 }
 
 func (o *options) TypeForTimer(value []byte) Type {
-	return typeFor(value, o.timerTypeStrings)
+	if aggType := typeFor(value, o.timerTypeStrings); aggType != UnknownType {
+		return aggType
+	}
+	return o.timerQuantileTypeStrings.TypeFor(value)
 }
 
 func (o *options) TypeForGauge(value []byte) Type {
-	return typeFor(value, o.gaugeTypeStrings)
+	if aggType := typeFor(value, o.gaugeTypeStrings); aggType != UnknownType {
+		return aggType
+	}
+	return o.gaugeQuantileTypeStrings.TypeFor(value)
 }
 
 func (o *options) Quantiles() []float64 {
 	return o.quantiles
 }
 
+func (o *options) ValidateTimerTypeStrings(aggTypes Types) error {
+	for i, aggType := range aggTypes {
+		if !aggType.IsParameterisedQuantile() {
+			continue
+		}
+		typeString := o.TypeStringForTimer(aggType)
+		// NB: An empty type string means type strings are disabled altogether.
+		if len(typeString) == 0 {
+			continue
+		}
+		if other := typeFor(typeString, o.timerTypeStrings); other != UnknownType {
+			return fmt.Errorf("aggregation type %v has the same type string %s as %v", aggType, typeString, other)
+		}
+		for _, other := range aggTypes[:i] {
+			if other != aggType && bytes.Equal(typeString, o.TypeStringForTimer(other)) {
+				return fmt.Errorf("aggregation type %v has the same type string %s as %v", aggType, typeString, other)
+			}
+		}
+	}
+	return nil
+}
+
 func (o *options) IsContainedInDefaultAggregationTypes(at Type, mt metric.Type) bool {
 	var aggTypes Types
 	switch mt {
@@ -348,14 +423,17 @@ func (o *options) computeQuantiles() {
 
 func (o *options) computeCounterTypeStrings() {
 	o.counterTypeStrings = o.computeTypeStrings(o.counterTypeStringTransformFn)
+	o.counterQuantileTypeStrings = newQuantileTypeStrings(o.paramQuantileTypeStringFn, o.counterTypeStringTransformFn)
 }
 
 func (o *options) computeTimerTypeStrings() {
 	o.timerTypeStrings = o.computeTypeStrings(o.timerTypeStringTransformFn)
+	o.timerQuantileTypeStrings = newQuantileTypeStrings(o.paramQuantileTypeStringFn, o.timerTypeStringTransformFn)
 }
 
 func (o *options) computeGaugeTypeStrings() {
 	o.gaugeTypeStrings = o.computeTypeStrings(o.gaugeTypeStringTransformFn)
+	o.gaugeQuantileTypeStrings = newQuantileTypeStrings(o.paramQuantileTypeStringFn, o.gaugeTypeStringTransformFn)
 }
 
 func (o *options) computeTypeStrings(transformFn TypeStringTransformFn) [][]byte {
@@ -376,6 +454,74 @@ func (o *options) computeTypeStrings(transformFn TypeStringTransformFn) [][]byte
 	return res
 }
 
+// quantileTypeStrings lazily computes and caches the type strings of parameterised
+// quantile types, which unlike the predefined types are not known upfront.
+type quantileTypeStrings struct {
+	sync.RWMutex
+
+	quantileTypeStringFn QuantileTypeStringFn
+	transformFn          TypeStringTransformFn
+	typeStrings          map[Type][]byte
+	types                map[string]Type
+}
+
+func newQuantileTypeStrings(
+	quantileTypeStringFn QuantileTypeStringFn,
+	transformFn TypeStringTransformFn,
+) *quantileTypeStrings {
+	return &quantileTypeStrings{
+		quantileTypeStringFn: quantileTypeStringFn,
+		transformFn:          transformFn,
+		typeStrings:          make(map[Type][]byte),
+	}
+}
+
+func (s *quantileTypeStrings) TypeStringFor(aggType Type) []byte {
+	s.RLock()
+	typeString, exists := s.typeStrings[aggType]
+	s.RUnlock()
+	if exists {
+		return typeString
+	}
+
+	typeString = s.computeTypeString(aggType)
+	s.Lock()
+	s.typeStrings[aggType] = typeString
+	s.Unlock()
+	return typeString
+}
+
+// TypeFor returns the parameterised quantile type for the given type string,
+// computing the type strings of all parameterised quantile types on first use.
+func (s *quantileTypeStrings) TypeFor(value []byte) Type {
+	s.RLock()
+	types := s.types
+	s.RUnlock()
+	if types == nil {
+		types = make(map[string]Type, quantileTypeResolution)
+		for units := 1; units < quantileTypeResolution; units++ {
+			aggType := quantileTypeOffset + Type(units)
+			// NB: Quantiles with a dedicated type never map to a parameterised type.
+			if q, _ := aggType.Quantile(); MustQuantileType(q) != aggType {
+				continue
+			}
+			types[string(s.computeTypeString(aggType))] = aggType
+		}
+		s.Lock()
+		s.types = types
+		s.Unlock()
+	}
+	if aggType, exists := types[string(value)]; exists {
+		return aggType
+	}
+	return UnknownType
+}
+
+func (s *quantileTypeStrings) computeTypeString(aggType Type) []byte {
+	q, _ := aggType.Quantile()
+	return s.transformFn(s.quantileTypeStringFn(q))
+}
+
 func typeFor(value []byte, typeStrings [][]byte) Type {
 	for id, typeString := range typeStrings {
 		if !bytes.Equal(value, typeString) {
@@ -398,6 +544,18 @@ func defaultQuantileTypeStringFn(quantile float64) []byte {
 	return []byte("p" + str)
 }
 
+// By default parameterised quantiles use e.g. "p75", "p99_5" and "p0_55" for
+// the 75th/99.5th/0.55th percentile, separating the fractional part of the
+// percentile so that distinct quantiles never share a type string.
+func defaultParameterisedQuantileTypeStringFn(quantile float64) []byte {
+	units := int(math.Round(quantile * quantileTypeResolution))
+	percentile := strconv.Itoa(units / (quantileTypeResolution / 100))
+	if fraction := units % (quantileTypeResolution / 100); fraction != 0 {
+		percentile += "_" + strings.TrimRight(fmt.Sprintf("%02d", fraction), "0")
+	}
+	return []byte("p" + percentile)
+}
+
 // NoOpTransform returns the input byte slice as is.
 func NoOpTransform(b []byte) []byte { return b }
 
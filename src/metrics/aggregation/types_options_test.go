@@ -114,6 +114,7 @@ func TestOptionSetTimerTypeStringTranformFn(t *testing.T) {
 		{aggType: Sum, expected: []byte(".sum")},
 		{aggType: SumSq, expected: []byte(".sum_sq")},
 		{aggType: Stdev, expected: []byte(".stdev")},
+		{aggType: CountDistinct, expected: []byte(".count_distinct")},
 		{aggType: P50, expected: []byte(".p50")},
 		{aggType: P95, expected: []byte(".p95")},
 		{aggType: P99, expected: []byte(".p99")},
@@ -184,6 +185,7 @@ func TestOptionSetAllTypeStringTranformFns(t *testing.T) {
 		{aggType: Sum, expected: []byte(".sum")},
 		{aggType: SumSq, expected: []byte(".sum_sq")},
 		{aggType: Stdev, expected: []byte(".stdev")},
+		{aggType: CountDistinct, expected: []byte(".count_distinct")},
 		{aggType: P50, expected: []byte(".p50")},
 		{aggType: P95, expected: []byte(".p95")},
 		{aggType: P99, expected: []byte(".p99")},
@@ -428,6 +430,47 @@ func TestOptionQuantileTypeString(t *testing.T) {
 	}
 }
 
+func TestOptionParameterisedQuantileTypeString(t *testing.T) {
+	o := NewTypesOptions()
+	cases := []struct {
+		quantile float64
+		b        []byte
+	}{
+		{quantile: 0.75, b: []byte("p75")},
+		{quantile: 0.995, b: []byte("p99_5")},
+		{quantile: 0.9995, b: []byte("p99_95")},
+		{quantile: 0.055, b: []byte("p5_5")},
+		{quantile: 0.0055, b: []byte("p0_55")},
+		{quantile: 0.095, b: []byte("p9_5")},
+		{quantile: 0.0001, b: []byte("p0_01")},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.b, o.ParameterisedQuantileTypeStringFn()(c.quantile))
+		aggType := MustQuantileType(c.quantile)
+		require.Equal(t, c.b, o.TypeStringForCounter(aggType))
+		require.Equal(t, aggType, o.TypeForCounter(c.b))
+	}
+}
+
+func TestOptionsValidateTimerTypeStrings(t *testing.T) {
+	o := NewTypesOptions().SetTimerTypeStringTransformFn(SuffixTransform)
+	require.NoError(t, o.ValidateTimerTypeStrings(Types{
+		P95, MustQuantileType(0.095), MustQuantileType(0.055), MustQuantileType(0.0055),
+	}))
+
+	// Dropping the decimal point maps 0.095 to the same type string as P95 and
+	// 0.25 to the same type string as 0.025.
+	o = o.SetParameterisedQuantileTypeStringFn(o.QuantileTypeStringFn())
+	require.Error(t, o.ValidateTimerTypeStrings(Types{Max, MustQuantileType(0.095)}))
+	require.Error(t, o.ValidateTimerTypeStrings(Types{MustQuantileType(0.25), MustQuantileType(0.025)}))
+	require.NoError(t, o.ValidateTimerTypeStrings(Types{Max, MustQuantileType(0.055)}))
+
+	// Type strings are not checked when they are disabled altogether.
+	o = o.SetTimerTypeStringTransformFn(EmptyTransform)
+	require.NoError(t, o.ValidateTimerTypeStrings(Types{Max, MustQuantileType(0.095)}))
+}
+
 func TestSetQuantilesPool(t *testing.T) {
 	p := pool.NewFloatsPool(nil, nil)
 	o := NewTypesOptions().SetQuantilesPool(p)
@@ -450,28 +493,29 @@ func validateQuantiles(t *testing.T, o TypesOptions) {
 
 func typeStrings(overrides map[Type][]byte) [][]byte {
 	defaultTypeStrings := map[Type][]byte{
-		Last:   []byte("last"),
-		Min:    []byte("lower"),
-		Max:    []byte("upper"),
-		Mean:   []byte("mean"),
-		Median: []byte("median"),
-		Count:  []byte("count"),
-		Sum:    []byte("sum"),
-		SumSq:  []byte("sum_sq"),
-		Stdev:  []byte("stdev"),
-		P10:    []byte("p10"),
-		P20:    []byte("p20"),
-		P30:    []byte("p30"),
-		P40:    []byte("p40"),
-		P50:    []byte("p50"),
-		P60:    []byte("p60"),
-		P70:    []byte("p70"),
-		P80:    []byte("p80"),
-		P90:    []byte("p90"),
-		P95:    []byte("p95"),
-		P99:    []byte("p99"),
-		P999:   []byte("p999"),
-		P9999:  []byte("p9999"),
+		Last:          []byte("last"),
+		Min:           []byte("lower"),
+		Max:           []byte("upper"),
+		Mean:          []byte("mean"),
+		Median:        []byte("median"),
+		Count:         []byte("count"),
+		Sum:           []byte("sum"),
+		SumSq:         []byte("sum_sq"),
+		Stdev:         []byte("stdev"),
+		P10:           []byte("p10"),
+		P20:           []byte("p20"),
+		P30:           []byte("p30"),
+		P40:           []byte("p40"),
+		P50:           []byte("p50"),
+		P60:           []byte("p60"),
+		P70:           []byte("p70"),
+		P80:           []byte("p80"),
+		P90:           []byte("p90"),
+		P95:           []byte("p95"),
+		P99:           []byte("p99"),
+		P999:          []byte("p999"),
+		P9999:         []byte("p9999"),
+		CountDistinct: []byte("count_distinct"),
 	}
 	res := make([][]byte, maxTypeID+1)
 	for t, bstr := range defaultTypeStrings {
@@ -22,6 +22,7 @@ package carbon
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -49,14 +50,34 @@ const (
 
 	initScannerBufferSize = 2 << 15 // ~ 65KiB
 	maxScannerBufferSize  = 2 << 17 // ~ 0.25iB
+
+	tagSeparator      = ';'
+	tagValueSeparator = '='
+	// tagNameInvalidChars are the characters graphite does not allow in
+	// tag names.
+	tagNameInvalidChars = ";!^="
+	// tagValueInvalidPrefix is not allowed at the start of tag values since
+	// graphite uses it to denote regexp tag expressions.
+	tagValueInvalidPrefix = '~'
 )
 
 var (
-	errInvalidLine = errors.New("invalid line")
-	errNotUTF8     = errors.New("not valid UTF8 string")
-	mathNan        = math.NaN()
+	errInvalidLine     = errors.New("invalid line")
+	errNotUTF8         = errors.New("not valid UTF8 string")
+	errEmptyPath       = errors.New("tagged name has an empty path")
+	errInvalidTag      = errors.New("tagged name has an invalid tag")
+	errInvalidTagName  = errors.New("tagged name has an invalid tag name")
+	errInvalidTagValue = errors.New("tagged name has an invalid tag value")
+	errDuplicateTag    = errors.New("tagged name has a duplicate tag name")
+	mathNan            = math.NaN()
 )
 
+// Tag is a tag of a graphite tagged name.
+type Tag struct {
+	Name  []byte
+	Value []byte
+}
+
 // Metric represents a carbon metric.
 type Metric struct {
 	Name []byte
@@ -142,6 +163,11 @@ func ParseName(line []byte) (name []byte, rest []byte, err error) {
 		err = errNotUTF8
 		return
 	}
+	if IsTaggedName(name) {
+		if err = validateTaggedName(name); err != nil {
+			return
+		}
+	}
 
 	nonSpaceIdx := firstSepIdx + 1
 	for nonSpaceIdx < len(line) && line[nonSpaceIdx] == ' ' {
@@ -152,6 +178,100 @@ func ParseName(line []byte) (name []byte, rest []byte, err error) {
 	return
 }
 
+// IsTaggedName returns whether the name is a graphite tagged name.
+func IsTaggedName(name []byte) bool {
+	return bytes.IndexByte(name, tagSeparator) != -1
+}
+
+// ParseTaggedName parses a graphite tagged name of the form
+// path;tag1=value1;tag2=value2 and returns the path and the tags appended to
+// the given slice in the order they appear in the name. The returned path
+// and tags reference the bytes of the name.
+func ParseTaggedName(name []byte, tags []Tag) ([]byte, []Tag, error) {
+	path, rest, err := splitTaggedName(name)
+	if err != nil {
+		return nil, tags, err
+	}
+
+	start := len(tags)
+	for len(rest) > 0 {
+		var tag Tag
+		tag, rest, err = nextTag(rest)
+		if err != nil {
+			return nil, tags, err
+		}
+		for _, existing := range tags[start:] {
+			if bytes.Equal(existing.Name, tag.Name) {
+				return nil, tags, errDuplicateTag
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return path, tags, nil
+}
+
+func validateTaggedName(name []byte) error {
+	_, rest, err := splitTaggedName(name)
+	if err != nil {
+		return err
+	}
+
+	for len(rest) > 0 {
+		if _, rest, err = nextTag(rest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitTaggedName splits a tagged name into the path and the tags.
+func splitTaggedName(name []byte) ([]byte, []byte, error) {
+	idx := bytes.IndexByte(name, tagSeparator)
+	if idx == -1 {
+		if len(name) == 0 {
+			return nil, nil, errEmptyPath
+		}
+		return name, nil, nil
+	}
+	if idx == 0 {
+		return nil, nil, errEmptyPath
+	}
+	if idx == len(name)-1 {
+		return nil, nil, errInvalidTag
+	}
+
+	return name[:idx], name[idx+1:], nil
+}
+
+// nextTag parses the next tag=value pair from the tags of a tagged name and
+// returns the remaining tags.
+func nextTag(tags []byte) (Tag, []byte, error) {
+	var rest []byte
+	if idx := bytes.IndexByte(tags, tagSeparator); idx != -1 {
+		tags, rest = tags[:idx], tags[idx+1:]
+		if len(rest) == 0 {
+			return Tag{}, nil, errInvalidTag
+		}
+	}
+
+	idx := bytes.IndexByte(tags, tagValueSeparator)
+	if idx == -1 {
+		return Tag{}, nil, errInvalidTag
+	}
+
+	tag := Tag{Name: tags[:idx], Value: tags[idx+1:]}
+	if len(tag.Name) == 0 || bytes.ContainsAny(tag.Name, tagNameInvalidChars) {
+		return Tag{}, nil, errInvalidTagName
+	}
+	if len(tag.Value) == 0 || tag.Value[0] == tagValueInvalidPrefix {
+		return Tag{}, nil, errInvalidTagValue
+	}
+
+	return tag, rest, nil
+}
+
 // ParseRemainder parses a line's components (name and remainder) and returns
 // all but the name and returns the timestamp of the metric, its value, the
 // time it was received and any error encountered.
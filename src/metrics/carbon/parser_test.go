@@ -140,6 +140,72 @@ func TestParseErrors(t *testing.T) {
 	assertParseError(t, "foo 4384 1428951394 1428951394 bar")
 }
 
+func TestParseTaggedName(t *testing.T) {
+	tests := []struct {
+		name         string
+		expectedPath string
+		expectedTags []Tag
+	}{
+		{
+			name:         "foo.bar",
+			expectedPath: "foo.bar",
+		},
+		{
+			name:         "foo.bar;env=prod;dc=us.east",
+			expectedPath: "foo.bar",
+			expectedTags: []Tag{
+				{Name: []byte("env"), Value: []byte("prod")},
+				{Name: []byte("dc"), Value: []byte("us.east")},
+			},
+		},
+		{
+			name:         "foo;query=a=b",
+			expectedPath: "foo",
+			expectedTags: []Tag{
+				{Name: []byte("query"), Value: []byte("a=b")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		path, tags, err := ParseTaggedName([]byte(test.name), nil)
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.expectedPath, string(path), test.name)
+		assert.Equal(t, test.expectedTags, tags, test.name)
+		assert.Equal(t, len(test.expectedTags) > 0, IsTaggedName([]byte(test.name)))
+	}
+}
+
+func TestParseTaggedNameErrors(t *testing.T) {
+	for _, name := range []string{
+		"",
+		";env=prod",
+		"foo;",
+		"foo;env",
+		"foo;env=",
+		"foo;=prod",
+		"foo;env=prod;",
+		"foo;env=prod;;dc=east",
+		"foo;e!nv=prod",
+		"foo;env=~prod",
+		"foo;env=prod;env=dev",
+	} {
+		_, _, err := ParseTaggedName([]byte(name), nil)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseTaggedLine(t *testing.T) {
+	name, timestamp, value, err := Parse([]byte("foo.bar;env=prod 1.5 1428951394"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo.bar;env=prod", string(name))
+	assert.Equal(t, time.Unix(1428951394, 0), timestamp)
+	assert.Equal(t, 1.5, value)
+
+	assertParseError(t, "foo.bar;env 1.5 1428951394")
+	assertParseError(t, "foo.bar;env=~prod 1.5 1428951394")
+}
+
 func TestParsePacket(t *testing.T) {
 	mets, malformed := ParsePacket([]byte(`
 foo.bar.zed 45565.02 1428951394
@@ -233,6 +233,13 @@ func TestAggregatedEncodeDecodeChunkedMetricWithPolicyAndEncodeTime(t *testing.T
 	})
 }
 
+func TestAggregatedEncodeDecodeMetricWithSlidingWindowPolicy(t *testing.T) {
+	validateAggregatedRoundtrip(t, metricWithPolicyAndEncodeTime{
+		metric: testMetric,
+		policy: policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour, 5*time.Minute),
+	})
+}
+
 func TestAggregatedEncodeDecodeStress(t *testing.T) {
 	var (
 		numIter    = 10
@@ -47,6 +47,7 @@ type baseEncoder struct {
 	encodeArrayLenFn      encodeArrayLenFn
 	encodeStoragePolicyFn encodeStoragePolicyFn
 	encodePolicyFn        encodePolicyFn
+	quantileUnits         []uint64
 }
 
 func newBaseEncoder(encoder BufferedEncoder) encoderBase {
@@ -105,26 +106,62 @@ func (enc *baseEncoder) encodeCompressedAggregationTypes(aggTypes aggregation.ID
 		return
 	}
 
+	// NB: parameterised quantile types are encoded as an additional field that
+	// iterators predating them skip, so ids with them can be decoded by those
+	// iterators albeit without the quantile types. Iterators should therefore
+	// be upgraded before encoders start encoding parameterised quantile types.
+	hasQuantileTypes := aggTypes.HasQuantileTypes()
 	if aggregation.IDLen == 1 {
-		enc.encodeNumObjectFields(numFieldsForType(shortAggregationID))
+		if hasQuantileTypes {
+			enc.encodeNumObjectFields(numShortAggregationIDWithQuantilesFields)
+		} else {
+			enc.encodeNumObjectFields(numFieldsForType(shortAggregationID))
+		}
 		enc.encodeObjectType(shortAggregationID)
 		enc.encodeVarintFn(int64(aggTypes[0]))
+		if hasQuantileTypes {
+			enc.encodeQuantileUnits(aggTypes)
+		}
 		return
 	}
 
 	// NB(cw): Only reachable after we start to support more than 63 aggregation types
-	enc.encodeNumObjectFields(numFieldsForType(longAggregationID))
+	if hasQuantileTypes {
+		enc.encodeNumObjectFields(numLongAggregationIDWithQuantilesFields)
+	} else {
+		enc.encodeNumObjectFields(numFieldsForType(longAggregationID))
+	}
 	enc.encodeObjectType(longAggregationID)
 	enc.encodeArrayLen(aggregation.IDLen)
-	for _, v := range aggTypes {
+	for _, v := range aggTypes[:aggregation.IDLen] {
 		enc.encodeVarint(int64(v))
 	}
+	if hasQuantileTypes {
+		enc.encodeQuantileUnits(aggTypes)
+	}
+}
+
+func (enc *baseEncoder) encodeQuantileUnits(aggTypes aggregation.ID) {
+	enc.quantileUnits = aggTypes.AppendQuantileUnits(enc.quantileUnits[:0])
+	enc.encodeArrayLen(len(enc.quantileUnits))
+	for _, u := range enc.quantileUnits {
+		enc.encodeVarint(int64(u))
+	}
 }
 
 func (enc *baseEncoder) encodeStoragePolicyInternal(p policy.StoragePolicy) {
-	enc.encodeNumObjectFields(numFieldsForType(storagePolicyType))
+	// NB: Tumbling window storage policies keep the original encoding so they
+	// can still be decoded by iterators that predate sliding windows.
+	if !p.IsSlidingWindow() {
+		enc.encodeNumObjectFields(numFieldsForType(storagePolicyType))
+		enc.encodeResolution(p.Resolution())
+		enc.encodeRetention(p.Retention())
+		return
+	}
+	enc.encodeNumObjectFields(numSlidingWindowStoragePolicyFields)
 	enc.encodeResolution(p.Resolution())
 	enc.encodeRetention(p.Retention())
+	enc.encodeVarintFn(int64(p.SlidingWindow()))
 }
 
 func (enc *baseEncoder) encodeResolution(resolution policy.Resolution) {
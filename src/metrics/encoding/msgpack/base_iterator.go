@@ -45,6 +45,7 @@ type baseIterator struct {
 	bufReader        bufReader
 	decoder          *msgpack.Decoder
 	decodeErr        error
+	quantileUnits    []uint64
 }
 
 func newBaseIterator(reader io.Reader, readerBufferSize int) iteratorBase {
@@ -93,12 +94,16 @@ func (it *baseIterator) decodeCompressedAggregationTypes() aggregation.ID {
 		return aggregation.DefaultID
 	}
 
-	var aggTypes aggregation.ID
+	var (
+		aggTypes                     aggregation.ID
+		numExpectedWithQuantileTypes int
+	)
 	switch aggregationEncodeType {
 	case defaultAggregationID:
 	case shortAggregationID:
 		value := it.decodeVarint()
 		aggTypes[0] = uint64(value)
+		numExpectedWithQuantileTypes = numShortAggregationIDWithQuantilesFields
 	case longAggregationID:
 		numValues := it.decodeArrayLen()
 		if numValues > aggregation.IDLen {
@@ -109,14 +114,35 @@ func (it *baseIterator) decodeCompressedAggregationTypes() aggregation.ID {
 		for i := 0; i < numValues; i++ {
 			aggTypes[i] = uint64(it.decodeVarint())
 		}
+		numExpectedWithQuantileTypes = numLongAggregationIDWithQuantilesFields
 	default:
 		it.decodeErr = fmt.Errorf("unrecognized aggregation encode type %v", aggregationEncodeType)
 		return aggTypes
 	}
+	// NB: parameterised quantile types are encoded as an additional field.
+	if numExpectedWithQuantileTypes > 0 && numActualFields >= numExpectedWithQuantileTypes {
+		it.decodeQuantileUnits(&aggTypes)
+		numExpectedFields = numExpectedWithQuantileTypes
+	}
 	it.skip(numActualFields - numExpectedFields)
 	return aggTypes
 }
 
+func (it *baseIterator) decodeQuantileUnits(aggTypes *aggregation.ID) {
+	numUnits := it.decodeArrayLen()
+	if it.decodeErr != nil {
+		return
+	}
+	it.quantileUnits = it.quantileUnits[:0]
+	for i := 0; i < numUnits && it.decodeErr == nil; i++ {
+		it.quantileUnits = append(it.quantileUnits, uint64(it.decodeVarint()))
+	}
+	if it.decodeErr != nil {
+		return
+	}
+	it.decodeErr = aggTypes.SetQuantileUnits(it.quantileUnits)
+}
+
 func (it *baseIterator) decodeStoragePolicy() policy.StoragePolicy {
 	numExpectedFields, numActualFields, ok := it.checkNumFieldsForType(storagePolicyType)
 	if !ok {
@@ -124,8 +150,19 @@ func (it *baseIterator) decodeStoragePolicy() policy.StoragePolicy {
 	}
 	resolution := it.decodeResolution()
 	retention := it.decodeRetention()
-	sp := policy.NewStoragePolicy(resolution.Window, resolution.Precision, time.Duration(retention))
-	it.skip(numActualFields - numExpectedFields)
+	if numActualFields < numSlidingWindowStoragePolicyFields {
+		sp := policy.NewStoragePolicy(resolution.Window, resolution.Precision, time.Duration(retention))
+		it.skip(numActualFields - numExpectedFields)
+		return sp
+	}
+	slidingWindow := time.Duration(it.decodeVarint())
+	sp := policy.NewSlidingWindowStoragePolicy(
+		resolution.Window,
+		resolution.Precision,
+		time.Duration(retention),
+		slidingWindow,
+	)
+	it.skip(numActualFields - numSlidingWindowStoragePolicyFields)
 	return sp
 }
 
@@ -37,6 +37,12 @@ func TestAggregationTypesRoundTrip(t *testing.T) {
 		aggregation.ID{5},
 		aggregation.ID{100},
 		aggregation.ID{12345},
+		aggregation.MustCompressTypes(aggregation.Max, aggregation.MustQuantileType(0.995)),
+		aggregation.MustCompressTypes(
+			aggregation.MustQuantileType(0.0055), aggregation.MustQuantileType(0.25),
+			aggregation.MustQuantileType(0.75), aggregation.MustQuantileType(0.9995),
+			aggregation.MustQuantileType(0.999), aggregation.MustQuantileType(0.98),
+		),
 	}
 
 	for _, input := range inputs {
@@ -49,6 +55,29 @@ func TestAggregationTypesRoundTrip(t *testing.T) {
 	}
 }
 
+func TestAggregationTypesWithQuantileTypesDecodedByPriorIterators(t *testing.T) {
+	input := aggregation.MustCompressTypes(
+		aggregation.Max, aggregation.P99, aggregation.MustQuantileType(0.995),
+	)
+	enc := newBaseEncoder(NewBufferedEncoder()).(*baseEncoder)
+	enc.encodeCompressedAggregationTypes(input)
+	enc.encodeVarint(42)
+	require.NoError(t, enc.err())
+
+	// Iterators predating parameterised quantile types decode the short form
+	// and skip the quantile types as an unknown field.
+	it := newBaseIterator(enc.bufEncoder.Buffer(), 16).(*baseIterator)
+	numActualFields := it.decodeNumObjectFields()
+	require.Equal(t, shortAggregationID, it.decodeObjectType())
+	word := uint64(it.decodeVarint())
+	it.skip(numActualFields - numShortAggregationIDFields)
+	require.Equal(t, int64(42), it.decodeVarint())
+	require.NoError(t, it.err())
+
+	expected := aggregation.MustCompressTypes(aggregation.Max, aggregation.P99)
+	require.Equal(t, expected[0], word)
+}
+
 func TestUnaggregatedPolicyRoundTrip(t *testing.T) {
 	inputs := []policy.Policy{
 		policy.NewPolicy(policy.NewStoragePolicy(10*time.Second, xtime.Second, 24*time.Hour), aggregation.DefaultID),
@@ -86,12 +86,15 @@ const (
 	numCustomStagedPoliciesListFields                = 2
 	numStagedPoliciesFields                          = 3
 	numStoragePolicyFields                           = 2
+	numSlidingWindowStoragePolicyFields              = 3
 	numKnownResolutionFields                         = 2
 	numUnknownResolutionFields                       = 3
 	numKnownRetentionFields                          = 2
 	numDefaultAggregationIDFields                    = 1
 	numShortAggregationIDFields                      = 2
+	numShortAggregationIDWithQuantilesFields         = 3
 	numLongAggregationIDFields                       = 2
+	numLongAggregationIDWithQuantilesFields          = 3
 	numPolicyFields                                  = 2
 )
 
@@ -244,13 +244,27 @@ func expectedResultsForCompressedAggregationTypes(compressed aggregation.ID) []i
 		return append(results, numFieldsForType(defaultAggregationID), int64(defaultAggregationID))
 	}
 
-	if len(compressed) == 1 {
-		return append(results, numFieldsForType(shortAggregationID), int64(shortAggregationID), int64(compressed[0]))
+	var quantileUnits []interface{}
+	if units := compressed.AppendQuantileUnits(nil); len(units) > 0 {
+		quantileUnits = append(quantileUnits, len(units))
+		for _, u := range units {
+			quantileUnits = append(quantileUnits, int64(u))
+		}
+	}
+
+	if aggregation.IDLen == 1 {
+		if len(quantileUnits) > 0 {
+			results = append(results, numShortAggregationIDWithQuantilesFields)
+		} else {
+			results = append(results, numFieldsForType(shortAggregationID))
+		}
+		results = append(results, int64(shortAggregationID), int64(compressed[0]))
+		return append(results, quantileUnits...)
 	}
 
-	results = append(results, numFieldsForType(longAggregationID), int64(longAggregationID), int64(len(compressed)))
+	results = append(results, numFieldsForType(longAggregationID), int64(longAggregationID), int64(aggregation.IDLen))
 
-	for _, code := range compressed {
+	for _, code := range compressed[:aggregation.IDLen] {
 		results = append(results, code)
 	}
 
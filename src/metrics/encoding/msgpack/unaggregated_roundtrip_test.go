@@ -272,6 +272,25 @@ func TestUnaggregatedEncodeDecodeAllTypesWithMultiCustomPoliciesList(t *testing.
 	validateUnaggregatedMetricWithPoliciesListRoundtrip(t, testInputWithAllTypesAndMultiCustomPoliciesList...)
 }
 
+func TestUnaggregatedEncodeDecodeSlidingWindowPoliciesList(t *testing.T) {
+	validateUnaggregatedMetricWithPoliciesListRoundtrip(t, metricWithPoliciesList{
+		metric: testCounter,
+		policiesList: policy.PoliciesList{
+			policy.NewStagedPolicies(
+				time.Now().UnixNano(),
+				false,
+				[]policy.Policy{
+					policy.NewPolicy(policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour), aggregation.DefaultID),
+					policy.NewPolicy(
+						policy.NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour, 5*time.Minute),
+						aggregation.DefaultID,
+					),
+				},
+			),
+		},
+	})
+}
+
 func TestUnaggregatedEncodeDecodeMetricStress(t *testing.T) {
 	numIter := 10
 	numMetrics := 10000
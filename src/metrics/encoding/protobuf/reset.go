@@ -130,6 +130,7 @@ func resetForwardedMetric(pb *metricpb.ForwardedMetric) {
 	pb.Id = pb.Id[:0]
 	pb.TimeNanos = 0
 	pb.Values = pb.Values[:0]
+	pb.CountDistinctSketch = pb.CountDistinctSketch[:0]
 }
 
 func resetTimedMetric(pb *metricpb.TimedMetric) {
@@ -158,6 +159,7 @@ func resetForwardMetadata(pb *metricpb.ForwardMetadata) {
 	pb.Pipeline.Ops = pb.Pipeline.Ops[:0]
 	pb.SourceId = 0
 	pb.NumForwardedTimes = 0
+	pb.RollupRuleId = pb.RollupRuleId[:0]
 }
 
 func resetTimedMetadata(pb *metricpb.TimedMetadata) {
@@ -64,16 +64,18 @@ var (
 		Value:     1.23,
 	}
 	testForwardedMetricBeforeResetProto = metricpb.ForwardedMetric{
-		Type:      metricpb.MetricType_COUNTER,
-		Id:        []byte("testForwardedMetric"),
-		TimeNanos: 1234,
-		Values:    []float64{1.23, -4.56},
+		Type:                metricpb.MetricType_COUNTER,
+		Id:                  []byte("testForwardedMetric"),
+		TimeNanos:           1234,
+		Values:              []float64{1.23, -4.56},
+		CountDistinctSketch: []byte{1, 2, 3},
 	}
 	testForwardedMetricAfterResetProto = metricpb.ForwardedMetric{
-		Type:      metricpb.MetricType_UNKNOWN,
-		Id:        []byte{},
-		TimeNanos: 0,
-		Values:    []float64{},
+		Type:                metricpb.MetricType_UNKNOWN,
+		Id:                  []byte{},
+		TimeNanos:           0,
+		Values:              []float64{},
+		CountDistinctSketch: []byte{},
 	}
 	testMetadatasBeforeResetProto = metricpb.StagedMetadatas{
 		Metadatas: []metricpb.StagedMetadata{
@@ -104,6 +106,7 @@ var (
 		},
 		SourceId:          342,
 		NumForwardedTimes: 23,
+		RollupRuleId:      []byte("rule"),
 	}
 	testForwardMetadataAfterResetProto = metricpb.ForwardMetadata{
 		Pipeline: pipelinepb.AppliedPipeline{
@@ -111,6 +114,7 @@ var (
 		},
 		SourceId:          0,
 		NumForwardedTimes: 0,
+		RollupRuleId:      []byte{},
 	}
 )
 
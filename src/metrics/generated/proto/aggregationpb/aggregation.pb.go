@@ -49,32 +49,36 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// AggregationType is an aggregation type. In addition to the enumerated values,
+// values between 65536 and 75536 exclusive are parameterised quantile types
+// holding the quantile in units of 0.0001 on top of 65536, e.g. 75486 is p99.5.
 type AggregationType int32
 
 const (
-	AggregationType_UNKNOWN AggregationType = 0
-	AggregationType_LAST    AggregationType = 1
-	AggregationType_MIN     AggregationType = 2
-	AggregationType_MAX     AggregationType = 3
-	AggregationType_MEAN    AggregationType = 4
-	AggregationType_MEDIAN  AggregationType = 5
-	AggregationType_COUNT   AggregationType = 6
-	AggregationType_SUM     AggregationType = 7
-	AggregationType_SUMSQ   AggregationType = 8
-	AggregationType_STDEV   AggregationType = 9
-	AggregationType_P10     AggregationType = 10
-	AggregationType_P20     AggregationType = 11
-	AggregationType_P30     AggregationType = 12
-	AggregationType_P40     AggregationType = 13
-	AggregationType_P50     AggregationType = 14
-	AggregationType_P60     AggregationType = 15
-	AggregationType_P70     AggregationType = 16
-	AggregationType_P80     AggregationType = 17
-	AggregationType_P90     AggregationType = 18
-	AggregationType_P95     AggregationType = 19
-	AggregationType_P99     AggregationType = 20
-	AggregationType_P999    AggregationType = 21
-	AggregationType_P9999   AggregationType = 22
+	AggregationType_UNKNOWN        AggregationType = 0
+	AggregationType_LAST           AggregationType = 1
+	AggregationType_MIN            AggregationType = 2
+	AggregationType_MAX            AggregationType = 3
+	AggregationType_MEAN           AggregationType = 4
+	AggregationType_MEDIAN         AggregationType = 5
+	AggregationType_COUNT          AggregationType = 6
+	AggregationType_SUM            AggregationType = 7
+	AggregationType_SUMSQ          AggregationType = 8
+	AggregationType_STDEV          AggregationType = 9
+	AggregationType_P10            AggregationType = 10
+	AggregationType_P20            AggregationType = 11
+	AggregationType_P30            AggregationType = 12
+	AggregationType_P40            AggregationType = 13
+	AggregationType_P50            AggregationType = 14
+	AggregationType_P60            AggregationType = 15
+	AggregationType_P70            AggregationType = 16
+	AggregationType_P80            AggregationType = 17
+	AggregationType_P90            AggregationType = 18
+	AggregationType_P95            AggregationType = 19
+	AggregationType_P99            AggregationType = 20
+	AggregationType_P999           AggregationType = 21
+	AggregationType_P9999          AggregationType = 22
+	AggregationType_COUNT_DISTINCT AggregationType = 23
 )
 
 var AggregationType_name = map[int32]string{
@@ -101,31 +105,33 @@ var AggregationType_name = map[int32]string{
 	20: "P99",
 	21: "P999",
 	22: "P9999",
+	23: "COUNT_DISTINCT",
 }
 var AggregationType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"LAST":    1,
-	"MIN":     2,
-	"MAX":     3,
-	"MEAN":    4,
-	"MEDIAN":  5,
-	"COUNT":   6,
-	"SUM":     7,
-	"SUMSQ":   8,
-	"STDEV":   9,
-	"P10":     10,
-	"P20":     11,
-	"P30":     12,
-	"P40":     13,
-	"P50":     14,
-	"P60":     15,
-	"P70":     16,
-	"P80":     17,
-	"P90":     18,
-	"P95":     19,
-	"P99":     20,
-	"P999":    21,
-	"P9999":   22,
+	"UNKNOWN":        0,
+	"LAST":           1,
+	"MIN":            2,
+	"MAX":            3,
+	"MEAN":           4,
+	"MEDIAN":         5,
+	"COUNT":          6,
+	"SUM":            7,
+	"SUMSQ":          8,
+	"STDEV":          9,
+	"P10":            10,
+	"P20":            11,
+	"P30":            12,
+	"P40":            13,
+	"P50":            14,
+	"P60":            15,
+	"P70":            16,
+	"P80":            17,
+	"P90":            18,
+	"P95":            19,
+	"P99":            20,
+	"P999":           21,
+	"P9999":          22,
+	"COUNT_DISTINCT": 23,
 }
 
 func (x AggregationType) String() string {
@@ -136,7 +142,8 @@ func (AggregationType) EnumDescriptor() ([]byte, []int) { return fileDescriptorA
 // AggregationID is a unique identifier uniquely identifying
 // one or more aggregation types.
 type AggregationID struct {
-	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id        uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Quantiles []uint64 `protobuf:"varint,2,rep,packed,name=quantiles" json:"quantiles,omitempty"`
 }
 
 func (m *AggregationID) Reset()                    { *m = AggregationID{} }
@@ -151,6 +158,13 @@ func (m *AggregationID) GetId() uint64 {
 	return 0
 }
 
+func (m *AggregationID) GetQuantiles() []uint64 {
+	if m != nil {
+		return m.Quantiles
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*AggregationID)(nil), "aggregationpb.AggregationID")
 	proto.RegisterEnum("aggregationpb.AggregationType", AggregationType_name, AggregationType_value)
@@ -175,6 +189,23 @@ func (m *AggregationID) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintAggregation(dAtA, i, uint64(m.Id))
 	}
+	if len(m.Quantiles) > 0 {
+		dAtA2 := make([]byte, len(m.Quantiles)*10)
+		var j1 int
+		for _, num := range m.Quantiles {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAggregation(dAtA, i, uint64(j1))
+		i += copy(dAtA[i:], dAtA2[:j1])
+	}
 	return i, nil
 }
 
@@ -193,6 +224,13 @@ func (m *AggregationID) Size() (n int) {
 	if m.Id != 0 {
 		n += 1 + sovAggregation(uint64(m.Id))
 	}
+	if len(m.Quantiles) > 0 {
+		l = 0
+		for _, e := range m.Quantiles {
+			l += sovAggregation(uint64(e))
+		}
+		n += 1 + sovAggregation(uint64(l)) + l
+	}
 	return n
 }
 
@@ -257,6 +295,68 @@ func (m *AggregationID) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAggregation
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Quantiles = append(m.Quantiles, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAggregation
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthAggregation
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAggregation
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Quantiles = append(m.Quantiles, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quantiles", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAggregation(dAtA[iNdEx:])
@@ -388,25 +488,27 @@ func init() {
 }
 
 var fileDescriptorAggregation = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0xd1, 0x3d, 0x4e, 0xc3, 0x30,
-	0x1c, 0x05, 0xf0, 0xba, 0xdf, 0x75, 0x69, 0xfb, 0xc7, 0x7c, 0x88, 0x29, 0x20, 0x26, 0xc4, 0x50,
-	0x1b, 0x42, 0x81, 0x48, 0x2c, 0x81, 0x76, 0xa8, 0x20, 0x6e, 0x21, 0x09, 0x20, 0xb6, 0xa6, 0x89,
-	0x42, 0x86, 0x34, 0x55, 0x1a, 0x06, 0x6e, 0xc1, 0xb1, 0x18, 0x39, 0x02, 0x0a, 0x37, 0xe0, 0x04,
-	0xc8, 0xff, 0x0e, 0x94, 0x99, 0xed, 0xe7, 0xf7, 0x9e, 0x64, 0x4b, 0xa6, 0x32, 0x8c, 0xb2, 0xe7,
-	0x17, 0xaf, 0x3b, 0x4d, 0x62, 0x1e, 0xeb, 0xbe, 0xc7, 0x63, 0x9d, 0x2f, 0xd2, 0x29, 0x8f, 0x83,
-	0x2c, 0x8d, 0xa6, 0x0b, 0x1e, 0x06, 0xb3, 0x20, 0x9d, 0x64, 0x81, 0xcf, 0xe7, 0x69, 0x92, 0x25,
-	0x7c, 0x12, 0x86, 0x69, 0x10, 0x4e, 0xb2, 0x28, 0x99, 0xcd, 0xbd, 0xd5, 0x53, 0x17, 0x7b, 0xd6,
-	0xfa, 0x33, 0xd8, 0xdf, 0xa5, 0x2d, 0xf3, 0x37, 0x18, 0xf6, 0x59, 0x9b, 0x16, 0x23, 0x7f, 0x87,
-	0xec, 0x91, 0x83, 0xf2, 0x5d, 0x31, 0xf2, 0x0f, 0xbf, 0x09, 0xed, 0xac, 0x2c, 0x9c, 0xd7, 0x79,
-	0xc0, 0x9a, 0xb4, 0xe6, 0xca, 0x6b, 0x39, 0x7a, 0x90, 0x50, 0x60, 0x75, 0x5a, 0xbe, 0x31, 0x6d,
-	0x07, 0x08, 0xab, 0xd1, 0x92, 0x35, 0x94, 0x50, 0x44, 0x98, 0x8f, 0x50, 0x52, 0x9d, 0x35, 0x30,
-	0x25, 0x94, 0x19, 0xa5, 0x55, 0x6b, 0xd0, 0x1f, 0x9a, 0x12, 0x2a, 0xac, 0x41, 0x2b, 0x57, 0x23,
-	0x57, 0x3a, 0x50, 0x55, 0x4b, 0xdb, 0xb5, 0xa0, 0xa6, 0x32, 0xdb, 0xb5, 0xec, 0x5b, 0xa8, 0x23,
-	0x9d, 0xfe, 0xe0, 0x1e, 0x1a, 0xaa, 0x1e, 0x1f, 0x09, 0xa0, 0x88, 0x63, 0x01, 0x4d, 0x84, 0x2e,
-	0x60, 0x0d, 0x71, 0x22, 0xa0, 0x85, 0xe8, 0x09, 0x68, 0x23, 0x4e, 0x05, 0x74, 0x10, 0x67, 0x02,
-	0x00, 0x71, 0x2e, 0x60, 0x1d, 0x61, 0x08, 0x60, 0x4b, 0xf4, 0x60, 0x63, 0x09, 0x03, 0x36, 0xd5,
-	0x13, 0xc7, 0x86, 0x61, 0xc0, 0x96, 0xba, 0x57, 0xc9, 0x80, 0xed, 0x4b, 0xf9, 0x9e, 0x6b, 0xe4,
-	0x23, 0xd7, 0xc8, 0x67, 0xae, 0x91, 0xb7, 0x2f, 0xad, 0xf0, 0x74, 0xf1, 0x9f, 0x6f, 0xf0, 0xaa,
-	0x18, 0xea, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x82, 0xc4, 0x3c, 0xea, 0xcd, 0x01, 0x00, 0x00,
+	// 352 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0xd1, 0x3d, 0x4f, 0xc2, 0x40,
+	0x18, 0x07, 0x70, 0x5a, 0xca, 0xdb, 0x21, 0xf0, 0x78, 0xbe, 0x31, 0x98, 0x86, 0x38, 0x11, 0x07,
+	0x7a, 0x5a, 0x51, 0x9b, 0xe8, 0x50, 0x29, 0x43, 0xa3, 0x3d, 0x90, 0xb6, 0x6a, 0x5c, 0x4c, 0x4b,
+	0x9b, 0xda, 0xc4, 0x52, 0x2c, 0x65, 0xf0, 0x0b, 0x38, 0xfb, 0xb1, 0x1c, 0xfd, 0x08, 0x06, 0xbf,
+	0x88, 0xe9, 0x31, 0x80, 0xb3, 0xdb, 0xef, 0xfe, 0xff, 0xe7, 0x72, 0x4f, 0x72, 0x88, 0x06, 0x61,
+	0xfa, 0x3c, 0x77, 0x3b, 0xe3, 0x38, 0x92, 0x22, 0xd9, 0x73, 0xa5, 0x48, 0x96, 0x66, 0xc9, 0x58,
+	0x8a, 0xfc, 0x34, 0x09, 0xc7, 0x33, 0x29, 0xf0, 0x27, 0x7e, 0xe2, 0xa4, 0xbe, 0x27, 0x4d, 0x93,
+	0x38, 0x8d, 0x25, 0x27, 0x08, 0x12, 0x3f, 0x70, 0xd2, 0x30, 0x9e, 0x4c, 0xdd, 0xf5, 0x53, 0x87,
+	0xf5, 0xb8, 0xf6, 0x67, 0xe0, 0xe0, 0x12, 0xd5, 0xd4, 0x55, 0xa0, 0x6b, 0xb8, 0x8e, 0xf8, 0xd0,
+	0x6b, 0x72, 0x2d, 0xae, 0x2d, 0x8c, 0xf8, 0xd0, 0xc3, 0xfb, 0xa8, 0xf2, 0x3a, 0x77, 0x26, 0x69,
+	0xf8, 0xe2, 0xcf, 0x9a, 0x7c, 0x2b, 0xdf, 0x16, 0x46, 0xab, 0xe0, 0xf0, 0x9d, 0x47, 0x8d, 0xb5,
+	0xfb, 0xd6, 0xdb, 0xd4, 0xc7, 0x55, 0x54, 0xb2, 0xe9, 0x35, 0x1d, 0xdc, 0x53, 0xc8, 0xe1, 0x32,
+	0x12, 0x6e, 0x54, 0xd3, 0x02, 0x0e, 0x97, 0x50, 0xde, 0xd0, 0x29, 0xf0, 0x0c, 0xea, 0x03, 0xe4,
+	0xb3, 0xce, 0xe8, 0xab, 0x14, 0x04, 0x8c, 0x50, 0xd1, 0xe8, 0x6b, 0xba, 0x4a, 0xa1, 0x80, 0x2b,
+	0xa8, 0xd0, 0x1b, 0xd8, 0xd4, 0x82, 0x62, 0x36, 0x69, 0xda, 0x06, 0x94, 0xb2, 0xcc, 0xb4, 0x0d,
+	0xf3, 0x16, 0xca, 0x8c, 0x96, 0xd6, 0xbf, 0x83, 0x4a, 0x56, 0x0f, 0x8f, 0x08, 0x20, 0x86, 0x63,
+	0x02, 0x55, 0x06, 0x99, 0xc0, 0x06, 0xc3, 0x09, 0x81, 0x1a, 0x43, 0x97, 0x40, 0x9d, 0xe1, 0x94,
+	0x40, 0x83, 0xe1, 0x8c, 0x00, 0x30, 0x9c, 0x13, 0xd8, 0x64, 0x50, 0x08, 0xe0, 0x25, 0xba, 0xb0,
+	0xb5, 0x84, 0x02, 0xdb, 0xd9, 0x8a, 0x43, 0x45, 0x51, 0x60, 0x27, 0x7b, 0x37, 0x93, 0x02, 0xbb,
+	0x18, 0xa3, 0x3a, 0xdb, 0xf0, 0x49, 0xd3, 0x4d, 0x4b, 0xa7, 0x3d, 0x0b, 0xf6, 0xae, 0xe8, 0xe7,
+	0x42, 0xe4, 0xbe, 0x16, 0x22, 0xf7, 0xbd, 0x10, 0xb9, 0x8f, 0x1f, 0x31, 0xf7, 0x78, 0xf1, 0x9f,
+	0x8f, 0x73, 0x8b, 0x2c, 0x94, 0x7f, 0x07, 0x00, 0xa5, 0xca, 0xce, 0xfa, 0xff, 0x01, 0x00, 0x00,
 }
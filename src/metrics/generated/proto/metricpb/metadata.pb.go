@@ -148,6 +148,7 @@ type ForwardMetadata struct {
 	Pipeline          pipelinepb.AppliedPipeline  `protobuf:"bytes,3,opt,name=pipeline" json:"pipeline"`
 	SourceId          uint32                      `protobuf:"varint,4,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
 	NumForwardedTimes int32                       `protobuf:"varint,5,opt,name=num_forwarded_times,json=numForwardedTimes,proto3" json:"num_forwarded_times,omitempty"`
+	RollupRuleId      []byte                      `protobuf:"bytes,6,opt,name=rollup_rule_id,json=rollupRuleId,proto3" json:"rollup_rule_id,omitempty"`
 }
 
 func (m *ForwardMetadata) Reset()                    { *m = ForwardMetadata{} }
@@ -190,6 +191,13 @@ func (m *ForwardMetadata) GetNumForwardedTimes() int32 {
 	return 0
 }
 
+func (m *ForwardMetadata) GetRollupRuleId() []byte {
+	if m != nil {
+		return m.RollupRuleId
+	}
+	return nil
+}
+
 type TimedMetadata struct {
 	AggregationId aggregationpb.AggregationID `protobuf:"bytes,1,opt,name=aggregation_id,json=aggregationId" json:"aggregation_id"`
 	StoragePolicy policypb.StoragePolicy      `protobuf:"bytes,2,opt,name=storage_policy,json=storagePolicy" json:"storage_policy"`
@@ -423,6 +431,12 @@ func (m *ForwardMetadata) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMetadata(dAtA, i, uint64(m.NumForwardedTimes))
 	}
+	if len(m.RollupRuleId) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintMetadata(dAtA, i, uint64(len(m.RollupRuleId)))
+		i += copy(dAtA[i:], m.RollupRuleId)
+	}
 	return i, nil
 }
 
@@ -541,6 +555,10 @@ func (m *ForwardMetadata) Size() (n int) {
 	if m.NumForwardedTimes != 0 {
 		n += 1 + sovMetadata(uint64(m.NumForwardedTimes))
 	}
+	l = len(m.RollupRuleId)
+	if l > 0 {
+		n += 1 + l + sovMetadata(uint64(l))
+	}
 	return n
 }
 
@@ -1165,6 +1183,37 @@ func (m *ForwardMetadata) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollupRuleId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RollupRuleId = append(m.RollupRuleId[:0], dAtA[iNdEx:postIndex]...)
+			if m.RollupRuleId == nil {
+				m.RollupRuleId = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetadata(dAtA[iNdEx:])
@@ -1406,40 +1455,41 @@ func init() {
 }
 
 var fileDescriptorMetadata = []byte{
-	// 551 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0xd1, 0x8a, 0xd3, 0x40,
-	0x14, 0xdd, 0x69, 0x77, 0x25, 0x9d, 0xda, 0x76, 0x8d, 0x82, 0xa1, 0x2b, 0xb5, 0xc4, 0x97, 0xbe,
-	0x38, 0x81, 0x56, 0xf1, 0x45, 0x85, 0x5d, 0x4a, 0xd9, 0x0a, 0xae, 0x4b, 0xd6, 0x27, 0x5f, 0x42,
-	0x92, 0x99, 0x8d, 0x03, 0x4d, 0x26, 0xcc, 0x4c, 0x94, 0x7e, 0x83, 0x2f, 0xfe, 0x80, 0xe0, 0x17,
-	0xf8, 0x1d, 0xfb, 0xe8, 0x17, 0x88, 0xd4, 0x1f, 0x91, 0x24, 0x33, 0x49, 0xba, 0x2f, 0x52, 0x45,
-	0xd8, 0xb7, 0x7b, 0xef, 0xdc, 0x7b, 0x38, 0xe7, 0x70, 0x18, 0xb8, 0x88, 0xa8, 0x7c, 0x9f, 0x05,
-	0x28, 0x64, 0xb1, 0x13, 0xcf, 0x70, 0xe0, 0xc4, 0x33, 0x47, 0xf0, 0xd0, 0x89, 0x89, 0xe4, 0x34,
-	0x14, 0x4e, 0x44, 0x12, 0xc2, 0x7d, 0x49, 0xb0, 0x93, 0x72, 0x26, 0x99, 0x9a, 0xa7, 0x41, 0x5e,
-	0xf8, 0xd8, 0x97, 0x3e, 0x2a, 0xe6, 0xa6, 0xa1, 0x1f, 0x86, 0x8f, 0x1b, 0x88, 0x11, 0x8b, 0x58,
-	0x79, 0x18, 0x64, 0x97, 0x45, 0x57, 0xa2, 0xe4, 0x55, 0x79, 0x38, 0x3c, 0xdb, 0x91, 0x80, 0x1f,
-	0x45, 0x9c, 0x44, 0xbe, 0xa4, 0x2c, 0x49, 0x83, 0x66, 0xa7, 0xf0, 0xe6, 0x3b, 0xe2, 0xa5, 0x6c,
-	0x45, 0xc3, 0x75, 0x1a, 0xa8, 0x42, 0xa1, 0x9c, 0xee, 0x8a, 0x42, 0x53, 0xb2, 0xa2, 0x09, 0xc9,
-	0x71, 0x54, 0x59, 0x22, 0xd9, 0x5f, 0x5a, 0xf0, 0xf0, 0x5c, 0x8d, 0x5e, 0x2b, 0xcf, 0xcc, 0x25,
-	0xec, 0x37, 0x98, 0x7b, 0x14, 0x5b, 0x60, 0x0c, 0x26, 0xdd, 0xe9, 0x03, 0xb4, 0x25, 0x0f, 0x1d,
-	0xd7, 0xdd, 0x72, 0x7e, 0xb2, 0x7f, 0xf5, 0xe3, 0xe1, 0x9e, 0xdb, 0x6b, 0xac, 0x2c, 0xb1, 0x79,
-	0x0a, 0x0f, 0x85, 0x64, 0xdc, 0x8f, 0x88, 0x57, 0x28, 0xa0, 0x44, 0x58, 0xad, 0x71, 0x7b, 0xd2,
-	0x9d, 0xde, 0x47, 0x5a, 0x1b, 0xba, 0x28, 0x37, 0xce, 0x8b, 0x5e, 0xe1, 0x0c, 0x44, 0x63, 0x48,
-	0x89, 0x30, 0x5f, 0x40, 0x43, 0x73, 0xb7, 0xda, 0x05, 0x9d, 0x23, 0x54, 0xeb, 0x42, 0xc7, 0x69,
-	0xba, 0xa2, 0x04, 0x6b, 0x2d, 0x0a, 0xa5, 0x3a, 0x31, 0x9f, 0xc2, 0x2e, 0xe6, 0x2c, 0x2d, 0x59,
-	0xac, 0xad, 0xfd, 0x31, 0x98, 0xf4, 0xa7, 0xf7, 0x6a, 0x0e, 0x73, 0xce, 0xd2, 0x92, 0x80, 0x0b,
-	0x71, 0x55, 0xdb, 0xaf, 0xa0, 0x51, 0xd9, 0xf2, 0x12, 0x76, 0x34, 0x9c, 0xb0, 0x40, 0x21, 0x62,
-	0x88, 0x74, 0xb0, 0xd0, 0x75, 0x17, 0x15, 0x83, 0xfa, 0xc4, 0xfe, 0x04, 0x60, 0xff, 0x42, 0xfa,
-	0x11, 0xc1, 0x15, 0xe4, 0x23, 0xd8, 0x0b, 0x33, 0xc9, 0x3e, 0x10, 0xee, 0x25, 0x7e, 0xc2, 0x44,
-	0x61, 0x74, 0xdb, 0xbd, 0xad, 0x86, 0x67, 0xf9, 0xcc, 0x1c, 0x41, 0x28, 0x59, 0x1c, 0x08, 0xc9,
-	0x12, 0x82, 0xad, 0xd6, 0x18, 0x4c, 0x0c, 0xb7, 0x31, 0x31, 0x9f, 0x40, 0x43, 0xc7, 0x5d, 0x39,
-	0x63, 0xd6, 0xb4, 0xae, 0xd1, 0xa9, 0x36, 0xed, 0x37, 0x70, 0xb0, 0x4d, 0x46, 0x98, 0xcf, 0x61,
-	0x47, 0x3f, 0x6b, 0x81, 0x56, 0x8d, 0xb4, 0xbd, 0xad, 0xe5, 0x55, 0x07, 0xf6, 0xb7, 0x16, 0x1c,
-	0x2c, 0x18, 0xff, 0xe8, 0x73, 0xfc, 0x3f, 0x92, 0x34, 0x87, 0xfd, 0xad, 0x24, 0xad, 0x0b, 0x27,
-	0xfe, 0x98, 0xa3, 0x5e, 0x33, 0x47, 0xeb, 0x7f, 0x4d, 0xd1, 0x11, 0xec, 0x08, 0x96, 0xf1, 0x90,
-	0xe4, 0x52, 0xf2, 0x0c, 0xf5, 0x5c, 0xa3, 0x1c, 0x2c, 0xb1, 0x89, 0xe0, 0xdd, 0x24, 0x8b, 0xbd,
-	0xcb, 0xd2, 0x03, 0x82, 0x3d, 0x49, 0x63, 0x22, 0xac, 0x83, 0x31, 0x98, 0x1c, 0xb8, 0x77, 0x92,
-	0x2c, 0x5e, 0xe8, 0x97, 0xb7, 0xf9, 0x83, 0xfd, 0x15, 0xc0, 0x5e, 0x5e, 0xdd, 0x5c, 0xbb, 0x4e,
-	0x96, 0x57, 0x9b, 0x11, 0xf8, 0xbe, 0x19, 0x81, 0x9f, 0x9b, 0x11, 0xf8, 0xfc, 0x6b, 0xb4, 0xf7,
-	0xee, 0xd9, 0x5f, 0xfe, 0xc8, 0xc1, 0xad, 0xa2, 0x9f, 0xfd, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x29,
-	0x6f, 0x13, 0xd0, 0xd3, 0x05, 0x00, 0x00,
+	// 571 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0xcb, 0x8a, 0xd4, 0x40,
+	0x14, 0x9d, 0xea, 0x79, 0x90, 0xa9, 0x7e, 0x8d, 0x51, 0x30, 0xf4, 0x48, 0x1b, 0xa2, 0x8b, 0x6c,
+	0x4c, 0xa0, 0x5b, 0x71, 0xa3, 0xc2, 0x0c, 0x4d, 0x33, 0x11, 0x1c, 0x87, 0x8c, 0x2b, 0x37, 0x21,
+	0x49, 0xd5, 0xc4, 0x40, 0x92, 0x2a, 0xaa, 0x2a, 0x4a, 0x7f, 0x83, 0x1b, 0x7f, 0x40, 0xf0, 0x73,
+	0x66, 0xe9, 0xc2, 0xb5, 0x48, 0xfb, 0x23, 0x92, 0x47, 0x25, 0xe9, 0xd9, 0x48, 0x2b, 0x82, 0xbb,
+	0x7b, 0x4f, 0xdd, 0x7b, 0x38, 0xe7, 0x72, 0x28, 0xb8, 0x8c, 0x62, 0xf1, 0x2e, 0x0f, 0xac, 0x90,
+	0xa4, 0x76, 0x3a, 0x47, 0x81, 0x9d, 0xce, 0x6d, 0xce, 0x42, 0x3b, 0xc5, 0x82, 0xc5, 0x21, 0xb7,
+	0x23, 0x9c, 0x61, 0xe6, 0x0b, 0x8c, 0x6c, 0xca, 0x88, 0x20, 0x35, 0x4e, 0x83, 0xa2, 0xf0, 0x91,
+	0x2f, 0x7c, 0xab, 0xc4, 0x55, 0x45, 0x3e, 0x4c, 0x1e, 0x75, 0x18, 0x23, 0x12, 0x91, 0x6a, 0x31,
+	0xc8, 0xaf, 0xca, 0xae, 0x62, 0x29, 0xaa, 0x6a, 0x71, 0x72, 0xbe, 0xa5, 0x00, 0x3f, 0x8a, 0x18,
+	0x8e, 0x7c, 0x11, 0x93, 0x8c, 0x06, 0xdd, 0xae, 0xe6, 0x5b, 0x6c, 0xc9, 0x47, 0x49, 0x12, 0x87,
+	0x2b, 0x1a, 0xd4, 0x45, 0xcd, 0x72, 0xb6, 0x2d, 0x4b, 0x4c, 0x71, 0x12, 0x67, 0x98, 0x06, 0x4d,
+	0x59, 0x31, 0x19, 0x9f, 0x7b, 0xf0, 0xe8, 0xa2, 0x86, 0x5e, 0xd5, 0x37, 0x53, 0x1d, 0x38, 0xea,
+	0x28, 0xf7, 0x62, 0xa4, 0x01, 0x1d, 0x98, 0xfd, 0xd9, 0x3d, 0x6b, 0xc3, 0x9e, 0x75, 0xd2, 0x76,
+	0xce, 0xe2, 0x74, 0xef, 0xfa, 0xfb, 0xfd, 0x1d, 0x77, 0xd8, 0x19, 0x71, 0x90, 0x7a, 0x06, 0x8f,
+	0xb8, 0x20, 0xcc, 0x8f, 0xb0, 0x57, 0x3a, 0x88, 0x31, 0xd7, 0x7a, 0xfa, 0xae, 0xd9, 0x9f, 0xdd,
+	0xb5, 0xa4, 0x37, 0xeb, 0xb2, 0x9a, 0xb8, 0x28, 0xfb, 0x9a, 0x67, 0xcc, 0x3b, 0x60, 0x8c, 0xb9,
+	0xfa, 0x1c, 0x2a, 0x52, 0xbb, 0xb6, 0x5b, 0xca, 0x39, 0xb6, 0x5a, 0x5f, 0xd6, 0x09, 0xa5, 0x49,
+	0x8c, 0x91, 0xf4, 0x52, 0xb3, 0x34, 0x2b, 0xea, 0x13, 0xd8, 0x47, 0x8c, 0xd0, 0x4a, 0xc5, 0x4a,
+	0xdb, 0xd3, 0x81, 0x39, 0x9a, 0xdd, 0x69, 0x35, 0x2c, 0x18, 0xa1, 0x95, 0x00, 0x17, 0xa2, 0xa6,
+	0x36, 0x5e, 0x42, 0xa5, 0x39, 0xcb, 0x0b, 0x78, 0x28, 0xe9, 0xb8, 0x06, 0x4a, 0x13, 0x13, 0x4b,
+	0x06, 0xcb, 0xba, 0x79, 0xc5, 0x5a, 0x41, 0xbb, 0x62, 0x7c, 0x04, 0x70, 0x74, 0x29, 0xfc, 0x08,
+	0xa3, 0x86, 0xf2, 0x01, 0x1c, 0x86, 0xb9, 0x20, 0xef, 0x31, 0xf3, 0x32, 0x3f, 0x23, 0xbc, 0x3c,
+	0xf4, 0xae, 0x3b, 0xa8, 0xc1, 0xf3, 0x02, 0x53, 0xa7, 0x10, 0x0a, 0x92, 0x06, 0x5c, 0x90, 0x0c,
+	0x23, 0xad, 0xa7, 0x03, 0x53, 0x71, 0x3b, 0x88, 0xfa, 0x18, 0x2a, 0x32, 0xee, 0xf5, 0x65, 0xd4,
+	0x56, 0xd6, 0x0d, 0x39, 0xcd, 0xa4, 0xf1, 0x1a, 0x8e, 0x37, 0xc5, 0x70, 0xf5, 0x19, 0x3c, 0x94,
+	0xcf, 0xd2, 0xa0, 0xd6, 0x32, 0x6d, 0x4e, 0x4b, 0x7b, 0xcd, 0x82, 0xf1, 0xad, 0x07, 0xc7, 0x4b,
+	0xc2, 0x3e, 0xf8, 0x0c, 0xfd, 0x8b, 0x24, 0x2d, 0xe0, 0x68, 0x23, 0x49, 0xab, 0xf2, 0x12, 0xbf,
+	0xcd, 0xd1, 0xb0, 0x9b, 0xa3, 0xd5, 0xdf, 0xa6, 0xe8, 0x18, 0x1e, 0x72, 0x92, 0xb3, 0x10, 0x17,
+	0x56, 0x8a, 0x0c, 0x0d, 0x5d, 0xa5, 0x02, 0x1c, 0xa4, 0x5a, 0xf0, 0x76, 0x96, 0xa7, 0xde, 0x55,
+	0x75, 0x03, 0x8c, 0x3c, 0x11, 0xa7, 0x98, 0x6b, 0xfb, 0x3a, 0x30, 0xf7, 0xdd, 0x5b, 0x59, 0x9e,
+	0x2e, 0xe5, 0xcb, 0x9b, 0xe2, 0x41, 0x7d, 0x08, 0x47, 0x8c, 0x24, 0x49, 0x4e, 0x3d, 0x96, 0x27,
+	0x25, 0xe3, 0x81, 0x0e, 0xcc, 0x81, 0x3b, 0xa8, 0x50, 0x37, 0x4f, 0xb0, 0x83, 0x8c, 0x2f, 0x00,
+	0x0e, 0x8b, 0xf9, 0xff, 0xf7, 0xa8, 0xa7, 0xce, 0xf5, 0x7a, 0x0a, 0xbe, 0xae, 0xa7, 0xe0, 0xc7,
+	0x7a, 0x0a, 0x3e, 0xfd, 0x9c, 0xee, 0xbc, 0x7d, 0xfa, 0x87, 0xff, 0x76, 0x70, 0x50, 0xf6, 0xf3,
+	0x5f, 0x03, 0x00, 0x07, 0xbc, 0xe1, 0x5e, 0xf9, 0x05, 0x00, 0x00,
 }
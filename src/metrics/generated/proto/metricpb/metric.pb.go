@@ -176,10 +176,11 @@ func (m *TimedMetric) GetValue() float64 {
 }
 
 type ForwardedMetric struct {
-	Type      MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=metricpb.MetricType" json:"type,omitempty"`
-	Id        []byte     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	TimeNanos int64      `protobuf:"varint,3,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
-	Values    []float64  `protobuf:"fixed64,4,rep,packed,name=values" json:"values,omitempty"`
+	Type                MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=metricpb.MetricType" json:"type,omitempty"`
+	Id                  []byte     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	TimeNanos           int64      `protobuf:"varint,3,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
+	Values              []float64  `protobuf:"fixed64,4,rep,packed,name=values" json:"values,omitempty"`
+	CountDistinctSketch []byte     `protobuf:"bytes,5,opt,name=count_distinct_sketch,json=countDistinctSketch,proto3" json:"count_distinct_sketch,omitempty"`
 }
 
 func (m *ForwardedMetric) Reset()                    { *m = ForwardedMetric{} }
@@ -215,6 +216,13 @@ func (m *ForwardedMetric) GetValues() []float64 {
 	return nil
 }
 
+func (m *ForwardedMetric) GetCountDistinctSketch() []byte {
+	if m != nil {
+		return m.CountDistinctSketch
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Counter)(nil), "metricpb.Counter")
 	proto.RegisterType((*BatchTimer)(nil), "metricpb.BatchTimer")
@@ -397,6 +405,12 @@ func (m *ForwardedMetric) MarshalTo(dAtA []byte) (int, error) {
 			i += 8
 		}
 	}
+	if len(m.CountDistinctSketch) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.CountDistinctSketch)))
+		i += copy(dAtA[i:], m.CountDistinctSketch)
+	}
 	return i, nil
 }
 
@@ -483,6 +497,10 @@ func (m *ForwardedMetric) Size() (n int) {
 	if len(m.Values) > 0 {
 		n += 1 + sovMetric(uint64(len(m.Values)*8)) + len(m.Values)*8
 	}
+	l = len(m.CountDistinctSketch)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
 	return n
 }
 
@@ -1092,6 +1110,37 @@ func (m *ForwardedMetric) Unmarshal(dAtA []byte) error {
 			} else {
 				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
 			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountDistinctSketch", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CountDistinctSketch = append(m.CountDistinctSketch[:0], dAtA[iNdEx:postIndex]...)
+			if m.CountDistinctSketch == nil {
+				m.CountDistinctSketch = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetric(dAtA[iNdEx:])
@@ -1223,27 +1272,29 @@ func init() {
 }
 
 var fileDescriptorMetric = []byte{
-	// 340 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x72, 0x49, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
-	0x2e, 0x4a, 0xd6, 0xcf, 0x4d, 0x2d, 0x29, 0xca, 0x4c, 0x2e, 0xd6, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d,
-	0x4a, 0x2c, 0x49, 0x4d, 0xd1, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x87, 0x8a, 0x17, 0x24, 0x41, 0x19,
-	0x7a, 0x60, 0x51, 0x21, 0x0e, 0x98, 0xb0, 0x92, 0x3e, 0x17, 0xbb, 0x73, 0x7e, 0x69, 0x5e, 0x49,
-	0x6a, 0x91, 0x10, 0x1f, 0x17, 0x53, 0x66, 0x8a, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x4f, 0x10, 0x53,
-	0x66, 0x8a, 0x90, 0x08, 0x17, 0x6b, 0x59, 0x62, 0x4e, 0x69, 0xaa, 0x04, 0x93, 0x02, 0xa3, 0x06,
-	0x73, 0x10, 0x84, 0xa3, 0x64, 0xc2, 0xc5, 0xe5, 0x94, 0x58, 0x92, 0x9c, 0x11, 0x92, 0x99, 0x8b,
-	0x45, 0x8f, 0x18, 0x17, 0x1b, 0x58, 0x59, 0xb1, 0x04, 0x93, 0x02, 0xb3, 0x06, 0x63, 0x10, 0x94,
-	0xa7, 0xa4, 0xcb, 0xc5, 0xea, 0x9e, 0x58, 0x9a, 0x9e, 0x8a, 0xdf, 0x12, 0x46, 0x98, 0x25, 0x35,
-	0x5c, 0xdc, 0x20, 0xf3, 0x53, 0x7c, 0xc1, 0xce, 0x14, 0xd2, 0xe0, 0x62, 0x29, 0xa9, 0x2c, 0x48,
-	0x05, 0x6b, 0xe3, 0x33, 0x12, 0xd1, 0x83, 0xb9, 0x5e, 0x0f, 0x22, 0x1f, 0x52, 0x59, 0x90, 0x1a,
-	0x04, 0x56, 0x01, 0x35, 0x9e, 0x09, 0x6e, 0xbc, 0x2c, 0x17, 0x57, 0x49, 0x66, 0x6e, 0x6a, 0x7c,
-	0x5e, 0x62, 0x5e, 0x7e, 0xb1, 0x04, 0x33, 0xd8, 0x23, 0x9c, 0x20, 0x11, 0x3f, 0x90, 0x00, 0xc2,
-	0x76, 0x16, 0x64, 0xdb, 0x9b, 0x18, 0xb9, 0xf8, 0xdd, 0xf2, 0x8b, 0xca, 0x13, 0x8b, 0x52, 0x68,
-	0xef, 0x04, 0x44, 0x88, 0xb1, 0x20, 0x87, 0x98, 0x96, 0x0d, 0x17, 0x17, 0xc2, 0x68, 0x21, 0x6e,
-	0x2e, 0xf6, 0x50, 0x3f, 0x6f, 0x3f, 0xff, 0x70, 0x3f, 0x01, 0x06, 0x10, 0xc7, 0xd9, 0x3f, 0xd4,
-	0x2f, 0xc4, 0x35, 0x48, 0x80, 0x51, 0x88, 0x93, 0x8b, 0x35, 0xc4, 0xd3, 0xd7, 0x35, 0x48, 0x80,
-	0x09, 0xc4, 0x74, 0x77, 0x0c, 0x75, 0x77, 0x15, 0x60, 0x76, 0xf2, 0x3c, 0xf1, 0x48, 0x8e, 0xf1,
-	0xc2, 0x23, 0x39, 0xc6, 0x07, 0x8f, 0xe4, 0x18, 0x27, 0x3c, 0x96, 0x63, 0x88, 0x32, 0x27, 0x33,
-	0xe1, 0x24, 0xb1, 0x81, 0xf9, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x24, 0x3b, 0xb0, 0x62,
-	0x7a, 0x02, 0x00, 0x00,
+	// 372 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x52, 0xcd, 0x4a, 0xeb, 0x40,
+	0x18, 0xed, 0x24, 0x4d, 0x7b, 0xfb, 0xf5, 0xd2, 0x1b, 0x72, 0x7b, 0x2f, 0xd9, 0x18, 0x4a, 0x57,
+	0x41, 0x30, 0x81, 0x56, 0x70, 0xe3, 0xc6, 0xfe, 0x58, 0x8a, 0x34, 0x85, 0x98, 0x22, 0xb8, 0x29,
+	0xf9, 0x19, 0xda, 0x41, 0xf3, 0xc3, 0x64, 0xa2, 0x14, 0x7c, 0x08, 0x9f, 0xc6, 0x67, 0x70, 0xe9,
+	0x23, 0x48, 0x7d, 0x11, 0xc9, 0x34, 0xb5, 0x5d, 0x88, 0x0b, 0xc1, 0xdd, 0x9c, 0x73, 0xe6, 0x3b,
+	0xe7, 0xe4, 0xcb, 0xc0, 0x60, 0x41, 0xd8, 0x32, 0xf3, 0x0c, 0x3f, 0x0e, 0xcd, 0xb0, 0x1b, 0x78,
+	0x66, 0xd8, 0x35, 0x53, 0xea, 0x9b, 0x21, 0x66, 0x94, 0xf8, 0xa9, 0xb9, 0xc0, 0x11, 0xa6, 0x2e,
+	0xc3, 0x81, 0x99, 0xd0, 0x98, 0xc5, 0x05, 0x9f, 0x78, 0xc5, 0xc1, 0xe0, 0xac, 0xf2, 0x6b, 0x4b,
+	0xb7, 0x4d, 0xa8, 0xf6, 0xe3, 0x2c, 0x62, 0x98, 0x2a, 0x0d, 0x10, 0x48, 0xa0, 0xa2, 0x16, 0xd2,
+	0x7f, 0xdb, 0x02, 0x09, 0x94, 0x26, 0x48, 0x77, 0xee, 0x6d, 0x86, 0x55, 0xa1, 0x85, 0x74, 0xd1,
+	0xde, 0x80, 0xf6, 0x31, 0x40, 0xcf, 0x65, 0xfe, 0xd2, 0x21, 0xe1, 0x27, 0x33, 0xff, 0xa1, 0xc2,
+	0xaf, 0xa5, 0xaa, 0xd0, 0x12, 0x75, 0x64, 0x17, 0xa8, 0x7d, 0x04, 0xd2, 0xc8, 0xcd, 0x16, 0xf8,
+	0xeb, 0x10, 0xb4, 0x0d, 0x79, 0x80, 0x7a, 0xee, 0x1f, 0x4c, 0x78, 0x4d, 0x45, 0x87, 0x32, 0x5b,
+	0x25, 0x98, 0x8f, 0x35, 0x3a, 0x4d, 0x63, 0xdb, 0xde, 0xd8, 0xe8, 0xce, 0x2a, 0xc1, 0x36, 0xbf,
+	0x51, 0xd8, 0x0b, 0x1f, 0xf6, 0x07, 0x00, 0x8c, 0x84, 0x78, 0x1e, 0xb9, 0x51, 0x9c, 0xaa, 0x22,
+	0xff, 0x90, 0x5a, 0xce, 0x58, 0x39, 0xb1, 0x4b, 0x2f, 0xef, 0xa7, 0x3f, 0x21, 0xf8, 0x73, 0x1e,
+	0xd3, 0x7b, 0x97, 0x06, 0x3f, 0x5f, 0x61, 0xb7, 0xb1, 0xf2, 0xfe, 0xc6, 0x94, 0x0e, 0xfc, 0xf3,
+	0xf3, 0x1f, 0x33, 0x0f, 0x48, 0xca, 0x48, 0xe4, 0xb3, 0x79, 0x7a, 0x83, 0x99, 0xbf, 0x54, 0x25,
+	0xee, 0xfc, 0x97, 0x8b, 0x83, 0x42, 0xbb, 0xe4, 0xd2, 0xe1, 0x29, 0xc0, 0xae, 0x8e, 0x52, 0x87,
+	0xea, 0xcc, 0xba, 0xb0, 0xa6, 0x57, 0x96, 0x5c, 0xca, 0x41, 0x7f, 0x3a, 0xb3, 0x9c, 0xa1, 0x2d,
+	0x23, 0xa5, 0x06, 0x92, 0x33, 0x9e, 0x0c, 0x6d, 0x59, 0xc8, 0x8f, 0xa3, 0xb3, 0xd9, 0x68, 0x28,
+	0x8b, 0xbd, 0xf1, 0xf3, 0x5a, 0x43, 0x2f, 0x6b, 0x0d, 0xbd, 0xae, 0x35, 0xf4, 0xf8, 0xa6, 0x95,
+	0xae, 0x4f, 0xbe, 0xf9, 0xd8, 0xbc, 0x0a, 0xc7, 0xdd, 0xf7, 0x01, 0x00, 0x08, 0x21, 0xd3, 0x00,
+	0xae, 0x02, 0x00, 0x00,
 }
@@ -237,6 +237,7 @@ func (m *Pipeline) GetOps() []PipelineOp {
 type AppliedRollupOp struct {
 	Id            []byte                      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	AggregationId aggregationpb.AggregationID `protobuf:"bytes,2,opt,name=aggregation_id,json=aggregationId" json:"aggregation_id"`
+	RuleId        []byte                      `protobuf:"bytes,3,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
 }
 
 func (m *AppliedRollupOp) Reset()                    { *m = AppliedRollupOp{} }
@@ -258,6 +259,13 @@ func (m *AppliedRollupOp) GetAggregationId() aggregationpb.AggregationID {
 	return aggregationpb.AggregationID{}
 }
 
+func (m *AppliedRollupOp) GetRuleId() []byte {
+	if m != nil {
+		return m.RuleId
+	}
+	return nil
+}
+
 // AppliedPipelineOp is a pipeline operation that has
 // been applied against a metric.
 type AppliedPipelineOp struct {
@@ -536,6 +544,12 @@ func (m *AppliedRollupOp) MarshalTo(dAtA []byte) (int, error) {
 		return 0, err
 	}
 	i += n6
+	if len(m.RuleId) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintPipeline(dAtA, i, uint64(len(m.RuleId)))
+		i += copy(dAtA[i:], m.RuleId)
+	}
 	return i, nil
 }
 
@@ -704,6 +718,10 @@ func (m *AppliedRollupOp) Size() (n int) {
 	}
 	l = m.AggregationId.Size()
 	n += 1 + l + sovPipeline(uint64(l))
+	l = len(m.RuleId)
+	if l > 0 {
+		n += 1 + l + sovPipeline(uint64(l))
+	}
 	return n
 }
 
@@ -1396,6 +1414,37 @@ func (m *AppliedRollupOp) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPipeline
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPipeline
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuleId = append(m.RuleId[:0], dAtA[iNdEx:postIndex]...)
+			if m.RuleId == nil {
+				m.RuleId = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPipeline(dAtA[iNdEx:])
@@ -1743,42 +1792,43 @@ func init() {
 }
 
 var fileDescriptorPipeline = []byte{
-	// 587 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0x4d, 0x6e, 0xd3, 0x40,
-	0x1c, 0xc5, 0x3b, 0x76, 0x94, 0xa6, 0xff, 0xd0, 0x34, 0x1d, 0x21, 0xe4, 0x7e, 0x10, 0x22, 0x8b,
-	0x45, 0x16, 0x60, 0x4b, 0x89, 0x40, 0x7c, 0xac, 0x52, 0x02, 0x69, 0x94, 0x60, 0x57, 0x43, 0x2a,
-	0x24, 0x36, 0x95, 0x1d, 0x4f, 0x8d, 0xa5, 0xd8, 0x1e, 0xd9, 0x8e, 0xaa, 0x5e, 0x80, 0x35, 0x87,
-	0xe1, 0x10, 0x5d, 0x72, 0x02, 0x84, 0xc2, 0x31, 0xd8, 0xa0, 0xd8, 0x4e, 0x32, 0x93, 0x04, 0x44,
-	0xbb, 0x1b, 0x8f, 0xdf, 0x7b, 0xf3, 0x9f, 0xf7, 0xb3, 0x0c, 0xa7, 0xae, 0x97, 0x7c, 0x9e, 0xd8,
-	0xda, 0x28, 0xf4, 0x75, 0xbf, 0xe5, 0xd8, 0xba, 0xdf, 0xd2, 0xe3, 0x68, 0xa4, 0xfb, 0x34, 0x89,
-	0xbc, 0x51, 0xac, 0xbb, 0x34, 0xa0, 0x91, 0x95, 0x50, 0x47, 0x67, 0x51, 0x98, 0x84, 0x3a, 0xf3,
-	0x18, 0x1d, 0x7b, 0x01, 0x65, 0xf6, 0x62, 0xa9, 0xa5, 0x6f, 0x30, 0x2c, 0x5f, 0x1d, 0x3e, 0xe5,
-	0x52, 0xdd, 0xd0, 0x0d, 0x33, 0xb3, 0x3d, 0xb9, 0x4c, 0x9f, 0xb2, 0xa4, 0xd9, 0x2a, 0xb3, 0x1e,
-	0x1a, 0xb7, 0x1c, 0xc2, 0x72, 0xdd, 0x88, 0xba, 0x56, 0xe2, 0x85, 0x01, 0xb3, 0xf9, 0xa7, 0x3c,
-	0x6f, 0x78, 0xcb, 0xbc, 0x24, 0xb2, 0x82, 0xf8, 0x32, 0x8c, 0xfc, 0x79, 0xa4, 0xb8, 0x91, 0xa5,
-	0xaa, 0x6f, 0x60, 0xb7, 0xbd, 0x3c, 0xca, 0x64, 0xb8, 0x09, 0x85, 0xe4, 0x9a, 0x51, 0x05, 0xd5,
-	0x51, 0xa3, 0xd2, 0xac, 0x69, 0xc2, 0x58, 0x1a, 0xa7, 0x1d, 0x5e, 0x33, 0x4a, 0x52, 0xad, 0x3a,
-	0x80, 0xea, 0x50, 0x08, 0x37, 0x19, 0x7e, 0x21, 0xe4, 0x3c, 0xd6, 0x56, 0xc7, 0xd1, 0x44, 0x07,
-	0x97, 0xf6, 0x05, 0x41, 0x89, 0x84, 0xe3, 0xf1, 0x84, 0x99, 0x0c, 0x1f, 0x40, 0x29, 0xa0, 0x57,
-	0x17, 0x81, 0xe5, 0x67, 0x51, 0x3b, 0x64, 0x3b, 0xa0, 0x57, 0x86, 0xe5, 0x53, 0x8c, 0xa1, 0x90,
-	0x58, 0x6e, 0xac, 0x48, 0x75, 0xb9, 0xb1, 0x43, 0xd2, 0x35, 0xee, 0xc3, 0x3e, 0x37, 0xf0, 0xc5,
-	0x2c, 0x2f, 0x56, 0xe4, 0xba, 0xfc, 0x1f, 0x57, 0xa9, 0x5a, 0xe2, 0x46, 0xac, 0x7e, 0x93, 0x00,
-	0xce, 0x72, 0xfe, 0x26, 0xc3, 0xba, 0x70, 0xa3, 0x23, 0x6d, 0xf9, 0x69, 0x68, 0x4b, 0x95, 0xb6,
-	0xbc, 0x08, 0x7e, 0x0d, 0x65, 0x2e, 0x53, 0x91, 0xea, 0xa8, 0x51, 0x6e, 0x1e, 0xf0, 0x3e, 0xa1,
-	0x7a, 0xc2, 0xab, 0x71, 0x07, 0x2a, 0x62, 0x65, 0x8a, 0x9c, 0xfa, 0x8f, 0x79, 0xff, 0x6a, 0xeb,
-	0x64, 0xc5, 0x83, 0x9f, 0x40, 0x31, 0x4a, 0xab, 0x54, 0x0a, 0xa9, 0xfb, 0x3e, 0xef, 0x9e, 0x97,
-	0x4c, 0x72, 0x8d, 0xda, 0x81, 0xc2, 0x6c, 0x7c, 0x5c, 0x86, 0xed, 0x73, 0xa3, 0x6f, 0x98, 0x1f,
-	0x8d, 0xea, 0x16, 0xde, 0x83, 0x72, 0xbb, 0xdb, 0x25, 0x6f, 0xbb, 0xed, 0x61, 0xcf, 0x34, 0xaa,
-	0x08, 0x63, 0xa8, 0x0c, 0x49, 0xdb, 0xf8, 0xf0, 0xce, 0x24, 0xef, 0xb3, 0x3d, 0x09, 0x03, 0x14,
-	0x89, 0x39, 0x18, 0x9c, 0x9f, 0x55, 0x65, 0xf5, 0x15, 0x94, 0xe6, 0x7d, 0x60, 0x0d, 0xe4, 0x90,
-	0xc5, 0x0a, 0xaa, 0xcb, 0x8d, 0x72, 0xf3, 0xc1, 0xe6, 0xca, 0x4e, 0x0a, 0x37, 0x3f, 0x1e, 0x6d,
-	0x91, 0x99, 0x50, 0x1d, 0xc3, 0x5e, 0x9b, 0xb1, 0xb1, 0x47, 0x9d, 0xc5, 0x17, 0x50, 0x01, 0xc9,
-	0x73, 0xd2, 0xd2, 0xef, 0x11, 0xc9, 0x73, 0x70, 0x0f, 0x2a, 0x3c, 0x62, 0xcf, 0xc9, 0x8b, 0x3d,
-	0xfe, 0x3b, 0xdf, 0x5e, 0x27, 0x3f, 0x63, 0x97, 0x93, 0xf4, 0x1c, 0xf5, 0x37, 0x82, 0xfd, 0xfc,
-	0x38, 0x8e, 0xf3, 0x73, 0x81, 0xb3, 0x2a, 0xf0, 0x5a, 0x15, 0xf3, 0xb8, 0xd7, 0x89, 0x49, 0x77,
-	0x20, 0xd6, 0x5a, 0x10, 0xcb, 0x78, 0x1f, 0x6d, 0x38, 0x7f, 0x0d, 0x5c, 0x6b, 0x13, 0xb8, 0x75,
-	0x4e, 0x88, 0xe3, 0x24, 0xa9, 0xa7, 0x8b, 0xae, 0x17, 0xb8, 0x9e, 0xf1, 0xb8, 0x1e, 0xfe, 0xf3,
-	0xe6, 0x1c, 0xb5, 0x93, 0xfe, 0xcd, 0xb4, 0x86, 0xbe, 0x4f, 0x6b, 0xe8, 0xe7, 0xb4, 0x86, 0xbe,
-	0xfe, 0xaa, 0x6d, 0x7d, 0x7a, 0x79, 0xe7, 0x3f, 0xb0, 0x5d, 0x4c, 0x77, 0x5a, 0x7f, 0x02, 0x00,
-	0x00, 0xff, 0xff, 0x26, 0x29, 0x00, 0xcc, 0xc5, 0x05, 0x00, 0x00,
+	// 601 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0xed, 0xd8, 0x51, 0x9a, 0xde, 0xd0, 0x24, 0x1d, 0x21, 0x70, 0x1f, 0x84, 0xc8, 0x62, 0x91,
+	0x05, 0xd8, 0x52, 0x22, 0x10, 0x8f, 0x55, 0x4a, 0x20, 0x8d, 0x12, 0xec, 0x6a, 0x48, 0x85, 0xc4,
+	0xa6, 0xb2, 0xe3, 0xa9, 0xb1, 0x14, 0xdb, 0x23, 0xdb, 0x51, 0xd5, 0x0f, 0x80, 0x35, 0x1f, 0xc3,
+	0x47, 0x74, 0xc9, 0x17, 0x20, 0x14, 0x3e, 0x83, 0x0d, 0xf2, 0x23, 0xc9, 0x38, 0x09, 0x88, 0x76,
+	0x37, 0x73, 0x7d, 0xce, 0x99, 0x7b, 0xcf, 0xb9, 0x32, 0x9c, 0xd8, 0x4e, 0xf4, 0x69, 0x6a, 0x2a,
+	0x63, 0xdf, 0x55, 0xdd, 0xb6, 0x65, 0xaa, 0x6e, 0x5b, 0x0d, 0x83, 0xb1, 0xea, 0xd2, 0x28, 0x70,
+	0xc6, 0xa1, 0x6a, 0x53, 0x8f, 0x06, 0x46, 0x44, 0x2d, 0x95, 0x05, 0x7e, 0xe4, 0xab, 0xcc, 0x61,
+	0x74, 0xe2, 0x78, 0x94, 0x99, 0x8b, 0xa3, 0x92, 0x7c, 0xc1, 0xb0, 0xfc, 0x74, 0xf0, 0x84, 0x53,
+	0xb5, 0x7d, 0xdb, 0x4f, 0xc9, 0xe6, 0xf4, 0x22, 0xb9, 0xa5, 0x4a, 0xf1, 0x29, 0xa5, 0x1e, 0x68,
+	0x37, 0x6c, 0xc2, 0xb0, 0xed, 0x80, 0xda, 0x46, 0xe4, 0xf8, 0x1e, 0x33, 0xf9, 0x5b, 0xa6, 0x37,
+	0xba, 0xa1, 0x5e, 0x14, 0x18, 0x5e, 0x78, 0xe1, 0x07, 0xee, 0x5c, 0x32, 0x5f, 0x48, 0x55, 0xe5,
+	0xd7, 0xb0, 0xdb, 0x59, 0x3e, 0xa5, 0x33, 0xdc, 0x82, 0x42, 0x74, 0xc5, 0xa8, 0x84, 0x1a, 0xa8,
+	0x59, 0x69, 0xd5, 0x95, 0x5c, 0x5b, 0x0a, 0x87, 0x1d, 0x5d, 0x31, 0x4a, 0x12, 0xac, 0x3c, 0x84,
+	0xda, 0x28, 0x27, 0xae, 0x33, 0xfc, 0x3c, 0xa7, 0xf3, 0x48, 0x59, 0x6d, 0x47, 0xc9, 0x33, 0x38,
+	0xb5, 0x2f, 0x08, 0x4a, 0xc4, 0x9f, 0x4c, 0xa6, 0x4c, 0x67, 0x78, 0x1f, 0x4a, 0x1e, 0xbd, 0x3c,
+	0xf7, 0x0c, 0x37, 0x95, 0xda, 0x21, 0xdb, 0x1e, 0xbd, 0xd4, 0x0c, 0x97, 0x62, 0x0c, 0x85, 0xc8,
+	0xb0, 0x43, 0x49, 0x68, 0x88, 0xcd, 0x1d, 0x92, 0x9c, 0xf1, 0x00, 0xf6, 0xb8, 0x86, 0xcf, 0x63,
+	0xbd, 0x50, 0x12, 0x1b, 0xe2, 0x7f, 0x8c, 0x52, 0x33, 0xf2, 0x85, 0x50, 0xfe, 0x26, 0x00, 0x9c,
+	0x66, 0xf9, 0xeb, 0x0c, 0xab, 0xb9, 0x89, 0x0e, 0x95, 0xe5, 0x6a, 0x28, 0x4b, 0x94, 0xb2, 0x1c,
+	0x04, 0xbf, 0x82, 0x32, 0xa7, 0x29, 0x09, 0x0d, 0xd4, 0x2c, 0xb7, 0xf6, 0x79, 0x5e, 0xce, 0x7a,
+	0xc2, 0xa3, 0x71, 0x17, 0x2a, 0x79, 0xcb, 0x24, 0x31, 0xe1, 0x1f, 0xf1, 0xfc, 0x55, 0xd7, 0xc9,
+	0x0a, 0x07, 0x3f, 0x86, 0x62, 0x90, 0x58, 0x29, 0x15, 0x12, 0xf6, 0x5d, 0x9e, 0x3d, 0x37, 0x99,
+	0x64, 0x18, 0xb9, 0x0b, 0x85, 0xb8, 0x7d, 0x5c, 0x86, 0xed, 0x33, 0x6d, 0xa0, 0xe9, 0x1f, 0xb4,
+	0xda, 0x16, 0xae, 0x42, 0xb9, 0xd3, 0xeb, 0x91, 0x37, 0xbd, 0xce, 0xa8, 0xaf, 0x6b, 0x35, 0x84,
+	0x31, 0x54, 0x46, 0xa4, 0xa3, 0xbd, 0x7f, 0xab, 0x93, 0x77, 0x69, 0x4d, 0xc0, 0x00, 0x45, 0xa2,
+	0x0f, 0x87, 0x67, 0xa7, 0x35, 0x51, 0x7e, 0x09, 0xa5, 0xb9, 0x1f, 0x58, 0x01, 0xd1, 0x67, 0xa1,
+	0x84, 0x1a, 0x62, 0xb3, 0xdc, 0xba, 0xb7, 0xd9, 0xb2, 0xe3, 0xc2, 0xf5, 0x8f, 0x87, 0x5b, 0x24,
+	0x06, 0xca, 0x9f, 0x11, 0x54, 0x3b, 0x8c, 0x4d, 0x1c, 0x6a, 0x2d, 0x56, 0xa0, 0x02, 0x82, 0x63,
+	0x25, 0xae, 0xdf, 0x21, 0x82, 0x63, 0xe1, 0x3e, 0x54, 0xf8, 0x8c, 0x1d, 0x2b, 0x73, 0xf6, 0xe8,
+	0xef, 0x01, 0xf7, 0xbb, 0xd9, 0x23, 0xbb, 0x1c, 0xa4, 0x6f, 0xe1, 0xfb, 0xb0, 0x1d, 0x4c, 0x27,
+	0x34, 0xd6, 0x10, 0x13, 0xfd, 0x62, 0x7c, 0xed, 0x5b, 0xf2, 0x6f, 0x04, 0x7b, 0x59, 0x1f, 0xdc,
+	0x06, 0x3c, 0xcb, 0x6d, 0x80, 0x9c, 0x4b, 0x72, 0x15, 0xcc, 0x2f, 0xc2, 0x7a, 0x96, 0xc2, 0x2d,
+	0xb2, 0x6c, 0x2f, 0xb2, 0x4c, 0x37, 0xe1, 0x70, 0xc3, 0xfb, 0x6b, 0x91, 0xb6, 0x37, 0x45, 0xba,
+	0x9e, 0x20, 0xe2, 0x12, 0x14, 0xe4, 0x13, 0xa8, 0xae, 0xcc, 0x83, 0x9f, 0xf2, 0x41, 0x3e, 0xf8,
+	0xe7, 0xe4, 0x5c, 0x9e, 0xc7, 0x83, 0xeb, 0x59, 0x1d, 0x7d, 0x9f, 0xd5, 0xd1, 0xcf, 0x59, 0x1d,
+	0x7d, 0xfd, 0x55, 0xdf, 0xfa, 0xf8, 0xe2, 0xd6, 0xff, 0x66, 0xb3, 0x98, 0x54, 0xda, 0x7f, 0x06,
+	0x00, 0x1e, 0x47, 0x63, 0xe9, 0xdf, 0x05, 0x00, 0x00,
 }
@@ -120,6 +120,9 @@ func (m *Retention) GetPeriod() int64 {
 type StoragePolicy struct {
 	Resolution *Resolution `protobuf:"bytes,1,opt,name=resolution" json:"resolution,omitempty"`
 	Retention  *Retention  `protobuf:"bytes,2,opt,name=retention" json:"retention,omitempty"`
+	// sliding_window is the size of the sliding window in nanoseconds aggregated
+	// at every resolution step, a zero value means tumbling windows.
+	SlidingWindow int64 `protobuf:"varint,3,opt,name=sliding_window,json=slidingWindow,proto3" json:"sliding_window,omitempty"`
 }
 
 func (m *StoragePolicy) Reset()                    { *m = StoragePolicy{} }
@@ -141,6 +144,13 @@ func (m *StoragePolicy) GetRetention() *Retention {
 	return nil
 }
 
+func (m *StoragePolicy) GetSlidingWindow() int64 {
+	if m != nil {
+		return m.SlidingWindow
+	}
+	return 0
+}
+
 type Policy struct {
 	StoragePolicy    *StoragePolicy                  `protobuf:"bytes,1,opt,name=storage_policy,json=storagePolicy" json:"storage_policy,omitempty"`
 	AggregationTypes []aggregationpb.AggregationType `protobuf:"varint,2,rep,packed,name=aggregation_types,json=aggregationTypes,enum=aggregationpb.AggregationType" json:"aggregation_types,omitempty"`
@@ -258,6 +268,11 @@ func (m *StoragePolicy) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if m.SlidingWindow != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintPolicy(dAtA, i, uint64(m.SlidingWindow))
+	}
 	return i, nil
 }
 
@@ -347,6 +362,9 @@ func (m *StoragePolicy) Size() (n int) {
 		l = m.Retention.Size()
 		n += 1 + l + sovPolicy(uint64(l))
 	}
+	if m.SlidingWindow != 0 {
+		n += 1 + sovPolicy(uint64(m.SlidingWindow))
+	}
 	return n
 }
 
@@ -632,6 +650,25 @@ func (m *StoragePolicy) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlidingWindow", wireType)
+			}
+			m.SlidingWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SlidingWindow |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPolicy(dAtA[iNdEx:])
@@ -908,30 +945,31 @@ func init() {
 }
 
 var fileDescriptorPolicy = []byte{
-	// 390 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0xcd, 0xce, 0xd2, 0x40,
-	0x14, 0xfd, 0xca, 0x67, 0xc8, 0xd7, 0x4b, 0x20, 0x75, 0x34, 0x48, 0x8c, 0xa9, 0xa4, 0x6e, 0x88,
-	0x8b, 0x36, 0x82, 0x89, 0x2b, 0x4d, 0x50, 0x34, 0x12, 0xa4, 0x90, 0x01, 0x17, 0xba, 0x69, 0xfa,
-	0x33, 0xa9, 0x93, 0xd0, 0xce, 0x64, 0x66, 0x08, 0xc2, 0x53, 0xb8, 0xf1, 0x9d, 0x5c, 0xfa, 0x08,
-	0x06, 0x5f, 0xc4, 0x38, 0x2d, 0xb6, 0x5d, 0xfa, 0xed, 0xe6, 0x9c, 0x7b, 0xef, 0x39, 0xe7, 0xde,
-	0x0c, 0xcc, 0x52, 0xaa, 0xbe, 0xec, 0x23, 0x37, 0x66, 0x99, 0x97, 0x4d, 0x92, 0xc8, 0xcb, 0x26,
-	0x9e, 0x14, 0xb1, 0x97, 0x11, 0x25, 0x68, 0x2c, 0xbd, 0x94, 0xe4, 0x44, 0x84, 0x8a, 0x24, 0x1e,
-	0x17, 0x4c, 0x31, 0x8f, 0xb3, 0x1d, 0x8d, 0x8f, 0x3c, 0x2a, 0x1f, 0xae, 0x66, 0xd1, 0xcd, 0x85,
-	0x7e, 0xe8, 0xff, 0xa7, 0x5e, 0x98, 0xa6, 0x82, 0xa4, 0xa1, 0xa2, 0x2c, 0xe7, 0x51, 0x1d, 0x15,
-	0xca, 0xce, 0x02, 0x00, 0x13, 0xc9, 0x76, 0xfb, 0xbf, 0x1c, 0x7a, 0x0c, 0x9d, 0x03, 0xcd, 0x13,
-	0x76, 0x08, 0x24, 0x3d, 0x91, 0x81, 0x31, 0x34, 0x46, 0xd7, 0x18, 0x0a, 0x6a, 0x43, 0x4f, 0x04,
-	0x3d, 0x02, 0x93, 0x0b, 0x12, 0x53, 0x49, 0x59, 0x3e, 0x68, 0xe9, 0x72, 0x45, 0x38, 0x4f, 0xc0,
-	0xc4, 0x44, 0x91, 0x5c, 0x6b, 0xf5, 0xa1, 0xcd, 0x89, 0xa0, 0x2c, 0x29, 0x65, 0x4a, 0xe4, 0x7c,
-	0x85, 0xee, 0x46, 0x31, 0x11, 0xa6, 0x64, 0xad, 0x97, 0x42, 0xcf, 0x01, 0xc4, 0xbf, 0x08, 0xba,
-	0xb9, 0x33, 0xbe, 0xef, 0x5e, 0x36, 0x76, 0xab, 0x78, 0xb8, 0xd6, 0x87, 0x9e, 0x81, 0x29, 0x2e,
-	0x5e, 0x3a, 0x49, 0x67, 0x7c, 0xaf, 0x3e, 0x54, 0x96, 0x70, 0xd5, 0xe5, 0x7c, 0x37, 0xa0, 0x5d,
-	0x7a, 0xbe, 0x82, 0x9e, 0x2c, 0x42, 0x04, 0xc5, 0x4c, 0xe9, 0xfb, 0xa0, 0x92, 0x68, 0x84, 0xc4,
-	0x5d, 0xd9, 0xc8, 0xbc, 0x80, 0xbb, 0xb5, 0x5b, 0x06, 0xea, 0xc8, 0x89, 0x1c, 0xb4, 0x86, 0xd7,
-	0xa3, 0xde, 0xd8, 0x76, 0x1b, 0x37, 0x77, 0xa7, 0x15, 0xda, 0x1e, 0x39, 0xc1, 0x56, 0xd8, 0x24,
-	0xe4, 0xd3, 0x97, 0x00, 0x33, 0xc1, 0x78, 0x29, 0x7d, 0x03, 0x77, 0xfc, 0x95, 0xff, 0xd6, 0xba,
-	0x42, 0x5d, 0x30, 0x67, 0x78, 0xb5, 0x0e, 0x96, 0x1f, 0x37, 0x5b, 0xcb, 0x40, 0x7d, 0x40, 0x1a,
-	0xce, 0xdf, 0x05, 0x2b, 0xff, 0xc3, 0xa7, 0x60, 0x39, 0xdd, 0xbe, 0x79, 0x6f, 0xb5, 0x5e, 0xcf,
-	0x7f, 0x9c, 0x6d, 0xe3, 0xe7, 0xd9, 0x36, 0x7e, 0x9d, 0x6d, 0xe3, 0xdb, 0x6f, 0xfb, 0xea, 0xf3,
-	0x8b, 0x5b, 0x7e, 0xba, 0xa8, 0xad, 0xf1, 0xe4, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4d, 0x58,
-	0xd9, 0xc4, 0xb6, 0x02, 0x00, 0x00,
+	// 410 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x4f, 0x8b, 0xd3, 0x40,
+	0x18, 0xc6, 0x77, 0x5a, 0x29, 0xdb, 0xb7, 0xb4, 0xc4, 0x51, 0xd6, 0x20, 0x12, 0x97, 0x88, 0xb0,
+	0x78, 0x48, 0xb0, 0x15, 0x3c, 0x29, 0xac, 0x56, 0x71, 0x59, 0x37, 0x5d, 0xa6, 0x15, 0xd1, 0x4b,
+	0xc8, 0x9f, 0x21, 0x0e, 0x34, 0x99, 0x61, 0x66, 0x96, 0xa5, 0xfd, 0x14, 0x5e, 0x3c, 0xfb, 0x75,
+	0x3c, 0xfa, 0x11, 0xa4, 0x7e, 0x11, 0x71, 0x32, 0x35, 0xc9, 0xd1, 0xbd, 0xe5, 0xf9, 0xcd, 0x9b,
+	0xe7, 0x7d, 0x9e, 0x64, 0x60, 0x5e, 0x30, 0xfd, 0xe5, 0x2a, 0x0d, 0x32, 0x5e, 0x86, 0xe5, 0x2c,
+	0x4f, 0xc3, 0x72, 0x16, 0x2a, 0x99, 0x85, 0x25, 0xd5, 0x92, 0x65, 0x2a, 0x2c, 0x68, 0x45, 0x65,
+	0xa2, 0x69, 0x1e, 0x0a, 0xc9, 0x35, 0x0f, 0x05, 0x5f, 0xb3, 0x6c, 0x23, 0x52, 0xfb, 0x10, 0x18,
+	0x8a, 0x0f, 0xf7, 0xf8, 0x7e, 0xf4, 0x9f, 0x7e, 0x49, 0x51, 0x48, 0x5a, 0x24, 0x9a, 0xf1, 0x4a,
+	0xa4, 0x6d, 0x55, 0x3b, 0xfb, 0xe7, 0x00, 0x84, 0x2a, 0xbe, 0xbe, 0xfa, 0xcb, 0xf0, 0x43, 0x18,
+	0x5d, 0xb3, 0x2a, 0xe7, 0xd7, 0xb1, 0x62, 0x5b, 0xea, 0xa2, 0x63, 0x74, 0xd2, 0x27, 0x50, 0xa3,
+	0x25, 0xdb, 0x52, 0xfc, 0x00, 0x86, 0x42, 0xd2, 0x8c, 0x29, 0xc6, 0x2b, 0xb7, 0x67, 0x8e, 0x1b,
+	0xe0, 0x3f, 0x82, 0x21, 0xa1, 0x9a, 0x56, 0xc6, 0xeb, 0x08, 0x06, 0x82, 0x4a, 0xc6, 0x73, 0x6b,
+	0x63, 0x95, 0xff, 0x1d, 0xc1, 0x78, 0xa9, 0xb9, 0x4c, 0x0a, 0x7a, 0x69, 0x5a, 0xe1, 0x67, 0x00,
+	0xf2, 0x5f, 0x06, 0x33, 0x3d, 0x9a, 0xde, 0x0d, 0xf6, 0x95, 0x83, 0x26, 0x1f, 0x69, 0xcd, 0xe1,
+	0xa7, 0x30, 0x94, 0xfb, 0x65, 0x26, 0xca, 0x68, 0x7a, 0xa7, 0xfd, 0x92, 0x3d, 0x22, 0xcd, 0x14,
+	0x7e, 0x0c, 0x13, 0xb5, 0x66, 0x39, 0xab, 0x8a, 0xb8, 0xee, 0xe4, 0xf6, 0x4d, 0xb4, 0xb1, 0xa5,
+	0x1f, 0x0d, 0xf4, 0xbf, 0x21, 0x18, 0xd8, 0x68, 0x2f, 0x61, 0xa2, 0xea, 0xac, 0x71, 0x6d, 0x6d,
+	0xe3, 0xdd, 0x6b, 0x36, 0x75, 0xba, 0x90, 0xb1, 0xea, 0x54, 0x3b, 0x87, 0xdb, 0xad, 0x6f, 0x1e,
+	0xeb, 0x8d, 0xa0, 0xca, 0xed, 0x1d, 0xf7, 0x4f, 0x26, 0x53, 0x2f, 0xe8, 0xfc, 0x9b, 0xe0, 0xb4,
+	0x51, 0xab, 0x8d, 0xa0, 0xc4, 0x49, 0xba, 0x40, 0x3d, 0x79, 0x01, 0x30, 0x97, 0x5c, 0x58, 0xeb,
+	0x43, 0xb8, 0x15, 0x2d, 0xa2, 0x37, 0xce, 0x01, 0x1e, 0xc3, 0x70, 0x4e, 0x16, 0x97, 0xf1, 0xc5,
+	0x87, 0xe5, 0xca, 0x41, 0xf8, 0x08, 0xb0, 0x91, 0x67, 0x6f, 0xe3, 0x45, 0xf4, 0xfe, 0x53, 0x7c,
+	0x71, 0xba, 0x7a, 0xfd, 0xce, 0xe9, 0xbd, 0x3a, 0xfb, 0xb1, 0xf3, 0xd0, 0xcf, 0x9d, 0x87, 0x7e,
+	0xed, 0x3c, 0xf4, 0xf5, 0xb7, 0x77, 0xf0, 0xf9, 0xf9, 0x0d, 0x2f, 0x67, 0x3a, 0x30, 0x7a, 0xf6,
+	0x67, 0x00, 0x95, 0x0a, 0x9f, 0x98, 0xde, 0x02, 0x00, 0x00,
 }
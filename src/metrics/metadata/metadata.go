@@ -311,6 +311,9 @@ type ForwardMetadata struct {
 
 	// Number of times this metric has been forwarded.
 	NumForwardedTimes int
+
+	// ID of the rollup rule that produced this metric, if any.
+	RollupRuleID []byte
 }
 
 // ToProto converts the forward metadata to a protobuf message in place.
@@ -326,6 +329,7 @@ func (m ForwardMetadata) ToProto(pb *metricpb.ForwardMetadata) error {
 	}
 	pb.SourceId = m.SourceID
 	pb.NumForwardedTimes = int32(m.NumForwardedTimes)
+	pb.RollupRuleId = m.RollupRuleID
 	return nil
 }
 
@@ -342,6 +346,7 @@ func (m *ForwardMetadata) FromProto(pb metricpb.ForwardMetadata) error {
 	}
 	m.SourceID = pb.SourceId
 	m.NumForwardedTimes = int(pb.NumForwardedTimes)
+	m.RollupRuleID = pb.RollupRuleId
 	return nil
 }
 
@@ -77,6 +77,7 @@ var (
 		}),
 		SourceID:          897,
 		NumForwardedTimes: 2,
+		RollupRuleID:      []byte("rule"),
 	}
 	testSmallPipelineMetadata = PipelineMetadata{
 		AggregationID: aggregation.DefaultID,
@@ -291,6 +292,7 @@ var (
 		},
 		SourceId:          897,
 		NumForwardedTimes: 2,
+		RollupRuleId:      []byte("rule"),
 	}
 	testBadForwardMetadataProto    = metricpb.ForwardMetadata{}
 	testSmallPipelineMetadataProto = metricpb.PipelineMetadata{
@@ -146,6 +146,9 @@ type ForwardedMetric struct {
 	ID        id.RawID
 	TimeNanos int64
 	Values    []float64
+	// CountDistinctSketch holds the registers of the count distinct sketch
+	// forwarded alongside the values, if any.
+	CountDistinctSketch []byte
 }
 
 // ToProto converts the forwarded metric to a protobuf message in place.
@@ -156,6 +159,7 @@ func (m ForwardedMetric) ToProto(pb *metricpb.ForwardedMetric) error {
 	pb.Id = m.ID
 	pb.TimeNanos = m.TimeNanos
 	pb.Values = m.Values
+	pb.CountDistinctSketch = m.CountDistinctSketch
 	return nil
 }
 
@@ -167,6 +171,7 @@ func (m *ForwardedMetric) FromProto(pb metricpb.ForwardedMetric) error {
 	m.ID = pb.Id
 	m.TimeNanos = pb.TimeNanos
 	m.Values = pb.Values
+	m.CountDistinctSketch = pb.CountDistinctSketch
 	return nil
 }
 
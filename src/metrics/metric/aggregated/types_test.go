@@ -68,10 +68,11 @@ var (
 		Values:    []float64{1, 289},
 	}
 	testForwardedMetric2 = ForwardedMetric{
-		Type:      metric.GaugeType,
-		ID:        []byte("testForwardedMetric2"),
-		TimeNanos: 67890,
-		Values:    []float64{1.34, -26.57},
+		Type:                metric.GaugeType,
+		ID:                  []byte("testForwardedMetric2"),
+		TimeNanos:           67890,
+		Values:              []float64{1.34, -26.57},
+		CountDistinctSketch: []byte{0, 3, 1, 2},
 	}
 	testBadForwardedMetric = ForwardedMetric{
 		Type: 999,
@@ -134,10 +135,11 @@ var (
 		Values:    []float64{1, 289},
 	}
 	testForwardedMetric2Proto = metricpb.ForwardedMetric{
-		Type:      metricpb.MetricType_GAUGE,
-		Id:        []byte("testForwardedMetric2"),
-		TimeNanos: 67890,
-		Values:    []float64{1.34, -26.57},
+		Type:                metricpb.MetricType_GAUGE,
+		Id:                  []byte("testForwardedMetric2"),
+		TimeNanos:           67890,
+		Values:              []float64{1.34, -26.57},
+		CountDistinctSketch: []byte{0, 3, 1, 2},
 	}
 	testForwardMetadata1Proto = metricpb.ForwardMetadata{
 		AggregationId: aggregationpb.AggregationID{Id: 0},
@@ -57,10 +57,16 @@ var (
 // NewRollupID generates a new rollup id given the new metric name
 // and a list of tag pairs. Note that tagPairs are mutated in place.
 func NewRollupID(name []byte, tagPairs []id.TagPair) []byte {
-	var buf bytes.Buffer
-
 	// Adding rollup tag pair to the list of tag pairs.
 	tagPairs = append(tagPairs, rollupTagPair)
+	return NewMetricID(name, tagPairs)
+}
+
+// NewMetricID generates a new metric id given the metric name and a list
+// of tag pairs. Note that tagPairs are sorted in place.
+func NewMetricID(name []byte, tagPairs []id.TagPair) []byte {
+	var buf bytes.Buffer
+
 	sort.Sort(id.TagPairsByNameAsc(tagPairs))
 
 	buf.Write(m3Prefix)
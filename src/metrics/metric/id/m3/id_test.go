@@ -42,6 +42,18 @@ func TestNewRollupID(t *testing.T) {
 	require.Equal(t, expected, NewRollupID(name, tagPairs))
 }
 
+func TestNewMetricID(t *testing.T) {
+	var (
+		name     = []byte("foo")
+		tagPairs = []id.TagPair{
+			{Name: []byte("tagName1"), Value: []byte("tagValue1")},
+			{Name: []byte("tagName0"), Value: []byte("tagValue0")},
+		}
+	)
+	expected := []byte("m3+foo+tagName0=tagValue0,tagName1=tagValue1")
+	require.Equal(t, expected, NewMetricID(name, tagPairs))
+}
+
 func TestIsRollupIDNilIterator(t *testing.T) {
 	inputs := []struct {
 		name     []byte
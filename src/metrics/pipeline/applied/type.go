@@ -43,18 +43,27 @@ type RollupOp struct {
 	ID []byte
 	// Type of aggregations performed within each unique dimension combination.
 	AggregationID aggregation.ID
+	// ID of the rollup rule the operation belongs to.
+	RuleID []byte
 }
 
 // Equal determines whether two rollup operations are equal.
 func (op RollupOp) Equal(other RollupOp) bool {
-	return op.AggregationID == other.AggregationID && bytes.Equal(op.ID, other.ID)
+	return op.AggregationID == other.AggregationID &&
+		bytes.Equal(op.ID, other.ID) &&
+		bytes.Equal(op.RuleID, other.RuleID)
 }
 
 // Clone clones the rollup operation.
 func (op RollupOp) Clone() RollupOp {
 	idClone := make([]byte, len(op.ID))
 	copy(idClone, op.ID)
-	return RollupOp{ID: idClone, AggregationID: op.AggregationID}
+	var ruleIDClone []byte
+	if op.RuleID != nil {
+		ruleIDClone = make([]byte, len(op.RuleID))
+		copy(ruleIDClone, op.RuleID)
+	}
+	return RollupOp{ID: idClone, AggregationID: op.AggregationID, RuleID: ruleIDClone}
 }
 
 func (op RollupOp) String() string {
@@ -67,6 +76,7 @@ func (op RollupOp) ToProto(pb *pipelinepb.AppliedRollupOp) error {
 		return err
 	}
 	pb.Id = op.ID
+	pb.RuleId = op.RuleID
 	return nil
 }
 
@@ -79,6 +89,7 @@ func (op *RollupOp) FromProto(pb *pipelinepb.AppliedRollupOp) error {
 		return err
 	}
 	op.ID = pb.Id
+	op.RuleID = pb.RuleId
 	return nil
 }
 
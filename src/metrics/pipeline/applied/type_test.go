@@ -55,6 +55,7 @@ var (
 			Rollup: RollupOp{
 				ID:            []byte("bar"),
 				AggregationID: aggregation.MustCompressTypes(aggregation.Last, aggregation.Sum),
+				RuleID:        []byte("rule"),
 			},
 		},
 		{
@@ -106,6 +107,7 @@ var (
 				Rollup: &pipelinepb.AppliedRollupOp{
 					Id:            []byte("bar"),
 					AggregationId: aggregationpb.AggregationID{Id: aggregation.MustCompressTypes(aggregation.Last, aggregation.Sum)[0]},
+					RuleId:        []byte("rule"),
 				},
 			},
 			{
@@ -138,6 +140,19 @@ var (
 	}
 )
 
+func TestRollupOpEqualRuleID(t *testing.T) {
+	op := RollupOp{
+		ID:            []byte("foo"),
+		AggregationID: aggregation.DefaultID,
+		RuleID:        []byte("rule1"),
+	}
+	require.True(t, op.Equal(op.Clone()))
+
+	other := op.Clone()
+	other.RuleID = []byte("rule2")
+	require.False(t, op.Equal(other))
+}
+
 func TestPipelineIsEmpty(t *testing.T) {
 	inputs := []struct {
 		p        Pipeline
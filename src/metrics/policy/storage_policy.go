@@ -33,6 +33,7 @@ import (
 
 const (
 	resolutionRetentionSeparator = ":"
+	slidingWindowSeparator       = "~"
 )
 
 var (
@@ -41,13 +42,17 @@ var (
 
 	errNilStoragePolicyProto      = errors.New("nil storage policy proto")
 	errInvalidStoragePolicyString = errors.New("invalid storage policy string")
+	errInvalidSlidingWindow       = errors.New("sliding window must be a multiple of the resolution window larger than the resolution window")
 )
 
 // StoragePolicy represents the resolution and retention period metric datapoints
-// are stored at.
+// are stored at. A storage policy may optionally have a sliding window, in which
+// case a datapoint aggregating the values received during the sliding window is
+// produced at every resolution step instead of one per resolution window.
 type StoragePolicy struct {
-	resolution Resolution
-	retention  Retention
+	resolution    Resolution
+	retention     Retention
+	slidingWindow time.Duration
 }
 
 // NewStoragePolicy creates a new storage policy given a resolution and a retention.
@@ -61,6 +66,19 @@ func NewStoragePolicy(window time.Duration, precision xtime.Unit, retention time
 	}
 }
 
+// NewSlidingWindowStoragePolicy creates a new storage policy producing a datapoint
+// aggregating the values received during the sliding window at every step.
+func NewSlidingWindowStoragePolicy(
+	step time.Duration,
+	precision xtime.Unit,
+	retention time.Duration,
+	slidingWindow time.Duration,
+) StoragePolicy {
+	sp := NewStoragePolicy(step, precision, retention)
+	sp.slidingWindow = slidingWindow
+	return sp
+}
+
 // NewStoragePolicyFromProto creates a new storage policy from a storage policy protobuf message.
 func NewStoragePolicyFromProto(pb *policypb.StoragePolicy) (StoragePolicy, error) {
 	if pb == nil {
@@ -75,6 +93,10 @@ func NewStoragePolicyFromProto(pb *policypb.StoragePolicy) (StoragePolicy, error
 
 // String is the string representation of a storage policy.
 func (p StoragePolicy) String() string {
+	if p.slidingWindow > 0 {
+		return fmt.Sprintf("%s%s%s%s%s", p.resolution.String(), slidingWindowSeparator,
+			xtime.ToExtendedString(p.slidingWindow), resolutionRetentionSeparator, p.retention.String())
+	}
 	return fmt.Sprintf("%s%s%s", p.resolution.String(), resolutionRetentionSeparator, p.retention.String())
 }
 
@@ -88,6 +110,37 @@ func (p StoragePolicy) Retention() Retention {
 	return p.retention
 }
 
+// SlidingWindow returns the size of the sliding window aggregated at every
+// resolution step, or zero if the storage policy uses tumbling windows.
+func (p StoragePolicy) SlidingWindow() time.Duration {
+	return p.slidingWindow
+}
+
+// IsSlidingWindow returns whether the storage policy has a sliding window.
+func (p StoragePolicy) IsSlidingWindow() bool {
+	return p.slidingWindow > 0
+}
+
+// NumSlidingWindowSteps returns the number of resolution steps covered by the
+// sliding window, or one if the storage policy uses tumbling windows.
+func (p StoragePolicy) NumSlidingWindowSteps() int {
+	if p.slidingWindow <= 0 || p.resolution.Window <= 0 {
+		return 1
+	}
+	return int(p.slidingWindow / p.resolution.Window)
+}
+
+func (p StoragePolicy) validateSlidingWindow() error {
+	if p.slidingWindow == 0 {
+		return nil
+	}
+	window := p.resolution.Window
+	if window <= 0 || p.slidingWindow <= window || p.slidingWindow%window != 0 {
+		return errInvalidSlidingWindow
+	}
+	return nil
+}
+
 // Proto returns the proto message for the storage policy.
 func (p StoragePolicy) Proto() (*policypb.StoragePolicy, error) {
 	var pb policypb.StoragePolicy
@@ -109,6 +162,7 @@ func (p StoragePolicy) ToProto(pb *policypb.StoragePolicy) error {
 		pb.Retention = &policypb.Retention{}
 	}
 	p.retention.ToProto(pb.Retention)
+	pb.SlidingWindow = p.slidingWindow.Nanoseconds()
 	return nil
 }
 
@@ -120,7 +174,8 @@ func (p *StoragePolicy) FromProto(pb policypb.StoragePolicy) error {
 	if err := p.retention.FromProto(pb.Retention); err != nil {
 		return err
 	}
-	return nil
+	p.slidingWindow = time.Duration(pb.SlidingWindow)
+	return p.validateSlidingWindow()
 }
 
 // MarshalText returns the text encoding of a storage policy.
@@ -139,13 +194,25 @@ func (p *StoragePolicy) UnmarshalText(data []byte) error {
 	return nil
 }
 
-// ParseStoragePolicy parses a storage policy in the form of resolution:retention.
+// ParseStoragePolicy parses a storage policy in the form of resolution:retention,
+// or resolution~window:retention for a storage policy with a sliding window.
 func ParseStoragePolicy(str string) (StoragePolicy, error) {
 	parts := strings.Split(str, resolutionRetentionSeparator)
 	if len(parts) != 2 {
 		return EmptyStoragePolicy, errInvalidStoragePolicyString
 	}
-	resolution, err := ParseResolution(parts[0])
+	var (
+		resolutionStr = parts[0]
+		slidingWindow time.Duration
+	)
+	if idx := strings.Index(resolutionStr, slidingWindowSeparator); idx != -1 {
+		window, err := xtime.ParseExtendedDuration(resolutionStr[idx+1:])
+		if err != nil {
+			return EmptyStoragePolicy, err
+		}
+		resolutionStr, slidingWindow = resolutionStr[:idx], window
+	}
+	resolution, err := ParseResolution(resolutionStr)
 	if err != nil {
 		return EmptyStoragePolicy, err
 	}
@@ -153,7 +220,11 @@ func ParseStoragePolicy(str string) (StoragePolicy, error) {
 	if err != nil {
 		return EmptyStoragePolicy, err
 	}
-	return StoragePolicy{resolution: resolution, retention: retention}, nil
+	sp := StoragePolicy{resolution: resolution, retention: retention, slidingWindow: slidingWindow}
+	if err := sp.validateSlidingWindow(); err != nil {
+		return EmptyStoragePolicy, err
+	}
+	return sp, nil
 }
 
 // MustParseStoragePolicy parses a storage policy in the form of resolution:retention,
@@ -242,7 +313,11 @@ func (sp ByResolutionAscRetentionDesc) Less(i, j int) bool {
 	if rt1 != rt2 {
 		return rt1 > rt2
 	}
-	return sp[i].Resolution().Precision < sp[j].Resolution().Precision
+	p1, p2 := sp[i].Resolution().Precision, sp[j].Resolution().Precision
+	if p1 != p2 {
+		return p1 < p2
+	}
+	return sp[i].SlidingWindow() < sp[j].SlidingWindow()
 }
 
 // ByRetentionAscResolutionAsc implements the sort.Sort interface that enables sorting
@@ -261,5 +336,9 @@ func (sp ByRetentionAscResolutionAsc) Less(i, j int) bool {
 	if rw1 != rw2 {
 		return rw1 < rw2
 	}
-	return sp[i].Resolution().Precision < sp[j].Resolution().Precision
+	p1, p2 := sp[i].Resolution().Precision, sp[j].Resolution().Precision
+	if p1 != p2 {
+		return p1 < p2
+	}
+	return sp[i].SlidingWindow() < sp[j].SlidingWindow()
 }
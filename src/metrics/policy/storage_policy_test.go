@@ -76,6 +76,8 @@ func TestStoragePolicyString(t *testing.T) {
 		{p: NewStoragePolicy(10*time.Second, xtime.Second, time.Hour), expected: "10s:1h"},
 		{p: NewStoragePolicy(time.Minute, xtime.Minute, 12*time.Hour), expected: "1m:12h"},
 		{p: NewStoragePolicy(time.Minute, xtime.Second, 12*time.Hour), expected: "1m@1s:12h"},
+		{p: NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 12*time.Hour, 5*time.Minute), expected: "10s~5m:12h"},
+		{p: NewSlidingWindowStoragePolicy(time.Minute, xtime.Second, 12*time.Hour, time.Hour), expected: "1m@1s~1h:12h"},
 	}
 	for _, input := range inputs {
 		require.Equal(t, input.expected, input.p.String())
@@ -127,6 +129,14 @@ func TestParseStoragePolicy(t *testing.T) {
 			str:      "1h:24h",
 			expected: NewStoragePolicy(time.Hour, xtime.Hour, 24*time.Hour),
 		},
+		{
+			str:      "10s~5m:2d",
+			expected: NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour, 5*time.Minute),
+		},
+		{
+			str:      "10s@1s~300s:2d",
+			expected: NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour, 5*time.Minute),
+		},
 	}
 	for _, input := range inputs {
 		res, err := ParseStoragePolicy(input.str)
@@ -145,6 +155,8 @@ func TestStoragePolicyParseRoundTrip(t *testing.T) {
 		NewStoragePolicy(10*time.Second, xtime.Second, 24*time.Hour),
 		NewStoragePolicy(time.Minute, xtime.Second, 24*time.Hour),
 		NewStoragePolicy(time.Minute, xtime.Minute, 24*time.Hour),
+		NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, 24*time.Hour, 5*time.Minute),
+		NewSlidingWindowStoragePolicy(time.Minute, xtime.Second, 24*time.Hour, time.Hour),
 	}
 
 	for _, input := range inputs {
@@ -163,6 +175,10 @@ func TestParseStoragePolicyErrors(t *testing.T) {
 		"10s@2s:1d",
 		"0.1s@1s:1d",
 		"10s@2minutes:2d",
+		"10s~5minutes:2d",
+		"10s~10s:2d",
+		"10s~15s:2d",
+		"10s~5m~10m:2d",
 	}
 	for _, input := range inputs {
 		_, err := ParseStoragePolicy(input)
@@ -480,6 +496,30 @@ func TestStoragePolicyProtoRoundTrip(t *testing.T) {
 	require.Equal(t, testStoragePolicy, res)
 }
 
+func TestSlidingWindowStoragePolicyProtoRoundTrip(t *testing.T) {
+	var (
+		pb  policypb.StoragePolicy
+		res StoragePolicy
+		sp  = NewSlidingWindowStoragePolicy(10*time.Second, xtime.Second, time.Hour, 5*time.Minute)
+	)
+	require.NoError(t, sp.ToProto(&pb))
+	require.Equal(t, (5 * time.Minute).Nanoseconds(), pb.SlidingWindow)
+	require.NoError(t, res.FromProto(pb))
+	require.Equal(t, sp, res)
+	require.True(t, res.IsSlidingWindow())
+	require.Equal(t, 30, res.NumSlidingWindowSteps())
+
+	// Reusing the proto message for a tumbling storage policy clears the window.
+	require.NoError(t, testStoragePolicy.ToProto(&pb))
+	require.NoError(t, res.FromProto(pb))
+	require.Equal(t, testStoragePolicy, res)
+	require.False(t, res.IsSlidingWindow())
+	require.Equal(t, 1, res.NumSlidingWindowSteps())
+
+	pb.SlidingWindow = (15 * time.Second).Nanoseconds()
+	require.Equal(t, errInvalidSlidingWindow, res.FromProto(pb))
+}
+
 func TestStoragePoliciesJSONMarshal(t *testing.T) {
 	input := StoragePolicies{
 		NewStoragePolicy(time.Second, xtime.Second, time.Hour),
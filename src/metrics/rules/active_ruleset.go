@@ -255,7 +255,7 @@ func (as *activeRuleSet) mappingsForNonRollupID(
 func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResults {
 	var (
 		cutoverNanos  int64
-		rollupTargets []rollupTarget
+		rollupTargets []ruleRollupTarget
 	)
 	for _, rollupRule := range as.rollupRules {
 		snapshot := rollupRule.activeSnapshot(timeNanos)
@@ -275,8 +275,12 @@ func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResu
 		if snapshot.tombstoned {
 			continue
 		}
+		ruleID := []byte(rollupRule.uuid)
 		for _, target := range snapshot.targets {
-			rollupTargets = append(rollupTargets, target.clone())
+			rollupTargets = append(rollupTargets, ruleRollupTarget{
+				ruleID: ruleID,
+				target: target.clone(),
+			})
 		}
 	}
 	// NB: could log the matching error here if needed.
@@ -284,6 +288,13 @@ func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResu
 	return res
 }
 
+// ruleRollupTarget is a rollup target alongside the id of the rollup rule
+// it belongs to.
+type ruleRollupTarget struct {
+	ruleID []byte
+	target rollupTarget
+}
+
 // toRollupMatchResult applies the rollup operation in each rollup pipelines contained
 // in the rollup targets against the matching ID to determine the resulting new rollup
 // ID. It additionally distinguishes rollup pipelines whose first operation is a rollup
@@ -294,9 +305,9 @@ func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResu
 func (as *activeRuleSet) toRollupResults(
 	id []byte,
 	cutoverNanos int64,
-	targets []rollupTarget,
+	ruleTargets []ruleRollupTarget,
 ) (rollupResults, error) {
-	if len(targets) == 0 {
+	if len(ruleTargets) == 0 {
 		return rollupResults{}, nil
 	}
 
@@ -309,12 +320,13 @@ func (as *activeRuleSet) toRollupResults(
 
 	var (
 		multiErr           = xerrors.NewMultiError()
-		pipelines          = make([]metadata.PipelineMetadata, 0, len(targets))
-		newRollupIDResults = make([]idWithMatchResults, 0, len(targets))
+		pipelines          = make([]metadata.PipelineMetadata, 0, len(ruleTargets))
+		newRollupIDResults = make([]idWithMatchResults, 0, len(ruleTargets))
 		tagPairs           []metricID.TagPair
 	)
 
-	for _, target := range targets {
+	for _, ruleTarget := range ruleTargets {
+		target := ruleTarget.target
 		pipeline := target.Pipeline
 		// A rollup target should always have a non-empty pipeline but
 		// just being defensive here.
@@ -364,7 +376,7 @@ func (as *activeRuleSet) toRollupResults(
 			continue
 		}
 		tagPairs = tagPairs[:0]
-		applied, err := as.applyIDToPipeline(sortedTagPairBytes, toApply, tagPairs)
+		applied, err := as.applyIDToPipeline(sortedTagPairBytes, toApply, ruleTarget.ruleID, tagPairs)
 		if err != nil {
 			err = fmt.Errorf("failed to apply id %s to pipeline %v: %v", id, toApply, err)
 			multiErr = multiErr.Add(err)
@@ -443,6 +455,7 @@ func (as *activeRuleSet) matchRollupTarget(
 func (as *activeRuleSet) applyIDToPipeline(
 	sortedTagPairBytes []byte,
 	pipeline mpipeline.Pipeline,
+	ruleID []byte,
 	tagPairs []metricID.TagPair, // buffer for reuse across calls
 ) (applied.Pipeline, error) {
 	operations := make([]applied.OpUnion, 0, pipeline.Len())
@@ -470,8 +483,12 @@ func (as *activeRuleSet) applyIDToPipeline(
 				return applied.Pipeline{}, err
 			}
 			opUnion = applied.OpUnion{
-				Type:   mpipeline.RollupOpType,
-				Rollup: applied.RollupOp{ID: rollupID, AggregationID: rollupOp.AggregationID},
+				Type: mpipeline.RollupOpType,
+				Rollup: applied.RollupOp{
+					ID:            rollupID,
+					AggregationID: rollupOp.AggregationID,
+					RuleID:        ruleID,
+				},
 			}
 		default:
 			return applied.Pipeline{}, fmt.Errorf("unexpected pipeline op type: %v", pipelineOp.Type)
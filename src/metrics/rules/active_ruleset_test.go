@@ -584,6 +584,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -606,6 +607,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -752,6 +754,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -778,6 +781,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -800,6 +804,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -826,6 +831,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -848,6 +854,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -878,6 +885,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -900,6 +908,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -930,6 +939,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -952,6 +962,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -982,6 +993,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1012,6 +1024,7 @@ func TestActiveRuleSetForwardMatchWithRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1380,6 +1393,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1402,6 +1416,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -1494,6 +1509,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1685,6 +1701,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1733,6 +1750,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1755,6 +1773,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -1803,6 +1822,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1825,6 +1845,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -1875,6 +1896,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1897,6 +1919,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -1946,6 +1969,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -1968,6 +1992,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName2|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+											RuleID:        b("rollupRule2"),
 										},
 									},
 								}),
@@ -2010,6 +2035,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
@@ -2052,6 +2078,7 @@ func TestActiveRuleSetForwardMatchWithMappingRulesAndRollupRules(t *testing.T) {
 										Rollup: applied.RollupOp{
 											ID:            b("rName1|rtagName1=rtagValue1,rtagName2=rtagValue2"),
 											AggregationID: aggregation.DefaultID,
+											RuleID:        b("rollupRule1"),
 										},
 									},
 								}),
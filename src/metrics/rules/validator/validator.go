@@ -42,6 +42,7 @@ var (
 	errMoreThanOneAggregationOpInPipeline = errors.New("more than one aggregation operation in pipeline")
 	errAggregationOpNotFirstInPipeline    = errors.New("aggregation operation is not the first operation in pipeline")
 	errNoRollupOpInPipeline               = errors.New("no rollup operation in pipeline")
+	errCountDistinctNotMerged             = errors.New("count distinct sketches forwarded to rollup without count distinct aggregation")
 )
 
 type validator struct {
@@ -285,6 +286,9 @@ func (v *validator) validateStoragePolicies(
 //   be no more than the maximum transformation derivative order that is supported.
 // * The pipeline must contain at least one rollup operation and at most `n` rollup operations,
 //   where `n` is the maximum supported number of rollup levels.
+// * A rollup operation following an operation that aggregates with the count distinct
+//   aggregation type must also aggregate with the count distinct aggregation type, so
+//   the forwarded sketches can be merged.
 func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric.Type) error {
 	if pipeline.IsEmpty() {
 		return errEmptyPipeline
@@ -294,6 +298,7 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 		transformationDerivativeOrder int
 		numRollupOps                  int
 		previousRollupTags            map[string]struct{}
+		forwardsCountDistinct         bool
 		numPipelineOps                = pipeline.Len()
 	)
 	for i := 0; i < numPipelineOps; i++ {
@@ -310,6 +315,7 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 			if err := v.validateAggregationOp(pipelineOp.Aggregation, types); err != nil {
 				return fmt.Errorf("invalid aggregation operation at index %d: %v", i, err)
 			}
+			forwardsCountDistinct = pipelineOp.Aggregation.Type == aggregation.CountDistinct
 		case mpipeline.TransformationOpType:
 			transformOp := pipelineOp.Transformation
 			if transformOp.Type.IsBinaryTransform() {
@@ -333,6 +339,11 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 			if err := v.validateRollupOp(pipelineOp.Rollup, i, types, previousRollupTags); err != nil {
 				return fmt.Errorf("invalid rollup operation at index %d: %v", i, err)
 			}
+			rollupCountsDistinct := pipelineOp.Rollup.AggregationID.Contains(aggregation.CountDistinct)
+			if forwardsCountDistinct && !rollupCountsDistinct {
+				return fmt.Errorf("invalid rollup operation at index %d: %v", i, errCountDistinctNotMerged)
+			}
+			forwardsCountDistinct = rollupCountsDistinct
 			previousRollupTags = make(map[string]struct{}, len(pipelineOp.Rollup.Tags))
 			for _, tag := range pipelineOp.Rollup.Tags {
 				previousRollupTags[string(tag)] = struct{}{}
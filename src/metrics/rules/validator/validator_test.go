@@ -906,6 +906,47 @@ func TestValidatorValidateRollupRulePipelineMultiLevelRollup(t *testing.T) {
 	require.NoError(t, validator.ValidateSnapshot(view))
 }
 
+func TestValidatorValidateRollupRulePipelineCountDistinct(t *testing.T) {
+	newView := func(rollupAggregationType aggregation.Type) view.RuleSet {
+		return view.RuleSet{
+			RollupRules: []view.RollupRule{
+				{
+					Name:   "snapshot1",
+					Filter: testTypeTag + ":" + testCounterType,
+					Targets: []view.RollupTarget{
+						{
+							Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+								{
+									Type:        pipeline.AggregationOpType,
+									Aggregation: pipeline.AggregationOp{Type: aggregation.CountDistinct},
+								},
+								{
+									Type: pipeline.RollupOpType,
+									Rollup: pipeline.RollupOp{
+										NewName:       []byte("rName1"),
+										Tags:          [][]byte{[]byte("rtagName1")},
+										AggregationID: aggregation.MustCompressTypes(rollupAggregationType),
+									},
+								},
+							}),
+							StoragePolicies: testStoragePolicies(),
+						},
+					},
+				},
+			},
+		}
+	}
+	opts := testValidatorOptions().
+		SetAllowedFirstLevelAggregationTypesFor(metric.CounterType, aggregation.Types{aggregation.CountDistinct}).
+		SetAllowedNonFirstLevelAggregationTypesFor(metric.CounterType, aggregation.Types{aggregation.Sum, aggregation.CountDistinct})
+	validator := NewValidator(opts)
+	require.NoError(t, validator.ValidateSnapshot(newView(aggregation.CountDistinct)))
+
+	err := validator.ValidateSnapshot(newView(aggregation.Sum))
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "invalid rollup operation at index 1"))
+}
+
 func TestValidatorValidateRollupRuleRollupOpDuplicateRollupTag(t *testing.T) {
 	view := view.RuleSet{
 		RollupRules: []view.RollupRule{
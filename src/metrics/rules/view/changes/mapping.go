@@ -20,7 +20,11 @@
 
 package changes
 
-import "github.com/m3db/m3/src/metrics/rules/view"
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/metrics/rules/view"
+)
 
 // MappingRuleChange is a mapping rule diff.
 type MappingRuleChange struct {
@@ -51,3 +55,56 @@ func (a mappingRuleChangesByOpAscNameAscIDAsc) Less(i, j int) bool {
 	// This should not happen
 	return false
 }
+
+// newMappingRuleChanges computes the mapping rule changes required to turn the
+// current rules into the desired rules. Rules are matched by name.
+func newMappingRuleChanges(current, desired []view.MappingRule) ([]MappingRuleChange, error) {
+	currentByName := make(map[string]view.MappingRule, len(current))
+	for _, rule := range current {
+		if !rule.Tombstoned {
+			currentByName[rule.Name] = rule
+		}
+	}
+
+	var (
+		desiredNames = make(map[string]struct{}, len(desired))
+		res          []MappingRuleChange
+	)
+	for _, rule := range desired {
+		rule := rule
+		if _, exists := desiredNames[rule.Name]; exists {
+			return nil, fmt.Errorf("duplicate mapping rule name %s", rule.Name)
+		}
+		desiredNames[rule.Name] = struct{}{}
+
+		existing, exists := currentByName[rule.Name]
+		switch {
+		case !exists && rule.Tombstoned:
+			// Already absent from the current rules.
+		case !exists:
+			rule.ID = ""
+			res = append(res, MappingRuleChange{Op: AddOp, RuleData: &rule})
+		case rule.Tombstoned:
+			res = append(res, newMappingRuleDelete(existing))
+		default:
+			rule.ID = existing.ID
+			if !rule.Equal(&existing) {
+				res = append(res, MappingRuleChange{Op: ChangeOp, RuleID: &rule.ID, RuleData: &rule})
+			}
+		}
+	}
+
+	for _, rule := range current {
+		if rule.Tombstoned {
+			continue
+		}
+		if _, exists := desiredNames[rule.Name]; !exists {
+			res = append(res, newMappingRuleDelete(rule))
+		}
+	}
+	return res, nil
+}
+
+func newMappingRuleDelete(rule view.MappingRule) MappingRuleChange {
+	return MappingRuleChange{Op: DeleteOp, RuleID: &rule.ID, RuleData: &rule}
+}
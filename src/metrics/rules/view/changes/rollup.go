@@ -20,7 +20,11 @@
 
 package changes
 
-import "github.com/m3db/m3/src/metrics/rules/view"
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/metrics/rules/view"
+)
 
 // RollupRuleChange is a rollup rule diff.
 type RollupRuleChange struct {
@@ -51,3 +55,56 @@ func (a rollupRuleChangesByOpAscNameAscIDAsc) Less(i, j int) bool {
 	// This should not happen.
 	return false
 }
+
+// newRollupRuleChanges computes the rollup rule changes required to turn the
+// current rules into the desired rules. Rules are matched by name.
+func newRollupRuleChanges(current, desired []view.RollupRule) ([]RollupRuleChange, error) {
+	currentByName := make(map[string]view.RollupRule, len(current))
+	for _, rule := range current {
+		if !rule.Tombstoned {
+			currentByName[rule.Name] = rule
+		}
+	}
+
+	var (
+		desiredNames = make(map[string]struct{}, len(desired))
+		res          []RollupRuleChange
+	)
+	for _, rule := range desired {
+		rule := rule
+		if _, exists := desiredNames[rule.Name]; exists {
+			return nil, fmt.Errorf("duplicate rollup rule name %s", rule.Name)
+		}
+		desiredNames[rule.Name] = struct{}{}
+
+		existing, exists := currentByName[rule.Name]
+		switch {
+		case !exists && rule.Tombstoned:
+			// Already absent from the current rules.
+		case !exists:
+			rule.ID = ""
+			res = append(res, RollupRuleChange{Op: AddOp, RuleData: &rule})
+		case rule.Tombstoned:
+			res = append(res, newRollupRuleDelete(existing))
+		default:
+			rule.ID = existing.ID
+			if !rule.Equal(&existing) {
+				res = append(res, RollupRuleChange{Op: ChangeOp, RuleID: &rule.ID, RuleData: &rule})
+			}
+		}
+	}
+
+	for _, rule := range current {
+		if rule.Tombstoned {
+			continue
+		}
+		if _, exists := desiredNames[rule.Name]; !exists {
+			res = append(res, newRollupRuleDelete(rule))
+		}
+	}
+	return res, nil
+}
+
+func newRollupRuleDelete(rule view.RollupRule) RollupRuleChange {
+	return RollupRuleChange{Op: DeleteOp, RuleID: &rule.ID, RuleData: &rule}
+}
@@ -21,7 +21,10 @@
 package changes
 
 import (
+	"fmt"
 	"sort"
+
+	"github.com/m3db/m3/src/metrics/rules/view"
 )
 
 // RuleSetChanges is a ruleset diff.
@@ -36,3 +39,31 @@ func (d *RuleSetChanges) Sort() {
 	sort.Sort(mappingRuleChangesByOpAscNameAscIDAsc(d.MappingRuleChanges))
 	sort.Sort(rollupRuleChangesByOpAscNameAscIDAsc(d.RollupRuleChanges))
 }
+
+// NewRuleSetChanges computes the changes required to turn the current ruleset
+// into the desired ruleset. Rules are matched by name, rules missing from the
+// desired ruleset are deleted, and desired rules marked as tombstoned are
+// deleted if they are still live in the current ruleset.
+func NewRuleSetChanges(current, desired view.RuleSet) (RuleSetChanges, error) {
+	if desired.Namespace != "" && desired.Namespace != current.Namespace {
+		return RuleSetChanges{}, fmt.Errorf(
+			"namespace mismatch: current %s, desired %s",
+			current.Namespace, desired.Namespace,
+		)
+	}
+	mrChanges, err := newMappingRuleChanges(current.MappingRules, desired.MappingRules)
+	if err != nil {
+		return RuleSetChanges{}, err
+	}
+	rrChanges, err := newRollupRuleChanges(current.RollupRules, desired.RollupRules)
+	if err != nil {
+		return RuleSetChanges{}, err
+	}
+	res := RuleSetChanges{
+		Namespace:          current.Namespace,
+		MappingRuleChanges: mrChanges,
+		RollupRuleChanges:  rrChanges,
+	}
+	res.Sort()
+	return res, nil
+}
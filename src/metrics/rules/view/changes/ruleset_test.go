@@ -109,6 +109,120 @@ func TestRuleSetChangeSetsSort(t *testing.T) {
 	require.Equal(t, expected, ruleSet)
 }
 
+func TestNewRuleSetChanges(t *testing.T) {
+	current := view.RuleSet{
+		Namespace: "service1",
+		Version:   3,
+		MappingRules: []view.MappingRule{
+			{ID: "mrID1", Name: "unchanged", Filter: "tag1:value1"},
+			{ID: "mrID2", Name: "changed", Filter: "tag1:value1"},
+			{ID: "mrID3", Name: "deleted", Filter: "tag1:value1"},
+			{ID: "mrID4", Name: "tombstoned", Filter: "tag1:value1"},
+		},
+		RollupRules: []view.RollupRule{
+			{ID: "rrID1", Name: "unchanged", Filter: "tag2:value2"},
+			{ID: "rrID2", Name: "deleted", Filter: "tag2:value2"},
+		},
+	}
+	desired := view.RuleSet{
+		MappingRules: []view.MappingRule{
+			{Name: "unchanged", Filter: "tag1:value1"},
+			{Name: "changed", Filter: "tag1:value2"},
+			{Name: "tombstoned", Tombstoned: true, Filter: "tag1:value1"},
+			{Name: "absent", Tombstoned: true, Filter: "tag1:value1"},
+			{ID: "bogus", Name: "added", Filter: "tag1:value3"},
+		},
+		RollupRules: []view.RollupRule{
+			{Name: "unchanged", Filter: "tag2:value2"},
+			{Name: "added", Filter: "tag2:value3"},
+		},
+	}
+
+	expected := RuleSetChanges{
+		Namespace: "service1",
+		MappingRuleChanges: []MappingRuleChange{
+			{
+				Op:       AddOp,
+				RuleData: &view.MappingRule{Name: "added", Filter: "tag1:value3"},
+			},
+			{
+				Op:       ChangeOp,
+				RuleID:   ptr("mrID2"),
+				RuleData: &view.MappingRule{ID: "mrID2", Name: "changed", Filter: "tag1:value2"},
+			},
+			{
+				Op:       DeleteOp,
+				RuleID:   ptr("mrID3"),
+				RuleData: &view.MappingRule{ID: "mrID3", Name: "deleted", Filter: "tag1:value1"},
+			},
+			{
+				Op:       DeleteOp,
+				RuleID:   ptr("mrID4"),
+				RuleData: &view.MappingRule{ID: "mrID4", Name: "tombstoned", Filter: "tag1:value1"},
+			},
+		},
+		RollupRuleChanges: []RollupRuleChange{
+			{
+				Op:       AddOp,
+				RuleData: &view.RollupRule{Name: "added", Filter: "tag2:value3"},
+			},
+			{
+				Op:       DeleteOp,
+				RuleID:   ptr("rrID2"),
+				RuleData: &view.RollupRule{ID: "rrID2", Name: "deleted", Filter: "tag2:value2"},
+			},
+		},
+	}
+
+	res, err := NewRuleSetChanges(current, desired)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func TestNewRuleSetChangesNoChanges(t *testing.T) {
+	current := view.RuleSet{
+		Namespace: "service1",
+		MappingRules: []view.MappingRule{
+			{ID: "mrID1", Name: "mr", Filter: "tag1:value1"},
+		},
+		RollupRules: []view.RollupRule{
+			{ID: "rrID1", Name: "rr", Filter: "tag2:value2"},
+		},
+	}
+	desired := view.RuleSet{
+		Namespace: "service1",
+		MappingRules: []view.MappingRule{
+			{Name: "mr", Filter: "tag1:value1"},
+		},
+		RollupRules: []view.RollupRule{
+			{Name: "rr", Filter: "tag2:value2"},
+		},
+	}
+
+	res, err := NewRuleSetChanges(current, desired)
+	require.NoError(t, err)
+	require.Equal(t, RuleSetChanges{Namespace: "service1"}, res)
+}
+
+func TestNewRuleSetChangesDuplicateRuleName(t *testing.T) {
+	desired := view.RuleSet{
+		RollupRules: []view.RollupRule{
+			{Name: "rr", Filter: "tag2:value2"},
+			{Name: "rr", Filter: "tag2:value3"},
+		},
+	}
+	_, err := NewRuleSetChanges(view.RuleSet{Namespace: "service1"}, desired)
+	require.Error(t, err)
+}
+
+func TestNewRuleSetChangesNamespaceMismatch(t *testing.T) {
+	_, err := NewRuleSetChanges(
+		view.RuleSet{Namespace: "service1"},
+		view.RuleSet{Namespace: "service2"},
+	)
+	require.Error(t, err)
+}
+
 var (
 	ruleSet = RuleSetChanges{
 		Namespace: "service1",
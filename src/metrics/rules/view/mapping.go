@@ -27,16 +27,16 @@ import (
 
 // MappingRule is a mapping rule model at a given point in time.
 type MappingRule struct {
-	ID                  string                 `json:"id,omitempty"`
-	Name                string                 `json:"name" validate:"required"`
-	Tombstoned          bool                   `json:"tombstoned"`
-	CutoverMillis       int64                  `json:"cutoverMillis,omitempty"`
-	Filter              string                 `json:"filter" validate:"required"`
-	AggregationID       aggregation.ID         `json:"aggregation"`
-	StoragePolicies     policy.StoragePolicies `json:"storagePolicies"`
-	DropPolicy          policy.DropPolicy      `json:"dropPolicy"`
-	LastUpdatedBy       string                 `json:"lastUpdatedBy"`
-	LastUpdatedAtMillis int64                  `json:"lastUpdatedAtMillis"`
+	ID                  string                 `json:"id,omitempty" yaml:"id,omitempty"`
+	Name                string                 `json:"name" yaml:"name" validate:"required"`
+	Tombstoned          bool                   `json:"tombstoned" yaml:"tombstoned"`
+	CutoverMillis       int64                  `json:"cutoverMillis,omitempty" yaml:"cutoverMillis,omitempty"`
+	Filter              string                 `json:"filter" yaml:"filter" validate:"required"`
+	AggregationID       aggregation.ID         `json:"aggregation" yaml:"aggregation"`
+	StoragePolicies     policy.StoragePolicies `json:"storagePolicies" yaml:"storagePolicies"`
+	DropPolicy          policy.DropPolicy      `json:"dropPolicy" yaml:"dropPolicy"`
+	LastUpdatedBy       string                 `json:"lastUpdatedBy" yaml:"lastUpdatedBy"`
+	LastUpdatedAtMillis int64                  `json:"lastUpdatedAtMillis" yaml:"lastUpdatedAtMillis"`
 }
 
 // Equal determines whether two mapping rules are equal.
@@ -0,0 +1,61 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package view
+
+import (
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/policy"
+)
+
+// MatchResults are the results of matching a list of metric IDs against a ruleset.
+type MatchResults struct {
+	Namespace string        `json:"id"`
+	Version   int           `json:"version"`
+	Results   []MatchResult `json:"results"`
+}
+
+// MatchResult is the result of matching a metric ID against a ruleset.
+type MatchResult struct {
+	ID              string            `json:"id"`
+	ForExistingID   []StagedMetadata  `json:"forExistingID"`
+	ForNewRollupIDs []RollupIDMatches `json:"forNewRollupIDs"`
+}
+
+// RollupIDMatches are the staged metadatas of a rollup ID generated by rule matching.
+type RollupIDMatches struct {
+	ID        string           `json:"id"`
+	Metadatas []StagedMetadata `json:"metadatas"`
+}
+
+// StagedMetadata is the metadata matched for a metric ID that takes effect at a given time.
+type StagedMetadata struct {
+	CutoverMillis int64              `json:"cutoverMillis"`
+	Tombstoned    bool               `json:"tombstoned"`
+	Pipelines     []PipelineMetadata `json:"pipelines"`
+}
+
+// PipelineMetadata is the metadata of a single pipeline matched for a metric ID.
+type PipelineMetadata struct {
+	AggregationID   aggregation.ID         `json:"aggregation"`
+	StoragePolicies policy.StoragePolicies `json:"storagePolicies"`
+	Pipeline        string                 `json:"pipeline,omitempty"`
+	DropPolicy      policy.DropPolicy      `json:"dropPolicy,omitempty"`
+}
@@ -27,8 +27,8 @@ import (
 
 // RollupTarget is a rollup target model.
 type RollupTarget struct {
-	Pipeline        pipeline.Pipeline      `json:"pipeline" validate:"required"`
-	StoragePolicies policy.StoragePolicies `json:"storagePolicies" validate:"required"`
+	Pipeline        pipeline.Pipeline      `json:"pipeline" yaml:"pipeline" validate:"required"`
+	StoragePolicies policy.StoragePolicies `json:"storagePolicies" yaml:"storagePolicies" validate:"required"`
 }
 
 // Equal determines whether two rollup targets are equal.
@@ -44,14 +44,14 @@ func (t *RollupTarget) Equal(other *RollupTarget) bool {
 
 // RollupRule is rollup rule model.
 type RollupRule struct {
-	ID                  string         `json:"id,omitempty"`
-	Name                string         `json:"name" validate:"required"`
-	Tombstoned          bool           `json:"tombstoned"`
-	CutoverMillis       int64          `json:"cutoverMillis,omitempty"`
-	Filter              string         `json:"filter" validate:"required"`
-	Targets             []RollupTarget `json:"targets" validate:"required,dive,required"`
-	LastUpdatedBy       string         `json:"lastUpdatedBy"`
-	LastUpdatedAtMillis int64          `json:"lastUpdatedAtMillis"`
+	ID                  string         `json:"id,omitempty" yaml:"id,omitempty"`
+	Name                string         `json:"name" yaml:"name" validate:"required"`
+	Tombstoned          bool           `json:"tombstoned" yaml:"tombstoned"`
+	CutoverMillis       int64          `json:"cutoverMillis,omitempty" yaml:"cutoverMillis,omitempty"`
+	Filter              string         `json:"filter" yaml:"filter" validate:"required"`
+	Targets             []RollupTarget `json:"targets" yaml:"targets" validate:"required,dive,required"`
+	LastUpdatedBy       string         `json:"lastUpdatedBy" yaml:"lastUpdatedBy"`
+	LastUpdatedAtMillis int64          `json:"lastUpdatedAtMillis" yaml:"lastUpdatedAtMillis"`
 }
 
 // Equal determines whether two rollup rules are equal.
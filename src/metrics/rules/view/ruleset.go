@@ -26,11 +26,11 @@ import (
 
 // RuleSet is a snapshot of the rule set at a given point in time.
 type RuleSet struct {
-	Namespace     string        `json:"id"`
-	Version       int           `json:"version"`
-	CutoverMillis int64         `json:"cutoverMillis"`
-	MappingRules  []MappingRule `json:"mappingRules"`
-	RollupRules   []RollupRule  `json:"rollupRules"`
+	Namespace     string        `json:"id" yaml:"id"`
+	Version       int           `json:"version" yaml:"version"`
+	CutoverMillis int64         `json:"cutoverMillis" yaml:"cutoverMillis"`
+	MappingRules  []MappingRule `json:"mappingRules" yaml:"mappingRules"`
+	RollupRules   []RollupRule  `json:"rollupRules" yaml:"rollupRules"`
 }
 
 // Sort sorts the rules in the ruleset.
@@ -31,6 +31,7 @@ import (
 	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestRuleSetSort(t *testing.T) {
@@ -213,6 +214,74 @@ func TestRuleSetSort(t *testing.T) {
 	require.Equal(t, expected, ruleset)
 }
 
+func TestRuleSetUnmarshalYAML(t *testing.T) {
+	input := `
+id: testNamespace
+mappingRules:
+  - name: foo
+    filter: filter1
+    aggregation:
+      - Sum
+    storagePolicies:
+      - 10s:1h
+rollupRules:
+  - name: car
+    tombstoned: true
+    filter: filter3
+    targets:
+      - pipeline:
+          - rollup:
+              newName: name
+              tags:
+                - tag1
+                - tag2
+        storagePolicies:
+          - 1m:2d
+`
+
+	var ruleset RuleSet
+	require.NoError(t, yaml.Unmarshal([]byte(input), &ruleset))
+
+	expected := RuleSet{
+		Namespace: "testNamespace",
+		MappingRules: []MappingRule{
+			{
+				Name:          "foo",
+				Filter:        "filter1",
+				AggregationID: aggregation.MustCompressTypes(aggregation.Sum),
+				StoragePolicies: policy.StoragePolicies{
+					policy.NewStoragePolicy(10*time.Second, xtime.Second, time.Hour),
+				},
+			},
+		},
+		RollupRules: []RollupRule{
+			{
+				Name:       "car",
+				Tombstoned: true,
+				Filter:     "filter3",
+				Targets: []RollupTarget{
+					{
+						Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+							{
+								Type: pipeline.RollupOpType,
+								Rollup: pipeline.RollupOp{
+									NewName:       []byte("name"),
+									Tags:          [][]byte{[]byte("tag1"), []byte("tag2")},
+									AggregationID: aggregation.DefaultID,
+								},
+							},
+						}),
+						StoragePolicies: policy.StoragePolicies{
+							policy.NewStoragePolicy(time.Minute, xtime.Minute, 48*time.Hour),
+						},
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, ruleset)
+}
+
 func TestRuleSetsSort(t *testing.T) {
 	rulesets := RuleSets{
 		"ns1": &RuleSet{
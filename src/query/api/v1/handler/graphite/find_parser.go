@@ -44,8 +44,9 @@ import (
 // _childQuery, which adds an explicit match all after the last term in the
 // given query; this will return all values for exactly that tag which have at
 // least one child node.
-// _rawQueryString, which is the initial query request (bar final
-// matcher), which  is used to reconstruct the return values.
+// _rawQueryString, which is the path of the initial query request (bar final
+// matcher and any tag expressions), which is used to reconstruct the return
+// values.
 // _err, any error encountered during parsing.
 //
 // As an example, given the query `a.b*`, and metrics `a.bar.c` and `a.biz`,
@@ -136,7 +137,10 @@ func parseFindParamsToQueries(r *http.Request) (
 		End:              until,
 	}
 
-	return terminatedQuery, childQuery, query, nil
+	// NB: tagged queries, i.e. foo.*;env=prod, only return the path nodes so
+	// the tag expressions are not part of the result prefix.
+	path, _ := graphite.SplitTaggedQuery(query)
+	return terminatedQuery, childQuery, path, nil
 }
 
 func findResultsJSON(
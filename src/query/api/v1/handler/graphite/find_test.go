@@ -120,15 +120,18 @@ func bs(ss ...string) [][]byte {
 	return bb
 }
 
-func setupStorage(ctrl *gomock.Controller) storage.Storage {
+func setupStorage(
+	ctrl *gomock.Controller,
+	tagMatchers ...models.Matcher,
+) storage.Storage {
 	store := storage.NewMockStorage(ctrl)
 	// set up no children case
 	noChildrenMatcher := &completeTagQueryMatcher{
-		matchers: []models.Matcher{
-			{Type: models.MatchEqual, Name: b("__g0__"), Value: b("foo")},
-			{Type: models.MatchRegexp, Name: b("__g1__"), Value: b(`b[^\.]*`)},
-			{Type: models.MatchNotField, Name: b("__g2__")},
-		},
+		matchers: append(append([]models.Matcher{}, tagMatchers...),
+			models.Matcher{Type: models.MatchEqual, Name: b("__g0__"), Value: b("foo")},
+			models.Matcher{Type: models.MatchRegexp, Name: b("__g1__"), Value: b(`b[^\.]*`)},
+			models.Matcher{Type: models.MatchNotField, Name: b("__g2__")},
+		),
 	}
 
 	noChildrenResult := &storage.CompleteTagsResult{
@@ -143,11 +146,11 @@ func setupStorage(ctrl *gomock.Controller) storage.Storage {
 
 	// set up children case
 	childrenMatcher := &completeTagQueryMatcher{
-		matchers: []models.Matcher{
-			{Type: models.MatchEqual, Name: b("__g0__"), Value: b("foo")},
-			{Type: models.MatchRegexp, Name: b("__g1__"), Value: b(`b[^\.]*`)},
-			{Type: models.MatchField, Name: b("__g2__")},
-		},
+		matchers: append(append([]models.Matcher{}, tagMatchers...),
+			models.Matcher{Type: models.MatchEqual, Name: b("__g0__"), Value: b("foo")},
+			models.Matcher{Type: models.MatchRegexp, Name: b("__g1__"), Value: b(`b[^\.]*`)},
+			models.Matcher{Type: models.MatchField, Name: b("__g2__")},
+		),
 	}
 
 	childrenResult := &storage.CompleteTagsResult{
@@ -197,11 +200,22 @@ func (r results) Less(i, j int) bool {
 }
 
 func TestFind(t *testing.T) {
+	testFind(t, "foo.b*")
+}
+
+func TestFindTagged(t *testing.T) {
+	testFind(t, "foo.b*;env=prod;dc!=~us-.*",
+		models.Matcher{Type: models.MatchEqual, Name: b("env"), Value: b("prod")},
+		models.Matcher{Type: models.MatchNotRegexp, Name: b("dc"), Value: b("(?:us-.*).*")},
+	)
+}
+
+func testFind(t *testing.T, query string, tagMatchers ...models.Matcher) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	// setup storage and handler
-	store := setupStorage(ctrl)
+	store := setupStorage(ctrl, tagMatchers...)
 	handler := NewFindHandler(store,
 		handler.NewFetchOptionsBuilder(handler.FetchOptionsBuilderOptions{}),
 		instrument.NewOptions())
@@ -210,7 +224,8 @@ func TestFind(t *testing.T) {
 	w := &writer{}
 	req := &http.Request{
 		URL: &url.URL{
-			RawQuery: fmt.Sprintf("query=foo.b*&from=%s&until=%s", from.s, until.s),
+			RawQuery: fmt.Sprintf("query=%s&from=%s&until=%s",
+				url.QueryEscape(query), from.s, until.s),
 		},
 	}
 
@@ -34,3 +34,39 @@ const (
 	opSetItems   = 0x75
 	opProto      = 0x80
 )
+
+// list of additional opcodes required for unpickling metrics sent by
+// graphite relays with pickle protocols 0 to 4.
+const (
+	opInt             = 0x49
+	opBinInt1         = 0x4b
+	opBinInt2         = 0x4d
+	opLong            = 0x4c
+	opLong1           = 0x8a
+	opFloat           = 0x46
+	opString          = 0x53
+	opBinString       = 0x54
+	opShortBinString  = 0x55
+	opUnicode         = 0x56
+	opShortBinUnicode = 0x8c
+	opBinUnicode8     = 0x8d
+	opBinBytes        = 0x42
+	opShortBinBytes   = 0x43
+	opNewTrue         = 0x88
+	opNewFalse        = 0x89
+	opAppend          = 0x61
+	opList            = 0x6c
+	opTuple           = 0x74
+	opEmptyTuple      = 0x29
+	opTuple1          = 0x85
+	opTuple2          = 0x86
+	opTuple3          = 0x87
+	opPut             = 0x70
+	opBinPut          = 0x71
+	opLongBinPut      = 0x72
+	opGet             = 0x67
+	opBinGet          = 0x68
+	opLongBinGet      = 0x6a
+	opMemoize         = 0x94
+	opFrame           = 0x95
+)
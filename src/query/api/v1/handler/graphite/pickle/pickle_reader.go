@@ -0,0 +1,519 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package pickle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+var (
+	errTruncated      = errors.New("pickle: unexpected end of data")
+	errStackUnderflow = errors.New("pickle: stack underflow")
+	errNoMark         = errors.New("pickle: no mark on the stack")
+	errNotList        = errors.New("pickle: append to a value that is not a list")
+	errNoStop         = errors.New("pickle: no stop opcode")
+	errRecursiveList  = errors.New("pickle: recursive lists are not supported")
+	errTooManyValues  = fmt.Errorf("pickle: more than %d values", maxValues)
+	errTooDeep        = fmt.Errorf("pickle: nested more than %d levels deep", maxDepth)
+)
+
+const (
+	// maxValues is the maximum number of values a pickle may expand to, lists
+	// and tuples referenced more than once via the memo are expanded each
+	// time so a small pickle could otherwise expand exponentially.
+	maxValues = 1 << 20
+	// maxDepth is the maximum nesting depth of lists and tuples.
+	maxDepth = 64
+)
+
+// list is a python list, lists are kept as pointers while unpickling since
+// they may be memoized before their items are appended.
+type list struct {
+	items []interface{}
+}
+
+// A Reader is capable of reading the opcodes required to unpickle metrics
+// sent by graphite relays, i.e. lists and tuples of strings and numbers.
+// Note that this is a very limited implementation of unpickling, dicts,
+// sets and python objects are not supported.
+type Reader struct {
+	data  []byte
+	pos   int
+	stack []interface{}
+	marks []int
+	memo  map[int]interface{}
+}
+
+// NewReader creates a new pickle reader.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Read reads the pickled value, lists and tuples are returned as
+// []interface{}, strings and bytes as string, integers as int64 and floats
+// as float64.
+// Values which expand to more than maxValues values or nest deeper than
+// maxDepth levels are rejected.
+func (r *Reader) Read() (interface{}, error) {
+	for r.pos < len(r.data) {
+		op := r.data[r.pos]
+		r.pos++
+
+		if op == opStop {
+			value, err := r.pop()
+			if err != nil {
+				return nil, err
+			}
+			c := converter{visiting: make(map[*list]struct{})}
+			return c.toValue(value, 0)
+		}
+
+		if err := r.readOp(op); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errNoStop
+}
+
+func (r *Reader) readOp(op byte) error {
+	switch op {
+	case opProto:
+		_, err := r.readN(1)
+		return err
+	case opFrame:
+		_, err := r.readN(8)
+		return err
+	case opMark:
+		r.marks = append(r.marks, len(r.stack))
+	case opNone:
+		r.push(nil)
+	case opNewTrue:
+		r.push(true)
+	case opNewFalse:
+		r.push(false)
+
+	case opInt:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		switch string(line) {
+		case "00":
+			r.push(false)
+		case "01":
+			r.push(true)
+		default:
+			n, err := strconv.ParseInt(string(line), 10, 64)
+			if err != nil {
+				return fmt.Errorf("pickle: invalid int: %v", err)
+			}
+			r.push(n)
+		}
+	case opBinInt:
+		b, err := r.readN(4)
+		if err != nil {
+			return err
+		}
+		r.push(int64(int32(binary.LittleEndian.Uint32(b))))
+	case opBinInt1:
+		b, err := r.readN(1)
+		if err != nil {
+			return err
+		}
+		r.push(int64(b[0]))
+	case opBinInt2:
+		b, err := r.readN(2)
+		if err != nil {
+			return err
+		}
+		r.push(int64(binary.LittleEndian.Uint16(b)))
+	case opLong:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(string(bytes.TrimSuffix(line, []byte("L"))), 10, 64)
+		if err != nil {
+			return fmt.Errorf("pickle: invalid long: %v", err)
+		}
+		r.push(n)
+	case opLong1:
+		b, err := r.readN(1)
+		if err != nil {
+			return err
+		}
+		b, err = r.readN(int(b[0]))
+		if err != nil {
+			return err
+		}
+		n, err := decodeLong(b)
+		if err != nil {
+			return err
+		}
+		r.push(n)
+	case opFloat:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(string(line), 64)
+		if err != nil {
+			return fmt.Errorf("pickle: invalid float: %v", err)
+		}
+		r.push(v)
+	case opBinFloat:
+		b, err := r.readN(8)
+		if err != nil {
+			return err
+		}
+		r.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+
+	case opString:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		s, err := unquote(line)
+		if err != nil {
+			return err
+		}
+		r.push(s)
+	case opUnicode:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		r.push(string(line))
+	case opShortBinString, opShortBinBytes, opShortBinUnicode:
+		b, err := r.readN(1)
+		if err != nil {
+			return err
+		}
+		return r.pushString(int(b[0]))
+	case opBinString, opBinBytes, opBinUnicode:
+		b, err := r.readN(4)
+		if err != nil {
+			return err
+		}
+		return r.pushString(int(binary.LittleEndian.Uint32(b)))
+	case opBinUnicode8:
+		b, err := r.readN(8)
+		if err != nil {
+			return err
+		}
+		n := binary.LittleEndian.Uint64(b)
+		if n > uint64(len(r.data)) {
+			return errTruncated
+		}
+		return r.pushString(int(n))
+
+	case opEmptyList:
+		r.push(&list{})
+	case opList:
+		items, err := r.popMark()
+		if err != nil {
+			return err
+		}
+		r.push(&list{items: items})
+	case opAppend:
+		value, err := r.pop()
+		if err != nil {
+			return err
+		}
+		return r.appendToList(value)
+	case opAppends:
+		items, err := r.popMark()
+		if err != nil {
+			return err
+		}
+		return r.appendToList(items...)
+
+	case opEmptyTuple:
+		r.push([]interface{}{})
+	case opTuple:
+		items, err := r.popMark()
+		if err != nil {
+			return err
+		}
+		r.push(items)
+	case opTuple1, opTuple2, opTuple3:
+		n := int(op-opTuple1) + 1
+		if len(r.stack) < n {
+			return errStackUnderflow
+		}
+		items := make([]interface{}, n)
+		copy(items, r.stack[len(r.stack)-n:])
+		r.stack = r.stack[:len(r.stack)-n]
+		r.push(items)
+
+	case opPut:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		idx, err := strconv.Atoi(string(line))
+		if err != nil {
+			return fmt.Errorf("pickle: invalid memo index: %v", err)
+		}
+		return r.memoize(idx)
+	case opBinPut:
+		b, err := r.readN(1)
+		if err != nil {
+			return err
+		}
+		return r.memoize(int(b[0]))
+	case opLongBinPut:
+		b, err := r.readN(4)
+		if err != nil {
+			return err
+		}
+		return r.memoize(int(binary.LittleEndian.Uint32(b)))
+	case opMemoize:
+		return r.memoize(len(r.memo))
+	case opGet:
+		line, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		idx, err := strconv.Atoi(string(line))
+		if err != nil {
+			return fmt.Errorf("pickle: invalid memo index: %v", err)
+		}
+		return r.get(idx)
+	case opBinGet:
+		b, err := r.readN(1)
+		if err != nil {
+			return err
+		}
+		return r.get(int(b[0]))
+	case opLongBinGet:
+		b, err := r.readN(4)
+		if err != nil {
+			return err
+		}
+		return r.get(int(binary.LittleEndian.Uint32(b)))
+
+	default:
+		return fmt.Errorf("pickle: unsupported opcode 0x%x", op)
+	}
+
+	return nil
+}
+
+func (r *Reader) readN(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, errTruncated
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *Reader) readLine() ([]byte, error) {
+	idx := bytes.IndexByte(r.data[r.pos:], '\n')
+	if idx < 0 {
+		return nil, errTruncated
+	}
+	line := r.data[r.pos : r.pos+idx]
+	r.pos += idx + 1
+	return line, nil
+}
+
+func (r *Reader) pushString(n int) error {
+	b, err := r.readN(n)
+	if err != nil {
+		return err
+	}
+	r.push(string(b))
+	return nil
+}
+
+func (r *Reader) push(value interface{}) {
+	r.stack = append(r.stack, value)
+}
+
+func (r *Reader) pop() (interface{}, error) {
+	if len(r.stack) == 0 {
+		return nil, errStackUnderflow
+	}
+	value := r.stack[len(r.stack)-1]
+	r.stack = r.stack[:len(r.stack)-1]
+	return value, nil
+}
+
+func (r *Reader) popMark() ([]interface{}, error) {
+	if len(r.marks) == 0 {
+		return nil, errNoMark
+	}
+	mark := r.marks[len(r.marks)-1]
+	r.marks = r.marks[:len(r.marks)-1]
+	if mark > len(r.stack) {
+		return nil, errStackUnderflow
+	}
+
+	items := make([]interface{}, len(r.stack)-mark)
+	copy(items, r.stack[mark:])
+	r.stack = r.stack[:mark]
+	return items, nil
+}
+
+func (r *Reader) appendToList(items ...interface{}) error {
+	if len(r.stack) == 0 {
+		return errStackUnderflow
+	}
+	l, ok := r.stack[len(r.stack)-1].(*list)
+	if !ok {
+		return errNotList
+	}
+	l.items = append(l.items, items...)
+	return nil
+}
+
+func (r *Reader) memoize(idx int) error {
+	if len(r.stack) == 0 {
+		return errStackUnderflow
+	}
+	if r.memo == nil {
+		r.memo = make(map[int]interface{})
+	}
+	r.memo[idx] = r.stack[len(r.stack)-1]
+	return nil
+}
+
+func (r *Reader) get(idx int) error {
+	value, ok := r.memo[idx]
+	if !ok {
+		return fmt.Errorf("pickle: memo index %d not found", idx)
+	}
+	r.push(value)
+	return nil
+}
+
+// converter converts the lists referenced by a value into slices, visiting
+// holds the lists being converted to detect lists that contain themselves
+// and numValues the number of values converted so far to bound expansion.
+type converter struct {
+	visiting  map[*list]struct{}
+	numValues int
+}
+
+func (c *converter) toValue(value interface{}, depth int) (interface{}, error) {
+	c.numValues++
+	if c.numValues > maxValues {
+		return nil, errTooManyValues
+	}
+
+	switch v := value.(type) {
+	case *list:
+		if _, ok := c.visiting[v]; ok {
+			return nil, errRecursiveList
+		}
+		c.visiting[v] = struct{}{}
+		values, err := c.toValues(v.items, depth+1)
+		delete(c.visiting, v)
+		return values, err
+	case []interface{}:
+		return c.toValues(v, depth+1)
+	}
+	return value, nil
+}
+
+func (c *converter) toValues(items []interface{}, depth int) ([]interface{}, error) {
+	if depth > maxDepth {
+		return nil, errTooDeep
+	}
+	if c.numValues+len(items) > maxValues {
+		return nil, errTooManyValues
+	}
+
+	values := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		value, err := c.toValue(item, depth)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decodeLong decodes a little endian two's complement integer.
+func decodeLong(b []byte) (int64, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if len(b) > 8 {
+		return 0, errors.New("pickle: long does not fit in 64 bits")
+	}
+
+	var n uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		n = n<<8 | uint64(b[i])
+	}
+	if b[len(b)-1]&0x80 != 0 {
+		// Sign extend negative numbers.
+		n |= math.MaxUint64 << (uint(len(b)) * 8)
+	}
+	return int64(n), nil
+}
+
+// unquote unquotes a protocol 0 python string repr such as 'foo'.
+func unquote(b []byte) (string, error) {
+	if len(b) < 2 || b[0] != b[len(b)-1] || (b[0] != '\'' && b[0] != '"') {
+		return "", fmt.Errorf("pickle: invalid string: %s", b)
+	}
+	if b[0] == '"' {
+		return strconv.Unquote(string(b))
+	}
+
+	// Convert a single quoted python repr into a double quoted go string.
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	inner := b[1 : len(b)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner) && inner[i+1] == '\'':
+			buf.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(inner):
+			buf.WriteByte(c)
+			buf.WriteByte(inner[i+1])
+			i++
+		case c == '"':
+			buf.WriteString(`\"`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	s, err := strconv.Unquote(buf.String())
+	if err != nil {
+		return "", fmt.Errorf("pickle: invalid string: %v", err)
+	}
+	return s, nil
+}
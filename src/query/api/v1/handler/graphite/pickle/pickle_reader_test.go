@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package pickle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderProtocols(t *testing.T) {
+	expected := []interface{}{
+		[]interface{}{"foo.bar", []interface{}{int64(1556813561), 1.5}},
+		[]interface{}{"foo.baz", []interface{}{1556813562.5, int64(-2)}},
+		[]interface{}{"foo.qux", []interface{}{int64(1556813563), int64(3)}},
+	}
+
+	// Generated with pickle.dumps in python 3 for each protocol.
+	tests := map[string]string{
+		"protocol 0": "(lp0\n(Vfoo.bar\np1\n(I1556813561\nF1.5\ntp2\ntp3\na" +
+			"(Vfoo.baz\np4\n(F1556813562.5\nI-2\ntp5\ntp6\na" +
+			"(Vfoo.qux\np7\n(I1556813563\nI3\ntp8\ntp9\na.",
+		"protocol 1": "]q\x00((X\x07\x00\x00\x00foo.barq\x01(J\xf9\x16\xcb\\G?\xf8\x00\x00\x00\x00\x00\x00tq\x02tq\x03" +
+			"(X\x07\x00\x00\x00foo.bazq\x04(GA\xd72\xc5\xbe\xa0\x00\x00J\xfe\xff\xff\xfftq\x05tq\x06" +
+			"(X\x07\x00\x00\x00foo.quxq\x07(J\xfb\x16\xcb\\K\x03tq\x08tq\te.",
+		"protocol 2": "\x80\x02]q\x00(X\x07\x00\x00\x00foo.barq\x01J\xf9\x16\xcb\\G?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03" +
+			"X\x07\x00\x00\x00foo.bazq\x04GA\xd72\xc5\xbe\xa0\x00\x00J\xfe\xff\xff\xff\x86q\x05\x86q\x06" +
+			"X\x07\x00\x00\x00foo.quxq\x07J\xfb\x16\xcb\\K\x03\x86q\x08\x86q\te.",
+		"protocol 4": "\x80\x04\x95R\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x07foo.bar\x94J\xf9\x16\xcb\\G?\xf8\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94" +
+			"\x8c\x07foo.baz\x94GA\xd72\xc5\xbe\xa0\x00\x00J\xfe\xff\xff\xff\x86\x94\x86\x94" +
+			"\x8c\x07foo.qux\x94J\xfb\x16\xcb\\K\x03\x86\x94\x86\x94e.",
+		// Python 2 protocol 0 uses quoted strings and longs.
+		"python 2 protocol 0": "(lp0\n(S'foo.bar'\np1\n(L1556813561L\nF1.5\ntp2\ntp3\na" +
+			"(S'foo.baz'\np4\n(F1556813562.5\nI-2\ntp5\ntp6\na" +
+			"(S\"foo.qux\"\np7\n(I1556813563\nI3\ntp8\ntp9\na.",
+	}
+
+	for name, data := range tests {
+		value, err := NewReader([]byte(data)).Read()
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, value, name)
+	}
+}
+
+func TestReaderMemo(t *testing.T) {
+	// The same list referenced twice via the memo, the first reference is
+	// memoized before any items are appended.
+	data := "\x80\x02]q\x00(K\x01K\x02eh\x00\x86q\x01."
+	value, err := NewReader([]byte(data)).Read()
+	require.NoError(t, err)
+
+	list := []interface{}{int64(1), int64(2)}
+	assert.Equal(t, []interface{}{list, list}, value)
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := map[string]string{
+		"truncated":    "\x80\x02X\x07\x00\x00\x00foo",
+		"no stop":      "\x80\x02K\x01",
+		"no mark":      "\x80\x02]e.",
+		"underflow":    "\x80\x02.",
+		"bad opcode":   "\x80\x02c.",
+		"bad memo":     "\x80\x02h\x05.",
+		"not a list":   "\x80\x02K\x01K\x02a.",
+		"bad string":   "(S'foo\n.",
+		"recursive":    "\x80\x02]q\x00h\x00a.",
+		"long too big": "\x80\x02\x8a\x09\x01\x01\x01\x01\x01\x01\x01\x01\x01.",
+	}
+
+	for name, data := range tests {
+		_, err := NewReader([]byte(data)).Read()
+		assert.Error(t, err, name)
+	}
+}
+
+func TestReaderSharedReferencesExpansion(t *testing.T) {
+	// Each tuple references the previously memoized tuple twice, so the
+	// value doubles in size with each level while the pickle only grows by
+	// a few bytes.
+	var buf bytes.Buffer
+	buf.WriteString("\x80\x02]q\x00K\x01a")
+	for i := 1; i < 64; i++ {
+		buf.Write([]byte{opBinGet, byte(i - 1), opBinGet, byte(i - 1), opTuple2,
+			opBinPut, byte(i)})
+	}
+	buf.WriteByte(opStop)
+	require.True(t, buf.Len() < 512)
+
+	_, err := NewReader(buf.Bytes()).Read()
+	require.Equal(t, errTooManyValues, err)
+}
+
+func TestReaderMaxDepth(t *testing.T) {
+	nested := func(depth int) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("\x80\x02")
+		buf.WriteString(strings.Repeat("]", depth))
+		buf.WriteString(strings.Repeat("a", depth-1))
+		buf.WriteByte(opStop)
+		return buf.Bytes()
+	}
+
+	_, err := NewReader(nested(maxDepth)).Read()
+	require.NoError(t, err)
+
+	_, err = NewReader(nested(maxDepth + 1)).Read()
+	require.Equal(t, errTooDeep, err)
+}
+
+func TestDecodeLong(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected int64
+	}{
+		{data: nil, expected: 0},
+		{data: []byte{0xff, 0x00}, expected: 255},
+		{data: []byte{0xff}, expected: -1},
+		{data: []byte{0x00, 0xff}, expected: -256},
+		{data: []byte{0xf9, 0x16, 0xcb, 0x5c}, expected: 1556813561},
+	}
+
+	for _, test := range tests {
+		n, err := decodeLong(test.data)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, n)
+	}
+}
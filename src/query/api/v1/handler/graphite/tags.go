@@ -0,0 +1,490 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	graphiteStorage "github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	tagVar = "tag"
+
+	// TagsURL is the url for listing graphite tags.
+	TagsURL = handler.RoutePrefixV1 + "/graphite/tags"
+
+	// TagValuesURL is the url for listing the values of a graphite tag.
+	TagValuesURL = TagsURL + "/{" + tagVar + "}"
+
+	// AutoCompleteTagsURL is the url for auto completing graphite tags.
+	AutoCompleteTagsURL = TagsURL + "/autoComplete/tags"
+
+	// AutoCompleteValuesURL is the url for auto completing graphite tag values.
+	AutoCompleteValuesURL = TagsURL + "/autoComplete/values"
+
+	// defaultAutoCompleteLimit is the limit graphite applies to auto complete
+	// results when none is given.
+	defaultAutoCompleteLimit = 100
+)
+
+var (
+	// TagsHTTPMethods is the HTTP methods used with the tags resources.
+	TagsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	errNoTag = errors.New("no tag specified")
+)
+
+type tagsHandlerType int
+
+const (
+	listTagsHandler tagsHandlerType = iota
+	tagValuesHandler
+	autoCompleteTagsHandler
+	autoCompleteValuesHandler
+)
+
+type graphiteTagsHandler struct {
+	handlerType         tagsHandlerType
+	storage             storage.Storage
+	fetchOptionsBuilder handler.FetchOptionsBuilder
+	instrumentOpts      instrument.Options
+}
+
+// NewTagsHandler returns a handler listing the tags of graphite series,
+// optionally filtered by a regular expression anchored at the start of the
+// tag name.
+func NewTagsHandler(
+	storage storage.Storage,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return newTagsHandler(listTagsHandler, storage, fetchOptionsBuilder,
+		instrumentOpts)
+}
+
+// NewTagValuesHandler returns a handler listing the values of a graphite tag,
+// optionally filtered by a regular expression anchored at the start of the
+// tag value.
+func NewTagValuesHandler(
+	storage storage.Storage,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return newTagsHandler(tagValuesHandler, storage, fetchOptionsBuilder,
+		instrumentOpts)
+}
+
+// NewAutoCompleteTagsHandler returns a handler auto completing the tags of
+// graphite series matching the given tag expressions.
+func NewAutoCompleteTagsHandler(
+	storage storage.Storage,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return newTagsHandler(autoCompleteTagsHandler, storage, fetchOptionsBuilder,
+		instrumentOpts)
+}
+
+// NewAutoCompleteValuesHandler returns a handler auto completing the values
+// of a tag of graphite series matching the given tag expressions.
+func NewAutoCompleteValuesHandler(
+	storage storage.Storage,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return newTagsHandler(autoCompleteValuesHandler, storage, fetchOptionsBuilder,
+		instrumentOpts)
+}
+
+func newTagsHandler(
+	handlerType tagsHandlerType,
+	storage storage.Storage,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &graphiteTagsHandler{
+		handlerType:         handlerType,
+		storage:             storage,
+		fetchOptionsBuilder: fetchOptionsBuilder,
+		instrumentOpts:      instrumentOpts,
+	}
+}
+
+// tagsParams are the parameters of a tags request, the filter is only set
+// for the tags and tag values requests and the prefix and expressions only
+// for the auto complete requests.
+type tagsParams struct {
+	tag    string
+	filter *regexp.Regexp
+	prefix string
+	exprs  []string
+	limit  int
+}
+
+func (h *graphiteTagsHandler) parseParams(r *http.Request) (tagsParams, error) {
+	var params tagsParams
+	if err := r.ParseForm(); err != nil {
+		return params, err
+	}
+
+	switch h.handlerType {
+	case listTagsHandler, tagValuesHandler:
+		if h.handlerType == tagValuesHandler {
+			params.tag = mux.Vars(r)[tagVar]
+		}
+
+		if filter := r.FormValue("filter"); filter != "" {
+			// NB: graphite filters are only anchored at the start.
+			re, err := regexp.Compile("^(?:" + filter + ")")
+			if err != nil {
+				return params, fmt.Errorf("invalid 'filter': %v", err)
+			}
+			params.filter = re
+		}
+	case autoCompleteTagsHandler:
+		params.prefix = r.FormValue("tagPrefix")
+	case autoCompleteValuesHandler:
+		params.tag = r.FormValue(tagVar)
+		params.prefix = r.FormValue("valuePrefix")
+	}
+
+	if h.handlerType == tagValuesHandler || h.handlerType == autoCompleteValuesHandler {
+		if params.tag == "" {
+			return params, errNoTag
+		}
+	}
+
+	if h.handlerType == autoCompleteTagsHandler || h.handlerType == autoCompleteValuesHandler {
+		// NB: graphite accepts repeated expressions as either expr or expr[].
+		params.exprs = append(params.exprs, r.Form["expr"]...)
+		params.exprs = append(params.exprs, r.Form["expr[]"]...)
+		params.limit = defaultAutoCompleteLimit
+	}
+
+	if limit := r.FormValue("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return params, fmt.Errorf("invalid 'limit': %s", limit)
+		}
+		params.limit = n
+	}
+
+	return params, nil
+}
+
+func (h *graphiteTagsHandler) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	w.Header().Set("Content-Type", "application/json")
+
+	params, err := h.parseParams(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	opts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r)
+	if rErr != nil {
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	matchers, err := tagsMatchers(params.exprs)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var results map[string]struct{}
+	switch h.handlerType {
+	case listTagsHandler, autoCompleteTagsHandler:
+		results, err = h.tagNames(ctx, matchers, opts)
+	default:
+		results, err = h.tagValues(ctx, params.tag, matchers, opts)
+	}
+	if err != nil {
+		logger.Error("unable to complete tags", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if h.handlerType == autoCompleteTagsHandler {
+		// NB: graphite does not suggest tags which are already being matched.
+		for _, e := range params.exprs {
+			if expr, err := graphite.ParseTagExpression(e); err == nil {
+				delete(results, expr.Name)
+			}
+		}
+	}
+
+	found := len(results) > 0
+	values := filterTagsResults(results, params)
+	switch h.handlerType {
+	case listTagsHandler:
+		err = renderTagsJSON(w, values)
+	case tagValuesHandler:
+		err = renderTagValuesJSON(w, params.tag, values, found)
+	default:
+		err = renderAutoCompleteJSON(w, values)
+	}
+	if err != nil {
+		logger.Error("unable to render tags results", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+	}
+}
+
+// tagsMatchers converts tag expressions to matchers, an additional matcher
+// on the first path tag ensures only graphite series are matched.
+func tagsMatchers(exprs []string) (models.Matchers, error) {
+	graphiteMatcher := models.Matcher{
+		Type: models.MatchField,
+		Name: graphite.TagName(0),
+	}
+
+	if len(exprs) == 0 {
+		return models.Matchers{graphiteMatcher}, nil
+	}
+
+	query, err := graphite.TaggedQuery(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers, err := graphiteStorage.TranslateQueryToMatchers(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(matchers, graphiteMatcher), nil
+}
+
+// tagNames returns the tag names of the matched series, with the path tags
+// replaced by the graphite name tag.
+func (h *graphiteTagsHandler) tagNames(
+	ctx context.Context,
+	matchers models.Matchers,
+	opts *storage.FetchOptions,
+) (map[string]struct{}, error) {
+	result, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+		CompleteNameOnly: true,
+		TagMatchers:      matchers,
+		// NB: necessarily spans the entire timerange for the index.
+		Start: time.Time{},
+		End:   time.Now(),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(result.CompletedTags))
+	for _, tag := range result.CompletedTags {
+		if graphite.IsTagName(tag.Name) {
+			names[graphite.TaggedNameTag] = struct{}{}
+			continue
+		}
+
+		names[string(tag.Name)] = struct{}{}
+	}
+
+	return names, nil
+}
+
+// tagValues returns the values of the tag for the matched series, the values
+// of the graphite name tag are the paths of the series.
+func (h *graphiteTagsHandler) tagValues(
+	ctx context.Context,
+	tag string,
+	matchers models.Matchers,
+	opts *storage.FetchOptions,
+) (map[string]struct{}, error) {
+	if tag == graphite.TaggedNameTag {
+		return h.pathValues(ctx, matchers, opts)
+	}
+
+	name := []byte(tag)
+	result, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+		CompleteNameOnly: false,
+		FilterNameTags:   [][]byte{name},
+		TagMatchers: append(matchers, models.Matcher{
+			Type: models.MatchField,
+			Name: name,
+		}),
+		// NB: necessarily spans the entire timerange for the index.
+		Start: time.Time{},
+		End:   time.Now(),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]struct{})
+	for _, completed := range result.CompletedTags {
+		if string(completed.Name) != tag {
+			continue
+		}
+
+		for _, value := range completed.Values {
+			values[string(value)] = struct{}{}
+		}
+	}
+
+	return values, nil
+}
+
+func (h *graphiteTagsHandler) pathValues(
+	ctx context.Context,
+	matchers models.Matchers,
+	opts *storage.FetchOptions,
+) (map[string]struct{}, error) {
+	result, err := h.storage.SearchSeries(ctx, &storage.FetchQuery{
+		TagMatchers: matchers,
+		// NB: necessarily spans the entire timerange for the index.
+		Start: time.Time{},
+		End:   time.Now(),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{}, len(result.Metrics))
+	for _, metric := range result.Metrics {
+		var parts []string
+		for i := 0; ; i++ {
+			part, ok := metric.Tags.Get(graphite.TagName(i))
+			if !ok {
+				break
+			}
+			parts = append(parts, string(part))
+		}
+
+		if len(parts) > 0 {
+			paths[strings.Join(parts, ".")] = struct{}{}
+		}
+	}
+
+	return paths, nil
+}
+
+// filterTagsResults applies the filter, prefix and limit of the request to
+// the results, which are returned in sorted order.
+func filterTagsResults(results map[string]struct{}, params tagsParams) []string {
+	filtered := make([]string, 0, len(results))
+	for result := range results {
+		if params.filter != nil && !params.filter.MatchString(result) {
+			continue
+		}
+
+		if !strings.HasPrefix(result, params.prefix) {
+			continue
+		}
+
+		filtered = append(filtered, result)
+	}
+
+	sort.Strings(filtered)
+	if params.limit > 0 && len(filtered) > params.limit {
+		filtered = filtered[:params.limit]
+	}
+
+	return filtered
+}
+
+func renderTagsJSON(w io.Writer, tags []string) error {
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+	for _, tag := range tags {
+		jw.BeginObject()
+		jw.BeginObjectField("tag")
+		jw.WriteString(tag)
+		jw.EndObject()
+	}
+
+	jw.EndArray()
+	return jw.Close()
+}
+
+// renderTagValuesJSON renders the values of a tag, like graphite it renders
+// null for tags which do not exist. Graphite also includes the number of
+// series for each value, which is not available when completing tags.
+func renderTagValuesJSON(
+	w io.Writer,
+	tag string,
+	values []string,
+	found bool,
+) error {
+	jw := json.NewWriter(w)
+	if !found {
+		jw.WriteNull()
+		return jw.Close()
+	}
+
+	jw.BeginObject()
+	jw.BeginObjectField("tag")
+	jw.WriteString(tag)
+
+	jw.BeginObjectField("values")
+	jw.BeginArray()
+	for _, value := range values {
+		jw.BeginObject()
+		jw.BeginObjectField("value")
+		jw.WriteString(value)
+		jw.EndObject()
+	}
+
+	jw.EndArray()
+	jw.EndObject()
+	return jw.Close()
+}
+
+func renderAutoCompleteJSON(w io.Writer, values []string) error {
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+	for _, value := range values {
+		jw.WriteString(value)
+	}
+
+	jw.EndArray()
+	return jw.Close()
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type tagsQueryMatcher struct {
+	completeNameOnly bool
+	filterNameTags   [][]byte
+	matchers         models.Matchers
+}
+
+func (m *tagsQueryMatcher) String() string {
+	return fmt.Sprintf("tags query with matchers %v", m.matchers)
+}
+
+func (m *tagsQueryMatcher) Matches(x interface{}) bool {
+	var matchers models.Matchers
+	switch q := x.(type) {
+	case *storage.CompleteTagsQuery:
+		if q.CompleteNameOnly != m.completeNameOnly ||
+			fmt.Sprint(q.FilterNameTags) != fmt.Sprint(m.filterNameTags) {
+			return false
+		}
+		matchers = q.TagMatchers
+	case *storage.FetchQuery:
+		matchers = q.TagMatchers
+	default:
+		return false
+	}
+
+	return fmt.Sprint(matchers) == fmt.Sprint(m.matchers)
+}
+
+var _ gomock.Matcher = &tagsQueryMatcher{}
+
+var graphiteMatcher = models.Matcher{Type: models.MatchField, Name: b("__g0__")}
+
+func serveTags(
+	t *testing.T,
+	store storage.Storage,
+	path string,
+	values url.Values,
+) *httptest.ResponseRecorder {
+	var (
+		builder = handler.NewFetchOptionsBuilder(handler.FetchOptionsBuilderOptions{})
+		iOpts   = instrument.NewOptions()
+		router  = mux.NewRouter()
+	)
+	router.Handle(AutoCompleteTagsURL, NewAutoCompleteTagsHandler(store, builder, iOpts))
+	router.Handle(AutoCompleteValuesURL, NewAutoCompleteValuesHandler(store, builder, iOpts))
+	router.Handle(TagsURL, NewTagsHandler(store, builder, iOpts))
+	router.Handle(TagValuesURL, NewTagValuesHandler(store, builder, iOpts))
+
+	req := httptest.NewRequest(http.MethodGet, path+"?"+values.Encode(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestListTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), &tagsQueryMatcher{
+		completeNameOnly: true,
+		matchers:         models.Matchers{graphiteMatcher},
+	}, gomock.Any()).Return(&storage.CompleteTagsResult{
+		CompleteNameOnly: true,
+		CompletedTags: []storage.CompletedTag{
+			{Name: b("__g0__")}, {Name: b("__g1__")}, {Name: b("env")},
+			{Name: b("dc")}, {Name: b("region")},
+		},
+	}, nil).Times(2)
+
+	w := serveTags(t, store, TagsURL, url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t,
+		`[{"tag":"dc"},{"tag":"env"},{"tag":"name"},{"tag":"region"}]`,
+		w.Body.String())
+
+	// NB: the filter is anchored at the start of the tag name.
+	w = serveTags(t, store, TagsURL, url.Values{
+		"filter": []string{"[dn]|gion"},
+		"limit":  []string{"2"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `[{"tag":"dc"},{"tag":"name"}]`, w.Body.String())
+}
+
+func TestTagValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	for tag, values := range map[string][][]byte{
+		"env":     bs("staging", "prod", "dev"),
+		"missing": nil,
+	} {
+		var completed []storage.CompletedTag
+		if len(values) > 0 {
+			completed = []storage.CompletedTag{{Name: b(tag), Values: values}}
+		}
+
+		store.EXPECT().CompleteTags(gomock.Any(), &tagsQueryMatcher{
+			filterNameTags: bs(tag),
+			matchers: models.Matchers{
+				graphiteMatcher,
+				{Type: models.MatchField, Name: b(tag)},
+			},
+		}, gomock.Any()).Return(&storage.CompleteTagsResult{
+			CompletedTags: completed,
+		}, nil)
+	}
+
+	w := serveTags(t, store, TagsURL+"/env", url.Values{
+		"filter": []string{"prod|dev"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t,
+		`{"tag":"env","values":[{"value":"dev"},{"value":"prod"}]}`,
+		w.Body.String())
+
+	w = serveTags(t, store, TagsURL+"/missing", url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "null", w.Body.String())
+}
+
+func TestTagValuesName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metric := func(parts ...string) models.Metric {
+		tags := models.NewTags(len(parts), models.NewTagOptions())
+		for i, part := range parts {
+			tags = tags.AddTag(models.Tag{
+				Name:  b(fmt.Sprintf("__g%d__", i)),
+				Value: b(part),
+			})
+		}
+		return models.Metric{Tags: tags}
+	}
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().SearchSeries(gomock.Any(), &tagsQueryMatcher{
+		matchers: models.Matchers{graphiteMatcher},
+	}, gomock.Any()).Return(&storage.SearchResults{
+		Metrics: models.Metrics{
+			metric("foo", "bar"),
+			metric("foo", "baz", "qux"),
+			metric("foo", "bar"),
+		},
+	}, nil)
+
+	w := serveTags(t, store, TagsURL+"/name", url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t,
+		`{"tag":"name","values":[{"value":"foo.bar"},{"value":"foo.baz.qux"}]}`,
+		w.Body.String())
+}
+
+func TestAutoCompleteTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), &tagsQueryMatcher{
+		completeNameOnly: true,
+		matchers: models.Matchers{
+			{Type: models.MatchEqual, Name: b("env"), Value: b("prod")},
+			{Type: models.MatchEqual, Name: b("__g0__"), Value: b("foo")},
+			{Type: models.MatchNotField, Name: b("__g1__")},
+			graphiteMatcher,
+		},
+	}, gomock.Any()).Return(&storage.CompleteTagsResult{
+		CompleteNameOnly: true,
+		CompletedTags: []storage.CompletedTag{
+			{Name: b("__g0__")}, {Name: b("env")}, {Name: b("dc")},
+			{Name: b("datacenter")}, {Name: b("region")},
+		},
+	}, nil)
+
+	// NB: tags that are already matched are not suggested.
+	w := serveTags(t, store, AutoCompleteTagsURL, url.Values{
+		"expr[]":    []string{"name=foo", "env=prod"},
+		"tagPrefix": []string{"d"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `["datacenter","dc"]`, w.Body.String())
+}
+
+func TestAutoCompleteValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	values := make([][]byte, 0, 2*defaultAutoCompleteLimit)
+	for i := 0; i < 2*defaultAutoCompleteLimit; i++ {
+		values = append(values, b(fmt.Sprintf("host%03d", i)))
+	}
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), &tagsQueryMatcher{
+		filterNameTags: bs("host"),
+		matchers: models.Matchers{
+			{Type: models.MatchRegexp, Name: b("dc"), Value: b("(?:us-.*).*")},
+			graphiteMatcher,
+			{Type: models.MatchField, Name: b("host")},
+		},
+	}, gomock.Any()).Return(&storage.CompleteTagsResult{
+		CompletedTags: []storage.CompletedTag{{Name: b("host"), Values: values}},
+	}, nil).Times(2)
+
+	w := serveTags(t, store, AutoCompleteValuesURL, url.Values{
+		"tag":  []string{"host"},
+		"expr": []string{"dc=~us-.*"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, defaultAutoCompleteLimit,
+		strings.Count(w.Body.String(), "host"))
+	require.True(t, strings.HasPrefix(w.Body.String(), `["host000",`))
+
+	w = serveTags(t, store, AutoCompleteValuesURL, url.Values{
+		"tag":         []string{"host"},
+		"expr":        []string{"dc=~us-.*"},
+		"valuePrefix": []string{"host01"},
+		"limit":       []string{"3"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `["host010","host011","host012"]`, w.Body.String())
+}
+
+func TestTagsInvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	for _, test := range []struct {
+		path   string
+		values url.Values
+	}{
+		{TagsURL, url.Values{"filter": []string{"("}}},
+		{TagsURL, url.Values{"limit": []string{"-1"}}},
+		{AutoCompleteValuesURL, url.Values{}},
+		{AutoCompleteTagsURL, url.Values{"expr": []string{"env!=prod"}}},
+		{AutoCompleteTagsURL, url.Values{"expr": []string{"name=~foo"}}},
+	} {
+		w := serveTags(t, store, test.path, test.values)
+		require.Equal(t, http.StatusBadRequest, w.Code, test.values.Encode())
+	}
+}
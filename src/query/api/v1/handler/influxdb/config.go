@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package influxdb
+
+import (
+	"fmt"
+)
+
+// NameScheme is the scheme used to map InfluxDB measurements and fields
+// to metric names and tags.
+type NameScheme string
+
+const (
+	// MeasurementFieldNameScheme names each series after its measurement and
+	// field joined by the name separator, e.g. "cpu_usage_idle".
+	MeasurementFieldNameScheme NameScheme = "measurement_field"
+
+	// MeasurementNameScheme names each series after its measurement and
+	// records the field in the field tag, e.g. "cpu{field="usage_idle"}".
+	MeasurementNameScheme NameScheme = "measurement"
+
+	defaultNameScheme    = MeasurementFieldNameScheme
+	defaultNameSeparator = "_"
+	defaultFieldTag      = "field"
+)
+
+var validNameSchemes = []NameScheme{
+	MeasurementFieldNameScheme,
+	MeasurementNameScheme,
+}
+
+// WriteConfiguration is the configuration for mapping InfluxDB line
+// protocol points to series.
+type WriteConfiguration struct {
+	// NameScheme is the scheme used to name series, defaults to
+	// measurement_field.
+	NameScheme NameScheme `yaml:"nameScheme"`
+
+	// NameSeparator joins the measurement and field with the
+	// measurement_field scheme, defaults to "_".
+	NameSeparator *string `yaml:"nameSeparator"`
+
+	// FieldTag is the tag name the field is stored in with the measurement
+	// scheme, defaults to "field".
+	FieldTag string `yaml:"fieldTag"`
+
+	// DisableSanitization disables replacing characters in metric and tag
+	// names that are not valid in Prometheus names with underscores.
+	DisableSanitization bool `yaml:"disableSanitization"`
+}
+
+// Validate validates the write configuration.
+func (c WriteConfiguration) Validate() error {
+	if c.NameScheme == "" {
+		return nil
+	}
+	for _, valid := range validNameSchemes {
+		if c.NameScheme == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid influxdb name scheme '%s', valid schemes are: %v",
+		c.NameScheme, validNameSchemes)
+}
+
+type nameOptions struct {
+	scheme    NameScheme
+	separator []byte
+	fieldTag  []byte
+	sanitize  bool
+}
+
+func (c WriteConfiguration) nameOptions() nameOptions {
+	opts := nameOptions{
+		scheme:    defaultNameScheme,
+		separator: []byte(defaultNameSeparator),
+		fieldTag:  []byte(defaultFieldTag),
+		sanitize:  !c.DisableSanitization,
+	}
+	if c.NameScheme != "" {
+		opts.scheme = c.NameScheme
+	}
+	if c.NameSeparator != nil {
+		opts.separator = []byte(*c.NameSeparator)
+	}
+	if c.FieldTag != "" {
+		opts.fieldTag = []byte(c.FieldTag)
+	}
+	return opts
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package influxdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	errMissingMeasurement = errors.New("missing measurement")
+	errMissingTagValue    = errors.New("missing tag value")
+	errMissingFields      = errors.New("missing fields")
+	errMissingFieldValue  = errors.New("missing field value")
+	errUnterminatedString = errors.New("unterminated string field value")
+	errInvalidPrecision   = errors.New("invalid precision, must be one of: n, ns, u, us, µ, ms, s, m, h")
+)
+
+// precision is the precision of the timestamps of a line protocol request.
+type precision struct {
+	duration time.Duration
+	unit     xtime.Unit
+}
+
+// parsePrecision parses the precision query parameter of a write request,
+// the default precision is nanoseconds.
+func parsePrecision(value string) (precision, error) {
+	switch value {
+	case "", "n", "ns":
+		return precision{duration: time.Nanosecond, unit: xtime.Nanosecond}, nil
+	case "u", "us", "µ":
+		return precision{duration: time.Microsecond, unit: xtime.Microsecond}, nil
+	case "ms":
+		return precision{duration: time.Millisecond, unit: xtime.Millisecond}, nil
+	case "s":
+		return precision{duration: time.Second, unit: xtime.Second}, nil
+	case "m":
+		// NB: Series are written at second precision since coarser units
+		// are not supported by all encoders.
+		return precision{duration: time.Minute, unit: xtime.Second}, nil
+	case "h":
+		return precision{duration: time.Hour, unit: xtime.Second}, nil
+	}
+	return precision{}, errInvalidPrecision
+}
+
+// point is a single parsed line of line protocol.
+type point struct {
+	measurement  []byte
+	tags         []tag
+	fields       []field
+	timestamp    int64
+	hasTimestamp bool
+}
+
+type tag struct {
+	name  []byte
+	value []byte
+}
+
+type field struct {
+	name []byte
+	// numeric is false for string fields, which have no numeric value
+	// and cannot be stored as a datapoint.
+	numeric bool
+	value   float64
+}
+
+// parsePoints parses newline separated line protocol, lines that fail to
+// parse are skipped and the first parse error is returned alongside all
+// the points that parsed successfully.
+func parsePoints(data []byte) ([]point, error) {
+	var (
+		points   []point
+		firstErr error
+		lineNum  int
+	)
+	for len(data) > 0 {
+		var line []byte
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			line, data = data[:idx], data[idx+1:]
+		} else {
+			line, data = data, nil
+		}
+		lineNum++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		p, err := parseLine(line)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("unable to parse line %d '%s': %v",
+					lineNum, line, err)
+			}
+			continue
+		}
+		points = append(points, p)
+	}
+	return points, firstErr
+}
+
+func parseLine(line []byte) (point, error) {
+	var p point
+
+	measurement, i := scanToken(line, 0, ", ", ", ")
+	if len(measurement) == 0 {
+		return point{}, errMissingMeasurement
+	}
+	p.measurement = measurement
+
+	for i < len(line) && line[i] == ',' {
+		name, next := scanToken(line, i+1, ",= ", ",= ")
+		if len(name) == 0 || next >= len(line) || line[next] != '=' {
+			return point{}, errMissingTagValue
+		}
+		value, next := scanToken(line, next+1, ", ", ",= ")
+		if len(value) == 0 {
+			return point{}, errMissingTagValue
+		}
+		p.tags = append(p.tags, tag{name: name, value: value})
+		i = next
+	}
+
+	i = skipSpaces(line, i)
+	if i >= len(line) {
+		return point{}, errMissingFields
+	}
+
+	for {
+		name, next := scanToken(line, i, ",= ", ",= ")
+		if len(name) == 0 || next >= len(line) || line[next] != '=' {
+			return point{}, errMissingFieldValue
+		}
+		f, next, err := parseFieldValue(line, next+1)
+		if err != nil {
+			return point{}, fmt.Errorf("field '%s': %v", name, err)
+		}
+		f.name = name
+		p.fields = append(p.fields, f)
+
+		i = next
+		if i >= len(line) || line[i] != ',' {
+			break
+		}
+		i++
+	}
+
+	i = skipSpaces(line, i)
+	if i < len(line) {
+		timestamp, err := strconv.ParseInt(string(line[i:]), 10, 64)
+		if err != nil {
+			return point{}, fmt.Errorf("invalid timestamp: %v", err)
+		}
+		p.timestamp = timestamp
+		p.hasTimestamp = true
+	}
+
+	return p, nil
+}
+
+func parseFieldValue(line []byte, start int) (field, int, error) {
+	if start >= len(line) {
+		return field{}, start, errMissingFieldValue
+	}
+
+	if line[start] == '"' {
+		// String values may contain any character and are only terminated
+		// by an unescaped quote.
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return field{numeric: false}, i + 1, nil
+			}
+		}
+		return field{}, len(line), errUnterminatedString
+	}
+
+	end := start
+	for end < len(line) && line[end] != ',' && line[end] != ' ' {
+		end++
+	}
+	raw := line[start:end]
+	if len(raw) == 0 {
+		return field{}, end, errMissingFieldValue
+	}
+
+	value, err := parseNumericFieldValue(raw)
+	if err != nil {
+		return field{}, end, err
+	}
+	return field{numeric: true, value: value}, end, nil
+}
+
+func parseNumericFieldValue(raw []byte) (float64, error) {
+	switch string(raw) {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+
+	switch raw[len(raw)-1] {
+	case 'i':
+		v, err := strconv.ParseInt(string(raw[:len(raw)-1]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer value: %v", err)
+		}
+		return float64(v), nil
+	case 'u':
+		v, err := strconv.ParseUint(string(raw[:len(raw)-1]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid unsigned value: %v", err)
+		}
+		return float64(v), nil
+	}
+
+	v, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid float value: %v", err)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid float value: %s", raw)
+	}
+	return v, nil
+}
+
+// scanToken scans from start until an unescaped delimiter and returns the
+// unescaped token and the index of the delimiter, a backslash only escapes
+// the characters in escapable and is otherwise kept as is.
+func scanToken(line []byte, start int, delims, escapable string) ([]byte, int) {
+	var (
+		i       = start
+		escaped bool
+	)
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && strings.IndexByte(escapable, line[i+1]) >= 0 {
+			escaped = true
+			i++
+			continue
+		}
+		if strings.IndexByte(delims, c) >= 0 {
+			break
+		}
+	}
+
+	token := line[start:i]
+	if !escaped {
+		return token, i
+	}
+
+	unescaped := make([]byte, 0, len(token))
+	for j := 0; j < len(token); j++ {
+		if token[j] == '\\' && j+1 < len(token) &&
+			strings.IndexByte(escapable, token[j+1]) >= 0 {
+			j++
+		}
+		unescaped = append(unescaped, token[j])
+	}
+	return unescaped, i
+}
+
+func skipSpaces(line []byte, i int) int {
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return i
+}
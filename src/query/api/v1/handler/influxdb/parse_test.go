@@ -0,0 +1,144 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package influxdb
+
+import (
+	"testing"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected point
+	}{
+		{
+			line: "cpu,host=a,region=us\\ west usage_idle=98.5,usage_user=1i 1556813561098000000",
+			expected: point{
+				measurement: []byte("cpu"),
+				tags: []tag{
+					{name: []byte("host"), value: []byte("a")},
+					{name: []byte("region"), value: []byte("us west")},
+				},
+				fields: []field{
+					{name: []byte("usage_idle"), numeric: true, value: 98.5},
+					{name: []byte("usage_user"), numeric: true, value: 1},
+				},
+				timestamp:    1556813561098000000,
+				hasTimestamp: true,
+			},
+		},
+		{
+			line: "disk\\,io,path=/var free=10u,healthy=t,label=\"a, b=c \\\"d\\\"\"",
+			expected: point{
+				measurement: []byte("disk,io"),
+				tags: []tag{
+					{name: []byte("path"), value: []byte("/var")},
+				},
+				fields: []field{
+					{name: []byte("free"), numeric: true, value: 10},
+					{name: []byte("healthy"), numeric: true, value: 1},
+					{name: []byte("label"), numeric: false},
+				},
+			},
+		},
+		{
+			line: "mem used=-1.5e3",
+			expected: point{
+				measurement: []byte("mem"),
+				fields: []field{
+					{name: []byte("used"), numeric: true, value: -1500},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			p, err := parseLine([]byte(test.line))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, p)
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	tests := []string{
+		",host=a value=1",
+		"cpu",
+		"cpu,host value=1",
+		"cpu,host= value=1",
+		"cpu value",
+		"cpu value=",
+		"cpu value=abc",
+		"cpu value=\"unterminated",
+		"cpu value=1 notatimestamp",
+	}
+
+	for _, line := range tests {
+		t.Run(line, func(t *testing.T) {
+			_, err := parseLine([]byte(line))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParsePointsSkipsInvalidLines(t *testing.T) {
+	data := []byte("# comment\ncpu value=1 1\n\ncpu value\nmem value=2 2\n")
+
+	points, err := parsePoints(data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 4")
+	require.Equal(t, 2, len(points))
+	assert.Equal(t, "cpu", string(points[0].measurement))
+	assert.Equal(t, "mem", string(points[1].measurement))
+}
+
+func TestParsePrecision(t *testing.T) {
+	tests := []struct {
+		value    string
+		duration time.Duration
+		unit     xtime.Unit
+	}{
+		{value: "", duration: time.Nanosecond, unit: xtime.Nanosecond},
+		{value: "ns", duration: time.Nanosecond, unit: xtime.Nanosecond},
+		{value: "u", duration: time.Microsecond, unit: xtime.Microsecond},
+		{value: "ms", duration: time.Millisecond, unit: xtime.Millisecond},
+		{value: "s", duration: time.Second, unit: xtime.Second},
+		{value: "m", duration: time.Minute, unit: xtime.Second},
+		{value: "h", duration: time.Hour, unit: xtime.Second},
+	}
+
+	for _, test := range tests {
+		p, err := parsePrecision(test.value)
+		require.NoError(t, err)
+		assert.Equal(t, test.duration, p.duration)
+		assert.Equal(t, test.unit, p.unit)
+	}
+
+	_, err := parsePrecision("d")
+	require.Error(t, err)
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package influxdb
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/query/util/promname"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// InfluxDBWriteURL is the url for the InfluxDB line protocol write handler.
+	InfluxDBWriteURL = handler.RoutePrefixV1 + "/influxdb/write"
+
+	// InfluxDBWriteHTTPMethod is the HTTP method used with this resource.
+	InfluxDBWriteHTTPMethod = http.MethodPost
+
+	precisionParam = "precision"
+)
+
+var (
+	errNoDownsamplerAndWriter = errors.New("no downsampler and writer set")
+	errNoTagOptions           = errors.New("no tag options set")
+	errNoNowFn                = errors.New("no now fn set")
+	errEmptyBody              = errors.New("empty request body")
+)
+
+// WriteHandler represents a handler for the InfluxDB line protocol write
+// endpoint, points are written via the same downsampler and writer used by
+// Prometheus remote write.
+type WriteHandler struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	tagOptions           models.TagOptions
+	nameOpts             nameOptions
+	nowFn                clock.NowFn
+	instrumentOpts       instrument.Options
+	metrics              writeMetrics
+}
+
+// NewWriteHandler returns a new instance of the InfluxDB write handler.
+func NewWriteHandler(
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	cfg WriteConfiguration,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) (http.Handler, error) {
+	if downsamplerAndWriter == nil {
+		return nil, errNoDownsamplerAndWriter
+	}
+	if tagOptions == nil {
+		return nil, errNoTagOptions
+	}
+	if nowFn == nil {
+		return nil, errNoNowFn
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &WriteHandler{
+		downsamplerAndWriter: downsamplerAndWriter,
+		tagOptions:           tagOptions,
+		nameOpts:             cfg.nameOptions(),
+		nowFn:                nowFn,
+		instrumentOpts:       instrumentOpts,
+		metrics:              newWriteMetrics(instrumentOpts.MetricsScope()),
+	}, nil
+}
+
+type writeMetrics struct {
+	writeSuccess      tally.Counter
+	writeErrorsServer tally.Counter
+	writeErrorsClient tally.Counter
+	invalidLines      tally.Counter
+	stringFields      tally.Counter
+}
+
+func newWriteMetrics(scope tally.Scope) writeMetrics {
+	writeScope := scope.SubScope("influxdb-write")
+	return writeMetrics{
+		writeSuccess:      writeScope.Counter("success"),
+		writeErrorsServer: writeScope.Tagged(map[string]string{"code": "5XX"}).Counter("errors"),
+		writeErrorsClient: writeScope.Tagged(map[string]string{"code": "4XX"}).Counter("errors"),
+		invalidLines:      writeScope.Counter("invalid-lines"),
+		stringFields:      writeScope.Counter("string-fields-skipped"),
+	}
+}
+
+func (h *WriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	precision, err := parsePrecision(r.URL.Query().Get(precisionParam))
+	if err != nil {
+		h.metrics.writeErrorsClient.Inc(1)
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	body, rErr := h.readBody(r)
+	if rErr != nil {
+		h.metrics.writeErrorsClient.Inc(1)
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	points, parseErr := parsePoints(body)
+	if parseErr != nil {
+		h.metrics.invalidLines.Inc(1)
+	}
+
+	iter := h.newIter(points, precision)
+	if len(iter.tags) > 0 {
+		if batchErr := h.write(r.Context(), iter); batchErr != nil {
+			status := batchErrorStatus(batchErr)
+			if status == http.StatusBadRequest {
+				h.metrics.writeErrorsClient.Inc(1)
+			} else {
+				h.metrics.writeErrorsServer.Inc(1)
+			}
+
+			logger := logging.WithContext(r.Context(), h.instrumentOpts)
+			logger.Error("write error",
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.Int("httpResponseStatusCode", status),
+				zap.Int("numErrors", len(batchErr.Errors())),
+				zap.Error(batchErr.LastError()))
+			xhttp.Error(w, fmt.Errorf("write errors: count=%d, last=%v",
+				len(batchErr.Errors()), batchErr.LastError()), status)
+			return
+		}
+	}
+
+	if parseErr != nil {
+		// Mirror InfluxDB which writes all valid lines and reports the
+		// invalid ones as a partial write.
+		h.metrics.writeErrorsClient.Inc(1)
+		xhttp.Error(w, fmt.Errorf("partial write: %v", parseErr),
+			http.StatusBadRequest)
+		return
+	}
+
+	h.metrics.writeSuccess.Inc(1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WriteHandler) readBody(r *http.Request) ([]byte, *xhttp.ParseError) {
+	if r.Body == nil {
+		return nil, xhttp.NewParseError(errEmptyBody, http.StatusBadRequest)
+	}
+	defer r.Body.Close()
+
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	return body, nil
+}
+
+func (h *WriteHandler) write(ctx context.Context, iter *pointsIter) ingest.BatchError {
+	return h.downsamplerAndWriter.WriteBatch(ctx, iter, ingest.WriteOptions{})
+}
+
+func batchErrorStatus(batchErr ingest.BatchError) int {
+	for _, err := range batchErr.Errors() {
+		if !client.IsBadRequestError(err) && !xerrors.IsInvalidParams(err) {
+			return http.StatusInternalServerError
+		}
+	}
+	return http.StatusBadRequest
+}
+
+func (h *WriteHandler) newIter(points []point, precision precision) *pointsIter {
+	var (
+		now        = h.nowFn()
+		tags       = make([]models.Tags, 0, len(points))
+		datapoints = make([]ts.Datapoints, 0, len(points))
+	)
+	for _, p := range points {
+		timestamp := now.Truncate(precision.duration)
+		if p.hasTimestamp {
+			timestamp = time.Unix(0, p.timestamp*int64(precision.duration))
+		}
+
+		for _, f := range p.fields {
+			if !f.numeric {
+				h.metrics.stringFields.Inc(1)
+				continue
+			}
+			tags = append(tags, h.seriesTags(p, f))
+			datapoints = append(datapoints, ts.Datapoints{
+				{Timestamp: timestamp, Value: f.value},
+			})
+		}
+	}
+
+	return &pointsIter{
+		idx:        -1,
+		tags:       tags,
+		datapoints: datapoints,
+		unit:       precision.unit,
+	}
+}
+
+func (h *WriteHandler) seriesTags(p point, f field) models.Tags {
+	opts := h.nameOpts
+	tags := models.NewTags(len(p.tags)+2, h.tagOptions)
+	for _, t := range p.tags {
+		name := t.name
+		if opts.sanitize {
+			name = promname.SanitizeTagName(name)
+		}
+		tags = tags.AddTagWithoutNormalizing(models.Tag{Name: name, Value: t.value})
+	}
+
+	var metricName []byte
+	switch opts.scheme {
+	case MeasurementNameScheme:
+		metricName = p.measurement
+		tags = tags.AddOrUpdateTag(models.Tag{Name: opts.fieldTag, Value: f.name})
+	default:
+		metricName = make([]byte, 0,
+			len(p.measurement)+len(opts.separator)+len(f.name))
+		metricName = append(metricName, p.measurement...)
+		metricName = append(metricName, opts.separator...)
+		metricName = append(metricName, f.name...)
+	}
+	if opts.sanitize {
+		metricName = promname.SanitizeMetricName(metricName)
+	}
+
+	return tags.SetName(metricName).Normalize()
+}
+
+type pointsIter struct {
+	idx        int
+	tags       []models.Tags
+	datapoints []ts.Datapoints
+	unit       xtime.Unit
+}
+
+func (i *pointsIter) Next() bool {
+	i.idx++
+	return i.idx < len(i.tags)
+}
+
+func (i *pointsIter) Current() (models.Tags, ts.Datapoints, xtime.Unit) {
+	if len(i.tags) == 0 || i.idx < 0 || i.idx >= len(i.tags) {
+		return models.EmptyTags(), nil, 0
+	}
+
+	return i.tags[i.idx], i.datapoints[i.idx], i.unit
+}
+
+func (i *pointsIter) Reset() error {
+	i.idx = -1
+	return nil
+}
+
+func (i *pointsIter) Error() error {
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package influxdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type writtenSeries struct {
+	tags       map[string]string
+	datapoints ts.Datapoints
+	unit       xtime.Unit
+}
+
+func newTestWriteHandler(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	cfg WriteConfiguration,
+	written *[]writtenSeries,
+) http.Handler {
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			for iter.Next() {
+				tags, datapoints, unit := iter.Current()
+				series := writtenSeries{
+					tags:       make(map[string]string),
+					datapoints: datapoints,
+					unit:       unit,
+				}
+				for _, tag := range tags.Tags {
+					series.tags[string(tag.Name)] = string(tag.Value)
+				}
+				*written = append(*written, series)
+			}
+			return nil
+		}).
+		AnyTimes()
+
+	handler, err := NewWriteHandler(mockDownsamplerAndWriter,
+		models.NewTagOptions(), cfg, time.Now, instrument.NewOptions())
+	require.NoError(t, err)
+	return handler
+}
+
+func TestWriteMeasurementFieldScheme(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestWriteHandler(t, ctrl, WriteConfiguration{}, &written)
+
+	body := strings.NewReader("cpu,host=a,cpu.id=0 usage_idle=98.5,usage_user=1i,label=\"x\" 1556813561\n")
+	req := httptest.NewRequest(InfluxDBWriteHTTPMethod,
+		InfluxDBWriteURL+"?precision=s", body)
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusNoContent, writer.Code)
+
+	require.Equal(t, 2, len(written))
+	assert.Equal(t, map[string]string{
+		"__name__": "cpu_usage_idle",
+		"host":     "a",
+		"cpu_id":   "0",
+	}, written[0].tags)
+	assert.Equal(t, ts.Datapoints{
+		{Timestamp: time.Unix(1556813561, 0), Value: 98.5},
+	}, written[0].datapoints)
+	assert.Equal(t, xtime.Second, written[0].unit)
+	assert.Equal(t, "cpu_usage_user", written[1].tags["__name__"])
+	assert.Equal(t, 1.0, written[1].datapoints[0].Value)
+}
+
+func TestWriteMeasurementScheme(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestWriteHandler(t, ctrl, WriteConfiguration{
+		NameScheme: MeasurementNameScheme,
+		FieldTag:   "influx_field",
+	}, &written)
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	_, err := gzipWriter.Write([]byte("mem.stats used=10 1556813561000\n"))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	req := httptest.NewRequest(InfluxDBWriteHTTPMethod,
+		InfluxDBWriteURL+"?precision=ms", &buf)
+	req.Header.Set("Content-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusNoContent, writer.Code)
+
+	require.Equal(t, 1, len(written))
+	assert.Equal(t, map[string]string{
+		"__name__":     "mem_stats",
+		"influx_field": "used",
+	}, written[0].tags)
+	assert.Equal(t, time.Unix(1556813561, 0), written[0].datapoints[0].Timestamp)
+	assert.Equal(t, xtime.Millisecond, written[0].unit)
+}
+
+func TestWritePartialWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestWriteHandler(t, ctrl, WriteConfiguration{}, &written)
+
+	body := strings.NewReader("cpu value=1\ncpu value\n")
+	req := httptest.NewRequest(InfluxDBWriteHTTPMethod, InfluxDBWriteURL, body)
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusBadRequest, writer.Code)
+	assert.Contains(t, writer.Body.String(), "partial write")
+	require.Equal(t, 1, len(written))
+}
+
+func TestWriteInvalidPrecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestWriteHandler(t, ctrl, WriteConfiguration{}, &written)
+
+	req := httptest.NewRequest(InfluxDBWriteHTTPMethod,
+		InfluxDBWriteURL+"?precision=d", strings.NewReader("cpu value=1"))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusBadRequest, writer.Code)
+	require.Equal(t, 0, len(written))
+}
+
+func TestWriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batchErr := ingest.BatchError(xerrors.NewMultiError().Add(errors.New("an error")))
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(batchErr)
+
+	handler, err := NewWriteHandler(mockDownsamplerAndWriter,
+		models.NewTagOptions(), WriteConfiguration{}, time.Now,
+		instrument.NewOptions())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(InfluxDBWriteHTTPMethod, InfluxDBWriteURL,
+		strings.NewReader("cpu value=1"))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusInternalServerError, writer.Code)
+	assert.Contains(t, writer.Body.String(), "an error")
+}
+
+func TestWriteConfigurationValidate(t *testing.T) {
+	require.NoError(t, WriteConfiguration{}.Validate())
+	require.NoError(t, WriteConfiguration{NameScheme: MeasurementNameScheme}.Validate())
+	require.Error(t, WriteConfiguration{NameScheme: "unknown"}.Validate())
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+// Configuration is the configuration for the OTLP metrics receivers.
+type Configuration struct {
+	// GRPCListenAddress is the address the OTLP/gRPC receiver listens on,
+	// the gRPC receiver is disabled when not set. The OTLP/HTTP receiver is
+	// always served from the coordinator API listen address.
+	GRPCListenAddress string `yaml:"grpcListenAddress"`
+
+	// DisableResourceAttributes disables adding resource attributes, such as
+	// service.name, to the tags of each series.
+	DisableResourceAttributes bool `yaml:"disableResourceAttributes"`
+
+	// DisableSanitization disables replacing characters in metric and tag
+	// names that are not valid in Prometheus names with underscores.
+	DisableSanitization bool `yaml:"disableSanitization"`
+}
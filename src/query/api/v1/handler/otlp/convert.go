@@ -0,0 +1,421 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/promname"
+	"github.com/m3db/m3/src/x/clock"
+)
+
+const (
+	// noRecordedValueFlag is set on data points that explicitly mark the
+	// absence of a value, e.g. when a target stops being scraped.
+	noRecordedValueFlag = 1
+
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+	minSuffix    = "_min"
+	maxSuffix    = "_max"
+)
+
+var (
+	bucketTagName = []byte("le")
+	infBucket     = []byte("+Inf")
+
+	// temporalityTagName marks the series of delta sums and histograms, whose
+	// values are increments rather than running totals, so that queries and
+	// rollup rules can aggregate them accordingly.
+	temporalityTagName = []byte("__temporality__")
+	deltaTemporality   = []byte("delta")
+
+	errUnsupportedMetric     = errors.New("unsupported metric type, only sums, gauges and histograms are supported")
+	errEmptyMetricName       = errors.New("metric has no name")
+	errNoDataPointValue      = errors.New("number data point has no value")
+	errInvalidBucketCounts   = errors.New("histogram bucket counts do not match explicit bounds")
+	errInvalidDataPointValue = errors.New("data point value is not a number")
+)
+
+// converter maps OTLP metrics onto series, every data point becomes a single
+// datapoint written with the same downsampler and writer used by Prometheus
+// remote write. Sums are written as is, so cumulative sums keep their running
+// total while delta sums keep one increment per collection interval. Since the
+// downsampler only receives values, the series of delta sums and histograms
+// are tagged with their temporality so that they are not mistaken for gauges
+// or running totals. Histograms with explicit buckets are written as
+// Prometheus style _bucket, _sum and _count series sharing the same tags, with
+// _min and _max series when the data point records them.
+type converter struct {
+	tagOptions         models.TagOptions
+	resourceAttributes bool
+	sanitize           bool
+	nowFn              clock.NowFn
+}
+
+func newConverter(
+	tagOptions models.TagOptions,
+	cfg Configuration,
+	nowFn clock.NowFn,
+) converter {
+	return converter{
+		tagOptions:         tagOptions,
+		resourceAttributes: !cfg.DisableResourceAttributes,
+		sanitize:           !cfg.DisableSanitization,
+		nowFn:              nowFn,
+	}
+}
+
+// converted holds the series converted from a single export request along
+// with the number of data points that could not be converted.
+type converted struct {
+	tags       []models.Tags
+	datapoints []ts.Datapoints
+	rejected   int64
+	lastErr    error
+}
+
+func (c *converted) add(tags models.Tags, timestamp time.Time, value float64) {
+	c.tags = append(c.tags, tags)
+	c.datapoints = append(c.datapoints, ts.Datapoints{
+		{Timestamp: timestamp, Value: value},
+	})
+}
+
+func (c *converted) reject(n int, err error) {
+	c.rejected += int64(n)
+	c.lastErr = err
+}
+
+func (c converter) convert(req *otlppb.ExportMetricsServiceRequest) *converted {
+	var (
+		now    = c.nowFn()
+		result = &converted{}
+	)
+	for _, rm := range req.ResourceMetrics {
+		var resourceTags []models.Tag
+		if c.resourceAttributes && rm.Resource != nil {
+			resourceTags = c.attributeTags(nil, rm.Resource.Attributes)
+		}
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				c.convertMetric(metric, resourceTags, now, result)
+			}
+		}
+	}
+	return result
+}
+
+func (c converter) convertMetric(
+	metric *otlppb.Metric,
+	resourceTags []models.Tag,
+	now time.Time,
+	result *converted,
+) {
+	if metric.Name == "" {
+		result.reject(numDataPoints(metric), errEmptyMetricName)
+		return
+	}
+
+	name := []byte(metric.Name)
+	if c.sanitize {
+		name = promname.SanitizeMetricName(name)
+	}
+
+	switch data := metric.Data.(type) {
+	case *otlppb.Metric_Gauge:
+		c.convertNumberDataPoints(name, data.Gauge.DataPoints,
+			resourceTags, nil, now, result)
+	case *otlppb.Metric_Sum:
+		c.convertNumberDataPoints(name, data.Sum.DataPoints,
+			resourceTags, temporalityTags(data.Sum.AggregationTemporality),
+			now, result)
+	case *otlppb.Metric_Histogram:
+		c.convertHistogramDataPoints(name, data.Histogram.DataPoints,
+			resourceTags, temporalityTags(data.Histogram.AggregationTemporality),
+			now, result)
+	default:
+		// Summaries and exponential histograms are skipped as unknown fields
+		// when decoding so their data points cannot be counted.
+		result.reject(1, fmt.Errorf("metric %s: %v", metric.Name,
+			errUnsupportedMetric))
+	}
+}
+
+func (c converter) convertNumberDataPoints(
+	name []byte,
+	dataPoints []*otlppb.NumberDataPoint,
+	resourceTags []models.Tag,
+	extraTags []models.Tag,
+	now time.Time,
+	result *converted,
+) {
+	for _, dp := range dataPoints {
+		if dp.Flags&noRecordedValueFlag != 0 {
+			continue
+		}
+
+		var value float64
+		switch v := dp.Value.(type) {
+		case *otlppb.NumberDataPoint_AsDouble:
+			value = v.AsDouble
+		case *otlppb.NumberDataPoint_AsInt:
+			value = float64(v.AsInt)
+		default:
+			result.reject(1, errNoDataPointValue)
+			continue
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			result.reject(1, errInvalidDataPointValue)
+			continue
+		}
+
+		tags := c.attributeTags(resourceTags, dp.Attributes)
+		result.add(c.newTags(name, tags, extraTags...),
+			timestamp(dp.TimeUnixNano, now), value)
+	}
+}
+
+func (c converter) convertHistogramDataPoints(
+	name []byte,
+	dataPoints []*otlppb.HistogramDataPoint,
+	resourceTags []models.Tag,
+	extraTags []models.Tag,
+	now time.Time,
+	result *converted,
+) {
+	var (
+		bucketName = suffixedName(name, bucketSuffix)
+		sumName    = suffixedName(name, sumSuffix)
+		countName  = suffixedName(name, countSuffix)
+		minName    = suffixedName(name, minSuffix)
+		maxName    = suffixedName(name, maxSuffix)
+		bucketTags = make([]models.Tag, len(extraTags)+1)
+	)
+	copy(bucketTags, extraTags)
+	for _, dp := range dataPoints {
+		if dp.Flags&noRecordedValueFlag != 0 {
+			continue
+		}
+
+		numBuckets := len(dp.BucketCounts)
+		if numBuckets > 0 && numBuckets != len(dp.ExplicitBounds)+1 {
+			result.reject(1, errInvalidBucketCounts)
+			continue
+		}
+
+		var (
+			tags = c.attributeTags(resourceTags, dp.Attributes)
+			t    = timestamp(dp.TimeUnixNano, now)
+		)
+		result.add(c.newTags(countName, tags, extraTags...), t, float64(dp.Count))
+		result.add(c.newTags(sumName, tags, extraTags...), t, dp.Sum)
+
+		// NB: min and max are optional and decode as zero when not recorded.
+		if dp.Count > 0 && (dp.Min != 0 || dp.Max != 0) {
+			result.add(c.newTags(minName, tags, extraTags...), t, dp.Min)
+			result.add(c.newTags(maxName, tags, extraTags...), t, dp.Max)
+		}
+
+		// Bucket counts are per bucket in OTLP while Prometheus style
+		// buckets are cumulative, the last bucket is always +Inf.
+		var cumulative uint64
+		for i, count := range dp.BucketCounts {
+			cumulative += count
+			bound := infBucket
+			if i < len(dp.ExplicitBounds) {
+				bound = []byte(strconv.FormatFloat(dp.ExplicitBounds[i], 'f', -1, 64))
+			}
+			bucketTags[len(extraTags)] = models.Tag{Name: bucketTagName, Value: bound}
+			result.add(c.newTags(bucketName, tags, bucketTags...),
+				t, float64(cumulative))
+		}
+	}
+}
+
+// attributeTags returns the base tags with the attributes added, attributes
+// replace any base tags of the same name.
+func (c converter) attributeTags(
+	base []models.Tag,
+	attributes []*otlppb.KeyValue,
+) []models.Tag {
+	if len(attributes) == 0 {
+		return base
+	}
+
+	tags := make([]models.Tag, len(base), len(base)+len(attributes))
+	copy(tags, base)
+	for _, attr := range attributes {
+		value, ok := attributeValue(attr.Value)
+		if !ok || attr.Key == "" {
+			continue
+		}
+
+		name := []byte(attr.Key)
+		if c.sanitize {
+			name = promname.SanitizeTagName(name)
+		}
+
+		tag := models.Tag{Name: name, Value: value}
+		if idx := tagIndex(tags, name); idx >= 0 {
+			tags[idx] = tag
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (c converter) newTags(
+	name []byte,
+	attributes []models.Tag,
+	extra ...models.Tag,
+) models.Tags {
+	tags := models.NewTags(len(attributes)+len(extra)+1, c.tagOptions)
+	for _, tag := range attributes {
+		tags = tags.AddTagWithoutNormalizing(tag)
+	}
+	for _, tag := range extra {
+		tags = tags.AddOrUpdateTag(tag)
+	}
+	return tags.SetName(name).Normalize()
+}
+
+// temporalityTags returns the tags marking the series of a metric with the
+// given aggregation temporality, only delta temporality is marked.
+func temporalityTags(temporality otlppb.AggregationTemporality) []models.Tag {
+	if temporality != otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA {
+		return nil
+	}
+	return []models.Tag{{Name: temporalityTagName, Value: deltaTemporality}}
+}
+
+func tagIndex(tags []models.Tag, name []byte) int {
+	for i, tag := range tags {
+		if string(tag.Name) == string(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// attributeValue returns the tag value for an attribute value, arrays and
+// key value lists are encoded as JSON.
+func attributeValue(v *otlppb.AnyValue) ([]byte, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	var value string
+	switch val := v.Value.(type) {
+	case *otlppb.AnyValue_StringValue:
+		value = val.StringValue
+	case *otlppb.AnyValue_BoolValue:
+		value = strconv.FormatBool(val.BoolValue)
+	case *otlppb.AnyValue_IntValue:
+		value = strconv.FormatInt(val.IntValue, 10)
+	case *otlppb.AnyValue_DoubleValue:
+		value = strconv.FormatFloat(val.DoubleValue, 'f', -1, 64)
+	case *otlppb.AnyValue_BytesValue:
+		value = base64.StdEncoding.EncodeToString(val.BytesValue)
+	case *otlppb.AnyValue_ArrayValue, *otlppb.AnyValue_KvlistValue:
+		b, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return nil, false
+		}
+		value = string(b)
+	}
+	if value == "" {
+		return nil, false
+	}
+	return []byte(value), true
+}
+
+func jsonValue(v *otlppb.AnyValue) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	switch val := v.Value.(type) {
+	case *otlppb.AnyValue_StringValue:
+		return val.StringValue
+	case *otlppb.AnyValue_BoolValue:
+		return val.BoolValue
+	case *otlppb.AnyValue_IntValue:
+		return val.IntValue
+	case *otlppb.AnyValue_DoubleValue:
+		if math.IsNaN(val.DoubleValue) || math.IsInf(val.DoubleValue, 0) {
+			return strconv.FormatFloat(val.DoubleValue, 'f', -1, 64)
+		}
+		return val.DoubleValue
+	case *otlppb.AnyValue_BytesValue:
+		return val.BytesValue
+	case *otlppb.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(val.ArrayValue.GetValues()))
+		for _, elem := range val.ArrayValue.GetValues() {
+			values = append(values, jsonValue(elem))
+		}
+		return values
+	case *otlppb.AnyValue_KvlistValue:
+		values := make(map[string]interface{}, len(val.KvlistValue.GetValues()))
+		for _, kv := range val.KvlistValue.GetValues() {
+			values[kv.Key] = jsonValue(kv.Value)
+		}
+		return values
+	}
+	return nil
+}
+
+func numDataPoints(metric *otlppb.Metric) int {
+	switch data := metric.Data.(type) {
+	case *otlppb.Metric_Gauge:
+		return len(data.Gauge.DataPoints)
+	case *otlppb.Metric_Sum:
+		return len(data.Sum.DataPoints)
+	case *otlppb.Metric_Histogram:
+		return len(data.Histogram.DataPoints)
+	}
+	return 1
+}
+
+func timestamp(unixNanos uint64, now time.Time) time.Time {
+	if unixNanos == 0 {
+		return now
+	}
+	return time.Unix(0, int64(unixNanos))
+}
+
+func suffixedName(name []byte, suffix string) []byte {
+	suffixed := make([]byte, 0, len(name)+len(suffix))
+	suffixed = append(suffixed, name...)
+	return append(suffixed, suffix...)
+}
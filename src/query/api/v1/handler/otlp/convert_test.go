@@ -0,0 +1,341 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Unix(1500000000, 0)
+
+func testConverter(cfg Configuration) converter {
+	return newConverter(models.NewTagOptions(), cfg, func() time.Time {
+		return testNow
+	})
+}
+
+func stringAttr(key, value string) *otlppb.KeyValue {
+	return &otlppb.KeyValue{
+		Key:   key,
+		Value: &otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func testRequest(resource []*otlppb.KeyValue, metrics ...*otlppb.Metric) *otlppb.ExportMetricsServiceRequest {
+	return &otlppb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlppb.ResourceMetrics{
+			{
+				Resource:     &otlppb.Resource{Attributes: resource},
+				ScopeMetrics: []*otlppb.ScopeMetrics{{Metrics: metrics}},
+			},
+		},
+	}
+}
+
+func tagsMap(tags models.Tags) map[string]string {
+	m := make(map[string]string, len(tags.Tags))
+	for _, tag := range tags.Tags {
+		m[string(tag.Name)] = string(tag.Value)
+	}
+	return m
+}
+
+func TestConvertGaugeAndSum(t *testing.T) {
+	req := testRequest(
+		[]*otlppb.KeyValue{
+			stringAttr("service.name", "api"),
+			stringAttr("host", "resource-host"),
+		},
+		&otlppb.Metric{
+			Name: "process.cpu.time",
+			Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+				AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+				DataPoints: []*otlppb.NumberDataPoint{
+					{
+						TimeUnixNano: 1000,
+						Value:        &otlppb.NumberDataPoint_AsInt{AsInt: 42},
+						Attributes:   []*otlppb.KeyValue{stringAttr("host", "a")},
+					},
+				},
+			}},
+		},
+		&otlppb.Metric{
+			Name: "queue_size",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []*otlppb.NumberDataPoint{
+					{Value: &otlppb.NumberDataPoint_AsDouble{AsDouble: 1.5}},
+					{
+						TimeUnixNano: 2000,
+						Flags:        noRecordedValueFlag,
+					},
+				},
+			}},
+		},
+	)
+
+	result := testConverter(Configuration{}).convert(req)
+	require.Equal(t, int64(0), result.rejected)
+	require.Equal(t, 2, len(result.tags))
+
+	// Delta sums are tagged with their temporality.
+	assert.Equal(t, map[string]string{
+		"__name__":        "process_cpu_time",
+		"__temporality__": "delta",
+		"service_name":    "api",
+		"host":            "a",
+	}, tagsMap(result.tags[0]))
+	assert.Equal(t, time.Unix(0, 1000), result.datapoints[0][0].Timestamp)
+	assert.Equal(t, 42.0, result.datapoints[0][0].Value)
+
+	assert.Equal(t, map[string]string{
+		"__name__":     "queue_size",
+		"service_name": "api",
+		"host":         "resource-host",
+	}, tagsMap(result.tags[1]))
+	assert.Equal(t, testNow, result.datapoints[1][0].Timestamp)
+	assert.Equal(t, 1.5, result.datapoints[1][0].Value)
+}
+
+func TestConvertHistogram(t *testing.T) {
+	req := testRequest(nil, &otlppb.Metric{
+		Name: "http.duration",
+		Data: &otlppb.Metric_Histogram{Histogram: &otlppb.Histogram{
+			DataPoints: []*otlppb.HistogramDataPoint{
+				{
+					TimeUnixNano:   1000,
+					Count:          6,
+					Sum:            12.5,
+					BucketCounts:   []uint64{1, 2, 3},
+					ExplicitBounds: []float64{0.5, 1},
+					Attributes:     []*otlppb.KeyValue{stringAttr("route", "/")},
+				},
+			},
+		}},
+	})
+
+	result := testConverter(Configuration{}).convert(req)
+	require.Equal(t, int64(0), result.rejected)
+	require.Equal(t, 5, len(result.tags))
+
+	type series struct {
+		tags  map[string]string
+		value float64
+	}
+	var actual []series
+	for i, tags := range result.tags {
+		actual = append(actual, series{
+			tags:  tagsMap(tags),
+			value: result.datapoints[i][0].Value,
+		})
+	}
+
+	assert.Equal(t, []series{
+		{tags: map[string]string{"__name__": "http_duration_count", "route": "/"}, value: 6},
+		{tags: map[string]string{"__name__": "http_duration_sum", "route": "/"}, value: 12.5},
+		{tags: map[string]string{"__name__": "http_duration_bucket", "route": "/", "le": "0.5"}, value: 1},
+		{tags: map[string]string{"__name__": "http_duration_bucket", "route": "/", "le": "1"}, value: 3},
+		{tags: map[string]string{"__name__": "http_duration_bucket", "route": "/", "le": "+Inf"}, value: 6},
+	}, actual)
+}
+
+func TestConvertDeltaHistogram(t *testing.T) {
+	req := testRequest(nil, &otlppb.Metric{
+		Name: "http.duration",
+		Data: &otlppb.Metric_Histogram{Histogram: &otlppb.Histogram{
+			AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+			DataPoints: []*otlppb.HistogramDataPoint{
+				{
+					TimeUnixNano:   1000,
+					Count:          2,
+					Sum:            1.5,
+					Min:            0.25,
+					Max:            1.25,
+					BucketCounts:   []uint64{1, 1},
+					ExplicitBounds: []float64{1},
+				},
+			},
+		}},
+	})
+
+	result := testConverter(Configuration{}).convert(req)
+	require.Equal(t, int64(0), result.rejected)
+
+	type series struct {
+		tags  map[string]string
+		value float64
+	}
+	var actual []series
+	for i, tags := range result.tags {
+		actual = append(actual, series{
+			tags:  tagsMap(tags),
+			value: result.datapoints[i][0].Value,
+		})
+	}
+
+	assert.Equal(t, []series{
+		{tags: map[string]string{"__name__": "http_duration_count", "__temporality__": "delta"}, value: 2},
+		{tags: map[string]string{"__name__": "http_duration_sum", "__temporality__": "delta"}, value: 1.5},
+		{tags: map[string]string{"__name__": "http_duration_min", "__temporality__": "delta"}, value: 0.25},
+		{tags: map[string]string{"__name__": "http_duration_max", "__temporality__": "delta"}, value: 1.25},
+		{tags: map[string]string{"__name__": "http_duration_bucket", "__temporality__": "delta", "le": "1"}, value: 1},
+		{tags: map[string]string{"__name__": "http_duration_bucket", "__temporality__": "delta", "le": "+Inf"}, value: 2},
+	}, actual)
+}
+
+func TestConvertRejectsInvalidDataPoints(t *testing.T) {
+	req := testRequest(nil,
+		&otlppb.Metric{
+			Name: "no_value",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []*otlppb.NumberDataPoint{{TimeUnixNano: 1000}},
+			}},
+		},
+		&otlppb.Metric{
+			Name: "bad_buckets",
+			Data: &otlppb.Metric_Histogram{Histogram: &otlppb.Histogram{
+				DataPoints: []*otlppb.HistogramDataPoint{
+					{BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{1, 2}},
+				},
+			}},
+		},
+		&otlppb.Metric{
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []*otlppb.NumberDataPoint{
+					{Value: &otlppb.NumberDataPoint_AsInt{AsInt: 1}},
+					{Value: &otlppb.NumberDataPoint_AsInt{AsInt: 2}},
+				},
+			}},
+		},
+		&otlppb.Metric{Name: "summary"},
+		&otlppb.Metric{
+			Name: "ok",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []*otlppb.NumberDataPoint{
+					{Value: &otlppb.NumberDataPoint_AsInt{AsInt: 1}},
+				},
+			}},
+		},
+	)
+
+	result := testConverter(Configuration{}).convert(req)
+	assert.Equal(t, int64(5), result.rejected)
+	require.Error(t, result.lastErr)
+	assert.Contains(t, result.lastErr.Error(), "unsupported metric type")
+	require.Equal(t, 1, len(result.tags))
+	assert.Equal(t, map[string]string{"__name__": "ok"}, tagsMap(result.tags[0]))
+}
+
+func TestConvertConfiguration(t *testing.T) {
+	req := testRequest(
+		[]*otlppb.KeyValue{stringAttr("service.name", "api")},
+		&otlppb.Metric{
+			Name: "queue.size",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []*otlppb.NumberDataPoint{
+					{
+						Value:      &otlppb.NumberDataPoint_AsInt{AsInt: 1},
+						Attributes: []*otlppb.KeyValue{stringAttr("queue.name", "q")},
+					},
+				},
+			}},
+		},
+	)
+
+	result := testConverter(Configuration{
+		DisableResourceAttributes: true,
+		DisableSanitization:       true,
+	}).convert(req)
+	require.Equal(t, 1, len(result.tags))
+	assert.Equal(t, map[string]string{
+		"__name__":   "queue.size",
+		"queue.name": "q",
+	}, tagsMap(result.tags[0]))
+}
+
+func TestAttributeValue(t *testing.T) {
+	tests := []struct {
+		value    *otlppb.AnyValue
+		expected string
+		ok       bool
+	}{
+		{value: nil},
+		{value: &otlppb.AnyValue{}},
+		{value: &otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: ""}}},
+		{
+			value:    &otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: "foo"}},
+			expected: "foo",
+			ok:       true,
+		},
+		{
+			value:    &otlppb.AnyValue{Value: &otlppb.AnyValue_BoolValue{BoolValue: false}},
+			expected: "false",
+			ok:       true,
+		},
+		{
+			value:    &otlppb.AnyValue{Value: &otlppb.AnyValue_IntValue{IntValue: -3}},
+			expected: "-3",
+			ok:       true,
+		},
+		{
+			value:    &otlppb.AnyValue{Value: &otlppb.AnyValue_DoubleValue{DoubleValue: 0.25}},
+			expected: "0.25",
+			ok:       true,
+		},
+		{
+			value:    &otlppb.AnyValue{Value: &otlppb.AnyValue_BytesValue{BytesValue: []byte("hi")}},
+			expected: "aGk=",
+			ok:       true,
+		},
+		{
+			value: &otlppb.AnyValue{Value: &otlppb.AnyValue_ArrayValue{
+				ArrayValue: &otlppb.ArrayValue{Values: []*otlppb.AnyValue{
+					{Value: &otlppb.AnyValue_StringValue{StringValue: "a"}},
+					{Value: &otlppb.AnyValue_IntValue{IntValue: 1}},
+				}},
+			}},
+			expected: `["a",1]`,
+			ok:       true,
+		},
+		{
+			value: &otlppb.AnyValue{Value: &otlppb.AnyValue_KvlistValue{
+				KvlistValue: &otlppb.KeyValueList{Values: []*otlppb.KeyValue{
+					stringAttr("k", "v"),
+				}},
+			}},
+			expected: `{"k":"v"}`,
+			ok:       true,
+		},
+	}
+
+	for _, test := range tests {
+		value, ok := attributeValue(test.value)
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.expected, string(value))
+	}
+}
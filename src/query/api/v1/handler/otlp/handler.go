@@ -0,0 +1,155 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// OTLPMetricsURL is the url for the OTLP/HTTP metrics receiver, OTLP
+	// exporters should be configured with this path as their metrics
+	// endpoint.
+	OTLPMetricsURL = handler.RoutePrefixV1 + "/otlp/v1/metrics"
+
+	// OTLPMetricsHTTPMethod is the HTTP method used with this resource.
+	OTLPMetricsHTTPMethod = http.MethodPost
+
+	protobufContentType = "application/x-protobuf"
+)
+
+var (
+	errEmptyBody              = errors.New("empty request body")
+	errUnsupportedContentType = errors.New(
+		"unsupported content type, only " + protobufContentType + " is supported")
+)
+
+// MetricsHandler represents a handler for the OTLP/HTTP metrics endpoint.
+type MetricsHandler struct {
+	receiver *receiver
+}
+
+// NewMetricsHandler returns a new instance of the OTLP/HTTP metrics handler.
+func NewMetricsHandler(
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	cfg Configuration,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) (http.Handler, error) {
+	receiver, err := newReceiver(downsamplerAndWriter, tagOptions, cfg,
+		nowFn, "http", instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricsHandler{receiver: receiver}, nil
+}
+
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, rErr := h.parseRequest(r)
+	if rErr != nil {
+		h.receiver.metrics.writeErrorsClient.Inc(1)
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	resp, batchErr := h.receiver.export(r.Context(), req)
+	if batchErr != nil {
+		status := http.StatusInternalServerError
+		if isBadRequest(batchErr) {
+			status = http.StatusBadRequest
+		}
+
+		logger := logging.WithContext(r.Context(), h.receiver.instrumentOpts)
+		logger.Error("write error",
+			zap.String("remoteAddr", r.RemoteAddr),
+			zap.Int("httpResponseStatusCode", status),
+			zap.Int("numErrors", len(batchErr.Errors())),
+			zap.Error(batchErr.LastError()))
+		xhttp.Error(w, batchErrorMessage(batchErr), status)
+		return
+	}
+
+	data, err := resp.Marshal()
+	if err != nil {
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", protobufContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *MetricsHandler) parseRequest(
+	r *http.Request,
+) (*otlppb.ExportMetricsServiceRequest, *xhttp.ParseError) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != protobufContentType {
+			return nil, xhttp.NewParseError(errUnsupportedContentType,
+				http.StatusUnsupportedMediaType)
+		}
+	}
+
+	if r.Body == nil {
+		return nil, xhttp.NewParseError(errEmptyBody, http.StatusBadRequest)
+	}
+	defer r.Body.Close()
+
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	var req otlppb.ExportMetricsServiceRequest
+	if err := req.Unmarshal(body); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	return &req, nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type writtenSeries struct {
+	tags       map[string]string
+	datapoints ts.Datapoints
+	unit       xtime.Unit
+}
+
+func newTestDownsamplerAndWriter(
+	ctrl *gomock.Controller,
+	written *[]writtenSeries,
+) ingest.DownsamplerAndWriter {
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			for iter.Next() {
+				tags, datapoints, unit := iter.Current()
+				*written = append(*written, writtenSeries{
+					tags:       tagsMap(tags),
+					datapoints: datapoints,
+					unit:       unit,
+				})
+			}
+			return nil
+		}).
+		AnyTimes()
+	return mockDownsamplerAndWriter
+}
+
+func newTestMetricsHandler(
+	t *testing.T,
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+) http.Handler {
+	handler, err := NewMetricsHandler(downsamplerAndWriter,
+		models.NewTagOptions(), Configuration{}, time.Now,
+		instrument.NewOptions())
+	require.NoError(t, err)
+	return handler
+}
+
+func testGaugeRequest(values ...float64) *otlppb.ExportMetricsServiceRequest {
+	dataPoints := make([]*otlppb.NumberDataPoint, 0, len(values))
+	for _, value := range values {
+		dataPoints = append(dataPoints, &otlppb.NumberDataPoint{
+			TimeUnixNano: uint64(time.Unix(1556813561, 0).UnixNano()),
+			Value:        &otlppb.NumberDataPoint_AsDouble{AsDouble: value},
+		})
+	}
+	return testRequest(
+		[]*otlppb.KeyValue{stringAttr("service.name", "api")},
+		&otlppb.Metric{
+			Name: "queue.size",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{DataPoints: dataPoints}},
+		},
+	)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestMetricsHandler(t,
+		newTestDownsamplerAndWriter(ctrl, &written))
+
+	data, err := testGaugeRequest(2.5).Marshal()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	_, err = gzipWriter.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	req := httptest.NewRequest(OTLPMetricsHTTPMethod, OTLPMetricsURL, &buf)
+	req.Header.Set("Content-Type", protobufContentType)
+	req.Header.Set("Content-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, protobufContentType, writer.Header().Get("Content-Type"))
+
+	var resp otlppb.ExportMetricsServiceResponse
+	require.NoError(t, resp.Unmarshal(writer.Body.Bytes()))
+	assert.Nil(t, resp.PartialSuccess)
+
+	require.Equal(t, 1, len(written))
+	assert.Equal(t, map[string]string{
+		"__name__":     "queue_size",
+		"service_name": "api",
+	}, written[0].tags)
+	assert.Equal(t, ts.Datapoints{
+		{Timestamp: time.Unix(1556813561, 0), Value: 2.5},
+	}, written[0].datapoints)
+	assert.Equal(t, xtime.Nanosecond, written[0].unit)
+}
+
+func TestMetricsHandlerPartialSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestMetricsHandler(t,
+		newTestDownsamplerAndWriter(ctrl, &written))
+
+	data, err := testRequest(nil, &otlppb.Metric{Name: "summary"}).Marshal()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(OTLPMetricsHTTPMethod, OTLPMetricsURL,
+		bytes.NewReader(data))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusOK, writer.Code)
+
+	var resp otlppb.ExportMetricsServiceResponse
+	require.NoError(t, resp.Unmarshal(writer.Body.Bytes()))
+	require.NotNil(t, resp.PartialSuccess)
+	assert.Equal(t, int64(1), resp.PartialSuccess.RejectedDataPoints)
+	assert.Contains(t, resp.PartialSuccess.ErrorMessage, "unsupported metric type")
+	assert.Equal(t, 0, len(written))
+}
+
+func TestMetricsHandlerInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	handler := newTestMetricsHandler(t,
+		newTestDownsamplerAndWriter(ctrl, &written))
+
+	req := httptest.NewRequest(OTLPMetricsHTTPMethod, OTLPMetricsURL,
+		bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusUnsupportedMediaType, writer.Code)
+
+	req = httptest.NewRequest(OTLPMetricsHTTPMethod, OTLPMetricsURL,
+		bytes.NewReader([]byte{0xff, 0xff}))
+	writer = httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusBadRequest, writer.Code)
+	assert.Equal(t, 0, len(written))
+}
+
+func TestMetricsHandlerWriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batchErr := ingest.BatchError(xerrors.NewMultiError().Add(errors.New("an error")))
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(batchErr)
+	handler := newTestMetricsHandler(t, mockDownsamplerAndWriter)
+
+	data, err := testGaugeRequest(1).Marshal()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(OTLPMetricsHTTPMethod, OTLPMetricsURL,
+		bytes.NewReader(data))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusInternalServerError, writer.Code)
+	assert.Contains(t, writer.Body.String(), "an error")
+}
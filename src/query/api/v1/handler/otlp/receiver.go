@@ -0,0 +1,187 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errNoDownsamplerAndWriter = errors.New("no downsampler and writer set")
+	errNoTagOptions           = errors.New("no tag options set")
+	errNoNowFn                = errors.New("no now fn set")
+)
+
+// receiver converts OTLP export requests and writes them, it is shared by
+// the OTLP/HTTP and OTLP/gRPC receivers.
+type receiver struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	converter            converter
+	instrumentOpts       instrument.Options
+	metrics              receiverMetrics
+}
+
+func newReceiver(
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	cfg Configuration,
+	nowFn clock.NowFn,
+	transport string,
+	instrumentOpts instrument.Options,
+) (*receiver, error) {
+	if downsamplerAndWriter == nil {
+		return nil, errNoDownsamplerAndWriter
+	}
+	if tagOptions == nil {
+		return nil, errNoTagOptions
+	}
+	if nowFn == nil {
+		return nil, errNoNowFn
+	}
+
+	return &receiver{
+		downsamplerAndWriter: downsamplerAndWriter,
+		converter:            newConverter(tagOptions, cfg, nowFn),
+		instrumentOpts:       instrumentOpts,
+		metrics: newReceiverMetrics(instrumentOpts.MetricsScope(),
+			transport),
+	}, nil
+}
+
+type receiverMetrics struct {
+	writeSuccess       tally.Counter
+	writeErrorsServer  tally.Counter
+	writeErrorsClient  tally.Counter
+	datapointsWritten  tally.Counter
+	datapointsRejected tally.Counter
+}
+
+func newReceiverMetrics(scope tally.Scope, transport string) receiverMetrics {
+	writeScope := scope.SubScope("otlp-write").Tagged(map[string]string{
+		"transport": transport,
+	})
+	return receiverMetrics{
+		writeSuccess:       writeScope.Counter("success"),
+		writeErrorsServer:  writeScope.Tagged(map[string]string{"code": "5XX"}).Counter("errors"),
+		writeErrorsClient:  writeScope.Tagged(map[string]string{"code": "4XX"}).Counter("errors"),
+		datapointsWritten:  writeScope.Counter("datapoints-written"),
+		datapointsRejected: writeScope.Counter("datapoints-rejected"),
+	}
+}
+
+// export writes the metrics in the request, data points that cannot be
+// converted are reported as rejected in the partial success of the response
+// rather than failing the whole request.
+func (r *receiver) export(
+	ctx context.Context,
+	req *otlppb.ExportMetricsServiceRequest,
+) (*otlppb.ExportMetricsServiceResponse, ingest.BatchError) {
+	result := r.converter.convert(req)
+	if len(result.tags) > 0 {
+		iter := newSeriesIter(result.tags, result.datapoints)
+		batchErr := r.downsamplerAndWriter.WriteBatch(ctx, iter,
+			ingest.WriteOptions{})
+		if batchErr != nil {
+			if isBadRequest(batchErr) {
+				r.metrics.writeErrorsClient.Inc(1)
+			} else {
+				r.metrics.writeErrorsServer.Inc(1)
+			}
+			return nil, batchErr
+		}
+		r.metrics.datapointsWritten.Inc(int64(len(result.tags)))
+	}
+
+	r.metrics.writeSuccess.Inc(1)
+	resp := &otlppb.ExportMetricsServiceResponse{}
+	if result.rejected > 0 {
+		r.metrics.datapointsRejected.Inc(result.rejected)
+		resp.PartialSuccess = &otlppb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: result.rejected,
+			ErrorMessage:       result.lastErr.Error(),
+		}
+	}
+	return resp, nil
+}
+
+func isBadRequest(batchErr ingest.BatchError) bool {
+	for _, err := range batchErr.Errors() {
+		if !client.IsBadRequestError(err) && !xerrors.IsInvalidParams(err) {
+			return false
+		}
+	}
+	return true
+}
+
+func batchErrorMessage(batchErr ingest.BatchError) error {
+	return fmt.Errorf("write errors: count=%d, last=%v",
+		len(batchErr.Errors()), batchErr.LastError())
+}
+
+type seriesIter struct {
+	idx        int
+	tags       []models.Tags
+	datapoints []ts.Datapoints
+}
+
+func newSeriesIter(tags []models.Tags, datapoints []ts.Datapoints) *seriesIter {
+	return &seriesIter{
+		idx:        -1,
+		tags:       tags,
+		datapoints: datapoints,
+	}
+}
+
+func (i *seriesIter) Next() bool {
+	i.idx++
+	return i.idx < len(i.tags)
+}
+
+func (i *seriesIter) Current() (models.Tags, ts.Datapoints, xtime.Unit) {
+	if len(i.tags) == 0 || i.idx < 0 || i.idx >= len(i.tags) {
+		return models.EmptyTags(), nil, 0
+	}
+
+	return i.tags[i.idx], i.datapoints[i.idx], xtime.Nanosecond
+}
+
+func (i *seriesIter) Reset() error {
+	i.idx = -1
+	return nil
+}
+
+func (i *seriesIter) Error() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"go.uber.org/zap"
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type metricsService struct {
+	receiver *receiver
+}
+
+// NewMetricsService returns a new OTLP/gRPC metrics service.
+func NewMetricsService(
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	cfg Configuration,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) (otlppb.MetricsServiceServer, error) {
+	receiver, err := newReceiver(downsamplerAndWriter, tagOptions, cfg,
+		nowFn, "grpc", instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricsService{receiver: receiver}, nil
+}
+
+func (s *metricsService) Export(
+	ctx xnetcontext.Context,
+	req *otlppb.ExportMetricsServiceRequest,
+) (*otlppb.ExportMetricsServiceResponse, error) {
+	resp, batchErr := s.receiver.export(ctx, req)
+	if batchErr != nil {
+		code := codes.Unavailable
+		if isBadRequest(batchErr) {
+			code = codes.InvalidArgument
+		}
+
+		logger := logging.WithContext(ctx, s.receiver.instrumentOpts)
+		logger.Error("write error",
+			zap.Int("numErrors", len(batchErr.Errors())),
+			zap.Error(batchErr.LastError()))
+		return nil, grpc.Errorf(code, "%s", batchErrorMessage(batchErr).Error())
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMetricsServiceExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var written []writtenSeries
+	service, err := NewMetricsService(newTestDownsamplerAndWriter(ctrl, &written),
+		models.NewTagOptions(), Configuration{}, time.Now,
+		instrument.NewOptions())
+	require.NoError(t, err)
+
+	resp, err := service.Export(context.Background(), testGaugeRequest(1, 2))
+	require.NoError(t, err)
+	assert.Nil(t, resp.PartialSuccess)
+
+	require.Equal(t, 2, len(written))
+	assert.Equal(t, "queue_size", written[0].tags["__name__"])
+	assert.Equal(t, 2.0, written[1].datapoints[0].Value)
+}
+
+func TestMetricsServiceExportBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batchErr := ingest.BatchError(xerrors.NewMultiError().
+		Add(xerrors.NewInvalidParamsError(errors.New("bad tags"))))
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(batchErr)
+
+	service, err := NewMetricsService(mockDownsamplerAndWriter,
+		models.NewTagOptions(), Configuration{}, time.Now,
+		instrument.NewOptions())
+	require.NoError(t, err)
+
+	_, err = service.Export(context.Background(), testGaugeRequest(1))
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Contains(t, grpc.ErrorDesc(err), "bad tags")
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ActiveQueriesURL is the url for listing and cancelling the queries
+	// currently executing.
+	ActiveQueriesURL = handler.RoutePrefixV1 + "/debug/active_queries"
+
+	queryIDParam = "id"
+)
+
+var (
+	// ActiveQueriesHTTPMethods are the HTTP methods used with this resource.
+	ActiveQueriesHTTPMethods = []string{http.MethodGet, http.MethodDelete}
+
+	errNoQueryID = errors.New("no query id provided")
+)
+
+// ActiveQueriesHandler lists the queries currently executing in the engine,
+// and cancels a query by ID on DELETE.
+type ActiveQueriesHandler struct {
+	engine         executor.Engine
+	nowFn          func() time.Time
+	instrumentOpts instrument.Options
+}
+
+// NewActiveQueriesHandler returns a new instance of handler.
+func NewActiveQueriesHandler(
+	engine executor.Engine,
+	nowFn func() time.Time,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &ActiveQueriesHandler{
+		engine:         engine,
+		nowFn:          nowFn,
+		instrumentOpts: instrumentOpts,
+	}
+}
+
+type activeQuery struct {
+	ID              string    `json:"id"`
+	Query           string    `json:"query"`
+	Start           time.Time `json:"start"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Caller          string    `json:"caller"`
+	Cost            float64   `json:"cost"`
+}
+
+type activeQueriesResponse struct {
+	Status string        `json:"status"`
+	Data   []activeQuery `json:"data"`
+}
+
+type cancelQueryResponse struct {
+	Status string `json:"status"`
+}
+
+func (h *ActiveQueriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	if r.Method == http.MethodDelete {
+		h.cancel(w, r, logger)
+		return
+	}
+
+	var (
+		now     = h.nowFn()
+		queries = h.engine.ActiveQueries()
+		data    = make([]activeQuery, 0, len(queries))
+	)
+	for _, q := range queries {
+		data = append(data, activeQuery{
+			ID:              q.ID,
+			Query:           q.Query,
+			Start:           q.Start,
+			DurationSeconds: now.Sub(q.Start).Seconds(),
+			Caller:          q.Caller,
+			Cost:            float64(q.Cost),
+		})
+	}
+
+	xhttp.WriteJSONResponse(w, activeQueriesResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+func (h *ActiveQueriesHandler) cancel(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+) {
+	id := r.FormValue(queryIDParam)
+	if id == "" {
+		xhttp.Error(w, errNoQueryID, http.StatusBadRequest)
+		return
+	}
+
+	if !h.engine.CancelQuery(id) {
+		xhttp.Error(w, fmt.Errorf("no active query with id: %s", id),
+			http.StatusNotFound)
+		return
+	}
+
+	logger.Info("cancelled active query", zap.String("id", id))
+	xhttp.WriteJSONResponse(w, cancelQueryResponse{Status: "success"}, logger)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveQueriesHandlerList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	engine := executor.NewMockEngine(ctrl)
+	engine.EXPECT().ActiveQueries().Return([]executor.ActiveQuery{
+		{
+			ID:     "1",
+			Query:  "sum(foo)",
+			Start:  now.Add(-3 * time.Second),
+			Caller: "10.0.0.1:1234",
+			Cost:   42,
+		},
+	})
+
+	h := NewActiveQueriesHandler(engine, func() time.Time { return now },
+		instrument.NewOptions())
+	req := httptest.NewRequest(http.MethodGet, ActiveQueriesURL, nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp activeQueriesResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, activeQueriesResponse{
+		Status: "success",
+		Data: []activeQuery{
+			{
+				ID:              "1",
+				Query:           "sum(foo)",
+				Start:           now.Add(-3 * time.Second),
+				DurationSeconds: 3,
+				Caller:          "10.0.0.1:1234",
+				Cost:            42,
+			},
+		},
+	}, resp)
+}
+
+func TestActiveQueriesHandlerCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := executor.NewMockEngine(ctrl)
+	engine.EXPECT().CancelQuery("1").Return(true)
+	engine.EXPECT().CancelQuery("2").Return(false)
+
+	h := NewActiveQueriesHandler(engine, time.Now, instrument.NewOptions())
+	for _, tt := range []struct {
+		id   string
+		code int
+	}{
+		{id: "1", code: http.StatusOK},
+		{id: "2", code: http.StatusNotFound},
+		{id: "", code: http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodDelete,
+			ActiveQueriesURL+"?id="+tt.id, nil)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		assert.Equal(t, tt.code, recorder.Code, tt.id)
+	}
+}
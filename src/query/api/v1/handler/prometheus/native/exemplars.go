@@ -0,0 +1,187 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage/exemplar"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// QueryExemplarsURL is the url for querying exemplars.
+	QueryExemplarsURL = handler.RoutePrefixV1 + "/query_exemplars"
+)
+
+var (
+	// QueryExemplarsHTTPMethods are the HTTP methods used with this resource.
+	QueryExemplarsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	errQueryExemplarsNoQuery = errors.New("no query specified")
+)
+
+// QueryExemplarsHandler returns the exemplars of the series selected by a
+// query in the same shape as the Prometheus query exemplars API.
+// NB: exemplars are kept in memory by each coordinator, so only exemplars
+// written to the coordinator serving the query are returned.
+type QueryExemplarsHandler struct {
+	store          exemplar.Store
+	tagOptions     models.TagOptions
+	nowFn          clock.NowFn
+	instrumentOpts instrument.Options
+}
+
+// NewQueryExemplarsHandler returns a new instance of handler, all queries
+// return no exemplars if the store is nil.
+func NewQueryExemplarsHandler(
+	store exemplar.Store,
+	tagOptions models.TagOptions,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &QueryExemplarsHandler{
+		store:          store,
+		tagOptions:     tagOptions,
+		nowFn:          nowFn,
+		instrumentOpts: instrumentOpts,
+	}
+}
+
+type exemplarEntry struct {
+	Labels    map[string]string `json:"labels"`
+	Value     string            `json:"value"`
+	Timestamp float64           `json:"timestamp"`
+}
+
+type exemplarSeries struct {
+	SeriesLabels map[string]string `json:"seriesLabels"`
+	Exemplars    []exemplarEntry   `json:"exemplars"`
+}
+
+type queryExemplarsResponse struct {
+	Status string           `json:"status"`
+	Data   []exemplarSeries `json:"data"`
+}
+
+func (h *QueryExemplarsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	query := r.FormValue("query")
+	if query == "" {
+		xhttp.Error(w, errQueryExemplarsNoQuery, http.StatusBadRequest)
+		return
+	}
+
+	matchers, err := promql.ParseSelectorMatchers(query, h.tagOptions)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	start, end, err := h.parseRange(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	data := []exemplarSeries{}
+	if h.store != nil {
+		result, err := h.store.Query(start, end, matchers)
+		if err != nil {
+			logger.Error("unable to query exemplars", zap.Error(err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		for _, series := range result {
+			exemplars := make([]exemplarEntry, 0, len(series.Exemplars))
+			for _, e := range series.Exemplars {
+				exemplars = append(exemplars, exemplarEntry{
+					Labels:    tagsToMap(e.Labels),
+					Value:     strconv.FormatFloat(e.Value, 'f', -1, 64),
+					Timestamp: float64(e.Timestamp.UnixNano()) / float64(time.Second),
+				})
+			}
+
+			data = append(data, exemplarSeries{
+				SeriesLabels: tagsToMap(series.Tags),
+				Exemplars:    exemplars,
+			})
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, queryExemplarsResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+func (h *QueryExemplarsHandler) parseRange(
+	r *http.Request,
+) (time.Time, time.Time, error) {
+	var (
+		start = time.Unix(0, 0)
+		end   = h.nowFn()
+		err   error
+	)
+
+	if v := r.FormValue("start"); v != "" {
+		if start, err = util.ParseTimeString(v); err != nil {
+			return start, end, err
+		}
+	}
+
+	if v := r.FormValue("end"); v != "" {
+		if end, err = util.ParseTimeString(v); err != nil {
+			return start, end, err
+		}
+	}
+
+	if end.Before(start) {
+		return start, end, errors.New("end must not be before start")
+	}
+
+	return start, end, nil
+}
+
+func tagsToMap(tags models.Tags) map[string]string {
+	result := make(map[string]string, tags.Len())
+	for _, tag := range tags.Tags {
+		result[string(tag.Name)] = string(tag.Value)
+	}
+
+	return result
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/exemplar"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryExemplars(t *testing.T) {
+	var (
+		now      = time.Unix(1000, 0)
+		tagOpts  = models.NewTagOptions()
+		store    = exemplar.NewStore(10)
+		exLabels = models.NewTags(1, tagOpts).AddTag(models.Tag{
+			Name:  []byte("trace_id"),
+			Value: []byte("abc"),
+		})
+	)
+
+	for _, job := range []string{"a", "b"} {
+		tags := models.NewTags(2, tagOpts).
+			SetName([]byte("http_requests_total")).
+			AddTag(models.Tag{Name: []byte("job"), Value: []byte(job)})
+		require.NoError(t, store.Add(tags, exemplar.Exemplar{
+			Labels:    exLabels,
+			Value:     1.5,
+			Timestamp: now.Add(-time.Second),
+		}))
+	}
+
+	h := NewQueryExemplarsHandler(store, tagOpts,
+		func() time.Time { return now }, instrument.NewOptions())
+
+	query := url.QueryEscape(`rate(http_requests_total{job="a"}[1m])`)
+	req := httptest.NewRequest(http.MethodGet,
+		QueryExemplarsURL+"?query="+query+"&start=990", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp queryExemplarsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []exemplarSeries{{
+		SeriesLabels: map[string]string{
+			"__name__": "http_requests_total",
+			"job":      "a",
+		},
+		Exemplars: []exemplarEntry{{
+			Labels:    map[string]string{"trace_id": "abc"},
+			Value:     "1.5",
+			Timestamp: 999,
+		}},
+	}}, resp.Data)
+
+	// Exemplars outside of the range are not returned.
+	req = httptest.NewRequest(http.MethodGet,
+		QueryExemplarsURL+"?query="+query+"&end=990", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = queryExemplarsResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []exemplarSeries{}, resp.Data)
+}
+
+func TestQueryExemplarsNoQuery(t *testing.T) {
+	h := NewQueryExemplarsHandler(nil, models.NewTagOptions(),
+		time.Now, instrument.NewOptions())
+
+	req := httptest.NewRequest(http.MethodGet, QueryExemplarsURL, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
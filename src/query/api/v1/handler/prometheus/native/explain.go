@@ -0,0 +1,277 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ExplainURL is the url for explaining how a query is planned and,
+	// optionally, executed.
+	ExplainURL = handler.RoutePrefixV1 + "/query/explain"
+
+	// ExplainHTTPMethod is the HTTP method used with this resource.
+	ExplainHTTPMethod = http.MethodGet
+
+	analyzeParam = "analyze"
+)
+
+// ExplainHandler returns the physical plan of a PromQL query, annotated
+// with the namespaces and estimated series of each fetch. In analyze mode
+// the query is also executed and each node is annotated with its execution
+// statistics.
+type ExplainHandler struct {
+	engine              executor.Engine
+	fetchOptionsBuilder handler.FetchOptionsBuilder
+	tagOpts             models.TagOptions
+	timeoutOpts         *prometheus.TimeoutOpts
+	instrumentOpts      instrument.Options
+}
+
+// NewExplainHandler returns a new instance of handler.
+func NewExplainHandler(
+	engine executor.Engine,
+	fetchOptionsBuilder handler.FetchOptionsBuilder,
+	tagOpts models.TagOptions,
+	timeoutOpts *prometheus.TimeoutOpts,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &ExplainHandler{
+		engine:              engine,
+		fetchOptionsBuilder: fetchOptionsBuilder,
+		tagOpts:             tagOpts,
+		timeoutOpts:         timeoutOpts,
+		instrumentOpts:      instrumentOpts,
+	}
+}
+
+type explainTimeSpec struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Step  string    `json:"step"`
+}
+
+type explainNamespace struct {
+	Storage     string `json:"storage"`
+	Namespace   string `json:"namespace,omitempty"`
+	MetricsType string `json:"metricsType,omitempty"`
+	Retention   string `json:"retention,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+	Coverage    string `json:"coverage,omitempty"`
+}
+
+type explainStats struct {
+	DurationSeconds float64 `json:"durationSeconds"`
+	Blocks          *int    `json:"blocks,omitempty"`
+	Datapoints      int64   `json:"datapoints"`
+	PeakDatapoints  int64   `json:"peakDatapoints"`
+	PeakMemoryBytes int64   `json:"peakMemoryBytes"`
+	DatapointsLimit *int64  `json:"datapointsLimit,omitempty"`
+}
+
+type explainNode struct {
+	ID              string             `json:"id"`
+	OpType          string             `json:"opType"`
+	Op              string             `json:"op"`
+	Namespaces      []explainNamespace `json:"namespaces,omitempty"`
+	EstimatedSeries *int               `json:"estimatedSeries,omitempty"`
+	SeriesLimited   bool               `json:"seriesLimited,omitempty"`
+	Stats           *explainStats      `json:"stats,omitempty"`
+	Inputs          []explainNode      `json:"inputs,omitempty"`
+}
+
+type explainData struct {
+	Query    string          `json:"query"`
+	Analyzed bool            `json:"analyzed"`
+	TimeSpec explainTimeSpec `json:"timeSpec"`
+	Stats    *explainStats   `json:"stats,omitempty"`
+	Plan     explainNode     `json:"plan"`
+}
+
+type explainResponse struct {
+	Status string      `json:"status"`
+	Data   explainData `json:"data"`
+}
+
+func (h *ExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	fetchOpts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r)
+	if rErr != nil {
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	params, rErr := h.parseParams(r, fetchOpts)
+	if rErr != nil {
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	analyze := false
+	if v := r.FormValue(analyzeParam); v != "" {
+		var err error
+		analyze, err = strconv.ParseBool(v)
+		if err != nil {
+			xhttp.Error(w, fmt.Errorf(formatErrStr, analyzeParam, err),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	queryOpts := &executor.QueryOptions{
+		QueryContextOptions: models.QueryContextOptions{
+			LimitMaxTimeseries: fetchOpts.Limit,
+		},
+		Caller: r.RemoteAddr,
+	}
+	if restrictOpts := fetchOpts.RestrictFetchOptions; restrictOpts != nil {
+		restrict := &models.RestrictFetchTypeQueryContextOptions{
+			MetricsType:   uint(restrictOpts.MetricsType),
+			StoragePolicy: restrictOpts.StoragePolicy,
+		}
+		queryOpts.QueryContextOptions.RestrictFetchType = restrict
+	}
+
+	parser, err := promql.Parse(params.Query, h.tagOpts)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, params.Timeout)
+	defer cancel()
+
+	explanation, err := h.engine.ExplainExpr(ctx, parser, queryOpts,
+		fetchOpts, params, analyze)
+	if err != nil {
+		logger.Error("unable to explain query", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	data := explainData{
+		Query:    params.Query,
+		Analyzed: analyze,
+		TimeSpec: explainTimeSpec{
+			Start: explanation.TimeSpec.Start,
+			End:   explanation.TimeSpec.End,
+			Step:  explanation.TimeSpec.Step.String(),
+		},
+		Plan: newExplainNode(explanation.Root),
+	}
+	if s := explanation.Stats; s != nil {
+		data.Stats = &explainStats{
+			DurationSeconds: s.Duration.Seconds(),
+			Datapoints:      s.Datapoints,
+			PeakDatapoints:  s.PeakDatapoints,
+			PeakMemoryBytes: s.PeakMemoryBytes(),
+		}
+		if s.DatapointsLimit > 0 {
+			limit := s.DatapointsLimit
+			data.Stats.DatapointsLimit = &limit
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, explainResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+// parseParams parses range query params if a start is set, otherwise
+// instantaneous query params.
+func (h *ExplainHandler) parseParams(
+	r *http.Request,
+	fetchOpts *storage.FetchOptions,
+) (models.RequestParams, *xhttp.ParseError) {
+	if r.FormValue(startParam) != "" {
+		return parseParams(r, h.engine.Options(), h.timeoutOpts,
+			fetchOpts, h.instrumentOpts)
+	}
+
+	return parseInstantaneousParams(r, h.engine.Options(), h.timeoutOpts,
+		fetchOpts, h.instrumentOpts)
+}
+
+func newExplainNode(node executor.ExplainNode) explainNode {
+	result := explainNode{
+		ID:     string(node.ID),
+		OpType: node.OpType,
+		Op:     node.Op,
+	}
+
+	for _, n := range node.Namespaces {
+		ns := explainNamespace{
+			Storage:   n.Storage,
+			Namespace: n.Namespace,
+			Coverage:  n.Coverage,
+		}
+		if n.Namespace != "" {
+			ns.MetricsType = n.Attributes.MetricsType.String()
+			ns.Retention = n.Attributes.Retention.String()
+			ns.Resolution = n.Attributes.Resolution.String()
+		}
+
+		result.Namespaces = append(result.Namespaces, ns)
+	}
+
+	if node.Fetch {
+		estimated := node.EstimatedSeries
+		result.EstimatedSeries = &estimated
+		result.SeriesLimited = node.SeriesLimited
+	}
+
+	if s := node.Stats; s != nil {
+		blocks := s.Blocks
+		result.Stats = &explainStats{
+			DurationSeconds: s.Duration.Seconds(),
+			Blocks:          &blocks,
+			Datapoints:      s.Datapoints,
+			PeakDatapoints:  s.PeakDatapoints,
+			PeakMemoryBytes: s.PeakMemoryBytes(),
+		}
+	}
+
+	for _, input := range node.Inputs {
+		result.Inputs = append(result.Inputs, newExplainNode(input))
+	}
+
+	return result
+}
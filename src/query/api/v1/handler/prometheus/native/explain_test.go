@@ -0,0 +1,116 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExplainTestSetup() *testSetup {
+	values, bounds := test.GenerateValuesAndBounds(nil, nil)
+	setup := newTestSetup()
+	setup.Storage.SetSearchSeriesResult(&storage.SearchResults{
+		Metrics: models.Metrics{{ID: []byte("dummy0")}, {ID: []byte("dummy1")}},
+	}, nil)
+	setup.Storage.SetFetchBlocksResult(block.Result{
+		Blocks: []block.Block{test.NewBlockFromValues(bounds, values)},
+	}, nil)
+	return setup
+}
+
+func serveExplain(t *testing.T, setup *testSetup, analyze string) (int, explainResponse) {
+	params := defaultParams()
+	params.Set(queryParam, "sum(dummy0{})")
+	if analyze != "" {
+		params.Set(analyzeParam, analyze)
+	}
+
+	req := httptest.NewRequest(ExplainHTTPMethod, ExplainURL, nil)
+	req.URL.RawQuery = params.Encode()
+	recorder := httptest.NewRecorder()
+	setup.Handlers.Explain.ServeHTTP(recorder, req)
+
+	var resp explainResponse
+	if recorder.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	}
+
+	return recorder.Code, resp
+}
+
+func TestExplainHandler(t *testing.T) {
+	code, resp := serveExplain(t, newExplainTestSetup(), "")
+	require.Equal(t, http.StatusOK, code)
+
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, "sum(dummy0{})", resp.Data.Query)
+	assert.False(t, resp.Data.Analyzed)
+	assert.Nil(t, resp.Data.Stats)
+	assert.Equal(t, "10s", resp.Data.TimeSpec.Step)
+
+	plan := resp.Data.Plan
+	assert.Equal(t, "sum", plan.OpType)
+	assert.Nil(t, plan.EstimatedSeries)
+	assert.Nil(t, plan.Stats)
+	require.Equal(t, 1, len(plan.Inputs))
+
+	fetch := plan.Inputs[0]
+	assert.Equal(t, "fetch", fetch.OpType)
+	require.NotNil(t, fetch.EstimatedSeries)
+	assert.Equal(t, 2, *fetch.EstimatedSeries)
+	assert.Nil(t, fetch.Stats)
+}
+
+func TestExplainHandlerAnalyze(t *testing.T) {
+	code, resp := serveExplain(t, newExplainTestSetup(), "true")
+	require.Equal(t, http.StatusOK, code)
+
+	assert.True(t, resp.Data.Analyzed)
+	require.NotNil(t, resp.Data.Stats)
+	assert.Nil(t, resp.Data.Stats.Blocks)
+
+	plan := resp.Data.Plan
+	require.NotNil(t, plan.Stats)
+	require.NotNil(t, plan.Stats.Blocks)
+	assert.Equal(t, 1, *plan.Stats.Blocks)
+	assert.NotZero(t, plan.Stats.Datapoints)
+	assert.Equal(t, plan.Stats.PeakDatapoints*8, plan.Stats.PeakMemoryBytes)
+
+	require.Equal(t, 1, len(plan.Inputs))
+	require.NotNil(t, plan.Inputs[0].Stats)
+	assert.Equal(t, 1, *plan.Inputs[0].Stats.Blocks)
+}
+
+func TestExplainHandlerInvalidAnalyze(t *testing.T) {
+	code, _ := serveExplain(t, newExplainTestSetup(), "maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
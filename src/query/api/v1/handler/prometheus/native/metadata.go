@@ -0,0 +1,244 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage/metadata"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MetadataURL is the url for the metadata of metric families.
+	MetadataURL = handler.RoutePrefixV1 + "/metadata"
+
+	// TargetsMetadataURL is the url for the metadata of metric families
+	// by target.
+	TargetsMetadataURL = handler.RoutePrefixV1 + "/targets/metadata"
+
+	// MetadataHTTPMethod is the HTTP method used with these resources.
+	MetadataHTTPMethod = http.MethodGet
+)
+
+// MetadataHandler returns the metadata of metric families written with
+// Prometheus remote write in the same shape as the Prometheus metadata API.
+type MetadataHandler struct {
+	store          metadata.Store
+	instrumentOpts instrument.Options
+}
+
+// NewMetadataHandler returns a new instance of handler.
+func NewMetadataHandler(
+	store metadata.Store,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &MetadataHandler{
+		store:          store,
+		instrumentOpts: instrumentOpts,
+	}
+}
+
+type metadataEntry struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+type metadataResponse struct {
+	Status string                     `json:"status"`
+	Data   map[string][]metadataEntry `json:"data"`
+}
+
+func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	limit, err := parseMetadataLimit(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	names, result, err := h.metadata(r.FormValue("metric"))
+	if err != nil {
+		logger.Error("unable to fetch metadata", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	data := make(map[string][]metadataEntry, len(names))
+	for _, name := range names {
+		if limit > 0 && len(data) >= limit {
+			break
+		}
+
+		m := result[name]
+		data[name] = []metadataEntry{{Type: m.Type, Help: m.Help, Unit: m.Unit}}
+	}
+
+	xhttp.WriteJSONResponse(w, metadataResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+// metadata returns the metadata of all metric families, or only of the
+// given metric family if set, and their names in sorted order.
+func (h *MetadataHandler) metadata(
+	metric string,
+) ([]string, map[string]metadata.Metadata, error) {
+	result, err := h.store.Metadata()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(result))
+	for name := range result {
+		if metric == "" || name == metric {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, result, nil
+}
+
+// TargetsMetadataHandler returns the metadata of metric families in the same
+// shape as the Prometheus targets metadata API.
+// NB: metadata is written with Prometheus remote write which does not
+// include the targets it was scraped from, so all metadata is returned with
+// an empty target and a target matcher only matches if it matches an empty
+// set of labels.
+type TargetsMetadataHandler struct {
+	metadataHandler *MetadataHandler
+	tagOptions      models.TagOptions
+	instrumentOpts  instrument.Options
+}
+
+// NewTargetsMetadataHandler returns a new instance of handler.
+func NewTargetsMetadataHandler(
+	store metadata.Store,
+	tagOptions models.TagOptions,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &TargetsMetadataHandler{
+		metadataHandler: &MetadataHandler{
+			store:          store,
+			instrumentOpts: instrumentOpts,
+		},
+		tagOptions:     tagOptions,
+		instrumentOpts: instrumentOpts,
+	}
+}
+
+type targetMetadataEntry struct {
+	Target map[string]string `json:"target"`
+	Metric string            `json:"metric"`
+	Type   string            `json:"type"`
+	Help   string            `json:"help"`
+	Unit   string            `json:"unit"`
+}
+
+type targetsMetadataResponse struct {
+	Status string                `json:"status"`
+	Data   []targetMetadataEntry `json:"data"`
+}
+
+func (h *TargetsMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	limit, err := parseMetadataLimit(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	matchTarget := true
+	if v := r.FormValue("match_target"); v != "" {
+		matchers, err := promql.ParseSelectorMatchers(v, h.tagOptions)
+		if err != nil {
+			xhttp.Error(w, err, http.StatusBadRequest)
+			return
+		}
+
+		empty := models.NewTags(0, h.tagOptions)
+		for _, m := range matchers {
+			matchTarget = matchTarget && m.MatchesTags(empty)
+		}
+	}
+
+	data := []targetMetadataEntry{}
+	if matchTarget {
+		names, result, err := h.metadataHandler.metadata(r.FormValue("metric"))
+		if err != nil {
+			logger.Error("unable to fetch metadata", zap.Error(err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		for _, name := range names {
+			if limit > 0 && len(data) >= limit {
+				break
+			}
+
+			m := result[name]
+			data = append(data, targetMetadataEntry{
+				Target: map[string]string{},
+				Metric: name,
+				Type:   m.Type,
+				Help:   m.Help,
+				Unit:   m.Unit,
+			})
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, targetsMetadataResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+func parseMetadataLimit(r *http.Request) (int, error) {
+	v := r.FormValue("limit")
+	if v == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid limit: %s", v)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/metadata"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetadataStore(t *testing.T) metadata.Store {
+	store, err := metadata.NewKVStore(mem.NewStore(), "", instrument.NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, store.Update([]*prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricMetadata_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total number of HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricMetadata_GAUGE,
+			MetricFamilyName: "memory_usage",
+			Help:             "Memory usage.",
+			Unit:             "bytes",
+		},
+	}))
+	return store
+}
+
+func TestMetadata(t *testing.T) {
+	h := NewMetadataHandler(newTestMetadataStore(t), instrument.NewOptions())
+
+	req := httptest.NewRequest(MetadataHTTPMethod, MetadataURL, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp metadataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, map[string][]metadataEntry{
+		"http_requests_total": {{Type: "counter", Help: "Total number of HTTP requests."}},
+		"memory_usage":        {{Type: "gauge", Help: "Memory usage.", Unit: "bytes"}},
+	}, resp.Data)
+
+	req = httptest.NewRequest(MetadataHTTPMethod, MetadataURL+"?limit=1", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = metadataResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string][]metadataEntry{
+		"http_requests_total": {{Type: "counter", Help: "Total number of HTTP requests."}},
+	}, resp.Data)
+
+	req = httptest.NewRequest(MetadataHTTPMethod, MetadataURL+"?metric=memory_usage", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = metadataResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string][]metadataEntry{
+		"memory_usage": {{Type: "gauge", Help: "Memory usage.", Unit: "bytes"}},
+	}, resp.Data)
+}
+
+func TestMetadataInvalidLimit(t *testing.T) {
+	h := NewMetadataHandler(newTestMetadataStore(t), instrument.NewOptions())
+
+	req := httptest.NewRequest(MetadataHTTPMethod, MetadataURL+"?limit=-1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTargetsMetadata(t *testing.T) {
+	h := NewTargetsMetadataHandler(newTestMetadataStore(t),
+		models.NewTagOptions(), instrument.NewOptions())
+
+	req := httptest.NewRequest(MetadataHTTPMethod,
+		TargetsMetadataURL+"?metric=memory_usage", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp targetsMetadataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []targetMetadataEntry{{
+		Target: map[string]string{},
+		Metric: "memory_usage",
+		Type:   "gauge",
+		Help:   "Memory usage.",
+		Unit:   "bytes",
+	}}, resp.Data)
+
+	// No metadata has a target so a target matcher matches no metadata.
+	req = httptest.NewRequest(MetadataHTTPMethod,
+		TargetsMetadataURL+`?match_target={job="prometheus"}`, nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = targetsMetadataResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []targetMetadataEntry{}, resp.Data)
+}
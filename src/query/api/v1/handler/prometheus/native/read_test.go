@@ -126,6 +126,7 @@ type testSetup struct {
 type testSetupHandlers struct {
 	Read        *PromReadHandler
 	InstantRead *PromReadInstantHandler
+	Explain     http.Handler
 }
 
 func newTestSetup() *testSetup {
@@ -150,11 +151,15 @@ func newTestSetup() *testSetup {
 	instantRead := NewPromReadInstantHandler(engine, fetchOptsBuilder,
 		tagOpts, timeoutOpts, instrumentOpts)
 
+	explain := NewExplainHandler(engine, fetchOptsBuilder, tagOpts,
+		timeoutOpts, instrumentOpts)
+
 	return &testSetup{
 		Storage: mockStorage,
 		Handlers: testSetupHandlers{
 			Read:        read,
 			InstantRead: instantRead,
+			Explain:     explain,
 		},
 		QueryOpts:   &executor.QueryOptions{},
 		FetchOpts:   storage.NewFetchOptions(),
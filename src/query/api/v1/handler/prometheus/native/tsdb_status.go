@@ -0,0 +1,213 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TSDBStatusURL is the url for the cardinality statistics of the index.
+	TSDBStatusURL = handler.RoutePrefixV1 + "/status/tsdb"
+
+	// TSDBStatusHTTPMethod is the HTTP method used with this resource.
+	TSDBStatusHTTPMethod = http.MethodGet
+
+	defaultTSDBStatusLimit = 10
+)
+
+var (
+	errTSDBStatusNoClusters     = errors.New("no local m3db clusters configured")
+	errTSDBStatusNoAdminSession = errors.New("session does not support cardinality queries")
+)
+
+// TSDBStatusHandler returns the series cardinality of the unaggregated
+// namespace in the same shape as the Prometheus TSDB status API.
+// NB: counts are approximate, series are counted once per index block they
+// are written to and the largest count of any index block in the queried
+// range is returned.
+type TSDBStatusHandler struct {
+	clusters       m3.Clusters
+	tagOptions     models.TagOptions
+	nowFn          clock.NowFn
+	instrumentOpts instrument.Options
+}
+
+// NewTSDBStatusHandler returns a new instance of handler.
+func NewTSDBStatusHandler(
+	clusters m3.Clusters,
+	tagOptions models.TagOptions,
+	nowFn clock.NowFn,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &TSDBStatusHandler{
+		clusters:       clusters,
+		tagOptions:     tagOptions,
+		nowFn:          nowFn,
+		instrumentOpts: instrumentOpts,
+	}
+}
+
+type tsdbStatusHeadStats struct {
+	NumSeries int64 `json:"numSeries"`
+	MinTime   int64 `json:"minTime"`
+	MaxTime   int64 `json:"maxTime"`
+}
+
+type tsdbStatusEntry struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+type tsdbStatusData struct {
+	HeadStats                   tsdbStatusHeadStats `json:"headStats"`
+	SeriesCountByMetricName     []tsdbStatusEntry   `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []tsdbStatusEntry   `json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []tsdbStatusEntry   `json:"seriesCountByLabelValuePair"`
+	Exhaustive                  bool                `json:"exhaustive"`
+}
+
+type tsdbStatusResponse struct {
+	Status string         `json:"status"`
+	Data   tsdbStatusData `json:"data"`
+}
+
+func (h *TSDBStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	if h.clusters == nil {
+		xhttp.Error(w, errTSDBStatusNoClusters, http.StatusBadRequest)
+		return
+	}
+
+	ns := h.clusters.UnaggregatedClusterNamespace()
+	session, ok := ns.Session().(client.AdminSession)
+	if !ok {
+		xhttp.Error(w, errTSDBStatusNoAdminSession, http.StatusInternalServerError)
+		return
+	}
+
+	opts, err := h.parseOptions(r, ns.Options().Attributes().Retention)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := session.Cardinality(ns.NamespaceID(), opts)
+	if err != nil {
+		logger.Error("unable to query cardinality", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	stats := result.Stats
+	xhttp.WriteJSONResponse(w, tsdbStatusResponse{
+		Status: "success",
+		Data: tsdbStatusData{
+			HeadStats: tsdbStatusHeadStats{
+				NumSeries: stats.NumSeries,
+				MinTime:   opts.StartInclusive.UnixNano() / int64(time.Millisecond),
+				MaxTime:   opts.EndExclusive.UnixNano() / int64(time.Millisecond),
+			},
+			SeriesCountByMetricName:     toTSDBStatusEntries(stats.SeriesCountByMetricName),
+			LabelValueCountByLabelName:  toTSDBStatusEntries(stats.LabelValueCountByLabelName),
+			SeriesCountByLabelValuePair: toTSDBStatusEntries(stats.SeriesCountByLabelValuePair),
+			Exhaustive:                  result.Exhaustive,
+		},
+	}, logger)
+}
+
+func (h *TSDBStatusHandler) parseOptions(
+	r *http.Request,
+	retention time.Duration,
+) (index.CardinalityOptions, error) {
+	var (
+		now  = h.nowFn()
+		opts = index.CardinalityOptions{
+			QueryOptions: index.QueryOptions{
+				StartInclusive: now.Add(-retention),
+				EndExclusive:   now,
+			},
+			TopN:      defaultTSDBStatusLimit,
+			NameField: h.tagOptions.MetricName(),
+		}
+	)
+
+	if v := r.FormValue("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return opts, fmt.Errorf("invalid limit: %s", v)
+		}
+		opts.TopN = limit
+	}
+
+	if v := r.FormValue("start"); v != "" {
+		start, err := util.ParseTimeString(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.StartInclusive = start
+	}
+
+	if v := r.FormValue("end"); v != "" {
+		end, err := util.ParseTimeString(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.EndExclusive = end
+	}
+
+	if !opts.StartInclusive.Before(opts.EndExclusive) {
+		return opts, errors.New("start must be before end")
+	}
+
+	return opts, nil
+}
+
+func toTSDBStatusEntries(entries []index.CardinalityEntry) []tsdbStatusEntry {
+	result := make([]tsdbStatusEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, tsdbStatusEntry{
+			Name:  entry.Name,
+			Value: entry.Value,
+		})
+	}
+	return result
+}
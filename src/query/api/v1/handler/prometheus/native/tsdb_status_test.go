@@ -0,0 +1,110 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSDBStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1000, 0)
+	session := client.NewMockAdminSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics"),
+		Session:     session,
+		Retention:   time.Minute,
+	})
+	require.NoError(t, err)
+
+	session.EXPECT().Cardinality(ident.NewIDMatcher("metrics"), index.CardinalityOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: now.Add(-time.Minute),
+			EndExclusive:   now,
+		},
+		TopN:      3,
+		NameField: []byte("__name__"),
+	}).Return(index.CardinalityQueryResult{
+		Stats: index.CardinalityStats{
+			NumSeries: 2,
+			SeriesCountByMetricName: []index.CardinalityEntry{
+				{Name: "up", Value: 2},
+			},
+		},
+		Exhaustive: true,
+	}, nil)
+
+	h := NewTSDBStatusHandler(clusters, models.NewTagOptions(),
+		func() time.Time { return now }, instrument.NewOptions())
+
+	req := httptest.NewRequest(TSDBStatusHTTPMethod, TSDBStatusURL+"?limit=3", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp tsdbStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, int64(2), resp.Data.HeadStats.NumSeries)
+	assert.Equal(t, int64(940000), resp.Data.HeadStats.MinTime)
+	assert.Equal(t, int64(1000000), resp.Data.HeadStats.MaxTime)
+	assert.Equal(t, []tsdbStatusEntry{{Name: "up", Value: 2}},
+		resp.Data.SeriesCountByMetricName)
+	assert.Equal(t, []tsdbStatusEntry{}, resp.Data.LabelValueCountByLabelName)
+	assert.True(t, resp.Data.Exhaustive)
+}
+
+func TestTSDBStatusInvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics"),
+		Session:     client.NewMockAdminSession(ctrl),
+		Retention:   time.Minute,
+	})
+	require.NoError(t, err)
+
+	h := NewTSDBStatusHandler(clusters, models.NewTagOptions(),
+		time.Now, instrument.NewOptions())
+
+	req := httptest.NewRequest(TSDBStatusHTTPMethod, TSDBStatusURL+"?limit=-1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -78,7 +78,7 @@ func setupServer(t *testing.T) *httptest.Server {
 	// No calls expected on session object
 	lstore, session := m3.NewStorageAndSession(t, ctrl)
 	session.EXPECT().
-		FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+		FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, false, fmt.Errorf("not initialized"))
 	storage := test.NewSlowStorage(lstore, 10*time.Millisecond)
 	promRead := readHandler(storage, timeoutOpts)
@@ -150,7 +150,7 @@ func TestPromReadParsingBad(t *testing.T) {
 func TestPromReadStorageWithFetchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	store, session := m3.NewStorageAndSession(t, ctrl)
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, true, fmt.Errorf("unable to get data"))
 	session.EXPECT().IteratorPools().
 		Return(nil, nil)
@@ -199,7 +199,7 @@ func TestQueryKillOnTimeout(t *testing.T) {
 func TestReadErrorMetricsCount(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, session := m3.NewStorageAndSession(t, ctrl)
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, true, fmt.Errorf("unable to get data"))
 	session.EXPECT().IteratorPools().
 		Return(nil, nil)
@@ -38,6 +38,8 @@ import (
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/exemplar"
+	"github.com/m3db/m3/src/query/storage/metadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/clock"
@@ -77,6 +79,8 @@ var (
 type PromWriteHandler struct {
 	downsamplerAndWriter   ingest.DownsamplerAndWriter
 	tagOptions             models.TagOptions
+	metadataStore          metadata.Store
+	exemplarStore          exemplar.Store
 	forwarding             PromWriteHandlerForwardingOptions
 	forwardTimeout         time.Duration
 	forwardHTTPClient      *http.Client
@@ -103,10 +107,13 @@ type PromWriteHandlerForwardTargetOptions struct {
 	Method string `yaml:"method"`
 }
 
-// NewPromWriteHandler returns a new instance of handler.
+// NewPromWriteHandler returns a new instance of handler. The metric metadata
+// and exemplars of writes are dropped if the respective store is nil.
 func NewPromWriteHandler(
 	downsamplerAndWriter ingest.DownsamplerAndWriter,
 	tagOptions models.TagOptions,
+	metadataStore metadata.Store,
+	exemplarStore exemplar.Store,
 	forwarding PromWriteHandlerForwardingOptions,
 	nowFn clock.NowFn,
 	instrumentOpts instrument.Options,
@@ -146,6 +153,8 @@ func NewPromWriteHandler(
 	return &PromWriteHandler{
 		downsamplerAndWriter:   downsamplerAndWriter,
 		tagOptions:             tagOptions,
+		metadataStore:          metadataStore,
+		exemplarStore:          exemplarStore,
 		forwarding:             forwarding,
 		forwardTimeout:         forwardTimeout,
 		forwardHTTPClient:      xhttp.NewHTTPClient(forwardHTTPOpts),
@@ -167,6 +176,8 @@ type promWriteMetrics struct {
 	forwardErrors        tally.Counter
 	forwardDropped       tally.Counter
 	forwardLatency       tally.Histogram
+	metadataErrors       tally.Counter
+	exemplarsDropped     tally.Counter
 }
 
 func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
@@ -225,6 +236,8 @@ func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
 		forwardErrors:        scope.SubScope("forward").Counter("errors"),
 		forwardDropped:       scope.SubScope("forward").Counter("dropped"),
 		forwardLatency:       scope.SubScope("forward").Histogram("latency", forwardLatencyBuckets),
+		metadataErrors:       scope.SubScope("metadata").Counter("errors"),
+		exemplarsDropped:     scope.SubScope("exemplars").Counter("dropped"),
 	}, nil
 }
 
@@ -272,6 +285,7 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	batchErr := h.write(r.Context(), req, opts)
+	h.storeMetadataAndExemplars(r.Context(), req)
 
 	// Record ingestion delay latency
 	now := h.nowFn()
@@ -414,6 +428,45 @@ func (h *PromWriteHandler) write(
 	return h.downsamplerAndWriter.WriteBatch(ctx, iter, opts)
 }
 
+// storeMetadataAndExemplars stores the metric metadata and exemplars of a
+// write, failures are only logged since they do not affect the samples.
+func (h *PromWriteHandler) storeMetadataAndExemplars(
+	ctx context.Context,
+	r *prompb.WriteRequest,
+) {
+	if h.metadataStore != nil && len(r.Metadata) > 0 {
+		if err := h.metadataStore.Update(r.Metadata); err != nil {
+			h.metrics.metadataErrors.Inc(1)
+			logger := logging.WithContext(ctx, h.instrumentOpts)
+			logger.Error("metadata update error", zap.Error(err))
+		}
+	}
+
+	if h.exemplarStore == nil {
+		return
+	}
+
+	for _, series := range r.Timeseries {
+		if len(series.Exemplars) == 0 {
+			continue
+		}
+
+		tags := storage.PromLabelsToM3Tags(series.Labels, h.tagOptions)
+		for _, e := range series.Exemplars {
+			err := h.exemplarStore.Add(tags, exemplar.Exemplar{
+				Labels:    storage.PromLabelsToM3Tags(e.Labels, h.tagOptions),
+				Value:     e.Value,
+				Timestamp: storage.PromTimestampToTime(e.Timestamp),
+			})
+			if err != nil {
+				// NB: out of order exemplars are expected when multiple
+				// Prometheus replicas write the same series.
+				h.metrics.exemplarsDropped.Inc(1)
+			}
+		}
+	}
+}
+
 func (h *PromWriteHandler) forward(
 	ctx context.Context,
 	request prometheus.ParsePromCompressedRequestResult,
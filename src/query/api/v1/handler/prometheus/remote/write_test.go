@@ -31,12 +31,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/exemplar"
+	"github.com/m3db/m3/src/query/storage/metadata"
 	xclock "github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/instrument"
@@ -52,7 +56,7 @@ func TestPromWriteParsing(t *testing.T) {
 
 	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
 	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions())
 	require.NoError(t, err)
 
@@ -76,7 +80,7 @@ func TestPromWrite(t *testing.T) {
 		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
 
 	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions())
 	require.NoError(t, err)
 
@@ -90,6 +94,66 @@ func TestPromWrite(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestPromWriteMetadataAndExemplars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	metadataStore, err := metadata.NewKVStore(mem.NewStore(), "", instrument.NewOptions())
+	require.NoError(t, err)
+	exemplarStore := exemplar.NewStore(10)
+
+	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
+		models.NewTagOptions(), metadataStore, exemplarStore,
+		PromWriteHandlerForwardingOptions{}, time.Now, instrument.NewOptions())
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Millisecond)
+	promReq := test.GeneratePromWriteRequest()
+	promReq.Timeseries[0].Exemplars = []*prompb.Exemplar{{
+		Labels:    []*prompb.Label{{Name: []byte("trace_id"), Value: []byte("abc")}},
+		Value:     1.0,
+		Timestamp: storage.TimeToPromTimestamp(now),
+	}}
+	promReq.Metadata = []*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_COUNTER,
+		MetricFamilyName: "first",
+		Help:             "The first metric.",
+	}}
+
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	result, err := metadataStore.Metadata()
+	require.NoError(t, err)
+	require.Equal(t, map[string]metadata.Metadata{
+		"first": {Type: "counter", Help: "The first metric."},
+	}, result)
+
+	matcher, err := models.NewMatcher(models.MatchEqual,
+		[]byte("foo"), []byte("bar"))
+	require.NoError(t, err)
+	series, err := exemplarStore.Query(now, now,
+		[]models.Matchers{{matcher}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(series))
+	name, ok := series[0].Tags.Name()
+	require.True(t, ok)
+	require.Equal(t, "first", string(name))
+	require.Equal(t, 1, len(series[0].Exemplars))
+	require.Equal(t, 1.0, series[0].Exemplars[0].Value)
+	require.True(t, now.Equal(series[0].Exemplars[0].Timestamp))
+}
+
 func TestPromWriteError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -103,7 +167,7 @@ func TestPromWriteError(t *testing.T) {
 		Return(batchErr)
 
 	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions())
 	require.NoError(t, err)
 
@@ -135,7 +199,7 @@ func TestWriteErrorMetricCount(t *testing.T) {
 		SetMetricsScope(scope)
 
 	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, iopts)
 	require.NoError(t, err)
 
@@ -162,7 +226,7 @@ func TestWriteDatapointDelayMetric(t *testing.T) {
 		map[string]string{"test": "delay-metric-test"})
 
 	handler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions().SetMetricsScope(scope))
 	require.NoError(t, err)
 
@@ -224,7 +288,7 @@ func TestPromWriteUnaggregatedMetricsWithHeader(t *testing.T) {
 		WriteBatch(gomock.Any(), gomock.Any(), expectedIngestWriteOptions)
 
 	writeHandler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions())
 	require.NoError(t, err)
 
@@ -259,7 +323,7 @@ func TestPromWriteAggregatedMetricsWithHeader(t *testing.T) {
 		WriteBatch(gomock.Any(), gomock.Any(), expectedIngestWriteOptions)
 
 	writeHandler, err := NewPromWriteHandler(mockDownsamplerAndWriter,
-		models.NewTagOptions(), PromWriteHandlerForwardingOptions{},
+		models.NewTagOptions(), nil, nil, PromWriteHandlerForwardingOptions{},
 		time.Now, instrument.NewOptions())
 	require.NoError(t, err)
 
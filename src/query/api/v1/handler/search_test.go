@@ -95,7 +95,7 @@ func searchServer(t *testing.T) *SearchHandler {
 	mockTaggedIDsIter := generateTagIters(ctrl)
 
 	storage, session := m3.NewStorageAndSession(t, ctrl)
-	session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(mockTaggedIDsIter, false, nil).AnyTimes()
 
 	search := NewSearchHandler(storage,
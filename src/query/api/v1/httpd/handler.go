@@ -29,15 +29,19 @@ import (
 	"time"
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/database"
 	"github.com/m3db/m3/src/query/api/v1/handler/graphite"
+	"github.com/m3db/m3/src/query/api/v1/handler/influxdb"
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
 	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
 	"github.com/m3db/m3/src/query/api/v1/handler/openapi"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/placement"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
@@ -49,6 +53,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/storage/metadata"
 	"github.com/m3db/m3/src/query/util/logging"
 	xdebug "github.com/m3db/m3/src/x/debug"
 	"github.com/m3db/m3/src/x/instrument"
@@ -194,8 +199,29 @@ func (h *Handler) RegisterRoutes() error {
 
 	promRemoteReadHandler := remote.NewPromReadHandler(h.engine,
 		h.fetchOptionsBuilder, h.timeoutOpts, keepNans, remoteSourceInstrumentOpts)
+	metadataKVStore, err := h.metadataKVStore()
+	if err != nil {
+		return err
+	}
+	metadataStore, err := metadata.NewKVStore(metadataKVStore, metadata.DefaultKey,
+		h.instrumentOpts)
+	if err != nil {
+		return err
+	}
+	exemplarStore := h.config.Exemplars.NewStore()
 	promRemoteWriteHandler, err := remote.NewPromWriteHandler(h.downsamplerAndWriter,
-		h.tagOptions, h.config.WriteForwarding.PromRemoteWrite, nowFn, remoteSourceInstrumentOpts)
+		h.tagOptions, metadataStore, exemplarStore, h.config.WriteForwarding.PromRemoteWrite,
+		nowFn, remoteSourceInstrumentOpts)
+	if err != nil {
+		return err
+	}
+	influxDBWriteHandler, err := influxdb.NewWriteHandler(h.downsamplerAndWriter,
+		h.tagOptions, h.config.InfluxDB, nowFn, h.instrumentOpts)
+	if err != nil {
+		return err
+	}
+	otlpMetricsHandler, err := otlp.NewMetricsHandler(h.downsamplerAndWriter,
+		h.tagOptions, h.config.OTLP, nowFn, h.instrumentOpts)
 	if err != nil {
 		return err
 	}
@@ -212,6 +238,12 @@ func (h *Handler) RegisterRoutes() error {
 	h.router.HandleFunc(remote.PromWriteURL,
 		panicOnly(promRemoteWriteHandler).ServeHTTP,
 	).Methods(remote.PromWriteHTTPMethod)
+	h.router.HandleFunc(influxdb.InfluxDBWriteURL,
+		panicOnly(influxDBWriteHandler).ServeHTTP,
+	).Methods(influxdb.InfluxDBWriteHTTPMethod)
+	h.router.HandleFunc(otlp.OTLPMetricsURL,
+		panicOnly(otlpMetricsHandler).ServeHTTP,
+	).Methods(otlp.OTLPMetricsHTTPMethod)
 	h.router.HandleFunc(native.PromReadURL,
 		wrapped(nativePromReadHandler).ServeHTTP,
 	).Methods(native.PromReadHTTPMethod)
@@ -219,6 +251,10 @@ func (h *Handler) RegisterRoutes() error {
 		wrapped(native.NewPromReadInstantHandler(h.engine, h.fetchOptionsBuilder,
 			h.tagOptions, h.timeoutOpts, h.instrumentOpts)).ServeHTTP,
 	).Methods(native.PromReadInstantHTTPMethod)
+	h.router.HandleFunc(native.ExplainURL,
+		wrapped(native.NewExplainHandler(h.engine, h.fetchOptionsBuilder,
+			h.tagOptions, h.timeoutOpts, h.instrumentOpts)).ServeHTTP,
+	).Methods(native.ExplainHTTPMethod)
 
 	// Native M3 search and write endpoints
 	h.router.HandleFunc(handler.SearchURL,
@@ -247,6 +283,26 @@ func (h *Handler) RegisterRoutes() error {
 		).Methods(method)
 	}
 
+	// Cardinality endpoint
+	h.router.HandleFunc(native.TSDBStatusURL,
+		wrapped(native.NewTSDBStatusHandler(h.clusters, h.tagOptions,
+			nowFn, h.instrumentOpts)).ServeHTTP,
+	).Methods(native.TSDBStatusHTTPMethod)
+
+	// Metadata and exemplar endpoints
+	h.router.HandleFunc(native.MetadataURL,
+		wrapped(native.NewMetadataHandler(metadataStore,
+			h.instrumentOpts)).ServeHTTP,
+	).Methods(native.MetadataHTTPMethod)
+	h.router.HandleFunc(native.TargetsMetadataURL,
+		wrapped(native.NewTargetsMetadataHandler(metadataStore,
+			h.tagOptions, h.instrumentOpts)).ServeHTTP,
+	).Methods(native.MetadataHTTPMethod)
+	h.router.HandleFunc(native.QueryExemplarsURL,
+		wrapped(native.NewQueryExemplarsHandler(exemplarStore,
+			h.tagOptions, nowFn, h.instrumentOpts)).ServeHTTP,
+	).Methods(native.QueryExemplarsHTTPMethods...)
+
 	// Series match endpoints
 	for _, method := range remote.PromSeriesMatchHTTPMethods {
 		h.router.HandleFunc(remote.PromSeriesMatchURL,
@@ -260,6 +316,10 @@ func (h *Handler) RegisterRoutes() error {
 		wrapped(validator.NewPromDebugHandler(nativePromReadHandler,
 			h.fetchOptionsBuilder, *h.config.LookbackDuration, h.instrumentOpts)).ServeHTTP,
 	).Methods(validator.PromDebugHTTPMethod)
+	h.router.HandleFunc(native.ActiveQueriesURL,
+		wrapped(native.NewActiveQueriesHandler(h.engine, nowFn,
+			h.instrumentOpts)).ServeHTTP,
+	).Methods(native.ActiveQueriesHTTPMethods...)
 
 	// Graphite endpoints
 	h.router.HandleFunc(graphite.ReadURL,
@@ -272,6 +332,26 @@ func (h *Handler) RegisterRoutes() error {
 			h.fetchOptionsBuilder, h.instrumentOpts)).ServeHTTP,
 	).Methods(graphite.FindHTTPMethods...)
 
+	h.router.HandleFunc(graphite.AutoCompleteTagsURL,
+		wrapped(graphite.NewAutoCompleteTagsHandler(h.storage,
+			h.fetchOptionsBuilder, h.instrumentOpts)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
+	h.router.HandleFunc(graphite.AutoCompleteValuesURL,
+		wrapped(graphite.NewAutoCompleteValuesHandler(h.storage,
+			h.fetchOptionsBuilder, h.instrumentOpts)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
+	h.router.HandleFunc(graphite.TagsURL,
+		wrapped(graphite.NewTagsHandler(h.storage,
+			h.fetchOptionsBuilder, h.instrumentOpts)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
+	h.router.HandleFunc(graphite.TagValuesURL,
+		wrapped(graphite.NewTagValuesHandler(h.storage,
+			h.fetchOptionsBuilder, h.instrumentOpts)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
 	if h.clusterClient != nil {
 		placementOpts, err := h.placementOpts()
 		if err != nil {
@@ -320,6 +400,17 @@ func (h *Handler) placementOpts() (placement.HandlerOptions, error) {
 	)
 }
 
+// metadataKVStore returns the KV store the metric metadata is stored in, an
+// in memory store is used when there is no cluster client so that metadata
+// is still available without a KV store, though not shared by coordinators.
+func (h *Handler) metadataKVStore() (kv.Store, error) {
+	if h.clusterClient == nil {
+		return mem.NewStore(), nil
+	}
+
+	return h.clusterClient.KV()
+}
+
 func (h *Handler) m3AggServiceOptions() *handler.M3AggServiceOptions {
 	if h.clusters == nil {
 		return nil
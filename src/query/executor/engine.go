@@ -34,13 +34,16 @@ import (
 )
 
 type engine struct {
-	opts    EngineOptions
-	metrics *engineMetrics
+	opts     EngineOptions
+	metrics  *engineMetrics
+	registry *queryRegistry
 }
 
 // QueryOptions can be used to pass custom flags to engine.
 type QueryOptions struct {
 	QueryContextOptions models.QueryContextOptions
+	// Caller identifies who issued the query in the active query list.
+	Caller string
 }
 
 // Query is the result after execution.
@@ -58,8 +61,9 @@ func NewEngine(
 	}
 
 	return &engine{
-		metrics: newEngineMetrics(engineOpts.InstrumentOptions().MetricsScope()),
-		opts:    engineOpts,
+		metrics:  newEngineMetrics(engineOpts.InstrumentOptions().MetricsScope()),
+		opts:     engineOpts,
+		registry: newQueryRegistry(),
 	}
 }
 
@@ -126,21 +130,23 @@ func (e *engine) ExecuteExpr(
 	params models.RequestParams,
 ) (Result, error) {
 	perQueryEnforcer := e.opts.GlobalEnforcer().Child(qcost.QueryLevel)
-	defer perQueryEnforcer.Close()
 	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
 
 	nodes, edges, err := req.compile(ctx, parser)
 	if err != nil {
+		perQueryEnforcer.Close()
 		return nil, err
 	}
 
 	pp, err := req.plan(ctx, nodes, edges)
 	if err != nil {
+		perQueryEnforcer.Close()
 		return nil, err
 	}
 
 	state, err := req.generateExecutionState(ctx, pp)
 	if err != nil {
+		perQueryEnforcer.Close()
 		return nil, err
 	}
 
@@ -148,13 +154,21 @@ func (e *engine) ExecuteExpr(
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "executing")
 	defer sp.Finish()
 
+	// NB: the query stays registered, and its cost accounted, until
+	// execution completes rather than until this method returns.
+	ctx, deregister := e.registry.register(ctx, params.Query, opts.Caller,
+		perQueryEnforcer)
+
 	result := state.resultNode
 	scope := e.opts.InstrumentOptions().MetricsScope()
 	queryCtx := models.NewQueryContext(ctx, scope, perQueryEnforcer,
 		opts.QueryContextOptions)
 
 	go func() {
-		if err := state.Execute(queryCtx); err != nil {
+		err := state.Execute(queryCtx)
+		deregister()
+		perQueryEnforcer.Close()
+		if err != nil {
 			result.abort(err)
 		} else {
 			result.done()
@@ -164,6 +178,62 @@ func (e *engine) ExecuteExpr(
 	return result, nil
 }
 
+func (e *engine) ExplainExpr(
+	ctx context.Context,
+	parser parser.Parser,
+	opts *QueryOptions,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+	analyze bool,
+) (Explanation, error) {
+	perQueryEnforcer := e.opts.GlobalEnforcer().Child(qcost.QueryLevel)
+	defer perQueryEnforcer.Close()
+	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
+
+	nodes, edges, err := req.compile(ctx, parser)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	pp, err := req.plan(ctx, nodes, edges)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	scope := e.opts.InstrumentOptions().MetricsScope()
+	queryCtx := models.NewQueryContext(ctx, scope, perQueryEnforcer,
+		opts.QueryContextOptions)
+
+	explanation, err := req.explain(queryCtx, pp)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	if !analyze {
+		return explanation, nil
+	}
+
+	ctx, deregister := e.registry.register(ctx, params.Query, opts.Caller,
+		perQueryEnforcer)
+	defer deregister()
+	queryCtx = models.NewQueryContext(ctx, scope, perQueryEnforcer,
+		opts.QueryContextOptions)
+
+	if err := req.analyze(queryCtx, pp, &explanation); err != nil {
+		return Explanation{}, err
+	}
+
+	return explanation, nil
+}
+
+func (e *engine) ActiveQueries() []ActiveQuery {
+	return e.registry.activeQueries()
+}
+
+func (e *engine) CancelQuery(id string) bool {
+	return e.registry.cancel(id)
+}
+
 func (e *engine) Options() EngineOptions {
 	return e.opts
 }
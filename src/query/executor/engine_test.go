@@ -58,8 +58,8 @@ func newEngine(
 func TestEngine_Execute(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	store, session := m3.NewStorageAndSession(t, ctrl)
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(),
-		gomock.Any()).Return(nil, false, fmt.Errorf("dummy"))
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).Return(nil, false, fmt.Errorf("dummy"))
 	session.EXPECT().IteratorPools().Return(nil, nil)
 
 	// Results is closed by execute
@@ -84,7 +84,7 @@ func TestEngine_ExecuteExpr(t *testing.T) {
 
 	engine := newEngine(mock.NewMockStorage(), defaultLookbackDuration,
 		mockParent, instrument.NewOptions())
-	_, err = engine.ExecuteExpr(context.TODO(), parser,
+	result, err := engine.ExecuteExpr(context.TODO(), parser,
 		&QueryOptions{}, storage.NewFetchOptions(), models.RequestParams{
 			Start: time.Now().Add(-2 * time.Second),
 			End:   time.Now(),
@@ -92,4 +92,8 @@ func TestEngine_ExecuteExpr(t *testing.T) {
 		})
 
 	require.NoError(t, err)
+
+	// The per query enforcer is closed once execution completes.
+	for range result.ResultChan() {
+	}
 }
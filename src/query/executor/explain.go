@@ -0,0 +1,496 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/plan"
+	"github.com/m3db/m3/src/query/storage"
+	xcost "github.com/m3db/m3/src/x/cost"
+	"github.com/m3db/m3/src/x/opentracing"
+)
+
+// datapointSizeBytes is the in memory size of a single datapoint value.
+const datapointSizeBytes = 8
+
+// Explanation describes how a query was planned and, if analyzed, how
+// the plan executed.
+type Explanation struct {
+	// TimeSpec is the time range the plan executes over.
+	TimeSpec transform.TimeSpec
+	// Root is the node which produces the query result.
+	Root ExplainNode
+	// Stats are the query wide execution statistics, only set when the
+	// query was analyzed.
+	Stats *QueryStats
+}
+
+// ExplainNode describes a single step of a physical plan.
+type ExplainNode struct {
+	ID     parser.NodeID
+	OpType string
+	Op     string
+	// Fetch is set if the node fetches from storage.
+	Fetch bool
+	// Namespaces are the namespaces a fetch resolves to.
+	Namespaces []storage.ResolvedNamespace
+	// EstimatedSeries is the number of series matched by a fetch according
+	// to the index, SeriesLimited is set if the estimate hit the fetch limit.
+	EstimatedSeries int
+	SeriesLimited   bool
+	// Stats are the execution statistics of the node, only set when the
+	// query was analyzed.
+	Stats *NodeStats
+	// Inputs are the nodes which feed blocks into this node.
+	Inputs []ExplainNode
+}
+
+// NodeStats are execution statistics gathered for a single plan node.
+type NodeStats struct {
+	// Duration is the time spent in the node, excluding downstream nodes.
+	Duration time.Duration
+	// Blocks is the number of blocks emitted by the node.
+	Blocks int
+	// Datapoints is the number of datapoints accounted to the cost enforcer
+	// by the node.
+	Datapoints int64
+	// PeakDatapoints is the largest number of datapoints held by the node
+	// at any one time.
+	PeakDatapoints int64
+}
+
+// PeakMemoryBytes returns the estimated peak memory held by the node.
+func (s NodeStats) PeakMemoryBytes() int64 {
+	return s.PeakDatapoints * datapointSizeBytes
+}
+
+// QueryStats are execution statistics gathered for a whole query.
+type QueryStats struct {
+	// Duration is the time taken to execute the query and consume its result.
+	Duration time.Duration
+	// Datapoints is the number of datapoints accounted to the cost enforcer.
+	Datapoints int64
+	// PeakDatapoints is the largest number of datapoints held at any one time.
+	PeakDatapoints int64
+	// DatapointsLimit is the per query datapoint limit, zero if disabled.
+	DatapointsLimit int64
+}
+
+// PeakMemoryBytes returns the estimated peak memory held by the query.
+func (s QueryStats) PeakMemoryBytes() int64 {
+	return s.PeakDatapoints * datapointSizeBytes
+}
+
+// fetchQueryParams is implemented by source params which fetch from storage.
+type fetchQueryParams interface {
+	FetchQuery(timeSpec transform.TimeSpec) *storage.FetchQuery
+}
+
+func (r *Request) explain(
+	queryCtx *models.QueryContext,
+	pp plan.PhysicalPlan,
+) (Explanation, error) {
+	sp, ctx := opentracing.StartSpanFromContext(queryCtx.Ctx, "explain")
+	defer sp.Finish()
+
+	fetchOpts, err := r.fetchOpts.QueryFetchOptions(queryCtx, pp.BlockType)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	root, err := r.explainStep(ctx, pp, pp.ResultStep.Parent, fetchOpts)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	return Explanation{
+		TimeSpec: pp.TimeSpec,
+		Root:     root,
+	}, nil
+}
+
+func (r *Request) explainStep(
+	ctx context.Context,
+	pp plan.PhysicalPlan,
+	id parser.NodeID,
+	fetchOpts *storage.FetchOptions,
+) (ExplainNode, error) {
+	step, ok := pp.Step(id)
+	if !ok {
+		return ExplainNode{}, fmt.Errorf("incorrect step reference, id: %s", id)
+	}
+
+	node := ExplainNode{
+		ID:     id,
+		OpType: step.Transform.Op.OpType(),
+		Op:     step.Transform.Op.String(),
+	}
+
+	if fetch, ok := step.Transform.Op.(fetchQueryParams); ok {
+		var (
+			store = r.engine.opts.Store()
+			query = fetch.FetchQuery(pp.TimeSpec)
+		)
+
+		node.Fetch = true
+		if resolver, ok := store.(storage.NamespaceResolver); ok {
+			namespaces, err := resolver.ResolveNamespaces(query, fetchOpts)
+			if err != nil {
+				return ExplainNode{}, err
+			}
+
+			node.Namespaces = namespaces
+		}
+
+		result, err := store.SearchSeries(ctx, query, fetchOpts)
+		if err != nil {
+			return ExplainNode{}, err
+		}
+
+		if result != nil {
+			node.EstimatedSeries = len(result.Metrics)
+		}
+		node.SeriesLimited = fetchOpts.Limit > 0 &&
+			node.EstimatedSeries >= fetchOpts.Limit
+	}
+
+	for _, parentID := range step.Parents {
+		input, err := r.explainStep(ctx, pp, parentID, fetchOpts)
+		if err != nil {
+			return ExplainNode{}, err
+		}
+
+		node.Inputs = append(node.Inputs, input)
+	}
+
+	return node, nil
+}
+
+// analyze executes the plan, consuming and discarding its result, and
+// annotates the explanation with the gathered statistics.
+func (r *Request) analyze(
+	queryCtx *models.QueryContext,
+	pp plan.PhysicalPlan,
+	explanation *Explanation,
+) error {
+	sp, ctx := opentracing.StartSpanFromContext(queryCtx.Ctx, "analyze")
+	defer sp.Finish()
+
+	stats := newExecutionStats()
+	state, err := generateExecutionState(pp, r.engine.opts.Store(),
+		r.fetchOpts, r.instrumentOpts, stats)
+	if err != nil {
+		return err
+	}
+
+	var (
+		start    = time.Now()
+		result   = state.resultNode
+		firstErr error
+	)
+
+	go func() {
+		if err := state.Execute(queryCtx.WithContext(ctx)); err != nil {
+			result.abort(err)
+		} else {
+			result.done()
+		}
+	}()
+
+	for res := range result.ResultChan() {
+		if res.Err != nil {
+			if firstErr == nil {
+				firstErr = res.Err
+			}
+			continue
+		}
+
+		// NB: blocks may be lazily evaluated, so they are consumed to make
+		// sure their cost is accounted for.
+		if err := consumeBlock(res.Block); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		res.Block.Close()
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	total, peak := stats.query.values()
+	explanation.Stats = &QueryStats{
+		Duration:       time.Since(start),
+		Datapoints:     total,
+		PeakDatapoints: peak,
+	}
+	if limit := queryCtx.Enforcer.Limit(); limit.Enabled {
+		explanation.Stats.DatapointsLimit = int64(limit.Threshold)
+	}
+
+	annotateStats(&explanation.Root, stats)
+	return nil
+}
+
+func consumeBlock(b block.Block) error {
+	iter, err := b.StepIter()
+	if err != nil {
+		return err
+	}
+
+	defer iter.Close()
+	for iter.Next() {
+	}
+
+	return iter.Err()
+}
+
+func annotateStats(node *ExplainNode, stats *executionStats) {
+	if n := stats.node(node.ID); n != nil {
+		s := n.snapshot()
+		node.Stats = &s
+	}
+
+	for i := range node.Inputs {
+		annotateStats(&node.Inputs[i], stats)
+	}
+}
+
+// executionStats collects per node statistics while a plan executes.
+type executionStats struct {
+	sync.RWMutex
+	query *datapointCounter
+	nodes map[parser.NodeID]*nodeStats
+}
+
+func newExecutionStats() *executionStats {
+	return &executionStats{
+		query: &datapointCounter{},
+		nodes: make(map[parser.NodeID]*nodeStats),
+	}
+}
+
+func (s *executionStats) node(id parser.NodeID) *nodeStats {
+	s.RLock()
+	n := s.nodes[id]
+	s.RUnlock()
+	return n
+}
+
+func (s *executionStats) getOrCreateNode(id parser.NodeID) *nodeStats {
+	s.Lock()
+	defer s.Unlock()
+	n, ok := s.nodes[id]
+	if !ok {
+		n = &nodeStats{
+			datapoints: &datapointCounter{parent: s.query},
+		}
+		s.nodes[id] = n
+	}
+
+	return n
+}
+
+type nodeStats struct {
+	sync.Mutex
+	total      time.Duration
+	downstream time.Duration
+	blocks     int
+	datapoints *datapointCounter
+}
+
+func (n *nodeStats) addTotal(d time.Duration) {
+	n.Lock()
+	n.total += d
+	n.Unlock()
+}
+
+func (n *nodeStats) addDownstream(d time.Duration) {
+	n.Lock()
+	n.downstream += d
+	n.blocks++
+	n.Unlock()
+}
+
+// queryContext returns a copy of the query context which accounts the cost
+// of blocks built by the node to it.
+func (n *nodeStats) queryContext(
+	queryCtx *models.QueryContext,
+) *models.QueryContext {
+	nodeCtx := *queryCtx
+	nodeCtx.Enforcer = newAccountingEnforcer(queryCtx.Enforcer, n.datapoints)
+	return &nodeCtx
+}
+
+func (n *nodeStats) snapshot() NodeStats {
+	n.Lock()
+	duration := n.total - n.downstream
+	blocks := n.blocks
+	n.Unlock()
+	if duration < 0 {
+		duration = 0
+	}
+
+	total, peak := n.datapoints.values()
+	return NodeStats{
+		Duration:       duration,
+		Blocks:         blocks,
+		Datapoints:     total,
+		PeakDatapoints: peak,
+	}
+}
+
+// datapointCounter tracks the total and peak cost added to enforcers,
+// rolling up into its parent.
+type datapointCounter struct {
+	sync.Mutex
+	parent  *datapointCounter
+	total   xcost.Cost
+	current xcost.Cost
+	peak    xcost.Cost
+}
+
+func (c *datapointCounter) add(v xcost.Cost) {
+	c.Lock()
+	if v > 0 {
+		c.total += v
+	}
+	c.current += v
+	if c.current > c.peak {
+		c.peak = c.current
+	}
+	c.Unlock()
+
+	if c.parent != nil {
+		c.parent.add(v)
+	}
+}
+
+func (c *datapointCounter) values() (int64, int64) {
+	c.Lock()
+	defer c.Unlock()
+	return int64(c.total), int64(c.peak)
+}
+
+// accountingEnforcer wraps an enforcer, counting all cost added through it
+// and any of its children.
+type accountingEnforcer struct {
+	qcost.ChainedEnforcer
+
+	mu      sync.Mutex
+	local   xcost.Cost
+	counter *datapointCounter
+}
+
+func newAccountingEnforcer(
+	enforcer qcost.ChainedEnforcer,
+	counter *datapointCounter,
+) qcost.ChainedEnforcer {
+	if enforcer == nil {
+		enforcer = qcost.NoopChainedEnforcer()
+	}
+
+	// NB: unwrap enforcers belonging to upstream nodes so that cost is only
+	// accounted to the node doing the work.
+	if accounting, ok := enforcer.(*accountingEnforcer); ok {
+		enforcer = accounting.ChainedEnforcer
+	}
+
+	return &accountingEnforcer{
+		ChainedEnforcer: enforcer,
+		counter:         counter,
+	}
+}
+
+func (e *accountingEnforcer) Add(c xcost.Cost) xcost.Report {
+	e.mu.Lock()
+	e.local += c
+	e.mu.Unlock()
+	e.counter.add(c)
+	return e.ChainedEnforcer.Add(c)
+}
+
+func (e *accountingEnforcer) Child(resourceName string) qcost.ChainedEnforcer {
+	return newAccountingEnforcer(e.ChainedEnforcer.Child(resourceName),
+		e.counter)
+}
+
+func (e *accountingEnforcer) Close() {
+	e.mu.Lock()
+	local := e.local
+	e.local = 0
+	e.mu.Unlock()
+	e.counter.add(-local)
+	e.ChainedEnforcer.Close()
+}
+
+// analyzedSource times a source node.
+type analyzedSource struct {
+	parser.Source
+	stats *nodeStats
+}
+
+func (s *analyzedSource) Execute(queryCtx *models.QueryContext) error {
+	start := time.Now()
+	err := s.Source.Execute(s.stats.queryContext(queryCtx))
+	s.stats.addTotal(time.Since(start))
+	return err
+}
+
+// analyzedOpNode times a transform node, attributing the time spent to the
+// upstream node which emitted the block as downstream time.
+type analyzedOpNode struct {
+	transform.OpNode
+	stats *nodeStats
+	all   *executionStats
+}
+
+func (n *analyzedOpNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	if n.stats != nil {
+		queryCtx = n.stats.queryContext(queryCtx)
+	}
+
+	start := time.Now()
+	err := n.OpNode.Process(queryCtx, ID, b)
+	elapsed := time.Since(start)
+	if n.stats != nil {
+		n.stats.addTotal(elapsed)
+	}
+
+	if upstream := n.all.node(ID); upstream != nil {
+		upstream.addDownstream(elapsed)
+	}
+
+	return err
+}
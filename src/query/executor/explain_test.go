@@ -0,0 +1,147 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/test"
+	xcost "github.com/m3db/m3/src/x/cost"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGlobalEnforcer(t *testing.T, limit xcost.Cost) qcost.ChainedEnforcer {
+	newEnforcer := func() xcost.Enforcer {
+		return xcost.NewEnforcer(
+			xcost.NewStaticLimitManager(xcost.NewLimitManagerOptions().
+				SetDefaultLimit(xcost.Limit{Threshold: limit, Enabled: true})),
+			xcost.NewTracker(),
+			nil,
+		)
+	}
+
+	enforcer, err := qcost.NewChainedEnforcer(qcost.GlobalLevel,
+		[]xcost.Enforcer{newEnforcer(), newEnforcer(), newEnforcer()})
+	require.NoError(t, err)
+	return enforcer
+}
+
+func newExplainTestEngine(
+	t *testing.T,
+	enforcer qcost.ChainedEnforcer,
+) (Engine, models.RequestParams) {
+	now := time.Now().Truncate(time.Second)
+	params := models.RequestParams{
+		Start:            now.Add(-2 * time.Second),
+		End:              now,
+		Now:              now,
+		Step:             time.Second,
+		LookbackDuration: defaultLookbackDuration,
+	}
+
+	store := mock.NewMockStorage()
+	store.SetSearchSeriesResult(&storage.SearchResults{
+		Metrics: models.Metrics{{ID: []byte("a")}, {ID: []byte("b")}},
+	}, nil)
+
+	bounds := models.Bounds{
+		Start:    params.Start,
+		Duration: params.End.Sub(params.Start),
+		StepSize: params.Step,
+	}
+	store.SetFetchBlocksResult(block.Result{
+		Blocks: []block.Block{
+			test.NewBlockFromValues(bounds, [][]float64{{1, 2}, {3, 4}}),
+		},
+	}, nil)
+
+	engine := newEngine(store, defaultLookbackDuration, enforcer,
+		instrument.NewOptions())
+	return engine, params
+}
+
+func TestEngineExplainExpr(t *testing.T) {
+	engine, params := newExplainTestEngine(t, newTestGlobalEnforcer(t, 100))
+	parser, err := promql.Parse("sum(foo)", models.NewTagOptions())
+	require.NoError(t, err)
+
+	fetchOpts := storage.NewFetchOptions()
+	fetchOpts.Limit = 2
+	explanation, err := engine.ExplainExpr(context.TODO(), parser,
+		&QueryOptions{}, fetchOpts, params, false)
+	require.NoError(t, err)
+
+	assert.Nil(t, explanation.Stats)
+	root := explanation.Root
+	assert.Equal(t, "sum", root.OpType)
+	assert.Nil(t, root.Stats)
+	require.Equal(t, 1, len(root.Inputs))
+
+	fetch := root.Inputs[0]
+	assert.Equal(t, "fetch", fetch.OpType)
+	assert.True(t, fetch.Fetch)
+	assert.Equal(t, 2, fetch.EstimatedSeries)
+	assert.True(t, fetch.SeriesLimited)
+	assert.Nil(t, fetch.Stats)
+}
+
+func TestEngineExplainExprAnalyze(t *testing.T) {
+	global := newTestGlobalEnforcer(t, 100)
+	engine, params := newExplainTestEngine(t, global)
+	parser, err := promql.Parse("sum(foo)", models.NewTagOptions())
+	require.NoError(t, err)
+
+	explanation, err := engine.ExplainExpr(context.TODO(), parser,
+		&QueryOptions{}, storage.NewFetchOptions(), params, true)
+	require.NoError(t, err)
+
+	stats := explanation.Stats
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(100), stats.DatapointsLimit)
+
+	root := explanation.Root
+	require.NotNil(t, root.Stats)
+	assert.Equal(t, 1, root.Stats.Blocks)
+	assert.NotZero(t, root.Stats.Datapoints)
+	assert.Equal(t, root.Stats.Datapoints, stats.Datapoints)
+	assert.Equal(t, root.Stats.PeakDatapoints*8, root.Stats.PeakMemoryBytes())
+
+	require.Equal(t, 1, len(root.Inputs))
+	fetch := root.Inputs[0]
+	require.NotNil(t, fetch.Stats)
+	assert.Equal(t, 1, fetch.Stats.Blocks)
+	assert.Equal(t, int64(0), fetch.Stats.Datapoints)
+
+	// All datapoints are released once the query completes.
+	report, _ := global.State()
+	assert.Equal(t, xcost.Cost(0), report.Cost)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/x/cost"
+)
+
+// ActiveQuery describes a query that is currently executing.
+type ActiveQuery struct {
+	// ID uniquely identifies the query within this engine.
+	ID string
+	// Query is the query text as provided by the caller.
+	Query string
+	// Start is the time execution started.
+	Start time.Time
+	// Caller identifies who issued the query, usually the remote address.
+	Caller string
+	// Cost is the current cost accumulated by the query.
+	Cost cost.Cost
+}
+
+type activeQuery struct {
+	query    string
+	start    time.Time
+	caller   string
+	enforcer qcost.ChainedEnforcer
+	cancel   context.CancelFunc
+}
+
+// queryRegistry tracks the queries currently executing in an engine so that
+// they can be listed and cancelled.
+type queryRegistry struct {
+	sync.RWMutex
+	nextID  uint64
+	queries map[string]activeQuery
+	nowFn   func() time.Time
+}
+
+func newQueryRegistry() *queryRegistry {
+	return &queryRegistry{
+		queries: make(map[string]activeQuery),
+		nowFn:   time.Now,
+	}
+}
+
+// register tracks a query until the returned function is called. Cancelling
+// the query cancels the provided context.
+func (r *queryRegistry) register(
+	ctx context.Context,
+	query string,
+	caller string,
+	enforcer qcost.ChainedEnforcer,
+) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	r.Lock()
+	r.nextID++
+	id := strconv.FormatUint(r.nextID, 10)
+	r.queries[id] = activeQuery{
+		query:    query,
+		start:    r.nowFn(),
+		caller:   caller,
+		enforcer: enforcer,
+		cancel:   cancel,
+	}
+	r.Unlock()
+
+	return ctx, func() {
+		r.Lock()
+		delete(r.queries, id)
+		r.Unlock()
+		cancel()
+	}
+}
+
+func (r *queryRegistry) activeQueries() []ActiveQuery {
+	r.RLock()
+	queries := make([]ActiveQuery, 0, len(r.queries))
+	for id, q := range r.queries {
+		report, _ := q.enforcer.State()
+		queries = append(queries, ActiveQuery{
+			ID:     id,
+			Query:  q.query,
+			Start:  q.start,
+			Caller: q.caller,
+			Cost:   report.Cost,
+		})
+	}
+	r.RUnlock()
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Start.Before(queries[j].Start)
+	})
+
+	return queries
+}
+
+func (r *queryRegistry) cancel(id string) bool {
+	r.RLock()
+	q, ok := r.queries[id]
+	r.RUnlock()
+	if !ok {
+		return false
+	}
+
+	q.cancel()
+	return true
+}
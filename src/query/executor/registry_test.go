@@ -0,0 +1,133 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	xcost "github.com/m3db/m3/src/x/cost"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRegistry(t *testing.T) {
+	var (
+		global   = newTestGlobalEnforcer(t, 100)
+		registry = newQueryRegistry()
+		now      = time.Now()
+	)
+
+	registry.nowFn = func() time.Time { return now }
+	first := global.Child(qcost.QueryLevel)
+	firstCtx, firstDone := registry.register(context.Background(),
+		"foo", "10.0.0.1:1234", first)
+	first.Add(xcost.Cost(3))
+
+	registry.nowFn = func() time.Time { return now.Add(time.Second) }
+	second := global.Child(qcost.QueryLevel)
+	secondCtx, secondDone := registry.register(context.Background(),
+		"bar", "10.0.0.2:1234", second)
+	defer secondDone()
+
+	assert.Equal(t, []ActiveQuery{
+		{ID: "1", Query: "foo", Start: now, Caller: "10.0.0.1:1234", Cost: 3},
+		{ID: "2", Query: "bar", Start: now.Add(time.Second),
+			Caller: "10.0.0.2:1234", Cost: 0},
+	}, registry.activeQueries())
+
+	assert.False(t, registry.cancel("3"))
+	assert.True(t, registry.cancel("1"))
+	assert.Equal(t, context.Canceled, firstCtx.Err())
+	assert.NoError(t, secondCtx.Err())
+
+	// Cancelled queries remain listed until execution completes.
+	assert.Len(t, registry.activeQueries(), 2)
+	firstDone()
+	queries := registry.activeQueries()
+	require.Len(t, queries, 1)
+	assert.Equal(t, "2", queries[0].ID)
+	assert.False(t, registry.cancel("1"))
+}
+
+func TestEngineCancelQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	started := make(chan struct{})
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			ctx context.Context,
+			_ *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			close(started)
+			<-ctx.Done()
+			return block.Result{}, ctx.Err()
+		})
+
+	engine := newEngine(store, defaultLookbackDuration,
+		newTestGlobalEnforcer(t, 100), instrument.NewOptions())
+	parser, err := promql.Parse("foo", models.NewTagOptions())
+	require.NoError(t, err)
+
+	now := time.Now()
+	result, err := engine.ExecuteExpr(context.TODO(), parser,
+		&QueryOptions{Caller: "10.0.0.1:1234"}, storage.NewFetchOptions(),
+		models.RequestParams{
+			Query: "foo",
+			Start: now.Add(-2 * time.Second),
+			End:   now,
+			Now:   now,
+			Step:  time.Second,
+		})
+	require.NoError(t, err)
+
+	<-started
+	queries := engine.ActiveQueries()
+	require.Len(t, queries, 1)
+	assert.Equal(t, "foo", queries[0].Query)
+	assert.Equal(t, "10.0.0.1:1234", queries[0].Caller)
+
+	require.True(t, engine.CancelQuery(queries[0].ID))
+
+	var resultErr error
+	for r := range result.ResultChan() {
+		if r.Err != nil {
+			resultErr = r.Err
+		}
+	}
+
+	require.Error(t, resultErr)
+	assert.Contains(t, resultErr.Error(), context.Canceled.Error())
+	assert.Empty(t, engine.ActiveQueries())
+	assert.False(t, engine.CancelQuery(queries[0].ID))
+}
@@ -41,6 +41,7 @@ type ExecutionState struct {
 	sources    []parser.Source
 	resultNode Result
 	storage    storage.Storage
+	stats      *executionStats
 }
 
 // CreateSource creates a source node.
@@ -93,11 +94,23 @@ func GenerateExecutionState(
 	storage storage.Storage,
 	fetchOpts *storage.FetchOptions,
 	instrumentOpts instrument.Options,
+) (*ExecutionState, error) {
+	return generateExecutionState(pplan, storage, fetchOpts,
+		instrumentOpts, nil)
+}
+
+func generateExecutionState(
+	pplan plan.PhysicalPlan,
+	storage storage.Storage,
+	fetchOpts *storage.FetchOptions,
+	instrumentOpts instrument.Options,
+	stats *executionStats,
 ) (*ExecutionState, error) {
 	result := pplan.ResultStep
 	state := &ExecutionState{
 		plan:    pplan,
 		storage: storage,
+		stats:   stats,
 	}
 
 	step, ok := pplan.Step(result.Parent)
@@ -128,7 +141,7 @@ func GenerateExecutionState(
 
 	rNode := newResultNode()
 	state.resultNode = rNode
-	controller.AddTransform(rNode)
+	controller.AddTransform(state.instrumentTransform("", rNode))
 
 	return state, nil
 }
@@ -141,16 +154,21 @@ func (s *ExecutionState) createNode(
 	// TODO: consider using a registry instead of casting to an interface.
 	sourceParams, ok := step.Transform.Op.(SourceParams)
 	if ok {
+		sourceOptions, err := s.sourceOptions(step.ID(), options)
+		if err != nil {
+			return nil, err
+		}
+
 		source, controller := CreateSource(step.ID(), sourceParams,
-			s.storage, options)
-		s.sources = append(s.sources, source)
+			s.storage, sourceOptions)
+		s.sources = append(s.sources, s.instrumentSource(step.ID(), source))
 		return controller, nil
 	}
 
 	scalarParams, ok := step.Transform.Op.(ScalarParams)
 	if ok {
 		source, controller := CreateScalarSource(step.ID(), scalarParams, options)
-		s.sources = append(s.sources, source)
+		s.sources = append(s.sources, s.instrumentSource(step.ID(), source))
 		return controller, nil
 	}
 
@@ -161,6 +179,7 @@ func (s *ExecutionState) createNode(
 
 	transformNode, controller := CreateTransform(step.ID(),
 		transformParams, options)
+	transformNode = s.instrumentTransform(step.ID(), transformNode)
 	for _, parentID := range step.Parents {
 		parentStep, ok := s.plan.Step(parentID)
 		if !ok {
@@ -179,6 +198,63 @@ func (s *ExecutionState) createNode(
 	return controller, nil
 }
 
+// sourceOptions returns the options for a source node, accounting the
+// datapoints it fetches to the node when the execution is analyzed.
+func (s *ExecutionState) sourceOptions(
+	ID parser.NodeID,
+	options transform.Options,
+) (transform.Options, error) {
+	if s.stats == nil {
+		return options, nil
+	}
+
+	fetchOpts := options.FetchOptions().Clone()
+	fetchOpts.Enforcer = newAccountingEnforcer(fetchOpts.Enforcer,
+		s.stats.getOrCreateNode(ID).datapoints)
+	return transform.NewOptions(transform.OptionsParams{
+		FetchOptions:      fetchOpts,
+		TimeSpec:          options.TimeSpec(),
+		Debug:             options.Debug(),
+		BlockType:         options.BlockType(),
+		InstrumentOptions: options.InstrumentOptions(),
+	})
+}
+
+func (s *ExecutionState) instrumentSource(
+	ID parser.NodeID,
+	source parser.Source,
+) parser.Source {
+	if s.stats == nil {
+		return source
+	}
+
+	return &analyzedSource{
+		Source: source,
+		stats:  s.stats.getOrCreateNode(ID),
+	}
+}
+
+// instrumentTransform wraps a transform node to gather statistics when the
+// execution is analyzed, an empty ID only attributes time to upstream nodes.
+func (s *ExecutionState) instrumentTransform(
+	ID parser.NodeID,
+	node transform.OpNode,
+) transform.OpNode {
+	if s.stats == nil {
+		return node
+	}
+
+	analyzed := &analyzedOpNode{
+		OpNode: node,
+		all:    s.stats,
+	}
+	if ID != "" {
+		analyzed.stats = s.stats.getOrCreateNode(ID)
+	}
+
+	return analyzed
+}
+
 // Execute the sources in parallel and return the first error.
 func (s *ExecutionState) Execute(queryCtx *models.QueryContext) error {
 	requests := make([]execution.Request, len(s.sources))
@@ -50,6 +50,24 @@ type Engine interface {
 		params models.RequestParams,
 	) (Result, error)
 
+	// ExplainExpr plans the query DAG and describes the resulting physical
+	// plan, executing it to gather per node statistics if analyze is set.
+	ExplainExpr(
+		ctx context.Context,
+		parser parser.Parser,
+		opts *QueryOptions,
+		fetchOpts *storage.FetchOptions,
+		params models.RequestParams,
+		analyze bool,
+	) (Explanation, error)
+
+	// ActiveQueries returns the queries currently executing.
+	ActiveQueries() []ActiveQuery
+
+	// CancelQuery cancels the executing query with the given ID, returning
+	// false if no such query is active.
+	CancelQuery(id string) bool
+
 	// Options returns the currently configured options.
 	Options() EngineOptions
 
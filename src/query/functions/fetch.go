@@ -79,6 +79,18 @@ func (o FetchOp) String() string {
 	return fmt.Sprintf("type: %s. name: %s, range: %v, offset: %v, matchers: %v", o.OpType(), o.Name, o.Range, o.Offset, o.Matchers)
 }
 
+// FetchQuery returns the storage query issued by the fetch for the given
+// time spec.
+func (o FetchOp) FetchQuery(timeSpec transform.TimeSpec) *storage.FetchQuery {
+	// No need to adjust start and ends since physical plan already considers the offset, range
+	return &storage.FetchQuery{
+		Start:       timeSpec.Start.Add(-1 * o.Offset),
+		End:         timeSpec.End.Add(-1 * o.Offset),
+		TagMatchers: o.Matchers,
+		Interval:    timeSpec.Step,
+	}
+}
+
 // Node creates an execution node
 func (o FetchOp) Node(controller *transform.Controller, storage storage.Storage, options transform.Options) parser.Source {
 	return &FetchNode{
@@ -98,23 +110,12 @@ func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "fetch")
 	defer sp.Finish()
 
-	timeSpec := n.timespec
-	// No need to adjust start and ends since physical plan already considers the offset, range
-	startTime := timeSpec.Start
-	endTime := timeSpec.End
-
 	opts, err := n.fetchOpts.QueryFetchOptions(queryCtx, n.blockType)
 	if err != nil {
 		return block.Result{}, err
 	}
 
-	offset := n.op.Offset
-	return n.storage.FetchBlocks(ctx, &storage.FetchQuery{
-		Start:       startTime.Add(-1 * offset),
-		End:         endTime.Add(-1 * offset),
-		TagMatchers: n.op.Matchers,
-		Interval:    timeSpec.Step,
-	}, opts)
+	return n.storage.FetchBlocks(ctx, n.op.FetchQuery(n.timespec), opts)
 }
 
 // Execute runs the fetch node operation
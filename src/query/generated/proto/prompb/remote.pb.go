@@ -60,7 +60,8 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type WriteRequest struct {
-	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+	Timeseries []*TimeSeries     `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+	Metadata   []*MetricMetadata `protobuf:"bytes,3,rep,name=metadata" json:"metadata,omitempty"`
 }
 
 func (m *WriteRequest) Reset()                    { *m = WriteRequest{} }
@@ -75,6 +76,13 @@ func (m *WriteRequest) GetTimeseries() []*TimeSeries {
 	return nil
 }
 
+func (m *WriteRequest) GetMetadata() []*MetricMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type ReadRequest struct {
 	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
 }
@@ -190,6 +198,18 @@ func (m *WriteRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Metadata) > 0 {
+		for _, msg := range m.Metadata {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -341,6 +361,12 @@ func (m *WriteRequest) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if len(m.Metadata) > 0 {
+		for _, e := range m.Metadata {
+			l = e.Size()
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -471,6 +497,37 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata, &MetricMetadata{})
+			if err := m.Metadata[len(m.Metadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -964,26 +1021,28 @@ func init() {
 }
 
 var fileDescriptorRemote = []byte{
-	// 328 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x91, 0xbf, 0x4a, 0xc3, 0x50,
-	0x14, 0xc6, 0x8d, 0xc5, 0x56, 0x4e, 0x8b, 0xd4, 0x0c, 0x1a, 0x1c, 0x82, 0x64, 0x2a, 0x28, 0xb9,
-	0x68, 0xc5, 0xc1, 0xc5, 0x3f, 0xa0, 0x93, 0x1d, 0xbc, 0x16, 0x04, 0x97, 0x92, 0x34, 0x87, 0x36,
-	0xd0, 0x9b, 0xa4, 0xf7, 0x9c, 0x0c, 0x7d, 0x0b, 0x17, 0xdf, 0xc9, 0xd1, 0x47, 0x90, 0xf8, 0x22,
-	0x92, 0x1b, 0x62, 0x23, 0x6e, 0x5d, 0x32, 0x9c, 0xef, 0x77, 0xbe, 0xfc, 0xb8, 0x07, 0x6e, 0x66,
-	0x31, 0xcf, 0xf3, 0xd0, 0x9f, 0xa6, 0x4a, 0xa8, 0x61, 0x14, 0x0a, 0x35, 0x14, 0xa4, 0xa7, 0x62,
-	0x99, 0xa3, 0x5e, 0x89, 0x19, 0x26, 0xa8, 0x03, 0xc6, 0x48, 0x64, 0x3a, 0xe5, 0xb4, 0xfc, 0xaa,
-	0x2c, 0x14, 0x1a, 0x55, 0xca, 0xe8, 0x9b, 0x99, 0x0d, 0xe5, 0x10, 0x79, 0x8e, 0x39, 0x1d, 0x5d,
-	0x6f, 0xd2, 0xc6, 0xab, 0x0c, 0xa9, 0x2a, 0xf3, 0x1e, 0xa0, 0xf7, 0xa2, 0x63, 0x46, 0x89, 0xcb,
-	0x1c, 0x89, 0xed, 0x4b, 0x00, 0x8e, 0x15, 0x12, 0xea, 0x18, 0xc9, 0xb1, 0x8e, 0x5b, 0x83, 0xee,
-	0xf9, 0x81, 0xbf, 0xfe, 0xa3, 0x3f, 0x8e, 0x15, 0x3e, 0x9b, 0x54, 0x36, 0x48, 0xef, 0x0a, 0xba,
-	0x12, 0x83, 0xa8, 0xae, 0x39, 0x81, 0x4e, 0xa9, 0xb0, 0xee, 0xd8, 0x6f, 0x76, 0x3c, 0x95, 0x76,
-	0xb2, 0x26, 0xbc, 0x5b, 0xe8, 0x55, 0xbb, 0x94, 0xa5, 0x09, 0xa1, 0x7d, 0x06, 0x1d, 0x8d, 0x94,
-	0x2f, 0xb8, 0x5e, 0x3e, 0xfc, 0xbf, 0x6c, 0x72, 0x59, 0x73, 0xde, 0xbb, 0x05, 0x3b, 0x26, 0xb0,
-	0x4f, 0xc1, 0x26, 0x0e, 0x34, 0x4f, 0x8c, 0x1c, 0x07, 0x2a, 0x9b, 0xa8, 0xb2, 0xc7, 0x1a, 0xb4,
-	0x64, 0xdf, 0x24, 0xe3, 0x3a, 0x18, 0x91, 0x3d, 0x80, 0x3e, 0x26, 0xd1, 0x5f, 0x76, 0xdb, 0xb0,
-	0x7b, 0x98, 0x44, 0x4d, 0xf2, 0x02, 0x76, 0x55, 0xc0, 0xd3, 0x39, 0x6a, 0x72, 0x5a, 0xc6, 0xca,
-	0x69, 0x5a, 0x3d, 0x06, 0x21, 0x2e, 0x46, 0x15, 0x20, 0x7f, 0x49, 0xef, 0x1e, 0xba, 0x0d, 0xdf,
-	0x4d, 0x5f, 0xf7, 0xce, 0xf9, 0x28, 0x5c, 0xeb, 0xb3, 0x70, 0xad, 0xaf, 0xc2, 0xb5, 0xde, 0xbe,
-	0xdd, 0xad, 0xd7, 0x76, 0x75, 0xcb, 0xb0, 0x6d, 0xce, 0x38, 0xfc, 0x09, 0x00, 0x00, 0xff, 0xff,
-	0x6c, 0xdc, 0xb6, 0x36, 0x57, 0x02, 0x00, 0x00,
+	// 361 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xbf, 0x4e, 0xe3, 0x40,
+	0x10, 0xc6, 0xcf, 0xf1, 0x5d, 0x12, 0x4d, 0xa2, 0x53, 0xce, 0xc5, 0x9d, 0x95, 0xc2, 0x3a, 0xb9,
+	0x8a, 0x04, 0xb2, 0x05, 0x41, 0x29, 0x68, 0xf8, 0x23, 0xd1, 0x20, 0x5c, 0xb0, 0x44, 0x42, 0xa2,
+	0x89, 0xd6, 0xf6, 0x28, 0xb1, 0x94, 0xb5, 0x9d, 0xdd, 0x71, 0x91, 0x9e, 0x07, 0xa0, 0xe1, 0x9d,
+	0x28, 0x79, 0x04, 0x14, 0x5e, 0x04, 0x79, 0x1d, 0x07, 0x23, 0xba, 0x34, 0x2e, 0xe6, 0xfb, 0x7d,
+	0x9f, 0xbf, 0xb1, 0x07, 0xce, 0xe7, 0x09, 0x2d, 0x8a, 0xd0, 0x8b, 0x32, 0xe1, 0x8b, 0x71, 0x1c,
+	0xfa, 0x62, 0xec, 0x2b, 0x19, 0xf9, 0xab, 0x02, 0xe5, 0xda, 0x9f, 0x63, 0x8a, 0x92, 0x13, 0xc6,
+	0x7e, 0x2e, 0x33, 0xca, 0xca, 0xa7, 0xc8, 0x43, 0x5f, 0xa2, 0xc8, 0x08, 0x3d, 0x3d, 0xb3, 0xa0,
+	0x1c, 0x22, 0x2d, 0xb0, 0x50, 0xc3, 0xb3, 0x7d, 0xd2, 0x68, 0x9d, 0xa3, 0xaa, 0xc2, 0xdc, 0x47,
+	0x03, 0xfa, 0xf7, 0x32, 0x21, 0x64, 0xb8, 0x2a, 0x50, 0x91, 0x35, 0x01, 0xa0, 0x44, 0xa0, 0x42,
+	0x99, 0xa0, 0xb2, 0x8d, 0xff, 0xe6, 0xa8, 0x77, 0xfc, 0xd7, 0xfb, 0x7c, 0xa5, 0x37, 0x4d, 0x04,
+	0xde, 0x69, 0x95, 0x35, 0x48, 0x6b, 0x02, 0x5d, 0x81, 0xc4, 0x63, 0x4e, 0xdc, 0x36, 0xb5, 0x6b,
+	0xd8, 0x74, 0x05, 0x48, 0x32, 0x89, 0x82, 0x2d, 0xc1, 0x76, 0xec, 0xf5, 0xcf, 0x6e, 0x6b, 0x60,
+	0xba, 0xa7, 0xd0, 0x63, 0xc8, 0xe3, 0xba, 0xc4, 0x01, 0x74, 0x56, 0x45, 0xb3, 0xc1, 0x9f, 0x66,
+	0xd6, 0x6d, 0xb9, 0x1c, 0xab, 0x09, 0xf7, 0x02, 0xfa, 0x95, 0x57, 0xe5, 0x59, 0xaa, 0xd0, 0x3a,
+	0x82, 0x8e, 0x44, 0x55, 0x2c, 0xa9, 0x36, 0xff, 0xfb, 0x6e, 0xd6, 0x3a, 0xab, 0x39, 0xf7, 0xd9,
+	0x80, 0x5f, 0x5a, 0xb0, 0x0e, 0xc1, 0x52, 0xc4, 0x25, 0xcd, 0xf4, 0x6a, 0xc4, 0x45, 0x3e, 0x13,
+	0x65, 0x8e, 0x31, 0x32, 0xd9, 0x40, 0x2b, 0xd3, 0x5a, 0x08, 0x94, 0x35, 0x82, 0x01, 0xa6, 0xf1,
+	0x57, 0xb6, 0xa5, 0xd9, 0xdf, 0x98, 0xc6, 0x4d, 0xf2, 0x04, 0xba, 0x82, 0x53, 0xb4, 0x40, 0xa9,
+	0xb6, 0x9f, 0xc7, 0x6e, 0xb6, 0xba, 0xe1, 0x21, 0x2e, 0x83, 0x0a, 0x60, 0x3b, 0xd2, 0xbd, 0x82,
+	0x5e, 0xa3, 0xef, 0xbe, 0xff, 0xe6, 0xd2, 0x7e, 0xd9, 0x38, 0xc6, 0xeb, 0xc6, 0x31, 0xde, 0x36,
+	0x8e, 0xf1, 0xf4, 0xee, 0xfc, 0x78, 0x68, 0x57, 0xa7, 0x10, 0xb6, 0xf5, 0x15, 0x8c, 0x3f, 0x06,
+	0x00, 0x72, 0xfb, 0x7e, 0x19, 0x96, 0x02, 0x00, 0x00,
 }
@@ -64,6 +64,47 @@ func (x LabelMatcher_Type) String() string {
 }
 func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4, 0} }
 
+type MetricMetadata_MetricType int32
+
+const (
+	MetricMetadata_UNKNOWN        MetricMetadata_MetricType = 0
+	MetricMetadata_COUNTER        MetricMetadata_MetricType = 1
+	MetricMetadata_GAUGE          MetricMetadata_MetricType = 2
+	MetricMetadata_HISTOGRAM      MetricMetadata_MetricType = 3
+	MetricMetadata_GAUGEHISTOGRAM MetricMetadata_MetricType = 4
+	MetricMetadata_SUMMARY        MetricMetadata_MetricType = 5
+	MetricMetadata_INFO           MetricMetadata_MetricType = 6
+	MetricMetadata_STATESET       MetricMetadata_MetricType = 7
+)
+
+var MetricMetadata_MetricType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "COUNTER",
+	2: "GAUGE",
+	3: "HISTOGRAM",
+	4: "GAUGEHISTOGRAM",
+	5: "SUMMARY",
+	6: "INFO",
+	7: "STATESET",
+}
+var MetricMetadata_MetricType_value = map[string]int32{
+	"UNKNOWN":        0,
+	"COUNTER":        1,
+	"GAUGE":          2,
+	"HISTOGRAM":      3,
+	"GAUGEHISTOGRAM": 4,
+	"SUMMARY":        5,
+	"INFO":           6,
+	"STATESET":       7,
+}
+
+func (x MetricMetadata_MetricType) String() string {
+	return proto.EnumName(MetricMetadata_MetricType_name, int32(x))
+}
+func (MetricMetadata_MetricType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorTypes, []int{6, 0}
+}
+
 type Sample struct {
 	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
 	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
@@ -89,8 +130,9 @@ func (m *Sample) GetTimestamp() int64 {
 }
 
 type TimeSeries struct {
-	Labels  []*Label  `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
-	Samples []*Sample `protobuf:"bytes,2,rep,name=samples" json:"samples,omitempty"`
+	Labels    []*Label    `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
+	Samples   []*Sample   `protobuf:"bytes,2,rep,name=samples" json:"samples,omitempty"`
+	Exemplars []*Exemplar `protobuf:"bytes,3,rep,name=exemplars" json:"exemplars,omitempty"`
 }
 
 func (m *TimeSeries) Reset()                    { *m = TimeSeries{} }
@@ -112,6 +154,13 @@ func (m *TimeSeries) GetSamples() []*Sample {
 	return nil
 }
 
+func (m *TimeSeries) GetExemplars() []*Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
 type Label struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -185,13 +234,88 @@ func (m *LabelMatcher) GetValue() []byte {
 	return nil
 }
 
+type Exemplar struct {
+	Labels    []*Label `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
+	Value     float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Exemplar) Reset()                    { *m = Exemplar{} }
+func (m *Exemplar) String() string            { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()               {}
+func (*Exemplar) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{5} }
+
+func (m *Exemplar) GetLabels() []*Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type MetricMetadata struct {
+	Type             MetricMetadata_MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=prometheus.MetricMetadata_MetricType" json:"type,omitempty"`
+	MetricFamilyName string                    `protobuf:"bytes,2,opt,name=metric_family_name,json=metricFamilyName,proto3" json:"metric_family_name,omitempty"`
+	Help             string                    `protobuf:"bytes,4,opt,name=help,proto3" json:"help,omitempty"`
+	Unit             string                    `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (m *MetricMetadata) Reset()                    { *m = MetricMetadata{} }
+func (m *MetricMetadata) String() string            { return proto.CompactTextString(m) }
+func (*MetricMetadata) ProtoMessage()               {}
+func (*MetricMetadata) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{6} }
+
+func (m *MetricMetadata) GetType() MetricMetadata_MetricType {
+	if m != nil {
+		return m.Type
+	}
+	return MetricMetadata_UNKNOWN
+}
+
+func (m *MetricMetadata) GetMetricFamilyName() string {
+	if m != nil {
+		return m.MetricFamilyName
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetHelp() string {
+	if m != nil {
+		return m.Help
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Sample)(nil), "prometheus.Sample")
 	proto.RegisterType((*TimeSeries)(nil), "prometheus.TimeSeries")
 	proto.RegisterType((*Label)(nil), "prometheus.Label")
 	proto.RegisterType((*Labels)(nil), "prometheus.Labels")
 	proto.RegisterType((*LabelMatcher)(nil), "prometheus.LabelMatcher")
+	proto.RegisterType((*Exemplar)(nil), "prometheus.Exemplar")
+	proto.RegisterType((*MetricMetadata)(nil), "prometheus.MetricMetadata")
 	proto.RegisterEnum("prometheus.LabelMatcher_Type", LabelMatcher_Type_name, LabelMatcher_Type_value)
+	proto.RegisterEnum("prometheus.MetricMetadata_MetricType", MetricMetadata_MetricType_name, MetricMetadata_MetricType_value)
 }
 func (m *Sample) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -261,6 +385,18 @@ func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -359,6 +495,88 @@ func (m *LabelMatcher) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, msg := range m.Labels {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Value != 0 {
+		dAtA[i] = 0x11
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
+func (m *MetricMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+	}
+	if len(m.MetricFamilyName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.MetricFamilyName)))
+		i += copy(dAtA[i:], m.MetricFamilyName)
+	}
+	if len(m.Help) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
+		i += copy(dAtA[i:], m.Help)
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	return i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -395,6 +613,12 @@ func (m *TimeSeries) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -441,6 +665,45 @@ func (m *LabelMatcher) Size() (n int) {
 	return n
 }
 
+func (m *Exemplar) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *MetricMetadata) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.MetricFamilyName)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Help)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	for {
 		n++
@@ -625,6 +888,37 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, &Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -970,6 +1264,273 @@ func (m *LabelMatcher) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, &Label{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MetricMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (MetricMetadata_MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricFamilyName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricFamilyName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Help = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1080,28 +1641,41 @@ func init() {
 }
 
 var fileDescriptorTypes = []byte{
-	// 368 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x8a, 0xdb, 0x30,
-	0x10, 0x86, 0x23, 0xdb, 0x71, 0xe8, 0x34, 0x14, 0x57, 0xf4, 0x60, 0x4a, 0xeb, 0x06, 0x9f, 0x5c,
-	0x68, 0x2d, 0x92, 0x9c, 0x0a, 0x85, 0x42, 0xc0, 0xb7, 0xb4, 0x10, 0x25, 0xa7, 0xde, 0xec, 0x64,
-	0xea, 0x18, 0xac, 0xd8, 0xb5, 0xe4, 0x42, 0xde, 0xa2, 0x97, 0xbe, 0x53, 0x8e, 0x7d, 0x82, 0x65,
-	0xc9, 0xbe, 0xc8, 0x62, 0x29, 0xd9, 0x04, 0x76, 0x61, 0x2f, 0x66, 0xe6, 0xf7, 0xff, 0xcf, 0x7c,
-	0x48, 0x82, 0x6f, 0x79, 0xa1, 0xb6, 0x6d, 0x16, 0xaf, 0x2b, 0xc1, 0xc4, 0x74, 0x93, 0x31, 0x31,
-	0x65, 0xb2, 0x59, 0xb3, 0xdf, 0x2d, 0x36, 0x7b, 0x96, 0xe3, 0x0e, 0x9b, 0x54, 0xe1, 0x86, 0xd5,
-	0x4d, 0xa5, 0xaa, 0xee, 0x2b, 0xea, 0x8c, 0xa9, 0x7d, 0x8d, 0x32, 0xd6, 0x12, 0x85, 0x4e, 0x43,
-	0xb5, 0xc5, 0x56, 0xbe, 0xfd, 0x7c, 0x35, 0x2c, 0xaf, 0xf2, 0xca, 0xa4, 0xb2, 0xf6, 0x97, 0xee,
-	0xcc, 0x88, 0xae, 0x32, 0xd1, 0xf0, 0x2b, 0xb8, 0xcb, 0x54, 0xd4, 0x25, 0xd2, 0x37, 0xd0, 0xff,
-	0x93, 0x96, 0x2d, 0xfa, 0x64, 0x44, 0x22, 0xc2, 0x4d, 0x43, 0xdf, 0xc1, 0x0b, 0x55, 0x08, 0x94,
-	0x2a, 0x15, 0xb5, 0x6f, 0x8d, 0x48, 0x64, 0xf3, 0x8b, 0x10, 0x22, 0xc0, 0xaa, 0x10, 0xb8, 0xc4,
-	0xa6, 0x40, 0x49, 0x3f, 0x82, 0x5b, 0xa6, 0x19, 0x96, 0xd2, 0x27, 0x23, 0x3b, 0x7a, 0x39, 0x79,
-	0x1d, 0x5f, 0xb8, 0xe2, 0x79, 0xf7, 0x87, 0x9f, 0x0c, 0xf4, 0x13, 0x0c, 0xa4, 0x5e, 0x2b, 0x7d,
-	0x4b, 0x7b, 0xe9, 0xb5, 0xd7, 0x10, 0xf1, 0xb3, 0x25, 0x1c, 0x43, 0x5f, 0xc7, 0x29, 0x05, 0x67,
-	0x97, 0x0a, 0x83, 0x38, 0xe4, 0xba, 0xbe, 0x70, 0x5b, 0x5a, 0x34, 0x4d, 0xf8, 0x05, 0xdc, 0xb9,
-	0x59, 0xc5, 0x9e, 0xa5, 0x9a, 0x39, 0x87, 0x9b, 0x0f, 0xbd, 0x33, 0x5b, 0xf8, 0x8f, 0xc0, 0x50,
-	0xeb, 0xdf, 0x53, 0xb5, 0xde, 0x62, 0x43, 0xc7, 0xe0, 0x74, 0xa7, 0xad, 0xb7, 0xbe, 0x9a, 0xbc,
-	0x7f, 0x94, 0x3f, 0xf9, 0xe2, 0xd5, 0xbe, 0x46, 0xae, 0xad, 0x0f, 0xa0, 0xd6, 0x53, 0xa0, 0xf6,
-	0x35, 0x68, 0x04, 0x4e, 0x97, 0xa3, 0x2e, 0x58, 0xc9, 0xc2, 0xeb, 0xd1, 0x01, 0xd8, 0x3f, 0x92,
-	0x85, 0x47, 0x3a, 0x81, 0x27, 0x9e, 0xa5, 0x05, 0x9e, 0x78, 0xf6, 0xcc, 0x3f, 0x1c, 0x03, 0xf2,
-	0xff, 0x18, 0x90, 0xdb, 0x63, 0x40, 0xfe, 0xde, 0x05, 0xbd, 0x9f, 0xae, 0x79, 0x0b, 0x99, 0xab,
-	0xef, 0x72, 0x7a, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x6f, 0x4a, 0x7b, 0x68, 0x49, 0x02, 0x00, 0x00,
+	// 567 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xd1, 0x6a, 0xd4, 0x4c,
+	0x14, 0xde, 0x49, 0xb2, 0xd9, 0xee, 0x69, 0xff, 0x92, 0x7f, 0xe8, 0x45, 0x10, 0x5d, 0x4b, 0x40,
+	0x58, 0xa1, 0x6e, 0x68, 0x7b, 0x55, 0x10, 0x64, 0x2b, 0x69, 0x2d, 0x36, 0x59, 0x3a, 0xc9, 0x22,
+	0x7a, 0x53, 0x26, 0xdb, 0xe9, 0x6e, 0x20, 0xd3, 0x8d, 0xc9, 0x44, 0xdc, 0xb7, 0xf0, 0xc6, 0x0b,
+	0xc1, 0x07, 0xea, 0xa5, 0x4f, 0x20, 0x52, 0x5f, 0x44, 0x66, 0xb2, 0x6d, 0xb2, 0x5a, 0x10, 0x6f,
+	0xc2, 0x39, 0xdf, 0xf9, 0x4e, 0xce, 0x77, 0xbe, 0x99, 0x81, 0x17, 0xd3, 0x44, 0xcc, 0xca, 0x78,
+	0x30, 0x99, 0x73, 0x97, 0xef, 0x5f, 0xc4, 0x2e, 0xdf, 0x77, 0x8b, 0x7c, 0xe2, 0xbe, 0x2f, 0x59,
+	0xbe, 0x70, 0xa7, 0xec, 0x8a, 0xe5, 0x54, 0xb0, 0x0b, 0x37, 0xcb, 0xe7, 0x62, 0x2e, 0xbf, 0x3c,
+	0x8b, 0x5d, 0xb1, 0xc8, 0x58, 0x31, 0x50, 0x10, 0x06, 0x89, 0x31, 0x31, 0x63, 0x65, 0xf1, 0xe0,
+	0x59, 0xe3, 0x67, 0xd3, 0xf9, 0x74, 0x5e, 0x75, 0xc5, 0xe5, 0xa5, 0xca, 0xaa, 0x5f, 0xc8, 0xa8,
+	0x6a, 0x75, 0x9e, 0x83, 0x19, 0x52, 0x9e, 0xa5, 0x0c, 0x6f, 0x41, 0xfb, 0x03, 0x4d, 0x4b, 0x66,
+	0xa3, 0x6d, 0xd4, 0x47, 0xa4, 0x4a, 0xf0, 0x43, 0xe8, 0x8a, 0x84, 0xb3, 0x42, 0x50, 0x9e, 0xd9,
+	0xda, 0x36, 0xea, 0xeb, 0xa4, 0x06, 0x9c, 0x2f, 0x08, 0x20, 0x4a, 0x38, 0x0b, 0x59, 0x9e, 0xb0,
+	0x02, 0x3f, 0x05, 0x33, 0xa5, 0x31, 0x4b, 0x0b, 0x1b, 0x6d, 0xeb, 0xfd, 0xf5, 0xbd, 0xff, 0x07,
+	0xb5, 0xb0, 0xc1, 0xa9, 0xac, 0x90, 0x25, 0x01, 0xef, 0x40, 0xa7, 0x50, 0x73, 0x0b, 0x5b, 0x53,
+	0x5c, 0xdc, 0xe4, 0x56, 0x92, 0xc8, 0x2d, 0x05, 0xef, 0x41, 0x97, 0x7d, 0x64, 0x3c, 0x4b, 0x69,
+	0x5e, 0xd8, 0xba, 0xe2, 0x6f, 0x35, 0xf9, 0xde, 0xb2, 0x48, 0x6a, 0x9a, 0xb3, 0x0b, 0x6d, 0x35,
+	0x12, 0x63, 0x30, 0xae, 0x28, 0xaf, 0xf6, 0xda, 0x20, 0x2a, 0xae, 0x97, 0xd5, 0x14, 0x58, 0x25,
+	0xce, 0x01, 0x98, 0xa7, 0x95, 0x3c, 0xf7, 0xaf, 0x9b, 0x1c, 0x1a, 0xd7, 0xdf, 0x1f, 0xb7, 0x6e,
+	0xf7, 0x71, 0x3e, 0x23, 0xd8, 0x50, 0xb8, 0x4f, 0xc5, 0x64, 0xc6, 0x72, 0xbc, 0x0b, 0x86, 0x3c,
+	0x22, 0x35, 0x75, 0x73, 0xef, 0xd1, 0x1f, 0xfd, 0x4b, 0xde, 0x20, 0x5a, 0x64, 0x8c, 0x28, 0xea,
+	0x9d, 0x50, 0xed, 0x3e, 0xa1, 0x7a, 0x53, 0x68, 0x1f, 0x0c, 0xd9, 0x87, 0x4d, 0xd0, 0xbc, 0x33,
+	0xab, 0x85, 0x3b, 0xa0, 0x07, 0xde, 0x99, 0x85, 0x24, 0x40, 0x3c, 0x4b, 0x53, 0x00, 0xf1, 0x2c,
+	0xdd, 0x49, 0x60, 0xed, 0xd6, 0x9c, 0x7f, 0x39, 0x9e, 0x15, 0x7f, 0xee, 0xbf, 0x0c, 0xfa, 0xef,
+	0x97, 0xe1, 0xab, 0x06, 0x9b, 0x3e, 0x13, 0x79, 0x32, 0xf1, 0x99, 0xa0, 0x17, 0x54, 0x50, 0x7c,
+	0xb0, 0x62, 0xc2, 0x93, 0xe6, 0xbc, 0x55, 0xe6, 0x32, 0x6d, 0x98, 0xb1, 0x03, 0x98, 0x2b, 0xec,
+	0xfc, 0x92, 0xf2, 0x24, 0x5d, 0x9c, 0xdf, 0x59, 0xd3, 0x25, 0x56, 0x55, 0x39, 0x52, 0x85, 0x40,
+	0xda, 0x84, 0xc1, 0x98, 0xb1, 0x34, 0xb3, 0x0d, 0x55, 0x57, 0xb1, 0xc4, 0xca, 0xab, 0x44, 0xd8,
+	0xed, 0x0a, 0x93, 0xb1, 0xb3, 0x00, 0xa8, 0x27, 0xe1, 0x75, 0xe8, 0x8c, 0x83, 0xd7, 0xc1, 0xe8,
+	0x4d, 0x60, 0xb5, 0x64, 0xf2, 0x72, 0x34, 0x0e, 0x22, 0x8f, 0x58, 0x08, 0x77, 0xa1, 0x7d, 0x3c,
+	0x1c, 0x1f, 0x4b, 0x2b, 0xff, 0x83, 0xee, 0xab, 0x93, 0x30, 0x1a, 0x1d, 0x93, 0xa1, 0x6f, 0xe9,
+	0x18, 0xc3, 0xa6, 0xaa, 0xd4, 0x98, 0x21, 0x5b, 0xc3, 0xb1, 0xef, 0x0f, 0xc9, 0x5b, 0xab, 0x8d,
+	0xd7, 0xc0, 0x38, 0x09, 0x8e, 0x46, 0x96, 0x89, 0x37, 0x60, 0x2d, 0x8c, 0x86, 0x91, 0x17, 0x7a,
+	0x91, 0xd5, 0x39, 0xb4, 0xaf, 0x6f, 0x7a, 0xe8, 0xdb, 0x4d, 0x0f, 0xfd, 0xb8, 0xe9, 0xa1, 0x4f,
+	0x3f, 0x7b, 0xad, 0x77, 0x66, 0xf5, 0x94, 0x63, 0x53, 0x3d, 0xc5, 0xfd, 0x5f, 0x03, 0x00, 0x0f,
+	0xa6, 0x95, 0x11, 0x08, 0x04, 0x00, 0x00,
 }
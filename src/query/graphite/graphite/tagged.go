@@ -0,0 +1,163 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m3db/m3/src/query/graphite/errors"
+)
+
+const (
+	// TaggedQuerySeparator separates the path of a tagged query from its tag
+	// expressions, i.e. foo.bar;env=prod;dc!=us-east.
+	TaggedQuerySeparator = ";"
+
+	// TaggedNameTag is the tag name graphite uses to refer to the path of a
+	// series in tag expressions.
+	TaggedNameTag = "name"
+)
+
+// TagExpressionType is the type of a graphite tag expression.
+type TagExpressionType int
+
+const (
+	// TagExpressionEqual matches series with a tag equal to the value, or
+	// without the tag if the value is empty.
+	TagExpressionEqual TagExpressionType = iota
+	// TagExpressionNotEqual matches series without a tag equal to the value,
+	// or with the tag if the value is empty.
+	TagExpressionNotEqual
+	// TagExpressionRegexp matches series with a tag value matching the
+	// regular expression.
+	TagExpressionRegexp
+	// TagExpressionNotRegexp matches series without a tag value matching the
+	// regular expression.
+	TagExpressionNotRegexp
+)
+
+// TagExpression is a graphite tag expression as used by seriesByTag.
+type TagExpression struct {
+	Type  TagExpressionType
+	Name  string
+	Value string
+}
+
+// Positive returns whether the expression can only match series which have
+// the tag, at least one such expression is required to query by tags.
+func (e TagExpression) Positive() bool {
+	switch e.Type {
+	case TagExpressionEqual:
+		return e.Value != ""
+	case TagExpressionNotEqual:
+		return e.Value == ""
+	case TagExpressionRegexp:
+		return true
+	}
+	return false
+}
+
+// ParseTagExpression parses a tag expression of the form tag=value,
+// tag!=value, tag=~regexp or tag!=~regexp.
+func ParseTagExpression(expr string) (TagExpression, error) {
+	idx := strings.IndexByte(expr, '=')
+	if idx <= 0 {
+		return TagExpression{}, errors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression: %s", expr))
+	}
+
+	var (
+		name     = expr[:idx]
+		value    = expr[idx+1:]
+		exprType = TagExpressionEqual
+	)
+	if strings.HasSuffix(name, "!") {
+		name = name[:len(name)-1]
+		exprType = TagExpressionNotEqual
+	}
+	if strings.HasPrefix(value, "~") {
+		value = value[1:]
+		if exprType == TagExpressionEqual {
+			exprType = TagExpressionRegexp
+		} else {
+			exprType = TagExpressionNotRegexp
+		}
+	}
+
+	if name == "" {
+		return TagExpression{}, errors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression: %s", expr))
+	}
+
+	return TagExpression{Type: exprType, Name: name, Value: value}, nil
+}
+
+// SplitTaggedQuery splits a tagged query into its path and tag expressions,
+// the path is empty if the query only contains tag expressions.
+func SplitTaggedQuery(query string) (string, []string) {
+	parts := strings.Split(query, TaggedQuerySeparator)
+	return parts[0], parts[1:]
+}
+
+// TaggedQuery converts tag expressions, as given to seriesByTag, to a tagged
+// query, i.e. path;tag1=value1;tag2!=value2. The name expression is used as
+// the path and at least one expression must match a tag value.
+func TaggedQuery(tagExpressions []string) (string, error) {
+	var (
+		path     string
+		exprs    = make([]string, 0, len(tagExpressions))
+		positive bool
+	)
+	for _, e := range tagExpressions {
+		if strings.Contains(e, TaggedQuerySeparator) {
+			return "", errors.NewInvalidParamsError(
+				fmt.Errorf("invalid tag expression: %s", e))
+		}
+
+		expr, err := ParseTagExpression(e)
+		if err != nil {
+			return "", err
+		}
+
+		if expr.Name == TaggedNameTag {
+			if expr.Type != TagExpressionEqual || expr.Value == "" || path != "" {
+				return "", errors.NewInvalidParamsError(
+					fmt.Errorf("only a single name=<path> expression is supported: %s", e))
+			}
+
+			path = expr.Value
+			positive = true
+			continue
+		}
+
+		positive = positive || expr.Positive()
+		exprs = append(exprs, e)
+	}
+
+	if !positive {
+		return "", errors.NewInvalidParamsError(errors.New(
+			"tagged query requires at least one expression matching a tag value"))
+	}
+
+	return strings.Join(append([]string{path}, exprs...),
+		TaggedQuerySeparator), nil
+}
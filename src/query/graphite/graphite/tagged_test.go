@@ -0,0 +1,91 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected TagExpression
+		positive bool
+	}{
+		{"env=prod", TagExpression{Type: TagExpressionEqual, Name: "env", Value: "prod"}, true},
+		{"env=", TagExpression{Type: TagExpressionEqual, Name: "env"}, false},
+		{"env!=prod", TagExpression{Type: TagExpressionNotEqual, Name: "env", Value: "prod"}, false},
+		{"env!=", TagExpression{Type: TagExpressionNotEqual, Name: "env"}, true},
+		{"env=~pr.*", TagExpression{Type: TagExpressionRegexp, Name: "env", Value: "pr.*"}, true},
+		{"env!=~pr.*", TagExpression{Type: TagExpressionNotRegexp, Name: "env", Value: "pr.*"}, false},
+		{"query=a=b", TagExpression{Type: TagExpressionEqual, Name: "query", Value: "a=b"}, true},
+	}
+
+	for _, test := range tests {
+		expr, err := ParseTagExpression(test.expr)
+		require.NoError(t, err, test.expr)
+		assert.Equal(t, test.expected, expr, test.expr)
+		assert.Equal(t, test.positive, expr.Positive(), test.expr)
+	}
+
+	for _, expr := range []string{"", "env", "=prod", "!=prod"} {
+		_, err := ParseTagExpression(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestSplitTaggedQuery(t *testing.T) {
+	path, exprs := SplitTaggedQuery("foo.bar")
+	assert.Equal(t, "foo.bar", path)
+	assert.Equal(t, 0, len(exprs))
+
+	path, exprs = SplitTaggedQuery("foo.*;env=prod;dc!=us-east")
+	assert.Equal(t, "foo.*", path)
+	assert.Equal(t, []string{"env=prod", "dc!=us-east"}, exprs)
+
+	path, exprs = SplitTaggedQuery(";env=prod")
+	assert.Equal(t, "", path)
+	assert.Equal(t, []string{"env=prod"}, exprs)
+}
+
+func TestTaggedQuery(t *testing.T) {
+	query, err := TaggedQuery([]string{"env=prod", "dc=~us-.*"})
+	require.NoError(t, err)
+	assert.Equal(t, ";env=prod;dc=~us-.*", query)
+
+	for _, exprs := range [][]string{
+		nil,
+		{"env!=prod"},
+		{"env="},
+		{"name=~foo.*"},
+		{"name=foo", "name=bar"},
+		{"env=prod;dc=us-east"},
+		{"=prod"},
+		{"prod"},
+	} {
+		_, err := TaggedQuery(exprs)
+		assert.Error(t, err, fmt.Sprintf("%v", exprs))
+	}
+}
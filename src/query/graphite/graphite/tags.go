@@ -20,7 +20,10 @@
 
 package graphite
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+)
 
 const (
 	// graphiteFormat is the format for graphite metric tag names, which will be
@@ -40,6 +43,11 @@ const (
 	MatchAllPattern = ".*"
 )
 
+var (
+	tagNamePrefix = []byte("__g")
+	tagNameSuffix = []byte("__")
+)
+
 var (
 	// Should never be modified after init().
 	preFormattedTagNames [][]byte
@@ -62,6 +70,25 @@ func TagName(idx int) []byte {
 	return []byte(fmt.Sprintf(graphiteFormat, idx))
 }
 
+// IsTagName returns whether the given tag name is a graphite path tag name,
+// i.e. __g0__.
+func IsTagName(name []byte) bool {
+	prefixLen, suffixLen := len(tagNamePrefix), len(tagNameSuffix)
+	if len(name) <= prefixLen+suffixLen ||
+		!bytes.HasPrefix(name, tagNamePrefix) ||
+		!bytes.HasSuffix(name, tagNameSuffix) {
+		return false
+	}
+
+	for _, c := range name[prefixLen : len(name)-suffixLen] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
 func generateTagName(idx int) []byte {
 	return []byte(fmt.Sprintf(graphiteFormat, idx))
 }
@@ -33,3 +33,11 @@ func TestTagName(t *testing.T) {
 		require.Equal(t, expected, TagName(i))
 	}
 }
+
+func TestIsTagName(t *testing.T) {
+	require.True(t, IsTagName(TagName(0)))
+	require.True(t, IsTagName(TagName(2*numPreFormattedTagNames)))
+	for _, name := range []string{"", "__g__", "__gx__", "__g1_", "_g1__", "name", "g1"} {
+		require.False(t, IsTagName([]byte(name)), name)
+	}
+}
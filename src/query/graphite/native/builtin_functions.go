@@ -32,6 +32,7 @@ import (
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -261,6 +262,18 @@ func scale(ctx *common.Context, input singlePathSpec, scale float64) (ts.SeriesL
 	)
 }
 
+// seriesByTag returns the series matching all of the given tag expressions,
+// e.g. seriesByTag('name=foo.*', 'env=prod'). The name expression matches the
+// path of the series and only supports equality to a path glob.
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	query, err := graphite.TaggedQuery(tagExpressions)
+	if err != nil {
+		return ts.SeriesList{}, err
+	}
+
+	return newFetchExpression(query).Execute(ctx)
+}
+
 // scaleToSeconds makes a wildcard seriesList and returns "value per seconds"
 func scaleToSeconds(
 	ctx *common.Context,
@@ -1901,6 +1914,7 @@ func init() {
 	MustRegisterFunction(removeEmptySeries)
 	MustRegisterFunction(scale)
 	MustRegisterFunction(scaleToSeconds)
+	MustRegisterFunction(seriesByTag)
 	MustRegisterFunction(sortByMaxima)
 	MustRegisterFunction(sortByName)
 	MustRegisterFunction(sortByTotal)
@@ -2774,6 +2774,27 @@ func TestThreshold(t *testing.T) {
 	require.Equal(t, "1.000", results[0].Name())
 }
 
+func TestSeriesByTag(t *testing.T) {
+	engine := NewEngine(&common.MovingAverageStorage{
+		StepMillis: 10000,
+		Values:     []float64{1, 2},
+	})
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  testMovingAverageStart,
+		End:    testMovingAverageEnd,
+		Engine: engine,
+	})
+	defer ctx.Close()
+
+	expr, err := engine.Compile("seriesByTag('env=prod', 'name=foo.*', 'dc!=us-east')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Values))
+	// The storage names the series after the query it received.
+	assert.Equal(t, "foo.*;env=prod;dc!=us-east", res.Values[0].Name())
+}
+
 func TestFunctionsRegistered(t *testing.T) {
 	fnames := []string{
 		"abs",
@@ -2845,6 +2866,7 @@ func TestFunctionsRegistered(t *testing.T) {
 		"removeEmptySeries",
 		"scale",
 		"scaleToSeconds",
+		"seriesByTag",
 		"sortByMaxima",
 		"sortByName",
 		"sortByTotal",
@@ -21,6 +21,10 @@
 package storage
 
 import (
+	"fmt"
+	"regexp"
+
+	"github.com/m3db/m3/src/query/graphite/errors"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 )
@@ -65,3 +69,52 @@ func matcherTerminator(count int) models.Matcher {
 		Name: graphite.TagName(count),
 	}
 }
+
+// convertTagExpressionsToMatchers converts graphite tag expressions to tag
+// matchers, note that graphite regexp expressions are only anchored at the
+// start of the tag value.
+func convertTagExpressionsToMatchers(exprs []string) (models.Matchers, error) {
+	matchers := make(models.Matchers, 0, len(exprs))
+	for _, e := range exprs {
+		expr, err := graphite.ParseTagExpression(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr.Name == graphite.TaggedNameTag {
+			// The name is matched using the path of the query.
+			return nil, errors.NewInvalidParamsError(
+				fmt.Errorf("name tag expression must be the query path: %s", e))
+		}
+
+		matcher := models.Matcher{Name: []byte(expr.Name)}
+		switch expr.Type {
+		case graphite.TagExpressionEqual:
+			matcher.Type = models.MatchEqual
+			if expr.Value == "" {
+				matcher.Type = models.MatchNotField
+			}
+		case graphite.TagExpressionNotEqual:
+			matcher.Type = models.MatchNotEqual
+			if expr.Value == "" {
+				matcher.Type = models.MatchField
+			}
+		case graphite.TagExpressionRegexp, graphite.TagExpressionNotRegexp:
+			matcher.Type = models.MatchRegexp
+			if expr.Type == graphite.TagExpressionNotRegexp {
+				matcher.Type = models.MatchNotRegexp
+			}
+			expr.Value = "(?:" + expr.Value + ").*"
+			if _, err := regexp.Compile(expr.Value); err != nil {
+				return nil, errors.NewInvalidParamsError(err)
+			}
+		}
+
+		if matcher.Type != models.MatchField && matcher.Type != models.MatchNotField {
+			matcher.Value = []byte(expr.Value)
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, nil
+}
@@ -83,3 +83,23 @@ func TestMatcherTerminator(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	}
 }
+
+func TestConvertTagExpressionsToMatchers(t *testing.T) {
+	actual, err := convertTagExpressionsToMatchers([]string{
+		"env=prod", "dc!=us-east", "host=~web", "rack!=~a|b", "team=", "owner!=",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("env"), Value: []byte("prod")},
+		{Type: models.MatchNotEqual, Name: []byte("dc"), Value: []byte("us-east")},
+		{Type: models.MatchRegexp, Name: []byte("host"), Value: []byte("(?:web).*")},
+		{Type: models.MatchNotRegexp, Name: []byte("rack"), Value: []byte("(?:a|b).*")},
+		{Type: models.MatchNotField, Name: []byte("team")},
+		{Type: models.MatchField, Name: []byte("owner")},
+	}, actual)
+
+	for _, expr := range []string{"env", "=prod", "name=foo", "host=~web("} {
+		_, err := convertTagExpressionsToMatchers([]string{expr})
+		assert.Error(t, err, expr)
+	}
+}
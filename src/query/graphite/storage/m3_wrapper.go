@@ -71,23 +71,36 @@ func NewM3WrappedStorage(
 }
 
 // TranslateQueryToMatchersWithTerminator converts a graphite query to tag
-// matcher pairs, and adds a terminator matcher to the end.
+// matcher pairs, and adds a terminator matcher to the end. Queries for
+// tagged series, i.e. foo.*;env=prod, are converted to the tag matchers for
+// the tag expressions followed by the matchers for the path.
 func TranslateQueryToMatchersWithTerminator(
 	query string,
 ) (models.Matchers, error) {
-	metricLength := graphite.CountMetricParts(query)
+	path, exprs := graphite.SplitTaggedQuery(query)
+	tagMatchers, err := convertTagExpressionsToMatchers(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	metricLength := graphite.CountMetricParts(path)
+	if metricLength == 0 {
+		return nil, fmt.Errorf("invalid matcher format: %s", query)
+	}
+
 	// Add space for a terminator character.
-	matchersLength := metricLength + 1
-	matchers := make(models.Matchers, matchersLength)
+	matchersLength := len(tagMatchers) + metricLength + 1
+	matchers := make(models.Matchers, 0, matchersLength)
+	matchers = append(matchers, tagMatchers...)
 	for i := 0; i < metricLength; i++ {
-		metric := graphite.ExtractNthMetricPart(query, i)
+		metric := graphite.ExtractNthMetricPart(path, i)
 		if len(metric) > 0 {
 			m, err := convertMetricPartToMatcher(i, metric)
 			if err != nil {
 				return nil, err
 			}
 
-			matchers[i] = m
+			matchers = append(matchers, m)
 		} else {
 			return nil, fmt.Errorf("invalid matcher format: %s", query)
 		}
@@ -95,19 +108,20 @@ func TranslateQueryToMatchersWithTerminator(
 
 	// Add a terminator matcher at the end to ensure expansion is terminated at
 	// the last given metric part.
-	matchers[metricLength] = matcherTerminator(metricLength)
+	matchers = append(matchers, matcherTerminator(metricLength))
 	return matchers, nil
 }
 
 // GetQueryTerminatorTagName will return the name for the terminator matcher in
 // the given pattern. This is useful for filtering out any additional results.
 func GetQueryTerminatorTagName(query string) []byte {
-	metricLength := graphite.CountMetricParts(query)
+	path, _ := graphite.SplitTaggedQuery(query)
+	metricLength := graphite.CountMetricParts(path)
 	return graphite.TagName(metricLength)
 }
 
 func translateQuery(query string, opts FetchOptions) (*storage.FetchQuery, error) {
-	matchers, err := TranslateQueryToMatchersWithTerminator(query)
+	matchers, err := TranslateQueryToMatchers(query)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +137,32 @@ func translateQuery(query string, opts FetchOptions) (*storage.FetchQuery, error
 	}, nil
 }
 
+// TranslateQueryToMatchers converts a graphite query to tag matchers, unlike
+// TranslateQueryToMatchersWithTerminator it also accepts tagged queries
+// without a path, i.e. ;env=prod, which match series of any path.
+func TranslateQueryToMatchers(query string) (models.Matchers, error) {
+	path, exprs := graphite.SplitTaggedQuery(query)
+	if path != "" || len(exprs) == 0 {
+		return TranslateQueryToMatchersWithTerminator(query)
+	}
+
+	// Tagged queries without a path match series of any path, which requires
+	// at least one matcher that only matches series with the tag.
+	matchers, err := convertTagExpressionsToMatchers(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matchers {
+		if (m.Type == models.MatchEqual && len(m.Value) > 0) ||
+			m.Type == models.MatchRegexp || m.Type == models.MatchField {
+			return matchers, nil
+		}
+	}
+
+	return nil, fmt.Errorf("tagged query requires a positive tag expression: %s", query)
+}
+
 func translateTimeseries(
 	ctx xctx.Context,
 	m3list m3ts.SeriesList,
@@ -95,6 +95,41 @@ func TestTranslateQueryTrailingDot(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTranslateTaggedQuery(t *testing.T) {
+	query := `foo.b*;env=prod;dc=~us-.*`
+	translated, err := translateQuery(query, FetchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, query, translated.Raw)
+	assert.Equal(t, models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("env"), Value: []byte("prod")},
+		{Type: models.MatchRegexp, Name: []byte("dc"), Value: []byte("(?:us-.*).*")},
+		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("foo")},
+		{Type: models.MatchRegexp, Name: graphite.TagName(1), Value: []byte(`b[^\.]*`)},
+		{Type: models.MatchNotField, Name: graphite.TagName(2)},
+	}, translated.TagMatchers)
+	assert.Equal(t, graphite.TagName(2), GetQueryTerminatorTagName(query))
+
+	// Tagged queries without a path only match on tags.
+	translated, err = translateQuery(`;env=prod;dc!=us-east`, FetchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("env"), Value: []byte("prod")},
+		{Type: models.MatchNotEqual, Name: []byte("dc"), Value: []byte("us-east")},
+	}, translated.TagMatchers)
+
+	for _, query := range []string{
+		`;dc!=us-east`,
+		`;env=`,
+		`foo;env`,
+	} {
+		_, err := translateQuery(query, FetchOptions{})
+		assert.Error(t, err, query)
+	}
+
+	_, err = TranslateQueryToMatchersWithTerminator(`;env=prod`)
+	assert.Error(t, err)
+}
+
 func TestTranslateTimeseries(t *testing.T) {
 	ctx := xctx.New()
 	resolution := 10 * time.Second
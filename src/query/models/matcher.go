@@ -73,6 +73,48 @@ func (m Matcher) String() string {
 	return fmt.Sprintf("%s%s%q", m.Name, m.Type, m.Value)
 }
 
+// Matches returns whether the matcher matches the given tag value, where an
+// empty value is equivalent to the tag not being set.
+func (m Matcher) Matches(value []byte) bool {
+	switch m.Type {
+	case MatchEqual:
+		return bytes.Equal(m.Value, value)
+	case MatchNotEqual:
+		return !bytes.Equal(m.Value, value)
+	case MatchRegexp, MatchNotRegexp:
+		re := m.re
+		if re == nil {
+			var err error
+			re, err = regexp.Compile("^(?:" + string(m.Value) + ")$")
+			if err != nil {
+				return false
+			}
+		}
+
+		return re.Match(value) == (m.Type == MatchRegexp)
+	case MatchField:
+		return len(value) > 0
+	case MatchNotField:
+		return len(value) == 0
+	case MatchAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// MatchesTags returns whether all of the matchers match the given tags.
+func (m Matchers) MatchesTags(tags Tags) bool {
+	for _, matcher := range m {
+		value, _ := tags.Get(matcher.Name)
+		if !matcher.Matches(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ToTags converts Matchers to Tags
 // NB (braskin): this only works for exact matches
 func (m Matchers) ToTags(
@@ -47,6 +47,57 @@ func TestMatchType(t *testing.T) {
 	require.Equal(t, MatchEqual.String(), "=")
 }
 
+func TestMatcherMatches(t *testing.T) {
+	tests := []struct {
+		mType    MatchType
+		value    string
+		input    string
+		expected bool
+	}{
+		{MatchEqual, "foo", "foo", true},
+		{MatchEqual, "foo", "bar", false},
+		{MatchEqual, "", "", true},
+		{MatchNotEqual, "foo", "bar", true},
+		{MatchNotEqual, "foo", "foo", false},
+		{MatchRegexp, "fo+", "foo", true},
+		{MatchRegexp, "fo+", "food", false},
+		{MatchNotRegexp, "fo+", "bar", true},
+		{MatchNotRegexp, "fo+", "foo", false},
+		{MatchField, "", "foo", true},
+		{MatchField, "", "", false},
+		{MatchNotField, "", "", true},
+		{MatchNotField, "", "foo", false},
+		{MatchAll, "", "foo", true},
+	}
+
+	for _, tt := range tests {
+		m := newMatcher(t, tt.mType, tt.value)
+		assert.Equal(t, tt.expected, m.Matches([]byte(tt.input)),
+			"%s matching %q", m, tt.input)
+	}
+
+	// NB: matchers built without NewMatcher still match regular expressions.
+	m := Matcher{Type: MatchRegexp, Value: []byte("ba.")}
+	assert.True(t, m.Matches([]byte("bar")))
+}
+
+func TestMatchersMatchesTags(t *testing.T) {
+	tags := NewTags(2, nil).AddTags([]Tag{
+		{Name: []byte("a"), Value: []byte("1")},
+		{Name: []byte("b"), Value: []byte("2")},
+	})
+
+	matchers := func(ms ...Matcher) Matchers { return Matchers(ms) }
+	eq := func(name, value string) Matcher {
+		return Matcher{Type: MatchEqual, Name: []byte(name), Value: []byte(value)}
+	}
+
+	assert.True(t, matchers().MatchesTags(tags))
+	assert.True(t, matchers(eq("a", "1"), eq("b", "2")).MatchesTags(tags))
+	assert.False(t, matchers(eq("a", "1"), eq("b", "3")).MatchesTags(tags))
+	assert.True(t, matchers(eq("c", "")).MatchesTags(tags))
+}
+
 func TestMatchersFromEmptyString(t *testing.T) {
 	matchers, err := MatchersFromString("")
 	assert.NoError(t, err)
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models/strconv"
 	"github.com/m3db/m3/src/query/util/writer"
 
@@ -253,16 +254,22 @@ func (t Tags) prependMetaLen() (int, []int) {
 
 func (t Tags) graphiteID() []byte {
 	// TODO: pool these bytes.
-	id := make([]byte, t.idLenGraphite())
-	idx := 0
-	lastIndex := len(t.Tags) - 1
-	for _, tag := range t.Tags[:lastIndex] {
-		idx += copy(id[idx:], tag.Value)
-		id[idx] = graphiteSep
-		idx++
+	id := make([]byte, 0, t.idLenGraphite())
+	for i, tag := range t.Tags {
+		if !graphite.IsTagName(tag.Name) {
+			id = append(id, graphiteTagSep)
+			id = append(id, tag.Name...)
+			id = append(id, eq)
+			id = append(id, tag.Value...)
+			continue
+		}
+
+		if i > 0 {
+			id = append(id, graphiteSep)
+		}
+		id = append(id, tag.Value...)
 	}
 
-	copy(id[idx:], t.Tags[lastIndex].Value)
 	return id
 }
 
@@ -270,6 +277,9 @@ func (t Tags) idLenGraphite() int {
 	idLen := t.Len() - 1 // account for separators
 	for _, tag := range t.Tags {
 		idLen += len(tag.Value)
+		if !graphite.IsTagName(tag.Name) {
+			idLen += len(tag.Name) + 2 // account for the tag separators
+		}
 	}
 
 	return idLen
@@ -406,6 +416,16 @@ func (t sortableTagsNumericallyAsc) Swap(i, j int) {
 }
 func (t sortableTagsNumericallyAsc) Less(i, j int) bool {
 	iName, jName := t.Tags[i].Name, t.Tags[j].Name
+	iPath, jPath := graphite.IsTagName(iName), graphite.IsTagName(jName)
+	if iPath != jPath {
+		// Path tags precede any graphite tagged series tags.
+		return iPath
+	}
+
+	if !iPath {
+		return bytes.Compare(iName, jName) == -1
+	}
+
 	lenDiff := len(iName) - len(jName)
 	if lenDiff < 0 {
 		return true
@@ -82,6 +82,24 @@ func TestLongTagNewIDOutOfOrderGraphite(t *testing.T) {
 	assert.Equal(t, []byte("v0.v1.v2.v3.v4.v5.v6.v7.v8.v9.v10.v11.v12"), actual)
 }
 
+func TestGraphiteTaggedID(t *testing.T) {
+	opts := NewTagOptions().SetIDSchemeType(TypeGraphite)
+	tags := NewTags(5, opts).AddTags([]Tag{
+		{Name: []byte("env"), Value: []byte("prod")},
+		{Name: []byte("__g1__"), Value: []byte("bar")},
+		{Name: []byte("dc"), Value: []byte("us.east")},
+		{Name: []byte("__g0__"), Value: []byte("foo")},
+		{Name: []byte("__gx__"), Value: []byte("baz")},
+	})
+
+	names := make([]string, 0, tags.Len())
+	for _, tag := range tags.Tags {
+		names = append(names, string(tag.Name))
+	}
+	assert.Equal(t, []string{"__g0__", "__g1__", "__gx__", "dc", "env"}, names)
+	assert.Equal(t, []byte("foo.bar;__gx__=baz;dc=us.east;env=prod"), tags.ID())
+}
+
 func TestHashedID(t *testing.T) {
 	tags := testLongTagIDOutOfOrder(t, TypeLegacy)
 	actual := tags.HashedID()
@@ -26,12 +26,13 @@ import (
 
 // Separators for tags.
 const (
-	graphiteSep  = byte('.')
-	sep          = byte(',')
-	finish       = byte('!')
-	eq           = byte('=')
-	leftBracket  = byte('{')
-	rightBracket = byte('}')
+	graphiteSep    = byte('.')
+	graphiteTagSep = byte(';')
+	sep            = byte(',')
+	finish         = byte('!')
+	eq             = byte('=')
+	leftBracket    = byte('{')
+	rightBracket   = byte('}')
 )
 
 // IDSchemeType determines the scheme for generating
@@ -72,8 +73,13 @@ const (
 	// used on non-graphite data.
 	// {__g0__:v1},{__g1__:v2} -> v1.v2
 	//
-	// NB: when TypeGraphite is specified, tags are ordered numerically rather
-	// than lexically.
+	// Tags other than the graphite path tags are appended in the graphite
+	// tagged series format.
+	// {__g0__:v1},{__g1__:v2},{t1:v3} -> v1.v2;t1=v3
+	//
+	// NB: when TypeGraphite is specified, path tags are ordered numerically
+	// rather than lexically and precede all other tags, which are ordered
+	// lexically.
 	//
 	// NB 2: while the graphite scheme is valid, it is not available to choose as
 	// a general ID scheme; instead, it is set on any metric coming through the
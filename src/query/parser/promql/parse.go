@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 
+	"github.com/prometheus/prometheus/pkg/labels"
 	pql "github.com/prometheus/prometheus/promql"
 )
 
@@ -52,6 +53,68 @@ func Parse(q string, tagOpts models.TagOptions) (parser.Parser, error) {
 	}, nil
 }
 
+// ParseSelectorMatchers takes a promQL string and returns the matchers of each
+// of its vector and matrix selectors.
+func ParseSelectorMatchers(
+	q string,
+	tagOpts models.TagOptions,
+) ([]models.Matchers, error) {
+	expr, err := pql.ParseExpr(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.Matchers
+	err = walkSelectors(expr, func(lMatchers []*labels.Matcher) error {
+		matchers, err := LabelMatchersToModelMatcher(lMatchers, tagOpts)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, matchers)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func walkSelectors(
+	node pql.Node,
+	fn func(lMatchers []*labels.Matcher) error,
+) error {
+	switch n := node.(type) {
+	case *pql.VectorSelector:
+		return fn(n.LabelMatchers)
+	case *pql.MatrixSelector:
+		return fn(n.LabelMatchers)
+	case *pql.AggregateExpr:
+		if err := walkSelectors(n.Param, fn); err != nil {
+			return err
+		}
+		return walkSelectors(n.Expr, fn)
+	case *pql.BinaryExpr:
+		if err := walkSelectors(n.LHS, fn); err != nil {
+			return err
+		}
+		return walkSelectors(n.RHS, fn)
+	case *pql.Call:
+		for _, arg := range n.Args {
+			if err := walkSelectors(arg, fn); err != nil {
+				return err
+			}
+		}
+	case *pql.ParenExpr:
+		return walkSelectors(n.Expr, fn)
+	case *pql.UnaryExpr:
+		return walkSelectors(n.Expr, fn)
+	}
+
+	return nil
+}
+
 func (p *promParser) DAG() (parser.Nodes, parser.Edges, error) {
 	state := &parseState{tagOpts: p.tagOpts}
 	err := state.walk(p.expr)
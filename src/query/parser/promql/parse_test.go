@@ -498,3 +498,23 @@ func TestMissingTagsDoNotPanic(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotPanics(t, func() { _, _, _ = p.DAG() })
 }
+
+func TestParseSelectorMatchers(t *testing.T) {
+	q := `sum(rate(foo{a="1"}[5m])) / on(a) (bar{b=~"2.*"} + -baz)`
+	matchers, err := ParseSelectorMatchers(q, models.NewTagOptions())
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		actual = append(actual, m.String())
+	}
+
+	assert.Equal(t, []string{
+		`a="1",__name__="foo",`,
+		`b=~"2.*",__name__="bar",`,
+		`__name__="baz",`,
+	}, actual)
+
+	_, err = ParseSelectorMatchers("sum(", models.NewTagOptions())
+	assert.Error(t, err)
+}
@@ -42,9 +42,11 @@ import (
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/httpd"
 	m3dbcluster "github.com/m3db/m3/src/query/cluster/m3db"
 	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/policy/filter"
 	"github.com/m3db/m3/src/query/pools"
@@ -361,11 +363,40 @@ func Run(runOpts RunOptions) {
 	}
 
 	if cfg.Carbon != nil && cfg.Carbon.Ingester != nil {
-		server, ok := startCarbonIngestion(cfg.Carbon, instrumentOptions,
+		servers, ok := startCarbonIngestion(cfg.Carbon, instrumentOptions,
 			logger, m3dbClusters, downsamplerAndWriter)
 		if ok {
-			defer server.Close()
+			for _, server := range servers {
+				defer server.Close()
+			}
+		}
+	}
+
+	if cfg.Ruler != nil {
+		rulerManager, err := cfg.Ruler.NewManager(engine, downsamplerAndWriter,
+			clusterClient, tagOptions,
+			instrumentOptions.SetMetricsScope(scope.SubScope("ruler")))
+		if err != nil {
+			logger.Fatal("unable to create ruler", zap.Error(err))
+		}
+
+		if err := rulerManager.Start(); err != nil {
+			logger.Fatal("unable to start ruler", zap.Error(err))
 		}
+
+		logger.Info("started ruler", zap.Int("groups", len(rulerManager.Groups())))
+		defer rulerManager.Close()
+	}
+
+	if cfg.OTLP.GRPCListenAddress != "" {
+		server, err := startOTLPGRPCServer(cfg.OTLP, downsamplerAndWriter,
+			tagOptions, instrumentOptions)
+		if err != nil {
+			logger.Fatal("unable to start OTLP gRPC server",
+				zap.String("address", cfg.OTLP.GRPCListenAddress),
+				zap.Error(err))
+		}
+		defer server.GracefulStop()
 	}
 
 	// Wait for process interrupt.
@@ -849,7 +880,7 @@ func startCarbonIngestion(
 	logger *zap.Logger,
 	m3dbClusters m3.Clusters,
 	downsamplerAndWriter ingest.DownsamplerAndWriter,
-) (xserver.Server, bool) {
+) ([]ingestionServer, bool) {
 	ingesterCfg := cfg.Ingester
 	logger.Info("carbon ingestion enabled, configuring ingester")
 
@@ -965,8 +996,37 @@ func startCarbonIngestion(
 	}
 
 	logger.Info("started carbon ingestion server", zap.String("listenAddress", carbonListenAddress))
+	servers := []ingestionServer{carbonServer}
+
+	if addr := ingesterCfg.PickleListenAddress; addr != "" {
+		logger.Info("starting carbon pickle ingestion server", zap.String("listenAddress", addr))
+		pickleServer := xserver.NewServer(addr, ingester.PickleHandler(), serverOpts)
+		if err := pickleServer.ListenAndServe(); err != nil {
+			logger.Fatal("unable to start carbon pickle ingestion server at listen address",
+				zap.String("listenAddress", addr), zap.Error(err))
+		}
+		servers = append(servers, pickleServer)
+		logger.Info("started carbon pickle ingestion server", zap.String("listenAddress", addr))
+	}
+
+	if addr := ingesterCfg.UDPListenAddress; addr != "" {
+		logger.Info("starting carbon UDP ingestion server", zap.String("listenAddress", addr))
+		udpServer := ingestcarbon.NewUDPServer(addr, ingester,
+			ingesterCfg.UDPReaders, carbonIOpts)
+		if err := udpServer.ListenAndServe(); err != nil {
+			logger.Fatal("unable to start carbon UDP ingestion server at listen address",
+				zap.String("listenAddress", addr), zap.Error(err))
+		}
+		servers = append(servers, udpServer)
+		logger.Info("started carbon UDP ingestion server", zap.String("listenAddress", addr))
+	}
 
-	return carbonServer, true
+	return servers, true
+}
+
+// ingestionServer is a server started to ingest metrics.
+type ingestionServer interface {
+	Close()
 }
 
 func newDownsamplerAndWriter(storage storage.Storage, downsampler downsample.Downsampler) (ingest.DownsamplerAndWriter, error) {
@@ -984,3 +1044,52 @@ func newDownsamplerAndWriter(storage storage.Storage, downsampler downsample.Dow
 
 	return ingest.NewDownsamplerAndWriter(storage, downsampler, downAndWriteWorkerPool), nil
 }
+
+func startOTLPGRPCServer(
+	cfg otlp.Configuration,
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	instrumentOpts instrument.Options,
+) (*grpc.Server, error) {
+	logger := instrumentOpts.Logger()
+
+	server, err := newOTLPGRPCServer(cfg, downsamplerAndWriter, tagOptions,
+		instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", cfg.GRPCListenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("starting OTLP gRPC server",
+		zap.String("address", cfg.GRPCListenAddress))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			logger.Error("error from serving OTLP gRPC server", zap.Error(err))
+		}
+	}()
+
+	return server, nil
+}
+
+func newOTLPGRPCServer(
+	cfg otlp.Configuration,
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	tagOptions models.TagOptions,
+	instrumentOpts instrument.Options,
+) (*grpc.Server, error) {
+	service, err := otlp.NewMetricsService(downsamplerAndWriter, tagOptions,
+		cfg, time.Now, instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// NB: the OpenTelemetry collector gzips OTLP requests by default, and
+	// compressed requests are rejected unless a decompressor is installed.
+	server := grpc.NewServer(grpc.RPCDecompressor(grpc.NewGZIPDecompressor()))
+	otlppb.RegisterMetricsServiceServer(server, service)
+	return server, nil
+}
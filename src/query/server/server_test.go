@@ -31,12 +31,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
 	"github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
 	rpc "github.com/m3db/m3/src/query/generated/proto/rpcpb"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3"
 	xconfig "github.com/m3db/m3/src/x/config"
 	"github.com/m3db/m3/src/x/ident"
@@ -304,6 +308,64 @@ writeWorkerPoolPolicy:
 	<-doneCh
 }
 
+func TestOTLPGRPCServerGzipExport(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	var written int
+	downsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	downsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			for iter.Next() {
+				written++
+			}
+			return nil
+		})
+
+	s, err := newOTLPGRPCServer(otlp.Configuration{}, downsamplerAndWriter,
+		models.NewTagOptions(), instrument.NewOptions())
+	require.NoError(t, err)
+	defer s.Stop()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		s.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(),
+		grpc.WithCompressor(grpc.NewGZIPCompressor()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	req := &otlppb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlppb.ResourceMetrics{{
+			ScopeMetrics: []*otlppb.ScopeMetrics{{
+				Metrics: []*otlppb.Metric{{
+					Name: "queue.size",
+					Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+						DataPoints: []*otlppb.NumberDataPoint{{
+							TimeUnixNano: uint64(time.Now().UnixNano()),
+							Value:        &otlppb.NumberDataPoint_AsDouble{AsDouble: 1},
+						}},
+					}},
+				}},
+			}},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = otlppb.NewMetricsServiceClient(conn).Export(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+}
+
 func TestNewPerQueryEnforcer(t *testing.T) {
 	type testContext struct {
 		Global cost.ChainedEnforcer
@@ -1,4 +1,4 @@
-// Copyright (c) 2018 Uber Technologies, Inc.
+// Copyright (c) 2019 Uber Technologies, Inc.
 //
 // Permission is hereby granted, free of charge, to any person obtaining a copy
 // of this software and associated documentation files (the "Software"), to deal
@@ -18,19 +18,32 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-// generated by stringer -type=Type; DO NOT EDIT
+package exemplar
 
-package aggregation
+const (
+	defaultMaxExemplars = 100000
+)
 
-import "fmt"
-
-const _Type_name = "UnknownTypeLastMinMaxMeanMedianCountSumSumSqStdevP10P20P30P40P50P60P70P80P90P95P99P999P9999"
+// Configuration is the configuration for the exemplar store, which keeps
+// exemplars in the memory of a single coordinator only.
+type Configuration struct {
+	// MaxExemplars is the maximum number of exemplars kept across all series,
+	// the oldest exemplars are evicted once it is reached. Defaults to 100000,
+	// a negative value disables storing exemplars.
+	MaxExemplars int `yaml:"maxExemplars"`
+}
 
-var _Type_index = [...]uint8{0, 11, 15, 18, 21, 25, 31, 36, 39, 44, 49, 52, 55, 58, 61, 64, 67, 70, 73, 76, 79, 82, 86, 91}
+// NewStore returns a new exemplar store, or nil if storing exemplars is
+// disabled.
+func (c Configuration) NewStore() Store {
+	size := c.MaxExemplars
+	if size < 0 {
+		return nil
+	}
 
-func (i Type) String() string {
-	if i < 0 || i >= Type(len(_Type_index)-1) {
-		return fmt.Sprintf("Type(%d)", i)
+	if size == 0 {
+		size = defaultMaxExemplars
 	}
-	return _Type_name[_Type_index[i]:_Type_index[i+1]]
+
+	return NewStore(size)
 }
@@ -0,0 +1,213 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package exemplar provides an in memory store for the exemplars of series.
+//
+// Exemplars are neither persisted nor shared between coordinators: each
+// coordinator only holds the exemplars written to it, and loses them when
+// it restarts. Deployments with several coordinators behind a load balancer
+// return the exemplars of only the coordinator serving a query, so writes
+// and exemplar queries should be routed to the same coordinator.
+package exemplar
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+var (
+	errOutOfOrderExemplar = errors.New("out of order exemplar")
+)
+
+// Exemplar is an exemplar of a series, such as the trace of a request that
+// contributed to the series.
+type Exemplar struct {
+	Labels    models.Tags
+	Value     float64
+	Timestamp time.Time
+}
+
+// Series is a series and its exemplars ordered by timestamp.
+type Series struct {
+	Tags      models.Tags
+	Exemplars []Exemplar
+}
+
+// Store stores the exemplars of series.
+type Store interface {
+	// Add adds an exemplar to a series, an exemplar older than the newest
+	// exemplar of the series is rejected and a duplicate of the newest
+	// exemplar is ignored.
+	Add(tags models.Tags, exemplar Exemplar) error
+
+	// Query returns the exemplars between start and end inclusive of the
+	// series matching any of the given sets of matchers.
+	Query(
+		start, end time.Time,
+		matchers []models.Matchers,
+	) ([]Series, error)
+}
+
+type entry struct {
+	exemplar Exemplar
+	series   *seriesEntry
+	// next is the index of the next exemplar of the series, or -1.
+	next int
+}
+
+type seriesEntry struct {
+	key    string
+	tags   models.Tags
+	oldest int
+	newest int
+}
+
+// store is a circular buffer of exemplars shared by all series, each series
+// links its exemplars from oldest to newest so that the exemplars of a
+// series can be read without scanning the whole buffer.
+type store struct {
+	sync.RWMutex
+
+	exemplars []entry
+	series    map[string]*seriesEntry
+	next      int
+}
+
+// NewStore returns a new in memory exemplar store which keeps at most size
+// exemplars across all series.
+func NewStore(size int) Store {
+	if size < 1 {
+		size = 1
+	}
+
+	return &store{
+		exemplars: make([]entry, size),
+		series:    make(map[string]*seriesEntry),
+	}
+}
+
+func (s *store) Add(tags models.Tags, exemplar Exemplar) error {
+	key := string(tags.ID())
+
+	s.Lock()
+	defer s.Unlock()
+
+	if series, ok := s.series[key]; ok {
+		newest := s.exemplars[series.newest].exemplar
+		if exemplar.Timestamp.Before(newest.Timestamp) {
+			return errOutOfOrderExemplar
+		}
+
+		if exemplar.Timestamp.Equal(newest.Timestamp) &&
+			exemplar.Value == newest.Value &&
+			exemplar.Labels.Equals(newest.Labels) {
+			return nil
+		}
+	}
+
+	// Evict the oldest exemplar before looking up the series again since the
+	// evicted exemplar may have been the only exemplar of the series.
+	if evicted := s.exemplars[s.next].series; evicted != nil {
+		evicted.oldest = s.exemplars[s.next].next
+		if evicted.oldest == -1 {
+			delete(s.series, evicted.key)
+		}
+	}
+
+	series, ok := s.series[key]
+	if !ok {
+		series = &seriesEntry{
+			key:    key,
+			tags:   tags.Clone(),
+			oldest: s.next,
+		}
+		s.series[key] = series
+	} else {
+		s.exemplars[series.newest].next = s.next
+	}
+
+	exemplar.Labels = exemplar.Labels.Clone()
+	s.exemplars[s.next] = entry{
+		exemplar: exemplar,
+		series:   series,
+		next:     -1,
+	}
+
+	series.newest = s.next
+	s.next = (s.next + 1) % len(s.exemplars)
+	return nil
+}
+
+func (s *store) Query(
+	start, end time.Time,
+	matchers []models.Matchers,
+) ([]Series, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	var result []Series
+	for _, series := range s.series {
+		if !matches(series.tags, matchers) {
+			continue
+		}
+
+		var exemplars []Exemplar
+		for i := series.oldest; i != -1; i = s.exemplars[i].next {
+			exemplar := s.exemplars[i].exemplar
+			if exemplar.Timestamp.After(end) {
+				break
+			}
+
+			if !exemplar.Timestamp.Before(start) {
+				exemplars = append(exemplars, exemplar)
+			}
+		}
+
+		if len(exemplars) == 0 {
+			continue
+		}
+
+		result = append(result, Series{
+			Tags:      series.tags,
+			Exemplars: exemplars,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Tags.ID(), result[j].Tags.ID()) < 0
+	})
+
+	return result, nil
+}
+
+func matches(tags models.Tags, matchers []models.Matchers) bool {
+	for _, m := range matchers {
+		if m.MatchesTags(tags) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exemplar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTags(name, value string) models.Tags {
+	return models.NewTags(1, models.NewTagOptions()).AddTag(models.Tag{
+		Name:  []byte(name),
+		Value: []byte(value),
+	})
+}
+
+func testExemplar(traceID string, value float64, t time.Time) Exemplar {
+	return Exemplar{
+		Labels:    testTags("trace_id", traceID),
+		Value:     value,
+		Timestamp: t,
+	}
+}
+
+func testMatchers(t *testing.T, name, value string) []models.Matchers {
+	m, err := models.NewMatcher(models.MatchEqual, []byte(name), []byte(value))
+	require.NoError(t, err)
+	return []models.Matchers{{m}}
+}
+
+func exemplarValues(series Series) []float64 {
+	values := make([]float64, 0, len(series.Exemplars))
+	for _, e := range series.Exemplars {
+		values = append(values, e.Value)
+	}
+
+	return values
+}
+
+func TestStoreAddAndQuery(t *testing.T) {
+	s := NewStore(10)
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, s.Add(testTags("job", "a"), testExemplar("1", 1, now)))
+	require.NoError(t, s.Add(testTags("job", "b"), testExemplar("2", 2, now)))
+	require.NoError(t, s.Add(testTags("job", "a"),
+		testExemplar("3", 3, now.Add(time.Second))))
+
+	// A duplicate is ignored and an out of order exemplar is rejected.
+	require.NoError(t, s.Add(testTags("job", "a"),
+		testExemplar("3", 3, now.Add(time.Second))))
+	require.Error(t, s.Add(testTags("job", "a"), testExemplar("4", 4, now)))
+
+	result, err := s.Query(now, now.Add(time.Second), testMatchers(t, "job", "a"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "a", string(result[0].Tags.Tags[0].Value))
+	assert.Equal(t, []float64{1, 3}, exemplarValues(result[0]))
+	assert.Equal(t, "3", string(result[0].Exemplars[1].Labels.Tags[0].Value))
+
+	result, err = s.Query(now.Add(time.Second), now.Add(time.Minute),
+		testMatchers(t, "job", "a"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, []float64{3}, exemplarValues(result[0]))
+
+	m, err := models.NewMatcher(models.MatchRegexp, []byte("job"), []byte("a|b"))
+	require.NoError(t, err)
+	result, err = s.Query(now, now, []models.Matchers{{m}})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result))
+	assert.Equal(t, []float64{1}, exemplarValues(result[0]))
+	assert.Equal(t, []float64{2}, exemplarValues(result[1]))
+
+	result, err = s.Query(now, now, testMatchers(t, "job", "c"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(result))
+}
+
+func TestStoreEvictsOldest(t *testing.T) {
+	s := NewStore(3)
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, s.Add(testTags("job", "a"), testExemplar("1", 1, now)))
+	require.NoError(t, s.Add(testTags("job", "b"), testExemplar("2", 2, now)))
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i+1) * time.Second)
+		require.NoError(t, s.Add(testTags("job", "b"),
+			testExemplar("3", float64(3+i), ts)))
+	}
+
+	// The only exemplar of series a was evicted along with the series.
+	result, err := s.Query(now, now.Add(time.Minute), testMatchers(t, "job", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(result))
+
+	result, err = s.Query(now, now.Add(time.Minute), testMatchers(t, "job", "b"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, []float64{3, 4, 5}, exemplarValues(result[0]))
+
+	// Series a can be added again once evicted.
+	require.NoError(t, s.Add(testTags("job", "a"), testExemplar("1", 1, now)))
+	result, err = s.Query(now, now.Add(time.Minute), testMatchers(t, "job", "a"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, []float64{1}, exemplarValues(result[0]))
+}
+
+func TestConfigurationNewStore(t *testing.T) {
+	assert.NotNil(t, Configuration{}.NewStore())
+	assert.NotNil(t, Configuration{MaxExemplars: 10}.NewStore())
+	assert.Nil(t, Configuration{MaxExemplars: -1}.NewStore())
+}
@@ -255,6 +255,34 @@ func (s *fanoutStorage) SearchSeries(
 	return result, nil
 }
 
+func (s *fanoutStorage) ResolveNamespaces(
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) ([]storage.ResolvedNamespace, error) {
+	var resolved []storage.ResolvedNamespace
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	for _, store := range stores {
+		resolver, ok := store.(storage.NamespaceResolver)
+		if !ok {
+			// NB: stores which cannot resolve namespaces (e.g. remote stores)
+			// are still listed so that the fanout is visible.
+			resolved = append(resolved, storage.ResolvedNamespace{
+				Storage: store.Name(),
+			})
+			continue
+		}
+
+		namespaces, err := resolver.ResolveNamespaces(query, options)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, namespaces...)
+	}
+
+	return resolved, nil
+}
+
 func (s *fanoutStorage) CompleteTags(
 	ctx context.Context,
 	query *storage.CompleteTagsQuery,
@@ -82,10 +82,10 @@ func setupFanoutRead(t *testing.T, output bool, response ...*fetchResponse) stor
 	store1, session1 := m3.NewStorageAndSession(t, ctrl)
 	store2, session2 := m3.NewStorageAndSession(t, ctrl)
 
-	session1.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(response[0].result, true, response[0].err)
-	session2.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(response[len(response)-1].result, true, response[len(response)-1].err)
-	session1.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, errs.ErrNotImplemented)
-	session2.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, errs.ErrNotImplemented)
+	session1.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(response[0].result, true, response[0].err)
+	session2.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(response[len(response)-1].result, true, response[len(response)-1].err)
+	session1.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, errs.ErrNotImplemented)
+	session2.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, errs.ErrNotImplemented)
 	session1.EXPECT().IteratorPools().
 		Return(nil, nil).AnyTimes()
 	session2.EXPECT().IteratorPools().
@@ -109,9 +109,9 @@ func setupFanoutWrite(t *testing.T, output bool, errs ...error) storage.Storage
 		Return(errs[0])
 	session1.EXPECT().IteratorPools().
 		Return(nil, nil).AnyTimes()
-	session1.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+	session1.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, true, errs[0]).AnyTimes()
-	session1.EXPECT().Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+	session1.EXPECT().AggregateWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, true, errs[0]).AnyTimes()
 
 	session2.EXPECT().
@@ -391,3 +391,30 @@ func TestFanoutFetchErrorContinues(t *testing.T) {
 	require.Equal(t, 1, len(result.SeriesList))
 	assert.Equal(t, []byte("ok"), result.SeriesList[0].Name())
 }
+
+func TestFanoutResolveNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local, _ := m3.NewStorageAndSession(t, ctrl)
+	remote := storage.NewMockStorage(ctrl)
+	remote.EXPECT().Name().Return("remote").AnyTimes()
+
+	store := NewStorage([]storage.Storage{local, remote}, filterFunc(true),
+		filterFunc(true), filterCompleteTagsFunc(true), instrument.NewOptions())
+	resolver, ok := store.(storage.NamespaceResolver)
+	require.True(t, ok)
+
+	query := &storage.FetchQuery{
+		Start: time.Now().Add(-time.Hour),
+		End:   time.Now(),
+	}
+	resolved, err := resolver.ResolveNamespaces(query, storage.NewFetchOptions())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resolved))
+
+	assert.Equal(t, "local_store", resolved[0].Storage)
+	assert.Equal(t, m3.TestNamespaceID, resolved[0].Namespace)
+	assert.Equal(t, m3.TestRetention, resolved[0].Attributes.Retention)
+	assert.Equal(t, storage.ResolvedNamespace{Storage: "remote"}, resolved[1])
+}
@@ -117,6 +117,35 @@ func (s *m3storage) Name() string {
 	return "local_store"
 }
 
+func (s *m3storage) ResolveNamespaces(
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) ([]storage.ResolvedNamespace, error) {
+	fanout, namespaces, err := resolveClusterNamespacesForQuery(
+		s.nowFn(),
+		query.Start,
+		query.End,
+		s.clusters,
+		options.FanoutOptions,
+		options.RestrictFetchOptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]storage.ResolvedNamespace, 0, len(namespaces))
+	for _, n := range namespaces {
+		resolved = append(resolved, storage.ResolvedNamespace{
+			Storage:    s.Name(),
+			Namespace:  n.NamespaceID().String(),
+			Attributes: n.Options().Attributes(),
+			Coverage:   fanout.String(),
+		})
+	}
+
+	return resolved, nil
+}
+
 func (s *m3storage) Fetch(
 	ctx context.Context,
 	query *storage.FetchQuery,
@@ -318,9 +347,11 @@ func (s *m3storage) fetchCompressed(
 
 		wg.Add(1)
 		go func() {
+			// NB: the session stops the fetches in flight to the hosts as soon
+			// as the query is cancelled.
 			session := namespace.Session()
 			ns := namespace.NamespaceID()
-			iters, _, err := session.FetchTagged(ns, m3query, opts)
+			iters, _, err := session.FetchTaggedWithContext(ctx, ns, m3query, opts)
 			// Ignore error from getting iterator pools, since operation
 			// will not be dramatically impacted if pools is nil
 			result.Add(namespace.Options().Attributes(), iters, err)
@@ -333,6 +364,7 @@ func (s *m3storage) fetchCompressed(
 	// Check if the query was interrupted.
 	select {
 	case <-ctx.Done():
+		result.Close()
 		return nil, ctx.Err()
 	default:
 	}
@@ -438,7 +470,7 @@ func (s *m3storage) CompleteTags(
 			defer wg.Done()
 			session := namespace.Session()
 			namespaceID := namespace.NamespaceID()
-			aggTagIter, _, err := session.Aggregate(namespaceID, m3query, aggOpts)
+			aggTagIter, _, err := session.AggregateWithContext(ctx, namespaceID, m3query, aggOpts)
 			if err != nil {
 				multiErr.add(err)
 				return
@@ -537,7 +569,7 @@ func (s *m3storage) SearchCompressed(
 		go func() {
 			session := namespace.Session()
 			namespaceID := namespace.NamespaceID()
-			iter, _, err := session.FetchTaggedIDs(namespaceID, m3query, m3opts)
+			iter, _, err := session.FetchTaggedIDsWithContext(ctx, namespaceID, m3query, m3opts)
 			result.Add(iter, err)
 			wg.Done()
 		}()
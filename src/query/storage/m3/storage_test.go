@@ -30,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/test/seriesiter"
@@ -262,7 +263,7 @@ func TestLocalRead(t *testing.T) {
 	testTags := seriesiter.GenerateTag()
 
 	session := sessions.unaggregated1MonthRetention
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(seriesiter.NewMockSeriesIters(ctrl, testTags, 1, 2), true, nil)
 	session.EXPECT().IteratorPools().
 		Return(newTestIteratorPools(ctrl), nil).AnyTimes()
@@ -279,6 +280,34 @@ func TestLocalRead(t *testing.T) {
 	assert.Equal(t, []byte("name"), results.SeriesList[0].Tags.Opts.MetricName())
 }
 
+func TestLocalReadCancelledDuringFetch(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	started := make(chan struct{})
+	session := sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ ident.ID, _ index.Query, _ index.QueryOptions) (
+			encoding.SeriesIterators, bool, error) {
+			close(started)
+			// The session aborts the fetches in flight once the query is cancelled.
+			<-ctx.Done()
+			return nil, false, ctx.Err()
+		})
+	session.EXPECT().IteratorPools().
+		Return(newTestIteratorPools(ctrl), nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := store.Fetch(ctx, newFetchReq(), buildFetchOpts())
+	require.Equal(t, context.Canceled, err)
+}
+
 func TestLocalReadExceedsRetention(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -286,7 +315,7 @@ func TestLocalReadExceedsRetention(t *testing.T) {
 	testTag := seriesiter.GenerateTag()
 
 	session := sessions.aggregated1YearRetention10MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(seriesiter.NewMockSeriesIters(ctrl, testTag, 1, 2), true, nil)
 	session.EXPECT().IteratorPools().Return(nil, nil).AnyTimes()
 
@@ -311,12 +340,12 @@ func TestLocalReadExceedsUnaggregatedRetentionWithinAggregatedRetention(t *testi
 	testTag := seriesiter.GenerateTag()
 
 	session := sessions.aggregated3MonthRetention5MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(seriesiter.NewMockSeriesIters(ctrl, testTag, 1, 2), true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
 	session = sessions.aggregatedPartial6MonthRetention1MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(encoding.EmptySeriesIterators, true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
@@ -330,6 +359,40 @@ func TestLocalReadExceedsUnaggregatedRetentionWithinAggregatedRetention(t *testi
 	assertFetchResult(t, results, testTag)
 }
 
+func TestLocalResolveNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store, _ := setup(t, ctrl)
+
+	resolver, ok := store.(storage.NamespaceResolver)
+	require.True(t, ok)
+
+	resolved, err := resolver.ResolveNamespaces(newFetchReq(), buildFetchOpts())
+	require.NoError(t, err)
+	require.Equal(t, []storage.ResolvedNamespace{
+		{
+			Storage:   "local_store",
+			Namespace: "metrics_unaggregated",
+			Attributes: storage.Attributes{
+				MetricsType: storage.UnaggregatedMetricsType,
+				Retention:   test1MonthRetention,
+			},
+			Coverage: "coversAllQueryRange",
+		},
+	}, resolved)
+
+	searchReq := newFetchReq()
+	searchReq.Start = time.Now().Add(-2 * test1MonthRetention)
+	resolved, err = resolver.ResolveNamespaces(searchReq, buildFetchOpts())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resolved))
+	assert.Equal(t, "metrics_aggregated_5m:90d", resolved[0].Namespace)
+	assert.Equal(t, storage.AggregatedMetricsType,
+		resolved[0].Attributes.MetricsType)
+	assert.Equal(t, "metrics_aggregated_partial_1m:180d", resolved[1].Namespace)
+	assert.Equal(t, "coversPartialQueryRange", resolved[1].Coverage)
+}
+
 func TestLocalReadExceedsAggregatedButNotUnaggregatedAndPartialAggregated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -355,12 +418,12 @@ func TestLocalReadExceedsAggregatedButNotUnaggregatedAndPartialAggregated(t *tes
 	testTag := seriesiter.GenerateTag()
 
 	session := unaggregated1MonthRetention
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(seriesiter.NewMockSeriesIters(ctrl, testTag, 1, 2), true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
 	session = aggregatedPartial6MonthRetention1MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(encoding.EmptySeriesIterators, true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
@@ -405,12 +468,12 @@ func TestLocalReadExceedsAggregatedAndPartialAggregated(t *testing.T) {
 	testTag := seriesiter.GenerateTag()
 
 	session := aggregated3MonthRetention5MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(seriesiter.NewMockSeriesIters(ctrl, testTag, 1, 2), true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
 	session = aggregatedPartial6MonthRetention1MinuteResolution
-	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+	session.EXPECT().FetchTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(encoding.EmptySeriesIterators, true, nil)
 	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
 
@@ -441,7 +504,7 @@ func TestLocalSearchError(t *testing.T) {
 	defer ctrl.Finish()
 	store, sessions := setup(t, ctrl)
 	sessions.forEach(func(session *client.MockSession) {
-		session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+		session.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil, false, fmt.Errorf("an error"))
 		session.EXPECT().IteratorPools().
 			Return(nil, nil).AnyTimes()
@@ -510,7 +573,7 @@ func TestLocalSearchSuccess(t *testing.T) {
 				iter.EXPECT().Err().Return(nil),
 				iter.EXPECT().Finalize(),
 			)
-			session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+			session.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(iter, true, nil)
 			session.EXPECT().IteratorPools().
 				Return(nil, nil).AnyTimes()
@@ -533,7 +596,7 @@ func TestLocalSearchSuccess(t *testing.T) {
 			iter.EXPECT().Finalize(),
 		)
 
-		session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+		session.EXPECT().FetchTaggedIDsWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(iter, true, nil)
 
 		session.EXPECT().IteratorPools().
@@ -648,7 +711,7 @@ func TestLocalCompleteTagsSuccess(t *testing.T) {
 				iter.EXPECT().Err().Return(nil),
 				iter.EXPECT().Finalize(),
 			)
-			session.EXPECT().Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+			session.EXPECT().AggregateWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(iter, true, nil)
 			return
 		}
@@ -666,7 +729,7 @@ func TestLocalCompleteTagsSuccess(t *testing.T) {
 			iter.EXPECT().Finalize(),
 		)
 
-		session.EXPECT().Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+		session.EXPECT().AggregateWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(iter, true, nil)
 	})
 
@@ -726,7 +789,7 @@ func TestLocalCompleteTagsSuccessFinalize(t *testing.T) {
 		}),
 	)
 
-	unagg.EXPECT().Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).
+	unagg.EXPECT().AggregateWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(iter, true, nil)
 
 	req := newCompleteTagsReq()
@@ -0,0 +1,398 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package metadata provides a store for the metadata of Prometheus metric
+// families, such as their type, help and unit.
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/cespare/xxhash"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultKey is the default KV key prefix the metadata is stored at.
+	DefaultKey = "_prometheus_metric_metadata"
+
+	// NB: metric families are spread over a fixed number of KV values so
+	// that neither a single value grows with the number of metric families
+	// nor all coordinators contend on a single value.
+	numBuckets        = 64
+	flushInterval     = 10 * time.Second
+	maxUpdateAttempts = 5
+)
+
+var (
+	errNoKVStore              = errors.New("no kv store set")
+	errStoreClosed            = errors.New("metadata store is closed")
+	errUpdateAttemptsExceeded = errors.New("exceeded attempts to update metadata")
+)
+
+// Metadata is the metadata of a metric family.
+type Metadata struct {
+	Type string
+	Help string
+	Unit string
+}
+
+// Store stores the metadata of metric families.
+type Store interface {
+	// Update updates the metadata of the given metric families. The metadata
+	// of a metric family replaces any previous metadata with a different type
+	// or unit, a differing help text alone only replaces a missing help text.
+	Update(metadata []*prompb.MetricMetadata) error
+
+	// Metadata returns the metadata of all metric families by name.
+	Metadata() (map[string]Metadata, error)
+
+	// Close closes the store.
+	Close() error
+}
+
+type kvStore struct {
+	sync.Mutex
+
+	store  kv.Store
+	key    string
+	logger *zap.Logger
+
+	// known is the metadata last read from or written to the KV store, which
+	// allows updates that do not change any metadata to skip the KV store.
+	known map[string]Metadata
+	// pending is the metadata waiting to be written to the KV store.
+	pending map[string]Metadata
+
+	closed  bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewKVStore returns a metadata store which persists the metadata under the
+// given key prefix of the KV store, so that it is shared by all coordinators
+// using the same KV store. Updates are buffered and written to the KV store
+// in the background, outside of the write path.
+func NewKVStore(
+	store kv.Store,
+	key string,
+	instrumentOpts instrument.Options,
+) (Store, error) {
+	if store == nil {
+		return nil, errNoKVStore
+	}
+
+	if key == "" {
+		key = DefaultKey
+	}
+
+	s := &kvStore{
+		store:   store,
+		key:     key,
+		logger:  instrumentOpts.Logger(),
+		known:   make(map[string]Metadata),
+		pending: make(map[string]Metadata),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	go s.flushLoop()
+	return s, nil
+}
+
+// FromProto returns the name and metadata of a Prometheus metric family
+// metadata, the type is lower cased as with Prometheus, i.e. counter.
+func FromProto(metadata *prompb.MetricMetadata) (string, Metadata) {
+	return metadata.MetricFamilyName, Metadata{
+		Type: strings.ToLower(metadata.Type.String()),
+		Help: metadata.Help,
+		Unit: metadata.Unit,
+	}
+}
+
+func (s *kvStore) Update(metadata []*prompb.MetricMetadata) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return errStoreClosed
+	}
+
+	for _, m := range metadata {
+		name, value := FromProto(m)
+		if name == "" {
+			continue
+		}
+
+		existing, ok := s.pending[name]
+		if !ok {
+			existing, ok = s.known[name]
+		}
+
+		if ok && !replaces(existing, value) {
+			continue
+		}
+
+		s.pending[name] = value
+	}
+
+	return nil
+}
+
+func (s *kvStore) Metadata() (map[string]Metadata, error) {
+	result := make(map[string]Metadata)
+	for bucket := 0; bucket < numBuckets; bucket++ {
+		current, _, err := s.get(s.bucketKey(bucket))
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range current {
+			result[name] = value
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	// NB: include metadata that is yet to be written so that updates are
+	// visible immediately on this coordinator.
+	for name, value := range s.pending {
+		if existing, ok := result[name]; !ok || replaces(existing, value) {
+			result[name] = value
+		}
+	}
+
+	return result, nil
+}
+
+func (s *kvStore) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return errStoreClosed
+	}
+
+	s.closed = true
+	close(s.closeCh)
+	s.Unlock()
+
+	<-s.doneCh
+	return s.flush()
+}
+
+func (s *kvStore) flushLoop() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.flush(); err != nil {
+			s.logger.Error("metadata flush error", zap.Error(err))
+		}
+	}
+}
+
+// flush writes the pending metadata to the KV store.
+func (s *kvStore) flush() error {
+	s.Lock()
+	pending := s.pending
+	s.pending = make(map[string]Metadata)
+	s.Unlock()
+
+	byBucket := make(map[int]map[string]Metadata)
+	for name, value := range pending {
+		bucket := bucketOf(name)
+		if byBucket[bucket] == nil {
+			byBucket[bucket] = make(map[string]Metadata)
+		}
+
+		byBucket[bucket][name] = value
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for bucket, metadata := range byBucket {
+		if err := s.updateBucket(s.bucketKey(bucket), metadata); err != nil {
+			multiErr = multiErr.Add(err)
+			s.retry(metadata)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+// retry adds metadata that failed to be written back to the pending metadata
+// unless it has since been superseded.
+func (s *kvStore) retry(metadata map[string]Metadata) {
+	s.Lock()
+	defer s.Unlock()
+
+	for name, value := range metadata {
+		if _, ok := s.pending[name]; !ok {
+			s.pending[name] = value
+		}
+	}
+}
+
+func (s *kvStore) updateBucket(key string, metadata map[string]Metadata) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		current, version, err := s.get(key)
+		if err != nil {
+			return err
+		}
+
+		updated := make(map[string]Metadata, len(current)+len(metadata))
+		for name, value := range current {
+			updated[name] = value
+		}
+
+		changed := false
+		for name, value := range metadata {
+			if existing, ok := current[name]; ok && !replaces(existing, value) {
+				continue
+			}
+
+			updated[name] = value
+			changed = true
+		}
+
+		if !changed {
+			s.remember(current)
+			return nil
+		}
+
+		// NB: metadata is stored as a metadata only remote write request,
+		// the same message Prometheus uses to send the metadata.
+		value := toProto(updated)
+		if version == kv.UninitializedVersion {
+			_, err = s.store.SetIfNotExists(key, value)
+		} else {
+			_, err = s.store.CheckAndSet(key, version, value)
+		}
+
+		switch err {
+		case nil:
+			s.remember(updated)
+			return nil
+		case kv.ErrAlreadyExists, kv.ErrVersionMismatch:
+			// Concurrently updated by another coordinator, retry.
+			continue
+		default:
+			return err
+		}
+	}
+
+	return errUpdateAttemptsExceeded
+}
+
+func (s *kvStore) remember(metadata map[string]Metadata) {
+	s.Lock()
+	defer s.Unlock()
+
+	for name, value := range metadata {
+		s.known[name] = value
+	}
+}
+
+func (s *kvStore) bucketKey(bucket int) string {
+	return fmt.Sprintf("%s/%d", s.key, bucket)
+}
+
+func (s *kvStore) get(key string) (map[string]Metadata, int, error) {
+	value, err := s.store.Get(key)
+	if err == kv.ErrNotFound {
+		return make(map[string]Metadata), kv.UninitializedVersion, nil
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var req prompb.WriteRequest
+	if err := value.Unmarshal(&req); err != nil {
+		return nil, 0, err
+	}
+
+	result := make(map[string]Metadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		name, metadata := FromProto(m)
+		result[name] = metadata
+	}
+
+	return result, value.Version(), nil
+}
+
+func bucketOf(name string) int {
+	return int(xxhash.Sum64([]byte(name)) % numBuckets)
+}
+
+// replaces returns whether the metadata replaces the existing metadata of a
+// metric family. Metric families are commonly scraped by several Prometheus
+// servers with differing help texts, which must not make the metadata flap,
+// so a differing help text alone only replaces a missing help text.
+func replaces(existing, value Metadata) bool {
+	if existing.Type != value.Type || existing.Unit != value.Unit {
+		return true
+	}
+
+	return existing.Help == "" && value.Help != ""
+}
+
+func toProto(metadata map[string]Metadata) *prompb.WriteRequest {
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	req := &prompb.WriteRequest{
+		Metadata: make([]*prompb.MetricMetadata, 0, len(names)),
+	}
+
+	for _, name := range names {
+		value := metadata[name]
+		req.Metadata = append(req.Metadata, &prompb.MetricMetadata{
+			Type: prompb.MetricMetadata_MetricType(
+				prompb.MetricMetadata_MetricType_value[strings.ToUpper(value.Type)]),
+			MetricFamilyName: name,
+			Help:             value.Help,
+			Unit:             value.Unit,
+		})
+	}
+
+	return req
+}
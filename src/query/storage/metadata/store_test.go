@@ -0,0 +1,180 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKVStore(t *testing.T) *kvStore {
+	store, err := NewKVStore(mem.NewStore(), "", instrument.NewOptions())
+	require.NoError(t, err)
+	return store.(*kvStore)
+}
+
+func TestKVStoreUpdate(t *testing.T) {
+	store := newTestKVStore(t)
+	defer store.Close()
+
+	metadata, err := store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(metadata))
+
+	require.NoError(t, store.Update([]*prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricMetadata_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricMetadata_GAUGEHISTOGRAM,
+			MetricFamilyName: "request_duration_seconds",
+			Help:             "Request duration.",
+			Unit:             "seconds",
+		},
+		{Type: prompb.MetricMetadata_GAUGE},
+	}))
+
+	expected := map[string]Metadata{
+		"http_requests_total": {Type: "counter", Help: "Total HTTP requests."},
+		"request_duration_seconds": {
+			Type: "gaugehistogram",
+			Help: "Request duration.",
+			Unit: "seconds",
+		},
+	}
+
+	// Updates are visible before they are written to the KV store.
+	metadata, err = store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+
+	_, err = store.store.Get(store.bucketKey(bucketOf("http_requests_total")))
+	require.Error(t, err)
+
+	require.NoError(t, store.flush())
+	metadata, err = store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+
+	// Updates that do not change the metadata are not written.
+	key := store.bucketKey(bucketOf("http_requests_total"))
+	value, err := store.store.Get(key)
+	require.NoError(t, err)
+	require.NoError(t, store.Update([]*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_COUNTER,
+		MetricFamilyName: "http_requests_total",
+		Help:             "Total HTTP requests.",
+	}}))
+	assert.Equal(t, 0, len(store.pending))
+
+	// A differing help text alone does not replace the metadata.
+	require.NoError(t, store.Update([]*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_COUNTER,
+		MetricFamilyName: "http_requests_total",
+		Help:             "Total number of HTTP requests.",
+	}}))
+	assert.Equal(t, 0, len(store.pending))
+
+	require.NoError(t, store.flush())
+	unchanged, err := store.store.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value.Version(), unchanged.Version())
+
+	// Metadata with a different type replaces previous metadata.
+	require.NoError(t, store.Update([]*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_GAUGE,
+		MetricFamilyName: "http_requests_total",
+		Help:             "Total number of HTTP requests.",
+	}}))
+	require.NoError(t, store.flush())
+
+	expected["http_requests_total"] = Metadata{
+		Type: "gauge",
+		Help: "Total number of HTTP requests.",
+	}
+
+	metadata, err = store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+}
+
+func TestKVStoreSharedUpdates(t *testing.T) {
+	kvStore := mem.NewStore()
+	first, err := NewKVStore(kvStore, "", instrument.NewOptions())
+	require.NoError(t, err)
+	second, err := NewKVStore(kvStore, "", instrument.NewOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, first.Update([]*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_GAUGE,
+		MetricFamilyName: "foo",
+	}}))
+	require.NoError(t, first.Close())
+
+	// The second store has not seen the first update, which must not be
+	// overwritten.
+	require.NoError(t, second.Update([]*prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricMetadata_SUMMARY,
+			MetricFamilyName: "bar",
+		},
+		{
+			Type:             prompb.MetricMetadata_GAUGE,
+			MetricFamilyName: "foo",
+			Help:             "Foo.",
+		},
+	}))
+	require.NoError(t, second.Close())
+
+	third, err := NewKVStore(kvStore, "", instrument.NewOptions())
+	require.NoError(t, err)
+	defer third.Close()
+
+	metadata, err := third.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Metadata{
+		"foo": {Type: "gauge", Help: "Foo."},
+		"bar": {Type: "summary"},
+	}, metadata)
+}
+
+func TestKVStoreClosed(t *testing.T) {
+	store := newTestKVStore(t)
+	require.NoError(t, store.Close())
+	require.Error(t, store.Close())
+	require.Error(t, store.Update([]*prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_GAUGE,
+		MetricFamilyName: "foo",
+	}}))
+}
+
+func TestNewKVStoreNoStore(t *testing.T) {
+	_, err := NewKVStore(nil, "", instrument.NewOptions())
+	require.Error(t, err)
+}
@@ -347,6 +347,29 @@ type Querier interface {
 	) (*CompleteTagsResult, error)
 }
 
+// NamespaceResolver is implemented by storages which can report the
+// namespaces a fetch would be fanned out to without executing it.
+type NamespaceResolver interface {
+	// ResolveNamespaces returns the namespaces that would serve the query.
+	ResolveNamespaces(
+		query *FetchQuery,
+		options *FetchOptions,
+	) ([]ResolvedNamespace, error)
+}
+
+// ResolvedNamespace describes a namespace chosen to serve a fetch.
+type ResolvedNamespace struct {
+	// Storage is the name of the storage the namespace belongs to.
+	Storage string
+	// Namespace is the namespace ID, empty if the storage does not expose
+	// its namespaces.
+	Namespace string
+	// Attributes are the stored metrics attributes of the namespace.
+	Attributes Attributes
+	// Coverage describes how much of the query range the namespace covers.
+	Coverage string
+}
+
 // WriteQuery represents the input timeseries that is written to the db
 type WriteQuery struct {
 	Tags       models.Tags
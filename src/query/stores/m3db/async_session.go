@@ -21,6 +21,7 @@
 package m3db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -146,17 +147,34 @@ func (s *AsyncSession) FetchIDs(namespace ident.ID, ids ident.Iterator,
 func (s *AsyncSession) FetchTagged(namespace ident.ID, q index.Query,
 	opts index.QueryOptions) (results encoding.SeriesIterators,
 	exhaustive bool, err error) {
+	return s.FetchTaggedWithContext(context.Background(), namespace, q, opts)
+}
+
+// FetchTaggedWithContext resolves the provided query to known IDs, and
+// fetches the data for them, cancelling the fetches in flight when the
+// context is cancelled.
+func (s *AsyncSession) FetchTaggedWithContext(ctx context.Context,
+	namespace ident.ID, q index.Query, opts index.QueryOptions) (
+	results encoding.SeriesIterators, exhaustive bool, err error) {
 	s.RLock()
 	defer s.RUnlock()
 	if s.err != nil {
 		return nil, false, s.err
 	}
 
-	return s.session.FetchTagged(namespace, q, opts)
+	return s.session.FetchTaggedWithContext(ctx, namespace, q, opts)
 }
 
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query,
+	opts index.QueryOptions) (client.TaggedIDsIterator, bool, error) {
+	return s.FetchTaggedIDsWithContext(context.Background(), namespace, q, opts)
+}
+
+// FetchTaggedIDsWithContext resolves the provided query to known IDs,
+// cancelling the fetches in flight when the context is cancelled.
+func (s *AsyncSession) FetchTaggedIDsWithContext(ctx context.Context,
+	namespace ident.ID, q index.Query,
 	opts index.QueryOptions) (client.TaggedIDsIterator, bool, error) {
 	s.RLock()
 	defer s.RUnlock()
@@ -164,18 +182,24 @@ func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query,
 		return nil, false, s.err
 	}
 
-	return s.session.FetchTaggedIDs(namespace, q, opts)
+	return s.session.FetchTaggedIDsWithContext(ctx, namespace, q, opts)
 }
 
 // Aggregate aggregates values from the database for the given set of constraints.
 func (s *AsyncSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (client.AggregatedTagsIterator, bool, error) {
+	return s.AggregateWithContext(context.Background(), namespace, q, opts)
+}
+
+// AggregateWithContext aggregates values from the database for the given set of
+// constraints, cancelling the requests in flight when the context is cancelled.
+func (s *AsyncSession) AggregateWithContext(ctx context.Context, namespace ident.ID, q index.Query, opts index.AggregationOptions) (client.AggregatedTagsIterator, bool, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if s.err != nil {
 		return nil, false, s.err
 	}
 
-	return s.session.Aggregate(namespace, q, opts)
+	return s.session.AggregateWithContext(ctx, namespace, q, opts)
 }
 
 // ShardID returns the given shard for an ID for callers
@@ -21,6 +21,7 @@
 package m3db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -101,12 +102,21 @@ func TestAsyncSessionUninitialized(t *testing.T) {
 	assert.Equal(t, false, exhaustive)
 	assert.Equal(t, err, errSessionUninitialized)
 
+	_, _, err = asyncSession.FetchTaggedWithContext(context.Background(), namespace, index.Query{}, index.QueryOptions{})
+	assert.Equal(t, err, errSessionUninitialized)
+
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.Equal(t, err, errSessionUninitialized)
 
+	_, _, err = asyncSession.FetchTaggedIDsWithContext(context.Background(), namespace, index.Query{}, index.QueryOptions{})
+	assert.Equal(t, err, errSessionUninitialized)
+
 	_, _, err = asyncSession.Aggregate(namespace, index.Query{}, index.AggregationOptions{})
 	assert.Equal(t, err, errSessionUninitialized)
 
+	_, _, err = asyncSession.AggregateWithContext(context.Background(), namespace, index.Query{}, index.AggregationOptions{})
+	assert.Equal(t, err, errSessionUninitialized)
+
 	id, err := asyncSession.ShardID(nil)
 	assert.Equal(t, uint32(0), id)
 	assert.Equal(t, err, errSessionUninitialized)
@@ -147,17 +157,24 @@ func TestAsyncSessionInitialized(t *testing.T) {
 	_, err = asyncSession.FetchIDs(nil, nil, time.Now(), time.Now())
 	assert.NoError(t, err)
 
-	mockSession.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil)
+	ctx := context.Background()
+	mockSession.EXPECT().FetchTaggedWithContext(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil).Times(2)
 	_, _, err = asyncSession.FetchTagged(namespace, index.Query{}, index.QueryOptions{})
 	assert.NoError(t, err)
+	_, _, err = asyncSession.FetchTaggedWithContext(ctx, namespace, index.Query{}, index.QueryOptions{})
+	assert.NoError(t, err)
 
-	mockSession.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil)
+	mockSession.EXPECT().FetchTaggedIDsWithContext(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil).Times(2)
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.NoError(t, err)
+	_, _, err = asyncSession.FetchTaggedIDsWithContext(ctx, namespace, index.Query{}, index.QueryOptions{})
+	assert.NoError(t, err)
 
-	mockSession.EXPECT().Aggregate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil)
+	mockSession.EXPECT().AggregateWithContext(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil).Times(2)
 	_, _, err = asyncSession.Aggregate(namespace, index.Query{}, index.AggregationOptions{})
 	assert.NoError(t, err)
+	_, _, err = asyncSession.AggregateWithContext(ctx, namespace, index.Query{}, index.AggregationOptions{})
+	assert.NoError(t, err)
 
 	mockSession.EXPECT().ShardID(gomock.Any()).Return(uint32(0), nil)
 	_, err = asyncSession.ShardID(nil)
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package promname sanitizes metric and tag names so they are valid
+// Prometheus metric and label names.
+package promname
+
+// SanitizeMetricName replaces characters not valid in a Prometheus metric
+// name, such as the dots used by OpenTelemetry semantic conventions, with
+// underscores. The name is returned as is if it is valid, otherwise a copy
+// is sanitized.
+func SanitizeMetricName(name []byte) []byte {
+	return sanitize(name, func(c byte) bool {
+		return c == ':' || isTagNameChar(c)
+	})
+}
+
+// SanitizeTagName replaces characters not valid in a Prometheus label name
+// with underscores. The name is returned as is if it is valid, otherwise a
+// copy is sanitized.
+func SanitizeTagName(name []byte) []byte {
+	return sanitize(name, isTagNameChar)
+}
+
+func sanitize(name []byte, valid func(c byte) bool) []byte {
+	var sanitized []byte
+	for i, c := range name {
+		if valid(c) {
+			continue
+		}
+		if sanitized == nil {
+			sanitized = append([]byte(nil), name...)
+		}
+		sanitized[i] = '_'
+	}
+	if sanitized == nil {
+		return name
+	}
+	return sanitized
+}
+
+func isTagNameChar(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
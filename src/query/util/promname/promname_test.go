@@ -0,0 +1,68 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeMetricName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "http_requests_total", expected: "http_requests_total"},
+		{name: "job:requests:rate5m", expected: "job:requests:rate5m"},
+		{name: "process.cpu.time", expected: "process_cpu_time"},
+		{name: "cpu-usage%", expected: "cpu_usage_"},
+		{name: "", expected: ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, string(SanitizeMetricName([]byte(test.name))))
+	}
+}
+
+func TestSanitizeTagName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "service_name", expected: "service_name"},
+		{name: "service.name", expected: "service_name"},
+		{name: "a:b", expected: "a_b"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, string(SanitizeTagName([]byte(test.name))))
+	}
+}
+
+func TestSanitizeCopiesInvalidNames(t *testing.T) {
+	valid := []byte("foo")
+	assert.Equal(t, &valid[0], &SanitizeTagName(valid)[0])
+
+	invalid := []byte("foo.bar")
+	assert.Equal(t, "foo_bar", string(SanitizeTagName(invalid)))
+	assert.Equal(t, "foo.bar", string(invalid))
+}